@@ -586,7 +586,7 @@ func TestTLSSession(t *testing.T) {
 	assert.Equal(t, string(dict1Bytes), string(dict2Bytes))
 }
 func TestReassembled(t *testing.T) {
-	host, port := utils.DebugMockHTTPServerWithContextWithAddress(context.Background(), "127.0.0.1:9099", true, false, false, false, func(i []byte) []byte {
+	host, port := utils.DebugMockHTTPServerWithContextWithAddress(context.Background(), "127.0.0.1:9099", true, false, false, false, false, func(i []byte) []byte {
 		return []byte("HTTP/1.1 200 OK\r\n\r\nHello, world!")
 	})
 	payload := []byte{}
@@ -915,3 +915,161 @@ func testParse(data []byte, rule string) (*base.Node, error) {
 	}
 	return res, nil
 }
+func TestSMB2(t *testing.T) {
+	// NetBIOS Session Service + SMB2 NEGOTIATE Request
+	data := "00000068fe534d4240000000000000000000010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000024000200010000000000000011111111111111111111111111111111000000000000000002021002"
+	payload, err := codec.DecodeHex(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := parser.ParseBinary(bytes.NewReader(payload), "application-layer.smb2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	DumpNode(res)
+	resMap := NodeToMap(res)
+	for k, v := range map[string]any{
+		"Package/#0/NetBIOS Session Service/Length":    uint64(104),
+		"Package/#0/Messages/#0/Protocol Id":           []byte("\xfeSMB"),
+		"Package/#0/Messages/#0/Header/Structure Size": uint64(64),
+		"Package/#0/Messages/#0/Header/Command":        uint64(0),
+		"Package/#0/Messages/#0/Header/Credit":         uint64(1),
+		"Package/#0/Messages/#0/Body/Structure Size":   uint64(36),
+		"Package/#0/Messages/#0/Body/Data":             payload[4+64+2:],
+	} {
+		v1, ok := getSubData(resMap, k)
+		if !ok {
+			t.Fatalf("get %s failed", k)
+		}
+		assert.Equal(t, v, v1, k)
+	}
+}
+func TestKerberos(t *testing.T) {
+	// TCP: Record Mark + AS-REQ(pvno: 5, msg-type: 10)
+	// UDP: KRB-ERROR(pvno: 5, msg-type: 30)
+	for data, msgType := range map[string]uint64{
+		"0000000e6a0c300aa103020105a20302010a": 10,
+		"7e0c300aa103020105a20302011e":         30,
+	} {
+		payload, err := codec.DecodeHex(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := parser.ParseBinary(bytes.NewReader(payload), "application-layer.kerberos")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resMap := NodeToMap(res)
+		typ, ok := getSubData(resMap, "Package/#0/Kerberos Message/Type")
+		if !ok {
+			t.Fatal("get kerberos message type failed")
+		}
+		assert.Equal(t, 0x60+msgType, typ)
+		pvno, ok := getSubData(resMap, "Package/#0/Kerberos Message/Children/#0/Children/#0/Children/#0/Integer")
+		if !ok {
+			t.Fatal("get kerberos pvno failed")
+		}
+		assert.Equal(t, uint64(5), pvno)
+		v, ok := getSubData(resMap, "Package/#0/Kerberos Message/Children/#0/Children/#1/Children/#0/Integer")
+		if !ok {
+			t.Fatal("get kerberos msg-type failed")
+		}
+		assert.Equal(t, msgType, v)
+	}
+	payload, err := codec.DecodeHex("3084000000070201076000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := parser.ParseBinary(bytes.NewReader(payload), "application-layer.kerberos")
+	if err == nil {
+		_, err = res.Result()
+	}
+	assert.Error(t, err)
+}
+func TestMySQL(t *testing.T) {
+	// COM_QUERY + OK Packet
+	data := "210000000373656c65637420404076657273696f6e5f636f6d6d656e74206c696d69742031" + "0700000200000002000000"
+	payload, err := codec.DecodeHex(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := parser.ParseBinary(bytes.NewReader(payload), "application-layer.mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	DumpNode(res)
+	resMap := NodeToMap(res)
+	for k, v := range map[string]any{
+		"Package/#0/Payload Length": uint64(33),
+		"Package/#0/Sequence Id":    uint64(0),
+		"Package/#0/Payload/Header": uint64(3),
+		"Package/#0/Payload/Data":   []byte("select @@version_comment limit 1"),
+		"Package/#1/Payload Length": uint64(7),
+		"Package/#1/Sequence Id":    uint64(2),
+		"Package/#1/Payload/Header": uint64(0),
+	} {
+		v1, ok := getSubData(resMap, k)
+		if !ok {
+			t.Fatalf("get %s failed", k)
+		}
+		assert.Equal(t, v, v1, k)
+	}
+}
+func TestRESP(t *testing.T) {
+	payload := []byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n*1\r\n:1\r\n+OK\r\n-ERR unknown command\r\n$-1\r\n")
+	res, err := parser.ParseBinary(bytes.NewReader(payload), "application-layer.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	DumpNode(res)
+	resMap := NodeToMap(res)
+	for k, v := range map[string]any{
+		"Package/#0/Type":                          "*",
+		"Package/#0/Count":                         "3",
+		"Package/#0/Elements/#0/Data":              "SET",
+		"Package/#0/Elements/#1/Data":              "key",
+		"Package/#0/Elements/#2/Type":              "*",
+		"Package/#0/Elements/#2/Elements/#0/Value": "1",
+		"Package/#1/Value":                         "OK",
+		"Package/#2/Type":                          "-",
+		"Package/#2/Value":                         "ERR unknown command",
+		"Package/#3/Length":                        "-1",
+	} {
+		v1, ok := getSubData(resMap, k)
+		if !ok {
+			t.Fatalf("get %s failed", k)
+		}
+		assert.Equal(t, v, v1, k)
+	}
+}
+func TestPostgreSQL(t *testing.T) {
+	// StartupMessage + Query
+	data := "0000002500030000" + codec.EncodeToHex("user\x00postgres\x00database\x00test\x00\x00") + "510000000d" + codec.EncodeToHex("select 1\x00")
+	// AuthenticationOk + ReadyForQuery
+	data += "520000000800000000" + "5a0000000549"
+	payload, err := codec.DecodeHex(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := parser.ParseBinary(bytes.NewReader(payload), "application-layer.postgresql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	DumpNode(res)
+	resMap := NodeToMap(res)
+	for k, v := range map[string]any{
+		"Package/#0/Startup Message/Protocol Version": uint64(196608),
+		"Package/#0/Startup Message/Parameters":       []any{"user", "postgres", "database", "test", ""},
+		"Package/#1/Message/Type":                     "Q",
+		"Package/#1/Message/Body":                     []byte("select 1\x00"),
+		"Package/#2/Message/Type":                     "R",
+		"Package/#3/Message/Type":                     "Z",
+		"Package/#3/Message/Body":                     []byte("I"),
+	} {
+		v1, ok := getSubData(resMap, k)
+		if !ok {
+			t.Fatalf("get %s failed", k)
+		}
+		assert.Equal(t, v, v1, k)
+	}
+}
@@ -225,6 +225,8 @@ func (d *DefParser) Operate(operator *Operator, node *base.Node) error {
 				return err
 			}
 		}
+		// 嵌套的列表共享 Ctx，解析结束后需要恢复外层列表的状态
+		outerInList := node.Ctx.GetItem(CfgInList)
 		node.Ctx.SetItem(CfgInList, true)
 		if len(node.Children) == 0 {
 			return errors.New("get node element type error")
@@ -272,11 +274,14 @@ func (d *DefParser) Operate(operator *Operator, node *base.Node) error {
 				if err != nil {
 					return fmt.Errorf("get remaining space error: %w", err)
 				}
+				// 每个元素都有对应的备份点，结束时需要弹出，否则 DefParser 中的备份会不断累积
 				if l == 0 {
+					operator.PopBackup()
 					break
 				}
 				//cfgDeleteItem(element, CfgNodeResult)
 				if !node.Ctx.GetBool(CfgInList) {
+					operator.PopBackup()
 					break
 				}
 				err = operator.NodeParse(element)
@@ -293,6 +298,7 @@ func (d *DefParser) Operate(operator *Operator, node *base.Node) error {
 						return fmt.Errorf("parse list node index %d error: %w", index, err)
 					}
 				}
+				operator.PopBackup()
 				index++
 			}
 			return nil
@@ -300,8 +306,11 @@ func (d *DefParser) Operate(operator *Operator, node *base.Node) error {
 		if err != nil {
 			return fmt.Errorf("parse list node error: %w", err)
 		}
-		operator.PopBackup()
-		node.Ctx.DeleteItem(CfgInList)
+		if outerInList != nil {
+			node.Ctx.SetItem(CfgInList, outerInList)
+		} else {
+			node.Ctx.DeleteItem(CfgInList)
+		}
 		return nil
 	}
 	if node.Cfg.GetBool(CfgIsTerminal) {
@@ -497,10 +497,23 @@ func ExecOut(node *base.Node) (res *base.NodeValue, err error) {
 		"len": func(i interface{}) int {
 			return reflect.ValueOf(i).Len()
 		},
-		"data":           res,
-		"newStructValue": newStructNodeValue,
-		"newListValue":   newListNodeValue,
-		"newValue":       newNodeValue,
+		"data": res,
+		// out 脚本中构造的值没有对应的节点，使用当前节点作为 Origin，只替换名字
+		"newStructValue": func(name string, children ...*base.NodeValue) *base.NodeValue {
+			v := newStructNodeValue(node, children...)
+			v.Name = name
+			return v
+		},
+		"newListValue": func(name string, children ...*base.NodeValue) *base.NodeValue {
+			v := newListNodeValue(node, children...)
+			v.Name = name
+			return v
+		},
+		"newValue": func(name string, value any) *base.NodeValue {
+			v := newNodeValue(node, value)
+			v.Name = name
+			return v
+		},
 	}
 	engine := antlr4yak.New()
 	engine.ImportLibs(engineLib)
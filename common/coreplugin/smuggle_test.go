@@ -12,10 +12,12 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/google/uuid"
 	"github.com/yaklang/yaklang/common/consts"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/utils"
 	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/utils/lowhttp/poc"
 	"github.com/yaklang/yaklang/common/vulinbox"
 	"github.com/yaklang/yaklang/common/yak"
 	"github.com/yaklang/yaklang/common/yakgrpc"
@@ -260,3 +262,64 @@ func TestGRPCMUSTPASS_Smuggle_Plugin_Positive(t *testing.T) {
 		t.Fatal("risk not found")
 	}
 }
+
+func TestGRPCMUSTPASS_Smuggle_Plugin_DeepScan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	port := utils.GetRandomAvailableTCPPort()
+	go func() {
+		vulinbox.Smuggle(ctx, port)
+	}()
+	err := utils.WaitConnect(utils.HostPort("127.0.0.1", port), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initDB.Do(func() {
+		yakit.InitialDatabase()
+	})
+
+	codeBytes := GetCorePluginData("HTTP请求走私")
+	if codeBytes == nil {
+		t.Errorf("无法从bindata获取%v", "HTTP请求走私.yak")
+		return
+	}
+	runtimeId := uuid.New().String()
+	caller, err := yak.NewMixPluginCaller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caller.SetRuntimeId(runtimeId)
+	err = caller.LoadPluginByName(ctx, "HTTP请求走私", []*ypb.ExecParamItem{
+		{Key: "SMUGGLE_DEEP_SCAN", Value: "true"},
+	}, string(codeBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := "http://" + utils.HostPort("127.0.0.1", port) + "/"
+	rsp, _, err := poc.DoGET(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, body := lowhttp.SplitHTTPPacketFast(rsp.RawPacket)
+	caller.MirrorHTTPFlowEx(false, false, u, rsp.RawRequest, rsp.RawPacket, body)
+	caller.Wait()
+
+	// 开启深度探测后由 smuggle.Scan 代替 Pipeline 探测，不应再产生 Pipeline 风险
+	var checked = false
+	for r := range yakit.YieldRisksByRuntimeId(consts.GetGormProjectDatabase(), ctx, runtimeId) {
+		log.Infof("Risk: %v", r)
+		if r.Port != port {
+			continue
+		}
+		if strings.Contains(r.Title, "ErrPipeline") {
+			t.Fatalf("pipeline probe should be skipped when SMUGGLE_DEEP_SCAN is enabled: %v", r.Title)
+		}
+		if strings.HasPrefix(r.Title, "HTTP Request Smuggling") && r.RiskType == "http request smuggle" {
+			checked = true
+		}
+	}
+	if !checked {
+		t.Fatal("smuggle.Scan risk not found")
+	}
+}
@@ -61,6 +61,8 @@ func (c *Config) init() {
 	c.disallowMIMEType = ExcludedMIME
 	c.startFromParentPath = true
 	c.maxRetryTimes = 3
+	c.maxRelogin = 5
+	c.loggedOutDetector = &LoggedOutDetector{}
 	c.allowMethod = []string{"GET", "POST"}
 	c.allowUrlRegexp = make(map[string]*regexp.Regexp)
 	c.forbiddenUrlRegexp = make(map[string]*regexp.Regexp)
@@ -123,6 +125,13 @@ type Config struct {
 	onRequest func(req *Req)
 	onLogin   func(req *Req)
 
+	// 登录宏与掉线检测，在 NewCrawler 中生成 session
+	loginPackets      [][]byte
+	loginHttps        bool
+	loggedOutDetector *LoggedOutDetector
+	maxRelogin        int
+	session           *Session
+
 	extractionRules func(*Req) []interface{}
 	// appended links
 	extraPathForEveryPath     []string
@@ -159,8 +168,9 @@ func (c *Config) GetLowhttpConfig() []lowhttp.LowhttpOpt {
 	if c.maxRetryTimes > 0 {
 		opts = append(opts, lowhttp.WithRetryTimes(c.maxRetryTimes))
 	}
-	c._cachedOpts = opts
-	return opts
+	// 限制容量，调用方 append 时总是复制，避免并发请求之间互相覆盖
+	c._cachedOpts = opts[:len(opts):len(opts)]
+	return c._cachedOpts
 }
 
 func (c *Config) CheckShouldBeHandledURL(u *url.URL) bool {
@@ -587,15 +597,88 @@ func WithAutoLogin(username, password string, flags ...string) ConfigOpt {
 				req.err = err
 				return
 			}
-			for _, cookieItem := range lowhttp.ExtractCookieJarFromHTTPResponse(rspIns.RawPacket) {
+			cookies := lowhttp.ExtractCookieJarFromHTTPResponse(rspIns.RawPacket)
+			for _, cookieItem := range cookies {
 				c.cookie = append(c.cookie, &cookie{cookie: cookieItem, allowOverride: false})
 			}
+			// 没有设置登录宏时，使用自动登录的表单请求作为重新登录的登录宏
+			if c.session != nil && !c.session.HasLogin() {
+				c.session.SetLogin(NewRawRequestsLogin(req.IsHttps(), [][]byte{req.requestRaw}, c.GetLowhttpConfig()...), cookies)
+			}
 			req.responseRaw = rspIns.RawPacket
 			req.response, _ = utils.ReadHTTPResponseFromBytes(rspIns.RawPacket, req.request)
 		}
 	}
 }
 
+// loginRequests 是一个选项函数，用于指定登录宏：爬虫开始前按顺序发送这些原始请求（例如从 HTTP History 中选出的登录流程）进行登录，
+// 登录得到的 Cookie 会用于之后的所有请求，配合 loggedOutStatusCode / loggedOutBody / loggedOutRedirect 可以在掉线后自动重新登录并重试请求
+// Example:
+// ```
+// loginReq = `POST /login HTTP/1.1
+// Host: example.com
+// Content-Type: application/x-www-form-urlencoded
+//
+// username=admin&password=admin`
+// crawler.Start("https://example.com", crawler.loginRequests(true, loginReq), crawler.loggedOutRedirect(`/login`))
+// ```
+func WithLoginRequests(https bool, packets ...any) ConfigOpt {
+	return func(c *Config) {
+		c.loginHttps = https
+		for _, packet := range packets {
+			c.loginPackets = append(c.loginPackets, lowhttp.FixHTTPRequest(utils.InterfaceToBytes(packet)))
+		}
+	}
+}
+
+// loggedOutStatusCode 是一个选项函数，用于指定表示掉线的响应状态码，命中时会重新登录并重试请求
+// Example:
+// ```
+// crawler.Start("https://example.com", crawler.loginRequests(true, loginReq), crawler.loggedOutStatusCode(401, 403))
+// ```
+func WithLoggedOutStatusCode(codes ...int) ConfigOpt {
+	return func(c *Config) {
+		c.loggedOutDetector.AddStatusCode(codes...)
+	}
+}
+
+// loggedOutBody 是一个选项函数，用于指定表示掉线的响应内容正则，命中时会重新登录并重试请求
+// Example:
+// ```
+// crawler.Start("https://example.com", crawler.loginRequests(true, loginReq), crawler.loggedOutBody(`(?i)please\s+login`))
+// ```
+func WithLoggedOutBodyRegexp(rules ...string) ConfigOpt {
+	return func(c *Config) {
+		if err := c.loggedOutDetector.AddBodyRegexp(rules...); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// loggedOutRedirect 是一个选项函数，用于指定表示掉线的重定向地址（Location）正则，命中时会重新登录并重试请求
+// Example:
+// ```
+// crawler.Start("https://example.com", crawler.loginRequests(true, loginReq), crawler.loggedOutRedirect(`/login`))
+// ```
+func WithLoggedOutRedirectRegexp(rules ...string) ConfigOpt {
+	return func(c *Config) {
+		if err := c.loggedOutDetector.AddRedirectRegexp(rules...); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// maxRelogin 是一个选项函数，用于指定爬虫过程中最多重新登录的次数，默认为 5，小于等于 0 时不限制
+// Example:
+// ```
+// crawler.Start("https://example.com", crawler.loginRequests(true, loginReq), crawler.loggedOutStatusCode(401), crawler.maxRelogin(3))
+// ```
+func WithMaxRelogin(n int) ConfigOpt {
+	return func(c *Config) {
+		c.maxRelogin = n
+	}
+}
+
 func WithRuntimeID(id string) ConfigOpt {
 	return func(c *Config) {
 		c.runtimeID = id
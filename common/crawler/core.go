@@ -133,6 +133,9 @@ type Req struct {
 
 	// default
 	disallowedMITMType bool
+
+	// 配置了登录宏或掉线检测时，请求时的会话状态
+	sessionState string
 }
 
 func HostToWildcardGlobs(host string) []glob.Glob {
@@ -289,6 +292,13 @@ func NewCrawler(urls string, opts ...ConfigOpt) (*Crawler, error) {
 	if config.concurrent <= 0 {
 		config.concurrent = 20
 	}
+	if len(config.loginPackets) > 0 || !config.loggedOutDetector.Empty() {
+		var login LoginFunc
+		if len(config.loginPackets) > 0 {
+			login = NewRawRequestsLogin(config.loginHttps, config.loginPackets, append(config.GetLowhttpConfig(), lowhttp.WithRuntimeId(config.runtimeID))...)
+		}
+		config.session = NewSession(login, config.loggedOutDetector, config.maxRelogin)
+	}
 	c := &Crawler{
 		originUrls:       urlList,
 		config:           config,
@@ -319,6 +329,12 @@ func (c *Crawler) Run() error {
 	c.starting.Set()
 	defer c.starting.UnSet()
 
+	if session := c.config.session; session != nil && session.HasLogin() {
+		if err := session.Login(); err != nil {
+			log.Errorf("crawler login failed: %s", err)
+		}
+	}
+
 	swg := utils.NewSizedWaitGroup(2)
 	swg.Add()
 	swg.Add()
@@ -921,14 +937,14 @@ func (c *Crawler) execReq(r *Req) {
 
 	// config opts
 	opts := c.config.GetLowhttpConfig()
-	opts = append(opts, lowhttp.WithHttps(r.IsHttps()), lowhttp.WithPacketBytes(r.requestRaw), lowhttp.WithRuntimeId(c.config.runtimeID))
+	opts = append(opts, lowhttp.WithHttps(r.IsHttps()), lowhttp.WithRuntimeId(c.config.runtimeID))
 	if c.config.onLogin != nil && r.IsLoginForm() && r.IsForm() {
 		c.loginOnce.Do(func() {
 			c.config.onLogin(r)
 		})
 	}
 
-	lowRspIns, err := lowhttp.HTTP(opts...)
+	lowRspIns, err := c.execReqWithSession(r, opts)
 	if err != nil {
 		r.err = err
 		return
@@ -953,3 +969,43 @@ func (c *Crawler) execReq(r *Req) {
 		c.config.onRequest(r)
 	}
 }
+
+// execReqWithSession 使用当前的登录 Cookie 发送请求，发现掉线时重新登录并重试一次
+func (c *Crawler) execReqWithSession(r *Req, opts []lowhttp.LowhttpOpt) (*lowhttp.LowhttpResponse, error) {
+	session := c.config.session
+	if session == nil {
+		return lowhttp.HTTP(append(opts, lowhttp.WithPacketBytes(r.requestRaw))...)
+	}
+
+	do := func() (*lowhttp.LowhttpResponse, int64, error) {
+		cookies, version := session.Cookies()
+		r.requestRaw = ApplyCookiesToPacket(r.requestRaw, cookies)
+		rsp, err := lowhttp.HTTP(append(opts, lowhttp.WithPacketBytes(r.requestRaw))...)
+		return rsp, version, err
+	}
+
+	rsp, version, err := do()
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsLoggedOut(rsp) {
+		if session.LoggedIn() {
+			r.sessionState = SessionStateAuthenticated
+		}
+		return rsp, nil
+	}
+
+	r.sessionState = SessionStateLoggedOut
+	if err := session.Relogin(version); err != nil {
+		log.Errorf("crawler relogin failed: %s", err)
+		return rsp, nil
+	}
+	retryRsp, _, err := do()
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsLoggedOut(retryRsp) {
+		r.sessionState = SessionStateRelogin
+	}
+	return retryRsp, nil
+}
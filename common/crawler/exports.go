@@ -26,5 +26,10 @@ var Exports = map[string]interface{}{
 	"userAgent":           WithUserAgent,
 	"ua":                  WithUserAgent,
 	"autoLogin":           WithAutoLogin,
+	"loginRequests":       WithLoginRequests,
+	"loggedOutStatusCode": WithLoggedOutStatusCode,
+	"loggedOutBody":       WithLoggedOutBodyRegexp,
+	"loggedOutRedirect":   WithLoggedOutRedirectRegexp,
+	"maxRelogin":          WithMaxRelogin,
 	"RequestsFromFlow":    HandleRequestResult,
 }
@@ -92,3 +92,13 @@ func (r *Req) ResponseBody() []byte {
 func (r *Req) IsHttps() bool {
 	return r.https
 }
+
+// SessionState 返回请求时的会话状态，只有配置了登录宏或掉线检测时才有值：
+// "authenticated" 表示处于登录状态，"relogin" 表示掉线后重新登录并重试成功，"logged-out" 表示重新登录后仍然掉线
+// Example:
+// ```
+// req.SessionState()
+// ```
+func (r *Req) SessionState() string {
+	return r.sessionState
+}
@@ -0,0 +1,259 @@
+package crawler
+
+import (
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+// 请求结果中的会话状态，没有配置登录时为空
+const (
+	// SessionStateAuthenticated 请求时处于登录状态
+	SessionStateAuthenticated = "authenticated"
+	// SessionStateRelogin 请求时发现掉线，重新登录后重试成功
+	SessionStateRelogin = "relogin"
+	// SessionStateLoggedOut 请求时发现掉线，重新登录失败或重试后仍然掉线
+	SessionStateLoggedOut = "logged-out"
+)
+
+// LoggedOutDetector 根据状态码、响应内容与重定向地址判断会话是否已经失效，任意一条规则命中即认为掉线
+type LoggedOutDetector struct {
+	StatusCodes    []int
+	BodyRegexp     []*regexp.Regexp
+	RedirectRegexp []*regexp.Regexp
+}
+
+func (d *LoggedOutDetector) AddStatusCode(codes ...int) {
+	d.StatusCodes = append(d.StatusCodes, codes...)
+}
+
+func (d *LoggedOutDetector) AddBodyRegexp(rules ...string) error {
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule)
+		if err != nil {
+			return utils.Wrapf(err, "compile logged-out body regexp %#v failed", rule)
+		}
+		d.BodyRegexp = append(d.BodyRegexp, re)
+	}
+	return nil
+}
+
+func (d *LoggedOutDetector) AddRedirectRegexp(rules ...string) error {
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule)
+		if err != nil {
+			return utils.Wrapf(err, "compile logged-out redirect regexp %#v failed", rule)
+		}
+		d.RedirectRegexp = append(d.RedirectRegexp, re)
+	}
+	return nil
+}
+
+func (d *LoggedOutDetector) Empty() bool {
+	return d == nil || (len(d.StatusCodes) <= 0 && len(d.BodyRegexp) <= 0 && len(d.RedirectRegexp) <= 0)
+}
+
+// IsLoggedOutPacket 判断单个响应报文是否表示掉线
+func (d *LoggedOutDetector) IsLoggedOutPacket(packet []byte) bool {
+	if d.Empty() || len(packet) <= 0 {
+		return false
+	}
+	statusCode := lowhttp.GetStatusCodeFromResponse(packet)
+	for _, code := range d.StatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	if location := lowhttp.GetHTTPPacketHeader(packet, "Location"); location != "" {
+		for _, re := range d.RedirectRegexp {
+			if re.MatchString(location) {
+				return true
+			}
+		}
+	}
+	if len(d.BodyRegexp) > 0 {
+		_, body := lowhttp.SplitHTTPPacketFast(packet)
+		for _, re := range d.BodyRegexp {
+			if re.Match(body) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsLoggedOut 判断响应是否表示掉线，跟随重定向时会检查重定向链中的每一个响应
+func (d *LoggedOutDetector) IsLoggedOut(rsp *lowhttp.LowhttpResponse) bool {
+	if d.Empty() || rsp == nil {
+		return false
+	}
+	for _, flow := range rsp.RedirectRawPackets {
+		if d.IsLoggedOutPacket(flow.Response) {
+			return true
+		}
+	}
+	return d.IsLoggedOutPacket(rsp.RawPacket)
+}
+
+// LoginFunc 执行一次登录并返回登录后得到的 Cookie
+type LoginFunc func() ([]*http.Cookie, error)
+
+// NewRawRequestsLogin 返回按顺序重放原始请求（例如从 HTTP History 中选出的登录流程）的登录函数，
+// 前面请求得到的 Set-Cookie 会带到后面的请求中
+func NewRawRequestsLogin(https bool, packets [][]byte, opts ...lowhttp.LowhttpOpt) LoginFunc {
+	return func() ([]*http.Cookie, error) {
+		if len(packets) <= 0 {
+			return nil, utils.Error("login macro is empty")
+		}
+		var cookies []*http.Cookie
+		for index, packet := range packets {
+			packet = ApplyCookiesToPacket(packet, cookies)
+			reqOpts := append(append([]lowhttp.LowhttpOpt{}, opts...), lowhttp.WithHttps(https), lowhttp.WithPacketBytes(packet))
+			rsp, err := lowhttp.HTTP(reqOpts...)
+			if err != nil {
+				return nil, utils.Wrapf(err, "login macro request[%v] failed", index)
+			}
+			for _, flow := range rsp.RedirectRawPackets {
+				cookies = MergeCookieList(cookies, lowhttp.ExtractCookieJarFromHTTPResponse(flow.Response))
+			}
+			cookies = MergeCookieList(cookies, lowhttp.ExtractCookieJarFromHTTPResponse(rsp.RawPacket))
+		}
+		return cookies, nil
+	}
+}
+
+// MergeCookieList 合并 Cookie，同名 Cookie 使用 newer 中的值
+func MergeCookieList(origin []*http.Cookie, newer []*http.Cookie) []*http.Cookie {
+	var result []*http.Cookie
+	for _, c := range origin {
+		replaced := false
+		for _, n := range newer {
+			if n.Name == c.Name {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result = append(result, c)
+		}
+	}
+	return append(result, newer...)
+}
+
+// ApplyCookiesToPacket 将 Cookie 设置到请求报文中，同名 Cookie 会被覆盖
+func ApplyCookiesToPacket(packet []byte, cookies []*http.Cookie) []byte {
+	for _, c := range cookies {
+		packet = lowhttp.ReplaceHTTPPacketCookie(packet, c.Name, c.Value)
+	}
+	return packet
+}
+
+// ApplyCookiesToRequest 将 Cookie 设置到请求中，同名 Cookie 会被覆盖
+func ApplyCookiesToRequest(req *http.Request, cookies []*http.Cookie) {
+	if len(cookies) <= 0 {
+		return
+	}
+	req.Header.Set("Cookie", lowhttp.MergeCookies(MergeCookieList(req.Cookies(), cookies)...))
+}
+
+// Session 维护爬虫的登录态：保存登录得到的 Cookie，并在请求掉线时重新登录
+type Session struct {
+	login      LoginFunc
+	detector   *LoggedOutDetector
+	maxRelogin int
+
+	mutex      sync.Mutex
+	cookies    []*http.Cookie
+	version    int64
+	reloginNum int
+	loggedIn   bool
+}
+
+func NewSession(login LoginFunc, detector *LoggedOutDetector, maxRelogin int) *Session {
+	return &Session{
+		login:      login,
+		detector:   detector,
+		maxRelogin: maxRelogin,
+	}
+}
+
+func (s *Session) HasLogin() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.login != nil
+}
+
+// SetLogin 设置登录函数与当前已经得到的登录 Cookie
+func (s *Session) SetLogin(login LoginFunc, cookies []*http.Cookie) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.login = login
+	s.cookies = MergeCookieList(s.cookies, cookies)
+	s.loggedIn = true
+	s.version++
+}
+
+// Cookies 返回当前的登录 Cookie 与其版本号，版本号用于 Relogin 避免并发请求重复登录
+func (s *Session) Cookies() ([]*http.Cookie, int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cookies, s.version
+}
+
+func (s *Session) LoggedIn() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.loggedIn
+}
+
+func (s *Session) ReloginCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.reloginNum
+}
+
+func (s *Session) IsLoggedOut(rsp *lowhttp.LowhttpResponse) bool {
+	return s.detector.IsLoggedOut(rsp)
+}
+
+// Login 执行首次登录
+func (s *Session) Login() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.doLogin()
+}
+
+// Relogin 在掉线时重新登录，version 为发出请求时使用的 Cookie 版本号，
+// 如果其他请求已经重新登录过（版本号已经变化），则直接使用新的 Cookie
+func (s *Session) Relogin(version int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.version != version {
+		return nil
+	}
+	if s.maxRelogin > 0 && s.reloginNum >= s.maxRelogin {
+		return utils.Errorf("relogin too many times(%v)", s.reloginNum)
+	}
+	s.reloginNum++
+	log.Infof("session logged out, start to relogin (%v)", s.reloginNum)
+	return s.doLogin()
+}
+
+func (s *Session) doLogin() error {
+	if s.login == nil {
+		return utils.Error("login macro is not set")
+	}
+	cookies, err := s.login()
+	if err != nil {
+		s.loggedIn = false
+		return err
+	}
+	s.cookies = MergeCookieList(s.cookies, cookies)
+	s.loggedIn = true
+	s.version++
+	return nil
+}
@@ -0,0 +1,135 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+// newSessionTestServer 每个会话只能访问 2 个页面，之后重定向到登录页
+func newSessionTestServer(t *testing.T) (*httptest.Server, func() int) {
+	var mutex sync.Mutex
+	sessions := make(map[string]int)
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.URL.Path == "/login" {
+			if r.Method == http.MethodPost && r.PostFormValue("username") == "admin" && r.PostFormValue("password") == "admin" {
+				logins++
+				token := utils.RandStringBytes(16)
+				sessions[token] = 2
+				http.SetCookie(w, &http.Cookie{Name: "sid", Value: token})
+				http.Redirect(w, r, "/", http.StatusFound)
+				return
+			}
+			w.Write([]byte(`<form method="post" action="/login"><input name="username"><input name="password" type="password"></form>`))
+			return
+		}
+		sid, err := r.Cookie("sid")
+		if err != nil || sessions[sid.Value] <= 0 {
+			http.Redirect(w, r, "/login?expired=1", http.StatusFound)
+			return
+		}
+		sessions[sid.Value]--
+		if r.URL.Path == "/" {
+			w.Write([]byte(`<a href="/a">a</a><a href="/b">b</a><a href="/c">c</a><a href="/d">d</a>`))
+			return
+		}
+		w.Write([]byte("secret " + r.URL.Path))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return logins
+	}
+}
+
+func TestLoggedOutDetector(t *testing.T) {
+	d := &LoggedOutDetector{}
+	assert.True(t, d.Empty())
+	assert.False(t, d.IsLoggedOutPacket([]byte("HTTP/1.1 401 Unauthorized\r\n\r\n")))
+
+	d.AddStatusCode(401)
+	require.NoError(t, d.AddRedirectRegexp(`/login`))
+	require.NoError(t, d.AddBodyRegexp(`(?i)session\s+expired`))
+	require.Error(t, d.AddBodyRegexp(`(`))
+	assert.True(t, d.IsLoggedOutPacket([]byte("HTTP/1.1 401 Unauthorized\r\n\r\n")))
+	assert.True(t, d.IsLoggedOutPacket([]byte("HTTP/1.1 302 Found\r\nLocation: /login?next=/\r\n\r\n")))
+	assert.True(t, d.IsLoggedOutPacket([]byte("HTTP/1.1 200 OK\r\n\r\nyour Session Expired")))
+	assert.False(t, d.IsLoggedOutPacket([]byte("HTTP/1.1 302 Found\r\nLocation: /home\r\n\r\nok")))
+
+	// 跟随重定向时检查重定向链
+	assert.True(t, d.IsLoggedOut(&lowhttp.LowhttpResponse{
+		RawPacket:          []byte("HTTP/1.1 200 OK\r\n\r\nlogin page"),
+		RedirectRawPackets: []*lowhttp.RedirectFlow{{Response: []byte("HTTP/1.1 302 Found\r\nLocation: /login\r\n\r\n")}},
+	}))
+}
+
+func TestCrawlerRelogin(t *testing.T) {
+	server, logins := newSessionTestServer(t)
+	host, port, _ := utils.ParseStringToHostPort(server.URL)
+	loginPacket := fmt.Sprintf("POST /login HTTP/1.1\r\nHost: %v\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nusername=admin&password=admin", utils.HostPort(host, port))
+
+	var mutex sync.Mutex
+	states := make(map[string]string)
+	crawler, err := NewCrawler(
+		server.URL,
+		WithConcurrent(1),
+		WithLoginRequests(false, loginPacket),
+		WithLoggedOutRedirectRegexp(`/login`),
+		WithOnRequest(func(req *Req) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			states[req.Request().URL.Path] = req.SessionState()
+			if req.SessionState() != SessionStateLoggedOut {
+				_, body := lowhttp.SplitHTTPPacketFast(req.ResponseRaw())
+				assert.False(t, strings.Contains(string(body), "<form"), req.Url())
+			}
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, crawler.Run())
+
+	for _, path := range []string{"/", "/a", "/b", "/c", "/d"} {
+		assert.Contains(t, []string{SessionStateAuthenticated, SessionStateRelogin}, states[path], path)
+	}
+	assert.Greater(t, logins(), 1)
+	assert.Equal(t, logins()-1, crawler.config.session.ReloginCount())
+}
+
+func TestCrawlerReloginLimit(t *testing.T) {
+	server, _ := newSessionTestServer(t)
+	host, port, _ := utils.ParseStringToHostPort(server.URL)
+	badLogin := fmt.Sprintf("POST /login HTTP/1.1\r\nHost: %v\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nusername=admin&password=wrong", utils.HostPort(host, port))
+
+	var mutex sync.Mutex
+	var states []string
+	crawler, err := NewCrawler(
+		server.URL,
+		WithLoginRequests(false, badLogin),
+		WithLoggedOutRedirectRegexp(`/login`),
+		WithMaxRelogin(1),
+		WithOnRequest(func(req *Req) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			states = append(states, req.SessionState())
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, crawler.Run())
+	require.NotEmpty(t, states)
+	for _, state := range states {
+		assert.Equal(t, SessionStateLoggedOut, state)
+	}
+	assert.Equal(t, 1, crawler.config.session.ReloginCount())
+}
@@ -5,6 +5,7 @@ package crawlerx
 import (
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/yaklang/yaklang/common/crawlerx/tools"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/utils"
 	"strings"
@@ -347,37 +348,66 @@ func (starter *BrowserStarter) generateGetEventElements() func(*rod.Page) ([]str
 
 func (starter *BrowserStarter) generateUrlsExploit() func(string, string) error {
 	return func(originUrl string, targetUrl string) error {
-		if starter.stopSignal {
-			return nil
-		}
-		for k, f := range starter.urlCheck {
-			afterUrl := starter.urlAfterRepeat(targetUrl)
-			if !f(afterUrl) {
-				log.Debugf(`%s ban url: %s`, k, targetUrl)
-				if !starter.banList.Exist(targetUrl) {
-					starter.banList.Insert(targetUrl)
-				}
-				return nil
+		return starter.submitUrl(originUrl, targetUrl, tools.TransitionNavigate, "")
+	}
+}
+
+// submitUrl check target url and send to url channel, record transition to state graph if url in range
+func (starter *BrowserStarter) submitUrl(originUrl, targetUrl, action, selector string) error {
+	if starter.stopSignal {
+		return nil
+	}
+	repeated := false
+	for k, f := range starter.urlCheck {
+		afterUrl := starter.urlAfterRepeat(targetUrl)
+		if !f(afterUrl) {
+			if k == "repeat_url" {
+				repeated = true
+				continue
 			}
+			log.Debugf(`%s ban url: %s`, k, targetUrl)
+			if !starter.banList.Exist(targetUrl) {
+				starter.banList.Insert(targetUrl)
+			}
+			return nil
 		}
-		starter.urlTree.Add(originUrl, targetUrl)
-		select {
-		case <-starter.ctx.Done():
-			return utils.Error("context deadline exceed")
-		default:
-			starter.uChan.In <- targetUrl
+	}
+	if starter.stateGraph != nil {
+		starter.stateGraph.AddTransition(starter.stateGraph.LatestState(originUrl), targetUrl, nil, action, selector)
+	}
+	if repeated {
+		log.Debugf(`repeat_url ban url: %s`, targetUrl)
+		if !starter.banList.Exist(targetUrl) {
+			starter.banList.Insert(targetUrl)
 		}
 		return nil
 	}
+	starter.urlTree.Add(originUrl, targetUrl)
+	select {
+	case <-starter.ctx.Done():
+		return utils.Error("context deadline exceed")
+	default:
+		starter.uChan.In <- targetUrl
+	}
+	return nil
 }
 
 func (starter *BrowserStarter) generateClickElementsExploit() func(*rod.Page, string, string) error {
 	return func(page *rod.Page, originUrl string, clickSelector string) error {
+		// record state before click, the transition starts from it
+		var fromState *tools.PageState
+		if starter.stateGraph != nil {
+			fromState, _ = starter.recordPageState(page, originUrl)
+		}
 		status := starter.clickElementOnPageBySelector(page, clickSelector)
 		if !status {
 			return nil
 		}
 		currentUrl, _ := getCurrentUrl(page)
+		if currentUrl != "" && starter.stateGraph != nil {
+			toState, _ := starter.recordPageState(page, currentUrl)
+			starter.stateGraph.AddTransition(fromState, currentUrl, toState, tools.TransitionClick, clickSelector)
+		}
 		// analysis page after click
 		if currentUrl != "" && currentUrl != originUrl {
 			if !starter.urlTree.Has(currentUrl) {
@@ -515,7 +545,7 @@ func (starter *BrowserStarter) newEventElementsExploit() func(*rod.Page, string,
 			if starter.banList.Exist(currentUrl) {
 				return nil
 			}
-			err := starter.urlsExploit(originUrl, currentUrl)
+			err := starter.submitUrl(originUrl, currentUrl, tools.TransitionClick, eventSelector)
 			if err != nil {
 				return utils.Errorf(`Url %v from %v exploit error: %v`, currentUrl, originUrl, err.Error())
 			}
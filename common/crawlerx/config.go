@@ -6,9 +6,11 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/yaklang/yaklang/common/crawler"
 	"github.com/yaklang/yaklang/common/crawlerx/tools"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
 	"net/url"
 	"regexp"
 )
@@ -47,6 +49,13 @@ type BaseConfig struct {
 	jsResultSave      func(string)
 	vue               bool
 	response          map[string]string
+	loginPackets      [][]byte
+	loginHttps        bool
+	loginActions      []*LoginAction
+	loggedOutDetector *crawler.LoggedOutDetector
+	maxRelogin        int
+	stateGraphEnable  bool
+	incremental       bool
 
 	targetUrl      string
 	ch             chan ReqInfo
@@ -55,6 +64,8 @@ type BaseConfig struct {
 	resultSent     *tools.StringCountFilter
 	uChan          *tools.UChan
 	urlTree        *tools.UrlTree
+	stateGraph     *tools.StateGraph
+	previousGraph  *tools.StateGraph
 	waitGroup      *utils.SizedWaitGroup
 	startWaitGroup *utils.SizedWaitGroup
 }
@@ -99,6 +110,8 @@ func NewConfig() *Config {
 			evalJs:            make(map[string][]string),
 			vue:               false,
 			response:          make(map[string]string),
+			loggedOutDetector: &crawler.LoggedOutDetector{},
+			maxRelogin:        5,
 		},
 	}
 }
@@ -309,6 +322,73 @@ func WithInvalidSuffix(suffix []string) ConfigOpt {
 	}
 }
 
+// WithLoginRequests set login macro with raw requests (e.g. selected from http history), replayed in order before crawling and when logged out
+func WithLoginRequests(https bool, packets ...any) ConfigOpt {
+	return func(config *Config) {
+		config.baseConfig.loginHttps = https
+		for _, packet := range packets {
+			config.baseConfig.loginPackets = append(config.baseConfig.loginPackets, lowhttp.FixHTTPRequest(utils.InterfaceToBytes(packet)))
+		}
+	}
+}
+
+// WithLoginActions set login macro with recorded browser actions in json, e.g.
+// [{"action":"navigate","value":"http://example.com/login"},{"action":"input","selector":"#username","value":"admin"},{"action":"click","selector":"#submit"}]
+func WithLoginActions(data string) ConfigOpt {
+	actions, err := ParseLoginActions(data)
+	if err != nil {
+		log.Errorf("parse login actions error: %v", err)
+		return func(*Config) {}
+	}
+	return func(config *Config) {
+		config.baseConfig.loginActions = append(config.baseConfig.loginActions, actions...)
+	}
+}
+
+func WithLoggedOutStatusCode(codes ...int) ConfigOpt {
+	return func(config *Config) {
+		config.baseConfig.loggedOutDetector.AddStatusCode(codes...)
+	}
+}
+
+func WithLoggedOutBodyRegexp(rules ...string) ConfigOpt {
+	return func(config *Config) {
+		err := config.baseConfig.loggedOutDetector.AddBodyRegexp(rules...)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func WithLoggedOutRedirectRegexp(rules ...string) ConfigOpt {
+	return func(config *Config) {
+		err := config.baseConfig.loggedOutDetector.AddRedirectRegexp(rules...)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func WithMaxRelogin(n int) ConfigOpt {
+	return func(config *Config) {
+		config.baseConfig.maxRelogin = n
+	}
+}
+
+// WithStateGraph record crawl result as state graph (page states, dom hash, transitions, forms) and save to project database
+func WithStateGraph(enable bool) ConfigOpt {
+	return func(config *Config) {
+		config.baseConfig.stateGraphEnable = enable
+	}
+}
+
+// WithIncremental load previous state graph of target from project database, unchanged page states will not be explored again
+func WithIncremental(incremental bool) ConfigOpt {
+	return func(config *Config) {
+		config.baseConfig.incremental = incremental
+	}
+}
+
 // transport in code
 
 func WithTargetUrl(targetUrl string) ConfigOpt {
@@ -353,6 +433,13 @@ func WithUrlTree(tree *tools.UrlTree) ConfigOpt {
 	}
 }
 
+func WithStateGraphs(current, previous *tools.StateGraph) ConfigOpt {
+	return func(config *Config) {
+		config.baseConfig.stateGraph = current
+		config.baseConfig.previousGraph = previous
+	}
+}
+
 func WithPageSizedWaitGroup(pageSizedWaitGroup *utils.SizedWaitGroup) ConfigOpt {
 	return func(config *Config) {
 		config.baseConfig.waitGroup = pageSizedWaitGroup
@@ -3,8 +3,10 @@
 package crawlerx
 
 var CrawlerXExports = map[string]interface{}{
-	"StartCrawler":   StartCrawler,
-	"PageScreenShot": NewPageScreenShot,
+	"StartCrawler":     StartCrawler,
+	"PageScreenShot":   NewPageScreenShot,
+	"LoadStateGraph":   LoadStateGraph,
+	"ExportStateGraph": ExportStateGraph,
 
 	"browserInfo":       WithBrowserInfo,
 	"saveToDB":          WithSaveToDB,
@@ -37,6 +39,15 @@ var CrawlerXExports = map[string]interface{}{
 	"vue":               WithVue,
 	"response":          WithResponse,
 
+	"loginRequests":       WithLoginRequests,
+	"loginActions":        WithLoginActions,
+	"loggedOutStatusCode": WithLoggedOutStatusCode,
+	"loggedOutBody":       WithLoggedOutBodyRegexp,
+	"loggedOutRedirect":   WithLoggedOutRedirectRegexp,
+	"maxRelogin":          WithMaxRelogin,
+	"stateGraph":          WithStateGraph,
+	"incremental":         WithIncremental,
+
 	"UnLimitRepeat":      unlimited,
 	"LowRepeatLevel":     lowLevel,
 	"MediumRepeatLevel":  midLevel,
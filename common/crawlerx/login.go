@@ -0,0 +1,242 @@
+// Package crawlerx
+package crawlerx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/yaklang/yaklang/common/crawler"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+const (
+	loginActionNavigate = "navigate"
+	loginActionInput    = "input"
+	loginActionClick    = "click"
+	loginActionWait     = "wait"
+)
+
+// LoginAction is one step of recorded browser login:
+// navigate(value: url) / input(selector, value) / click(selector) / wait(selector or value: milliseconds)
+type LoginAction struct {
+	Action   string `json:"action"`
+	Selector string `json:"selector,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+func ParseLoginActions(data string) ([]*LoginAction, error) {
+	var actions []*LoginAction
+	err := json.Unmarshal([]byte(data), &actions)
+	if err != nil {
+		return nil, utils.Errorf(`unmarshal login actions error: %v`, err)
+	}
+	if len(actions) == 0 {
+		return nil, utils.Error(`login actions empty`)
+	}
+	for index, action := range actions {
+		if action == nil {
+			return nil, utils.Errorf(`login action %d empty`, index)
+		}
+		action.Action = strings.ToLower(strings.TrimSpace(action.Action))
+		switch action.Action {
+		case loginActionNavigate:
+			if action.Value == "" {
+				return nil, utils.Errorf(`login action %d navigate url empty`, index)
+			}
+		case loginActionInput, loginActionClick:
+			if action.Selector == "" {
+				return nil, utils.Errorf(`login action %d %s selector empty`, index, action.Action)
+			}
+		case loginActionWait:
+			if action.Selector != "" {
+				continue
+			}
+			if _, err := strconv.Atoi(action.Value); err != nil {
+				return nil, utils.Errorf(`login action %d wait time %#v invalid`, index, action.Value)
+			}
+		default:
+			return nil, utils.Errorf(`login action %d type %#v not supported`, index, action.Action)
+		}
+	}
+	return actions, nil
+}
+
+func (starter *BrowserStarter) createSession() {
+	baseConfig := starter.baseConfig
+	var login crawler.LoginFunc
+	if len(baseConfig.loginActions) > 0 {
+		login = starter.browserLogin
+	} else if len(baseConfig.loginPackets) > 0 {
+		opts := []lowhttp.LowhttpOpt{
+			lowhttp.WithTimeout(30 * time.Second),
+			lowhttp.WithSaveHTTPFlow(starter.saveToDB),
+			lowhttp.WithSource("crawlerx"),
+		}
+		if starter.browserConfig.proxyAddress != nil {
+			opts = append(opts, lowhttp.WithProxy(starter.browserConfig.proxyAddress.String()))
+		}
+		if starter.runtimeID != "" {
+			opts = append(opts, lowhttp.WithRuntimeId(starter.runtimeID))
+		}
+		login = starter.browserCookiesSync(crawler.NewRawRequestsLogin(baseConfig.loginHttps, baseConfig.loginPackets, opts...))
+	}
+	if login == nil && baseConfig.loggedOutDetector.Empty() {
+		return
+	}
+	starter.session = crawler.NewSession(login, baseConfig.loggedOutDetector, baseConfig.maxRelogin)
+}
+
+// browserCookiesSync set cookies got by raw requests login to browser
+func (starter *BrowserStarter) browserCookiesSync(login crawler.LoginFunc) crawler.LoginFunc {
+	return func() ([]*http.Cookie, error) {
+		cookies, err := login()
+		if err != nil {
+			return nil, err
+		}
+		params := make([]*proto.NetworkCookieParam, 0)
+		for _, cookie := range cookies {
+			params = append(params, &proto.NetworkCookieParam{Name: cookie.Name, Value: cookie.Value, URL: starter.baseUrl})
+		}
+		if len(params) > 0 {
+			err = starter.browser.SetCookies(params)
+			if err != nil {
+				log.Errorf(`browser set login cookies error: %v`, err)
+			}
+		}
+		return cookies, nil
+	}
+}
+
+func (starter *BrowserStarter) browserLogin() ([]*http.Cookie, error) {
+	page, err := starter.browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		return nil, utils.Errorf(`create login page error: %v`, err)
+	}
+	// requests on login page use browser cookies, do not crawl login page
+	starter.loginPages.Store(string(page.TargetID), struct{}{})
+	starter.loginPages.Store(string(page.FrameID), struct{}{})
+	defer func() {
+		_ = page.Close()
+		starter.loginPages.Delete(string(page.TargetID))
+		starter.loginPages.Delete(string(page.FrameID))
+	}()
+	actionPage := page
+	if starter.baseConfig.pageTimeout != 0 {
+		actionPage = page.Timeout(time.Duration(starter.baseConfig.pageTimeout) * time.Second)
+	}
+	for index, action := range starter.baseConfig.loginActions {
+		err = starter.doLoginAction(actionPage, action)
+		if err != nil {
+			return nil, utils.Errorf(`login action %d %s error: %v`, index, action.Action, err)
+		}
+	}
+	networkCookies, err := page.Cookies([]string{starter.baseUrl})
+	if err != nil {
+		return nil, utils.Errorf(`get login cookies error: %v`, err)
+	}
+	cookies := make([]*http.Cookie, 0)
+	for _, cookie := range networkCookies {
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return cookies, nil
+}
+
+func (starter *BrowserStarter) doLoginAction(page *rod.Page, action *LoginAction) error {
+	waitLoad := func() error {
+		err := page.WaitLoad()
+		if err != nil {
+			return err
+		}
+		if starter.extraWaitLoadTime != 0 {
+			time.Sleep(time.Duration(starter.extraWaitLoadTime) * time.Millisecond)
+		}
+		return nil
+	}
+	switch action.Action {
+	case loginActionNavigate:
+		err := page.Navigate(action.Value)
+		if err != nil {
+			return err
+		}
+		return waitLoad()
+	case loginActionInput:
+		element, err := page.Element(action.Selector)
+		if err != nil {
+			return err
+		}
+		err = element.SelectAllText()
+		if err != nil {
+			return err
+		}
+		return element.Input(action.Value)
+	case loginActionClick:
+		element, err := page.Element(action.Selector)
+		if err != nil {
+			return err
+		}
+		err = element.Click(proto.InputMouseButtonLeft, 1)
+		if err != nil {
+			return err
+		}
+		return waitLoad()
+	case loginActionWait:
+		if action.Selector != "" {
+			_, err := page.Element(action.Selector)
+			return err
+		}
+		wait, _ := strconv.Atoi(action.Value)
+		time.Sleep(time.Duration(wait) * time.Millisecond)
+	}
+	return nil
+}
+
+func (starter *BrowserStarter) isLoginPage(id string) bool {
+	_, ok := starter.loginPages.Load(id)
+	return ok
+}
+
+// loadResponseWithSession load response with session cookies, relogin and retry once when logged out
+func (starter *BrowserStarter) loadResponseWithSession(hijack *CrawlerHijack, opts []lowhttp.LowhttpOpt) (string, error) {
+	session := starter.session
+	if session == nil || starter.isLoginPage(string(hijack.Request.event.FrameID)) {
+		_, err := hijack.LoadLowhttpResponse(opts, true)
+		return "", err
+	}
+	load := func() (*lowhttp.LowhttpResponse, int64, error) {
+		cookies, version := session.Cookies()
+		crawler.ApplyCookiesToRequest(hijack.Request.Req(), cookies)
+		rsp, err := hijack.LoadLowhttpResponse(opts, true)
+		return rsp, version, err
+	}
+	rsp, version, err := load()
+	if err != nil {
+		return "", err
+	}
+	if !session.IsLoggedOut(rsp) {
+		if session.LoggedIn() {
+			return crawler.SessionStateAuthenticated, nil
+		}
+		return "", nil
+	}
+	err = session.Relogin(version)
+	if err != nil {
+		log.Errorf(`crawlerx relogin error: %v`, err)
+		return crawler.SessionStateLoggedOut, nil
+	}
+	hijack.Request.SetBody(hijack.Request.Body())
+	rsp, _, err = load()
+	if err != nil {
+		return "", err
+	}
+	if session.IsLoggedOut(rsp) {
+		return crawler.SessionStateLoggedOut, nil
+	}
+	return crawler.SessionStateRelogin, nil
+}
@@ -0,0 +1,35 @@
+// Package crawlerx
+package crawlerx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLoginActions(t *testing.T) {
+	actions, err := ParseLoginActions(`[
+		{"action": "navigate", "value": "http://127.0.0.1/login"},
+		{"action": "Input", "selector": "#username", "value": "admin"},
+		{"action": "input", "selector": "#password", "value": "admin"},
+		{"action": "click", "selector": "#submit"},
+		{"action": "wait", "value": "500"},
+		{"action": "wait", "selector": "#logout"}
+	]`)
+	require.NoError(t, err)
+	assert.Len(t, actions, 6)
+	assert.Equal(t, loginActionInput, actions[1].Action)
+
+	for _, data := range []string{
+		``,
+		`[]`,
+		`[{"action": "navigate"}]`,
+		`[{"action": "click", "value": "#submit"}]`,
+		`[{"action": "wait", "value": "soon"}]`,
+		`[{"action": "hover", "selector": "#menu"}]`,
+	} {
+		_, err = ParseLoginActions(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -71,6 +71,17 @@ func NewCrawlerCore(targetUrl string, opts ...ConfigOpt) (*CrawlerCore, error) {
 	}
 	WithTargetUrl(checkedUrl)(config)
 	WithUrlTree(tools.CreateTree(checkedUrl))(config)
+	if config.baseConfig.stateGraphEnable || config.baseConfig.incremental {
+		var previous *tools.StateGraph
+		if config.baseConfig.incremental {
+			previous, err = LoadStateGraph(checkedUrl)
+			if err != nil {
+				log.Infof(`load previous state graph of %v error: %v, crawl all states`, checkedUrl, err)
+				previous = nil
+			}
+		}
+		WithStateGraphs(tools.NewStateGraph(checkedUrl), previous)(config)
+	}
 	core := CrawlerCore{
 		targetUrl:      checkedUrl,
 		config:         config,
@@ -113,6 +124,12 @@ func (core *CrawlerCore) Start() {
 	time.Sleep(500 * time.Millisecond)
 	core.waitGroup.Wait()
 	core.cancel()
+	if graph := core.config.baseConfig.stateGraph; graph != nil {
+		err := saveStateGraph(graph, core.config.baseConfig.runtimeId)
+		if err != nil {
+			log.Errorf(`save state graph error: %v`, err)
+		}
+	}
 	close(core.uChan.In)
 	close(core.ch)
 	log.Debug(`Close uChan & channel.`)
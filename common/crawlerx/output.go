@@ -13,9 +13,10 @@ type OutputResults struct {
 }
 
 type OutputResult struct {
-	Url      string         `json:"url"`
-	Request  OutputRequest  `json:"request"`
-	Response OutputResponse `json:"response"`
+	Url          string         `json:"url"`
+	Request      OutputRequest  `json:"request"`
+	Response     OutputResponse `json:"response"`
+	SessionState string         `json:"session_state,omitempty"`
 }
 
 type OutputRequest struct {
@@ -59,7 +60,8 @@ func GeneratorOutput(reqInfo ReqInfo) *OutputResult {
 		log.Errorf("get http raw error: %v", err)
 	}
 	result := OutputResult{
-		Url: reqInfo.Url(),
+		Url:          reqInfo.Url(),
+		SessionState: reqInfo.SessionState(),
 		Request: OutputRequest{
 			Url:     reqInfo.Url(),
 			Method:  reqInfo.Method(),
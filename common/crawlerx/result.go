@@ -26,15 +26,19 @@ type ReqInfo interface {
 	Screenshot() string
 
 	From() string
+
+	// SessionState is "authenticated" / "relogin" / "logged-out" when login macro or logged-out detector set
+	SessionState() string
 }
 
 type RequestResult struct {
 	// request  *rod.HijackRequest
 	// response *rod.HijackResponse
 
-	request  HijackRequest
-	response HijackResponse
-	from     string
+	request      HijackRequest
+	response     HijackResponse
+	from         string
+	sessionState string
 }
 
 func (result *RequestResult) Url() string {
@@ -111,6 +115,10 @@ func (result *RequestResult) From() string {
 	return result.from
 }
 
+func (result *RequestResult) SessionState() string {
+	return result.sessionState
+}
+
 type SimpleResult struct {
 	url        string
 	screenshot string
@@ -182,3 +190,7 @@ func (*SimpleResult) StatusCode() int {
 func (simpleResult *SimpleResult) From() string {
 	return simpleResult.from
 }
+
+func (*SimpleResult) SessionState() string {
+	return ""
+}
@@ -197,18 +197,25 @@ func (hijack *CrawlerHijack) ContinueRequest(cq *proto.FetchContinueRequest) {
 }
 
 func (hijack *CrawlerHijack) LoadResponse(opts []lowhttp.LowhttpOpt, loadBody bool) error {
+	_, err := hijack.LoadLowhttpResponse(opts, loadBody)
+	return err
+}
+
+// LoadLowhttpResponse same as LoadResponse, return lowhttp response for logged-out detection
+func (hijack *CrawlerHijack) LoadLowhttpResponse(opts []lowhttp.LowhttpOpt, loadBody bool) (*lowhttp.LowhttpResponse, error) {
 	opts = append(opts, lowhttp.WithRequest(hijack.Request.req))
 	lowHttpResponse, err := lowhttp.HTTP(
 		opts...,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	res, err := lowhttp.ParseBytesToHTTPResponse(lowHttpResponse.RawPacket)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	hijack.Response.payload.ResponseCode = res.StatusCode
+	hijack.Response.payload.ResponseHeaders = nil
 	list := []string{}
 	for k, vs := range res.Header {
 		for _, v := range vs {
@@ -219,11 +226,11 @@ func (hijack *CrawlerHijack) LoadResponse(opts []lowhttp.LowhttpOpt, loadBody bo
 	if loadBody {
 		b, err := io.ReadAll(res.Body)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		hijack.Response.payload.Body = b
 	}
-	return nil
+	return lowHttpResponse, nil
 }
 
 type CrawlerHijackRequest struct {
@@ -15,9 +15,11 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/yaklang/yaklang/common/crawler"
 	"github.com/yaklang/yaklang/common/crawlerx/tools"
 	"github.com/yaklang/yaklang/common/utils"
 )
@@ -85,6 +87,12 @@ type BrowserStarter struct {
 	https        bool
 	evalJs       []*JSEval
 	jsResultSend func(string)
+
+	session    *crawler.Session
+	loginPages *sync.Map
+
+	stateGraph    *tools.StateGraph
+	previousGraph *tools.StateGraph
 }
 
 func NewBrowserStarter(browserConfig *BrowserConfig, baseConfig *BaseConfig) *BrowserStarter {
@@ -99,9 +107,11 @@ func NewBrowserStarter(browserConfig *BrowserConfig, baseConfig *BaseConfig) *Br
 		urlCheck: make(map[string]func(string) bool),
 		banList:  tools.NewCountFilter(),
 
-		urlTree: baseConfig.urlTree,
-		uChan:   baseConfig.uChan,
-		ch:      baseConfig.ch,
+		urlTree:       baseConfig.urlTree,
+		stateGraph:    baseConfig.stateGraph,
+		previousGraph: baseConfig.previousGraph,
+		uChan:         baseConfig.uChan,
+		ch:            baseConfig.ch,
 
 		formFill:   baseConfig.formFill,
 		fileUpload: baseConfig.fileInput,
@@ -127,6 +137,8 @@ func NewBrowserStarter(browserConfig *BrowserConfig, baseConfig *BaseConfig) *Br
 		https:     false,
 
 		evalJs: make([]*JSEval, 0),
+
+		loginPages: new(sync.Map),
 	}
 	var ctx context.Context
 	var cancel context.CancelFunc
@@ -177,6 +189,7 @@ func (starter *BrowserStarter) startBrowser() error {
 	}
 	starter.pageActionGenerator()
 	starter.pageDetectEventGenerator()
+	starter.createSession()
 	return nil
 }
 
@@ -292,6 +305,9 @@ func (starter *BrowserStarter) scanCreatedTarget(targetID proto.TargetTargetID)
 		},
 	)()
 	time.Sleep(500 * time.Millisecond)
+	if starter.isLoginPage(string(targetID)) {
+		return
+	}
 	err = page.WaitLoad()
 	if err != nil {
 		log.Errorf(`TargetID %s get page wait load error: %s`, targetID, err)
@@ -350,6 +366,13 @@ func (starter *BrowserStarter) scanCreatedTarget(targetID proto.TargetTargetID)
 	if starter.baseConfig.pageTimeout != 0 {
 		page = page.Timeout(time.Duration(starter.baseConfig.pageTimeout) * time.Second)
 	}
+	if starter.stateGraph != nil {
+		if state, unchanged := starter.recordPageState(page, urlStr); unchanged {
+			log.Debugf(`page %v not changed, replay previous transitions`, urlStr)
+			starter.replayTransitions(state)
+			return
+		}
+	}
 	//err = starter.actionOnPage(page)
 	err = starter.ActionOnPage(page)
 	if err != nil {
@@ -403,7 +426,7 @@ func (starter *BrowserStarter) createBrowserHijack(browser *rod.Browser) error {
 		if starter.runtimeID != "" {
 			opts = append(opts, lowhttp.WithRuntimeId(starter.runtimeID))
 		}
-		err := hijack.LoadResponse(opts, true)
+		sessionState, err := starter.loadResponseWithSession(hijack, opts)
 		if err != nil {
 			if !strings.Contains(err.Error(), "context canceled") {
 				log.Errorf("load response error: %s", err)
@@ -470,6 +493,7 @@ func (starter *BrowserStarter) createBrowserHijack(browser *rod.Browser) error {
 		result := RequestResult{}
 		result.request = hijack.Request
 		result.response = hijack.Response
+		result.sessionState = sessionState
 		//result.from = pageUrl
 		select {
 		case <-starter.ctx.Done():
@@ -510,6 +534,12 @@ func (starter *BrowserStarter) Start() {
 	} else {
 		log.Debug("stealth.min.js load done!")
 	}
+	if starter.session != nil && starter.session.HasLogin() {
+		err = starter.session.Login()
+		if err != nil {
+			log.Errorf("crawlerx login error: %v", err)
+		}
+	}
 	starter.baseConfig.startWaitGroup.Done()
 running:
 	for {
@@ -0,0 +1,103 @@
+// Package crawlerx
+package crawlerx
+
+import (
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/crawlerx/tools"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+// recordPageState record current page state to state graph, return the state and whether it exists in previous graph
+func (starter *BrowserStarter) recordPageState(page *rod.Page, urlStr string) (*tools.PageState, bool) {
+	html, err := page.HTML()
+	if err != nil {
+		log.Errorf(`page %v get html error: %v`, urlStr, err)
+		return nil, false
+	}
+	var title string
+	info, err := page.Info()
+	if err == nil && info != nil {
+		title = info.Title
+	}
+	domHash := tools.DomHash(html)
+	state := starter.stateGraph.AddState(urlStr, title, domHash, tools.ExtractForms(html))
+	if starter.previousGraph == nil {
+		return state, false
+	}
+	if starter.previousGraph.GetState(urlStr, domHash) == nil {
+		state.Changed = true
+		return state, false
+	}
+	return state, true
+}
+
+// replayTransitions reuse transitions of unchanged page state from previous graph, only load target pages without actions,
+// transitions staying on the same url (dom changed by action) can not be replayed by loading url and are skipped
+func (starter *BrowserStarter) replayTransitions(state *tools.PageState) {
+	for _, transition := range starter.previousGraph.OutTransitions(state.ID) {
+		if transition.Url == state.Url {
+			continue
+		}
+		err := starter.submitUrl(state.Url, transition.Url, transition.Action, transition.Selector)
+		if err != nil {
+			log.Errorf(`replay transition %v -> %v error: %v`, state.Url, transition.Url, err)
+			return
+		}
+	}
+}
+
+// LoadStateGraph load latest state graph of target from project database
+func LoadStateGraph(target string) (*tools.StateGraph, error) {
+	db := consts.GetGormProjectDatabase()
+	if db == nil {
+		return nil, utils.Error(`project database not found`)
+	}
+	record, err := yakit.GetLatestCrawlerStateGraph(db, target)
+	if err != nil {
+		return nil, err
+	}
+	return tools.ParseStateGraph([]byte(record.Graph))
+}
+
+// ExportStateGraph export latest state graph of target in json or dot format
+func ExportStateGraph(target string, format string) (string, error) {
+	graph, err := LoadStateGraph(target)
+	if err != nil {
+		return "", err
+	}
+	switch strings.ToLower(format) {
+	case "dot":
+		return graph.DOT(), nil
+	case "json", "":
+		raw, err := graph.JSON()
+		if err != nil {
+			return "", err
+		}
+		return string(raw), nil
+	default:
+		return "", utils.Errorf(`state graph format %v not supported`, format)
+	}
+}
+
+func saveStateGraph(graph *tools.StateGraph, runtimeId string) error {
+	db := consts.GetGormProjectDatabase()
+	if db == nil {
+		return utils.Error(`project database not found`)
+	}
+	raw, err := graph.JSON()
+	if err != nil {
+		return utils.Errorf(`marshal state graph error: %v`, err)
+	}
+	return yakit.SaveCrawlerStateGraph(db, &yakit.CrawlerStateGraph{
+		Target:          graph.Target,
+		RuntimeId:       runtimeId,
+		StateCount:      graph.StateCount(),
+		TransitionCount: graph.TransitionCount(),
+		Graph:           string(raw),
+	})
+}
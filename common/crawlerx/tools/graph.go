@@ -0,0 +1,340 @@
+// Package tools
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"golang.org/x/net/html"
+)
+
+const (
+	TransitionNavigate = "navigate"
+	TransitionClick    = "click"
+)
+
+// PageState is a page state in crawl state graph, identified by url and dom hash,
+// so dom changes on the same url (e.g. after click) are different states
+type PageState struct {
+	ID        string      `json:"id"`
+	Url       string      `json:"url"`
+	Title     string      `json:"title,omitempty"`
+	DomHash   string      `json:"dom_hash"`
+	Forms     []*PageForm `json:"forms,omitempty"`
+	Changed   bool        `json:"changed"`
+	VisitedAt int64       `json:"visited_at"`
+}
+
+type PageForm struct {
+	Action string       `json:"action"`
+	Method string       `json:"method"`
+	Fields []*FormField `json:"fields,omitempty"`
+}
+
+type FormField struct {
+	Tag  string `json:"tag"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// StateTransition is an action transfer page state From to state To,
+// To is empty when target page not recorded at transition time (e.g. url submitted to load later)
+type StateTransition struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Url      string `json:"url"`
+	Action   string `json:"action"`
+	Selector string `json:"selector,omitempty"`
+}
+
+type StateGraph struct {
+	sync.Mutex
+
+	Target      string             `json:"target"`
+	States      []*PageState       `json:"states"`
+	Transitions []*StateTransition `json:"transitions"`
+
+	stateIndex      map[string]*PageState
+	latestIndex     map[string]*PageState
+	transitionIndex map[string]struct{}
+}
+
+func NewStateGraph(target string) *StateGraph {
+	return &StateGraph{
+		Target:          target,
+		States:          make([]*PageState, 0),
+		Transitions:     make([]*StateTransition, 0),
+		stateIndex:      make(map[string]*PageState),
+		latestIndex:     make(map[string]*PageState),
+		transitionIndex: make(map[string]struct{}),
+	}
+}
+
+func ParseStateGraph(data []byte) (*StateGraph, error) {
+	graph := NewStateGraph("")
+	err := json.Unmarshal(data, graph)
+	if err != nil {
+		return nil, utils.Errorf(`unmarshal state graph error: %v`, err)
+	}
+	for _, state := range graph.States {
+		graph.stateIndex[state.ID] = state
+		latest, ok := graph.latestIndex[state.Url]
+		if !ok || latest.VisitedAt <= state.VisitedAt {
+			graph.latestIndex[state.Url] = state
+		}
+	}
+	for _, transition := range graph.Transitions {
+		graph.transitionIndex[transitionKey(transition)] = struct{}{}
+	}
+	return graph, nil
+}
+
+func StateID(url, domHash string) string {
+	return utils.CalcSha1(url + "|" + domHash)[:16]
+}
+
+func transitionKey(t *StateTransition) string {
+	return t.From + "|" + t.To + "|" + t.Action + "|" + t.Selector
+}
+
+// AddState add or update page state of url with dom hash
+func (graph *StateGraph) AddState(url, title, domHash string, forms []*PageForm) *PageState {
+	graph.Lock()
+	defer graph.Unlock()
+	id := StateID(url, domHash)
+	state, ok := graph.stateIndex[id]
+	if !ok {
+		state = &PageState{ID: id, Url: url, DomHash: domHash}
+		graph.stateIndex[id] = state
+		graph.States = append(graph.States, state)
+	}
+	state.Title = title
+	state.Forms = forms
+	state.VisitedAt = time.Now().Unix()
+	graph.latestIndex[url] = state
+	return state
+}
+
+func (graph *StateGraph) GetState(url, domHash string) *PageState {
+	graph.Lock()
+	defer graph.Unlock()
+	return graph.stateIndex[StateID(url, domHash)]
+}
+
+// LatestState return the last recorded page state of url
+func (graph *StateGraph) LatestState(url string) *PageState {
+	graph.Lock()
+	defer graph.Unlock()
+	return graph.latestIndex[url]
+}
+
+// AddTransition add transition from page state to url, to is the page state after action if recorded, otherwise nil
+func (graph *StateGraph) AddTransition(from *PageState, toUrl string, to *PageState, action, selector string) {
+	if from == nil || toUrl == "" {
+		return
+	}
+	transition := &StateTransition{
+		From:     from.ID,
+		Url:      toUrl,
+		Action:   action,
+		Selector: selector,
+	}
+	if to != nil {
+		transition.To = to.ID
+		if from.ID == to.ID {
+			return
+		}
+	} else if from.Url == toUrl {
+		return
+	}
+	graph.Lock()
+	defer graph.Unlock()
+	key := transitionKey(transition)
+	if _, ok := graph.transitionIndex[key]; ok {
+		return
+	}
+	graph.transitionIndex[key] = struct{}{}
+	graph.Transitions = append(graph.Transitions, transition)
+}
+
+// OutTransitions return transitions start from page state id
+func (graph *StateGraph) OutTransitions(stateID string) []*StateTransition {
+	graph.Lock()
+	defer graph.Unlock()
+	results := make([]*StateTransition, 0)
+	for _, transition := range graph.Transitions {
+		if transition.From == stateID {
+			results = append(results, transition)
+		}
+	}
+	return results
+}
+
+func (graph *StateGraph) StateCount() int {
+	graph.Lock()
+	defer graph.Unlock()
+	return len(graph.States)
+}
+
+func (graph *StateGraph) TransitionCount() int {
+	graph.Lock()
+	defer graph.Unlock()
+	return len(graph.Transitions)
+}
+
+func (graph *StateGraph) JSON() ([]byte, error) {
+	graph.Lock()
+	defer graph.Unlock()
+	return json.Marshal(graph)
+}
+
+// DOT export state graph in graphviz dot format, changed states filled with color
+func (graph *StateGraph) DOT() string {
+	graph.Lock()
+	defer graph.Unlock()
+	var buf bytes.Buffer
+	buf.WriteString("digraph G {\n")
+	buf.WriteString("  node [shape=box];\n")
+	nodes := make(map[string]struct{})
+	for _, state := range graph.States {
+		nodes[state.ID] = struct{}{}
+		label := state.Url
+		if state.Title != "" {
+			label = state.Title + "\n" + state.Url
+		}
+		if len(state.Forms) > 0 {
+			label += "\nforms: " + strconv.Itoa(len(state.Forms))
+		}
+		attrs := "label=" + strconv.Quote(label)
+		if state.Changed {
+			attrs += ", style=filled, fillcolor=\"#ffe7ba\""
+		}
+		buf.WriteString(fmt.Sprintf("  %s [%s];\n", strconv.Quote(state.ID), attrs))
+	}
+	// transition without recorded target state points to latest state of url, or a dashed placeholder
+	targets := make([]string, len(graph.Transitions))
+	for i, transition := range graph.Transitions {
+		target := transition.To
+		if target == "" {
+			if state, ok := graph.latestIndex[transition.Url]; ok {
+				target = state.ID
+			} else {
+				target = "url:" + transition.Url
+			}
+		}
+		targets[i] = target
+		if _, ok := nodes[target]; !ok {
+			nodes[target] = struct{}{}
+			buf.WriteString(fmt.Sprintf("  %s [label=%s, style=dashed];\n", strconv.Quote(target), strconv.Quote(transition.Url)))
+		}
+	}
+	for i, transition := range graph.Transitions {
+		label := transition.Action
+		if transition.Selector != "" {
+			label += " " + transition.Selector
+		}
+		buf.WriteString(fmt.Sprintf("  %s -> %s [label=%s];\n", strconv.Quote(transition.From), strconv.Quote(targets[i]), strconv.Quote(label)))
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+var domHashAttributes = []string{"id", "name", "type", "action", "method", "role"}
+
+// DomHash calculate hash of page dom structure (tags and key attributes), ignore text and script content
+func DomHash(htmlStr string) string {
+	var buf bytes.Buffer
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlStr))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		buf.WriteString("<" + token.Data)
+		attrs := make([]string, 0)
+		for _, attr := range token.Attr {
+			if stringArrayContains(domHashAttributes, attr.Key) {
+				attrs = append(attrs, attr.Key+"="+attr.Val)
+			}
+		}
+		sort.Strings(attrs)
+		for _, attr := range attrs {
+			buf.WriteString(" " + attr)
+		}
+		buf.WriteString(">")
+	}
+	return utils.CalcSha1(buf.String())
+}
+
+// ExtractForms extract forms and their fields from html
+func ExtractForms(htmlStr string) []*PageForm {
+	root, err := html.Parse(strings.NewReader(htmlStr))
+	if err != nil {
+		return nil
+	}
+	forms := make([]*PageForm, 0)
+	var walkFields func(*html.Node, *PageForm)
+	walkFields = func(node *html.Node, form *PageForm) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode {
+				switch child.Data {
+				case "input", "select", "textarea", "button":
+					name := getNodeAttribute(child, "name")
+					if name != "" {
+						form.Fields = append(form.Fields, &FormField{Tag: child.Data, Name: name, Type: getNodeAttribute(child, "type")})
+					}
+				}
+			}
+			walkFields(child, form)
+		}
+	}
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "form" {
+			form := &PageForm{
+				Action: getNodeAttribute(node, "action"),
+				Method: strings.ToUpper(getNodeAttribute(node, "method")),
+			}
+			if form.Method == "" {
+				form.Method = "GET"
+			}
+			walkFields(node, form)
+			forms = append(forms, form)
+			return
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	return forms
+}
+
+func getNodeAttribute(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func stringArrayContains(array []string, element string) bool {
+	for _, s := range array {
+		if s == element {
+			return true
+		}
+	}
+	return false
+}
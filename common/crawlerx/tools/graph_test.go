@@ -0,0 +1,75 @@
+// Package tools
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomHash(t *testing.T) {
+	page := `<html><body><div id="main"><a href="/a">%s</a><script>var t = %s</script></div></body></html>`
+	hash := DomHash(strings.Replace(page, "%s", "1", -1))
+	assert.Equal(t, hash, DomHash(strings.Replace(page, "%s", "2", -1)))
+	assert.NotEqual(t, hash, DomHash(`<html><body><div id="other"><a href="/a">1</a></div></body></html>`))
+}
+
+func TestExtractForms(t *testing.T) {
+	forms := ExtractForms(`<form action="/login" method="post"><div><input name="username"><input name="password" type="password"></div><button type="submit">go</button></form>
+<form action="/search"><select name="category"></select><textarea name="q"></textarea></form>`)
+	require.Len(t, forms, 2)
+	assert.Equal(t, "/login", forms[0].Action)
+	assert.Equal(t, "POST", forms[0].Method)
+	require.Len(t, forms[0].Fields, 2)
+	assert.Equal(t, "password", forms[0].Fields[1].Type)
+	assert.Equal(t, "GET", forms[1].Method)
+	assert.Equal(t, []*FormField{{Tag: "select", Name: "category"}, {Tag: "textarea", Name: "q"}}, forms[1].Fields)
+}
+
+func TestStateGraph(t *testing.T) {
+	graph := NewStateGraph("http://example.com/")
+	home := graph.AddState("http://example.com/", "home", "h1", nil)
+	login := graph.AddState("http://example.com/login", "login", "h2", ExtractForms(`<form><input name="user"></form>`))
+	login.Changed = true
+	graph.AddTransition(home, "http://example.com/login", nil, TransitionNavigate, "")
+	graph.AddTransition(home, "http://example.com/login", nil, TransitionNavigate, "")
+	graph.AddTransition(home, "http://example.com/admin", nil, TransitionClick, "#admin")
+	graph.AddTransition(home, "http://example.com/", nil, TransitionNavigate, "")
+	// click on home changes dom without leaving url, recorded as a new state of the same url
+	menu := graph.AddState("http://example.com/", "home", "h3", nil)
+	graph.AddTransition(home, "http://example.com/", menu, TransitionClick, "#menu")
+	graph.AddTransition(menu, "http://example.com/", menu, TransitionClick, "#menu")
+	assert.Equal(t, 3, graph.StateCount())
+	assert.Equal(t, 3, graph.TransitionCount())
+	assert.NotEqual(t, home.ID, menu.ID)
+	assert.Equal(t, "h1", graph.GetState("http://example.com/", "h1").DomHash)
+	assert.Equal(t, menu, graph.LatestState("http://example.com/"))
+	assert.Nil(t, graph.GetState("http://example.com/", "h4"))
+	assert.Len(t, graph.OutTransitions(home.ID), 3)
+	assert.Empty(t, graph.OutTransitions(menu.ID))
+
+	// recording an existing state again keeps it and makes it the latest state of url
+	assert.Equal(t, home, graph.AddState("http://example.com/", "home", "h1", nil))
+	assert.Equal(t, 3, graph.StateCount())
+	assert.Equal(t, home, graph.LatestState("http://example.com/"))
+
+	raw, err := graph.JSON()
+	require.NoError(t, err)
+	loaded, err := ParseStateGraph(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com/", loaded.Target)
+	assert.Equal(t, "user", loaded.GetState("http://example.com/login", "h2").Forms[0].Fields[0].Name)
+	assert.NotNil(t, loaded.GetState("http://example.com/", "h3"))
+	loaded.AddTransition(loaded.GetState("http://example.com/", "h1"), "http://example.com/login", nil, TransitionNavigate, "")
+	assert.Equal(t, 3, loaded.TransitionCount())
+
+	dot := graph.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph G {"))
+	assert.Contains(t, dot, `fillcolor="#ffe7ba"`)
+	assert.Contains(t, dot, `"`+home.ID+`" -> "`+login.ID+`" [label="navigate"]`)
+	assert.Contains(t, dot, `"`+home.ID+`" -> "`+menu.ID+`" [label="click #menu"]`)
+	assert.Contains(t, dot, `"`+home.ID+`" -> "url:http://example.com/admin" [label="click #admin"]`)
+	assert.Contains(t, dot, `[label="http://example.com/admin", style=dashed]`)
+}
@@ -134,6 +134,8 @@ type MITMServer struct {
 	gmOnly       bool
 
 	clientCerts []*ClientCertificationPair
+	// 转发请求时按目标选择的证书仓库中的客户端证书
+	clientCertificateRules []*lowhttp.ClientCertificateRule
 
 	DNSServers     []string
 	HostMapping    map[string]string
@@ -219,6 +221,9 @@ func (m *MITMServer) Serve(ctx context.Context, addr string) error {
 	if len(m.HostMapping) > 0 {
 		config = append(config, lowhttp.WithETCHosts(m.HostMapping))
 	}
+	for _, rule := range m.clientCertificateRules {
+		config = append(config, lowhttp.WithClientCertificate(rule.Name, rule.HostPatterns...))
+	}
 
 	m.proxy.SetLowhttpConfig(config)
 	m.proxy.SetGMTLS(m.gmtls)
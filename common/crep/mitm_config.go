@@ -440,6 +440,18 @@ func MITM_SetHostMapping(m map[string]string) MITMConfig {
 	}
 }
 
+// MITM_SetClientCertificate 转发匹配 hostPatterns 的请求时使用证书仓库中名为 name 的客户端证书，
+// hostPatterns 为空时对所有目标生效，未匹配的目标使用证书仓库中按 host 配置的证书
+func MITM_SetClientCertificate(name string, hostPatterns ...string) MITMConfig {
+	return func(server *MITMServer) error {
+		server.clientCertificateRules = append(server.clientCertificateRules, &lowhttp.ClientCertificateRule{
+			Name:         name,
+			HostPatterns: utils.StringArrayFilterEmpty(hostPatterns),
+		})
+		return nil
+	}
+}
+
 func MITM_SetMaxContentLength(m int64) MITMConfig {
 	return func(server *MITMServer) error {
 		server.maxContentLength = int(m)
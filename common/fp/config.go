@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/yaklang/yaklang/common/fp/webfingerprint"
+	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/utils"
 	"github.com/yaklang/yaklang/common/utils/hostsparser"
 	"io/ioutil"
@@ -490,6 +491,92 @@ func WithWebFingerprintRule(i interface{}) ConfigOption {
 	}
 }
 
+func readWebFingerprintRuleInput(i interface{}) []byte {
+	switch ret := i.(type) {
+	case []byte:
+		return ret
+	case string:
+		e := utils.GetFirstExistedPath(ret)
+		if e != "" {
+			raw, _ := ioutil.ReadFile(e)
+			return raw
+		}
+		return []byte(ret)
+	}
+	return nil
+}
+
+// mergeWebFingerprintRules 合并导入的规则到当前规则中，未设置规则时先加载默认规则
+func mergeWebFingerprintRules(rules []*webfingerprint.WebRule) ConfigOption {
+	return func(config *Config) {
+		if len(rules) <= 0 {
+			return
+		}
+		if len(config.WebFingerprintRules) <= 0 {
+			config.WebFingerprintRules, _ = GetDefaultWebFingerprintRules()
+		}
+		merged := make([]*webfingerprint.WebRule, 0, len(config.WebFingerprintRules)+len(rules))
+		merged = append(merged, config.WebFingerprintRules...)
+		config.WebFingerprintRules = append(merged, rules...)
+	}
+}
+
+// wappalyzerRule servicescan 的配置选项，导入 Wappalyzer 技术规则（apps.json / technologies/*.json）并合并到当前 Web 指纹规则中
+// 支持 headers、cookies、meta、html、scripts 匹配，版本提取以及 implies 隐含组件
+// @param {interface{}} i 规则文件路径或规则内容
+// Example:
+// ```
+// result, err = servicescan.Scan("127.0.0.1", "80", servicescan.web(), servicescan.wappalyzerRule("/tmp/technologies.json"))
+// die(err)
+//
+//	for v := range result {
+//		fmt.Println(v.String())
+//	}
+//
+// ```
+func WithWappalyzerRule(i interface{}) ConfigOption {
+	var rules []*webfingerprint.WebRule
+	switch ret := i.(type) {
+	case []*webfingerprint.WebRule:
+		rules = ret
+	default:
+		var err error
+		rules, err = webfingerprint.ParseWappalyzerRules(readWebFingerprintRuleInput(i))
+		if err != nil {
+			log.Errorf("parse wappalyzer rules failed: %s", err)
+		}
+	}
+	return mergeWebFingerprintRules(rules)
+}
+
+// fingerprintHubRule servicescan 的配置选项，导入 FingerprintHub（web_fingerprint_v3.json 或 v4 YAML 模版）
+// 或 EHole（finger.json）规则并合并到当前 Web 指纹规则中
+// @param {interface{}} i 规则文件路径或规则内容
+// Example:
+// ```
+// result, err = servicescan.Scan("127.0.0.1", "80", servicescan.web(), servicescan.fingerprintHubRule("/tmp/web_fingerprint_v3.json"))
+// die(err)
+//
+//	for v := range result {
+//		fmt.Println(v.String())
+//	}
+//
+// ```
+func WithFingerprintHubRule(i interface{}) ConfigOption {
+	var rules []*webfingerprint.WebRule
+	switch ret := i.(type) {
+	case []*webfingerprint.WebRule:
+		rules = ret
+	default:
+		var err error
+		rules, err = webfingerprint.ParseFingerprintHubRules(readWebFingerprintRuleInput(i))
+		if err != nil {
+			log.Errorf("parse fingerprinthub rules failed: %s", err)
+		}
+	}
+	return mergeWebFingerprintRules(rules)
+}
+
 // nmapRule servicescan 的配置选项，设置本次扫描使用的 Nmap 指纹规则
 // @param {interface{}} i Nmap 指纹规则
 func WithNmapRule(i interface{}) ConfigOption {
@@ -0,0 +1,315 @@
+package webfingerprint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/yaklang/yaklang/common/log"
+	"gopkg.in/yaml.v3"
+)
+
+const faviconPath = "/favicon.ico"
+
+// fingerprintHubV3Rule 是 FingerprintHub web_fingerprint_v3.json 中的一条规则
+type fingerprintHubV3Rule struct {
+	Name          string            `json:"name"`
+	Path          string            `json:"path"`
+	RequestMethod string            `json:"request_method"`
+	StatusCode    int               `json:"status_code"`
+	Headers       map[string]string `json:"headers"`
+	Keyword       []string          `json:"keyword"`
+	FaviconHash   []string          `json:"favicon_hash"`
+}
+
+// eHoleRule 是 EHole finger.json 中的一条规则
+type eHoleRule struct {
+	Cms      string   `json:"cms"`
+	Method   string   `json:"method"`
+	Location string   `json:"location"`
+	Keyword  []string `json:"keyword"`
+}
+
+// fingerprintHubTemplate 是 FingerprintHub v4 使用的 nuclei 风格 YAML 模版
+type fingerprintHubTemplate struct {
+	ID   string `yaml:"id"`
+	Info struct {
+		Name     string `yaml:"name"`
+		Metadata struct {
+			Vendor  string `yaml:"vendor"`
+			Product string `yaml:"product"`
+		} `yaml:"metadata"`
+	} `yaml:"info"`
+	Http []struct {
+		Method            string                   `yaml:"method"`
+		Path              []string                 `yaml:"path"`
+		MatchersCondition string                   `yaml:"matchers-condition"`
+		Matchers          []*fingerprintHubMatcher `yaml:"matchers"`
+	} `yaml:"http"`
+}
+
+type fingerprintHubMatcher struct {
+	Type            string   `yaml:"type"`
+	Part            string   `yaml:"part"`
+	Words           []string `yaml:"words"`
+	Regex           []string `yaml:"regex"`
+	Hash            []string `yaml:"hash"`
+	Status          []int    `yaml:"status"`
+	Condition       string   `yaml:"condition"`
+	CaseInsensitive bool     `yaml:"case-insensitive"`
+	Negative        bool     `yaml:"negative"`
+}
+
+// ParseFingerprintHubRules 把 FingerprintHub / EHole 规则转换为 WebRule，支持：
+// FingerprintHub v3 的 web_fingerprint_v3.json、EHole 的 finger.json 以及 FingerprintHub v4 的 nuclei 风格 YAML 模版
+func ParseFingerprintHubRules(raw []byte) ([]*WebRule, error) {
+	var (
+		rules []*WebRule
+		err   error
+	)
+	trimmed := bytes.TrimSpace(raw)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		rules, err = parseFingerprintHubV3Rules(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		rules, err = parseEHoleRules(trimmed)
+	default:
+		rules, err = parseFingerprintHubTemplates(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) <= 0 {
+		return nil, errors.New("no available fingerprinthub rules")
+	}
+	return rules, nil
+}
+
+func fingerprintHubCPE(name string) CPE {
+	return CPE{Product: wappalyzerProductName(name)}
+}
+
+func statusCodeRegexp(codes ...int) string {
+	var items []string
+	for _, code := range codes {
+		items = append(items, fmt.Sprint(code))
+	}
+	return fmt.Sprintf(`^HTTP/\S+ (?:%s) `, strings.Join(items, "|"))
+}
+
+func headerValueRegexp(name, value string) string {
+	return fmt.Sprintf(`(?im)^%s:[^\r\n]*%s`, regexp.QuoteMeta(name), regexp.QuoteMeta(value))
+}
+
+func faviconRule(cpe CPE, md5s []string, hashes []string) *WebRule {
+	method := &WebMatcherMethods{}
+	for _, m := range md5s {
+		method.MD5s = append(method.MD5s, &MD5Matcher{CPE: cpe, MD5: strings.ToLower(strings.TrimSpace(m))})
+	}
+	for _, h := range hashes {
+		method.FaviconHash = append(method.FaviconHash, &FaviconHashMatcher{CPE: cpe, Hash: h})
+	}
+	return &WebRule{Path: faviconPath, Methods: []*WebMatcherMethods{method}}
+}
+
+func parseFingerprintHubV3Rules(raw []byte) ([]*WebRule, error) {
+	var items []*fingerprintHubV3Rule
+	err := json.Unmarshal(raw, &items)
+	if err != nil {
+		return nil, errors.Errorf("unmarshal fingerprinthub rules failed: %s", err)
+	}
+
+	var rules []*WebRule
+	for _, item := range items {
+		if item == nil || item.Name == "" {
+			continue
+		}
+		// 主动探测只会发送 GET 请求
+		if item.RequestMethod != "" && !strings.EqualFold(item.RequestMethod, "get") {
+			log.Debugf("fingerprinthub rule[%s] request method %s is not supported", item.Name, item.RequestMethod)
+			continue
+		}
+		cpe := fingerprintHubCPE(item.Name)
+		if len(item.FaviconHash) > 0 {
+			rules = append(rules, faviconRule(cpe, item.FaviconHash, nil))
+		}
+
+		// keyword / headers / status_code 需要同时满足
+		method := &WebMatcherMethods{Condition: "and"}
+		for _, keyword := range item.Keyword {
+			method.Keywords = append(method.Keywords, &KeywordMatcher{CPE: cpe, Regexp: regexp.QuoteMeta(keyword)})
+		}
+		for _, name := range sortedKeys(item.Headers) {
+			method.Keywords = append(method.Keywords, &KeywordMatcher{CPE: cpe, Regexp: headerValueRegexp(name, item.Headers[name])})
+		}
+		if len(method.Keywords) <= 0 {
+			continue
+		}
+		if item.StatusCode > 0 {
+			method.Keywords = append(method.Keywords, &KeywordMatcher{CPE: cpe, Regexp: statusCodeRegexp(item.StatusCode)})
+		}
+		rules = append(rules, &WebRule{Path: item.Path, Methods: []*WebMatcherMethods{method}})
+	}
+	return rules, nil
+}
+
+func parseEHoleRules(raw []byte) ([]*WebRule, error) {
+	var finger struct {
+		Fingerprint []*eHoleRule `json:"fingerprint"`
+	}
+	err := json.Unmarshal(raw, &finger)
+	if err != nil {
+		return nil, errors.Errorf("unmarshal ehole rules failed: %s", err)
+	}
+
+	var rules []*WebRule
+	for _, item := range finger.Fingerprint {
+		if item == nil || item.Cms == "" || len(item.Keyword) <= 0 {
+			continue
+		}
+		cpe := fingerprintHubCPE(item.Cms)
+		if strings.EqualFold(item.Method, "faviconhash") {
+			rules = append(rules, faviconRule(cpe, nil, item.Keyword))
+			continue
+		}
+
+		method := &WebMatcherMethods{Condition: "and"}
+		for _, keyword := range item.Keyword {
+			re := regexp.QuoteMeta(keyword)
+			if strings.HasPrefix(strings.ToLower(item.Method), "regula") {
+				if _, err := regexp.Compile(keyword); err != nil {
+					log.Debugf("ehole rule[%s] regexp %s compile failed: %s", item.Cms, keyword, err)
+					method.Keywords = nil
+					break
+				}
+				re = keyword
+			}
+			if strings.EqualFold(item.Location, "title") {
+				re = `(?is)<title>[^<]*` + re
+			}
+			method.Keywords = append(method.Keywords, &KeywordMatcher{CPE: cpe, Regexp: re})
+		}
+		if len(method.Keywords) <= 0 {
+			continue
+		}
+		rules = append(rules, &WebRule{Methods: []*WebMatcherMethods{method}})
+	}
+	return rules, nil
+}
+
+func parseFingerprintHubTemplates(raw []byte) ([]*WebRule, error) {
+	var rules []*WebRule
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	for {
+		var template fingerprintHubTemplate
+		err := decoder.Decode(&template)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Errorf("unmarshal fingerprinthub template failed: %s", err)
+		}
+		rules = append(rules, convertFingerprintHubTemplate(&template)...)
+	}
+	return rules, nil
+}
+
+// fingerprintHubMatcherKeywords 把一个 nuclei matcher 转换为需要同时满足的 KeywordMatcher，
+// condition 为 or 的多个 word / regex 合并为一个分支正则
+func fingerprintHubMatcherKeywords(cpe CPE, m *fingerprintHubMatcher) []*KeywordMatcher {
+	var patterns []string
+	switch m.Type {
+	case "word":
+		for _, word := range m.Words {
+			patterns = append(patterns, regexp.QuoteMeta(word))
+		}
+	case "regex":
+		for _, re := range m.Regex {
+			if _, err := regexp.Compile(re); err != nil {
+				return nil
+			}
+			patterns = append(patterns, re)
+		}
+	case "status":
+		if len(m.Status) > 0 {
+			return []*KeywordMatcher{{CPE: cpe, Regexp: statusCodeRegexp(m.Status...)}}
+		}
+	}
+	if len(patterns) <= 0 {
+		return nil
+	}
+
+	prefix := ""
+	if m.CaseInsensitive {
+		prefix = "(?i)"
+	}
+	if m.Condition == "and" {
+		var keywords []*KeywordMatcher
+		for _, p := range patterns {
+			keywords = append(keywords, &KeywordMatcher{CPE: cpe, Regexp: prefix + p})
+		}
+		return keywords
+	}
+	return []*KeywordMatcher{{CPE: cpe, Regexp: prefix + "(?:" + strings.Join(patterns, "|") + ")"}}
+}
+
+func convertFingerprintHubTemplate(template *fingerprintHubTemplate) []*WebRule {
+	name := template.Info.Name
+	if name == "" {
+		name = template.ID
+	}
+	cpe := fingerprintHubCPE(name)
+	if template.Info.Metadata.Product != "" {
+		cpe = CPE{
+			Vendor:  strings.ToLower(template.Info.Metadata.Vendor),
+			Product: strings.ToLower(template.Info.Metadata.Product),
+		}
+	}
+
+	var rules []*WebRule
+	for _, req := range template.Http {
+		if req.Method != "" && !strings.EqualFold(req.Method, "get") {
+			log.Debugf("fingerprinthub template[%s] request method %s is not supported", template.ID, req.Method)
+			continue
+		}
+		path := "/"
+		if len(req.Path) > 0 {
+			path = strings.TrimPrefix(req.Path[0], "{{BaseURL}}")
+		}
+
+		var methods []*WebMatcherMethods
+		andMethod := &WebMatcherMethods{Condition: "and"}
+		for _, m := range req.Matchers {
+			if m == nil || m.Negative {
+				// 无法表达取反匹配，整条请求规则放弃，避免误报
+				methods, andMethod = nil, nil
+				break
+			}
+			if m.Type == "favicon" {
+				rules = append(rules, faviconRule(cpe, nil, m.Hash))
+				continue
+			}
+			keywords := fingerprintHubMatcherKeywords(cpe, m)
+			if len(keywords) <= 0 {
+				continue
+			}
+			if req.MatchersCondition == "and" {
+				andMethod.Keywords = append(andMethod.Keywords, keywords...)
+				continue
+			}
+			methods = append(methods, &WebMatcherMethods{Condition: "and", Keywords: keywords})
+		}
+		if andMethod != nil && len(andMethod.Keywords) > 0 {
+			methods = append(methods, andMethod)
+		}
+		if len(methods) <= 0 {
+			continue
+		}
+		rules = append(rules, &WebRule{Path: path, Methods: methods})
+	}
+	return rules
+}
@@ -0,0 +1,108 @@
+package webfingerprint
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+func matchTestResponse(t *testing.T, rules []*WebRule, body string) []string {
+	matcher, err := NewWebFingerprintMatcher(rules, false, true)
+	require.NoError(t, err)
+	header := http.Header{}
+	header.Set("Server", "Apache-Coyote/1.1")
+	u, _ := url.Parse("http://127.0.0.1/")
+	cpes, _ := matcher.Match(&HTTPResponseInfo{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     &header,
+		URL:        u,
+		Body:       []byte(body),
+	})
+	var products []string
+	for _, c := range cpes {
+		products = append(products, c.Product)
+	}
+	return products
+}
+
+func TestParseFingerprintHubRules(t *testing.T) {
+	v3 := `[
+  {"name": "Tomcat Manager", "path": "/", "request_method": "get", "status_code": 200,
+   "headers": {"Server": "Coyote"}, "keyword": ["Apache Tomcat"], "favicon_hash": ["4644f2d45601037b8423d45e13194c93"]},
+  {"name": "post-only", "path": "/", "request_method": "post", "keyword": ["x"]}
+]`
+	rules, err := ParseFingerprintHubRules([]byte(v3))
+	require.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, faviconPath, rules[0].Path)
+	assert.Contains(t, matchTestResponse(t, rules, "<h3>Apache Tomcat/8.5</h3>"), "tomcat_manager")
+	assert.NotContains(t, matchTestResponse(t, rules, "<h3>Jetty</h3>"), "tomcat_manager")
+
+	ehole := `{"fingerprint": [
+  {"cms": "seeyon", "method": "keyword", "location": "body", "keyword": ["/seeyon/USER-DATA/", "login.gif"]},
+  {"cms": "ruoyi", "method": "keyword", "location": "title", "keyword": ["若依"]},
+  {"cms": "shiro", "method": "faviconhash", "location": "body", "keyword": ["-1234"]}
+]}`
+	rules, err = ParseFingerprintHubRules([]byte(ehole))
+	require.NoError(t, err)
+	assert.Len(t, rules, 3)
+	assert.Contains(t, matchTestResponse(t, rules, `<img src="/seeyon/USER-DATA/IMAGES/LOGIN/login.gif">`), "seeyon")
+	assert.NotContains(t, matchTestResponse(t, rules, `<img src="/seeyon/USER-DATA/a.png">`), "seeyon")
+	assert.Contains(t, matchTestResponse(t, rules, `<title>若依管理系统</title>`), "ruoyi")
+
+	v4 := `id: nacos
+info:
+  name: Nacos
+  metadata:
+    vendor: alibaba
+    product: nacos
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}/nacos/"
+    matchers-condition: and
+    matchers:
+      - type: word
+        words: ["<title>Nacos</title>", "console-ui"]
+        case-insensitive: true
+      - type: status
+        status: [200]
+---
+id: grafana
+info:
+  name: Grafana
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}"
+    matchers:
+      - type: regex
+        regex: ["grafana-app"]
+      - type: favicon
+        hash: ["-1234"]
+`
+	rules, err = ParseFingerprintHubRules([]byte(v4))
+	require.NoError(t, err)
+	assert.Len(t, rules, 3)
+	assert.Equal(t, "/nacos/", rules[0].Path)
+	assert.Equal(t, "alibaba", rules[0].Methods[0].Keywords[0].Vendor)
+	assert.Contains(t, matchTestResponse(t, rules, `<div class="grafana-app">`), "grafana")
+}
+
+func TestFaviconHash(t *testing.T) {
+	// 与 python: mmh3.hash(codecs.encode(raw, "base64")) 一致，base64 每 76 个字符换行
+	raw := bytes.Repeat([]byte("a"), 60)
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	assert.Equal(t, utils.Mmh3Hash32([]byte(encoded[:76]+"\n"+encoded[76:]+"\n")), FaviconHash(raw))
+	m := &FaviconHashMatcher{CPE: CPE{Product: "test"}, Hash: FaviconHash([]byte("icon"))}
+	c, err := m.Match([]byte("icon"))
+	require.NoError(t, err)
+	assert.Equal(t, "test", c.Product)
+}
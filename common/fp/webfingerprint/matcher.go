@@ -242,6 +242,35 @@ func (f *Matcher) matchByRule(r *HTTPResponseInfo, ruleToUse *WebRule, config *C
 
 			cpes = append(cpes, cpe)
 		}
+
+		// 匹配 favicon hash
+		for _, m := range m.FaviconHash {
+			cpe, err := m.Match(r.Body)
+			if err != nil {
+				continue
+			}
+
+			cpes = append(cpes, cpe)
+		}
+	}
+	return cpes
+}
+
+// appendImpliedCPEs 添加规则中 implies 的组件，已经识别出的组件不会重复添加
+func appendImpliedCPEs(cpes []*CPE, implies []*CPE) []*CPE {
+	for _, implied := range implies {
+		existed := false
+		for _, c := range cpes {
+			if c.Vendor == implied.Vendor && c.Product == implied.Product {
+				existed = true
+				break
+			}
+		}
+		if existed {
+			continue
+		}
+		cpe := *implied
+		cpes = append(cpes, &cpe)
 	}
 	return cpes
 }
@@ -257,6 +286,7 @@ func (f *Matcher) matchWithConfig(rsp *HTTPResponseInfo, config *Config) []*CPE
 		results := f.matchByRule(rsp, rule, config)
 		if len(results) > 0 {
 			cpes = append(cpes, results...)
+			cpes = appendImpliedCPEs(cpes, rule.Implies)
 			if rule.NextStep != nil {
 				rule = rule.NextStep
 				goto MatchNext
@@ -192,15 +192,36 @@ func (h *MD5Matcher) Match(raw []byte) (*CPE, error) {
 	return &h.CPE, nil
 }
 
+// ////////////////////////////////////////////////////////////////////////////////////
+// ///////////////////////////Favicon Hash Matcher Model///////////////////////////////
+// ////////////////////////////////////////////////////////////////////////////////////
+// FaviconHashMatcher 使用 Shodan/FOFA 风格的 favicon mmh3 hash（对 base64 编码后的内容计算 mmh3）进行匹配
+type FaviconHashMatcher struct {
+	CPE `yaml:"cpe,inline,omitempty"`
+
+	Hash string `yaml:"hash"`
+}
+
+func (h *FaviconHashMatcher) Match(raw []byte) (*CPE, error) {
+	if len(raw) <= 0 {
+		return nil, errors.New("empty favicon")
+	}
+	if FaviconHash(raw) != strings.TrimSpace(h.Hash) {
+		return nil, errors.New("no matched")
+	}
+	return &h.CPE, nil
+}
+
 // ////////////////////////////////////////////////////////////////////////////////////
 // /////////////////////////WebMatcherMethods Model ///////////////////////////////////
 // ////////////////////////////////////////////////////////////////////////////////////
 type WebMatcherMethods struct {
 	Condition string `yaml:"condition,omitempty"`
 
-	Keywords    []*KeywordMatcher    `yaml:"keywords,omitempty"`
-	HTTPHeaders []*HTTPHeaderMatcher `yaml:"headers,omitempty"`
-	MD5s        []*MD5Matcher        `yaml:"md5s,omitempty"`
+	Keywords    []*KeywordMatcher     `yaml:"keywords,omitempty"`
+	HTTPHeaders []*HTTPHeaderMatcher  `yaml:"headers,omitempty"`
+	MD5s        []*MD5Matcher         `yaml:"md5s,omitempty"`
+	FaviconHash []*FaviconHashMatcher `yaml:"favicon_hash,omitempty"`
 }
 
 // ////////////////////////////////////////////////////////////////////////////////////
@@ -210,6 +231,9 @@ type WebRule struct {
 	Path     string `yaml:"path,omitempty"`
 	Methods  []*WebMatcherMethods
 	NextStep *WebRule `yaml:"next,omitempty"`
+
+	// Implies 规则命中后同时认为存在的组件（例如 WordPress 意味着 PHP 与 MySQL）
+	Implies []*CPE `yaml:"implies,omitempty"`
 }
 
 func (w *WebRule) IsActiveToProbePath() bool {
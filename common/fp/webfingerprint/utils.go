@@ -1,9 +1,12 @@
 package webfingerprint
 
 import (
+	"encoding/base64"
 	"net/url"
 	"regexp"
 	"strings"
+
+	utils2 "github.com/yaklang/yaklang/common/utils"
 )
 
 import (
@@ -173,3 +176,18 @@ func CalcAbsolutelyURLWithFragment(origin *url.URL, u string) string {
 	}
 	return absURL.String()
 }
+
+// FaviconHash 计算 Shodan/FOFA 风格的 favicon hash：按 76 字符换行的 base64 编码后计算 mmh3
+func FaviconHash(raw []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	var buf strings.Builder
+	for i := 0; i < len(encoded); i += 76 {
+		end := i + 76
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		buf.WriteString(encoded[i:end])
+		buf.WriteByte('\n')
+	}
+	return utils2.Mmh3Hash32([]byte(buf.String()))
+}
@@ -0,0 +1,288 @@
+package webfingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/yaklang/yaklang/common/log"
+)
+
+// wappalyzerStringList Wappalyzer 规则中的字段既可能是字符串也可能是字符串数组
+type wappalyzerStringList []string
+
+func (l *wappalyzerStringList) UnmarshalJSON(raw []byte) error {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		*l = []string{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+type wappalyzerTechnology struct {
+	Cpe       string                          `json:"cpe"`
+	Headers   map[string]string               `json:"headers"`
+	Cookies   map[string]string               `json:"cookies"`
+	Meta      map[string]wappalyzerStringList `json:"meta"`
+	Html      wappalyzerStringList            `json:"html"`
+	Text      wappalyzerStringList            `json:"text"`
+	Scripts   wappalyzerStringList            `json:"scripts"`
+	Script    wappalyzerStringList            `json:"script"`
+	ScriptSrc wappalyzerStringList            `json:"scriptSrc"`
+	Implies   wappalyzerStringList            `json:"implies"`
+}
+
+// wappalyzerPattern 是解析后的 Wappalyzer 匹配模式，原始格式为 `regexp\;version:\1\;confidence:50`
+type wappalyzerPattern struct {
+	Regexp       string
+	VersionIndex int
+	Version      string
+}
+
+var wappalyzerVersionIndexRegexp = regexp.MustCompile(`\\(\d+)`)
+
+func parseWappalyzerPattern(raw string) (*wappalyzerPattern, bool) {
+	parts := strings.Split(raw, `\;`)
+	pattern := &wappalyzerPattern{Regexp: parts[0]}
+	for _, part := range parts[1:] {
+		if !strings.HasPrefix(part, "version:") {
+			continue
+		}
+		version := strings.TrimPrefix(part, "version:")
+		switch {
+		case strings.Contains(version, "?"):
+			// 三元表达式 `\1?a:b` 无法用 KeywordMatcher 表达，放弃版本提取
+		case wappalyzerVersionIndexRegexp.MatchString(version):
+			// `\1` 或者 `\1.\2` 这类组合只取第一个分组
+			index, _ := strconv.Atoi(wappalyzerVersionIndexRegexp.FindStringSubmatch(version)[1])
+			pattern.VersionIndex = index
+		case !strings.Contains(version, `\`):
+			pattern.Version = version
+		}
+	}
+	if pattern.Regexp == "" {
+		return pattern, true
+	}
+	// Wappalyzer 的正则不区分大小写；Go 不支持 lookahead 等语法，无法编译的规则直接跳过
+	if _, err := regexp.Compile(pattern.Regexp); err != nil {
+		return nil, false
+	}
+	return pattern, true
+}
+
+// trimAnchor 去掉首尾锚点，用于在整个响应中搜索原本只针对某个值的模式
+func (p *wappalyzerPattern) trimAnchor() string {
+	return strings.TrimSuffix(strings.TrimPrefix(p.Regexp, "^"), "$")
+}
+
+func (p *wappalyzerPattern) keyword(cpe CPE, re string) *KeywordMatcher {
+	if p.Version != "" {
+		cpe.Version = p.Version
+	}
+	return &KeywordMatcher{
+		CPE:          cpe,
+		Regexp:       re,
+		VersionIndex: p.VersionIndex,
+	}
+}
+
+func wappalyzerProductName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+}
+
+func wappalyzerCPE(name string, tech *wappalyzerTechnology) CPE {
+	if tech != nil && tech.Cpe != "" {
+		if c, err := ParseToCPE(tech.Cpe); err == nil {
+			cpe := CPE{Part: c.Part, Vendor: c.Vendor, Product: c.Product}
+			if cpe.Vendor == "*" {
+				cpe.Vendor = ""
+			}
+			if cpe.Product != "*" {
+				return cpe
+			}
+		}
+	}
+	return CPE{Product: wappalyzerProductName(name)}
+}
+
+func parseWappalyzerTechnologies(raw []byte) (map[string]*wappalyzerTechnology, error) {
+	var root map[string]json.RawMessage
+	err := json.Unmarshal(raw, &root)
+	if err != nil {
+		return nil, errors.Errorf("unmarshal wappalyzer rules failed: %s", err)
+	}
+	// apps.json 旧版本使用 apps，新版本使用 technologies，拆分后的 src/technologies/*.json 直接是技术列表
+	for _, key := range []string{"technologies", "apps"} {
+		if data, ok := root[key]; ok {
+			root = nil
+			err = json.Unmarshal(data, &root)
+			if err != nil {
+				return nil, errors.Errorf("unmarshal wappalyzer %s failed: %s", key, err)
+			}
+			break
+		}
+	}
+
+	techs := make(map[string]*wappalyzerTechnology)
+	for name, data := range root {
+		var tech wappalyzerTechnology
+		err = json.Unmarshal(data, &tech)
+		if err != nil {
+			log.Debugf("unmarshal wappalyzer technology[%s] failed: %s", name, err)
+			continue
+		}
+		techs[name] = &tech
+	}
+	return techs, nil
+}
+
+// resolveWappalyzerImplies 递归解析 implies，返回去重后的全部隐含组件
+func resolveWappalyzerImplies(name string, techs map[string]*wappalyzerTechnology) []*CPE {
+	var results []*CPE
+	visited := map[string]bool{name: true}
+	var walk func(string)
+	walk = func(current string) {
+		tech, ok := techs[current]
+		if !ok {
+			return
+		}
+		for _, implied := range tech.Implies {
+			implied = strings.TrimSpace(strings.Split(implied, `\;`)[0])
+			if implied == "" || visited[implied] {
+				continue
+			}
+			visited[implied] = true
+			cpe := wappalyzerCPE(implied, techs[implied])
+			results = append(results, &cpe)
+			walk(implied)
+		}
+	}
+	walk(name)
+	return results
+}
+
+// ParseWappalyzerRules 把 Wappalyzer 技术规则（apps.json / technologies.json / src/technologies/*.json）
+// 转换为 WebRule，支持 headers / cookies / meta / html / text / scripts / scriptSrc 匹配、
+// `\;version:\1` 版本提取以及 implies 隐含组件；js / dom / url 等需要浏览器或请求上下文的规则会被忽略
+func ParseWappalyzerRules(raw []byte) ([]*WebRule, error) {
+	techs, err := parseWappalyzerTechnologies(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range techs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rules []*WebRule
+	for _, name := range names {
+		tech := techs[name]
+		cpe := wappalyzerCPE(name, tech)
+		method := &WebMatcherMethods{}
+
+		for _, header := range sortedKeys(tech.Headers) {
+			pattern, ok := parseWappalyzerPattern(tech.Headers[header])
+			if !ok {
+				continue
+			}
+			re := `(?i)` + pattern.Regexp
+			if pattern.Regexp == "" {
+				re = `.*`
+			}
+			method.HTTPHeaders = append(method.HTTPHeaders, &HTTPHeaderMatcher{
+				HeaderName:  header,
+				HeaderValue: *pattern.keyword(cpe, re),
+			})
+		}
+
+		for _, cookie := range sortedKeys(tech.Cookies) {
+			pattern, ok := parseWappalyzerPattern(tech.Cookies[cookie])
+			if !ok {
+				continue
+			}
+			re := fmt.Sprintf(`(?i)(?:^|[;,\s])%s=%s`, regexp.QuoteMeta(cookie), pattern.trimAnchor())
+			method.HTTPHeaders = append(method.HTTPHeaders, &HTTPHeaderMatcher{
+				HeaderName:  "Set-Cookie",
+				HeaderValue: *pattern.keyword(cpe, re),
+			})
+		}
+
+		var metaNames []string
+		for meta := range tech.Meta {
+			metaNames = append(metaNames, meta)
+		}
+		sort.Strings(metaNames)
+		for _, meta := range metaNames {
+			for _, value := range tech.Meta[meta] {
+				pattern, ok := parseWappalyzerPattern(value)
+				if !ok {
+					continue
+				}
+				re := fmt.Sprintf(
+					`(?i)<meta[^>]+(?:name|property)\s*=\s*["']?%s["']?[^>]*content\s*=\s*["'](?:%s)`,
+					regexp.QuoteMeta(meta), pattern.trimAnchor(),
+				)
+				method.Keywords = append(method.Keywords, pattern.keyword(cpe, re))
+			}
+		}
+
+		for _, value := range append(append(wappalyzerStringList{}, tech.Html...), tech.Text...) {
+			pattern, ok := parseWappalyzerPattern(value)
+			if !ok || pattern.Regexp == "" {
+				continue
+			}
+			method.Keywords = append(method.Keywords, pattern.keyword(cpe, `(?i)`+pattern.trimAnchor()))
+		}
+
+		// 新版本中 scripts 为内联脚本内容，scriptSrc（旧版本为 script）为脚本地址
+		for _, value := range tech.Scripts {
+			pattern, ok := parseWappalyzerPattern(value)
+			if !ok || pattern.Regexp == "" {
+				continue
+			}
+			method.Keywords = append(method.Keywords, pattern.keyword(cpe, `(?i)`+pattern.trimAnchor()))
+		}
+		for _, value := range append(append(wappalyzerStringList{}, tech.ScriptSrc...), tech.Script...) {
+			pattern, ok := parseWappalyzerPattern(value)
+			if !ok || pattern.Regexp == "" {
+				continue
+			}
+			re := `(?i)<script[^>]+src\s*=\s*["']?[^"'>]*(?:` + pattern.trimAnchor() + `)`
+			method.Keywords = append(method.Keywords, pattern.keyword(cpe, re))
+		}
+
+		if len(method.Keywords) <= 0 && len(method.HTTPHeaders) <= 0 {
+			continue
+		}
+		rules = append(rules, &WebRule{
+			Methods: []*WebMatcherMethods{method},
+			Implies: resolveWappalyzerImplies(name, techs),
+		})
+	}
+
+	if len(rules) <= 0 {
+		return nil, errors.New("no available wappalyzer rules")
+	}
+	return rules, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
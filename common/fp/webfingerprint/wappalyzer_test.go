@@ -0,0 +1,73 @@
+package webfingerprint
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWappalyzerRules = `{
+  "technologies": {
+    "WordPress": {
+      "cpe": "cpe:2.3:a:wordpress:wordpress:*:*:*:*:*:*:*:*",
+      "html": "<link rel=[\"']stylesheet[\"'] [^>]+/wp-(?:content|includes)/",
+      "meta": {"generator": "^WordPress(?: ([\\d.]+))?\\;version:\\1"},
+      "implies": ["PHP", "MySQL\\;confidence:50"]
+    },
+    "PHP": {
+      "cpe": "cpe:2.3:a:php:php:*:*:*:*:*:*:*:*",
+      "headers": {"X-Powered-By": "^php/?([\\d.]+)?\\;version:\\1"},
+      "cookies": {"PHPSESSID": ""}
+    },
+    "MySQL": {"implies": "SQL"},
+    "jQuery": {
+      "scriptSrc": "jquery(?:-(\\d+\\.\\d+\\.\\d+))[/.-]\\;version:\\1"
+    },
+    "Lookahead": {"html": "foo(?!bar)"},
+    "Nginx": {"headers": {"Server": "nginx(?:/([\\d.]+))?\\;version:\\1"}}
+  }
+}`
+
+func TestParseWappalyzerRules(t *testing.T) {
+	rules, err := ParseWappalyzerRules([]byte(testWappalyzerRules))
+	require.NoError(t, err)
+	// MySQL 只有 implies，Lookahead 的正则无法编译
+	assert.Len(t, rules, 4)
+
+	matcher, err := NewWebFingerprintMatcher(rules, false, true)
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("X-Powered-By", "PHP/7.4.3")
+	header.Set("Server", "nginx/1.18.0")
+	u, _ := url.Parse("http://127.0.0.1/")
+	cpes, err := matcher.Match(&HTTPResponseInfo{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     &header,
+		URL:        u,
+		Body: []byte(`<html><head><meta name="generator" content="WordPress 6.2.1">
+<link rel='stylesheet' href='/wp-content/themes/a.css'>
+<script src="/static/jquery-3.6.0.min.js"></script></head></html>`),
+	})
+	require.NoError(t, err)
+
+	versions := make(map[string]string)
+	for _, c := range cpes {
+		if c.Version != "" && c.Version != "*" {
+			versions[c.Product] = c.Version
+		} else if _, ok := versions[c.Product]; !ok {
+			versions[c.Product] = ""
+		}
+	}
+	assert.Equal(t, "6.2.1", versions["wordpress"])
+	assert.Equal(t, "7.4.3", versions["php"])
+	assert.Equal(t, "1.18.0", versions["nginx"])
+	assert.Equal(t, "3.6.0", versions["jquery"])
+	// implies 递归解析：WordPress -> MySQL -> SQL
+	assert.Contains(t, versions, "mysql")
+	assert.Contains(t, versions, "sql")
+}
@@ -0,0 +1,132 @@
+package grpcx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	frameFlagCompressed = 0x01
+	// grpc-web 在 body 中用 0x80 标记 trailers
+	frameFlagTrailer = 0x80
+	frameHeaderSize  = 5
+)
+
+// Frame 是 gRPC 的 Length-Prefixed-Message
+// https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md
+type Frame struct {
+	Compressed bool
+	Trailer    bool
+	// 解压之后的数据
+	Payload []byte
+}
+
+// IsGRPCContentType 判断 Content-Type 是否是 gRPC（包括 grpc-web，不包括 base64 编码的 grpc-web-text）
+func IsGRPCContentType(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	return strings.HasPrefix(contentType, "application/grpc") && !strings.HasPrefix(contentType, "application/grpc-web-text")
+}
+
+// IsNativeGRPCContentType 判断是否是只能通过 HTTP/2 传输的原生 gRPC（application/grpc、application/grpc+proto 等），
+// grpc-web 使用 HTTP/1.1 传输，不属于原生 gRPC
+func IsNativeGRPCContentType(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	if !strings.HasPrefix(contentType, "application/grpc") {
+		return false
+	}
+	rest := strings.TrimPrefix(contentType, "application/grpc")
+	return rest == "" || strings.HasPrefix(rest, "+") || strings.HasPrefix(rest, ";")
+}
+
+// ParseFrames 解析 gRPC body 中的所有消息，压缩的消息使用 gzip 解压
+func ParseFrames(body []byte) ([]*Frame, error) {
+	var frames []*Frame
+	for len(body) > 0 {
+		if len(body) < frameHeaderSize {
+			return nil, utils.Errorf("grpc frame header too short: %d", len(body))
+		}
+		flag := body[0]
+		length := binary.BigEndian.Uint32(body[1:frameHeaderSize])
+		if uint64(len(body)-frameHeaderSize) < uint64(length) {
+			return nil, utils.Errorf("grpc frame too short: expect %d but got %d", length, len(body)-frameHeaderSize)
+		}
+		payload := body[frameHeaderSize : frameHeaderSize+int(length)]
+		body = body[frameHeaderSize+int(length):]
+
+		frame := &Frame{
+			Compressed: flag&frameFlagCompressed != 0,
+			Trailer:    flag&frameFlagTrailer != 0,
+			Payload:    append([]byte{}, payload...),
+		}
+		if frame.Compressed {
+			reader, err := gzip.NewReader(bytes.NewReader(payload))
+			if err != nil {
+				return nil, utils.Wrapf(err, "decompress grpc frame failed")
+			}
+			frame.Payload, err = io.ReadAll(reader)
+			if err != nil {
+				return nil, utils.Wrapf(err, "decompress grpc frame failed")
+			}
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// EncodeFrames 把消息编码为 gRPC body，Compressed 的消息使用 gzip 压缩
+func EncodeFrames(frames ...*Frame) []byte {
+	var buf bytes.Buffer
+	for _, frame := range frames {
+		var flag byte
+		payload := frame.Payload
+		if frame.Compressed {
+			var compressed bytes.Buffer
+			writer := gzip.NewWriter(&compressed)
+			_, _ = writer.Write(payload)
+			_ = writer.Close()
+			payload = compressed.Bytes()
+			flag |= frameFlagCompressed
+		}
+		if frame.Trailer {
+			flag |= frameFlagTrailer
+		}
+		var header [frameHeaderSize]byte
+		header[0] = flag
+		binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+		buf.Write(header[:])
+		buf.Write(payload)
+	}
+	return buf.Bytes()
+}
+
+// NewFrame 把 protobuf 消息编码为一个 gRPC 消息
+func NewFrame(message []byte) *Frame {
+	return &Frame{Payload: message}
+}
+
+// DecodeMessages 解析 gRPC body 中的所有 protobuf 消息（跳过 grpc-web 的 trailers），md 为空时不使用 schema
+func DecodeMessages(body []byte, md protoreflect.MessageDescriptor) ([]*Message, error) {
+	frames, err := ParseFrames(body)
+	if err != nil {
+		return nil, err
+	}
+	var messages []*Message
+	for _, frame := range frames {
+		if frame.Trailer {
+			continue
+		}
+		msg, err := ParseMessage(frame.Payload)
+		if err != nil {
+			return nil, err
+		}
+		msg.ApplyDescriptor(md)
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
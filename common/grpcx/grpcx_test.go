@@ -0,0 +1,185 @@
+package grpcx
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const testProto = `
+syntax = "proto3";
+package demo.v1;
+
+import "google/protobuf/timestamp.proto";
+
+// 用户服务
+service UserService {
+  rpc GetUser (GetUserRequest) returns (User);
+  rpc Watch (stream GetUserRequest) returns (stream User) {}
+}
+
+enum Role {
+  ROLE_UNKNOWN = 0;
+  ROLE_ADMIN = 1 [deprecated = true];
+}
+
+message GetUserRequest {
+  int64 id = 1;
+  string name = 2;
+  Filter filter = 3;
+  message Filter {
+    sint32 offset = 1;
+    repeated string tags = 2;
+  }
+}
+
+message User {
+  option deprecated = true;
+  int64 id = 1;
+  string name = 2;
+  Role role = 3;
+  map<string, string> labels = 4;
+  oneof contact {
+    string email = 5;
+    string phone = 6;
+  }
+  google.protobuf.Timestamp created = 7;
+  reserved 8, 9;
+}
+`
+
+func testRequestMessage() []byte {
+	var filter []byte
+	filter = protowire.AppendTag(filter, 1, protowire.VarintType)
+	filter = protowire.AppendVarint(filter, protowire.EncodeZigZag(-3))
+	filter = protowire.AppendTag(filter, 2, protowire.BytesType)
+	filter = protowire.AppendString(filter, "a")
+	filter = protowire.AppendTag(filter, 2, protowire.BytesType)
+	filter = protowire.AppendString(filter, "b")
+
+	var raw []byte
+	raw = protowire.AppendTag(raw, 1, protowire.VarintType)
+	raw = protowire.AppendVarint(raw, 42)
+	raw = protowire.AppendTag(raw, 2, protowire.BytesType)
+	raw = protowire.AppendString(raw, "admin")
+	raw = protowire.AppendTag(raw, 3, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, filter)
+	return raw
+}
+
+func TestFrames(t *testing.T) {
+	payload := testRequestMessage()
+	body := EncodeFrames(NewFrame(payload), &Frame{Compressed: true, Payload: payload})
+	frames, err := ParseFrames(body)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	assert.False(t, frames[0].Compressed)
+	assert.True(t, frames[1].Compressed)
+	assert.Equal(t, payload, frames[0].Payload)
+	assert.Equal(t, payload, frames[1].Payload)
+
+	_, err = ParseFrames(body[:len(body)-1])
+	assert.Error(t, err)
+
+	assert.True(t, IsGRPCContentType("application/grpc"))
+	assert.True(t, IsGRPCContentType("application/grpc+proto"))
+	assert.True(t, IsGRPCContentType("application/grpc-web"))
+	assert.False(t, IsGRPCContentType("application/grpc-web-text"))
+	assert.False(t, IsGRPCContentType("application/json"))
+
+	assert.True(t, IsNativeGRPCContentType("application/grpc"))
+	assert.True(t, IsNativeGRPCContentType("application/grpc+proto"))
+	assert.True(t, IsNativeGRPCContentType("application/grpc; charset=utf-8"))
+	assert.False(t, IsNativeGRPCContentType("application/grpc-web"))
+	assert.False(t, IsNativeGRPCContentType("application/grpc-web+proto"))
+	assert.False(t, IsNativeGRPCContentType("application/json"))
+}
+
+func TestSchemalessMessage(t *testing.T) {
+	raw := testRequestMessage()
+	msg, err := ParseMessage(raw)
+	require.NoError(t, err)
+	assert.Equal(t, raw, msg.Marshal())
+
+	var paths []string
+	for _, leaf := range msg.Leaves() {
+		paths = append(paths, leaf.Path)
+	}
+	assert.Equal(t, []string{"1", "2", "3.1", "3.2[0]", "3.2[1]"}, paths)
+
+	fields := msg.Find("2")
+	require.Len(t, fields, 1)
+	assert.Equal(t, "admin", fields[0].String())
+	fields[0].SetValue("' or 1=1")
+
+	// 类型混淆：数字字段中设置字符串
+	msg.Find("1")[0].SetValue("abc")
+	fixed, err := ParseMessage(msg.Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, "abc", fixed.Find("1")[0].String())
+	assert.Equal(t, "' or 1=1", fixed.Find("2")[0].String())
+	assert.Equal(t, "b", fixed.Find("3.2[1]")[0].String())
+}
+
+func TestProtoSchema(t *testing.T) {
+	schema, err := LoadSchemaFromProtoFiles(map[string]string{"demo.proto": testProto})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/demo.v1.UserService/GetUser", "/demo.v1.UserService/Watch"}, schema.Methods())
+
+	md := schema.RequestType("/demo.v1.UserService/GetUser")
+	require.NotNil(t, md)
+	assert.EqualValues(t, "demo.v1.GetUserRequest", md.FullName())
+	assert.True(t, schema.ResponseType("/demo.v1.UserService/GetUser").Fields().ByName("labels").IsMap())
+	assert.Nil(t, schema.RequestType("/demo.v1.UserService/Missing"))
+
+	body := EncodeFrames(NewFrame(testRequestMessage()))
+	msgs, err := DecodeMessages(body, md)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	msg := msgs[0]
+
+	var paths []string
+	for _, leaf := range msg.Leaves() {
+		paths = append(paths, leaf.Path)
+	}
+	assert.Equal(t, []string{"id", "name", "filter.offset", "filter.tags[0]", "filter.tags[1]"}, paths)
+	assert.EqualValues(t, -3, msg.Find("filter.offset")[0].Value())
+	assert.JSONEq(t, `{"id":42,"name":"admin","filter":{"offset":-3,"tags":["a","b"]}}`, msg.ToJSON())
+
+	msg.Find("filter.offset")[0].SetValue("-100")
+	decoded, err := ParseMessage(msg.Marshal())
+	require.NoError(t, err)
+	decoded.ApplyDescriptor(md)
+	assert.EqualValues(t, -100, decoded.Find("3.1")[0].Value())
+
+	_, err = ParseProtoFile("bad.proto", "message A { int32 a = ; }")
+	assert.Error(t, err)
+}
+
+func TestFetchSchemaByReflection(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	schema, err := FetchSchemaByReflection(ctx, lis.Addr().String())
+	require.NoError(t, err)
+	assert.Contains(t, schema.Methods(), "/grpc.health.v1.Health/Check")
+	md := schema.RequestType("/grpc.health.v1.Health/Check")
+	require.NotNil(t, md)
+	assert.NotNil(t, md.Fields().ByName("service"))
+}
@@ -0,0 +1,414 @@
+package grpcx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Field 是 protobuf wire format 中的一个字段，同一个 repeated 字段的每个元素都是一个 Field
+type Field struct {
+	Number protowire.Number
+	Type   protowire.Type
+	// VarintType / Fixed32Type / Fixed64Type 的值
+	Uint uint64
+	// BytesType 的值
+	Bytes []byte
+	// BytesType 可以解析为嵌套消息时（或者 schema 中声明为消息时）不为空，StartGroupType 的内容
+	Message *Message
+	// schema 中的字段描述，没有 schema 时为空
+	Desc protoreflect.FieldDescriptor
+}
+
+// Message 是按 wire format 解析出的消息，字段保持原来的顺序，重新编码后与原始数据一致
+type Message struct {
+	Fields []*Field
+	Desc   protoreflect.MessageDescriptor
+}
+
+const maxMessageDepth = 32
+
+// isPrintable 判断 bytes 是否更像是字符串而不是嵌套的消息
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseMessage 不依赖 schema 解析 protobuf 消息，length-delimited 的字段不是可打印的字符串并且可以完整解析时作为嵌套消息
+func ParseMessage(raw []byte) (*Message, error) {
+	return parseMessage(raw, 0)
+}
+
+func parseMessage(raw []byte, depth int) (*Message, error) {
+	if depth > maxMessageDepth {
+		return nil, utils.Error("protobuf message too deep")
+	}
+	msg := &Message{}
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		if num > protowire.MaxValidNumber {
+			return nil, utils.Errorf("invalid field number: %d", num)
+		}
+		raw = raw[n:]
+
+		field := &Field{Number: num, Type: typ}
+		switch typ {
+		case protowire.VarintType:
+			field.Uint, n = protowire.ConsumeVarint(raw)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(raw)
+			field.Uint = uint64(v)
+		case protowire.Fixed64Type:
+			field.Uint, n = protowire.ConsumeFixed64(raw)
+		case protowire.BytesType:
+			var v []byte
+			v, n = protowire.ConsumeBytes(raw)
+			if n >= 0 {
+				field.Bytes = append([]byte{}, v...)
+				if len(v) > 0 && !isPrintable(v) {
+					if sub, err := parseMessage(v, depth+1); err == nil {
+						field.Message = sub
+					}
+				}
+			}
+		case protowire.StartGroupType:
+			var v []byte
+			v, n = protowire.ConsumeGroup(num, raw)
+			if n >= 0 {
+				sub, err := parseMessage(v, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				field.Message = sub
+			}
+		default:
+			return nil, utils.Errorf("unexpected wire type %d for field %d", typ, num)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		raw = raw[n:]
+		msg.Fields = append(msg.Fields, field)
+	}
+	return msg, nil
+}
+
+// ApplyDescriptor 使用 schema 标注字段的名字和类型，schema 中声明为消息的字段即使是可打印的也会作为消息解析
+func (m *Message) ApplyDescriptor(md protoreflect.MessageDescriptor) {
+	if m == nil || md == nil {
+		return
+	}
+	m.Desc = md
+	for _, field := range m.Fields {
+		fd := md.Fields().ByNumber(field.Number)
+		field.Desc = fd
+		if fd == nil {
+			continue
+		}
+		switch fd.Kind() {
+		case protoreflect.MessageKind, protoreflect.GroupKind:
+			if field.Message == nil && field.Type == protowire.BytesType {
+				if sub, err := ParseMessage(field.Bytes); err == nil {
+					field.Message = sub
+				}
+			}
+			field.Message.ApplyDescriptor(fd.Message())
+		default:
+			if field.Type == protowire.BytesType {
+				field.Message = nil
+			}
+		}
+	}
+}
+
+// Marshal 重新编码消息
+func (m *Message) Marshal() []byte {
+	var buf []byte
+	for _, field := range m.Fields {
+		buf = protowire.AppendTag(buf, field.Number, field.Type)
+		switch field.Type {
+		case protowire.VarintType:
+			buf = protowire.AppendVarint(buf, field.Uint)
+		case protowire.Fixed32Type:
+			buf = protowire.AppendFixed32(buf, uint32(field.Uint))
+		case protowire.Fixed64Type:
+			buf = protowire.AppendFixed64(buf, field.Uint)
+		case protowire.BytesType:
+			if field.Message != nil {
+				buf = protowire.AppendBytes(buf, field.Message.Marshal())
+			} else {
+				buf = protowire.AppendBytes(buf, field.Bytes)
+			}
+		case protowire.StartGroupType:
+			if field.Message != nil {
+				buf = append(buf, field.Message.Marshal()...)
+			}
+			buf = protowire.AppendTag(buf, field.Number, protowire.EndGroupType)
+		}
+	}
+	return buf
+}
+
+// Name 有 schema 时为字段名，否则为字段编号
+func (f *Field) Name() string {
+	if f.Desc != nil {
+		return string(f.Desc.Name())
+	}
+	return strconv.Itoa(int(f.Number))
+}
+
+func (f *Field) kind() protoreflect.Kind {
+	if f.Desc == nil {
+		return 0
+	}
+	return f.Desc.Kind()
+}
+
+// Value 返回标量字段的值，有 schema 时按照声明的类型解码（如 sint32 / float）
+func (f *Field) Value() any {
+	switch f.Type {
+	case protowire.VarintType:
+		switch f.kind() {
+		case protoreflect.BoolKind:
+			return protowire.DecodeBool(f.Uint)
+		case protoreflect.Int32Kind, protoreflect.EnumKind:
+			return int64(int32(f.Uint))
+		case protoreflect.Int64Kind:
+			return int64(f.Uint)
+		case protoreflect.Sint32Kind:
+			return int64(int32(protowire.DecodeZigZag(f.Uint & math.MaxUint32)))
+		case protoreflect.Sint64Kind:
+			return protowire.DecodeZigZag(f.Uint)
+		case protoreflect.Uint32Kind:
+			return uint64(uint32(f.Uint))
+		}
+		return f.Uint
+	case protowire.Fixed32Type:
+		switch f.kind() {
+		case protoreflect.FloatKind:
+			return float64(math.Float32frombits(uint32(f.Uint)))
+		case protoreflect.Sfixed32Kind:
+			return int64(int32(uint32(f.Uint)))
+		}
+		return f.Uint
+	case protowire.Fixed64Type:
+		switch f.kind() {
+		case protoreflect.DoubleKind:
+			return math.Float64frombits(f.Uint)
+		case protoreflect.Sfixed64Kind:
+			return int64(f.Uint)
+		}
+		return f.Uint
+	case protowire.BytesType:
+		return string(f.Bytes)
+	}
+	return nil
+}
+
+// String 返回字段值的字符串形式，嵌套消息返回空字符串
+func (f *Field) String() string {
+	if f.Message != nil {
+		return ""
+	}
+	return utils.InterfaceToString(f.Value())
+}
+
+// SetValue 设置字段的值，有 schema 时按照声明的类型编码。
+// 值与原来的类型不兼容时（如在数字字段中设置字符串）字段会被改为 length-delimited 类型，用于测试类型混淆
+func (f *Field) SetValue(value string) {
+	f.Message = nil
+	setBytes := func() {
+		f.Type = protowire.BytesType
+		f.Bytes = []byte(value)
+	}
+
+	switch f.kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		setBytes()
+		return
+	case protoreflect.BoolKind:
+		switch value {
+		case "true":
+			f.Type, f.Uint = protowire.VarintType, 1
+			return
+		case "false":
+			f.Type, f.Uint = protowire.VarintType, 0
+			return
+		}
+	case protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			f.Type, f.Uint = protowire.VarintType, protowire.EncodeZigZag(i)
+			return
+		}
+	case protoreflect.FloatKind:
+		if v, err := strconv.ParseFloat(value, 32); err == nil {
+			f.Type, f.Uint = protowire.Fixed32Type, uint64(math.Float32bits(float32(v)))
+			return
+		}
+	case protoreflect.DoubleKind:
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			f.Type, f.Uint = protowire.Fixed64Type, math.Float64bits(v)
+			return
+		}
+	}
+
+	if f.Type == protowire.BytesType || f.Type == protowire.StartGroupType {
+		setBytes()
+		return
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		f.Uint = uint64(i)
+		if f.Type == protowire.Fixed32Type {
+			f.Uint &= math.MaxUint32
+		}
+		return
+	}
+	if u, err := strconv.ParseUint(value, 10, 64); err == nil {
+		f.Uint = u
+		return
+	}
+	if f.Type == protowire.Fixed32Type || f.Type == protowire.Fixed64Type {
+		if v, err := strconv.ParseFloat(value, 64); err == nil && strings.Contains(value, ".") {
+			if f.Type == protowire.Fixed32Type {
+				f.Uint = uint64(math.Float32bits(float32(v)))
+			} else {
+				f.Uint = math.Float64bits(v)
+			}
+			return
+		}
+	}
+	setBytes()
+}
+
+// Leaf 是消息中的一个标量字段
+type Leaf struct {
+	// 如 user.name / 2.1 / tags[1]，有 schema 时使用字段名
+	Path string
+	// 只使用字段编号的路径，如 2.1
+	NumberPath string
+	Field      *Field
+}
+
+// Leaves 返回所有的标量字段（包括嵌套消息中的字段），同一个字段出现多次时使用 [i] 区分
+func (m *Message) Leaves() []*Leaf {
+	var leaves []*Leaf
+	var walk func(prefix, numberPrefix string, m *Message)
+	walk = func(prefix, numberPrefix string, m *Message) {
+		counts := make(map[protowire.Number]int)
+		for _, field := range m.Fields {
+			counts[field.Number]++
+		}
+		indexes := make(map[protowire.Number]int)
+		for _, field := range m.Fields {
+			name, number := field.Name(), strconv.Itoa(int(field.Number))
+			if counts[field.Number] > 1 {
+				index := "[" + strconv.Itoa(indexes[field.Number]) + "]"
+				name, number = name+index, number+index
+				indexes[field.Number]++
+			}
+			path, numberPath := prefix+name, numberPrefix+number
+			if field.Message != nil {
+				walk(path+".", numberPath+".", field.Message)
+				continue
+			}
+			leaves = append(leaves, &Leaf{Path: path, NumberPath: numberPath, Field: field})
+		}
+	}
+	walk("", "", m)
+	return leaves
+}
+
+// Find 按照 Leaves 中给出的路径（字段名或者字段编号）查找字段
+func (m *Message) Find(path string) []*Field {
+	var fields []*Field
+	for _, leaf := range m.Leaves() {
+		if leaf.Path == path || leaf.NumberPath == path {
+			fields = append(fields, leaf.Field)
+		}
+	}
+	return fields
+}
+
+func (f *Field) jsonValue() any {
+	if f.Message != nil {
+		return f.Message.jsonObject()
+	}
+	if f.kind() == protoreflect.EnumKind && f.Desc.Enum() != nil {
+		if v := f.Desc.Enum().Values().ByNumber(protoreflect.EnumNumber(int32(f.Uint))); v != nil {
+			return string(v.Name())
+		}
+	}
+	if f.Type == protowire.BytesType && f.kind() != protoreflect.StringKind && !isPrintable(f.Bytes) {
+		return hex.EncodeToString(f.Bytes)
+	}
+	return f.Value()
+}
+
+type jsonObjectField struct {
+	key    string
+	values []any
+	list   bool
+}
+
+func (m *Message) jsonObject() json.RawMessage {
+	var fields []*jsonObjectField
+	index := make(map[string]*jsonObjectField)
+	for _, field := range m.Fields {
+		key := field.Name()
+		item, ok := index[key]
+		if !ok {
+			item = &jsonObjectField{key: key, list: field.Desc != nil && field.Desc.IsList()}
+			index[key] = item
+			fields = append(fields, item)
+		}
+		item.values = append(item.values, field.jsonValue())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, item := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(item.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		var value any = item.values
+		if len(item.values) == 1 && !item.list {
+			value = item.values[0]
+		}
+		raw, _ := json.Marshal(value)
+		buf.Write(raw)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// ToJSON 把消息渲染为 JSON，没有 schema 时使用字段编号作为键，不可打印的 bytes 使用 hex 编码
+func (m *Message) ToJSON() string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, m.jsonObject(), "", "  "); err != nil {
+		return string(m.jsonObject())
+	}
+	return buf.String()
+}
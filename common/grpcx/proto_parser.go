@@ -0,0 +1,558 @@
+package grpcx
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// .proto 文件的简单解析，只解析类型需要的部分：package / import / message / enum / service，option 会被忽略
+// https://protobuf.dev/reference/protobuf/proto3-spec/
+
+type protoToken struct {
+	value string
+	// 字符串字面量
+	isString bool
+	line     int
+}
+
+func lexProto(src string) ([]*protoToken, error) {
+	var tokens []*protoToken
+	line := 1
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, utils.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += 2 + end + 2
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(src) && src[i] != c {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return nil, utils.Errorf("line %d: unterminated string", line)
+			}
+			i++
+			value, err := strconv.Unquote(`"` + strings.ReplaceAll(src[start+1:i-1], `"`, `\"`) + `"`)
+			if err != nil {
+				value = src[start+1 : i-1]
+			}
+			tokens = append(tokens, &protoToken{value: value, isString: true, line: line})
+		case c == '_' || c == '.' || c == '-' || c == '+' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '.' || src[i] == '-' || src[i] == '+' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, &protoToken{value: src[start:i], line: line})
+		default:
+			tokens = append(tokens, &protoToken{value: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+type protoParser struct {
+	tokens []*protoToken
+	pos    int
+}
+
+func (p *protoParser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *protoParser) peek() string {
+	if p.eof() {
+		return ""
+	}
+	return p.tokens[p.pos].value
+}
+
+func (p *protoParser) next() string {
+	if p.eof() {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1].value
+}
+
+func (p *protoParser) line() int {
+	if p.eof() {
+		if len(p.tokens) > 0 {
+			return p.tokens[len(p.tokens)-1].line
+		}
+		return 0
+	}
+	return p.tokens[p.pos].line
+}
+
+func (p *protoParser) expect(value string) error {
+	if p.eof() || p.tokens[p.pos].isString || p.peek() != value {
+		return utils.Errorf("line %d: expect %q but got %q", p.line(), value, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+func (p *protoParser) skip(value string) bool {
+	if !p.eof() && !p.tokens[p.pos].isString && p.peek() == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipStatement 跳过到 ; 或者配对的 {} 结束
+func (p *protoParser) skipStatement() error {
+	depth := 0
+	for !p.eof() {
+		switch p.next() {
+		case ";":
+			if depth == 0 {
+				return nil
+			}
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				p.skip(";")
+				return nil
+			}
+		}
+	}
+	return utils.Error("unexpected EOF")
+}
+
+// skipOptions 跳过字段后面的 [ ... ]
+func (p *protoParser) skipOptions() error {
+	if !p.skip("[") {
+		return nil
+	}
+	depth := 1
+	for !p.eof() {
+		switch p.next() {
+		case "[":
+			depth++
+		case "]":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+	return utils.Error("unexpected EOF in options")
+}
+
+func (p *protoParser) number() (int32, error) {
+	value := p.next()
+	n, err := strconv.ParseInt(value, 0, 32)
+	if err != nil {
+		return 0, utils.Errorf("line %d: invalid number %q", p.line(), value)
+	}
+	return int32(n), nil
+}
+
+var protoScalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+}
+
+// ParseProtoFile 把 .proto 文件解析为 FileDescriptorProto，消息和枚举类型的引用留给 protodesc 解析
+func ParseProtoFile(name string, src string) (*descriptorpb.FileDescriptorProto, error) {
+	tokens, err := lexProto(src)
+	if err != nil {
+		return nil, utils.Wrapf(err, "parse %s failed", name)
+	}
+	p := &protoParser{tokens: tokens}
+	fd := &descriptorpb.FileDescriptorProto{Name: proto.String(name), Syntax: proto.String("proto2")}
+	for !p.eof() {
+		switch p.peek() {
+		case "syntax", "edition":
+			p.next()
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			fd.Syntax = proto.String(p.next())
+			p.skip(";")
+		case "package":
+			p.next()
+			fd.Package = proto.String(p.next())
+			p.skip(";")
+		case "import":
+			p.next()
+			if p.peek() == "public" || p.peek() == "weak" {
+				p.next()
+			}
+			fd.Dependency = append(fd.Dependency, p.next())
+			p.skip(";")
+		case "message":
+			msg, err := p.parseMessage()
+			if err != nil {
+				return nil, utils.Wrapf(err, "parse %s failed", name)
+			}
+			fd.MessageType = append(fd.MessageType, msg)
+		case "enum":
+			enum, err := p.parseEnum()
+			if err != nil {
+				return nil, utils.Wrapf(err, "parse %s failed", name)
+			}
+			fd.EnumType = append(fd.EnumType, enum)
+		case "service":
+			service, err := p.parseService()
+			if err != nil {
+				return nil, utils.Wrapf(err, "parse %s failed", name)
+			}
+			fd.Service = append(fd.Service, service)
+		case ";":
+			p.next()
+		default:
+			// option / extend
+			if err := p.skipStatement(); err != nil {
+				return nil, utils.Wrapf(err, "parse %s failed", name)
+			}
+		}
+	}
+	if fd.GetSyntax() == "proto2" {
+		fd.Syntax = nil
+	}
+	return fd, nil
+}
+
+func (p *protoParser) parseMessage() (*descriptorpb.DescriptorProto, error) {
+	p.next()
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(p.next())}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMessageBody(msg, nil); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (p *protoParser) parseMessageBody(msg *descriptorpb.DescriptorProto, oneof *int32) error {
+	for {
+		if p.eof() {
+			return utils.Error("unexpected EOF in message")
+		}
+		switch p.peek() {
+		case "}":
+			p.next()
+			p.skip(";")
+			return nil
+		case ";":
+			p.next()
+		case "message":
+			sub, err := p.parseMessage()
+			if err != nil {
+				return err
+			}
+			msg.NestedType = append(msg.NestedType, sub)
+		case "enum":
+			enum, err := p.parseEnum()
+			if err != nil {
+				return err
+			}
+			msg.EnumType = append(msg.EnumType, enum)
+		case "oneof":
+			p.next()
+			index := int32(len(msg.OneofDecl))
+			msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(p.next())})
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(msg, &index); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions", "extend":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case "map":
+			if err := p.parseMapField(msg); err != nil {
+				return err
+			}
+		default:
+			field, err := p.parseField()
+			if err != nil {
+				return err
+			}
+			field.OneofIndex = oneof
+			msg.Field = append(msg.Field, field)
+		}
+	}
+}
+
+func (p *protoParser) parseField() (*descriptorpb.FieldDescriptorProto, error) {
+	label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	switch p.peek() {
+	case "optional":
+		p.next()
+	case "required":
+		p.next()
+		label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED
+	case "repeated":
+		p.next()
+		label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	}
+	typeName := p.next()
+	if typeName == "group" {
+		return nil, utils.Errorf("line %d: group is not supported", p.line())
+	}
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:  proto.String(p.next()),
+		Label: label.Enum(),
+	}
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	number, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+	field.Number = proto.Int32(number)
+	if t, ok := protoScalarTypes[typeName]; ok {
+		field.Type = t.Enum()
+	} else {
+		field.TypeName = proto.String(typeName)
+	}
+	field.JsonName = proto.String(protoJSONName(field.GetName()))
+	if err := p.skipOptions(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// parseMapField map<K, V> name = N; 等价于 repeated NameEntry，NameEntry 是 map_entry 的嵌套消息
+func (p *protoParser) parseMapField(msg *descriptorpb.DescriptorProto) error {
+	p.next()
+	if err := p.expect("<"); err != nil {
+		return err
+	}
+	keyType := p.next()
+	if err := p.expect(","); err != nil {
+		return err
+	}
+	valueType := p.next()
+	if err := p.expect(">"); err != nil {
+		return err
+	}
+	name := p.next()
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	number, err := p.number()
+	if err != nil {
+		return err
+	}
+	if err := p.skipOptions(); err != nil {
+		return err
+	}
+	if err := p.expect(";"); err != nil {
+		return err
+	}
+
+	entryName := protoCamelCase(name) + "Entry"
+	entry := &descriptorpb.DescriptorProto{
+		Name:    proto.String(entryName),
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+	for i, item := range []struct {
+		name, typ string
+	}{{"key", keyType}, {"value", valueType}} {
+		field := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(item.name),
+			JsonName: proto.String(item.name),
+			Number:   proto.Int32(int32(i + 1)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if t, ok := protoScalarTypes[item.typ]; ok {
+			field.Type = t.Enum()
+		} else {
+			field.TypeName = proto.String(item.typ)
+		}
+		entry.Field = append(entry.Field, field)
+	}
+	msg.NestedType = append(msg.NestedType, entry)
+	msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(protoJSONName(name)),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: proto.String(entryName),
+	})
+	return nil
+}
+
+func (p *protoParser) parseEnum() (*descriptorpb.EnumDescriptorProto, error) {
+	p.next()
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(p.next())}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		if p.eof() {
+			return nil, utils.Error("unexpected EOF in enum")
+		}
+		switch p.peek() {
+		case "}":
+			p.next()
+			p.skip(";")
+			return enum, nil
+		case ";":
+			p.next()
+		case "option", "reserved":
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		default:
+			name := p.next()
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			number, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.skipOptions(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{
+				Name:   proto.String(name),
+				Number: proto.Int32(number),
+			})
+		}
+	}
+}
+
+func (p *protoParser) parseService() (*descriptorpb.ServiceDescriptorProto, error) {
+	p.next()
+	service := &descriptorpb.ServiceDescriptorProto{Name: proto.String(p.next())}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		if p.eof() {
+			return nil, utils.Error("unexpected EOF in service")
+		}
+		switch p.peek() {
+		case "}":
+			p.next()
+			p.skip(";")
+			return service, nil
+		case ";":
+			p.next()
+		case "rpc":
+			p.next()
+			method := &descriptorpb.MethodDescriptorProto{Name: proto.String(p.next())}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			if p.skip("stream") {
+				method.ClientStreaming = proto.Bool(true)
+			}
+			method.InputType = proto.String(p.next())
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if err := p.expect("returns"); err != nil {
+				return nil, err
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			if p.skip("stream") {
+				method.ServerStreaming = proto.Bool(true)
+			}
+			method.OutputType = proto.String(p.next())
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if p.peek() == "{" {
+				if err := p.skipStatement(); err != nil {
+					return nil, err
+				}
+			} else if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			service.Method = append(service.Method, method)
+		default:
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func protoCamelCase(name string) string {
+	var buf strings.Builder
+	upper := true
+	for _, c := range name {
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			buf.WriteRune(unicode.ToUpper(c))
+			upper = false
+			continue
+		}
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
+
+func protoJSONName(name string) string {
+	camel := protoCamelCase(name)
+	if camel == "" {
+		return camel
+	}
+	if name[0] == '_' {
+		return camel
+	}
+	return strings.ToLower(camel[:1]) + camel[1:]
+}
@@ -0,0 +1,125 @@
+package grpcx
+
+import (
+	"context"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// FetchSchemaByReflection 通过服务端反射（grpc.reflection.v1alpha.ServerReflection）获取 schema，
+// 默认不使用 TLS，可以通过 opts 覆盖
+func FetchSchemaByReflection(ctx context.Context, addr string, opts ...grpc.DialOption) (*Schema, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.DialContext(ctx, addr, opts...)
+	if err != nil {
+		return nil, utils.Wrapf(err, "dial %s failed", addr)
+	}
+	defer conn.Close()
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, utils.Wrapf(err, "create reflection stream failed")
+	}
+	defer stream.CloseSend()
+
+	request := func(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, err
+		}
+		rsp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if e := rsp.GetErrorResponse(); e != nil {
+			return nil, utils.Errorf("reflection error(%d): %s", e.GetErrorCode(), e.GetErrorMessage())
+		}
+		return rsp, nil
+	}
+
+	rsp, err := request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, utils.Wrapf(err, "list services failed")
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	addFiles := func(rsp *rpb.ServerReflectionResponse) {
+		for _, raw := range rsp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := new(descriptorpb.FileDescriptorProto)
+			if err := proto.Unmarshal(raw, fd); err != nil {
+				log.Warnf("unmarshal file descriptor failed: %v", err)
+				continue
+			}
+			files[fd.GetName()] = fd
+		}
+	}
+	for _, service := range rsp.GetListServicesResponse().GetService() {
+		if service.GetName() == "grpc.reflection.v1alpha.ServerReflection" || service.GetName() == "grpc.reflection.v1.ServerReflection" {
+			continue
+		}
+		rsp, err := request(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service.GetName()},
+		})
+		if err != nil {
+			log.Warnf("fetch file containing %s failed: %v", service.GetName(), err)
+			continue
+		}
+		addFiles(rsp)
+	}
+
+	// 服务端不一定会返回所有依赖
+	schema := NewSchema()
+	for {
+		var missing []string
+		for _, fd := range files {
+			for _, dep := range fd.GetDependency() {
+				if _, ok := files[dep]; ok {
+					continue
+				}
+				if _, err := schema.FindFileByPath(dep); err == nil {
+					continue
+				}
+				missing = append(missing, dep)
+			}
+		}
+		if len(missing) <= 0 {
+			break
+		}
+		fetched := len(files)
+		for _, dep := range missing {
+			rsp, err := request(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err != nil {
+				log.Warnf("fetch file %s failed: %v", dep, err)
+				continue
+			}
+			addFiles(rsp)
+		}
+		if len(files) == fetched {
+			break
+		}
+	}
+
+	fds := make([]*descriptorpb.FileDescriptorProto, 0, len(files))
+	for _, fd := range files {
+		fds = append(fds, fd)
+	}
+	if len(fds) <= 0 {
+		return nil, utils.Errorf("no service found by reflection from %s", addr)
+	}
+	if err := schema.RegisterFiles(fds...); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
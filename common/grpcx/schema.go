@@ -0,0 +1,207 @@
+package grpcx
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Schema 保存 .proto / FileDescriptorSet / 服务端反射得到的类型，用于给 gRPC 消息标注字段名和类型
+type Schema struct {
+	files *protoregistry.Files
+}
+
+func NewSchema() *Schema {
+	return &Schema{files: new(protoregistry.Files)}
+}
+
+// FindFileByPath 先查找当前 schema，再查找 google/protobuf/*.proto 等内置的文件
+func (s *Schema) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := s.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (s *Schema) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := s.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// RegisterFiles 注册多个 FileDescriptorProto，会按照依赖顺序注册，已经注册过的文件会被忽略
+func (s *Schema) RegisterFiles(fds ...*descriptorpb.FileDescriptorProto) error {
+	pending := make([]*descriptorpb.FileDescriptorProto, 0, len(fds))
+	for _, fd := range fds {
+		if _, err := s.files.FindFileByPath(fd.GetName()); err == nil {
+			continue
+		}
+		pending = append(pending, fd)
+	}
+
+	for len(pending) > 0 {
+		var (
+			rest    []*descriptorpb.FileDescriptorProto
+			lastErr error
+		)
+		for _, fd := range pending {
+			if !s.dependenciesReady(fd, pending) {
+				rest = append(rest, fd)
+				continue
+			}
+			file, err := protodesc.NewFile(fd, s)
+			if err != nil {
+				lastErr = utils.Wrapf(err, "build file descriptor %s failed", fd.GetName())
+				rest = append(rest, fd)
+				continue
+			}
+			if err := s.files.RegisterFile(file); err != nil {
+				return utils.Wrapf(err, "register %s failed", fd.GetName())
+			}
+		}
+		if len(rest) == len(pending) {
+			if lastErr != nil {
+				return lastErr
+			}
+			// 依赖缺失的时候允许不完整的 schema
+			for _, fd := range rest {
+				file, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fd, s)
+				if err != nil {
+					return utils.Wrapf(err, "build file descriptor %s failed", fd.GetName())
+				}
+				if err := s.files.RegisterFile(file); err != nil {
+					return utils.Wrapf(err, "register %s failed", fd.GetName())
+				}
+			}
+			return nil
+		}
+		pending = rest
+	}
+	return nil
+}
+
+// dependenciesReady 依赖都已经注册，或者依赖不在本次注册的文件中
+func (s *Schema) dependenciesReady(fd *descriptorpb.FileDescriptorProto, pending []*descriptorpb.FileDescriptorProto) bool {
+	for _, dep := range fd.GetDependency() {
+		if _, err := s.files.FindFileByPath(dep); err == nil {
+			continue
+		}
+		for _, other := range pending {
+			if other.GetName() == dep {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RegisterFileDescriptorSet 注册 protoc --descriptor_set_out 生成的 FileDescriptorSet
+func (s *Schema) RegisterFileDescriptorSet(raw []byte) error {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &set); err != nil {
+		return utils.Wrapf(err, "unmarshal FileDescriptorSet failed")
+	}
+	return s.RegisterFiles(set.GetFile()...)
+}
+
+// LoadSchemaFromProtoFiles 从 .proto 文件的内容构建 schema，key 为 import 时使用的文件名
+func LoadSchemaFromProtoFiles(files map[string]string) (*Schema, error) {
+	var fds []*descriptorpb.FileDescriptorProto
+	for name, content := range files {
+		fd, err := ParseProtoFile(name, content)
+		if err != nil {
+			return nil, err
+		}
+		fds = append(fds, fd)
+	}
+	sort.Slice(fds, func(i, j int) bool {
+		return fds[i].GetName() < fds[j].GetName()
+	})
+	schema := NewSchema()
+	if err := schema.RegisterFiles(fds...); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// LoadSchemaFromFileDescriptorSet 从 FileDescriptorSet 构建 schema
+func LoadSchemaFromFileDescriptorSet(raw []byte) (*Schema, error) {
+	schema := NewSchema()
+	if err := schema.RegisterFileDescriptorSet(raw); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// FindMethod 通过 HTTP/2 的 :path（/package.Service/Method）查找方法
+func (s *Schema) FindMethod(path string) (protoreflect.MethodDescriptor, error) {
+	if s == nil {
+		return nil, utils.Error("empty schema")
+	}
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	service, method, ok := strings.Cut(path, "/")
+	if !ok {
+		return nil, utils.Errorf("invalid grpc path: %s", path)
+	}
+	d, err := s.files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, utils.Wrapf(err, "service %s not found", service)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, utils.Errorf("%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, utils.Errorf("method %s not found in %s", method, service)
+	}
+	return md, nil
+}
+
+// RequestType 请求消息的类型，找不到时返回 nil
+func (s *Schema) RequestType(path string) protoreflect.MessageDescriptor {
+	md, err := s.FindMethod(path)
+	if err != nil {
+		return nil
+	}
+	return md.Input()
+}
+
+// ResponseType 响应消息的类型，找不到时返回 nil
+func (s *Schema) ResponseType(path string) protoreflect.MessageDescriptor {
+	md, err := s.FindMethod(path)
+	if err != nil {
+		return nil
+	}
+	return md.Output()
+}
+
+// Methods 返回所有方法的 :path
+func (s *Schema) Methods() []string {
+	if s == nil {
+		return nil
+	}
+	var methods []string
+	s.files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			sd := services.Get(i)
+			for j := 0; j < sd.Methods().Len(); j++ {
+				methods = append(methods, "/"+string(sd.FullName())+"/"+string(sd.Methods().Get(j).Name()))
+			}
+		}
+		return true
+	})
+	sort.Strings(methods)
+	return methods
+}
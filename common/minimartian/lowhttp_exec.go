@@ -144,6 +144,9 @@ func (p *Proxy) execLowhttp(req *http.Request) (*http.Response, error) {
 		httpctx.SetRemoteAddr(req, lowHttpResp.RemoteAddr)
 		req.RemoteAddr = lowHttpResp.RemoteAddr
 	}
+	if lowHttpResp.ClientCertificate != "" {
+		httpctx.SetClientCertificate(req, lowHttpResp.ClientCertificate)
+	}
 
 	rsp, err := lowhttp.ParseBytesToHTTPResponse(lowHttpResp.RawPacket)
 	if rsp != nil {
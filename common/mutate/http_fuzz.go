@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/yaklang/yaklang/common/grpcx"
 	"github.com/yaklang/yaklang/common/jsonpath"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/utils"
@@ -33,6 +34,7 @@ type FuzzHTTPRequest struct {
 	_originRequestInstance *http.Request
 	chunked                bool
 	ctx                    context.Context
+	grpcSchema             *grpcx.Schema
 }
 
 func (r *FuzzHTTPRequest) NoAutoEncode() bool {
@@ -102,6 +104,15 @@ type FuzzHTTPRequestIf interface {
 	// 测试 PostJsonPath 中的数据
 	FuzzPostJsonPathParams(k any, jp string, v any) FuzzHTTPRequestIf
 
+	// 测试 XML（包括 SOAP）Body 中 XPath 对应的元素内容或属性值
+	FuzzPostXMLPath(xpath string, v any) FuzzHTTPRequestIf
+
+	// 测试 GraphQL 请求中的参数（如 GetUser.user(id)）或变量（如 $id）
+	FuzzPostGraphQLPath(path string, v any) FuzzHTTPRequestIf
+
+	// 测试 gRPC 请求 protobuf 消息中的字段（如 user.name / 2.1），多个消息时使用 [i] 前缀区分
+	FuzzPostGRPCPath(path string, v any) FuzzHTTPRequestIf
+
 	// 测试 Cookie 中的数据
 	FuzzCookieRaw(value interface{}) FuzzHTTPRequestIf
 
@@ -215,6 +226,7 @@ type buildFuzzHTTPRequestConfig struct {
 	NoAutoEncode bool
 	Proxy        string
 	Ctx          context.Context
+	GRPCSchema   *grpcx.Schema
 }
 
 type BuildFuzzHTTPRequestOption func(config *buildFuzzHTTPRequestConfig)
@@ -255,6 +267,13 @@ func OptContext(ctx context.Context) BuildFuzzHTTPRequestOption {
 	}
 }
 
+// OptGRPCSchema 设置 gRPC 请求使用的 schema，用于按照字段名和类型解析、测试 protobuf 消息
+func OptGRPCSchema(schema *grpcx.Schema) BuildFuzzHTTPRequestOption {
+	return func(config *buildFuzzHTTPRequestConfig) {
+		config.GRPCSchema = schema
+	}
+}
+
 func UrlsToHTTPRequests(target ...interface{}) (*FuzzHTTPRequestBatch, error) {
 	var reqs []*http.Request
 	for _, urlBase := range InterfaceToFuzzResults(target) {
@@ -423,6 +442,7 @@ func NewFuzzHTTPRequest(i interface{}, opts ...BuildFuzzHTTPRequestOption) (*Fuz
 	req.proxy = config.Proxy
 	req.noAutoEncode = config.NoAutoEncode
 	req.ctx = config.Ctx
+	req.grpcSchema = config.GRPCSchema
 	req.opts = opts
 	return req, nil
 }
@@ -444,6 +464,9 @@ func (f *FuzzHTTPRequest) GetCurrentOptions() []BuildFuzzHTTPRequestOption {
 	if f.noAutoEncode {
 		result = append(result, OptDisableAutoEncode(f.noAutoEncode))
 	}
+	if f.grpcSchema != nil {
+		result = append(result, OptGRPCSchema(f.grpcSchema))
+	}
 	return result
 }
 
@@ -735,7 +758,16 @@ func (f *FuzzHTTPRequest) GetPathParams() []*FuzzHTTPRequestParam {
 }
 
 func (f *FuzzHTTPRequest) GetCommonParams() []*FuzzHTTPRequestParam {
-	postParams := f.GetPostJsonParams()
+	postParams := f.GetPostGRPCParams()
+	if len(postParams) <= 0 {
+		postParams = f.GetPostGraphQLParams()
+	}
+	if len(postParams) <= 0 {
+		postParams = f.GetPostJsonParams()
+	}
+	if len(postParams) <= 0 {
+		postParams = f.GetPostXMLParams()
+	}
 	if len(postParams) <= 0 {
 		postParams = f.GetPostParams()
 	}
@@ -748,7 +780,13 @@ func (f *FuzzHTTPRequest) GetCommonParams() []*FuzzHTTPRequestParam {
 func (f *FuzzHTTPRequest) GetAllParams() []*FuzzHTTPRequestParam {
 	var params []*FuzzHTTPRequestParam
 	params = append(params, f.GetGetQueryParams()...)
-	if ret := f.GetPostParams(); len(ret) <= 0 {
+	if ret := f.GetPostGRPCParams(); len(ret) > 0 {
+		params = append(params, ret...)
+	} else if ret := f.GetPostGraphQLParams(); len(ret) > 0 {
+		params = append(params, ret...)
+	} else if ret := f.GetPostXMLParams(); len(ret) > 0 {
+		params = append(params, ret...)
+	} else if ret := f.GetPostParams(); len(ret) <= 0 {
 		ret = f.GetPostJsonParams()
 		params = append(params, ret...)
 	} else {
@@ -0,0 +1,501 @@
+package mutate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/utils/lowhttp/httpctx"
+)
+
+// graphqlRequestBody 是一个 GraphQL 请求：{"query": "...", "variables": {...}, "operationName": "..."}
+type graphqlRequestBody struct {
+	// application/graphql 的请求没有 JSON 包装，object 为 nil
+	object map[string]any
+	query  string
+	doc    *graphqlDocument
+}
+
+type graphqlBody struct {
+	// body 就是 GraphQL 文档（Content-Type: application/graphql）
+	raw bool
+	// JSON 数组形式的批量请求
+	batch    bool
+	requests []*graphqlRequestBody
+}
+
+// graphqlParamNode 是 GraphQL 请求中可以被测试的位置：文档中的参数（字面量）或者 variables 中的值
+type graphqlParamNode struct {
+	// 如 GetUser.user(id) / query.users(filter.name) / $id / [1]$input.name
+	path  string
+	name  string
+	value string
+
+	request int
+	// 文档中的参数
+	argument *graphqlValue
+	// variables 中的路径，元素为 string（对象的键）或 int（数组下标）
+	variable []any
+}
+
+func newGraphQLRequestBody(object map[string]any) (*graphqlRequestBody, error) {
+	query, ok := object["query"].(string)
+	if !ok {
+		return nil, utils.Error("query not found")
+	}
+	doc, err := parseGraphQLDocument(query)
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlRequestBody{object: object, query: query, doc: doc}, nil
+}
+
+// parseGraphQLBody 识别 GraphQL 请求，支持 JSON 对象、JSON 数组（批量请求）和 application/graphql
+func parseGraphQLBody(body []byte, contentType string) (*graphqlBody, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) <= 0 {
+		return nil, utils.Error("empty body")
+	}
+
+	switch body[0] {
+	case '{':
+		var object map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			return nil, err
+		}
+		req, err := newGraphQLRequestBody(object)
+		if err != nil {
+			return nil, err
+		}
+		return &graphqlBody{requests: []*graphqlRequestBody{req}}, nil
+	case '[':
+		var objects []map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&objects); err != nil {
+			return nil, err
+		}
+		if len(objects) <= 0 {
+			return nil, utils.Error("empty batch")
+		}
+		ret := &graphqlBody{batch: true}
+		for _, object := range objects {
+			req, err := newGraphQLRequestBody(object)
+			if err != nil {
+				return nil, err
+			}
+			ret.requests = append(ret.requests, req)
+		}
+		return ret, nil
+	}
+
+	if !strings.Contains(strings.ToLower(contentType), "graphql") {
+		return nil, utils.Error("body is not graphql")
+	}
+	doc, err := parseGraphQLDocument(string(body))
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlBody{raw: true, requests: []*graphqlRequestBody{{query: string(body), doc: doc}}}, nil
+}
+
+func (b *graphqlBody) params() []*graphqlParamNode {
+	var params []*graphqlParamNode
+	for i, req := range b.requests {
+		prefix := ""
+		if b.batch {
+			prefix = fmt.Sprintf("[%d]", i)
+		}
+
+		var walkValue func(path string, name string, v *graphqlValue)
+		walkValue = func(path string, name string, v *graphqlValue) {
+			switch v.kind {
+			case graphqlValueVariable:
+			case graphqlValueList:
+				for index, item := range v.list {
+					walkValue(fmt.Sprintf("%s[%d]", path, index), name, item)
+				}
+			case graphqlValueObject:
+				for _, field := range v.fields {
+					walkValue(path+"."+field.name, field.name, field.value)
+				}
+			default:
+				params = append(params, &graphqlParamNode{
+					path:     path,
+					name:     name,
+					value:    v.value,
+					request:  i,
+					argument: v,
+				})
+			}
+		}
+		var walkFields func(path string, fields []*graphqlField)
+		walkFields = func(path string, fields []*graphqlField) {
+			for _, field := range fields {
+				fieldPath := path + "." + field.responseKey()
+				for _, arg := range field.arguments {
+					walkValue(fieldPath+"("+arg.name, arg.name, arg.value)
+				}
+				walkFields(fieldPath, field.selections)
+			}
+		}
+		for _, def := range req.doc.definitions {
+			name := def.name
+			if name == "" {
+				name = def.kind
+			}
+			var start = len(params)
+			walkFields(prefix+name, def.selections)
+			for _, param := range params[start:] {
+				param.path += ")"
+			}
+		}
+
+		if req.object == nil {
+			continue
+		}
+		var walkVariable func(path string, name string, keys []any, v any)
+		walkVariable = func(path string, name string, keys []any, v any) {
+			switch ret := v.(type) {
+			case map[string]any:
+				names := make([]string, 0, len(ret))
+				for k := range ret {
+					names = append(names, k)
+				}
+				sort.Strings(names)
+				for _, k := range names {
+					subPath := path + "." + k
+					if path == prefix+"$" {
+						subPath = path + k
+					}
+					walkVariable(subPath, k, append(append([]any{}, keys...), k), ret[k])
+				}
+			case []any:
+				for index, item := range ret {
+					walkVariable(fmt.Sprintf("%s[%d]", path, index), name, append(append([]any{}, keys...), index), item)
+				}
+			default:
+				value := ""
+				if v != nil {
+					value = utils.InterfaceToString(v)
+				}
+				params = append(params, &graphqlParamNode{
+					path:     path,
+					name:     name,
+					value:    value,
+					request:  i,
+					variable: keys,
+				})
+			}
+		}
+		if variables, ok := req.object["variables"].(map[string]any); ok {
+			walkVariable(prefix+"$", "", nil, variables)
+		}
+	}
+	return params
+}
+
+func (b *graphqlBody) find(path string) ([]*graphqlParamNode, error) {
+	var nodes []*graphqlParamNode
+	for _, node := range b.params() {
+		if node.path == path {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) <= 0 {
+		return nil, utils.Errorf("graphql param %v not found", path)
+	}
+	return nodes, nil
+}
+
+// graphqlQuote 把字符串编码为 GraphQL 字符串字面量（与 JSON 字符串兼容）
+func graphqlQuote(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// graphqlLiteral 原来是字符串的参数总是使用字符串，其他类型（Int / Boolean / Enum ...）的参数值是合法的数字或布尔值时保持原样
+func graphqlLiteral(kind graphqlValueKind, value string) string {
+	if kind != graphqlValueString {
+		if utils.IsValidInteger(value) || utils.IsValidFloat(value) {
+			return value
+		}
+		switch value {
+		case "true", "false", "null":
+			return value
+		}
+	}
+	return graphqlQuote(value)
+}
+
+func setGraphQLVariable(variables any, keys []any, value string) {
+	for i, key := range keys {
+		last := i == len(keys)-1
+		switch container := variables.(type) {
+		case map[string]any:
+			k, _ := key.(string)
+			if !last {
+				variables = container[k]
+				continue
+			}
+			if _, isString := container[k].(string); isString {
+				container[k] = value
+			} else {
+				container[k] = valueToJsonValue(value)[0]
+			}
+		case []any:
+			index, _ := key.(int)
+			if index < 0 || index >= len(container) {
+				return
+			}
+			if !last {
+				variables = container[index]
+				continue
+			}
+			if _, isString := container[index].(string); isString {
+				container[index] = value
+			} else {
+				container[index] = valueToJsonValue(value)[0]
+			}
+		default:
+			return
+		}
+	}
+}
+
+// replace 把 nodes 对应的位置替换为 value，返回新的 body
+func (b *graphqlBody) replace(nodes []*graphqlParamNode, value string) ([]byte, error) {
+	for index, req := range b.requests {
+		var arguments []*graphqlValue
+		for _, node := range nodes {
+			if node.request != index {
+				continue
+			}
+			if node.argument != nil {
+				arguments = append(arguments, node.argument)
+			} else if req.object != nil {
+				setGraphQLVariable(req.object["variables"], node.variable, value)
+			}
+		}
+		if len(arguments) <= 0 {
+			continue
+		}
+		sort.Slice(arguments, func(i, j int) bool {
+			return arguments[i].start > arguments[j].start
+		})
+		query := req.query
+		for _, arg := range arguments {
+			query = query[:arg.start] + graphqlLiteral(arg.kind, value) + query[arg.end:]
+		}
+		req.query = query
+		if req.object != nil {
+			req.object["query"] = query
+		}
+	}
+	return b.bytes()
+}
+
+func (b *graphqlBody) bytes() ([]byte, error) {
+	if b.raw {
+		return []byte(b.requests[0].query), nil
+	}
+	var data any
+	if b.batch {
+		objects := make([]map[string]any, 0, len(b.requests))
+		for _, req := range b.requests {
+			objects = append(objects, req.object)
+		}
+		data = objects
+	} else {
+		data = b.requests[0].object
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func (f *FuzzHTTPRequest) GetPostGraphQLParams() []*FuzzHTTPRequestParam {
+	body, err := parseGraphQLBody(f.GetBody(), f.GetContentType())
+	if err != nil {
+		return nil
+	}
+
+	var params []*FuzzHTTPRequestParam
+	for _, node := range body.params() {
+		position := posPostGraphQL
+		if node.argument == nil {
+			position = posPostGraphQLVariable
+		}
+		params = append(params, &FuzzHTTPRequestParam{
+			typePosition:     position,
+			param:            node.name,
+			paramOriginValue: []string{node.value},
+			graphqlPath:      node.path,
+			origin:           f,
+		})
+	}
+	return params
+}
+
+func (f *FuzzHTTPRequest) fuzzPostGraphQLPath(path string, val any) ([]*http.Request, error) {
+	req, err := f.GetOriginHTTPRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	body, contentType := f.GetBody(), f.GetContentType()
+	parsed, err := parseGraphQLBody(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parsed.find(path); err != nil {
+		return nil, err
+	}
+
+	values := InterfaceToFuzzResults(val)
+	if values == nil {
+		return nil, utils.Errorf("empty values")
+	}
+
+	var reqs []*http.Request
+	origin := httpctx.GetBareRequestBytes(req)
+	for _, value := range values {
+		// 每次都重新解析，避免修改共享的 variables
+		parsed, err := parseGraphQLBody(body, contentType)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := parsed.find(path)
+		if err != nil {
+			return nil, err
+		}
+		newBody, err := parsed.replace(nodes, value)
+		if err != nil {
+			continue
+		}
+		reqIns, err := lowhttp.ParseBytesToHttpRequest(lowhttp.ReplaceHTTPPacketBodyFast(origin, newBody))
+		if err != nil {
+			continue
+		}
+		reqs = append(reqs, reqIns)
+	}
+	return reqs, nil
+}
+
+func (f *FuzzHTTPRequest) FuzzPostGraphQLPath(path string, val any) FuzzHTTPRequestIf {
+	reqs, err := f.fuzzPostGraphQLPath(path, val)
+	if err != nil {
+		return f.toFuzzHTTPRequestBatch()
+	}
+	return NewFuzzHTTPRequestBatch(f, reqs...)
+}
+
+func (f *FuzzHTTPRequestBatch) FuzzPostGraphQLPath(path string, val any) FuzzHTTPRequestIf {
+	if len(f.nextFuzzRequests) <= 0 {
+		return f.fallback.FuzzPostGraphQLPath(path, val)
+	}
+	var reqs []FuzzHTTPRequestIf
+	for _, req := range f.nextFuzzRequests {
+		reqs = append(reqs, req.FuzzPostGraphQLPath(path, val))
+	}
+	return f.toFuzzHTTPRequestIf(reqs)
+}
+
+// graphqlJSONPacket 使用新的 JSON body 构造请求，请求方法会被设置为 POST
+func (f *FuzzHTTPRequest) graphqlJSONPacket(data any) (*FuzzHTTPRequest, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	packet := lowhttp.ReplaceHTTPPacketMethod(f.originRequest, http.MethodPost)
+	packet = lowhttp.ReplaceHTTPPacketHeader(packet, "Content-Type", "application/json")
+	packet = lowhttp.ReplaceHTTPPacketBody(packet, bytes.TrimRight(buf.Bytes(), "\n"), false)
+	return NewFuzzHTTPRequest(packet, f.opts...)
+}
+
+func (f *FuzzHTTPRequest) graphqlRequestObjects() ([]map[string]any, error) {
+	body, err := parseGraphQLBody(f.GetBody(), f.GetContentType())
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]any
+	for _, req := range body.requests {
+		if req.object == nil {
+			objects = append(objects, map[string]any{"query": req.query})
+			continue
+		}
+		objects = append(objects, req.object)
+	}
+	return objects, nil
+}
+
+// GraphQLBatch 把当前的 GraphQL 请求重复 n 次，组成 JSON 数组形式的批量请求，用于绕过按请求计数的限速（如爆破登录 / OTP）
+func (f *FuzzHTTPRequest) GraphQLBatch(n int) (*FuzzHTTPRequest, error) {
+	if n <= 0 {
+		return nil, utils.Errorf("invalid batch size: %d", n)
+	}
+	objects, err := f.graphqlRequestObjects()
+	if err != nil {
+		return nil, err
+	}
+	var batch []map[string]any
+	for i := 0; i < n; i++ {
+		batch = append(batch, objects...)
+	}
+	return f.graphqlJSONPacket(batch)
+}
+
+// GraphQLAliasBatch 使用别名把 operation 中顶层的字段重复 n 次（alias1: login(...) alias2: login(...)），在一个请求中多次执行
+func (f *FuzzHTTPRequest) GraphQLAliasBatch(n int) (*FuzzHTTPRequest, error) {
+	if n <= 0 {
+		return nil, utils.Errorf("invalid batch size: %d", n)
+	}
+	objects, err := f.graphqlRequestObjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, object := range objects {
+		query := object["query"].(string)
+		doc, err := parseGraphQLDocument(query)
+		if err != nil {
+			return nil, err
+		}
+		var fields []*graphqlField
+		for _, def := range doc.definitions {
+			if def.kind != "fragment" {
+				fields = append(fields, def.selections...)
+			}
+		}
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].end > fields[j].end
+		})
+		for _, field := range fields {
+			var copies strings.Builder
+			for i := 1; i < n; i++ {
+				copies.WriteString(fmt.Sprintf(" alias%d: %s", i, query[field.nameStart:field.end]))
+			}
+			query = query[:field.end] + copies.String() + query[field.end:]
+		}
+		object["query"] = query
+	}
+	if len(objects) == 1 {
+		return f.graphqlJSONPacket(objects[0])
+	}
+	return f.graphqlJSONPacket(objects)
+}
@@ -0,0 +1,291 @@
+package mutate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+// GraphQLIntrospectionQuery 是获取 GraphQL Schema 的内省查询
+const GraphQLIntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  fields(includeDeprecated: true) {
+    name
+    args { ...InputValue }
+    type { ...TypeRef }
+  }
+  inputFields { ...InputValue }
+  enumValues(includeDeprecated: true) { name }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } }
+}`
+
+type graphqlTypeRef struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	OfType *graphqlTypeRef `json:"ofType"`
+}
+
+// named 去掉 NON_NULL / LIST 之后的类型
+func (t *graphqlTypeRef) named() *graphqlTypeRef {
+	for t != nil && t.OfType != nil && (t.Kind == "NON_NULL" || t.Kind == "LIST") {
+		t = t.OfType
+	}
+	return t
+}
+
+type graphqlInputValue struct {
+	Name string          `json:"name"`
+	Type *graphqlTypeRef `json:"type"`
+}
+
+type graphqlFieldDefinition struct {
+	Name string               `json:"name"`
+	Args []*graphqlInputValue `json:"args"`
+	Type *graphqlTypeRef      `json:"type"`
+}
+
+type graphqlFullType struct {
+	Kind        string                    `json:"kind"`
+	Name        string                    `json:"name"`
+	Fields      []*graphqlFieldDefinition `json:"fields"`
+	InputFields []*graphqlInputValue      `json:"inputFields"`
+	EnumValues  []struct {
+		Name string `json:"name"`
+	} `json:"enumValues"`
+}
+
+type graphqlSchema struct {
+	QueryType *struct {
+		Name string `json:"name"`
+	} `json:"queryType"`
+	MutationType *struct {
+		Name string `json:"name"`
+	} `json:"mutationType"`
+	Types []*graphqlFullType `json:"types"`
+
+	types map[string]*graphqlFullType
+}
+
+// parseGraphQLSchema 解析内省查询的结果，可以是完整的 HTTP 响应或者 JSON
+func parseGraphQLSchema(raw []byte) (*graphqlSchema, error) {
+	raw = bytes.TrimSpace(raw)
+	if bytes.HasPrefix(raw, []byte("HTTP/")) {
+		_, raw = lowhttp.SplitHTTPHeadersAndBodyFromPacket(raw)
+	}
+	var result struct {
+		Data struct {
+			Schema *graphqlSchema `json:"__schema"`
+		} `json:"data"`
+		Schema *graphqlSchema `json:"__schema"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, utils.Wrapf(err, "parse graphql introspection result failed")
+	}
+	schema := result.Data.Schema
+	if schema == nil {
+		schema = result.Schema
+	}
+	if schema == nil || len(schema.Types) <= 0 {
+		return nil, utils.Error("__schema not found in introspection result")
+	}
+	schema.types = make(map[string]*graphqlFullType, len(schema.Types))
+	for _, t := range schema.Types {
+		schema.types[t.Name] = t
+	}
+	return schema, nil
+}
+
+// exampleValue 生成参数类型对应的示例字面量
+func (s *graphqlSchema) exampleValue(t *graphqlTypeRef, depth int) string {
+	if t == nil {
+		return "null"
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return s.exampleValue(t.OfType, depth)
+	case "LIST":
+		return "[" + s.exampleValue(t.OfType, depth) + "]"
+	}
+	switch t.Name {
+	case "Int":
+		return "1"
+	case "Float":
+		return "1.0"
+	case "Boolean":
+		return "true"
+	case "ID":
+		return `"1"`
+	case "String":
+		return `"test"`
+	}
+	full, ok := s.types[t.Name]
+	if !ok {
+		return `"test"`
+	}
+	switch full.Kind {
+	case "ENUM":
+		if len(full.EnumValues) > 0 {
+			return full.EnumValues[0].Name
+		}
+		return "null"
+	case "INPUT_OBJECT":
+		if depth > 3 {
+			return "{}"
+		}
+		var fields []string
+		for _, field := range full.InputFields {
+			fields = append(fields, field.Name+": "+s.exampleValue(field.Type, depth+1))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return `"test"`
+}
+
+func (s *graphqlSchema) arguments(args []*graphqlInputValue) string {
+	if len(args) <= 0 {
+		return ""
+	}
+	var ret []string
+	for _, arg := range args {
+		ret = append(ret, arg.Name+": "+s.exampleValue(arg.Type, 0))
+	}
+	return "(" + strings.Join(ret, ", ") + ")"
+}
+
+// selection 生成返回值类型的查询字段，标量字段全部查询，对象字段最多展开两层
+func (s *graphqlSchema) selection(t *graphqlTypeRef, depth int) string {
+	named := t.named()
+	if named == nil {
+		return ""
+	}
+	full, ok := s.types[named.Name]
+	if !ok {
+		return ""
+	}
+	switch full.Kind {
+	case "OBJECT", "INTERFACE":
+	case "UNION":
+		return " { __typename }"
+	default:
+		return ""
+	}
+
+	var fields []string
+	for _, field := range full.Fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		fieldType := field.Type.named()
+		if fieldType == nil {
+			continue
+		}
+		if fieldFull, ok := s.types[fieldType.Name]; ok && (fieldFull.Kind == "OBJECT" || fieldFull.Kind == "INTERFACE" || fieldFull.Kind == "UNION") {
+			if depth >= 2 {
+				continue
+			}
+			fields = append(fields, field.Name+s.arguments(field.Args)+s.selection(field.Type, depth+1))
+			continue
+		}
+		fields = append(fields, field.Name+s.arguments(field.Args))
+	}
+	if len(fields) <= 0 {
+		fields = append(fields, "__typename")
+	}
+	return " { " + strings.Join(fields, " ") + " }"
+}
+
+// operations 为 Query / Mutation 中的每个字段生成一个 GraphQL 文档
+func (s *graphqlSchema) operations() []string {
+	var docs []string
+	for _, root := range []struct {
+		kind string
+		name string
+	}{
+		{kind: "query", name: "Query"},
+		{kind: "mutation", name: "Mutation"},
+	} {
+		switch root.kind {
+		case "query":
+			if s.QueryType != nil {
+				root.name = s.QueryType.Name
+			}
+		case "mutation":
+			if s.MutationType == nil {
+				continue
+			}
+			root.name = s.MutationType.Name
+		}
+		full, ok := s.types[root.name]
+		if !ok {
+			continue
+		}
+		for _, field := range full.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+			docs = append(docs, fmt.Sprintf("%s { %s%s%s }", root.kind, field.Name, s.arguments(field.Args), s.selection(field.Type, 0)))
+		}
+	}
+	return docs
+}
+
+// GraphQLIntrospectionRequest 生成发送到当前请求地址的内省查询请求
+func (f *FuzzHTTPRequest) GraphQLIntrospectionRequest() (*FuzzHTTPRequest, error) {
+	return f.graphqlJSONPacket(map[string]any{"query": GraphQLIntrospectionQuery})
+}
+
+// GraphQLRequestsFromSchema 根据内省查询的结果为每个 query / mutation 生成请求模板，参数使用示例值填充，
+// 可以通过 GetCommonParams 进一步测试每个参数。batch 大于 1 时额外为每个 operation 生成批量请求和别名批量请求
+func (f *FuzzHTTPRequest) GraphQLRequestsFromSchema(schemaResponse []byte, batch int) ([]*FuzzHTTPRequest, error) {
+	schema, err := parseGraphQLSchema(schemaResponse)
+	if err != nil {
+		return nil, err
+	}
+	var reqs []*FuzzHTTPRequest
+	for _, doc := range schema.operations() {
+		req, err := f.graphqlJSONPacket(map[string]any{"query": doc})
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+		if batch <= 1 {
+			continue
+		}
+		if batchReq, err := req.GraphQLBatch(batch); err == nil {
+			reqs = append(reqs, batchReq)
+		}
+		if aliasReq, err := req.GraphQLAliasBatch(batch); err == nil {
+			reqs = append(reqs, aliasReq)
+		}
+	}
+	if len(reqs) <= 0 {
+		return nil, utils.Error("no query or mutation found in schema")
+	}
+	return reqs, nil
+}
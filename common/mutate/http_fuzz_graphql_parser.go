@@ -0,0 +1,551 @@
+package mutate
+
+import (
+	"strings"
+
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// GraphQL 文档的简单解析，只保留模糊测试需要的信息（参数值在文档中的位置）
+// https://spec.graphql.org/October2021/#sec-Language
+
+type graphqlTokenKind int
+
+const (
+	graphqlEOF graphqlTokenKind = iota
+	graphqlPunct
+	graphqlName
+	graphqlInt
+	graphqlFloat
+	graphqlString
+)
+
+type graphqlToken struct {
+	kind       graphqlTokenKind
+	value      string
+	start, end int
+}
+
+func graphqlIsNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func graphqlIsDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func graphqlLex(src string) ([]*graphqlToken, error) {
+	var tokens []*graphqlToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, &graphqlToken{kind: graphqlPunct, value: "...", start: i, end: i + 3})
+			i += 3
+		case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+			tokens = append(tokens, &graphqlToken{kind: graphqlPunct, value: string(c), start: i, end: i + 1})
+			i++
+		case graphqlIsNameStart(c):
+			start := i
+			for i < len(src) && (graphqlIsNameStart(src[i]) || graphqlIsDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, &graphqlToken{kind: graphqlName, value: src[start:i], start: start, end: i})
+		case c == '-' || graphqlIsDigit(c):
+			start := i
+			kind := graphqlInt
+			i++
+			for i < len(src) && graphqlIsDigit(src[i]) {
+				i++
+			}
+			if i < len(src) && src[i] == '.' {
+				kind = graphqlFloat
+				i++
+				for i < len(src) && graphqlIsDigit(src[i]) {
+					i++
+				}
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				kind = graphqlFloat
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && graphqlIsDigit(src[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, &graphqlToken{kind: kind, value: src[start:i], start: start, end: i})
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			for end >= 0 && src[i+3+end-1] == '\\' {
+				next := strings.Index(src[i+3+end+3:], `"""`)
+				if next < 0 {
+					end = -1
+					break
+				}
+				end += 3 + next
+			}
+			if end < 0 {
+				return nil, utils.Errorf("unterminated block string at %d", i)
+			}
+			tokens = append(tokens, &graphqlToken{kind: graphqlString, value: src[i+3 : i+3+end], start: i, end: i + 3 + end + 3})
+			i += 3 + end + 3
+		case c == '"':
+			start := i
+			var buf strings.Builder
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\n' || src[i] == '\r' {
+					return nil, utils.Errorf("unterminated string at %d", start)
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						buf.WriteByte('\n')
+					case 't':
+						buf.WriteByte('\t')
+					case 'r':
+						buf.WriteByte('\r')
+					case 'b':
+						buf.WriteByte('\b')
+					case 'f':
+						buf.WriteByte('\f')
+					case 'u':
+						if i+4 < len(src) {
+							var r rune
+							for _, h := range src[i+1 : i+5] {
+								r <<= 4
+								switch {
+								case h >= '0' && h <= '9':
+									r |= h - '0'
+								case h >= 'a' && h <= 'f':
+									r |= h - 'a' + 10
+								case h >= 'A' && h <= 'F':
+									r |= h - 'A' + 10
+								}
+							}
+							buf.WriteRune(r)
+							i += 4
+						}
+					default:
+						buf.WriteByte(src[i])
+					}
+					i++
+					continue
+				}
+				buf.WriteByte(src[i])
+				i++
+			}
+			if i >= len(src) {
+				return nil, utils.Errorf("unterminated string at %d", start)
+			}
+			i++
+			tokens = append(tokens, &graphqlToken{kind: graphqlString, value: buf.String(), start: start, end: i})
+		default:
+			return nil, utils.Errorf("unexpected character %q at %d", c, i)
+		}
+	}
+	tokens = append(tokens, &graphqlToken{kind: graphqlEOF, start: len(src), end: len(src)})
+	return tokens, nil
+}
+
+type graphqlValueKind string
+
+const (
+	graphqlValueVariable graphqlValueKind = "Variable"
+	graphqlValueInt      graphqlValueKind = "Int"
+	graphqlValueFloat    graphqlValueKind = "Float"
+	graphqlValueString   graphqlValueKind = "String"
+	graphqlValueBoolean  graphqlValueKind = "Boolean"
+	graphqlValueNull     graphqlValueKind = "Null"
+	graphqlValueEnum     graphqlValueKind = "Enum"
+	graphqlValueList     graphqlValueKind = "List"
+	graphqlValueObject   graphqlValueKind = "Object"
+)
+
+type graphqlValue struct {
+	kind graphqlValueKind
+	// 标量的值（字符串已经去掉转义），变量为变量名
+	value      string
+	start, end int
+	list       []*graphqlValue
+	fields     []*graphqlArgument
+}
+
+type graphqlArgument struct {
+	name  string
+	value *graphqlValue
+}
+
+type graphqlField struct {
+	alias, name string
+	// 整个字段（包括别名）在文档中的位置
+	start, end int
+	// 去掉别名之后字段的起始位置
+	nameStart  int
+	arguments  []*graphqlArgument
+	selections []*graphqlField
+}
+
+func (f *graphqlField) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type graphqlVariableDefinition struct {
+	name, typ string
+}
+
+// graphqlDefinition 是 operation（query / mutation / subscription）或者 fragment
+type graphqlDefinition struct {
+	// query / mutation / subscription / fragment
+	kind          string
+	name          string
+	typeCondition string
+	variables     []*graphqlVariableDefinition
+	selections    []*graphqlField
+}
+
+type graphqlDocument struct {
+	source      string
+	definitions []*graphqlDefinition
+}
+
+type graphqlParser struct {
+	src    string
+	tokens []*graphqlToken
+	pos    int
+}
+
+func (p *graphqlParser) peek() *graphqlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *graphqlParser) next() *graphqlToken {
+	t := p.tokens[p.pos]
+	if t.kind != graphqlEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *graphqlParser) is(kind graphqlTokenKind, value string) bool {
+	t := p.peek()
+	return t.kind == kind && t.value == value
+}
+
+func (p *graphqlParser) skip(kind graphqlTokenKind, value string) bool {
+	if p.is(kind, value) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *graphqlParser) expect(kind graphqlTokenKind, value string) (*graphqlToken, error) {
+	t := p.peek()
+	if t.kind != kind || (value != "" && t.value != value) {
+		if value == "" {
+			value = "name"
+		}
+		return nil, utils.Errorf("expect %v but got %q at %d", value, t.value, t.start)
+	}
+	return p.next(), nil
+}
+
+func parseGraphQLDocument(src string) (*graphqlDocument, error) {
+	tokens, err := graphqlLex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &graphqlParser{src: src, tokens: tokens}
+	doc := &graphqlDocument{source: src}
+	for p.peek().kind != graphqlEOF {
+		def, err := p.parseDefinition()
+		if err != nil {
+			return nil, err
+		}
+		doc.definitions = append(doc.definitions, def)
+	}
+	if len(doc.definitions) <= 0 {
+		return nil, utils.Error("empty graphql document")
+	}
+	return doc, nil
+}
+
+func (p *graphqlParser) parseDefinition() (*graphqlDefinition, error) {
+	def := &graphqlDefinition{kind: "query"}
+	if p.is(graphqlPunct, "{") {
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		def.selections = selections
+		return def, nil
+	}
+
+	t, err := p.expect(graphqlName, "")
+	if err != nil {
+		return nil, err
+	}
+	switch t.value {
+	case "query", "mutation", "subscription":
+		def.kind = t.value
+		if p.peek().kind == graphqlName {
+			def.name = p.next().value
+		}
+		if p.skip(graphqlPunct, "(") {
+			for !p.skip(graphqlPunct, ")") {
+				if _, err := p.expect(graphqlPunct, "$"); err != nil {
+					return nil, err
+				}
+				name, err := p.expect(graphqlName, "")
+				if err != nil {
+					return nil, err
+				}
+				if _, err := p.expect(graphqlPunct, ":"); err != nil {
+					return nil, err
+				}
+				typeStart := p.peek().start
+				if err := p.parseType(); err != nil {
+					return nil, err
+				}
+				def.variables = append(def.variables, &graphqlVariableDefinition{
+					name: name.value,
+					typ:  p.src[typeStart:p.tokens[p.pos-1].end],
+				})
+				if p.skip(graphqlPunct, "=") {
+					if _, err := p.parseValue(); err != nil {
+						return nil, err
+					}
+				}
+				if err := p.parseDirectives(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case "fragment":
+		def.kind = t.value
+		name, err := p.expect(graphqlName, "")
+		if err != nil {
+			return nil, err
+		}
+		def.name = name.value
+		if _, err := p.expect(graphqlName, "on"); err != nil {
+			return nil, err
+		}
+		cond, err := p.expect(graphqlName, "")
+		if err != nil {
+			return nil, err
+		}
+		def.typeCondition = cond.value
+	default:
+		return nil, utils.Errorf("unexpected %q at %d", t.value, t.start)
+	}
+	if err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	def.selections = selections
+	return def, nil
+}
+
+func (p *graphqlParser) parseType() error {
+	if p.skip(graphqlPunct, "[") {
+		if err := p.parseType(); err != nil {
+			return err
+		}
+		if _, err := p.expect(graphqlPunct, "]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expect(graphqlName, ""); err != nil {
+		return err
+	}
+	p.skip(graphqlPunct, "!")
+	return nil
+}
+
+func (p *graphqlParser) parseDirectives() error {
+	for p.skip(graphqlPunct, "@") {
+		if _, err := p.expect(graphqlName, ""); err != nil {
+			return err
+		}
+		if _, err := p.parseArguments(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSelectionSet 解析 { ... }，inline fragment 中的字段会直接展开到当前层级，fragment spread 会被忽略
+func (p *graphqlParser) parseSelectionSet() ([]*graphqlField, error) {
+	if _, err := p.expect(graphqlPunct, "{"); err != nil {
+		return nil, err
+	}
+	var fields []*graphqlField
+	for !p.skip(graphqlPunct, "}") {
+		if p.peek().kind == graphqlEOF {
+			return nil, utils.Error("unexpected EOF in selection set")
+		}
+		if p.skip(graphqlPunct, "...") {
+			if p.is(graphqlName, "on") || p.is(graphqlPunct, "@") || p.is(graphqlPunct, "{") {
+				if p.skip(graphqlName, "on") {
+					if _, err := p.expect(graphqlName, ""); err != nil {
+						return nil, err
+					}
+				}
+				if err := p.parseDirectives(); err != nil {
+					return nil, err
+				}
+				inline, err := p.parseSelectionSet()
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, inline...)
+				continue
+			}
+			if _, err := p.expect(graphqlName, ""); err != nil {
+				return nil, err
+			}
+			if err := p.parseDirectives(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (p *graphqlParser) parseField() (*graphqlField, error) {
+	name, err := p.expect(graphqlName, "")
+	if err != nil {
+		return nil, err
+	}
+	field := &graphqlField{name: name.value, start: name.start, nameStart: name.start}
+	if p.skip(graphqlPunct, ":") {
+		realName, err := p.expect(graphqlName, "")
+		if err != nil {
+			return nil, err
+		}
+		field.alias, field.name, field.nameStart = name.value, realName.value, realName.start
+	}
+	field.arguments, err = p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.is(graphqlPunct, "{") {
+		field.selections, err = p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+	}
+	field.end = p.tokens[p.pos-1].end
+	return field, nil
+}
+
+func (p *graphqlParser) parseArguments() ([]*graphqlArgument, error) {
+	if !p.skip(graphqlPunct, "(") {
+		return nil, nil
+	}
+	var args []*graphqlArgument
+	for !p.skip(graphqlPunct, ")") {
+		name, err := p.expect(graphqlName, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(graphqlPunct, ":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &graphqlArgument{name: name.value, value: value})
+	}
+	return args, nil
+}
+
+func (p *graphqlParser) parseValue() (*graphqlValue, error) {
+	t := p.next()
+	v := &graphqlValue{value: t.value, start: t.start, end: t.end}
+	switch t.kind {
+	case graphqlInt:
+		v.kind = graphqlValueInt
+	case graphqlFloat:
+		v.kind = graphqlValueFloat
+	case graphqlString:
+		v.kind = graphqlValueString
+	case graphqlName:
+		switch t.value {
+		case "true", "false":
+			v.kind = graphqlValueBoolean
+		case "null":
+			v.kind = graphqlValueNull
+		default:
+			v.kind = graphqlValueEnum
+		}
+	case graphqlPunct:
+		switch t.value {
+		case "$":
+			name, err := p.expect(graphqlName, "")
+			if err != nil {
+				return nil, err
+			}
+			v.kind, v.value, v.end = graphqlValueVariable, name.value, name.end
+		case "[":
+			v.kind = graphqlValueList
+			for !p.is(graphqlPunct, "]") {
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				v.list = append(v.list, item)
+			}
+			v.end = p.next().end
+		case "{":
+			v.kind = graphqlValueObject
+			for !p.is(graphqlPunct, "}") {
+				name, err := p.expect(graphqlName, "")
+				if err != nil {
+					return nil, err
+				}
+				if _, err := p.expect(graphqlPunct, ":"); err != nil {
+					return nil, err
+				}
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				v.fields = append(v.fields, &graphqlArgument{name: name.value, value: item})
+			}
+			v.end = p.next().end
+		default:
+			return nil, utils.Errorf("unexpected %q at %d", t.value, t.start)
+		}
+	default:
+		return nil, utils.Errorf("unexpected EOF at %d", t.start)
+	}
+	return v, nil
+}
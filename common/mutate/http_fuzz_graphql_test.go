@@ -0,0 +1,173 @@
+package mutate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const graphqlRequest = `POST /graphql HTTP/1.1
+Host: www.example.com
+Content-Type: application/json
+
+{"operationName":"GetUser","query":"query GetUser($id: ID!, $limit: Int) {\n  user(id: $id) {\n    name\n    posts(first: 10, filter: {title: \"abc\", tags: [\"a\", \"b\"]}, order: DESC) { title ...PostFields }\n    ... on Admin { logs(level: 3) }\n  }\n}\nfragment PostFields on Post { comments(author: \"admin\") { text } }","variables":{"id":"1","limit":10,"input":{"name":"x"}}}`
+
+func graphqlQueryOf(t *testing.T, body []byte) map[string]any {
+	var object map[string]any
+	require.NoError(t, json.Unmarshal(body, &object))
+	return object
+}
+
+func TestFuzzHTTPRequest_GetPostGraphQLParams(t *testing.T) {
+	req, err := NewFuzzHTTPRequest(graphqlRequest)
+	require.NoError(t, err)
+
+	values := make(map[string]string)
+	for _, p := range req.GetCommonParams() {
+		values[p.graphqlPath] = p.GetFirstValue()
+	}
+	assert.Equal(t, map[string]string{
+		"GetUser.user.posts(first)":          "10",
+		"GetUser.user.posts(filter.title)":   "abc",
+		"GetUser.user.posts(filter.tags[0])": "a",
+		"GetUser.user.posts(filter.tags[1])": "b",
+		"GetUser.user.posts(order)":          "DESC",
+		"GetUser.user.logs(level)":           "3",
+		"PostFields.comments(author)":        "admin",
+		"$id":                                "1",
+		"$limit":                             "10",
+		"$input.name":                        "x",
+	}, values)
+}
+
+func TestFuzzHTTPRequest_FuzzPostGraphQLPath(t *testing.T) {
+	req, err := NewFuzzHTTPRequest(graphqlRequest)
+	require.NoError(t, err)
+
+	check := func(path string, payload string) map[string]any {
+		res, err := req.FuzzPostGraphQLPath(path, payload).Results()
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		return graphqlQueryOf(t, httpRequestReadBody(res[0]))
+	}
+
+	// 字符串参数
+	body := check("GetUser.user.posts(filter.title)", `' or "1"="1`)
+	assert.Contains(t, body["query"], `title: "' or \"1\"=\"1"`)
+	// 数字参数，数字保持原样，其他值使用字符串
+	body = check("GetUser.user.posts(first)", "100")
+	assert.Contains(t, body["query"], "first: 100,")
+	body = check("GetUser.user.posts(first)", "1 OR 1=1")
+	assert.Contains(t, body["query"], `first: "1 OR 1=1",`)
+	// fragment 中的参数
+	body = check("PostFields.comments(author)", "<script>")
+	assert.Contains(t, body["query"], `comments(author: "<script>")`)
+	// 变量保持类型
+	body = check("$limit", "20")
+	assert.Equal(t, float64(20), body["variables"].(map[string]any)["limit"])
+	body = check("$input.name", "{{int(1)}}")
+	assert.Equal(t, "1", body["variables"].(map[string]any)["input"].(map[string]any)["name"])
+	assert.Equal(t, "GetUser", body["operationName"])
+
+	// 所有的参数都可以通过 Fuzz 测试
+	for _, p := range req.GetCommonParams() {
+		res, err := p.Fuzz("HACKEDPARAM").Results()
+		require.NoError(t, err)
+		require.Len(t, res, 1, p.graphqlPath)
+		assert.Contains(t, string(httpRequestReadBody(res[0])), "HACKEDPARAM", p.graphqlPath)
+	}
+}
+
+func TestFuzzHTTPRequest_GraphQLRawAndBatch(t *testing.T) {
+	req, err := NewFuzzHTTPRequest("POST /graphql HTTP/1.1\r\nHost: www.example.com\r\nContent-Type: application/graphql\r\n\r\n" +
+		`mutation { login(username: "admin", password: "123456") { token } }`)
+	require.NoError(t, err)
+	params := req.GetCommonParams()
+	require.Len(t, params, 2)
+	assert.Equal(t, "mutation.login(password)", params[1].graphqlPath)
+	res, err := params[1].Fuzz("a", "b").Results()
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Contains(t, string(httpRequestReadBody(res[1])), `password: "b"`)
+
+	// 数组形式的批量请求
+	batch, err := req.GraphQLBatch(3)
+	require.NoError(t, err)
+	var objects []map[string]any
+	require.NoError(t, json.Unmarshal(batch.GetBody(), &objects))
+	assert.Len(t, objects, 3)
+	assert.Equal(t, "application/json", batch.GetContentType())
+	params = batch.GetCommonParams()
+	require.Len(t, params, 6)
+	assert.Equal(t, "[2]mutation.login(password)", params[5].graphqlPath)
+	res, err = params[5].Fuzz("c").Results()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(httpRequestReadBody(res[0]), &objects))
+	assert.Contains(t, objects[2]["query"], `password: "c"`)
+	assert.Contains(t, objects[1]["query"], `password: "123456"`)
+
+	// 别名批量请求
+	alias, err := req.GraphQLAliasBatch(3)
+	require.NoError(t, err)
+	query := graphqlQueryOf(t, alias.GetBody())["query"].(string)
+	assert.Equal(t, 3, strings.Count(query, "login("))
+	assert.Contains(t, query, `alias2: login(username: "admin", password: "123456") { token }`)
+	_, err = parseGraphQLDocument(query)
+	assert.NoError(t, err)
+}
+
+func TestFuzzHTTPRequest_GraphQLRequestsFromSchema(t *testing.T) {
+	schema := `{"data":{"__schema":{
+"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},
+"types":[
+ {"kind":"OBJECT","name":"Query","fields":[
+   {"name":"user","args":[{"name":"id","type":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","name":"ID"}}}],"type":{"kind":"OBJECT","name":"User"}},
+   {"name":"search","args":[{"name":"filter","type":{"kind":"INPUT_OBJECT","name":"Filter"}},{"name":"role","type":{"kind":"ENUM","name":"Role"}}],"type":{"kind":"LIST","ofType":{"kind":"OBJECT","name":"User"}}}
+ ]},
+ {"kind":"OBJECT","name":"Mutation","fields":[
+   {"name":"login","args":[{"name":"password","type":{"kind":"SCALAR","name":"String"}}],"type":{"kind":"SCALAR","name":"String"}}
+ ]},
+ {"kind":"OBJECT","name":"User","fields":[
+   {"name":"id","args":[],"type":{"kind":"SCALAR","name":"ID"}},
+   {"name":"age","args":[],"type":{"kind":"SCALAR","name":"Int"}},
+   {"name":"friends","args":[{"name":"first","type":{"kind":"SCALAR","name":"Int"}}],"type":{"kind":"LIST","ofType":{"kind":"OBJECT","name":"User"}}}
+ ]},
+ {"kind":"INPUT_OBJECT","name":"Filter","inputFields":[{"name":"name","type":{"kind":"SCALAR","name":"String"}},{"name":"age","type":{"kind":"SCALAR","name":"Int"}}]},
+ {"kind":"ENUM","name":"Role","enumValues":[{"name":"ADMIN"},{"name":"USER"}]}
+]}}}`
+
+	req, err := NewFuzzHTTPRequest("GET /graphql HTTP/1.1\r\nHost: www.example.com\r\n\r\n")
+	require.NoError(t, err)
+
+	introspection, err := req.GraphQLIntrospectionRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "POST", introspection.GetMethod())
+	assert.Contains(t, string(introspection.GetBody()), "__schema")
+
+	reqs, err := req.GraphQLRequestsFromSchema([]byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n"+schema), 1)
+	require.NoError(t, err)
+	require.Len(t, reqs, 3)
+	var queries []string
+	for _, r := range reqs {
+		queries = append(queries, graphqlQueryOf(t, r.GetBody())["query"].(string))
+	}
+	assert.Equal(t, []string{
+		`query { user(id: "1") { id age friends(first: 1) { id age friends(first: 1) { id age } } } }`,
+		`query { search(filter: {name: "test", age: 1}, role: ADMIN) { id age friends(first: 1) { id age friends(first: 1) { id age } } } }`,
+		`mutation { login(password: "test") }`,
+	}, queries)
+
+	var paths []string
+	for _, p := range reqs[1].GetCommonParams() {
+		paths = append(paths, p.graphqlPath)
+	}
+	assert.Contains(t, paths, "query.search(filter.name)")
+	assert.Contains(t, paths, "query.search(role)")
+
+	reqs, err = req.GraphQLRequestsFromSchema([]byte(schema), 5)
+	require.NoError(t, err)
+	assert.Len(t, reqs, 9)
+}
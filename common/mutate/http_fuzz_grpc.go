@@ -0,0 +1,176 @@
+package mutate
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/yaklang/yaklang/common/grpcx"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/utils/lowhttp/httpctx"
+)
+
+// grpcBody 是 gRPC 请求的 body，messages 与 frames 一一对应，trailer 对应的消息为 nil
+type grpcBody struct {
+	frames   []*grpcx.Frame
+	messages []*grpcx.Message
+	count    int
+}
+
+func (f *FuzzHTTPRequest) parseGRPCBody() (*grpcBody, error) {
+	if !grpcx.IsGRPCContentType(f.GetHeader("Content-Type")) {
+		return nil, utils.Error("not a grpc request")
+	}
+	frames, err := grpcx.ParseFrames(f.GetBody())
+	if err != nil {
+		return nil, err
+	}
+
+	md := f.grpcSchema.RequestType(f.GetPath())
+	body := &grpcBody{frames: frames, messages: make([]*grpcx.Message, len(frames))}
+	for i, frame := range frames {
+		if frame.Trailer {
+			continue
+		}
+		msg, err := grpcx.ParseMessage(frame.Payload)
+		if err != nil {
+			return nil, err
+		}
+		msg.ApplyDescriptor(md)
+		body.messages[i] = msg
+		body.count++
+	}
+	if body.count <= 0 {
+		return nil, utils.Error("empty grpc request")
+	}
+	return body, nil
+}
+
+func (b *grpcBody) prefix(index int) string {
+	if b.count > 1 {
+		return "[" + strconv.Itoa(index) + "]"
+	}
+	return ""
+}
+
+var grpcMessageIndexRegexp = regexp.MustCompile(`^\[(\d+)](.+)$`)
+
+// find 查找路径对应的字段，多个消息时路径以 [i] 开头
+func (b *grpcBody) find(path string) ([]*grpcx.Field, error) {
+	index := 0
+	if matched := grpcMessageIndexRegexp.FindStringSubmatch(path); matched != nil {
+		index, _ = strconv.Atoi(matched[1])
+		path = matched[2]
+	}
+	var fields []*grpcx.Field
+	for _, msg := range b.messages {
+		if msg == nil {
+			continue
+		}
+		if index == 0 {
+			fields = msg.Find(path)
+			break
+		}
+		index--
+	}
+	if len(fields) <= 0 {
+		return nil, utils.Errorf("grpc field %s not found", path)
+	}
+	return fields, nil
+}
+
+func (b *grpcBody) encode() []byte {
+	for i, frame := range b.frames {
+		if msg := b.messages[i]; msg != nil {
+			frame.Payload = msg.Marshal()
+		}
+	}
+	return grpcx.EncodeFrames(b.frames...)
+}
+
+// GetPostGRPCParams 解析 gRPC 请求中的 protobuf 消息，每个标量字段作为一个参数。
+// 设置了 schema（OptGRPCSchema）时使用字段名，否则使用字段编号
+func (f *FuzzHTTPRequest) GetPostGRPCParams() []*FuzzHTTPRequestParam {
+	body, err := f.parseGRPCBody()
+	if err != nil {
+		return nil
+	}
+
+	var params []*FuzzHTTPRequestParam
+	index := 0
+	for _, msg := range body.messages {
+		if msg == nil {
+			continue
+		}
+		prefix := body.prefix(index)
+		index++
+		for _, leaf := range msg.Leaves() {
+			params = append(params, &FuzzHTTPRequestParam{
+				typePosition:     posPostGRPC,
+				param:            leaf.Field.Name(),
+				paramOriginValue: []string{leaf.Field.String()},
+				grpcPath:         prefix + leaf.Path,
+				origin:           f,
+			})
+		}
+	}
+	return params
+}
+
+func (f *FuzzHTTPRequest) fuzzPostGRPCPath(path string, val any) ([]*http.Request, error) {
+	req, err := f.GetOriginHTTPRequest()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.parseGRPCBody(); err != nil {
+		return nil, err
+	}
+
+	values := InterfaceToFuzzResults(val)
+	if values == nil {
+		return nil, utils.Errorf("empty values")
+	}
+
+	var reqs []*http.Request
+	origin := httpctx.GetBareRequestBytes(req)
+	for _, value := range values {
+		// 每次重新解析，避免修改互相影响
+		body, err := f.parseGRPCBody()
+		if err != nil {
+			return nil, err
+		}
+		fields, err := body.find(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			field.SetValue(value)
+		}
+		reqIns, err := lowhttp.ParseBytesToHttpRequest(lowhttp.ReplaceHTTPPacketBodyFast(origin, body.encode()))
+		if err != nil {
+			continue
+		}
+		reqs = append(reqs, reqIns)
+	}
+	return reqs, nil
+}
+
+func (f *FuzzHTTPRequest) FuzzPostGRPCPath(path string, val any) FuzzHTTPRequestIf {
+	reqs, err := f.fuzzPostGRPCPath(path, val)
+	if err != nil {
+		return f.toFuzzHTTPRequestBatch()
+	}
+	return NewFuzzHTTPRequestBatch(f, reqs...)
+}
+
+func (f *FuzzHTTPRequestBatch) FuzzPostGRPCPath(path string, val any) FuzzHTTPRequestIf {
+	if len(f.nextFuzzRequests) <= 0 {
+		return f.fallback.FuzzPostGRPCPath(path, val)
+	}
+	var reqs []FuzzHTTPRequestIf
+	for _, req := range f.nextFuzzRequests {
+		reqs = append(reqs, req.FuzzPostGRPCPath(path, val))
+	}
+	return f.toFuzzHTTPRequestIf(reqs)
+}
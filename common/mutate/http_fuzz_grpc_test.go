@@ -0,0 +1,132 @@
+package mutate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/grpcx"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const grpcTestProto = `
+syntax = "proto3";
+package demo;
+
+service UserService {
+  rpc GetUser (GetUserRequest) returns (GetUserRequest);
+}
+
+message GetUserRequest {
+  int64 id = 1;
+  string name = 2;
+  Page page = 3;
+}
+
+message Page {
+  sint32 offset = 1;
+}
+`
+
+func grpcTestRequest(messages ...[]byte) []byte {
+	var frames []*grpcx.Frame
+	for _, msg := range messages {
+		frames = append(frames, grpcx.NewFrame(msg))
+	}
+	packet := []byte("POST /demo.UserService/GetUser HTTP/2\r\n" +
+		"Host: www.example.com\r\n" +
+		"Content-Type: application/grpc\r\n" +
+		"Te: trailers\r\n\r\n")
+	return lowhttp.ReplaceHTTPPacketBodyFast(packet, grpcx.EncodeFrames(frames...))
+}
+
+func grpcTestMessage(id uint64, name string) []byte {
+	var page []byte
+	page = protowire.AppendTag(page, 1, protowire.VarintType)
+	page = protowire.AppendVarint(page, protowire.EncodeZigZag(-1))
+
+	var raw []byte
+	raw = protowire.AppendTag(raw, 1, protowire.VarintType)
+	raw = protowire.AppendVarint(raw, id)
+	raw = protowire.AppendTag(raw, 2, protowire.BytesType)
+	raw = protowire.AppendString(raw, name)
+	raw = protowire.AppendTag(raw, 3, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, page)
+	return raw
+}
+
+func grpcFuzzResultMessages(t *testing.T, res FuzzHTTPRequestIf, schema *grpcx.Schema) []*grpcx.Message {
+	reqs, err := res.Results()
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	msgs, err := grpcx.DecodeMessages(httpRequestReadBody(reqs[0]), schema.RequestType("/demo.UserService/GetUser"))
+	require.NoError(t, err)
+	return msgs
+}
+
+func TestFuzzHTTPRequest_GetPostGRPCParams(t *testing.T) {
+	packet := grpcTestRequest(grpcTestMessage(1, "admin"))
+
+	// 没有 schema 时使用字段编号
+	req, err := NewFuzzHTTPRequest(packet)
+	require.NoError(t, err)
+	values := make(map[string]string)
+	for _, p := range req.GetCommonParams() {
+		assert.Equal(t, posPostGRPC, p.typePosition)
+		values[p.grpcPath] = p.GetFirstValue()
+	}
+	assert.Equal(t, map[string]string{"1": "1", "2": "admin", "3.1": "1"}, values)
+
+	schema, err := grpcx.LoadSchemaFromProtoFiles(map[string]string{"demo.proto": grpcTestProto})
+	require.NoError(t, err)
+	req, err = NewFuzzHTTPRequest(packet, OptGRPCSchema(schema))
+	require.NoError(t, err)
+	values = make(map[string]string)
+	for _, p := range req.GetCommonParams() {
+		values[p.grpcPath] = p.GetFirstValue()
+	}
+	assert.Equal(t, map[string]string{"id": "1", "name": "admin", "page.offset": "-1"}, values)
+
+	for _, p := range req.GetPostGRPCParams() {
+		switch p.grpcPath {
+		case "name":
+			msgs := grpcFuzzResultMessages(t, p.Fuzz("' or 1=1--"), schema)
+			assert.Equal(t, "' or 1=1--", msgs[0].Find("name")[0].String())
+			assert.EqualValues(t, 1, msgs[0].Find("id")[0].Value())
+		case "page.offset":
+			msgs := grpcFuzzResultMessages(t, p.Fuzz("-2147483648"), schema)
+			assert.EqualValues(t, -2147483648, msgs[0].Find("page.offset")[0].Value())
+		case "id":
+			// 类型混淆：数字字段中发送字符串
+			msgs := grpcFuzzResultMessages(t, p.Fuzz("abc"), schema)
+			assert.Equal(t, "abc", msgs[0].Find("id")[0].String())
+			assert.Equal(t, "admin", msgs[0].Find("name")[0].String())
+		}
+	}
+}
+
+func TestFuzzHTTPRequest_FuzzPostGRPCPath(t *testing.T) {
+	req, err := NewFuzzHTTPRequest(grpcTestRequest(grpcTestMessage(1, "a"), grpcTestMessage(2, "b")))
+	require.NoError(t, err)
+
+	var paths []string
+	for _, p := range req.GetPostGRPCParams() {
+		paths = append(paths, p.grpcPath)
+	}
+	assert.Equal(t, []string{"[0]1", "[0]2", "[0]3.1", "[1]1", "[1]2", "[1]3.1"}, paths)
+
+	reqs, err := req.FuzzPostGRPCPath("[1]2", []string{"x", "y"}).Results()
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+	msgs, err := grpcx.DecodeMessages(httpRequestReadBody(reqs[1]), nil)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "a", msgs[0].Find("2")[0].String())
+	assert.Equal(t, "y", msgs[1].Find("2")[0].String())
+
+	// 路径不存在时保持原请求
+	reqs, err = req.FuzzPostGRPCPath("[1]9", "x").Results()
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+}
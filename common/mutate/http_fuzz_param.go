@@ -27,6 +27,10 @@ var (
 	posPostQueryJson       httpParamPositionType = "post-query-json"
 	posPostQueryBase64Json httpParamPositionType = "post-query-base64-json"
 	posPostJson            httpParamPositionType = "post-json"
+	posPostXML             httpParamPositionType = "post-xml"
+	posPostGraphQL         httpParamPositionType = "post-graphql"
+	posPostGraphQLVariable httpParamPositionType = "post-graphql-variable"
+	posPostGRPC            httpParamPositionType = "post-grpc"
 	posCookie              httpParamPositionType = "cookie"
 	posCookieBase64        httpParamPositionType = "cookie-base64"
 	posCookieJson          httpParamPositionType = "cookie-json"
@@ -67,6 +71,14 @@ func PositionTypeVerbose(pos httpParamPositionType) string {
 		return "POST参数(Base64+JSON)"
 	case posPostJson:
 		return "JSON-Body参数"
+	case posPostXML:
+		return "XML-Body参数"
+	case posPostGraphQL:
+		return "GraphQL参数"
+	case posPostGraphQLVariable:
+		return "GraphQL变量"
+	case posPostGRPC:
+		return "gRPC参数"
 	case posCookie:
 		return "Cookie参数"
 	case posCookieBase64:
@@ -86,12 +98,15 @@ type FuzzHTTPRequestParam struct {
 	param2nd         interface{}
 	paramOriginValue interface{}
 	jsonPath         string
+	xpath            string
+	graphqlPath      string
+	grpcPath         string
 	origin           *FuzzHTTPRequest
 }
 
 func (p *FuzzHTTPRequestParam) IsPostParams() bool {
 	switch p.typePosition {
-	case posPostJson, posPostQuery, posPostQueryBase64, posPostQueryJson, posPostQueryBase64Json:
+	case posPostJson, posPostXML, posPostGraphQL, posPostGraphQLVariable, posPostGRPC, posPostQuery, posPostQueryBase64, posPostQueryJson, posPostQueryBase64Json:
 		return true
 	}
 	return false
@@ -242,6 +257,12 @@ func (p *FuzzHTTPRequestParam) Fuzz(i ...interface{}) FuzzHTTPRequestIf {
 		return p.origin.FuzzPath(InterfaceToFuzzResults(i)...)
 	case posPostJson:
 		return p.origin.FuzzPostJsonParams(p, i)
+	case posPostXML:
+		return p.origin.FuzzPostXMLPath(p.xpath, i)
+	case posPostGraphQL, posPostGraphQLVariable:
+		return p.origin.FuzzPostGraphQLPath(p.graphqlPath, i)
+	case posPostGRPC:
+		return p.origin.FuzzPostGRPCPath(p.grpcPath, i)
 	case posCookie:
 		return p.origin.FuzzCookie(p.param, InterfaceToFuzzResults(i))
 	case posCookieBase64:
@@ -296,6 +317,15 @@ func (p *FuzzHTTPRequestParam) String() string {
 	if p.jsonPath != "" {
 		return fmt.Sprintf("Name:%-20s JsonPath: %-12s Position:[%v(%v)]\n", p.Name(), p.jsonPath, p.PositionVerbose(), p.Position())
 	}
+	if p.xpath != "" {
+		return fmt.Sprintf("Name:%-20s XPath: %-12s Position:[%v(%v)]\n", p.Name(), p.xpath, p.PositionVerbose(), p.Position())
+	}
+	if p.graphqlPath != "" {
+		return fmt.Sprintf("Name:%-20s GraphQL: %-12s Position:[%v(%v)]\n", p.Name(), p.graphqlPath, p.PositionVerbose(), p.Position())
+	}
+	if p.grpcPath != "" {
+		return fmt.Sprintf("Name:%-20s gRPC: %-12s Position:[%v(%v)]\n", p.Name(), p.grpcPath, p.PositionVerbose(), p.Position())
+	}
 	return fmt.Sprintf("Name:%-20s Position:[%v(%v)]\n", p.Name(), p.PositionVerbose(), p.Position())
 }
 
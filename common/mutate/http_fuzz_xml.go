@@ -0,0 +1,386 @@
+package mutate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/utils/lowhttp/httpctx"
+)
+
+// xmlParamNode 是 XML Body 中可以被测试的位置：叶子元素的内容或元素的属性值
+type xmlParamNode struct {
+	xpath string
+	// 元素名（带前缀，如 soap:Body）或 @属性名
+	name  string
+	value string
+
+	// 值在 body 中的位置 [start, end)，CDATA 只替换 CDATA 内部的内容
+	start, end int
+	// 自闭合的元素 <a/> 替换时需要改写为 <a>payload</a>
+	prefix, suffix string
+}
+
+type xmlElement struct {
+	parent *xmlElement
+	name   string
+	// 同名兄弟元素中的序号，从 1 开始
+	index int
+	// 子元素名 => 数量
+	childCount map[string]int
+	children   int
+
+	startTagStart, startTagEnd int
+	selfClosing                bool
+	text                       bytes.Buffer
+
+	content *xmlParamNode
+	attrs   []*xmlParamNode
+}
+
+func (e *xmlElement) xpath() string {
+	if e.parent == nil {
+		return ""
+	}
+	path := e.parent.xpath() + "/" + e.name
+	if e.parent.childCount[e.name] > 1 {
+		path += "[" + strconv.Itoa(e.index) + "]"
+	}
+	return path
+}
+
+type xmlParamTree struct {
+	// 文档顺序的所有元素
+	elements []*xmlElement
+	params   []*xmlParamNode
+}
+
+func xmlQualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// scanXMLAttrValueSpans 扫描开始标签中属性值的位置（不包含引号），返回的位置相对于 tag
+func scanXMLAttrValueSpans(tag []byte) map[string][2]int {
+	isSpace := func(c byte) bool {
+		return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+	}
+	spans := make(map[string][2]int)
+	i := 1
+	for i < len(tag) && !isSpace(tag[i]) && tag[i] != '/' && tag[i] != '>' {
+		i++
+	}
+	for i < len(tag) {
+		for i < len(tag) && isSpace(tag[i]) {
+			i++
+		}
+		if i >= len(tag) || tag[i] == '/' || tag[i] == '>' {
+			break
+		}
+		nameStart := i
+		for i < len(tag) && !isSpace(tag[i]) && tag[i] != '=' && tag[i] != '/' && tag[i] != '>' {
+			i++
+		}
+		name := string(tag[nameStart:i])
+		for i < len(tag) && isSpace(tag[i]) {
+			i++
+		}
+		if i >= len(tag) || tag[i] != '=' {
+			continue
+		}
+		i++
+		for i < len(tag) && isSpace(tag[i]) {
+			i++
+		}
+		if i >= len(tag) || (tag[i] != '"' && tag[i] != '\'') {
+			continue
+		}
+		quote := tag[i]
+		i++
+		end := bytes.IndexByte(tag[i:], quote)
+		if end < 0 {
+			break
+		}
+		if _, ok := spans[name]; !ok {
+			spans[name] = [2]int{i, i + end}
+		}
+		i += end + 1
+	}
+	return spans
+}
+
+// parseXMLParamTree 解析 XML（包括 SOAP Envelope），找到所有叶子元素和属性的位置
+func parseXMLParamTree(body []byte) (*xmlParamTree, error) {
+	body = bytes.TrimRight(body, " \t\r\n")
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return nil, utils.Error("body is not xml")
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	// 不转换编码，保证 InputOffset 与 body 中的位置一致
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	doc := &xmlElement{childCount: make(map[string]int)}
+	current := doc
+	tree := &xmlParamTree{}
+	for {
+		start := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, utils.Wrapf(err, "parse xml failed")
+		}
+		end := int(decoder.InputOffset())
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := xmlQualifiedName(t.Name)
+			current.childCount[name]++
+			current.children++
+			element := &xmlElement{
+				parent:        current,
+				name:          name,
+				index:         current.childCount[name],
+				childCount:    make(map[string]int),
+				startTagStart: start,
+				startTagEnd:   end,
+				selfClosing:   bytes.HasSuffix(body[start:end], []byte("/>")),
+			}
+			spans := scanXMLAttrValueSpans(body[start:end])
+			for _, attr := range t.Attr {
+				attrName := xmlQualifiedName(attr.Name)
+				if attrName == "xmlns" || attr.Name.Space == "xmlns" {
+					continue
+				}
+				span, ok := spans[attrName]
+				if !ok {
+					continue
+				}
+				element.attrs = append(element.attrs, &xmlParamNode{
+					name:  "@" + attrName,
+					value: attr.Value,
+					start: start + span[0],
+					end:   start + span[1],
+				})
+			}
+			tree.elements = append(tree.elements, element)
+			current = element
+		case xml.EndElement:
+			if current == doc || current.name != xmlQualifiedName(t.Name) {
+				return nil, utils.Errorf("unexpected end element </%v>", xmlQualifiedName(t.Name))
+			}
+			node := &xmlParamNode{name: current.name, value: current.text.String()}
+			if current.selfClosing {
+				node.start, node.end = current.startTagEnd-2, current.startTagEnd
+				node.prefix, node.suffix = ">", "</"+current.name+">"
+			} else {
+				node.start, node.end = current.startTagEnd, start
+				inner := body[node.start:node.end]
+				if current.children == 0 && bytes.Count(inner, []byte("<![CDATA[")) == 1 {
+					trimmed := bytes.TrimSpace(inner)
+					if bytes.HasPrefix(trimmed, []byte("<![CDATA[")) && bytes.HasSuffix(trimmed, []byte("]]>")) {
+						node.start += bytes.Index(inner, []byte("<![CDATA[")) + len("<![CDATA[")
+						node.end = current.startTagEnd + bytes.LastIndex(inner, []byte("]]>"))
+					}
+				}
+			}
+			current.content = node
+			current = current.parent
+		case xml.CharData:
+			if current != doc {
+				current.text.Write(t)
+			}
+		}
+	}
+	if current != doc {
+		return nil, utils.Errorf("unclosed element <%v>", current.name)
+	}
+	if len(tree.elements) <= 0 {
+		return nil, utils.Error("no xml element found")
+	}
+
+	for _, element := range tree.elements {
+		path := element.xpath()
+		if element.children == 0 {
+			element.content.xpath = path
+			tree.params = append(tree.params, element.content)
+		}
+		for _, attr := range element.attrs {
+			attr.xpath = path + "/" + attr.name
+			tree.params = append(tree.params, attr)
+		}
+	}
+	return tree, nil
+}
+
+// find 查找 XPath 对应的位置，优先匹配 GetPostXMLParams 中给出的路径，否则作为 XPath 表达式查询
+func (t *xmlParamTree) find(body []byte, expr string) ([]*xmlParamNode, error) {
+	for _, param := range t.params {
+		if param.xpath == expr {
+			return []*xmlParamNode{param}, nil
+		}
+	}
+
+	doc, err := xmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, utils.Wrapf(err, "parse xml failed")
+	}
+	nodes, err := xmlquery.QueryAll(doc, expr)
+	if err != nil {
+		return nil, utils.Wrapf(err, "invalid xpath: %v", expr)
+	}
+
+	var elements []*xmlquery.Node
+	var walk func(*xmlquery.Node)
+	walk = func(n *xmlquery.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == xmlquery.ElementNode {
+				elements = append(elements, child)
+			}
+			walk(child)
+		}
+	}
+	walk(doc)
+	if len(elements) != len(t.elements) {
+		return nil, utils.Errorf("xml elements mismatch: %v != %v", len(elements), len(t.elements))
+	}
+	index := make(map[*xmlquery.Node]*xmlElement, len(elements))
+	for i, element := range elements {
+		index[element] = t.elements[i]
+	}
+
+	var results []*xmlParamNode
+	for _, node := range nodes {
+		switch node.Type {
+		case xmlquery.ElementNode:
+			if element, ok := index[node]; ok {
+				results = append(results, element.content)
+			}
+		case xmlquery.AttributeNode:
+			element, ok := index[node.Parent]
+			if !ok {
+				continue
+			}
+			for _, attr := range element.attrs {
+				if attr.name == "@"+node.Data || strings.HasSuffix(attr.name, ":"+node.Data) {
+					results = append(results, attr)
+					break
+				}
+			}
+		}
+	}
+	if len(results) <= 0 {
+		return nil, utils.Errorf("xpath %v not found", expr)
+	}
+	return results, nil
+}
+
+// replaceXMLParamNodes 把所有位置替换为 value，value 不做 XML 转义，便于测试 XXE 等注入
+func replaceXMLParamNodes(body []byte, nodes []*xmlParamNode, value string) []byte {
+	nodes = append([]*xmlParamNode{}, nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].start < nodes[j].start
+	})
+
+	var buf bytes.Buffer
+	last := 0
+	for _, node := range nodes {
+		if node.start < last {
+			// 嵌套的元素，外层已经被替换
+			continue
+		}
+		buf.Write(body[last:node.start])
+		buf.WriteString(node.prefix)
+		buf.WriteString(value)
+		buf.WriteString(node.suffix)
+		last = node.end
+	}
+	buf.Write(body[last:])
+	return buf.Bytes()
+}
+
+func (f *FuzzHTTPRequest) GetPostXMLParams() []*FuzzHTTPRequestParam {
+	body := f.GetBody()
+	tree, err := parseXMLParamTree(body)
+	if err != nil {
+		return nil
+	}
+
+	var params []*FuzzHTTPRequestParam
+	for _, node := range tree.params {
+		params = append(params, &FuzzHTTPRequestParam{
+			typePosition:     posPostXML,
+			param:            node.name,
+			paramOriginValue: []string{node.value},
+			xpath:            node.xpath,
+			origin:           f,
+		})
+	}
+	return params
+}
+
+func (f *FuzzHTTPRequest) fuzzPostXMLPath(xpath string, val any) ([]*http.Request, error) {
+	req, err := f.GetOriginHTTPRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	body := f.GetBody()
+	tree, err := parseXMLParamTree(body)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := tree.find(body, xpath)
+	if err != nil {
+		return nil, err
+	}
+
+	values := InterfaceToFuzzResults(val)
+	if values == nil {
+		return nil, utils.Errorf("empty values")
+	}
+
+	var reqs []*http.Request
+	origin := httpctx.GetBareRequestBytes(req)
+	for _, value := range values {
+		reqIns, err := lowhttp.ParseBytesToHttpRequest(lowhttp.ReplaceHTTPPacketBodyFast(origin, replaceXMLParamNodes(body, nodes, value)))
+		if err != nil {
+			continue
+		}
+		reqs = append(reqs, reqIns)
+	}
+	return reqs, nil
+}
+
+func (f *FuzzHTTPRequest) FuzzPostXMLPath(xpath string, val any) FuzzHTTPRequestIf {
+	reqs, err := f.fuzzPostXMLPath(xpath, val)
+	if err != nil {
+		return f.toFuzzHTTPRequestBatch()
+	}
+	return NewFuzzHTTPRequestBatch(f, reqs...)
+}
+
+func (f *FuzzHTTPRequestBatch) FuzzPostXMLPath(xpath string, val any) FuzzHTTPRequestIf {
+	if len(f.nextFuzzRequests) <= 0 {
+		return f.fallback.FuzzPostXMLPath(xpath, val)
+	}
+	var reqs []FuzzHTTPRequestIf
+	for _, req := range f.nextFuzzRequests {
+		reqs = append(reqs, req.FuzzPostXMLPath(xpath, val))
+	}
+	return f.toFuzzHTTPRequestIf(reqs)
+}
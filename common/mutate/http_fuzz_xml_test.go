@@ -0,0 +1,103 @@
+package mutate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const soapRequest = `POST /ws/user HTTP/1.1
+Host: www.example.com
+Content-Type: text/xml; charset=utf-8
+SOAPAction: "GetUser"
+
+<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:m="http://example.com/user">
+  <soap:Header><m:Token type="session">abc</m:Token></soap:Header>
+  <soap:Body>
+    <m:GetUser>
+      <m:Id>1</m:Id>
+      <m:Name><![CDATA[admin]]></m:Name>
+      <m:Tag>a</m:Tag>
+      <m:Tag>b</m:Tag>
+      <m:Empty/>
+    </m:GetUser>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestFuzzHTTPRequest_GetPostXMLParams(t *testing.T) {
+	req, err := NewFuzzHTTPRequest(soapRequest)
+	require.NoError(t, err)
+
+	params := req.GetCommonParams()
+	values := make(map[string]string)
+	for _, p := range params {
+		assert.Equal(t, posPostXML, p.typePosition)
+		values[p.xpath] = p.GetFirstValue()
+	}
+	assert.Equal(t, map[string]string{
+		"/soap:Envelope/soap:Header/m:Token":          "abc",
+		"/soap:Envelope/soap:Header/m:Token/@type":    "session",
+		"/soap:Envelope/soap:Body/m:GetUser/m:Id":     "1",
+		"/soap:Envelope/soap:Body/m:GetUser/m:Name":   "admin",
+		"/soap:Envelope/soap:Body/m:GetUser/m:Tag[1]": "a",
+		"/soap:Envelope/soap:Body/m:GetUser/m:Tag[2]": "b",
+		"/soap:Envelope/soap:Body/m:GetUser/m:Empty":  "",
+	}, values)
+
+	for _, p := range params {
+		res, err := p.Fuzz("HACKED<x>").Results()
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		body := string(httpRequestReadBody(res[0]))
+		assert.Equal(t, 1, strings.Count(body, "HACKED<x>"), p.xpath)
+		// 其他结构保持不变
+		assert.Contains(t, body, `<?xml version="1.0" encoding="utf-8"?>`)
+		assert.Contains(t, body, `xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"`)
+		switch p.xpath {
+		case "/soap:Envelope/soap:Body/m:GetUser/m:Name":
+			assert.Contains(t, body, "<m:Name><![CDATA[HACKED<x>]]></m:Name>")
+		case "/soap:Envelope/soap:Body/m:GetUser/m:Empty":
+			assert.Contains(t, body, "<m:Empty>HACKED<x></m:Empty>")
+		case "/soap:Envelope/soap:Header/m:Token/@type":
+			assert.Contains(t, body, `<m:Token type="HACKED<x>">abc</m:Token>`)
+		}
+	}
+}
+
+func TestFuzzHTTPRequest_FuzzPostXMLPath(t *testing.T) {
+	req, err := NewFuzzHTTPRequest(soapRequest)
+	require.NoError(t, err)
+
+	// XPath 表达式
+	res, err := req.FuzzPostXMLPath("//m:Tag", []string{"x", "y"}).Results()
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	body := string(httpRequestReadBody(res[1]))
+	assert.Contains(t, body, "<m:Tag>y</m:Tag>\n      <m:Tag>y</m:Tag>")
+
+	res, err = req.FuzzPostXMLPath("//m:GetUser/*[local-name()='Id']", "&xxe;").Results()
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Contains(t, string(httpRequestReadBody(res[0])), "<m:Id>&xxe;</m:Id>")
+
+	res, err = req.FuzzPostXMLPath("//m:Token/@type", "{{int(1-3)}}").Results()
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+	assert.Contains(t, string(httpRequestReadBody(res[2])), `type="3"`)
+
+	// 链式调用
+	res, err = req.FuzzPostXMLPath("/soap:Envelope/soap:Body/m:GetUser/m:Id", []string{"1", "2"}).
+		FuzzPostXMLPath("/soap:Envelope/soap:Body/m:GetUser/m:Tag[2]", []string{"c", "d"}).Results()
+	require.NoError(t, err)
+	assert.Len(t, res, 4)
+}
+
+func TestFuzzHTTPRequest_GetPostXMLParams_NotXML(t *testing.T) {
+	req, err := NewFuzzHTTPRequest("POST / HTTP/1.1\r\nHost: www.example.com\r\n\r\na=1&b=<2>")
+	require.NoError(t, err)
+	assert.Empty(t, req.GetPostXMLParams())
+	assert.Len(t, req.GetCommonParams(), 2)
+}
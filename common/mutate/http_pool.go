@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/yaklang/yaklang/common/grpcx"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/utils"
 	"github.com/yaklang/yaklang/common/utils/lowhttp"
@@ -73,6 +74,9 @@ type httpPoolConfig struct {
 	DNSServers []string
 	EtcHosts   map[string]string
 
+	// mTLS 客户端证书
+	ClientCertificates []*lowhttp.ClientCertificateRule
+
 	// RuntimeId
 	RuntimeId string
 
@@ -91,6 +95,16 @@ type httpPoolConfig struct {
 	ExternSwitch *utils.Switch
 }
 
+// _httpPool_ClientCertificate 请求匹配 hostPatterns 的目标时使用证书仓库中名为 name 的客户端证书
+func _httpPool_ClientCertificate(name string, hostPatterns ...string) HttpPoolConfigOption {
+	return func(config *httpPoolConfig) {
+		config.ClientCertificates = append(config.ClientCertificates, &lowhttp.ClientCertificateRule{
+			Name:         name,
+			HostPatterns: utils.StringArrayFilterEmpty(hostPatterns),
+		})
+	}
+}
+
 // WithPoolOpt_DNSNoCache is not effective
 func WithPoolOpt_DNSNoCache(b bool) HttpPoolConfigOption {
 	return func(config *httpPoolConfig) {
@@ -343,6 +357,13 @@ func _httpPool_IsGmTLS(f bool) HttpPoolConfigOption {
 	}
 }
 
+// _httpPool_ForceHttp2 强制使用 HTTP/2 发送请求，gRPC 请求（Content-Type: application/grpc）会自动使用 HTTP/2，grpc-web 仍然使用 HTTP/1.1
+func _httpPool_ForceHttp2(f bool) HttpPoolConfigOption {
+	return func(config *httpPoolConfig) {
+		config.ForceHttp2 = f
+	}
+}
+
 func _httpPool_proxies(proxies ...string) HttpPoolConfigOption {
 	return func(config *httpPoolConfig) {
 		config.Proxies = proxies
@@ -620,7 +641,7 @@ func _httpPool(i interface{}, opts ...HttpPoolConfigOption) (chan *HttpResult, e
 							lowhttp.WithJsRedirect(config.FollowJSRedirect),
 							lowhttp.WithContext(config.Ctx),
 							lowhttp.WithNoFixContentLength(config.NoFixContentLength),
-							lowhttp.WithHttp2(config.ForceHttp2),
+							lowhttp.WithHttp2(config.ForceHttp2 || grpcx.IsNativeGRPCContentType(lowhttp.GetHTTPPacketContentType(targetRequest))),
 							lowhttp.WithSource(config.Source),
 							lowhttp.WithProxy(config.Proxies...),
 							lowhttp.WithRetryTimes(config.RetryTimes),
@@ -642,6 +663,10 @@ func _httpPool(i interface{}, opts ...HttpPoolConfigOption) (chan *HttpResult, e
 							lowhttpOptions = append(lowhttpOptions, lowhttp.WithMaxContentLength(int(config.MaxContentLength)))
 						}
 
+						for _, rule := range config.ClientCertificates {
+							lowhttpOptions = append(lowhttpOptions, lowhttp.WithClientCertificate(rule.Name, rule.HostPatterns...))
+						}
+
 						rspInstance, err := lowhttp.HTTP(lowhttpOptions...)
 						var rsp []byte
 						if rspInstance != nil {
@@ -869,6 +894,7 @@ var HttpPoolExports = map[string]interface{}{
 	"fuzz":               _httpPool_SetForceFuzz,
 	"fuzzParams":         _httpPool_SetFuzzParams,
 	"noFixContentLength": _httpPool_noFixContentLength,
+	"http2":              _httpPool_ForceHttp2,
 }
 
 var (
@@ -884,6 +910,7 @@ var (
 	WithPoolOpt_Https                      = _httpPool_IsHttps
 	WithPoolOpt_RuntimeId                  = _httpPool_runtimeId
 	WithPoolOpt_GmTLS                      = _httpPool_IsGmTLS
+	WithPoolOpt_ForceHttp2                 = _httpPool_ForceHttp2
 	WithPoolOpt_NoFollowRedirect           = _httpPool_SetNoFollowRedirect
 	WithPoolOpt_FollowJSRedirect           = _httpPool_SetFollowJSRedirect
 	WithPoolOpt_Context                    = _httpPool_SetContext
@@ -909,6 +936,7 @@ var (
 	WithPoolOpt_EtcHosts                   = _httpPool_EtcHosts
 	WithPoolOpt_NoSystemProxy              = _httpPool_NoSystemProxy
 	WithPoolOpt_RequestCountLimiter        = _httpPool_RequestCountLimiter
+	WithPoolOpt_ClientCertificate          = _httpPool_ClientCertificate
 	WithConnPool                           = _httpPool_withConnPool
 	WithPoolOpt_ExternSwitch               = _httpPool_ExternSwitch
 )
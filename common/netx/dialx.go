@@ -202,6 +202,14 @@ func DialX(target string, opt ...DialXOption) (net.Conn, error) {
 	if config.ShouldOverrideTLSConfig {
 		tlsConfig = config.TLSConfig
 	}
+	if len(config.ClientCertificates) > 0 {
+		if ret, ok := tlsConfig.(*tls.Config); ok {
+			ret = ret.Clone()
+			ret.Certificates = config.ClientCertificates
+			ret.GetClientCertificate = clientCertificateGetter(config.ClientCertificates)
+			tlsConfig = ret
+		}
+	}
 	var tlsTimeout = 10 * time.Second
 	if config.TLSTimeout > 0 {
 		tlsTimeout = config.TLSTimeout
@@ -31,6 +31,8 @@ type dialXConfig struct {
 	ShouldOverrideSNI         bool
 	SNI                       string
 	TLSNextProto              []string
+	// ClientCertificates 为本次连接使用的客户端证书，设置后不再使用全局预置的证书
+	ClientCertificates []tls.Certificate
 
 	// Retry
 	EnableTimeoutRetry  bool
@@ -185,6 +187,13 @@ func DialX_WithTLSNextProto(nextProtos ...string) DialXOption {
 	}
 }
 
+// DialX_WithClientCertificates 为本次连接指定 mTLS 客户端证书，优先于 LoadP12Bytes 加载的全局证书
+func DialX_WithClientCertificates(certs ...tls.Certificate) DialXOption {
+	return func(c *dialXConfig) {
+		c.ClientCertificates = certs
+	}
+}
+
 func DialX_WithTLSConfig(tlsConfig any) DialXOption {
 	return func(c *dialXConfig) {
 		c.EnableTLS = true
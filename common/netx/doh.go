@@ -26,19 +26,23 @@ but... only few api source can be used.
 */
 
 type DoHDNSResponse struct {
-	Status   int  `json:"Status"`
-	TC       bool `json:"TC"`
-	RD       bool `json:"RD"`
-	RA       bool `json:"RA"`
-	AD       bool `json:"AD"`
-	CD       bool `json:"CD"`
-	Question json.RawMessage
-	Answer   []struct {
-		Name string `json:"name"`
-		Type int    `json:"type"`
-		TTL  int    `json:"TTL"`
-		Data string `json:"data"`
-	} `json:"Answer"`
+	Status     int  `json:"Status"`
+	TC         bool `json:"TC"`
+	RD         bool `json:"RD"`
+	RA         bool `json:"RA"`
+	AD         bool `json:"AD"`
+	CD         bool `json:"CD"`
+	Question   json.RawMessage
+	Answer     []*DoHDNSRecord `json:"Answer"`
+	Authority  []*DoHDNSRecord `json:"Authority"`
+	Additional []*DoHDNSRecord `json:"Additional"`
+}
+
+type DoHDNSRecord struct {
+	Name string `json:"name"`
+	Type int    `json:"type"`
+	TTL  int    `json:"TTL"`
+	Data string `json:"data"`
 }
 
 func dohRequest(domain string, dohUrl string, config *ReliableDNSConfig) error {
@@ -0,0 +1,161 @@
+package netx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// ExchangeDNS sends an arbitrary dns query (any type / class) and returns the whole dns response.
+//
+// the query is sent to SpecificDNSServers (udp, fallback to tcp when truncated or PreferTCP),
+// DoH servers will be used when PreferDoH / FallbackDoH is set.
+// return the response and the server which answered it.
+func ExchangeDNS(msg *dns.Msg, opt ...DNSOption) (*dns.Msg, string, error) {
+	if msg == nil || len(msg.Question) <= 0 {
+		return nil, "", utils.Error("dns question is empty")
+	}
+
+	config := NewDefaultReliableDNSConfig()
+	for _, o := range opt {
+		o(config)
+	}
+	if config.RetryTimes <= 0 {
+		config.RetryTimes = 1
+	}
+
+	defer func() {
+		if config.OnFinished != nil {
+			config.OnFinished()
+		}
+	}()
+
+	domain := strings.TrimSuffix(msg.Question[0].Name, ".")
+	if config.DisabledDomain != nil && config.DisabledDomain.Contains(domain) {
+		return nil, "", utils.Errorf("domain %s is disabled(forbidden by yaklang config, check WithDNSDisabledDomain)", domain)
+	}
+
+	startDoH := func() (*dns.Msg, string, error) {
+		for _, doh := range config.SpecificDoH {
+			rsp, err := exchangeDoH(msg, doh, config)
+			if err != nil {
+				log.Debugf("doh[%v] exchange %v failed: %s", doh, domain, err)
+				continue
+			}
+			return rsp, doh, nil
+		}
+		return nil, "", utils.Errorf("all doh servers failed for %v", domain)
+	}
+
+	var dohExecuted bool
+	if config.PreferDoH {
+		dohExecuted = true
+		if rsp, server, err := startDoH(); err == nil {
+			return rsp, server, nil
+		}
+	}
+
+	for _, server := range config.SpecificDNSServers {
+		for i := 0; i < config.RetryTimes; i++ {
+			rsp, err := exchangeDNSWithServer(msg, server, config)
+			if err != nil {
+				log.Debugf("dns server %s exchange %v failed: %s", server, domain, err)
+				continue
+			}
+			return rsp, server, nil
+		}
+	}
+
+	if config.FallbackDoH && !dohExecuted {
+		return startDoH()
+	}
+	return nil, "", utils.Errorf("exchange dns for %v failed", domain)
+}
+
+func exchangeDNSWithServer(msg *dns.Msg, server string, config *ReliableDNSConfig) (*dns.Msg, error) {
+	server = utils.AppendDefaultPort(server, 53)
+	ctx, cancel := context.WithTimeout(config.GetBaseContext(), config.Timeout)
+	defer cancel()
+
+	network := "udp"
+	if config.PreferTCP {
+		network = "tcp"
+	}
+	client := &dns.Client{Net: network, Timeout: config.Timeout}
+	rsp, _, err := client.ExchangeContext(ctx, msg, server)
+	if network == "udp" && (err != nil || (rsp != nil && rsp.Truncated)) {
+		if err == nil || config.FallbackTCP {
+			client.Net = "tcp"
+			if tcpRsp, _, tcpErr := client.ExchangeContext(ctx, msg, server); tcpErr == nil {
+				return tcpRsp, nil
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func exchangeDoH(msg *dns.Msg, dohUrl string, config *ReliableDNSConfig) (*dns.Msg, error) {
+	question := msg.Question[0]
+	var val = make(url.Values)
+	val.Set("name", question.Name)
+	val.Set("type", fmt.Sprint(question.Qtype))
+
+	ctx, cancel := context.WithTimeout(config.GetBaseContext(), config.Timeout)
+	defer cancel()
+	reqInstance, err := http.NewRequestWithContext(ctx, "GET", dohUrl, nil)
+	if err != nil {
+		return nil, utils.Errorf("build doh request failed: %s", err)
+	}
+	reqInstance.URL.RawQuery = val.Encode()
+	reqInstance.Header.Set("Accept", "application/dns-json")
+	rspInstance, err := defaultDoHHTTPClient.Do(reqInstance)
+	if err != nil {
+		return nil, utils.Errorf("doh request failed: %s", err)
+	}
+	defer rspInstance.Body.Close()
+	body, _ := io.ReadAll(rspInstance.Body)
+	var rspObj DoHDNSResponse
+	if err := json.Unmarshal(body, &rspObj); err != nil {
+		return nil, utils.Errorf("unmarshal doh response failed: %s", err)
+	}
+
+	rsp := new(dns.Msg)
+	rsp.SetReply(msg)
+	rsp.Rcode = rspObj.Status
+	rsp.Truncated = rspObj.TC
+	rsp.RecursionAvailable = rspObj.RA
+	rsp.AuthenticatedData = rspObj.AD
+	rsp.CheckingDisabled = rspObj.CD
+	rsp.Answer = dohRecordsToRR(rspObj.Answer)
+	rsp.Ns = dohRecordsToRR(rspObj.Authority)
+	rsp.Extra = dohRecordsToRR(rspObj.Additional)
+	return rsp, nil
+}
+
+func dohRecordsToRR(records []*DoHDNSRecord) []dns.RR {
+	var rrs []dns.RR
+	for _, record := range records {
+		typeStr, ok := dns.TypeToString[uint16(record.Type)]
+		if !ok {
+			continue
+		}
+		rr, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", dns.Fqdn(record.Name), record.TTL, typeStr, record.Data))
+		if err != nil {
+			log.Debugf("parse doh record %v failed: %s", record.Data, err)
+			continue
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs
+}
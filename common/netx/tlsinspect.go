@@ -2,6 +2,7 @@ package netx
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"strings"
 	"time"
@@ -56,57 +57,11 @@ func TLSInspectTimeout(addr string, seconds float64) ([]*TLSInspectResult, error
 				if cert == nil {
 					continue
 				}
-				var domains []string
-
-				var urls []string
-				for _, u := range cert.URIs {
-					urls = append(urls, u.String())
-					host, _, _ := utils.ParseStringToHostPort(u.Hostname())
-					if host == "" {
-						host = u.Hostname()
-					}
-					if host == "" {
-						continue
-					}
-					domains = append(domains, host)
-				}
-
-				domains = append(domains, cert.ExcludedURIDomains...)
-				domains = append(domains, cert.PermittedURIDomains...)
-
-				var emails []string
-				domains = append(domains, cert.DNSNames...)
-				domains = append(domains, cert.PermittedDNSDomains...)
-				domains = append(domains, cert.ExcludedDNSDomains...)
-				emails = append(emails, cert.EmailAddresses...)
-				emails = append(emails, cert.PermittedEmailAddresses...)
-				emails = append(emails, cert.ExcludedEmailAddresses...)
-				emails = utils.RemoveRepeatStringSlice(emails)
-				var accounts []string
-				for _, e := range emails {
-					if strings.Contains(e, "@") {
-						r := strings.Split(e, "@")
-						domains = append(domains, r[1])
-						accounts = append(accounts, r[0])
-					} else {
-						accounts = append(accounts, e)
-					}
-				}
-				domains = utils.RemoveRepeatStringSlice(domains)
-				text, err := tlsutils.CertificateText(cert)
+				result, err := certificateToTLSInspectResult(cert)
 				if err != nil {
 					continue
 				}
-
-				result := TLSInspectResult{
-					Description:     text,
-					Raw:             cert.Raw,
-					RelativeDomains: domains,
-					RelativeEmail:   emails,
-					RelativeAccount: utils.RemoveRepeatStringSlice(accounts),
-					RelativeURIs:    utils.RemoveRepeatStringSlice(urls),
-				}
-				results = append(results, &result)
+				results = append(results, result)
 			}
 			return nil
 		},
@@ -122,6 +77,59 @@ func TLSInspectTimeout(addr string, seconds float64) ([]*TLSInspectResult, error
 	return results, nil
 }
 
+func certificateToTLSInspectResult(cert *x509.Certificate) (*TLSInspectResult, error) {
+	var domains []string
+
+	var urls []string
+	for _, u := range cert.URIs {
+		urls = append(urls, u.String())
+		host, _, _ := utils.ParseStringToHostPort(u.Hostname())
+		if host == "" {
+			host = u.Hostname()
+		}
+		if host == "" {
+			continue
+		}
+		domains = append(domains, host)
+	}
+
+	domains = append(domains, cert.ExcludedURIDomains...)
+	domains = append(domains, cert.PermittedURIDomains...)
+
+	var emails []string
+	domains = append(domains, cert.DNSNames...)
+	domains = append(domains, cert.PermittedDNSDomains...)
+	domains = append(domains, cert.ExcludedDNSDomains...)
+	emails = append(emails, cert.EmailAddresses...)
+	emails = append(emails, cert.PermittedEmailAddresses...)
+	emails = append(emails, cert.ExcludedEmailAddresses...)
+	emails = utils.RemoveRepeatStringSlice(emails)
+	var accounts []string
+	for _, e := range emails {
+		if strings.Contains(e, "@") {
+			r := strings.Split(e, "@")
+			domains = append(domains, r[1])
+			accounts = append(accounts, r[0])
+		} else {
+			accounts = append(accounts, e)
+		}
+	}
+	domains = utils.RemoveRepeatStringSlice(domains)
+	text, err := tlsutils.CertificateText(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TLSInspectResult{
+		Description:     text,
+		Raw:             cert.Raw,
+		RelativeDomains: domains,
+		RelativeEmail:   emails,
+		RelativeAccount: utils.RemoveRepeatStringSlice(accounts),
+		RelativeURIs:    utils.RemoveRepeatStringSlice(urls),
+	}, nil
+}
+
 // Inspect 检查目标地址的TLS证书，并返回其证书信息与错误
 // Example:
 // ```
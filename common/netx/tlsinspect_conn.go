@@ -0,0 +1,271 @@
+package netx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yaklang/yaklang/common/gmsm/gmtls"
+	gmx509 "github.com/yaklang/yaklang/common/gmsm/x509"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// TLSInspectConnectionResult 是一次 TLS 握手协商出来的参数以及服务端的证书链
+type TLSInspectConnectionResult struct {
+	Address    string
+	ServerName string
+
+	// Version / CipherSuite 是协商出来的协议版本与加密套件
+	Version            uint16
+	VersionName        string
+	CipherSuite        uint16
+	CipherSuiteName    string
+	NegotiatedProtocol string
+
+	// IsGMTLS 为 true 时表示使用国密 SSL 完成的握手
+	IsGMTLS bool
+
+	// Certificates 是服务端发送的证书链，第一个为叶子证书
+	Certificates []*x509.Certificate
+	// CertificateResults 是证书链的详细信息
+	CertificateResults []*TLSInspectResult
+	// Verified 表示证书链能否被系统根证书验证
+	Verified bool
+	// VerifyError 是证书链验证失败的原因
+	VerifyError string
+}
+
+type tlsInspectConfig struct {
+	sni          string
+	timeout      time.Duration
+	proxy        []string
+	gmtls        bool
+	minVersion   uint16
+	maxVersion   uint16
+	cipherSuites []uint16
+}
+
+type TLSInspectOption func(*tlsInspectConfig)
+
+func TLSInspect_WithSNI(sni string) TLSInspectOption {
+	return func(config *tlsInspectConfig) {
+		config.sni = sni
+	}
+}
+
+func TLSInspect_WithTimeout(timeout time.Duration) TLSInspectOption {
+	return func(config *tlsInspectConfig) {
+		config.timeout = timeout
+	}
+}
+
+func TLSInspect_WithProxy(proxy ...string) TLSInspectOption {
+	return func(config *tlsInspectConfig) {
+		config.proxy = proxy
+	}
+}
+
+// TLSInspect_WithGMTLS 使用国密 SSL 进行握手
+func TLSInspect_WithGMTLS(b bool) TLSInspectOption {
+	return func(config *tlsInspectConfig) {
+		config.gmtls = b
+	}
+}
+
+// TLSInspect_WithVersion 限制握手的协议版本范围，国密握手固定协商 GMSSL，不受该选项影响
+func TLSInspect_WithVersion(min, max uint16) TLSInspectOption {
+	return func(config *tlsInspectConfig) {
+		config.minVersion = min
+		config.maxVersion = max
+	}
+}
+
+func TLSInspect_WithCipherSuites(suites ...uint16) TLSInspectOption {
+	return func(config *tlsInspectConfig) {
+		config.cipherSuites = suites
+	}
+}
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionSSL30:   "ssl30", // nolint[:staticcheck]
+	tls.VersionTLS10:   "tls10",
+	tls.VersionTLS11:   "tls11",
+	tls.VersionTLS12:   "tls12",
+	tls.VersionTLS13:   "tls13",
+	gmtls.VersionGMSSL: "gmssl",
+}
+
+var gmCipherSuiteNames = map[uint16]string{
+	gmtls.GMTLS_ECDHE_SM4_CBC_SM3: "GMTLS_ECDHE_SM4_CBC_SM3",
+	gmtls.GMTLS_ECDHE_SM4_GCM_SM3: "GMTLS_ECDHE_SM4_GCM_SM3",
+	gmtls.GMTLS_ECC_SM4_CBC_SM3:   "GMTLS_ECC_SM4_CBC_SM3",
+	gmtls.GMTLS_ECC_SM4_GCM_SM3:   "GMTLS_ECC_SM4_GCM_SM3",
+}
+
+// TLSVersionToString 把 TLS 版本转换为 ssl30 / tls10 / tls11 / tls12 / tls13 / gmssl
+func TLSVersionToString(v uint16) string {
+	if name, ok := tlsVersionNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", v)
+}
+
+// ParseTLSVersion 解析 TLS 版本，支持 tls12 / tls1.2 / TLSv1.2 / gmssl 等写法
+func ParseTLSVersion(s string) (uint16, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	name = strings.NewReplacer("v", "", ".", "", "_", "", "-", "").Replace(name)
+	for v, n := range tlsVersionNames {
+		if n == name {
+			return v, nil
+		}
+	}
+	return 0, utils.Errorf("unknown tls version: %v", s)
+}
+
+// TLSCipherSuiteToString 返回加密套件的名字，包含国密加密套件
+func TLSCipherSuiteToString(id uint16) string {
+	if name, ok := gmCipherSuiteNames[id]; ok {
+		return name
+	}
+	return tls.CipherSuiteName(id)
+}
+
+// ParseTLSCipherSuite 根据名字解析加密套件，包含不安全的加密套件以及国密加密套件
+func ParseTLSCipherSuite(s string) (uint16, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	for id, n := range gmCipherSuiteNames {
+		if n == name {
+			return id, nil
+		}
+	}
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+	return 0, utils.Errorf("unknown tls cipher suite: %v", s)
+}
+
+// TLSInspectConnection 与目标进行 TLS 握手，返回协商出来的版本、加密套件与证书链
+// Example:
+// ```
+// result, err := TLSInspectConnection("yaklang.io:443", TLSInspect_WithVersion(tls.VersionTLS10, tls.VersionTLS11))
+// ```
+func TLSInspectConnection(addr string, opts ...TLSInspectOption) (*TLSInspectConnectionResult, error) {
+	config := &tlsInspectConfig{
+		timeout:    10 * time.Second,
+		minVersion: tls.VersionSSL30, // nolint[:staticcheck]
+		maxVersion: tls.VersionTLS13,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	host, port, _ := utils.ParseStringToHostPort(addr)
+	if port <= 0 {
+		port = 443
+	}
+	if host == "" {
+		host = addr
+	}
+	if config.sni == "" && !utils.IsIPv4(host) && !utils.IsIPv6(host) {
+		config.sni = host
+	}
+
+	target := utils.HostPort(host, port)
+	conn, err := DialTCPTimeout(config.timeout, target, config.proxy...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result := &TLSInspectConnectionResult{Address: target, ServerName: config.sni}
+	if config.gmtls {
+		gmConn := gmtls.Client(conn, &gmtls.Config{
+			GMSupport:          gmtls.NewGMSupport(),
+			ServerName:         config.sni,
+			InsecureSkipVerify: true,
+			CipherSuites:       config.cipherSuites,
+		})
+		err = gmConn.HandshakeContext(utils.TimeoutContext(config.timeout))
+		if err != nil {
+			return nil, utils.Errorf("gmtls handshake error: %s", err)
+		}
+		state := gmConn.ConnectionState()
+		result.IsGMTLS = true
+		result.Version = state.Version
+		result.CipherSuite = state.CipherSuite
+		result.NegotiatedProtocol = state.NegotiatedProtocol
+		result.verifyGMCertificates(state.PeerCertificates)
+		for _, cert := range state.PeerCertificates {
+			result.Certificates = append(result.Certificates, cert.ToX509Certificate())
+		}
+	} else {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName:         config.sni,
+			InsecureSkipVerify: true,
+			MinVersion:         config.minVersion,
+			MaxVersion:         config.maxVersion,
+			CipherSuites:       config.cipherSuites,
+		})
+		err = tlsConn.HandshakeContext(utils.TimeoutContext(config.timeout))
+		if err != nil {
+			return nil, utils.Errorf("tls handshake error: %s", err)
+		}
+		state := tlsConn.ConnectionState()
+		result.Version = state.Version
+		result.CipherSuite = state.CipherSuite
+		result.NegotiatedProtocol = state.NegotiatedProtocol
+		result.Certificates = state.PeerCertificates
+		result.verifyCertificates()
+	}
+	result.VersionName = TLSVersionToString(result.Version)
+	result.CipherSuiteName = TLSCipherSuiteToString(result.CipherSuite)
+	for _, cert := range result.Certificates {
+		if cert == nil {
+			continue
+		}
+		r, err := certificateToTLSInspectResult(cert)
+		if err != nil {
+			continue
+		}
+		result.CertificateResults = append(result.CertificateResults, r)
+	}
+	return result, nil
+}
+
+func (r *TLSInspectConnectionResult) verifyCertificates() {
+	if len(r.Certificates) <= 0 {
+		r.VerifyError = "no certificate"
+		return
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range r.Certificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := r.Certificates[0].Verify(x509.VerifyOptions{Intermediates: intermediates})
+	if err != nil {
+		r.VerifyError = err.Error()
+		return
+	}
+	r.Verified = true
+}
+
+func (r *TLSInspectConnectionResult) verifyGMCertificates(certs []*gmx509.Certificate) {
+	if len(certs) <= 0 {
+		r.VerifyError = "no certificate"
+		return
+	}
+	intermediates := gmx509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(gmx509.VerifyOptions{Intermediates: intermediates})
+	if err != nil {
+		r.VerifyError = err.Error()
+		return
+	}
+	r.Verified = true
+}
@@ -0,0 +1,51 @@
+package netx
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	for name, want := range map[string]uint16{
+		"tls10":   tls.VersionTLS10,
+		"TLSv1.2": tls.VersionTLS12,
+		"tls1.3":  tls.VersionTLS13,
+		"gmssl":   0x0101,
+	} {
+		v, err := ParseTLSVersion(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, want, v, name)
+		assert.NotEmpty(t, TLSVersionToString(v))
+	}
+	_, err := ParseTLSVersion("tls99")
+	assert.NotNil(t, err)
+}
+
+func TestTLSInspectConnection(t *testing.T) {
+	host, port := utils.DebugMockHTTPS([]byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"))
+	result, err := TLSInspectConnection(utils.HostPort(host, port), TLSInspect_WithVersion(tls.VersionTLS10, tls.VersionTLS12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "tls12", result.VersionName)
+	assert.NotEmpty(t, result.CipherSuiteName)
+	assert.NotEmpty(t, result.Certificates)
+	assert.Len(t, result.CertificateResults, len(result.Certificates))
+	assert.False(t, result.IsGMTLS)
+}
+
+func TestTLSInspectConnection_GMTLS(t *testing.T) {
+	host, port := utils.DebugMockOnlyGMHTTP(context.Background(), nil)
+	result, err := TLSInspectConnection(utils.HostPort(host, port), TLSInspect_WithGMTLS(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, result.IsGMTLS)
+	assert.Equal(t, "gmssl", result.VersionName)
+	assert.Contains(t, result.CipherSuiteName, "SM4")
+	assert.NotEmpty(t, result.Certificates)
+}
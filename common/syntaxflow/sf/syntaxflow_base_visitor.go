@@ -75,6 +75,22 @@ func (v *BaseSyntaxFlowVisitor) VisitOptionalRootFilter(ctx *OptionalRootFilterC
 	return v.VisitChildren(ctx)
 }
 
+func (v *BaseSyntaxFlowVisitor) VisitFunctionCallFilter(ctx *FunctionCallFilterContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *BaseSyntaxFlowVisitor) VisitFunctionCallArgFilter(ctx *FunctionCallArgFilterContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *BaseSyntaxFlowVisitor) VisitFunctionParamFilter(ctx *FunctionParamFilterContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
+func (v *BaseSyntaxFlowVisitor) VisitFunctionReturnFilter(ctx *FunctionReturnFilterContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *BaseSyntaxFlowVisitor) VisitFlat(ctx *FlatContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -56,7 +56,7 @@ func syntaxflowParserInit() {
 	}
 	staticData.predictionContextCache = antlr.NewPredictionContextCache()
 	staticData.serializedATN = []int32{
-		4, 1, 47, 197, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4, 7,
+		4, 1, 47, 214, 2, 0, 7, 0, 2, 1, 7, 1, 2, 2, 7, 2, 2, 3, 7, 3, 2, 4, 7,
 		4, 2, 5, 7, 5, 2, 6, 7, 6, 2, 7, 7, 7, 2, 8, 7, 8, 2, 9, 7, 9, 2, 10, 7,
 		10, 2, 11, 7, 11, 2, 12, 7, 12, 2, 13, 7, 13, 2, 14, 7, 14, 2, 15, 7, 15,
 		1, 0, 1, 0, 1, 0, 1, 1, 4, 1, 37, 8, 1, 11, 1, 12, 1, 38, 1, 2, 3, 2, 42,
@@ -64,85 +64,93 @@ func syntaxflowParserInit() {
 		3, 1, 4, 1, 4, 1, 4, 1, 4, 1, 4, 1, 4, 3, 4, 60, 8, 4, 1, 5, 1, 5, 1, 5,
 		1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5,
 		1, 5, 1, 5, 1, 5, 3, 5, 80, 8, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1,
-		5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 5, 5, 94, 8, 5, 10, 5, 12, 5, 97, 9, 5,
-		1, 6, 1, 6, 1, 6, 1, 6, 5, 6, 103, 8, 6, 10, 6, 12, 6, 106, 9, 6, 1, 6,
-		3, 6, 109, 8, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6,
-		1, 6, 1, 6, 1, 6, 5, 6, 123, 8, 6, 10, 6, 12, 6, 126, 9, 6, 3, 6, 128,
-		8, 6, 1, 6, 3, 6, 131, 8, 6, 1, 6, 3, 6, 134, 8, 6, 1, 7, 1, 7, 1, 7, 1,
-		7, 1, 7, 1, 7, 1, 7, 3, 7, 143, 8, 7, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1,
-		8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 3, 8, 159, 8, 8, 1,
-		8, 1, 8, 1, 8, 3, 8, 164, 8, 8, 3, 8, 166, 8, 8, 1, 8, 1, 8, 1, 8, 1, 8,
-		1, 8, 1, 8, 5, 8, 174, 8, 8, 10, 8, 12, 8, 177, 9, 8, 1, 9, 1, 9, 1, 10,
-		1, 10, 1, 11, 1, 11, 1, 12, 1, 12, 1, 12, 1, 12, 1, 13, 1, 13, 3, 13, 191,
-		8, 13, 1, 14, 1, 14, 1, 15, 1, 15, 1, 15, 0, 2, 10, 16, 16, 0, 2, 4, 6,
-		8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 0, 5, 1, 0, 8, 9, 4, 0,
-		6, 7, 11, 11, 16, 17, 19, 20, 1, 0, 12, 13, 1, 0, 35, 38, 1, 0, 40, 44,
-		217, 0, 32, 1, 0, 0, 0, 2, 36, 1, 0, 0, 0, 4, 41, 1, 0, 0, 0, 6, 51, 1,
-		0, 0, 0, 8, 53, 1, 0, 0, 0, 10, 79, 1, 0, 0, 0, 12, 133, 1, 0, 0, 0, 14,
-		142, 1, 0, 0, 0, 16, 165, 1, 0, 0, 0, 18, 178, 1, 0, 0, 0, 20, 180, 1,
-		0, 0, 0, 22, 182, 1, 0, 0, 0, 24, 184, 1, 0, 0, 0, 26, 190, 1, 0, 0, 0,
-		28, 192, 1, 0, 0, 0, 30, 194, 1, 0, 0, 0, 32, 33, 3, 2, 1, 0, 33, 34, 5,
-		0, 0, 1, 34, 1, 1, 0, 0, 0, 35, 37, 3, 4, 2, 0, 36, 35, 1, 0, 0, 0, 37,
-		38, 1, 0, 0, 0, 38, 36, 1, 0, 0, 0, 38, 39, 1, 0, 0, 0, 39, 3, 1, 0, 0,
-		0, 40, 42, 3, 6, 3, 0, 41, 40, 1, 0, 0, 0, 41, 42, 1, 0, 0, 0, 42, 44,
-		1, 0, 0, 0, 43, 45, 7, 0, 0, 0, 44, 43, 1, 0, 0, 0, 44, 45, 1, 0, 0, 0,
-		45, 46, 1, 0, 0, 0, 46, 49, 3, 10, 5, 0, 47, 48, 5, 10, 0, 0, 48, 50, 3,
-		8, 4, 0, 49, 47, 1, 0, 0, 0, 49, 50, 1, 0, 0, 0, 50, 5, 1, 0, 0, 0, 51,
-		52, 3, 8, 4, 0, 52, 7, 1, 0, 0, 0, 53, 59, 5, 30, 0, 0, 54, 60, 3, 26,
-		13, 0, 55, 56, 5, 22, 0, 0, 56, 57, 3, 26, 13, 0, 57, 58, 5, 24, 0, 0,
-		58, 60, 1, 0, 0, 0, 59, 54, 1, 0, 0, 0, 59, 55, 1, 0, 0, 0, 60, 9, 1, 0,
-		0, 0, 61, 62, 6, 5, -1, 0, 62, 80, 5, 30, 0, 0, 63, 80, 3, 26, 13, 0, 64,
-		80, 3, 18, 9, 0, 65, 66, 7, 0, 0, 0, 66, 80, 3, 10, 5, 9, 67, 68, 5, 22,
-		0, 0, 68, 69, 3, 10, 5, 0, 69, 70, 5, 24, 0, 0, 70, 80, 1, 0, 0, 0, 71,
-		72, 5, 18, 0, 0, 72, 80, 3, 14, 7, 0, 73, 74, 5, 25, 0, 0, 74, 75, 3, 18,
-		9, 0, 75, 76, 5, 26, 0, 0, 76, 80, 1, 0, 0, 0, 77, 78, 5, 21, 0, 0, 78,
-		80, 3, 16, 8, 0, 79, 61, 1, 0, 0, 0, 79, 63, 1, 0, 0, 0, 79, 64, 1, 0,
-		0, 0, 79, 65, 1, 0, 0, 0, 79, 67, 1, 0, 0, 0, 79, 71, 1, 0, 0, 0, 79, 73,
-		1, 0, 0, 0, 79, 77, 1, 0, 0, 0, 80, 95, 1, 0, 0, 0, 81, 82, 10, 4, 0, 0,
-		82, 83, 5, 21, 0, 0, 83, 94, 3, 16, 8, 0, 84, 85, 10, 3, 0, 0, 85, 86,
-		5, 10, 0, 0, 86, 94, 3, 12, 6, 0, 87, 88, 10, 2, 0, 0, 88, 89, 5, 2, 0,
-		0, 89, 94, 3, 12, 6, 0, 90, 91, 10, 1, 0, 0, 91, 92, 5, 18, 0, 0, 92, 94,
-		3, 14, 7, 0, 93, 81, 1, 0, 0, 0, 93, 84, 1, 0, 0, 0, 93, 87, 1, 0, 0, 0,
-		93, 90, 1, 0, 0, 0, 94, 97, 1, 0, 0, 0, 95, 93, 1, 0, 0, 0, 95, 96, 1,
-		0, 0, 0, 96, 11, 1, 0, 0, 0, 97, 95, 1, 0, 0, 0, 98, 108, 5, 25, 0, 0,
-		99, 104, 3, 2, 1, 0, 100, 101, 5, 23, 0, 0, 101, 103, 3, 2, 1, 0, 102,
-		100, 1, 0, 0, 0, 103, 106, 1, 0, 0, 0, 104, 102, 1, 0, 0, 0, 104, 105,
-		1, 0, 0, 0, 105, 109, 1, 0, 0, 0, 106, 104, 1, 0, 0, 0, 107, 109, 5, 3,
-		0, 0, 108, 99, 1, 0, 0, 0, 108, 107, 1, 0, 0, 0, 109, 110, 1, 0, 0, 0,
-		110, 134, 5, 26, 0, 0, 111, 127, 5, 27, 0, 0, 112, 113, 3, 26, 13, 0, 113,
-		114, 5, 31, 0, 0, 114, 115, 1, 0, 0, 0, 115, 124, 3, 2, 1, 0, 116, 117,
-		5, 1, 0, 0, 117, 118, 3, 26, 13, 0, 118, 119, 5, 31, 0, 0, 119, 120, 1,
-		0, 0, 0, 120, 121, 3, 2, 1, 0, 121, 123, 1, 0, 0, 0, 122, 116, 1, 0, 0,
-		0, 123, 126, 1, 0, 0, 0, 124, 122, 1, 0, 0, 0, 124, 125, 1, 0, 0, 0, 125,
-		128, 1, 0, 0, 0, 126, 124, 1, 0, 0, 0, 127, 112, 1, 0, 0, 0, 127, 128,
-		1, 0, 0, 0, 128, 130, 1, 0, 0, 0, 129, 131, 5, 1, 0, 0, 130, 129, 1, 0,
-		0, 0, 130, 131, 1, 0, 0, 0, 131, 132, 1, 0, 0, 0, 132, 134, 5, 28, 0, 0,
-		133, 98, 1, 0, 0, 0, 133, 111, 1, 0, 0, 0, 134, 13, 1, 0, 0, 0, 135, 143,
-		3, 26, 13, 0, 136, 143, 3, 18, 9, 0, 137, 143, 3, 24, 12, 0, 138, 139,
-		5, 22, 0, 0, 139, 140, 3, 16, 8, 0, 140, 141, 5, 24, 0, 0, 141, 143, 1,
-		0, 0, 0, 142, 135, 1, 0, 0, 0, 142, 136, 1, 0, 0, 0, 142, 137, 1, 0, 0,
-		0, 142, 138, 1, 0, 0, 0, 143, 15, 1, 0, 0, 0, 144, 145, 6, 8, -1, 0, 145,
-		166, 3, 18, 9, 0, 146, 166, 3, 20, 10, 0, 147, 166, 3, 22, 11, 0, 148,
-		149, 5, 22, 0, 0, 149, 150, 3, 16, 8, 0, 150, 151, 5, 24, 0, 0, 151, 166,
-		1, 0, 0, 0, 152, 153, 5, 33, 0, 0, 153, 166, 3, 16, 8, 5, 154, 158, 7,
-		1, 0, 0, 155, 159, 3, 18, 9, 0, 156, 159, 3, 26, 13, 0, 157, 159, 3, 30,
-		15, 0, 158, 155, 1, 0, 0, 0, 158, 156, 1, 0, 0, 0, 158, 157, 1, 0, 0, 0,
-		159, 166, 1, 0, 0, 0, 160, 163, 7, 2, 0, 0, 161, 164, 3, 20, 10, 0, 162,
-		164, 3, 22, 11, 0, 163, 161, 1, 0, 0, 0, 163, 162, 1, 0, 0, 0, 164, 166,
-		1, 0, 0, 0, 165, 144, 1, 0, 0, 0, 165, 146, 1, 0, 0, 0, 165, 147, 1, 0,
-		0, 0, 165, 148, 1, 0, 0, 0, 165, 152, 1, 0, 0, 0, 165, 154, 1, 0, 0, 0,
-		165, 160, 1, 0, 0, 0, 166, 175, 1, 0, 0, 0, 167, 168, 10, 2, 0, 0, 168,
-		169, 5, 14, 0, 0, 169, 174, 3, 16, 8, 3, 170, 171, 10, 1, 0, 0, 171, 172,
-		5, 15, 0, 0, 172, 174, 3, 16, 8, 2, 173, 167, 1, 0, 0, 0, 173, 170, 1,
-		0, 0, 0, 174, 177, 1, 0, 0, 0, 175, 173, 1, 0, 0, 0, 175, 176, 1, 0, 0,
-		0, 176, 17, 1, 0, 0, 0, 177, 175, 1, 0, 0, 0, 178, 179, 7, 3, 0, 0, 179,
-		19, 1, 0, 0, 0, 180, 181, 3, 26, 13, 0, 181, 21, 1, 0, 0, 0, 182, 183,
-		5, 47, 0, 0, 183, 23, 1, 0, 0, 0, 184, 185, 5, 22, 0, 0, 185, 186, 3, 28,
-		14, 0, 186, 187, 5, 24, 0, 0, 187, 25, 1, 0, 0, 0, 188, 191, 5, 46, 0,
-		0, 189, 191, 3, 28, 14, 0, 190, 188, 1, 0, 0, 0, 190, 189, 1, 0, 0, 0,
-		191, 27, 1, 0, 0, 0, 192, 193, 7, 4, 0, 0, 193, 29, 1, 0, 0, 0, 194, 195,
-		5, 45, 0, 0, 195, 31, 1, 0, 0, 0, 21, 38, 41, 44, 49, 59, 79, 93, 95, 104,
-		108, 124, 127, 130, 133, 142, 158, 163, 165, 173, 175, 190,
+		5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1,
+		5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 1, 5, 5, 5, 111,
+		8, 5, 10, 5, 12, 5, 114, 9, 5, 1, 6, 1, 6, 1, 6, 1, 6, 5, 6, 120, 8, 6,
+		10, 6, 12, 6, 123, 9, 6, 1, 6, 3, 6, 126, 8, 6, 1, 6, 1, 6, 1, 6, 1, 6,
+		1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 1, 6, 5, 6, 140, 8, 6, 10, 6,
+		12, 6, 143, 9, 6, 3, 6, 145, 8, 6, 1, 6, 3, 6, 148, 8, 6, 1, 6, 3, 6, 151,
+		8, 6, 1, 7, 1, 7, 1, 7, 1, 7, 1, 7, 1, 7, 1, 7, 3, 7, 160, 8, 7, 1, 8,
+		1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8,
+		1, 8, 3, 8, 176, 8, 8, 1, 8, 1, 8, 1, 8, 3, 8, 181, 8, 8, 3, 8, 183, 8,
+		8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 1, 8, 5, 8, 191, 8, 8, 10, 8, 12, 8, 194,
+		9, 8, 1, 9, 1, 9, 1, 10, 1, 10, 1, 11, 1, 11, 1, 12, 1, 12, 1, 12, 1, 12,
+		1, 13, 1, 13, 3, 13, 208, 8, 13, 1, 14, 1, 14, 1, 15, 1, 15, 1, 15, 0,
+		2, 10, 16, 16, 0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30,
+		0, 5, 1, 0, 8, 9, 4, 0, 6, 7, 11, 11, 16, 17, 19, 20, 1, 0, 12, 13, 1,
+		0, 35, 38, 1, 0, 40, 44, 238, 0, 32, 1, 0, 0, 0, 2, 36, 1, 0, 0, 0, 4,
+		41, 1, 0, 0, 0, 6, 51, 1, 0, 0, 0, 8, 53, 1, 0, 0, 0, 10, 79, 1, 0, 0,
+		0, 12, 150, 1, 0, 0, 0, 14, 159, 1, 0, 0, 0, 16, 182, 1, 0, 0, 0, 18, 195,
+		1, 0, 0, 0, 20, 197, 1, 0, 0, 0, 22, 199, 1, 0, 0, 0, 24, 201, 1, 0, 0,
+		0, 26, 207, 1, 0, 0, 0, 28, 209, 1, 0, 0, 0, 30, 211, 1, 0, 0, 0, 32, 33,
+		3, 2, 1, 0, 33, 34, 5, 0, 0, 1, 34, 1, 1, 0, 0, 0, 35, 37, 3, 4, 2, 0,
+		36, 35, 1, 0, 0, 0, 37, 38, 1, 0, 0, 0, 38, 36, 1, 0, 0, 0, 38, 39, 1,
+		0, 0, 0, 39, 3, 1, 0, 0, 0, 40, 42, 3, 6, 3, 0, 41, 40, 1, 0, 0, 0, 41,
+		42, 1, 0, 0, 0, 42, 44, 1, 0, 0, 0, 43, 45, 7, 0, 0, 0, 44, 43, 1, 0, 0,
+		0, 44, 45, 1, 0, 0, 0, 45, 46, 1, 0, 0, 0, 46, 49, 3, 10, 5, 0, 47, 48,
+		5, 10, 0, 0, 48, 50, 3, 8, 4, 0, 49, 47, 1, 0, 0, 0, 49, 50, 1, 0, 0, 0,
+		50, 5, 1, 0, 0, 0, 51, 52, 3, 8, 4, 0, 52, 7, 1, 0, 0, 0, 53, 59, 5, 30,
+		0, 0, 54, 60, 3, 26, 13, 0, 55, 56, 5, 22, 0, 0, 56, 57, 3, 26, 13, 0,
+		57, 58, 5, 24, 0, 0, 58, 60, 1, 0, 0, 0, 59, 54, 1, 0, 0, 0, 59, 55, 1,
+		0, 0, 0, 60, 9, 1, 0, 0, 0, 61, 62, 6, 5, -1, 0, 62, 80, 5, 30, 0, 0, 63,
+		80, 3, 26, 13, 0, 64, 80, 3, 18, 9, 0, 65, 66, 7, 0, 0, 0, 66, 80, 3, 10,
+		5, 13, 67, 68, 5, 22, 0, 0, 68, 69, 3, 10, 5, 0, 69, 70, 5, 24, 0, 0, 70,
+		80, 1, 0, 0, 0, 71, 72, 5, 18, 0, 0, 72, 80, 3, 14, 7, 0, 73, 74, 5, 25,
+		0, 0, 74, 75, 3, 18, 9, 0, 75, 76, 5, 26, 0, 0, 76, 80, 1, 0, 0, 0, 77,
+		78, 5, 21, 0, 0, 78, 80, 3, 16, 8, 0, 79, 61, 1, 0, 0, 0, 79, 63, 1, 0,
+		0, 0, 79, 64, 1, 0, 0, 0, 79, 65, 1, 0, 0, 0, 79, 67, 1, 0, 0, 0, 79, 71,
+		1, 0, 0, 0, 79, 73, 1, 0, 0, 0, 79, 77, 1, 0, 0, 0, 80, 112, 1, 0, 0, 0,
+		81, 82, 10, 8, 0, 0, 82, 83, 5, 21, 0, 0, 83, 111, 3, 16, 8, 0, 84, 85,
+		10, 7, 0, 0, 85, 86, 5, 10, 0, 0, 86, 111, 3, 12, 6, 0, 87, 88, 10, 6,
+		0, 0, 88, 89, 5, 2, 0, 0, 89, 111, 3, 12, 6, 0, 90, 91, 10, 5, 0, 0, 91,
+		92, 5, 18, 0, 0, 92, 111, 3, 14, 7, 0, 93, 94, 10, 4, 0, 0, 94, 95, 5,
+		22, 0, 0, 95, 96, 5, 24, 0, 0, 96, 111, 1, 0, 0, 0, 97, 98, 10, 3, 0, 0,
+		98, 99, 5, 22, 0, 0, 99, 100, 5, 29, 0, 0, 100, 101, 3, 18, 9, 0, 101,
+		102, 5, 24, 0, 0, 102, 111, 1, 0, 0, 0, 103, 104, 10, 2, 0, 0, 104, 105,
+		5, 29, 0, 0, 105, 111, 3, 18, 9, 0, 106, 107, 10, 1, 0, 0, 107, 108, 5,
+		29, 0, 0, 108, 109, 5, 17, 0, 0, 109, 111, 1, 0, 0, 0, 110, 81, 1, 0, 0,
+		0, 110, 84, 1, 0, 0, 0, 110, 87, 1, 0, 0, 0, 110, 90, 1, 0, 0, 0, 110,
+		93, 1, 0, 0, 0, 110, 97, 1, 0, 0, 0, 110, 103, 1, 0, 0, 0, 110, 106, 1,
+		0, 0, 0, 111, 114, 1, 0, 0, 0, 112, 110, 1, 0, 0, 0, 112, 113, 1, 0, 0,
+		0, 113, 11, 1, 0, 0, 0, 114, 112, 1, 0, 0, 0, 115, 125, 5, 25, 0, 0, 116,
+		121, 3, 2, 1, 0, 117, 118, 5, 23, 0, 0, 118, 120, 3, 2, 1, 0, 119, 117,
+		1, 0, 0, 0, 120, 123, 1, 0, 0, 0, 121, 119, 1, 0, 0, 0, 121, 122, 1, 0,
+		0, 0, 122, 126, 1, 0, 0, 0, 123, 121, 1, 0, 0, 0, 124, 126, 5, 3, 0, 0,
+		125, 116, 1, 0, 0, 0, 125, 124, 1, 0, 0, 0, 126, 127, 1, 0, 0, 0, 127,
+		151, 5, 26, 0, 0, 128, 144, 5, 27, 0, 0, 129, 130, 3, 26, 13, 0, 130, 131,
+		5, 31, 0, 0, 131, 132, 1, 0, 0, 0, 132, 141, 3, 2, 1, 0, 133, 134, 5, 1,
+		0, 0, 134, 135, 3, 26, 13, 0, 135, 136, 5, 31, 0, 0, 136, 137, 1, 0, 0,
+		0, 137, 138, 3, 2, 1, 0, 138, 140, 1, 0, 0, 0, 139, 133, 1, 0, 0, 0, 140,
+		143, 1, 0, 0, 0, 141, 139, 1, 0, 0, 0, 141, 142, 1, 0, 0, 0, 142, 145,
+		1, 0, 0, 0, 143, 141, 1, 0, 0, 0, 144, 129, 1, 0, 0, 0, 144, 145, 1, 0,
+		0, 0, 145, 147, 1, 0, 0, 0, 146, 148, 5, 1, 0, 0, 147, 146, 1, 0, 0, 0,
+		147, 148, 1, 0, 0, 0, 148, 149, 1, 0, 0, 0, 149, 151, 5, 28, 0, 0, 150,
+		115, 1, 0, 0, 0, 150, 128, 1, 0, 0, 0, 151, 13, 1, 0, 0, 0, 152, 160, 3,
+		26, 13, 0, 153, 160, 3, 18, 9, 0, 154, 160, 3, 24, 12, 0, 155, 156, 5,
+		22, 0, 0, 156, 157, 3, 16, 8, 0, 157, 158, 5, 24, 0, 0, 158, 160, 1, 0,
+		0, 0, 159, 152, 1, 0, 0, 0, 159, 153, 1, 0, 0, 0, 159, 154, 1, 0, 0, 0,
+		159, 155, 1, 0, 0, 0, 160, 15, 1, 0, 0, 0, 161, 162, 6, 8, -1, 0, 162,
+		183, 3, 18, 9, 0, 163, 183, 3, 20, 10, 0, 164, 183, 3, 22, 11, 0, 165,
+		166, 5, 22, 0, 0, 166, 167, 3, 16, 8, 0, 167, 168, 5, 24, 0, 0, 168, 183,
+		1, 0, 0, 0, 169, 170, 5, 33, 0, 0, 170, 183, 3, 16, 8, 5, 171, 175, 7,
+		1, 0, 0, 172, 176, 3, 18, 9, 0, 173, 176, 3, 26, 13, 0, 174, 176, 3, 30,
+		15, 0, 175, 172, 1, 0, 0, 0, 175, 173, 1, 0, 0, 0, 175, 174, 1, 0, 0, 0,
+		176, 183, 1, 0, 0, 0, 177, 180, 7, 2, 0, 0, 178, 181, 3, 20, 10, 0, 179,
+		181, 3, 22, 11, 0, 180, 178, 1, 0, 0, 0, 180, 179, 1, 0, 0, 0, 181, 183,
+		1, 0, 0, 0, 182, 161, 1, 0, 0, 0, 182, 163, 1, 0, 0, 0, 182, 164, 1, 0,
+		0, 0, 182, 165, 1, 0, 0, 0, 182, 169, 1, 0, 0, 0, 182, 171, 1, 0, 0, 0,
+		182, 177, 1, 0, 0, 0, 183, 192, 1, 0, 0, 0, 184, 185, 10, 2, 0, 0, 185,
+		186, 5, 14, 0, 0, 186, 191, 3, 16, 8, 3, 187, 188, 10, 1, 0, 0, 188, 189,
+		5, 15, 0, 0, 189, 191, 3, 16, 8, 2, 190, 184, 1, 0, 0, 0, 190, 187, 1,
+		0, 0, 0, 191, 194, 1, 0, 0, 0, 192, 190, 1, 0, 0, 0, 192, 193, 1, 0, 0,
+		0, 193, 17, 1, 0, 0, 0, 194, 192, 1, 0, 0, 0, 195, 196, 7, 3, 0, 0, 196,
+		19, 1, 0, 0, 0, 197, 198, 3, 26, 13, 0, 198, 21, 1, 0, 0, 0, 199, 200,
+		5, 47, 0, 0, 200, 23, 1, 0, 0, 0, 201, 202, 5, 22, 0, 0, 202, 203, 3, 28,
+		14, 0, 203, 204, 5, 24, 0, 0, 204, 25, 1, 0, 0, 0, 205, 208, 5, 46, 0,
+		0, 206, 208, 3, 28, 14, 0, 207, 205, 1, 0, 0, 0, 207, 206, 1, 0, 0, 0,
+		208, 27, 1, 0, 0, 0, 209, 210, 7, 4, 0, 0, 210, 29, 1, 0, 0, 0, 211, 212,
+		5, 45, 0, 0, 212, 31, 1, 0, 0, 0, 21, 38, 41, 44, 49, 59, 79, 110, 112,
+		121, 125, 141, 144, 147, 150, 159, 175, 180, 182, 190, 192, 207,
 	}
 	deserializer := antlr.NewATNDeserializer(nil)
 	staticData.atn = deserializer.Deserialize(staticData.serializedATN)
@@ -1641,6 +1649,246 @@ func (s *OptionalRootFilterContext) Accept(visitor antlr.ParseTreeVisitor) inter
 	}
 }
 
+type FunctionCallFilterContext struct {
+	*FilterExprContext
+}
+
+func NewFunctionCallFilterContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *FunctionCallFilterContext {
+	var p = new(FunctionCallFilterContext)
+
+	p.FilterExprContext = NewEmptyFilterExprContext()
+	p.parser = parser
+	p.CopyFrom(ctx.(*FilterExprContext))
+
+	return p
+}
+
+func (s *FunctionCallFilterContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *FunctionCallFilterContext) FilterExpr() IFilterExprContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IFilterExprContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IFilterExprContext)
+}
+
+func (s *FunctionCallFilterContext) OpenParen() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserOpenParen, 0)
+}
+
+func (s *FunctionCallFilterContext) CloseParen() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserCloseParen, 0)
+}
+
+func (s *FunctionCallFilterContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case SyntaxFlowVisitor:
+		return t.VisitFunctionCallFilter(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+type FunctionCallArgFilterContext struct {
+	*FilterExprContext
+}
+
+func NewFunctionCallArgFilterContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *FunctionCallArgFilterContext {
+	var p = new(FunctionCallArgFilterContext)
+
+	p.FilterExprContext = NewEmptyFilterExprContext()
+	p.parser = parser
+	p.CopyFrom(ctx.(*FilterExprContext))
+
+	return p
+}
+
+func (s *FunctionCallArgFilterContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *FunctionCallArgFilterContext) FilterExpr() IFilterExprContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IFilterExprContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IFilterExprContext)
+}
+
+func (s *FunctionCallArgFilterContext) OpenParen() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserOpenParen, 0)
+}
+
+func (s *FunctionCallArgFilterContext) ListStart() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserListStart, 0)
+}
+
+func (s *FunctionCallArgFilterContext) NumberLiteral() INumberLiteralContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(INumberLiteralContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(INumberLiteralContext)
+}
+
+func (s *FunctionCallArgFilterContext) CloseParen() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserCloseParen, 0)
+}
+
+func (s *FunctionCallArgFilterContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case SyntaxFlowVisitor:
+		return t.VisitFunctionCallArgFilter(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+type FunctionParamFilterContext struct {
+	*FilterExprContext
+}
+
+func NewFunctionParamFilterContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *FunctionParamFilterContext {
+	var p = new(FunctionParamFilterContext)
+
+	p.FilterExprContext = NewEmptyFilterExprContext()
+	p.parser = parser
+	p.CopyFrom(ctx.(*FilterExprContext))
+
+	return p
+}
+
+func (s *FunctionParamFilterContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *FunctionParamFilterContext) FilterExpr() IFilterExprContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IFilterExprContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IFilterExprContext)
+}
+
+func (s *FunctionParamFilterContext) ListStart() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserListStart, 0)
+}
+
+func (s *FunctionParamFilterContext) NumberLiteral() INumberLiteralContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(INumberLiteralContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(INumberLiteralContext)
+}
+
+func (s *FunctionParamFilterContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case SyntaxFlowVisitor:
+		return t.VisitFunctionParamFilter(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
+type FunctionReturnFilterContext struct {
+	*FilterExprContext
+}
+
+func NewFunctionReturnFilterContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *FunctionReturnFilterContext {
+	var p = new(FunctionReturnFilterContext)
+
+	p.FilterExprContext = NewEmptyFilterExprContext()
+	p.parser = parser
+	p.CopyFrom(ctx.(*FilterExprContext))
+
+	return p
+}
+
+func (s *FunctionReturnFilterContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *FunctionReturnFilterContext) FilterExpr() IFilterExprContext {
+	var t antlr.RuleContext
+	for _, ctx := range s.GetChildren() {
+		if _, ok := ctx.(IFilterExprContext); ok {
+			t = ctx.(antlr.RuleContext)
+			break
+		}
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IFilterExprContext)
+}
+
+func (s *FunctionReturnFilterContext) ListStart() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserListStart, 0)
+}
+
+func (s *FunctionReturnFilterContext) Gt() antlr.TerminalNode {
+	return s.GetToken(SyntaxFlowParserGt, 0)
+}
+
+func (s *FunctionReturnFilterContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case SyntaxFlowVisitor:
+		return t.VisitFunctionReturnFilter(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
 func (p *SyntaxFlowParser) FilterExpr() (localctx IFilterExprContext) {
 	return p.filterExpr(0)
 }
@@ -1733,7 +1981,7 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 		}
 		{
 			p.SetState(66)
-			p.filterExpr(9)
+			p.filterExpr(13)
 		}
 
 	case SyntaxFlowParserOpenParen:
@@ -1800,7 +2048,7 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 		panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
 	}
 	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
-	p.SetState(95)
+	p.SetState(112)
 	p.GetErrorHandler().Sync(p)
 	_alt = p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 7, p.GetParserRuleContext())
 
@@ -1810,7 +2058,7 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 				p.TriggerExitRuleEvent()
 			}
 			_prevctx = localctx
-			p.SetState(93)
+			p.SetState(110)
 			p.GetErrorHandler().Sync(p)
 			switch p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 6, p.GetParserRuleContext()) {
 			case 1:
@@ -1818,8 +2066,8 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
 				p.SetState(81)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 8)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 8)", ""))
 				}
 				{
 					p.SetState(82)
@@ -1835,8 +2083,8 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
 				p.SetState(84)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 7)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 7)", ""))
 				}
 				{
 					p.SetState(85)
@@ -1852,8 +2100,8 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
 				p.SetState(87)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 6)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 6)", ""))
 				}
 				{
 					p.SetState(88)
@@ -1869,8 +2117,8 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
 				p.SetState(90)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 1)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 1)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 5)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 5)", ""))
 				}
 				{
 					p.SetState(91)
@@ -1881,10 +2129,86 @@ func (p *SyntaxFlowParser) filterExpr(_p int) (localctx IFilterExprContext) {
 					p.FilterFieldMember()
 				}
 
+			case 5:
+				localctx = NewFunctionCallFilterContext(p, NewFilterExprContext(p, _parentctx, _parentState))
+				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
+				p.SetState(93)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
+				}
+				{
+					p.SetState(94)
+					p.Match(SyntaxFlowParserOpenParen)
+				}
+				{
+					p.SetState(95)
+					p.Match(SyntaxFlowParserCloseParen)
+				}
+
+			case 6:
+				localctx = NewFunctionCallArgFilterContext(p, NewFilterExprContext(p, _parentctx, _parentState))
+				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
+				p.SetState(97)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
+				}
+				{
+					p.SetState(98)
+					p.Match(SyntaxFlowParserOpenParen)
+				}
+				{
+					p.SetState(99)
+					p.Match(SyntaxFlowParserListStart)
+				}
+				{
+					p.SetState(100)
+					p.NumberLiteral()
+				}
+				{
+					p.SetState(101)
+					p.Match(SyntaxFlowParserCloseParen)
+				}
+
+			case 7:
+				localctx = NewFunctionParamFilterContext(p, NewFilterExprContext(p, _parentctx, _parentState))
+				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
+				p.SetState(103)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
+				}
+				{
+					p.SetState(104)
+					p.Match(SyntaxFlowParserListStart)
+				}
+				{
+					p.SetState(105)
+					p.NumberLiteral()
+				}
+
+			case 8:
+				localctx = NewFunctionReturnFilterContext(p, NewFilterExprContext(p, _parentctx, _parentState))
+				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_filterExpr)
+				p.SetState(106)
+
+				if !(p.Precpred(p.GetParserRuleContext(), 1)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 1)", ""))
+				}
+				{
+					p.SetState(107)
+					p.Match(SyntaxFlowParserListStart)
+				}
+				{
+					p.SetState(108)
+					p.Match(SyntaxFlowParserGt)
+				}
+
 			}
 
 		}
-		p.SetState(97)
+		p.SetState(114)
 		p.GetErrorHandler().Sync(p)
 		_alt = p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 7, p.GetParserRuleContext())
 	}
@@ -2183,7 +2507,7 @@ func (p *SyntaxFlowParser) ChainFilter() (localctx IChainFilterContext) {
 
 	var _alt int
 
-	p.SetState(133)
+	p.SetState(150)
 	p.GetErrorHandler().Sync(p)
 
 	switch p.GetTokenStream().LA(1) {
@@ -2191,40 +2515,40 @@ func (p *SyntaxFlowParser) ChainFilter() (localctx IChainFilterContext) {
 		localctx = NewFlatContext(p, localctx)
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(98)
+			p.SetState(115)
 			p.Match(SyntaxFlowParserListSelectOpen)
 		}
-		p.SetState(108)
+		p.SetState(125)
 		p.GetErrorHandler().Sync(p)
 
 		switch p.GetTokenStream().LA(1) {
 		case SyntaxFlowParserDoubleLt, SyntaxFlowParserDoubleGt, SyntaxFlowParserDot, SyntaxFlowParserQuestion, SyntaxFlowParserOpenParen, SyntaxFlowParserListSelectOpen, SyntaxFlowParserDollarOutput, SyntaxFlowParserNumber, SyntaxFlowParserOctalNumber, SyntaxFlowParserBinaryNumber, SyntaxFlowParserHexNumber, SyntaxFlowParserStringType, SyntaxFlowParserListType, SyntaxFlowParserDictType, SyntaxFlowParserNumberType, SyntaxFlowParserBoolType, SyntaxFlowParserIdentifier:
 			{
-				p.SetState(99)
+				p.SetState(116)
 				p.Filters()
 			}
-			p.SetState(104)
+			p.SetState(121)
 			p.GetErrorHandler().Sync(p)
 			_la = p.GetTokenStream().LA(1)
 
 			for _la == SyntaxFlowParserComma {
 				{
-					p.SetState(100)
+					p.SetState(117)
 					p.Match(SyntaxFlowParserComma)
 				}
 				{
-					p.SetState(101)
+					p.SetState(118)
 					p.Filters()
 				}
 
-				p.SetState(106)
+				p.SetState(123)
 				p.GetErrorHandler().Sync(p)
 				_la = p.GetTokenStream().LA(1)
 			}
 
 		case SyntaxFlowParserDeep:
 			{
-				p.SetState(107)
+				p.SetState(124)
 				p.Match(SyntaxFlowParserDeep)
 			}
 
@@ -2232,7 +2556,7 @@ func (p *SyntaxFlowParser) ChainFilter() (localctx IChainFilterContext) {
 			panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
 		}
 		{
-			p.SetState(110)
+			p.SetState(127)
 			p.Match(SyntaxFlowParserListSelectClose)
 		}
 
@@ -2240,72 +2564,72 @@ func (p *SyntaxFlowParser) ChainFilter() (localctx IChainFilterContext) {
 		localctx = NewBuildMapContext(p, localctx)
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(111)
+			p.SetState(128)
 			p.Match(SyntaxFlowParserMapBuilderOpen)
 		}
-		p.SetState(127)
+		p.SetState(144)
 		p.GetErrorHandler().Sync(p)
 		_la = p.GetTokenStream().LA(1)
 
 		if (int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&104453604638720) != 0 {
 			{
-				p.SetState(112)
+				p.SetState(129)
 				p.Identifier()
 			}
 			{
-				p.SetState(113)
+				p.SetState(130)
 				p.Match(SyntaxFlowParserColon)
 			}
 
 			{
-				p.SetState(115)
+				p.SetState(132)
 				p.Filters()
 			}
-			p.SetState(124)
+			p.SetState(141)
 			p.GetErrorHandler().Sync(p)
 			_alt = p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 10, p.GetParserRuleContext())
 
 			for _alt != 2 && _alt != antlr.ATNInvalidAltNumber {
 				if _alt == 1 {
 					{
-						p.SetState(116)
+						p.SetState(133)
 						p.Match(SyntaxFlowParserT__0)
 					}
 
 					{
-						p.SetState(117)
+						p.SetState(134)
 						p.Identifier()
 					}
 					{
-						p.SetState(118)
+						p.SetState(135)
 						p.Match(SyntaxFlowParserColon)
 					}
 
 					{
-						p.SetState(120)
+						p.SetState(137)
 						p.Filters()
 					}
 
 				}
-				p.SetState(126)
+				p.SetState(143)
 				p.GetErrorHandler().Sync(p)
 				_alt = p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 10, p.GetParserRuleContext())
 			}
 
 		}
-		p.SetState(130)
+		p.SetState(147)
 		p.GetErrorHandler().Sync(p)
 		_la = p.GetTokenStream().LA(1)
 
 		if _la == SyntaxFlowParserT__0 {
 			{
-				p.SetState(129)
+				p.SetState(146)
 				p.Match(SyntaxFlowParserT__0)
 			}
 
 		}
 		{
-			p.SetState(132)
+			p.SetState(149)
 			p.Match(SyntaxFlowParserMapBuilderClose)
 		}
 
@@ -2467,42 +2791,42 @@ func (p *SyntaxFlowParser) FilterFieldMember() (localctx IFilterFieldMemberConte
 		}
 	}()
 
-	p.SetState(142)
+	p.SetState(159)
 	p.GetErrorHandler().Sync(p)
 	switch p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 14, p.GetParserRuleContext()) {
 	case 1:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(135)
+			p.SetState(152)
 			p.Identifier()
 		}
 
 	case 2:
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(136)
+			p.SetState(153)
 			p.NumberLiteral()
 		}
 
 	case 3:
 		p.EnterOuterAlt(localctx, 3)
 		{
-			p.SetState(137)
+			p.SetState(154)
 			p.TypeCast()
 		}
 
 	case 4:
 		p.EnterOuterAlt(localctx, 4)
 		{
-			p.SetState(138)
+			p.SetState(155)
 			p.Match(SyntaxFlowParserOpenParen)
 		}
 		{
-			p.SetState(139)
+			p.SetState(156)
 			p.conditionExpression(0)
 		}
 		{
-			p.SetState(140)
+			p.SetState(157)
 			p.Match(SyntaxFlowParserCloseParen)
 		}
 
@@ -3157,7 +3481,7 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 	var _alt int
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(165)
+	p.SetState(182)
 	p.GetErrorHandler().Sync(p)
 
 	switch p.GetTokenStream().LA(1) {
@@ -3167,7 +3491,7 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		_prevctx = localctx
 
 		{
-			p.SetState(145)
+			p.SetState(162)
 			p.NumberLiteral()
 		}
 
@@ -3176,7 +3500,7 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
 		{
-			p.SetState(146)
+			p.SetState(163)
 			p.StringLiteral()
 		}
 
@@ -3185,7 +3509,7 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
 		{
-			p.SetState(147)
+			p.SetState(164)
 			p.RegexpLiteral()
 		}
 
@@ -3194,15 +3518,15 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
 		{
-			p.SetState(148)
+			p.SetState(165)
 			p.Match(SyntaxFlowParserOpenParen)
 		}
 		{
-			p.SetState(149)
+			p.SetState(166)
 			p.conditionExpression(0)
 		}
 		{
-			p.SetState(150)
+			p.SetState(167)
 			p.Match(SyntaxFlowParserCloseParen)
 		}
 
@@ -3211,11 +3535,11 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
 		{
-			p.SetState(152)
+			p.SetState(169)
 			p.Match(SyntaxFlowParserBang)
 		}
 		{
-			p.SetState(153)
+			p.SetState(170)
 			p.conditionExpression(5)
 		}
 
@@ -3224,7 +3548,7 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
 		{
-			p.SetState(154)
+			p.SetState(171)
 
 			var _lt = p.GetTokenStream().LT(1)
 
@@ -3241,25 +3565,25 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 				p.Consume()
 			}
 		}
-		p.SetState(158)
+		p.SetState(175)
 		p.GetErrorHandler().Sync(p)
 
 		switch p.GetTokenStream().LA(1) {
 		case SyntaxFlowParserNumber, SyntaxFlowParserOctalNumber, SyntaxFlowParserBinaryNumber, SyntaxFlowParserHexNumber:
 			{
-				p.SetState(155)
+				p.SetState(172)
 				p.NumberLiteral()
 			}
 
 		case SyntaxFlowParserStringType, SyntaxFlowParserListType, SyntaxFlowParserDictType, SyntaxFlowParserNumberType, SyntaxFlowParserBoolType, SyntaxFlowParserIdentifier:
 			{
-				p.SetState(156)
+				p.SetState(173)
 				p.Identifier()
 			}
 
 		case SyntaxFlowParserBoolLiteral:
 			{
-				p.SetState(157)
+				p.SetState(174)
 				p.BoolLiteral()
 			}
 
@@ -3272,7 +3596,7 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
 		{
-			p.SetState(160)
+			p.SetState(177)
 
 			var _lt = p.GetTokenStream().LT(1)
 
@@ -3289,19 +3613,19 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 				p.Consume()
 			}
 		}
-		p.SetState(163)
+		p.SetState(180)
 		p.GetErrorHandler().Sync(p)
 
 		switch p.GetTokenStream().LA(1) {
 		case SyntaxFlowParserStringType, SyntaxFlowParserListType, SyntaxFlowParserDictType, SyntaxFlowParserNumberType, SyntaxFlowParserBoolType, SyntaxFlowParserIdentifier:
 			{
-				p.SetState(161)
+				p.SetState(178)
 				p.StringLiteral()
 			}
 
 		case SyntaxFlowParserRegexpLiteral:
 			{
-				p.SetState(162)
+				p.SetState(179)
 				p.RegexpLiteral()
 			}
 
@@ -3313,7 +3637,7 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 		panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
 	}
 	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
-	p.SetState(175)
+	p.SetState(192)
 	p.GetErrorHandler().Sync(p)
 	_alt = p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 19, p.GetParserRuleContext())
 
@@ -3323,47 +3647,47 @@ func (p *SyntaxFlowParser) conditionExpression(_p int) (localctx IConditionExpre
 				p.TriggerExitRuleEvent()
 			}
 			_prevctx = localctx
-			p.SetState(173)
+			p.SetState(190)
 			p.GetErrorHandler().Sync(p)
 			switch p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 18, p.GetParserRuleContext()) {
 			case 1:
 				localctx = NewFilterExpressionAndContext(p, NewConditionExpressionContext(p, _parentctx, _parentState))
 				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_conditionExpression)
-				p.SetState(167)
+				p.SetState(184)
 
 				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
 					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
 				}
 				{
-					p.SetState(168)
+					p.SetState(185)
 					p.Match(SyntaxFlowParserAnd)
 				}
 				{
-					p.SetState(169)
+					p.SetState(186)
 					p.conditionExpression(3)
 				}
 
 			case 2:
 				localctx = NewFilterExpressionOrContext(p, NewConditionExpressionContext(p, _parentctx, _parentState))
 				p.PushNewRecursionContext(localctx, _startState, SyntaxFlowParserRULE_conditionExpression)
-				p.SetState(170)
+				p.SetState(187)
 
 				if !(p.Precpred(p.GetParserRuleContext(), 1)) {
 					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 1)", ""))
 				}
 				{
-					p.SetState(171)
+					p.SetState(188)
 					p.Match(SyntaxFlowParserOr)
 				}
 				{
-					p.SetState(172)
+					p.SetState(189)
 					p.conditionExpression(2)
 				}
 
 			}
 
 		}
-		p.SetState(177)
+		p.SetState(194)
 		p.GetErrorHandler().Sync(p)
 		_alt = p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 19, p.GetParserRuleContext())
 	}
@@ -3469,7 +3793,7 @@ func (p *SyntaxFlowParser) NumberLiteral() (localctx INumberLiteralContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(178)
+		p.SetState(195)
 		_la = p.GetTokenStream().LA(1)
 
 		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&515396075520) != 0) {
@@ -3580,7 +3904,7 @@ func (p *SyntaxFlowParser) StringLiteral() (localctx IStringLiteralContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(180)
+		p.SetState(197)
 		p.Identifier()
 	}
 
@@ -3672,7 +3996,7 @@ func (p *SyntaxFlowParser) RegexpLiteral() (localctx IRegexpLiteralContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(182)
+		p.SetState(199)
 		p.Match(SyntaxFlowParserRegexpLiteral)
 	}
 
@@ -3784,15 +4108,15 @@ func (p *SyntaxFlowParser) TypeCast() (localctx ITypeCastContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(184)
+		p.SetState(201)
 		p.Match(SyntaxFlowParserOpenParen)
 	}
 	{
-		p.SetState(185)
+		p.SetState(202)
 		p.Types()
 	}
 	{
-		p.SetState(186)
+		p.SetState(203)
 		p.Match(SyntaxFlowParserCloseParen)
 	}
 
@@ -3898,21 +4222,21 @@ func (p *SyntaxFlowParser) Identifier() (localctx IIdentifierContext) {
 		}
 	}()
 
-	p.SetState(190)
+	p.SetState(207)
 	p.GetErrorHandler().Sync(p)
 
 	switch p.GetTokenStream().LA(1) {
 	case SyntaxFlowParserIdentifier:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(188)
+			p.SetState(205)
 			p.Match(SyntaxFlowParserIdentifier)
 		}
 
 	case SyntaxFlowParserStringType, SyntaxFlowParserListType, SyntaxFlowParserDictType, SyntaxFlowParserNumberType, SyntaxFlowParserBoolType:
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(189)
+			p.SetState(206)
 			p.Types()
 		}
 
@@ -4025,7 +4349,7 @@ func (p *SyntaxFlowParser) Types() (localctx ITypesContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(192)
+		p.SetState(209)
 		_la = p.GetTokenStream().LA(1)
 
 		if !((int64(_la) & ^0x3f) == 0 && ((int64(1)<<_la)&34084860461056) != 0) {
@@ -4124,7 +4448,7 @@ func (p *SyntaxFlowParser) BoolLiteral() (localctx IBoolLiteralContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(194)
+		p.SetState(211)
 		p.Match(SyntaxFlowParserBoolLiteral)
 	}
 
@@ -4158,15 +4482,27 @@ func (p *SyntaxFlowParser) FilterExpr_Sempred(localctx antlr.RuleContext, predIn
 
 	switch predIndex {
 	case 0:
-		return p.Precpred(p.GetParserRuleContext(), 4)
+		return p.Precpred(p.GetParserRuleContext(), 8)
 
 	case 1:
-		return p.Precpred(p.GetParserRuleContext(), 3)
+		return p.Precpred(p.GetParserRuleContext(), 7)
 
 	case 2:
-		return p.Precpred(p.GetParserRuleContext(), 2)
+		return p.Precpred(p.GetParserRuleContext(), 6)
 
 	case 3:
+		return p.Precpred(p.GetParserRuleContext(), 5)
+
+	case 4:
+		return p.Precpred(p.GetParserRuleContext(), 4)
+
+	case 5:
+		return p.Precpred(p.GetParserRuleContext(), 3)
+
+	case 6:
+		return p.Precpred(p.GetParserRuleContext(), 2)
+
+	case 7:
 		return p.Precpred(p.GetParserRuleContext(), 1)
 
 	default:
@@ -4179,10 +4515,10 @@ func (p *SyntaxFlowParser) ConditionExpression_Sempred(localctx antlr.RuleContex
 	_ = this
 
 	switch predIndex {
-	case 4:
+	case 8:
 		return p.Precpred(p.GetParserRuleContext(), 2)
 
-	case 5:
+	case 9:
 		return p.Precpred(p.GetParserRuleContext(), 1)
 
 	default:
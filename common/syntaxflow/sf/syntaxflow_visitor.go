@@ -58,6 +58,18 @@ type SyntaxFlowVisitor interface {
 	// Visit a parse tree produced by SyntaxFlowParser#OptionalRootFilter.
 	VisitOptionalRootFilter(ctx *OptionalRootFilterContext) interface{}
 
+	// Visit a parse tree produced by SyntaxFlowParser#FunctionCallFilter.
+	VisitFunctionCallFilter(ctx *FunctionCallFilterContext) interface{}
+
+	// Visit a parse tree produced by SyntaxFlowParser#FunctionCallArgFilter.
+	VisitFunctionCallArgFilter(ctx *FunctionCallArgFilterContext) interface{}
+
+	// Visit a parse tree produced by SyntaxFlowParser#FunctionParamFilter.
+	VisitFunctionParamFilter(ctx *FunctionParamFilterContext) interface{}
+
+	// Visit a parse tree produced by SyntaxFlowParser#FunctionReturnFilter.
+	VisitFunctionReturnFilter(ctx *FunctionReturnFilterContext) interface{}
+
 	// Visit a parse tree produced by SyntaxFlowParser#Flat.
 	VisitFlat(ctx *FlatContext) interface{}
 
@@ -163,6 +163,32 @@ func (v *SyntaxFlowVisitor) EmitTypeCast(i string) {
 	})
 }
 
+func (v *SyntaxFlowVisitor) EmitGetCall() {
+	v.codes = append(v.codes, &SFI{
+		OpCode: OpGetCall,
+	})
+}
+
+func (v *SyntaxFlowVisitor) EmitGetCallArgs(i int) {
+	v.codes = append(v.codes, &SFI{
+		OpCode:   OpGetCallArgs,
+		UnaryInt: i,
+	})
+}
+
+func (v *SyntaxFlowVisitor) EmitGetParams(i int) {
+	v.codes = append(v.codes, &SFI{
+		OpCode:   OpGetParams,
+		UnaryInt: i,
+	})
+}
+
+func (v *SyntaxFlowVisitor) EmitGetReturns() {
+	v.codes = append(v.codes, &SFI{
+		OpCode: OpGetReturns,
+	})
+}
+
 func (v *SyntaxFlowVisitor) EmitSearch(i string) {
 	v.codes = append(v.codes, &SFI{
 		OpCode:   OpPushMatch,
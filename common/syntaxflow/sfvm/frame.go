@@ -33,6 +33,8 @@ type SFFrame struct {
 	Codes       []*SFI
 	toLeft      bool
 	debug       bool
+	// input 是 exec 的输入，栈为空时（上一条语句的结果已经保存到变量中）从 input 重新开始查询
+	input *Value
 }
 
 func NewSFFrame(vars *omap.OrderedMap[string, any], text string, codes []*SFI) *SFFrame {
@@ -70,7 +72,8 @@ func (s *SFFrame) ToRight() bool {
 }
 
 func (s *SFFrame) exec(input *omap.OrderedMap[string, any]) (ret error) {
-	s.stack.Push(NewValue(input))
+	s.input = NewValue(input)
+	s.stack.Push(s.input)
 	defer func() {
 		if err := recover(); err != nil {
 			ret = utils.Errorf("sft panic: %v", err)
@@ -98,7 +101,13 @@ func (s *SFFrame) exec(input *omap.OrderedMap[string, any]) (ret error) {
 			s.stack.Push(NewValue(i.UnaryInt))
 		case OpPushMatch:
 			s.debugSubLog("<< pop search: %v", i.UnaryStr)
-			top := s.stack.Pop().AsMap()
+			var top *omap.OrderedMap[string, any]
+			if s.stack.Len() > 0 {
+				top = s.stack.Pop().AsMap()
+			} else {
+				s.debugSubLog("stack is empty, search from input")
+				top = s.input.AsMap()
+			}
 			res, err := top.WalkSearchGlobKey(i.UnaryStr)
 			if err != nil {
 				return utils.Wrapf(err, "search glob key failed")
@@ -149,7 +158,15 @@ func (s *SFFrame) exec(input *omap.OrderedMap[string, any]) (ret error) {
 			results := s.stack.Pop().AsMap()
 			s.debugSubLog(">> (pop)")
 			r, ok := results.Get(i.UnaryStr)
-			if !ok {
+			if ops := ValueOperators(results); !ok && len(ops) > 0 {
+				var fields []ValueOperator
+				for _, op := range ops {
+					fields = append(fields, op.GetFields(i.UnaryStr)...)
+				}
+				s.debugSubLog(".%v := (len: %v) values", i.UnaryStr, len(fields))
+				s.stack.Push(NewValue(newValueOperatorList(fields)))
+				s.debugSubLog("<< push")
+			} else if !ok {
 				s.debugSubLog(".%v empty", i.UnaryStr)
 				s.stack.Push(NewValue(omap.NewEmptyOrderedMap[string, any]()))
 			} else {
@@ -176,6 +193,26 @@ func (s *SFFrame) exec(input *omap.OrderedMap[string, any]) (ret error) {
 				s.stack.Push(NewValue(omap.NewGeneralOrderedMap()))
 				s.debugSubLog("<< push")
 			}
+		case OpGetCall:
+			s.debugSubLog(">> pop get call")
+			s.execValueOperator(func(op ValueOperator) []ValueOperator {
+				return op.GetCalled()
+			})
+		case OpGetCallArgs:
+			s.debugSubLog(">> pop get call args: #%v", i.UnaryInt)
+			s.execValueOperator(func(op ValueOperator) []ValueOperator {
+				return op.GetCallActualParams(i.UnaryInt)
+			})
+		case OpGetParams:
+			s.debugSubLog(">> pop get function params: #%v", i.UnaryInt)
+			s.execValueOperator(func(op ValueOperator) []ValueOperator {
+				return op.GetFunctionParams(i.UnaryInt)
+			})
+		case OpGetReturns:
+			s.debugSubLog(">> pop get returns")
+			s.execValueOperator(func(op ValueOperator) []ValueOperator {
+				return op.GetCallReturns()
+			})
 		case OpSetDirection:
 			s.toLeft = i.UnaryStr == "<<"
 		case OpFlatStart:
@@ -380,6 +417,16 @@ func (s *SFFrame) exec(input *omap.OrderedMap[string, any]) (ret error) {
 	return nil
 }
 
+// execValueOperator 对栈顶中所有的 ValueOperator 执行 f，把结果作为列表压入栈中
+func (s *SFFrame) execValueOperator(f func(ValueOperator) []ValueOperator) {
+	var results []ValueOperator
+	for _, op := range ValueOperators(s.stack.Pop().Value()) {
+		results = append(results, f(op)...)
+	}
+	s.stack.Push(NewValue(newValueOperatorList(results)))
+	s.debugSubLog("<< push (len: %v)", len(results))
+}
+
 func (s *SFFrame) debugLog(i string, item ...any) {
 	if !s.debug {
 		return
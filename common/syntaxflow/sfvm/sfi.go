@@ -39,6 +39,12 @@ const (
 	OpRestoreMapContext
 	OpTypeCast
 
+	// OpGetCall and OpGetCallArgs and OpGetParams and OpGetReturns can operate ValueOperator in stack top
+	OpGetCall
+	OpGetCallArgs
+	OpGetParams
+	OpGetReturns
+
 	/*
 		Binary Operator
 		Fetch TWO in STACK, calc result, push result into stack
@@ -112,6 +118,14 @@ func (s *SFI) String() string {
 		return fmt.Sprintf(verboseLen+" %v - %v", "=>build-map", s.UnaryInt, s.Values)
 	case OpTypeCast:
 		return fmt.Sprintf(verboseLen+" %v", "type-cast", s.UnaryStr)
+	case OpGetCall:
+		return fmt.Sprintf(verboseLen+" %v", "call", s.UnaryStr)
+	case OpGetCallArgs:
+		return fmt.Sprintf(verboseLen+" (#%v)", "call$args", s.UnaryInt)
+	case OpGetParams:
+		return fmt.Sprintf(verboseLen+" #%v", "func$params", s.UnaryInt)
+	case OpGetReturns:
+		return fmt.Sprintf(verboseLen+" %v", "returns", s.UnaryStr)
 	case OpEq:
 		return fmt.Sprintf(verboseLen+" %v", "(operator) ==", s.UnaryStr)
 	case OpNotEq:
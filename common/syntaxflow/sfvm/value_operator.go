@@ -0,0 +1,51 @@
+package sfvm
+
+import (
+	"github.com/yaklang/yaklang/common/utils/omap"
+)
+
+// ValueOperator 是可以被 SyntaxFlow 查询的程序中的值（例如 ssaapi.Value），
+// 调用、实参、形参与返回值相关的过滤器通过它来获取值之间的关系
+type ValueOperator interface {
+	// GetFields 返回对象中名为 key 的成员，如 exec.System 中的 System
+	GetFields(key string) []ValueOperator
+	// GetCalled 返回所有调用了这个值的调用
+	GetCalled() []ValueOperator
+	// GetCallActualParams 返回调用的第 index 个实参
+	GetCallActualParams(index int) []ValueOperator
+	// GetFunctionParams 返回函数的第 index 个形参
+	GetFunctionParams(index int) []ValueOperator
+	// GetCallReturns 返回调用（或函数）的返回值
+	GetCallReturns() []ValueOperator
+}
+
+// ValueOperators 返回 i 中（包括嵌套的 omap 与列表）所有的 ValueOperator
+func ValueOperators(i any) []ValueOperator {
+	switch ret := i.(type) {
+	case ValueOperator:
+		return []ValueOperator{ret}
+	case *omap.OrderedMap[string, any]:
+		var ops []ValueOperator
+		for _, v := range ret.Values() {
+			ops = append(ops, ValueOperators(v)...)
+		}
+		return ops
+	case []any:
+		var ops []ValueOperator
+		for _, v := range ret {
+			ops = append(ops, ValueOperators(v)...)
+		}
+		return ops
+	case []ValueOperator:
+		return ret
+	}
+	return nil
+}
+
+func newValueOperatorList(ops []ValueOperator) *omap.OrderedMap[string, any] {
+	result := omap.NewGeneralOrderedMap()
+	for _, op := range ops {
+		result.Add(op)
+	}
+	return result
+}
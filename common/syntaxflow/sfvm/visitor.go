@@ -156,6 +156,19 @@ func (y *SyntaxFlowVisitor) VisitFilterExpr(raw sf.IFilterExprContext) interface
 	case *sf.FieldChainFilterContext:
 		y.VisitFilterExpr(ret.FilterExpr())
 		y.VisitFilterFieldMember(ret.FilterFieldMember()) // emit field or cast type
+	case *sf.FunctionCallFilterContext:
+		y.VisitFilterExpr(ret.FilterExpr())
+		y.EmitGetCall()
+	case *sf.FunctionCallArgFilterContext:
+		y.VisitFilterExpr(ret.FilterExpr())
+		y.EmitGetCall()
+		y.EmitGetCallArgs(y.VisitNumberLiteral(ret.NumberLiteral()))
+	case *sf.FunctionParamFilterContext:
+		y.VisitFilterExpr(ret.FilterExpr())
+		y.EmitGetParams(y.VisitNumberLiteral(ret.NumberLiteral()))
+	case *sf.FunctionReturnFilterContext:
+		y.VisitFilterExpr(ret.FilterExpr())
+		y.EmitGetReturns()
 	default:
 		panic("BUG: in filterExpr")
 	}
@@ -6,6 +6,8 @@ import (
 	"github.com/yaklang/yaklang/common/syntaxflow/sf"
 	"github.com/yaklang/yaklang/common/utils"
 	"github.com/yaklang/yaklang/common/utils/omap"
+	"github.com/yaklang/yaklang/common/yak/antlr4util"
+	"strings"
 	"sync"
 )
 
@@ -40,11 +42,20 @@ func (s *SyntaxFlowVirtualMachine) Compile(text string) (ret error) {
 			ret = utils.Wrapf(utils.Error(err), "Panic for SyntaxFlow compile")
 		}
 	}()
+	errListener := antlr4util.NewErrorListener()
 	lexer := sf.NewSyntaxFlowLexer(antlr.NewInputStream(text))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errListener)
 	astParser := sf.NewSyntaxFlowParser(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+	astParser.RemoveErrorListeners()
+	astParser.AddErrorListener(errListener)
+	flow := astParser.Flow()
+	if errs := errListener.GetErrors(); len(errs) > 0 {
+		return utils.Errorf("SyntaxFlow compile error: %v", strings.Join(errs, "; "))
+	}
 	result := NewSyntaxFlowVisitor()
 	result.text = text
-	result.VisitFlow(astParser.Flow())
+	result.VisitFlow(flow)
 	var frame = result.CreateFrame(s.vars)
 	s.frames = append(s.frames, frame)
 	return nil
@@ -0,0 +1,349 @@
+package bruteutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/netx"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/kerberos"
+)
+
+// KerberosConfig 为域环境下使用 Kerberos 认证爆破时的配置
+type KerberosConfig struct {
+	// Realm 为空时从 user@REALM 或者 DOMAIN\user 格式的用户名中解析
+	Realm string
+	// KDC 为空时通过域的 SRV 记录查找，kerberos 类型的爆破会使用目标作为 KDC
+	KDC string
+}
+
+func (k *KerberosConfig) newClient(item *BruteItem, kdc string) (*kerberos.Client, error) {
+	return kerberos.NewClient(
+		item.Username, k.Realm,
+		kerberos.WithPassword(item.Password),
+		kerberos.WithKDC(kdc),
+		kerberos.WithTimeout(defaultTimeout),
+	)
+}
+
+// kerberosResult 根据 Kerberos 错误设置爆破结果，KDC 不可达时结束对当前目标的爆破
+func kerberosResult(item *BruteItem, err error) *BruteItemResult {
+	res := item.Result()
+	if err == nil {
+		res.Ok = true
+		return res
+	}
+	if kerberos.ErrorCode(err) < 0 {
+		log.Errorf("kerberos brute %v failed: %v", item.Target, err)
+		res.Finished = true
+	}
+	return res
+}
+
+// GetKerberosBruteFuncByType 返回使用 Kerberos 认证的爆破函数，支持以下类型：
+// kerberos：目标为 KDC，直接进行 AS 预认证；
+// smb：申请 cifs/<host> 的服务票据并进行 SMB2 会话建立；
+// mssql：申请 MSSQLSvc/<host>:<port> 的服务票据并使用 SSPI 进行 TDS 登录，不支持要求加密的服务端。
+// 域环境中服务票据与主机名绑定，smb 与 mssql 的目标应当使用主机名而不是 IP
+func GetKerberosBruteFuncByType(t string, config *KerberosConfig) (BruteCallback, error) {
+	if config == nil {
+		config = &KerberosConfig{}
+	}
+	info := &DefaultServiceAuthInfo{ServiceName: strings.ToLower(t)}
+	switch info.ServiceName {
+	case "kerberos":
+		info.BrutePass = func(item *BruteItem) *BruteItemResult {
+			kdc := config.KDC
+			if kdc == "" {
+				kdc = appendDefaultPort(item.Target, 88)
+			}
+			client, err := config.newClient(item, kdc)
+			if err != nil {
+				return kerberosResult(item, err)
+			}
+			return kerberosResult(item, client.Login())
+		}
+	case "smb":
+		info.BrutePass = func(item *BruteItem) *BruteItemResult {
+			item.Target = appendDefaultPort(item.Target, 445)
+			host, _, _ := utils.ParseStringToHostPort(item.Target)
+			client, err := config.newClient(item, config.KDC)
+			if err != nil {
+				return kerberosResult(item, err)
+			}
+			token, err := client.SPNEGOToken("cifs/" + strings.ToLower(host))
+			if err != nil {
+				return kerberosResult(item, err)
+			}
+			ok, err := smb2SessionSetup(item.Target, token)
+			if err != nil {
+				log.Errorf("smb2 session setup %v failed: %v", item.Target, err)
+				res := item.Result()
+				res.Finished = true
+				return res
+			}
+			res := item.Result()
+			res.Ok = ok
+			return res
+		}
+	case "mssql":
+		info.BrutePass = func(item *BruteItem) *BruteItemResult {
+			target := fixToTarget(item.Target, 1433)
+			client, err := config.newClient(item, config.KDC)
+			if err != nil {
+				return kerberosResult(item, err)
+			}
+			host, port, _ := utils.ParseStringToHostPort(target)
+			token, err := client.SPNEGOToken("MSSQLSvc/" + strings.ToLower(utils.HostPort(host, port)))
+			if err != nil {
+				return kerberosResult(item, err)
+			}
+			ok, err := mssqlSSPILogin(target, host, token)
+			if err != nil {
+				log.Errorf("mssql sspi login %v failed: %v", target, err)
+				res := item.Result()
+				res.Finished = true
+				return res
+			}
+			res := item.Result()
+			res.Ok = ok
+			return res
+		}
+	default:
+		return nil, utils.Errorf("kerberos brute type[%s] is not supported", t)
+	}
+	return info.GetBruteHandler(), nil
+}
+
+const (
+	smb2CommandNegotiate    = 0x0000
+	smb2CommandSessionSetup = 0x0001
+
+	smb2StatusSuccess     = 0x00000000
+	smb2StatusLogonFailed = 0xc000006d
+)
+
+// smb2SessionSetup 使用 SPNEGO 令牌进行 SMB2 会话建立，返回认证是否成功
+func smb2SessionSetup(target string, token []byte) (bool, error) {
+	conn, err := netx.DialTCPTimeout(defaultTimeout, target)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(defaultTimeout))
+
+	negotiate := new(bytes.Buffer)
+	binary.Write(negotiate, binary.LittleEndian, []uint16{36, 2, 1, 0}) // StructureSize, DialectCount, SecurityMode, Reserved
+	negotiate.Write(make([]byte, 4+16+8))                               // Capabilities, ClientGuid, ClientStartTime
+	binary.Write(negotiate, binary.LittleEndian, []uint16{0x0202, 0x0210})
+	status, _, err := smb2Request(conn, smb2CommandNegotiate, 0, negotiate.Bytes())
+	if err != nil {
+		return false, err
+	}
+	if status != smb2StatusSuccess {
+		return false, utils.Errorf("smb2 negotiate failed: status 0x%08x", status)
+	}
+
+	setup := new(bytes.Buffer)
+	binary.Write(setup, binary.LittleEndian, uint16(25)) // StructureSize
+	setup.Write([]byte{0, 1})                            // Flags, SecurityMode
+	setup.Write(make([]byte, 8))                         // Capabilities, Channel
+	binary.Write(setup, binary.LittleEndian, []uint16{64 + 24, uint16(len(token))})
+	setup.Write(make([]byte, 8)) // PreviousSessionId
+	setup.Write(token)
+	status, _, err = smb2Request(conn, smb2CommandSessionSetup, 1, setup.Bytes())
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case smb2StatusSuccess:
+		return true, nil
+	case smb2StatusLogonFailed:
+		return false, nil
+	}
+	log.Debugf("smb2 session setup %v status: 0x%08x", target, status)
+	return false, nil
+}
+
+// smb2Request 发送 SMB2 请求并读取响应，返回响应头中的状态码与响应体
+func smb2Request(conn net.Conn, command uint16, messageID uint64, body []byte) (uint32, []byte, error) {
+	header := new(bytes.Buffer)
+	header.WriteString("\xfeSMB")
+	binary.Write(header, binary.LittleEndian, uint16(64)) // StructureSize
+	binary.Write(header, binary.LittleEndian, uint16(0))  // CreditCharge
+	binary.Write(header, binary.LittleEndian, uint32(0))  // Status
+	binary.Write(header, binary.LittleEndian, command)
+	binary.Write(header, binary.LittleEndian, uint16(1)) // CreditRequest
+	binary.Write(header, binary.LittleEndian, uint32(0)) // Flags
+	binary.Write(header, binary.LittleEndian, uint32(0)) // NextCommand
+	binary.Write(header, binary.LittleEndian, messageID)
+	header.Write(make([]byte, 4+4+8+16)) // ProcessId, TreeId, SessionId, Signature
+
+	size := header.Len() + len(body)
+	packet := append([]byte{0, byte(size >> 16), byte(size >> 8), byte(size)}, header.Bytes()...)
+	if _, err := conn.Write(append(packet, body...)); err != nil {
+		return 0, nil, err
+	}
+
+	sizeBuf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, sizeBuf); err != nil {
+		return 0, nil, err
+	}
+	rsp := make([]byte, int(sizeBuf[1])<<16|int(sizeBuf[2])<<8|int(sizeBuf[3]))
+	if _, err := io.ReadFull(conn, rsp); err != nil {
+		return 0, nil, err
+	}
+	if len(rsp) < 64 || string(rsp[:4]) != "\xfeSMB" {
+		return 0, nil, utils.Error("invalid smb2 response")
+	}
+	return binary.LittleEndian.Uint32(rsp[8:12]), rsp[64:], nil
+}
+
+const (
+	tdsPacketPreLogin = 0x12
+	tdsPacketLogin7   = 0x10
+	tdsPacketReply    = 0x04
+
+	tdsPreLoginVersion    = 0x00
+	tdsPreLoginEncryption = 0x01
+	tdsPreLoginTerminator = 0xff
+
+	tdsEncryptOff    = 0x00
+	tdsEncryptNotSup = 0x02
+	tdsEncryptReq    = 0x03
+
+	tdsTokenSSPI      = 0xed
+	tdsTokenError     = 0xaa
+	tdsTokenInfo      = 0xab
+	tdsTokenLoginAck  = 0xad
+	tdsTokenEnvChange = 0xe3
+)
+
+// mssqlSSPILogin 使用 SPNEGO 令牌进行 TDS 登录（LOGIN7 的 fIntSecurity），返回认证是否成功。
+// 预登录时声明不支持加密，服务端要求加密时返回错误
+func mssqlSSPILogin(target string, serverName string, token []byte) (bool, error) {
+	conn, err := netx.DialTCPTimeout(defaultTimeout, target)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(defaultTimeout))
+
+	preLogin := []byte{
+		tdsPreLoginVersion, 0, 11, 0, 6,
+		tdsPreLoginEncryption, 0, 17, 0, 1,
+		tdsPreLoginTerminator,
+		0, 0, 0, 0, 0, 0, // version
+		tdsEncryptNotSup,
+	}
+	rsp, err := tdsRequest(conn, tdsPacketPreLogin, preLogin)
+	if err != nil {
+		return false, err
+	}
+	encryption := -1
+	for i := 0; i+5 <= len(rsp) && rsp[i] != tdsPreLoginTerminator; i += 5 {
+		offset, length := int(binary.BigEndian.Uint16(rsp[i+1:])), int(binary.BigEndian.Uint16(rsp[i+3:]))
+		if rsp[i] == tdsPreLoginEncryption && length == 1 && offset < len(rsp) {
+			encryption = int(rsp[offset])
+		}
+	}
+	if encryption != tdsEncryptOff && encryption != tdsEncryptNotSup {
+		return false, utils.Errorf("mssql server requires encryption: 0x%02x", encryption)
+	}
+
+	rsp, err = tdsRequest(conn, tdsPacketLogin7, tdsLogin7(serverName, token))
+	if err != nil {
+		return false, err
+	}
+	// 依次跳过 ERROR / INFO / ENVCHANGE / SSPI 等令牌，出现 LOGINACK 即认证成功
+	for len(rsp) >= 3 {
+		switch rsp[0] {
+		case tdsTokenLoginAck:
+			return true, nil
+		case tdsTokenError, tdsTokenInfo, tdsTokenEnvChange, tdsTokenSSPI:
+		default:
+			return false, nil
+		}
+		next := 3 + int(binary.LittleEndian.Uint16(rsp[1:]))
+		if next > len(rsp) {
+			break
+		}
+		rsp = rsp[next:]
+	}
+	return false, nil
+}
+
+// tdsLogin7 构造使用集成认证的 LOGIN7 报文，用户名与密码为空，SSPI 字段为 SPNEGO 令牌
+func tdsLogin7(serverName string, token []byte) []byte {
+	const fixedSize = 94
+	ucs2 := func(s string) []byte {
+		buf := make([]byte, 0, len(s)*2)
+		for _, r := range utf16.Encode([]rune(s)) {
+			buf = append(buf, byte(r), byte(r>>8))
+		}
+		return buf
+	}
+	server := ucs2(serverName)
+
+	fixed := new(bytes.Buffer)
+	binary.Write(fixed, binary.LittleEndian, uint32(fixedSize+len(server)+len(token)))
+	binary.Write(fixed, binary.LittleEndian, []uint32{0x74000004, 4096, 0, 0, 0}) // TDSVersion, PacketSize, ClientProgVer, ClientPID, ConnectionID
+	fixed.Write([]byte{0xa0, 0x82, 0, 0})                                         // OptionFlags1(USE_DB, SET_LANG), OptionFlags2(ODBC, INTEGRATED_SECURITY), TypeFlags, OptionFlags3
+	binary.Write(fixed, binary.LittleEndian, []uint32{0, 0x0409})                 // ClientTimeZone, ClientLCID
+	// HostName, UserName, Password, AppName, ServerName, Extension, CltIntName, Language, Database
+	for i := 0; i < 9; i++ {
+		length := 0
+		if i == 4 {
+			length = len(server) / 2
+		}
+		binary.Write(fixed, binary.LittleEndian, []uint16{fixedSize, uint16(length)})
+	}
+	fixed.Write(make([]byte, 6)) // ClientID
+	binary.Write(fixed, binary.LittleEndian, []uint16{uint16(fixedSize + len(server)), uint16(len(token))})
+	binary.Write(fixed, binary.LittleEndian, []uint16{fixedSize, 0, fixedSize, 0}) // AtchDBFile, ChangePassword
+	binary.Write(fixed, binary.LittleEndian, uint32(0))                            // cbSSPILong
+	fixed.Write(server)
+	fixed.Write(token)
+	return fixed.Bytes()
+}
+
+// tdsRequest 发送单个 TDS 报文并读取完整的响应，返回拼接后的响应数据
+func tdsRequest(conn net.Conn, packetType byte, data []byte) ([]byte, error) {
+	size := 8 + len(data)
+	if size > 0xffff {
+		return nil, utils.Errorf("tds packet too large: %v", size)
+	}
+	header := []byte{packetType, 0x01, byte(size >> 8), byte(size), 0, 0, 1, 0}
+	if _, err := conn.Write(append(header, data...)); err != nil {
+		return nil, err
+	}
+
+	var rsp []byte
+	for {
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return nil, err
+		}
+		if header[0] != tdsPacketReply {
+			return nil, utils.Errorf("unexpected tds packet type: 0x%02x", header[0])
+		}
+		size := int(binary.BigEndian.Uint16(header[2:]))
+		if size < 8 {
+			return nil, utils.Error("invalid tds packet length")
+		}
+		body := make([]byte, size-8)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return nil, err
+		}
+		rsp = append(rsp, body...)
+		if header[1]&0x01 != 0 {
+			return rsp, nil
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package bruteutils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils/kerberos"
+	"github.com/yaklang/yaklang/common/utils/kerberos/kerberostest"
+)
+
+// startMockSMB2 启动只处理 NEGOTIATE 与 SESSION_SETUP 的 SMB2 服务，使用 keytab 校验客户端的 SPNEGO 令牌
+func startMockSMB2(t *testing.T, keytab *kerberos.Keytab) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+
+	reply := func(conn net.Conn, req []byte, status uint32) {
+		rsp := make([]byte, 64+8)
+		copy(rsp, req[:64])
+		binary.LittleEndian.PutUint32(rsp[8:], status)
+		binary.LittleEndian.PutUint32(rsp[16:], 1) // SMB2_FLAGS_SERVER_TO_REDIR
+		binary.LittleEndian.PutUint16(rsp[64:], 9)
+		size := len(rsp)
+		conn.Write(append([]byte{0, byte(size >> 16), byte(size >> 8), byte(size)}, rsp...))
+	}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				for {
+					sizeBuf := make([]byte, 4)
+					if _, err := io.ReadFull(conn, sizeBuf); err != nil {
+						return
+					}
+					req := make([]byte, int(sizeBuf[1])<<16|int(sizeBuf[2])<<8|int(sizeBuf[3]))
+					if _, err := io.ReadFull(conn, req); err != nil {
+						return
+					}
+					switch binary.LittleEndian.Uint16(req[12:]) {
+					case smb2CommandNegotiate:
+						reply(conn, req, smb2StatusSuccess)
+					case smb2CommandSessionSetup:
+						offset := binary.LittleEndian.Uint16(req[64+12:])
+						length := binary.LittleEndian.Uint16(req[64+14:])
+						_, err := kerberos.VerifyAPReq(req[offset:offset+length], keytab)
+						if err != nil {
+							reply(conn, req, smb2StatusLogonFailed)
+						} else {
+							reply(conn, req, smb2StatusSuccess)
+						}
+					}
+				}
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// startMockMSSQL 启动只处理 PRELOGIN 与 LOGIN7 的 TDS 服务，并在 KDC 中注册 MSSQLSvc/127.0.0.1:<port>。
+// 服务端使用 principals 的密钥（默认为自身的 SPN）校验 LOGIN7 中的 SPNEGO 令牌
+func startMockMSSQL(t *testing.T, kdc *kerberostest.MockKDC, encryption byte, principals ...string) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	spn := fmt.Sprintf("MSSQLSvc/%v", lis.Addr().String())
+	kdc.AddPrincipal(spn, "mssql-secret")
+	if len(principals) <= 0 {
+		principals = []string{spn}
+	}
+	keytab := kdc.Keytab(principals...)
+
+	reply := func(conn net.Conn, data []byte) {
+		size := 8 + len(data)
+		conn.Write(append([]byte{tdsPacketReply, 0x01, byte(size >> 8), byte(size), 0, 0, 1, 0}, data...))
+	}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				for {
+					header := make([]byte, 8)
+					if _, err := io.ReadFull(conn, header); err != nil {
+						return
+					}
+					req := make([]byte, int(binary.BigEndian.Uint16(header[2:]))-8)
+					if _, err := io.ReadFull(conn, req); err != nil {
+						return
+					}
+					switch header[0] {
+					case tdsPacketPreLogin:
+						reply(conn, []byte{tdsPreLoginEncryption, 0, 6, 0, 1, tdsPreLoginTerminator, encryption})
+					case tdsPacketLogin7:
+						offset := binary.LittleEndian.Uint16(req[78:])
+						length := binary.LittleEndian.Uint16(req[80:])
+						done := []byte{0xfd, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+						if _, err := kerberos.VerifyAPReq(req[offset:offset+length], keytab); err != nil {
+							msg := []byte{0x18, 0x48, 0, 0, 1, 14} // Number 18456, State, Class
+							reply(conn, append(append([]byte{tdsTokenError, byte(len(msg)), 0}, msg...), done...))
+						} else {
+							ack := []byte{1, 0x74, 0, 0, 4, 0, 0, 0, 0, 0}
+							reply(conn, append(append([]byte{tdsTokenLoginAck, byte(len(ack)), 0}, ack...), done...))
+						}
+					}
+				}
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func TestKerberosBrute(t *testing.T) {
+	kdc := kerberostest.NewMockKDC("corp.example.com")
+	kdc.AddPrincipal("alice", "Summer2024!")
+	kdc.AddPrincipal("cifs/127.0.0.1", "cifs-secret")
+	kdc.AddPrincipal("MSSQLSvc/db.corp.example.com:1433", "mssql-secret")
+	kdcAddr, err := kdc.Start()
+	require.NoError(t, err)
+	defer kdc.Close()
+
+	check := func(f BruteCallback, target, user, pass string) bool {
+		return f(&BruteItem{Target: target, Username: user, Password: pass}).Ok
+	}
+
+	t.Run("kerberos", func(t *testing.T) {
+		f, err := GetKerberosBruteFuncByType("kerberos", &KerberosConfig{Realm: "corp.example.com"})
+		require.NoError(t, err)
+		assert.True(t, check(f, kdcAddr, "alice", "Summer2024!"))
+		assert.False(t, check(f, kdcAddr, "alice", "Winter2024!"))
+		assert.False(t, check(f, kdcAddr, "bob", "Summer2024!"))
+
+		// 用户名中携带域
+		f, err = GetKerberosBruteFuncByType("kerberos", nil)
+		require.NoError(t, err)
+		assert.True(t, check(f, kdcAddr, "CORP.EXAMPLE.COM\\alice", "Summer2024!"))
+
+		// KDC 不可达时结束爆破
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		closed := lis.Addr().String()
+		lis.Close()
+		res := f(&BruteItem{Target: closed, Username: "alice@corp.example.com", Password: "Summer2024!"})
+		assert.False(t, res.Ok)
+		assert.True(t, res.Finished)
+	})
+
+	t.Run("smb", func(t *testing.T) {
+		smbAddr := startMockSMB2(t, kdc.Keytab("cifs/127.0.0.1"))
+		f, err := GetKerberosBruteFuncByType("smb", &KerberosConfig{Realm: "corp.example.com", KDC: kdcAddr})
+		require.NoError(t, err)
+		assert.True(t, check(f, smbAddr, "alice", "Summer2024!"))
+		assert.False(t, check(f, smbAddr, "alice", "Winter2024!"))
+
+		// 服务端使用其他服务的密钥时无法通过认证
+		smbAddr = startMockSMB2(t, kdc.Keytab("alice"))
+		assert.False(t, check(f, smbAddr, "alice", "Summer2024!"))
+	})
+
+	t.Run("mssql", func(t *testing.T) {
+		mssqlAddr := startMockMSSQL(t, kdc, tdsEncryptNotSup)
+		f, err := GetKerberosBruteFuncByType("mssql", &KerberosConfig{Realm: "corp.example.com", KDC: kdcAddr})
+		require.NoError(t, err)
+		assert.True(t, check(f, mssqlAddr, "alice", "Summer2024!"))
+		assert.False(t, check(f, mssqlAddr, "alice", "Winter2024!"))
+
+		// KDC 能签发票据但服务端无法解密时登录失败
+		mssqlAddr = startMockMSSQL(t, kdc, tdsEncryptNotSup, "alice")
+		assert.False(t, check(f, mssqlAddr, "alice", "Summer2024!"))
+
+		// 服务端要求加密时结束爆破
+		mssqlAddr = startMockMSSQL(t, kdc, tdsEncryptReq)
+		res := f(&BruteItem{Target: mssqlAddr, Username: "alice", Password: "Summer2024!"})
+		assert.False(t, res.Ok)
+		assert.True(t, res.Finished)
+	})
+
+	_, err = GetKerberosBruteFuncByType("ftp", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package kerberos
+
+import (
+	"encoding/asn1"
+
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// Marshal 编码结构体，Kerberos 中的字符串均为 GeneralString，
+// 标准库只会编码为 PrintableString / UTF8String，编码后统一改写 tag
+func Marshal(v any) ([]byte, error) {
+	raw, err := asn1.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := rewriteStringTags(raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// MarshalApplication 编码结构体并包裹 [APPLICATION tag]
+func MarshalApplication(tag int, v any) ([]byte, error) {
+	body, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(asn1.RawValue{Class: asn1.ClassApplication, Tag: tag, IsCompound: true, Bytes: body})
+}
+
+// UnmarshalApplication 解析 [APPLICATION tag] 包裹的结构体，tags 为允许的 tag
+func UnmarshalApplication(raw []byte, v any, tags ...int) error {
+	var outer asn1.RawValue
+	if _, err := asn1.Unmarshal(raw, &outer); err != nil {
+		return utils.Wrap(err, "unmarshal kerberos message failed")
+	}
+	if outer.Class != asn1.ClassApplication {
+		return utils.Errorf("unexpected kerberos message class %v", outer.Class)
+	}
+	matched := false
+	for _, tag := range tags {
+		if outer.Tag == tag {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return utils.Errorf("unexpected kerberos message [APPLICATION %v], want %v", outer.Tag, tags)
+	}
+	if _, err := asn1.Unmarshal(outer.Bytes, v); err != nil {
+		return utils.Wrapf(err, "unmarshal kerberos [APPLICATION %v] failed", outer.Tag)
+	}
+	return nil
+}
+
+// ApplicationTag 返回消息的 APPLICATION tag，用于区分 KRB-ERROR 与正常响应
+func ApplicationTag(raw []byte) (int, error) {
+	var outer asn1.RawValue
+	if _, err := asn1.Unmarshal(raw, &outer); err != nil {
+		return 0, utils.Wrap(err, "unmarshal kerberos message failed")
+	}
+	if outer.Class != asn1.ClassApplication {
+		return 0, utils.Errorf("unexpected kerberos message class %v", outer.Class)
+	}
+	return outer.Tag, nil
+}
+
+// explicitRaw 将已编码的数据（如票据）作为 [tag] 显式标签字段
+func explicitRaw(tag int, der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: tag, IsCompound: true, Bytes: der}
+}
+
+func rewriteStringTags(der []byte) error {
+	for len(der) > 0 {
+		if len(der) < 2 {
+			return utils.Error("asn1 data truncated")
+		}
+		tag := der[0]
+		if tag&0x1f == 0x1f {
+			return utils.Error("high tag number is not supported")
+		}
+		length, header := int(der[1]), 2
+		if length&0x80 != 0 {
+			n := length & 0x7f
+			if n == 0 || n > 4 || len(der) < 2+n {
+				return utils.Error("invalid asn1 length")
+			}
+			length = 0
+			for _, b := range der[2 : 2+n] {
+				length = length<<8 | int(b)
+			}
+			header += n
+		}
+		if len(der) < header+length {
+			return utils.Error("asn1 data truncated")
+		}
+		content := der[header : header+length]
+		switch {
+		case tag&0x20 != 0:
+			if err := rewriteStringTags(content); err != nil {
+				return err
+			}
+		case tag == asn1.TagPrintableString, tag == asn1.TagUTF8String, tag == asn1.TagIA5String:
+			der[0] = asn1.TagGeneralString
+		}
+		der = der[header+length:]
+	}
+	return nil
+}
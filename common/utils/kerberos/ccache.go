@@ -0,0 +1,185 @@
+package kerberos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// Credential 为 ccache 中的一条票据
+type Credential struct {
+	Client      PrincipalName
+	ClientRealm string
+	Server      PrincipalName
+	ServerRealm string
+	Key         EncryptionKey
+	AuthTime    time.Time
+	StartTime   time.Time
+	EndTime     time.Time
+	RenewTill   time.Time
+	Flags       uint32
+	// Ticket 为 DER 编码的票据（[APPLICATION 1]）
+	Ticket []byte
+}
+
+// Expired 判断票据是否已过期（预留一分钟）
+func (c *Credential) Expired() bool {
+	return time.Now().Add(time.Minute).After(c.EndTime)
+}
+
+// IsTGT 判断票据是否为 krbtgt 票据
+func (c *Credential) IsTGT() bool {
+	return len(c.Server.NameString) == 2 && strings.EqualFold(c.Server.NameString[0], "krbtgt")
+}
+
+// CCache 为 MIT 凭据缓存文件（FILE: 类型，版本 3/4），可以导入 kinit 或者其他工具获取的票据
+type CCache struct {
+	DefaultPrincipal PrincipalName
+	DefaultRealm     string
+	Credentials      []*Credential
+}
+
+// LoadCCache 从文件中读取 ccache，path 支持 FILE: 前缀
+func LoadCCache(path string) (*CCache, error) {
+	path = strings.TrimPrefix(path, "FILE:")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, utils.Wrapf(err, "read ccache %v failed", path)
+	}
+	return ParseCCache(raw)
+}
+
+// ParseCCache 解析 ccache，仅支持大端序的 0x0503 与 0x0504 版本
+func ParseCCache(raw []byte) (*CCache, error) {
+	if len(raw) < 2 || raw[0] != 0x05 || (raw[1] != 0x03 && raw[1] != 0x04) {
+		return nil, utils.Error("invalid ccache: unsupported version")
+	}
+	v3 := raw[1] == 0x03
+	r := &binReader{r: bytes.NewReader(raw[2:]), order: binary.BigEndian}
+	if !v3 {
+		// header 中仅有 KDC 时间偏移，直接跳过
+		r.read(int(r.uint16()))
+	}
+	cc := &CCache{}
+	cc.DefaultPrincipal, cc.DefaultRealm = r.principal()
+	for r.err == nil && r.r.Len() > 0 {
+		cred := &Credential{}
+		cred.Client, cred.ClientRealm = r.principal()
+		cred.Server, cred.ServerRealm = r.principal()
+		cred.Key.KeyType = int32(r.uint16())
+		if v3 {
+			r.uint16()
+		}
+		cred.Key.KeyValue = r.bytes32()
+		cred.AuthTime = unixTime(r.uint32())
+		cred.StartTime = unixTime(r.uint32())
+		cred.EndTime = unixTime(r.uint32())
+		cred.RenewTill = unixTime(r.uint32())
+		r.uint8() // is_skey
+		cred.Flags = r.uint32()
+		for i := r.uint32(); i > 0 && r.err == nil; i-- { // addresses
+			r.uint16()
+			r.bytes32()
+		}
+		for i := r.uint32(); i > 0 && r.err == nil; i-- { // authdata
+			r.uint16()
+			r.bytes32()
+		}
+		cred.Ticket = r.bytes32()
+		r.bytes32() // second ticket
+		if r.err != nil {
+			break
+		}
+		// 跳过 X-CACHECONF: 等配置项
+		if cred.ServerRealm == "X-CACHECONF:" {
+			continue
+		}
+		cc.Credentials = append(cc.Credentials, cred)
+	}
+	if r.err != nil {
+		return nil, utils.Wrap(r.err, "parse ccache failed")
+	}
+	return cc, nil
+}
+
+func unixTime(i uint32) time.Time {
+	if i == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(i), 0)
+}
+
+func (b *binReader) principal() (PrincipalName, string) {
+	var p PrincipalName
+	p.NameType = int32(b.uint32())
+	count := int(b.uint32())
+	realm := string(b.bytes32())
+	for i := 0; i < count && b.err == nil; i++ {
+		p.NameString = append(p.NameString, string(b.bytes32()))
+	}
+	return p, realm
+}
+
+func (b *binWriter) principal(p PrincipalName, realm string) {
+	b.uint32(uint32(p.NameType))
+	b.uint32(uint32(len(p.NameString)))
+	b.bytes32([]byte(realm))
+	for _, name := range p.NameString {
+		b.bytes32([]byte(name))
+	}
+}
+
+// GetTGT 返回本域未过期的 TGT
+func (cc *CCache) GetTGT(realm string) (*Credential, bool) {
+	for _, cred := range cc.Credentials {
+		if !cred.IsTGT() || cred.Expired() {
+			continue
+		}
+		if realm == "" || strings.EqualFold(cred.Server.NameString[1], realm) {
+			return cred, true
+		}
+	}
+	return nil, false
+}
+
+// GetCredential 返回服务主体未过期的票据
+func (cc *CCache) GetCredential(spn PrincipalName) (*Credential, bool) {
+	for _, cred := range cc.Credentials {
+		if cred.Server.Equal(spn) && !cred.Expired() {
+			return cred, true
+		}
+	}
+	return nil, false
+}
+
+// Marshal 将 ccache 编码为 0x0504 格式，可以被 klist 等工具读取
+func (cc *CCache) Marshal() []byte {
+	w := &binWriter{order: binary.BigEndian}
+	w.buf.Write([]byte{0x05, 0x04})
+	w.uint16(0)
+	w.principal(cc.DefaultPrincipal, cc.DefaultRealm)
+	for _, cred := range cc.Credentials {
+		w.principal(cred.Client, cred.ClientRealm)
+		w.principal(cred.Server, cred.ServerRealm)
+		w.uint16(uint16(cred.Key.KeyType))
+		w.bytes32(cred.Key.KeyValue)
+		for _, t := range []time.Time{cred.AuthTime, cred.StartTime, cred.EndTime, cred.RenewTill} {
+			if t.IsZero() {
+				w.uint32(0)
+			} else {
+				w.uint32(uint32(t.Unix()))
+			}
+		}
+		w.uint8(0)
+		w.uint32(cred.Flags)
+		w.uint32(0)
+		w.uint32(0)
+		w.bytes32(cred.Ticket)
+		w.bytes32(nil)
+	}
+	return w.buf.Bytes()
+}
@@ -0,0 +1,571 @@
+package kerberos
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/netx"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// Kerberos 消息的 msg-type
+const (
+	MsgTypeASReq  = 10
+	MsgTypeASRep  = 11
+	MsgTypeTGSReq = 12
+	MsgTypeTGSRep = 13
+	MsgTypeAPReq  = 14
+	MsgTypeError  = 30
+)
+
+// Client 为 Kerberos 客户端，使用密码、keytab 或者 ccache 中的 TGT 向 KDC 申请服务票据
+type Client struct {
+	username PrincipalName
+	realm    string
+	password string
+	keytab   *Keytab
+	ccache   *CCache
+
+	kdc     []string
+	timeout time.Duration
+	etypes  []int32
+	proxy   []string
+
+	mutex   sync.Mutex
+	tgt     *Credential
+	tickets map[string]*Credential
+}
+
+type ClientOption func(c *Client)
+
+// WithPassword 设置用户密码
+func WithPassword(password string) ClientOption {
+	return func(c *Client) {
+		c.password = password
+	}
+}
+
+// WithKeytab 设置 keytab，使用其中的长期密钥进行认证
+func WithKeytab(kt *Keytab) ClientOption {
+	return func(c *Client) {
+		c.keytab = kt
+	}
+}
+
+// WithCCache 设置 ccache，优先使用其中未过期的 TGT 与服务票据
+func WithCCache(cc *CCache) ClientOption {
+	return func(c *Client) {
+		c.ccache = cc
+	}
+}
+
+// WithKDC 设置 KDC 地址，未设置时通过 _kerberos._tcp.REALM 的 SRV 记录查找，默认端口为 88
+func WithKDC(kdc ...string) ClientOption {
+	return func(c *Client) {
+		for _, addr := range kdc {
+			addr = strings.TrimSpace(addr)
+			if addr == "" {
+				continue
+			}
+			c.kdc = append(c.kdc, utils.AppendDefaultPort(addr, 88))
+		}
+	}
+}
+
+// WithTimeout 设置与 KDC 通信的超时时间
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// WithETypes 设置协商的加密类型
+func WithETypes(etypes ...int32) ClientOption {
+	return func(c *Client) {
+		c.etypes = etypes
+	}
+}
+
+// WithProxy 设置连接 KDC 使用的代理
+func WithProxy(proxy ...string) ClientOption {
+	return func(c *Client) {
+		c.proxy = append(c.proxy, proxy...)
+	}
+}
+
+// ParseUsername 解析用户名，支持 user@REALM 与 DOMAIN\user 两种写法，
+// realm 不为空时优先使用 realm，返回的 realm 均为大写
+func ParseUsername(username, realm string) (string, string) {
+	if strings.Contains(username, "\\") {
+		domainAndUser := strings.SplitN(username, "\\", 2)
+		if realm == "" {
+			realm = domainAndUser[0]
+		}
+		username = domainAndUser[1]
+	} else if idx := strings.LastIndex(username, "@"); idx > 0 {
+		if realm == "" {
+			realm = username[idx+1:]
+		}
+		username = username[:idx]
+	}
+	return username, strings.ToUpper(realm)
+}
+
+// NewClient 创建 Kerberos 客户端，username 支持 user@REALM 与 DOMAIN\user 写法，
+// 使用 ccache 时 username 可以为空，此时使用 ccache 中的默认主体
+func NewClient(username, realm string, opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		timeout: 10 * time.Second,
+		etypes:  DefaultETypes,
+		tickets: make(map[string]*Credential),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	name, realm := ParseUsername(username, realm)
+	if name == "" && c.ccache != nil {
+		c.username = c.ccache.DefaultPrincipal
+		if realm == "" {
+			realm = strings.ToUpper(c.ccache.DefaultRealm)
+		}
+	} else {
+		c.username = NewPrincipalName(NameTypePrincipal, name)
+	}
+	c.realm = realm
+	if len(c.username.NameString) <= 0 || c.username.NameString[0] == "" {
+		return nil, utils.Error("kerberos username is empty")
+	}
+	if c.realm == "" {
+		return nil, utils.Errorf("kerberos realm for %v is empty", c.username)
+	}
+	if c.password == "" && c.keytab == nil && c.ccache == nil {
+		return nil, utils.Errorf("kerberos credential for %v@%v is empty, need password, keytab or ccache", c.username, c.realm)
+	}
+	return c, nil
+}
+
+func (c *Client) Realm() string {
+	return c.realm
+}
+
+func (c *Client) Username() string {
+	return c.username.String()
+}
+
+// Login 获取 TGT，ccache 中存在可用的 TGT 时直接使用，否则进行 AS 交换
+func (c *Client) Login() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, err := c.getTGT()
+	return err
+}
+
+func (c *Client) getTGT() (*Credential, error) {
+	if c.tgt != nil && !c.tgt.Expired() {
+		return c.tgt, nil
+	}
+	if c.ccache != nil {
+		if tgt, ok := c.ccache.GetTGT(c.realm); ok {
+			c.tgt = tgt
+			return tgt, nil
+		}
+	}
+	if c.password == "" && c.keytab == nil {
+		return nil, utils.Errorf("no valid tgt in ccache for %v@%v", c.username, c.realm)
+	}
+	tgt, err := c.asExchange()
+	if err != nil {
+		return nil, err
+	}
+	c.tgt = tgt
+	return tgt, nil
+}
+
+// ServiceTicket 获取服务票据，spn 如 HTTP/www.example.com、cifs/dc01.example.com
+func (c *Client) ServiceTicket(spn string) (*Credential, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sname := NewPrincipalName(NameTypeSrvInst, spn)
+	if cred, ok := c.tickets[strings.ToLower(spn)]; ok && !cred.Expired() {
+		return cred, nil
+	}
+	if c.ccache != nil {
+		if cred, ok := c.ccache.GetCredential(sname); ok {
+			return cred, nil
+		}
+	}
+	tgt, err := c.getTGT()
+	if err != nil {
+		return nil, err
+	}
+	cred, err := c.tgsExchange(tgt, sname)
+	if err != nil {
+		return nil, err
+	}
+	c.tickets[strings.ToLower(spn)] = cred
+	return cred, nil
+}
+
+// CCache 导出当前获取到的 TGT 与服务票据
+func (c *Client) CCache() *CCache {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cc := &CCache{DefaultPrincipal: c.username, DefaultRealm: c.realm}
+	if c.tgt != nil {
+		cc.Credentials = append(cc.Credentials, c.tgt)
+	}
+	for _, cred := range c.tickets {
+		cc.Credentials = append(cc.Credentials, cred)
+	}
+	return cc
+}
+
+func (c *Client) clientKey(etype int32, salt string, s2kparams []byte) (EncryptionKey, error) {
+	if c.password != "" {
+		if salt == "" {
+			salt = DefaultSalt(c.realm, c.username)
+		}
+		return StringToKey(etype, c.password, salt, s2kparams)
+	}
+	if c.keytab != nil {
+		if key, _, ok := c.keytab.GetKey(c.username, c.realm, etype); ok {
+			return key, nil
+		}
+	}
+	return EncryptionKey{}, utils.Errorf("no %v key for %v@%v", ETypeName(etype), c.username, c.realm)
+}
+
+func (c *Client) asETypes() []int32 {
+	if c.password == "" && c.keytab != nil {
+		if etypes := c.keytab.ETypes(c.username, c.realm); len(etypes) > 0 {
+			return etypes
+		}
+	}
+	return c.etypes
+}
+
+func (c *Client) newASReq(etypes []int32, nonce int, padata []PAData) ([]byte, error) {
+	now := time.Now()
+	req := KDCReq{
+		PVNO:    5,
+		MsgType: MsgTypeASReq,
+		PAData:  padata,
+		ReqBody: KDCReqBody{
+			KDCOptions: newBitString(defaultASOptions),
+			CName:      c.username,
+			Realm:      c.realm,
+			SName:      PrincipalName{NameType: NameTypeSrvInst, NameString: []string{"krbtgt", c.realm}},
+			Till:       kerberosTime(now.Add(24 * time.Hour)),
+			RTime:      kerberosTime(now.Add(7 * 24 * time.Hour)),
+			Nonce:      nonce,
+			EType:      etypes,
+		},
+	}
+	return MarshalApplication(ApplicationASReq, req)
+}
+
+func (c *Client) asExchange() (*Credential, error) {
+	etypes := c.asETypes()
+	nonce := newNonce()
+	req, err := c.newASReq(etypes, nonce, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := c.sendToKDC(req)
+
+	salt, s2kparams := "", []byte(nil)
+	var krbErr *KRBError
+	if errors.As(err, &krbErr) && krbErr.ErrorCode == ErrCodePreAuthRequired {
+		req, salt, s2kparams, err = c.preAuthASReq(parseMethodData(krbErr.EData), etypes, nonce)
+		if err != nil {
+			return nil, err
+		}
+		rsp, err = c.sendToKDC(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rep KDCRep
+	if err := UnmarshalApplication(rsp, &rep, ApplicationASRep); err != nil {
+		return nil, err
+	}
+	if e, s, p := selectETypeInfo(rep.PAData, []int32{rep.EncPart.EType}); e != 0 {
+		salt, s2kparams = s, p
+	}
+	key, err := c.clientKey(rep.EncPart.EType, salt, s2kparams)
+	if err != nil {
+		return nil, err
+	}
+	return decryptKDCRep(&rep, key, KeyUsageASRepEncPart, nonce)
+}
+
+// preAuthASReq 根据 KDC 返回的 ETYPE-INFO2 构造携带 PA-ENC-TIMESTAMP 的 AS-REQ
+func (c *Client) preAuthASReq(padata []PAData, etypes []int32, nonce int) ([]byte, string, []byte, error) {
+	etype, salt, s2kparams := selectETypeInfo(padata, etypes)
+	if etype == 0 && len(padata) <= 0 && len(etypes) > 0 {
+		// KDC 没有给出 ETYPE-INFO2 时使用首选的加密类型与默认 salt
+		etype = etypes[0]
+	}
+	if etype == 0 {
+		return nil, "", nil, utils.Errorf("kdc does not support etypes %v for %v@%v", etypes, c.username, c.realm)
+	}
+	key, err := c.clientKey(etype, salt, s2kparams)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	pa, err := encTimestamp(key)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	req, err := c.newASReq(etypes, nonce, []PAData{pa})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return req, salt, s2kparams, nil
+}
+
+func (c *Client) tgsExchange(tgt *Credential, sname PrincipalName) (*Credential, error) {
+	now := time.Now()
+	nonce := newNonce()
+	body := KDCReqBody{
+		KDCOptions: newBitString(defaultTGSOptions),
+		Realm:      c.realm,
+		SName:      sname,
+		Till:       kerberosTime(now.Add(24 * time.Hour)),
+		Nonce:      nonce,
+		EType:      c.etypes,
+	}
+	bodyRaw, err := Marshal(body)
+	if err != nil {
+		return nil, utils.Wrap(err, "marshal tgs-req body failed")
+	}
+	cksum, err := MakeChecksum(tgt.Key, KeyUsageTGSReqChecksum, bodyRaw)
+	if err != nil {
+		return nil, err
+	}
+	apReq, err := NewAPReq(tgt, KeyUsageTGSReqAuthenticator, cksum, 0)
+	if err != nil {
+		return nil, err
+	}
+	req, err := MarshalApplication(ApplicationTGSReq, KDCReq{
+		PVNO:    5,
+		MsgType: MsgTypeTGSReq,
+		PAData:  []PAData{{PADataType: PADataTGSReq, PADataValue: apReq}},
+		ReqBody: body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := c.sendToKDC(req)
+	if err != nil {
+		return nil, utils.Wrapf(err, "request service ticket %v failed", sname)
+	}
+	var rep KDCRep
+	if err := UnmarshalApplication(rsp, &rep, ApplicationTGSRep); err != nil {
+		return nil, err
+	}
+	return decryptKDCRep(&rep, tgt.Key, KeyUsageTGSRepEncPart, nonce)
+}
+
+func decryptKDCRep(rep *KDCRep, key EncryptionKey, usage uint32, nonce int) (*Credential, error) {
+	plain, err := DecryptData(key, usage, rep.EncPart)
+	if err != nil {
+		return nil, utils.Wrap(err, "decrypt kdc reply failed")
+	}
+	var part EncKDCRepPart
+	// Windows 的 TGS-REP 中也可能使用 EncASRepPart
+	if err := UnmarshalApplication(plain, &part, ApplicationEncASRepPart, ApplicationEncTGSRepPart); err != nil {
+		return nil, err
+	}
+	if part.Nonce != nonce {
+		return nil, utils.Errorf("kdc reply nonce mismatch")
+	}
+	var flags uint32
+	if len(part.Flags.Bytes) >= 4 {
+		flags = binary.BigEndian.Uint32(part.Flags.Bytes)
+	}
+	return &Credential{
+		Client:      rep.CName,
+		ClientRealm: rep.CRealm,
+		Server:      part.SName,
+		ServerRealm: part.SRealm,
+		Key:         part.Key,
+		AuthTime:    part.AuthTime,
+		StartTime:   part.StartTime,
+		EndTime:     part.EndTime,
+		RenewTill:   part.RenewTill,
+		Flags:       flags,
+		Ticket:      rep.Ticket.Bytes,
+	}, nil
+}
+
+func encTimestamp(key EncryptionKey) (PAData, error) {
+	now := time.Now().UTC()
+	ts, err := Marshal(PAEncTimestamp{PATimestamp: kerberosTime(now), PAUSec: now.Nanosecond() / 1000})
+	if err != nil {
+		return PAData{}, err
+	}
+	enc, err := EncryptData(key, KeyUsageASReqTimestamp, ts, 0)
+	if err != nil {
+		return PAData{}, err
+	}
+	value, err := Marshal(enc)
+	if err != nil {
+		return PAData{}, err
+	}
+	return PAData{PADataType: PADataEncTimestamp, PADataValue: value}, nil
+}
+
+func parseMethodData(methodData []byte) []PAData {
+	var padata []PAData
+	if len(methodData) > 0 {
+		if _, err := asn1.Unmarshal(methodData, &padata); err != nil {
+			log.Debugf("unmarshal kerberos method-data failed: %v", err)
+		}
+	}
+	return padata
+}
+
+// selectETypeInfo 从 ETYPE-INFO2 中选出第一个支持的加密类型以及 salt
+func selectETypeInfo(padata []PAData, etypes []int32) (int32, string, []byte) {
+	for _, pa := range padata {
+		if pa.PADataType != PADataETypeInfo2 {
+			continue
+		}
+		var entries []ETypeInfo2Entry
+		if _, err := asn1.Unmarshal(pa.PADataValue, &entries); err != nil {
+			log.Debugf("unmarshal kerberos etype-info2 failed: %v", err)
+			continue
+		}
+		for _, entry := range entries {
+			for _, etype := range etypes {
+				if entry.EType == etype {
+					return entry.EType, entry.Salt, entry.S2KParams
+				}
+			}
+		}
+	}
+	return 0, "", nil
+}
+
+// NewAPReq 使用票据构造 AP-REQ，usage 为 Authenticator 的 key usage，
+// 访问服务时为 KeyUsageAPReqAuthenticator，TGS-REQ 中为 KeyUsageTGSReqAuthenticator
+func NewAPReq(cred *Credential, usage uint32, cksum Checksum, apOptions uint32) ([]byte, error) {
+	now := time.Now().UTC()
+	auth := Authenticator{
+		AVNO:      5,
+		CRealm:    cred.ClientRealm,
+		CName:     cred.Client,
+		Cksum:     cksum,
+		CUSec:     now.Nanosecond() / 1000,
+		CTime:     kerberosTime(now),
+		SeqNumber: int64(newNonce()),
+	}
+	authRaw, err := MarshalApplication(ApplicationAuthenticator, auth)
+	if err != nil {
+		return nil, utils.Wrap(err, "marshal authenticator failed")
+	}
+	enc, err := EncryptData(cred.Key, usage, authRaw, 0)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalApplication(ApplicationAPReq, APReq{
+		PVNO:          5,
+		MsgType:       MsgTypeAPReq,
+		APOptions:     newBitString(apOptions),
+		Ticket:        explicitRaw(3, cred.Ticket),
+		Authenticator: enc,
+	})
+}
+
+func newNonce() int {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return int(binary.BigEndian.Uint32(buf) & 0x7fffffff)
+}
+
+func (c *Client) kdcAddrs() []string {
+	if len(c.kdc) > 0 {
+		return c.kdc
+	}
+	var addrs []string
+	if _, srvs, err := net.LookupSRV("kerberos", "tcp", c.realm); err == nil {
+		for _, srv := range srvs {
+			addrs = append(addrs, utils.HostPort(strings.TrimSuffix(srv.Target, "."), srv.Port))
+		}
+	}
+	if len(addrs) <= 0 {
+		addrs = append(addrs, utils.HostPort(strings.ToLower(c.realm), 88))
+	}
+	return addrs
+}
+
+// sendToKDC 通过 TCP 发送请求，KDC 返回 KRB-ERROR 时返回 *KRBError
+func (c *Client) sendToKDC(req []byte) ([]byte, error) {
+	var lastErr error
+	for _, addr := range c.kdcAddrs() {
+		rsp, err := c.sendTCP(addr, req)
+		if err != nil {
+			log.Debugf("kerberos: send to kdc %v failed: %v", addr, err)
+			lastErr = err
+			continue
+		}
+		tag, err := ApplicationTag(rsp)
+		if err != nil {
+			return nil, err
+		}
+		if tag == ApplicationKRBError {
+			krbErr := &KRBError{}
+			if err := UnmarshalApplication(rsp, krbErr, ApplicationKRBError); err != nil {
+				return nil, err
+			}
+			return nil, krbErr
+		}
+		return rsp, nil
+	}
+	return nil, utils.Wrapf(lastErr, "kerberos: no kdc available for %v", c.realm)
+}
+
+func (c *Client) sendTCP(addr string, req []byte) ([]byte, error) {
+	conn, err := netx.DialTCPTimeout(c.timeout, addr, c.proxy...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.timeout))
+	packet := make([]byte, 4+len(req))
+	binary.BigEndian.PutUint32(packet, uint32(len(req)))
+	copy(packet[4:], req)
+	if _, err := conn.Write(packet); err != nil {
+		return nil, err
+	}
+	return ReadKDCMessage(conn)
+}
+
+// ReadKDCMessage 读取 TCP 传输的 Kerberos 消息，消息以 4 字节大端长度开头
+func ReadKDCMessage(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > 1<<20 {
+		return nil, utils.Errorf("kerberos message too large: %v", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,117 @@
+package kerberos_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils/kerberos"
+	"github.com/yaklang/yaklang/common/utils/kerberos/kerberostest"
+)
+
+func startMockKDC(t *testing.T) (*kerberostest.MockKDC, string) {
+	kdc := kerberostest.NewMockKDC("example.com")
+	kdc.AddPrincipal("alice", "Passw0rd!")
+	kdc.AddPrincipal("HTTP/www.example.com", "service-secret")
+	addr, err := kdc.Start()
+	require.NoError(t, err)
+	t.Cleanup(kdc.Close)
+	return kdc, addr
+}
+
+func TestParseUsername(t *testing.T) {
+	for _, c := range []struct {
+		username, realm         string
+		wantUsername, wantRealm string
+	}{
+		{"alice@example.com", "", "alice", "EXAMPLE.COM"},
+		{"EXAMPLE\\alice", "", "alice", "EXAMPLE"},
+		{"EXAMPLE\\alice", "example.com", "alice", "EXAMPLE.COM"},
+		{"alice", "example.com", "alice", "EXAMPLE.COM"},
+	} {
+		username, realm := kerberos.ParseUsername(c.username, c.realm)
+		assert.Equal(t, c.wantUsername, username)
+		assert.Equal(t, c.wantRealm, realm)
+	}
+}
+
+func TestClientPassword(t *testing.T) {
+	kdc, addr := startMockKDC(t)
+	for _, etype := range kerberos.DefaultETypes {
+		client, err := kerberos.NewClient("alice@example.com", "", kerberos.WithPassword("Passw0rd!"), kerberos.WithKDC(addr), kerberos.WithETypes(etype))
+		require.NoError(t, err)
+		require.NoError(t, client.Login(), kerberos.ETypeName(etype))
+
+		token, err := client.SPNEGOToken("HTTP/www.example.com")
+		require.NoError(t, err)
+		info, err := kerberos.VerifyAPReq(token, kdc.Keytab("HTTP/www.example.com"))
+		require.NoError(t, err)
+		assert.Equal(t, "alice@EXAMPLE.COM", info.ClientName())
+		assert.Equal(t, etype, info.SessionKey.KeyType)
+
+		// 其他服务的密钥无法解密票据
+		_, err = kerberos.VerifyAPReq(token, kdc.Keytab("alice"))
+		assert.Error(t, err)
+	}
+}
+
+func TestClientBadCredential(t *testing.T) {
+	_, addr := startMockKDC(t)
+	client, err := kerberos.NewClient("alice", "EXAMPLE.COM", kerberos.WithPassword("wrong"), kerberos.WithKDC(addr))
+	require.NoError(t, err)
+	err = client.Login()
+	require.Error(t, err)
+	assert.Equal(t, int32(kerberos.ErrCodePreAuthFailed), kerberos.ErrorCode(err))
+	assert.True(t, kerberos.IsCredentialError(err))
+
+	client, err = kerberos.NewClient("bob", "EXAMPLE.COM", kerberos.WithPassword("Passw0rd!"), kerberos.WithKDC(addr))
+	require.NoError(t, err)
+	err = client.Login()
+	assert.Equal(t, int32(kerberos.ErrCodePrincipalUnknown), kerberos.ErrorCode(err))
+
+	client, err = kerberos.NewClient("alice", "EXAMPLE.COM", kerberos.WithPassword("Passw0rd!"), kerberos.WithKDC(addr))
+	require.NoError(t, err)
+	_, err = client.ServiceTicket("HTTP/unknown.example.com")
+	assert.Equal(t, int32(kerberos.ErrCodeServerUnknown), kerberos.ErrorCode(err))
+}
+
+func TestClientKeytab(t *testing.T) {
+	kdc, addr := startMockKDC(t)
+	kt := kerberos.NewKeytab()
+	require.NoError(t, kt.AddEntry("alice", "EXAMPLE.COM", "Passw0rd!", 2, kerberos.ETypeAES128CTSHMACSHA196))
+	kt, err := kerberos.ParseKeytab(kt.Marshal())
+	require.NoError(t, err)
+	require.Len(t, kt.Entries, 1)
+	assert.Equal(t, uint32(2), kt.Entries[0].KVNO)
+
+	client, err := kerberos.NewClient("alice@EXAMPLE.COM", "", kerberos.WithKeytab(kt), kerberos.WithKDC(addr))
+	require.NoError(t, err)
+	token, err := client.SPNEGOToken("HTTP/www.example.com")
+	require.NoError(t, err)
+	info, err := kerberos.VerifyAPReq(token, kdc.Keytab("HTTP/www.example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice@EXAMPLE.COM", info.ClientName())
+}
+
+func TestClientCCache(t *testing.T) {
+	kdc, addr := startMockKDC(t)
+	client, err := kerberos.NewClient("alice@EXAMPLE.COM", "", kerberos.WithPassword("Passw0rd!"), kerberos.WithKDC(addr))
+	require.NoError(t, err)
+	require.NoError(t, client.Login())
+
+	cc, err := kerberos.ParseCCache(client.CCache().Marshal())
+	require.NoError(t, err)
+	tgt, ok := cc.GetTGT("EXAMPLE.COM")
+	require.True(t, ok)
+	assert.Equal(t, "alice", tgt.Client.String())
+
+	// 只使用 ccache 中的 TGT 申请服务票据
+	client, err = kerberos.NewClient("", "", kerberos.WithCCache(cc), kerberos.WithKDC(addr))
+	require.NoError(t, err)
+	assert.Equal(t, "EXAMPLE.COM", client.Realm())
+	token, err := client.SPNEGOToken("HTTP/www.example.com")
+	require.NoError(t, err)
+	info, err := kerberos.VerifyAPReq(token, kdc.Keytab("HTTP/www.example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice@EXAMPLE.COM", info.ClientName())
+}
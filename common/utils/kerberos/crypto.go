@@ -0,0 +1,404 @@
+package kerberos
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"crypto/sha1"
+	"encoding/binary"
+	"unicode/utf16"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"golang.org/x/crypto/md4"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	ETypeAES128CTSHMACSHA196 = 17
+	ETypeAES256CTSHMACSHA196 = 18
+	ETypeRC4HMAC             = 23
+)
+
+const (
+	ChecksumHMACSHA196AES128 = 15
+	ChecksumHMACSHA196AES256 = 16
+	ChecksumHMACMD5          = -138
+	// ChecksumGSSAPI 为 RFC 4121 中 AP-REQ Authenticator 携带的 GSS 通道绑定与标志
+	ChecksumGSSAPI = 0x8003
+)
+
+// DefaultETypes 为默认协商的加密类型，优先使用 AES
+var DefaultETypes = []int32{ETypeAES256CTSHMACSHA196, ETypeAES128CTSHMACSHA196, ETypeRC4HMAC}
+
+// ETypeName 返回加密类型的名称
+func ETypeName(etype int32) string {
+	switch etype {
+	case ETypeAES128CTSHMACSHA196:
+		return "aes128-cts-hmac-sha1-96"
+	case ETypeAES256CTSHMACSHA196:
+		return "aes256-cts-hmac-sha1-96"
+	case ETypeRC4HMAC:
+		return "rc4-hmac"
+	}
+	return "unknown"
+}
+
+// SupportedEType 判断是否支持该加密类型
+func SupportedEType(etype int32) bool {
+	switch etype {
+	case ETypeAES128CTSHMACSHA196, ETypeAES256CTSHMACSHA196, ETypeRC4HMAC:
+		return true
+	}
+	return false
+}
+
+func aesKeySize(etype int32) int {
+	if etype == ETypeAES128CTSHMACSHA196 {
+		return 16
+	}
+	return 32
+}
+
+// StringToKey 根据密码与 salt 生成长期密钥，AES 的 salt 一般为 REALM + 用户名，
+// s2kparams 为 ETYPE-INFO2 中的迭代次数，为空时使用默认的 4096
+func StringToKey(etype int32, password, salt string, s2kparams []byte) (EncryptionKey, error) {
+	switch etype {
+	case ETypeAES128CTSHMACSHA196, ETypeAES256CTSHMACSHA196:
+		iter := 4096
+		if len(s2kparams) == 4 {
+			iter = int(binary.BigEndian.Uint32(s2kparams))
+		}
+		tkey := pbkdf2.Key([]byte(password), []byte(salt), iter, aesKeySize(etype), sha1.New)
+		key, err := deriveKey(tkey, []byte("kerberos"))
+		if err != nil {
+			return EncryptionKey{}, err
+		}
+		return EncryptionKey{KeyType: etype, KeyValue: key}, nil
+	case ETypeRC4HMAC:
+		return EncryptionKey{KeyType: etype, KeyValue: ntHash(password)}, nil
+	}
+	return EncryptionKey{}, utils.Errorf("unsupported kerberos etype %v", etype)
+}
+
+func ntHash(password string) []byte {
+	u := utf16.Encode([]rune(password))
+	buf := make([]byte, len(u)*2)
+	for i, c := range u {
+		binary.LittleEndian.PutUint16(buf[i*2:], c)
+	}
+	h := md4.New()
+	h.Write(buf)
+	return h.Sum(nil)
+}
+
+// Encrypt 使用密钥加密数据，usage 为 RFC 4120 中的 key usage
+func Encrypt(key EncryptionKey, usage uint32, plaintext []byte) ([]byte, error) {
+	switch key.KeyType {
+	case ETypeAES128CTSHMACSHA196, ETypeAES256CTSHMACSHA196:
+		return aesEncrypt(key.KeyValue, usage, plaintext)
+	case ETypeRC4HMAC:
+		return rc4Encrypt(key.KeyValue, usage, plaintext)
+	}
+	return nil, utils.Errorf("unsupported kerberos etype %v", key.KeyType)
+}
+
+// Decrypt 解密数据并校验完整性
+func Decrypt(key EncryptionKey, usage uint32, ciphertext []byte) ([]byte, error) {
+	switch key.KeyType {
+	case ETypeAES128CTSHMACSHA196, ETypeAES256CTSHMACSHA196:
+		return aesDecrypt(key.KeyValue, usage, ciphertext)
+	case ETypeRC4HMAC:
+		return rc4Decrypt(key.KeyValue, usage, ciphertext)
+	}
+	return nil, utils.Errorf("unsupported kerberos etype %v", key.KeyType)
+}
+
+// EncryptData 加密并构造 EncryptedData
+func EncryptData(key EncryptionKey, usage uint32, plaintext []byte, kvno int) (EncryptedData, error) {
+	cipherText, err := Encrypt(key, usage, plaintext)
+	if err != nil {
+		return EncryptedData{}, err
+	}
+	return EncryptedData{EType: key.KeyType, KVNO: kvno, Cipher: cipherText}, nil
+}
+
+// DecryptData 解密 EncryptedData，密钥类型必须与密文一致
+func DecryptData(key EncryptionKey, usage uint32, data EncryptedData) ([]byte, error) {
+	if key.KeyType != data.EType {
+		return nil, utils.Errorf("kerberos etype mismatch: key %v, data %v", ETypeName(key.KeyType), ETypeName(data.EType))
+	}
+	return Decrypt(key, usage, data.Cipher)
+}
+
+// MakeChecksum 计算带密钥的校验和，用于 TGS-REQ 中对请求体的校验
+func MakeChecksum(key EncryptionKey, usage uint32, data []byte) (Checksum, error) {
+	switch key.KeyType {
+	case ETypeAES128CTSHMACSHA196, ETypeAES256CTSHMACSHA196:
+		kc, err := deriveKey(key.KeyValue, usageConstant(usage, 0x99))
+		if err != nil {
+			return Checksum{}, err
+		}
+		cksumType := int32(ChecksumHMACSHA196AES256)
+		if key.KeyType == ETypeAES128CTSHMACSHA196 {
+			cksumType = ChecksumHMACSHA196AES128
+		}
+		return Checksum{CksumType: cksumType, Checksum: hmacSHA1(kc, data)[:12]}, nil
+	case ETypeRC4HMAC:
+		ksign := hmacMD5(key.KeyValue, []byte("signaturekey\x00"))
+		h := md5.New()
+		h.Write(le32(rc4Usage(usage)))
+		h.Write(data)
+		return Checksum{CksumType: ChecksumHMACMD5, Checksum: hmacMD5(ksign, h.Sum(nil))}, nil
+	}
+	return Checksum{}, utils.Errorf("unsupported kerberos etype %v", key.KeyType)
+}
+
+// VerifyChecksum 校验带密钥的校验和
+func VerifyChecksum(key EncryptionKey, usage uint32, data []byte, cksum Checksum) bool {
+	expected, err := MakeChecksum(key, usage, data)
+	if err != nil || expected.CksumType != cksum.CksumType {
+		return false
+	}
+	return hmac.Equal(expected.Checksum, cksum.Checksum)
+}
+
+// RandomKey 生成与 etype 对应的随机会话密钥
+func RandomKey(etype int32) (EncryptionKey, error) {
+	size := 16
+	if etype == ETypeAES256CTSHMACSHA196 {
+		size = 32
+	}
+	if !SupportedEType(etype) {
+		return EncryptionKey{}, utils.Errorf("unsupported kerberos etype %v", etype)
+	}
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return EncryptionKey{}, err
+	}
+	return EncryptionKey{KeyType: etype, KeyValue: buf}, nil
+}
+
+func usageConstant(usage uint32, suffix byte) []byte {
+	return []byte{byte(usage >> 24), byte(usage >> 16), byte(usage >> 8), byte(usage), suffix}
+}
+
+func aesEncrypt(key []byte, usage uint32, plaintext []byte) ([]byte, error) {
+	ke, err := deriveKey(key, usageConstant(usage, 0xaa))
+	if err != nil {
+		return nil, err
+	}
+	ki, err := deriveKey(key, usageConstant(usage, 0x55))
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, aes.BlockSize+len(plaintext))
+	if _, err := rand.Read(data[:aes.BlockSize]); err != nil {
+		return nil, err
+	}
+	copy(data[aes.BlockSize:], plaintext)
+	ct, err := aesCTSEncrypt(ke, data)
+	if err != nil {
+		return nil, err
+	}
+	return append(ct, hmacSHA1(ki, data)[:12]...), nil
+}
+
+func aesDecrypt(key []byte, usage uint32, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aes.BlockSize+12 {
+		return nil, utils.Error("kerberos aes ciphertext too short")
+	}
+	ke, err := deriveKey(key, usageConstant(usage, 0xaa))
+	if err != nil {
+		return nil, err
+	}
+	ki, err := deriveKey(key, usageConstant(usage, 0x55))
+	if err != nil {
+		return nil, err
+	}
+	ct, mac := ciphertext[:len(ciphertext)-12], ciphertext[len(ciphertext)-12:]
+	data, err := aesCTSDecrypt(ke, ct)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(hmacSHA1(ki, data)[:12], mac) {
+		return nil, &KRBError{ErrorCode: ErrCodeIntegrityCheckFailed, EText: "integrity check failed"}
+	}
+	return data[aes.BlockSize:], nil
+}
+
+// aesCTSEncrypt 为 RFC 3962 中使用的 CBC 密文挪用（CBC-CS3），IV 为 0
+func aesCTSEncrypt(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	n := len(plaintext)
+	if n < aes.BlockSize {
+		return nil, utils.Error("aes-cts plaintext too short")
+	}
+	blocks := (n + aes.BlockSize - 1) / aes.BlockSize
+	padded := make([]byte, blocks*aes.BlockSize)
+	copy(padded, plaintext)
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(padded, padded)
+	if blocks == 1 {
+		return padded, nil
+	}
+	last := n - (blocks-1)*aes.BlockSize
+	out := make([]byte, 0, n)
+	out = append(out, padded[:(blocks-2)*aes.BlockSize]...)
+	out = append(out, padded[(blocks-1)*aes.BlockSize:]...)
+	out = append(out, padded[(blocks-2)*aes.BlockSize:(blocks-2)*aes.BlockSize+last]...)
+	return out, nil
+}
+
+func aesCTSDecrypt(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	n := len(ciphertext)
+	if n < aes.BlockSize {
+		return nil, utils.Error("aes-cts ciphertext too short")
+	}
+	iv := make([]byte, aes.BlockSize)
+	if n == aes.BlockSize {
+		out := make([]byte, n)
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, ciphertext)
+		return out, nil
+	}
+	blocks := (n + aes.BlockSize - 1) / aes.BlockSize
+	last := n - (blocks-1)*aes.BlockSize
+	prefix := ciphertext[:(blocks-2)*aes.BlockSize]
+	cn := ciphertext[(blocks-2)*aes.BlockSize : (blocks-1)*aes.BlockSize]
+	tail := ciphertext[(blocks-1)*aes.BlockSize:]
+
+	// 先解密最后一个完整块得到 Pn ^ Cn-1，再补全被截断的 Cn-1
+	dn := make([]byte, aes.BlockSize)
+	block.Decrypt(dn, cn)
+	cn1 := make([]byte, aes.BlockSize)
+	copy(cn1, tail)
+	copy(cn1[last:], dn[last:])
+	pn := make([]byte, last)
+	for i := 0; i < last; i++ {
+		pn[i] = dn[i] ^ cn1[i]
+	}
+
+	head := make([]byte, 0, (blocks-1)*aes.BlockSize)
+	head = append(head, prefix...)
+	head = append(head, cn1...)
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(head, head)
+	return append(head, pn...), nil
+}
+
+// deriveKey 为 RFC 3961 中的 DK(Key, Constant) = random-to-key(DR(Key, Constant))，AES 的 random-to-key 为恒等变换
+func deriveKey(key, constant []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	in := nfold(constant, aes.BlockSize)
+	out := make([]byte, 0, len(key)+aes.BlockSize)
+	for len(out) < len(key) {
+		buf := make([]byte, aes.BlockSize)
+		block.Encrypt(buf, in)
+		out = append(out, buf...)
+		in = buf
+	}
+	return out[:len(key)], nil
+}
+
+// nfold 为 RFC 3961 中的 n-fold 运算，n 为输出字节数
+func nfold(in []byte, n int) []byte {
+	inBytes := len(in)
+	a, b := n, inBytes
+	for b != 0 {
+		a, b = b, a%b
+	}
+	lcm := n * inBytes / a
+	out := make([]byte, n)
+	carry := 0
+	for i := lcm - 1; i >= 0; i-- {
+		msbit := ((inBytes << 3) - 1 + ((inBytes<<3)+13)*(i/inBytes) + ((inBytes - i%inBytes) << 3)) % (inBytes << 3)
+		hi := int(in[((inBytes-1)-(msbit>>3))%inBytes])
+		lo := int(in[(inBytes-(msbit>>3))%inBytes])
+		carry += ((hi<<8 | lo) >> ((msbit & 7) + 1)) & 0xff
+		carry += int(out[i%n])
+		out[i%n] = byte(carry)
+		carry >>= 8
+	}
+	if carry != 0 {
+		for i := n - 1; i >= 0; i-- {
+			carry += int(out[i])
+			out[i] = byte(carry)
+			carry >>= 8
+		}
+	}
+	return out
+}
+
+// rc4Usage 为 RFC 4757 中 key usage 到 Windows 消息类型的映射
+func rc4Usage(usage uint32) uint32 {
+	if usage == KeyUsageASRepEncPart {
+		return KeyUsageTGSRepEncPart
+	}
+	return usage
+}
+
+func rc4Encrypt(key []byte, usage uint32, plaintext []byte) ([]byte, error) {
+	k1 := hmacMD5(key, le32(rc4Usage(usage)))
+	data := make([]byte, 8+len(plaintext))
+	if _, err := rand.Read(data[:8]); err != nil {
+		return nil, err
+	}
+	copy(data[8:], plaintext)
+	cksum := hmacMD5(k1, data)
+	k3 := hmacMD5(k1, cksum)
+	c, err := rc4.NewCipher(k3)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 16+len(data))
+	copy(out, cksum)
+	c.XORKeyStream(out[16:], data)
+	return out, nil
+}
+
+func rc4Decrypt(key []byte, usage uint32, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 24 {
+		return nil, utils.Error("kerberos rc4 ciphertext too short")
+	}
+	k1 := hmacMD5(key, le32(rc4Usage(usage)))
+	cksum := ciphertext[:16]
+	k3 := hmacMD5(k1, cksum)
+	c, err := rc4.NewCipher(k3)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, len(ciphertext)-16)
+	c.XORKeyStream(data, ciphertext[16:])
+	if !hmac.Equal(hmacMD5(k1, data), cksum) {
+		return nil, &KRBError{ErrorCode: ErrCodeIntegrityCheckFailed, EText: "integrity check failed"}
+	}
+	return data[8:], nil
+}
+
+func hmacSHA1(key, data []byte) []byte {
+	h := hmac.New(sha1.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func hmacMD5(key, data []byte) []byte {
+	h := hmac.New(md5.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func le32(i uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, i)
+	return buf
+}
@@ -0,0 +1,96 @@
+package kerberos
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNFold(t *testing.T) {
+	// RFC 3961 A.1
+	for _, c := range []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"012345", 8, "be072631276b1955"},
+		{"password", 7, "78a07b6caf85fa"},
+		{"Rough Consensus, and Running Code", 8, "bb6ed30870b7f0e0"},
+		{"password", 21, "59e4a8ca7c0385c3c37b3f6d2000247cb6e6bd5b3e"},
+		{"kerberos", 8, "6b65726265726f73"},
+		{"kerberos", 16, "6b65726265726f737b9b5b2b93132b93"},
+		{"kerberos", 32, "6b65726265726f737b9b5b2b93132b935c9bdcdad95c9899c4cae4dee6d6cae4"},
+	} {
+		assert.Equal(t, c.want, hex.EncodeToString(nfold([]byte(c.in), c.n)), c.in)
+	}
+}
+
+func TestStringToKey(t *testing.T) {
+	// RFC 3962 B
+	iter1 := []byte{0, 0, 0, 1}
+	key, err := StringToKey(ETypeAES128CTSHMACSHA196, "password", "ATHENA.MIT.EDUraeburn", iter1)
+	require.NoError(t, err)
+	assert.Equal(t, "42263c6e89f4fc28b8df68ee09799f15", hex.EncodeToString(key.KeyValue))
+
+	key, err = StringToKey(ETypeAES256CTSHMACSHA196, "password", "ATHENA.MIT.EDUraeburn", iter1)
+	require.NoError(t, err)
+	assert.Equal(t, "fe697b52bc0d3ce14432ba036a92e65bbb52280990a2fa27883998d72af30161", hex.EncodeToString(key.KeyValue))
+
+	key, err = StringToKey(ETypeAES256CTSHMACSHA196, "password", "ATHENA.MIT.EDUraeburn", []byte{0, 0, 0x04, 0xb0})
+	require.NoError(t, err)
+	assert.Equal(t, "55a6ac740ad17b4846941051e1e8b0a7548d93b0ab30a8bc3ff16280382b8c2a", hex.EncodeToString(key.KeyValue))
+
+	key, err = StringToKey(ETypeRC4HMAC, "foo", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ac8e657f83df82beea5d43bdaf7800cc", hex.EncodeToString(key.KeyValue))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, etype := range DefaultETypes {
+		key, err := RandomKey(etype)
+		require.NoError(t, err)
+		for _, size := range []int{0, 1, 15, 16, 17, 31, 32, 100} {
+			plain := make([]byte, size)
+			for i := range plain {
+				plain[i] = byte(i)
+			}
+			ct, err := Encrypt(key, KeyUsageAPReqAuthenticator, plain)
+			require.NoError(t, err)
+			got, err := Decrypt(key, KeyUsageAPReqAuthenticator, ct)
+			require.NoError(t, err, ETypeName(etype))
+			assert.Equal(t, plain, got)
+
+			_, err = Decrypt(key, KeyUsageTicket, ct)
+			assert.Equal(t, int32(ErrCodeIntegrityCheckFailed), ErrorCode(err), ETypeName(etype))
+		}
+
+		cksum, err := MakeChecksum(key, KeyUsageTGSReqChecksum, []byte("body"))
+		require.NoError(t, err)
+		assert.True(t, VerifyChecksum(key, KeyUsageTGSReqChecksum, []byte("body"), cksum))
+		assert.False(t, VerifyChecksum(key, KeyUsageTGSReqChecksum, []byte("bodx"), cksum))
+	}
+}
+
+func TestAESCTS(t *testing.T) {
+	// RFC 3962 B, 使用 "chicken teriyaki" 作为密钥
+	key, _ := hex.DecodeString("636869636b656e207465726979616b69")
+	plain, _ := hex.DecodeString("4920776f756c64206c696b652074686520")
+	want := "c6353568f2bf8cb4d8a580362da7ff7f97"
+	ct, err := aesCTSEncrypt(key, plain)
+	require.NoError(t, err)
+	assert.Equal(t, want, hex.EncodeToString(ct))
+	got, err := aesCTSDecrypt(key, ct)
+	require.NoError(t, err)
+	assert.Equal(t, plain, got)
+
+	plain, _ = hex.DecodeString("4920776f756c64206c696b65207468652047656e6572616c20476175277320436869636b656e2c20706c656173652c")
+	want = "97687268d6ecccc0c07b25e25ecfe584b3fffd940c16a18c1b5549d2f838029e39312523a78662d5be7fcbcc98ebf5"
+	ct, err = aesCTSEncrypt(key, plain)
+	require.NoError(t, err)
+	assert.Equal(t, want, hex.EncodeToString(ct))
+	got, err = aesCTSDecrypt(key, ct)
+	require.NoError(t, err)
+	assert.Equal(t, plain, got)
+}
@@ -0,0 +1,323 @@
+// Package kerberostest 提供测试 Kerberos 认证流程使用的 KDC
+package kerberostest
+
+import (
+	"encoding/asn1"
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/kerberos"
+)
+
+// MockKDC 为仅支持 TCP 的最小 KDC 实现，要求预认证，用于测试 Kerberos 客户端以及依赖它的认证流程
+type MockKDC struct {
+	Realm string
+
+	mutex    sync.Mutex
+	keytab   *kerberos.Keytab
+	listener net.Listener
+}
+
+func NewMockKDC(realm string) *MockKDC {
+	m := &MockKDC{Realm: strings.ToUpper(realm), keytab: kerberos.NewKeytab()}
+	m.AddPrincipal("krbtgt/"+m.Realm, utils.RandStringBytes(32))
+	return m
+}
+
+// AddPrincipal 添加用户或者服务主体，服务主体如 HTTP/www.example.com
+func (m *MockKDC) AddPrincipal(name, password string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.keytab.AddEntry(name, m.Realm, password, 1)
+}
+
+// Keytab 返回包含指定主体密钥的 keytab，服务端可以用它校验 AP-REQ
+func (m *MockKDC) Keytab(names ...string) *kerberos.Keytab {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	kt := kerberos.NewKeytab()
+	for _, entry := range m.keytab.Entries {
+		for _, name := range names {
+			if entry.Principal.Equal(kerberos.NewPrincipalName(kerberos.NameTypePrincipal, name)) {
+				kt.Entries = append(kt.Entries, entry)
+			}
+		}
+	}
+	return kt
+}
+
+// Start 在 127.0.0.1 的随机端口上启动 KDC，返回监听地址
+func (m *MockKDC) Start() (string, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	m.listener = lis
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go m.serve(conn)
+		}
+	}()
+	return lis.Addr().String(), nil
+}
+
+func (m *MockKDC) Close() {
+	if m.listener != nil {
+		m.listener.Close()
+	}
+}
+
+func (m *MockKDC) serve(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	req, err := kerberos.ReadKDCMessage(conn)
+	if err != nil {
+		return
+	}
+	rsp, err := m.handle(req)
+	if err != nil {
+		krbErr, ok := err.(*kerberos.KRBError)
+		if !ok {
+			krbErr = &kerberos.KRBError{ErrorCode: kerberos.ErrCodeGeneric, EText: err.Error()}
+		}
+		rsp, err = m.errorMessage(krbErr)
+		if err != nil {
+			log.Errorf("mock kdc marshal error failed: %v", err)
+			return
+		}
+	}
+	packet := make([]byte, 4+len(rsp))
+	binary.BigEndian.PutUint32(packet, uint32(len(rsp)))
+	copy(packet[4:], rsp)
+	conn.Write(packet)
+}
+
+// kdcReqRaw 用于保留原始的 req-body，TGS-REQ 的校验和基于原始编码计算
+type kdcReqRaw struct {
+	PVNO    int               `asn1:"explicit,tag:1"`
+	MsgType int               `asn1:"explicit,tag:2"`
+	PAData  []kerberos.PAData `asn1:"optional,explicit,tag:3"`
+	ReqBody asn1.RawValue     `asn1:"explicit,tag:4"`
+}
+
+func (m *MockKDC) handle(raw []byte) ([]byte, error) {
+	tag, err := kerberos.ApplicationTag(raw)
+	if err != nil {
+		return nil, err
+	}
+	var req kdcReqRaw
+	if err := kerberos.UnmarshalApplication(raw, &req, kerberos.ApplicationASReq, kerberos.ApplicationTGSReq); err != nil {
+		return nil, err
+	}
+	var body kerberos.KDCReqBody
+	if _, err := asn1.Unmarshal(req.ReqBody.Bytes, &body); err != nil {
+		return nil, utils.Wrap(err, "unmarshal req-body failed")
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if tag == kerberos.ApplicationASReq {
+		return m.handleAS(&req, &body)
+	}
+	return m.handleTGS(&req, &body)
+}
+
+func (m *MockKDC) selectKey(name kerberos.PrincipalName, etypes []int32) (kerberos.EncryptionKey, bool) {
+	for _, etype := range etypes {
+		if key, _, ok := m.keytab.GetKey(name, m.Realm, etype); ok {
+			return key, true
+		}
+	}
+	return kerberos.EncryptionKey{}, false
+}
+
+func (m *MockKDC) etypeInfo(name kerberos.PrincipalName, etype int32) []kerberos.PAData {
+	entries, _ := kerberos.Marshal([]kerberos.ETypeInfo2Entry{{EType: etype, Salt: kerberos.DefaultSalt(m.Realm, name)}})
+	return []kerberos.PAData{{PADataType: kerberos.PADataETypeInfo2, PADataValue: entries}}
+}
+
+func (m *MockKDC) handleAS(req *kdcReqRaw, body *kerberos.KDCReqBody) ([]byte, error) {
+	if len(m.keytab.ETypes(body.CName, m.Realm)) <= 0 {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodePrincipalUnknown, CName: body.CName}
+	}
+	clientKey, ok := m.selectKey(body.CName, body.EType)
+	if !ok {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeETypeNotSupported}
+	}
+	var encTS *kerberos.EncryptedData
+	for _, pa := range req.PAData {
+		if pa.PADataType == kerberos.PADataEncTimestamp {
+			encTS = &kerberos.EncryptedData{}
+			if _, err := asn1.Unmarshal(pa.PADataValue, encTS); err != nil {
+				return nil, utils.Wrap(err, "unmarshal pa-enc-timestamp failed")
+			}
+		}
+	}
+	if encTS == nil {
+		methodData, _ := kerberos.Marshal(m.etypeInfo(body.CName, clientKey.KeyType))
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodePreAuthRequired, CName: body.CName, EData: methodData}
+	}
+	key, _, ok := m.keytab.GetKey(body.CName, m.Realm, encTS.EType)
+	if !ok {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeETypeNotSupported}
+	}
+	plain, err := kerberos.DecryptData(key, kerberos.KeyUsageASReqTimestamp, *encTS)
+	if err != nil {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodePreAuthFailed, CName: body.CName}
+	}
+	var ts kerberos.PAEncTimestamp
+	if _, err := asn1.Unmarshal(plain, &ts); err != nil {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodePreAuthFailed, CName: body.CName}
+	}
+	if d := time.Since(ts.PATimestamp); d > 5*time.Minute || d < -5*time.Minute {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeSkew}
+	}
+
+	tgs := kerberos.PrincipalName{NameType: kerberos.NameTypeSrvInst, NameString: []string{"krbtgt", m.Realm}}
+	return m.issue(kerberos.ApplicationASRep, body.CName, tgs, body, key, kerberos.KeyUsageASRepEncPart, m.etypeInfo(body.CName, key.KeyType))
+}
+
+func (m *MockKDC) handleTGS(req *kdcReqRaw, body *kerberos.KDCReqBody) ([]byte, error) {
+	var apReqRaw []byte
+	for _, pa := range req.PAData {
+		if pa.PADataType == kerberos.PADataTGSReq {
+			apReqRaw = pa.PADataValue
+		}
+	}
+	if apReqRaw == nil {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeGeneric, EText: "missing pa-tgs-req"}
+	}
+	var apReq kerberos.APReq
+	if err := kerberos.UnmarshalApplication(apReqRaw, &apReq, kerberos.ApplicationAPReq); err != nil {
+		return nil, err
+	}
+	var ticket kerberos.Ticket
+	if err := kerberos.UnmarshalApplication(apReq.Ticket.Bytes, &ticket, kerberos.ApplicationTicket); err != nil {
+		return nil, err
+	}
+	tgsKey, _, ok := m.keytab.GetKey(ticket.SName, m.Realm, ticket.EncPart.EType)
+	if !ok {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeServerUnknown}
+	}
+	plain, err := kerberos.DecryptData(tgsKey, kerberos.KeyUsageTicket, ticket.EncPart)
+	if err != nil {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeIntegrityCheckFailed}
+	}
+	var tgt kerberos.EncTicketPart
+	if err := kerberos.UnmarshalApplication(plain, &tgt, kerberos.ApplicationEncTicketPart); err != nil {
+		return nil, err
+	}
+	if time.Now().After(tgt.EndTime) {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeTicketExpired}
+	}
+	plain, err = kerberos.DecryptData(tgt.Key, kerberos.KeyUsageTGSReqAuthenticator, apReq.Authenticator)
+	if err != nil {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeIntegrityCheckFailed}
+	}
+	var auth kerberos.Authenticator
+	if err := kerberos.UnmarshalApplication(plain, &auth, kerberos.ApplicationAuthenticator); err != nil {
+		return nil, err
+	}
+	if !kerberos.VerifyChecksum(tgt.Key, kerberos.KeyUsageTGSReqChecksum, req.ReqBody.Bytes, auth.Cksum) {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeAPModified, EText: "req-body checksum mismatch"}
+	}
+	if len(m.keytab.ETypes(body.SName, m.Realm)) <= 0 {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeServerUnknown, SName: body.SName}
+	}
+	return m.issue(kerberos.ApplicationTGSRep, tgt.CName, body.SName, body, tgt.Key, kerberos.KeyUsageTGSRepEncPart, nil)
+}
+
+// issue 为 cname 签发 sname 的票据，回复中的 enc-part 使用 replyKey 加密
+func (m *MockKDC) issue(tag int, cname, sname kerberos.PrincipalName, body *kerberos.KDCReqBody, replyKey kerberos.EncryptionKey, usage uint32, padata []kerberos.PAData) ([]byte, error) {
+	serviceKey, ok := m.selectKey(sname, kerberos.DefaultETypes)
+	if !ok {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeServerUnknown, SName: sname}
+	}
+	sessionETypes := body.EType
+	if len(sessionETypes) <= 0 {
+		sessionETypes = kerberos.DefaultETypes
+	}
+	sessionKey, err := kerberos.RandomKey(sessionETypes[0])
+	if err != nil {
+		return nil, &kerberos.KRBError{ErrorCode: kerberos.ErrCodeETypeNotSupported}
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	endTime := now.Add(10 * time.Hour)
+	// forwardable, renewable, initial, pre-authent
+	flags := asn1.BitString{Bytes: []byte{0x40, 0xe0, 0x00, 0x00}, BitLength: 32}
+
+	encTicket, err := kerberos.MarshalApplication(kerberos.ApplicationEncTicketPart, kerberos.EncTicketPart{
+		Flags:     flags,
+		Key:       sessionKey,
+		CRealm:    m.Realm,
+		CName:     cname,
+		Transited: kerberos.TransitedEncoding{Contents: []byte{}},
+		AuthTime:  now,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encTicketData, err := kerberos.EncryptData(serviceKey, kerberos.KeyUsageTicket, encTicket, 1)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := kerberos.MarshalApplication(kerberos.ApplicationTicket, kerberos.Ticket{TktVNO: 5, Realm: m.Realm, SName: sname, EncPart: encTicketData})
+	if err != nil {
+		return nil, err
+	}
+
+	encPartTag := kerberos.ApplicationEncASRepPart
+	msgType := kerberos.MsgTypeASRep
+	if tag == kerberos.ApplicationTGSRep {
+		encPartTag = kerberos.ApplicationEncTGSRepPart
+		msgType = kerberos.MsgTypeTGSRep
+	}
+	encPart, err := kerberos.MarshalApplication(encPartTag, kerberos.EncKDCRepPart{
+		Key:      sessionKey,
+		LastReqs: []kerberos.LastReq{{LRValue: now}},
+		Nonce:    body.Nonce,
+		Flags:    flags,
+		AuthTime: now,
+		EndTime:  endTime,
+		SRealm:   m.Realm,
+		SName:    sname,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encPartData, err := kerberos.EncryptData(replyKey, usage, encPart, 0)
+	if err != nil {
+		return nil, err
+	}
+	return kerberos.MarshalApplication(tag, kerberos.KDCRep{
+		PVNO:    5,
+		MsgType: msgType,
+		PAData:  padata,
+		CRealm:  m.Realm,
+		CName:   cname,
+		Ticket:  asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 5, IsCompound: true, Bytes: ticket},
+		EncPart: encPartData,
+	})
+}
+
+func (m *MockKDC) errorMessage(krbErr *kerberos.KRBError) ([]byte, error) {
+	now := time.Now().UTC()
+	krbErr.PVNO = 5
+	krbErr.MsgType = kerberos.MsgTypeError
+	krbErr.STime = now.Truncate(time.Second)
+	krbErr.SUSec = now.Nanosecond() / 1000
+	krbErr.Realm = m.Realm
+	if len(krbErr.SName.NameString) <= 0 {
+		krbErr.SName = kerberos.PrincipalName{NameType: kerberos.NameTypeSrvInst, NameString: []string{"krbtgt", m.Realm}}
+	}
+	return kerberos.MarshalApplication(kerberos.ApplicationKRBError, *krbErr)
+}
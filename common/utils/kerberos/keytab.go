@@ -0,0 +1,262 @@
+package kerberos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+type KeytabEntry struct {
+	Principal PrincipalName
+	Realm     string
+	Timestamp time.Time
+	KVNO      uint32
+	Key       EncryptionKey
+}
+
+// Keytab 为 MIT keytab（0x0502 格式）文件，保存主体的长期密钥
+type Keytab struct {
+	Entries []*KeytabEntry
+}
+
+func NewKeytab() *Keytab {
+	return &Keytab{}
+}
+
+// LoadKeytab 从文件中读取 keytab
+func LoadKeytab(path string) (*Keytab, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, utils.Wrapf(err, "read keytab %v failed", path)
+	}
+	return ParseKeytab(raw)
+}
+
+// ParseKeytab 解析 keytab，支持 0x0501 与 0x0502 两种版本
+func ParseKeytab(raw []byte) (*Keytab, error) {
+	if len(raw) < 2 || raw[0] != 0x05 || (raw[1] != 0x01 && raw[1] != 0x02) {
+		return nil, utils.Error("invalid keytab: bad version")
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if raw[1] == 0x01 {
+		order = binary.LittleEndian
+	}
+	kt := NewKeytab()
+	r := bytes.NewReader(raw[2:])
+	for r.Len() > 0 {
+		var size int32
+		if err := binary.Read(r, order, &size); err != nil {
+			return nil, utils.Wrap(err, "read keytab entry size failed")
+		}
+		if size < 0 {
+			// 被删除的条目
+			if _, err := r.Seek(int64(-size), io.SeekCurrent); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entryRaw := make([]byte, size)
+		if _, err := io.ReadFull(r, entryRaw); err != nil {
+			return nil, utils.Wrap(err, "read keytab entry failed")
+		}
+		entry, err := parseKeytabEntry(entryRaw, order, raw[1] == 0x01)
+		if err != nil {
+			return nil, err
+		}
+		kt.Entries = append(kt.Entries, entry)
+	}
+	return kt, nil
+}
+
+func parseKeytabEntry(raw []byte, order binary.ByteOrder, v1 bool) (*KeytabEntry, error) {
+	r := &binReader{r: bytes.NewReader(raw), order: order}
+	count := int(r.uint16())
+	if v1 {
+		count--
+	}
+	entry := &KeytabEntry{Realm: string(r.bytes16())}
+	names := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		names = append(names, string(r.bytes16()))
+	}
+	entry.Principal.NameString = names
+	entry.Principal.NameType = NameTypePrincipal
+	if !v1 {
+		entry.Principal.NameType = int32(r.uint32())
+	}
+	entry.Timestamp = time.Unix(int64(r.uint32()), 0)
+	entry.KVNO = uint32(r.uint8())
+	entry.Key.KeyType = int32(r.uint16())
+	entry.Key.KeyValue = r.bytes16()
+	if r.err != nil {
+		return nil, utils.Wrap(r.err, "parse keytab entry failed")
+	}
+	// 新版本在条目末尾使用 32 位的 kvno
+	if r.r.Len() >= 4 {
+		if kvno := r.uint32(); kvno != 0 {
+			entry.KVNO = kvno
+		}
+	}
+	return entry, nil
+}
+
+// AddEntry 使用密码为主体生成各加密类型的密钥并加入 keytab
+func (kt *Keytab) AddEntry(principal, realm, password string, kvno uint32, etypes ...int32) error {
+	if len(etypes) <= 0 {
+		etypes = DefaultETypes
+	}
+	name := NewPrincipalName(NameTypePrincipal, principal)
+	for _, etype := range etypes {
+		key, err := StringToKey(etype, password, DefaultSalt(realm, name), nil)
+		if err != nil {
+			return err
+		}
+		kt.Entries = append(kt.Entries, &KeytabEntry{
+			Principal: name,
+			Realm:     realm,
+			Timestamp: time.Now(),
+			KVNO:      kvno,
+			Key:       key,
+		})
+	}
+	return nil
+}
+
+// GetKey 返回主体指定加密类型的密钥，存在多个版本时取 kvno 最大的
+func (kt *Keytab) GetKey(principal PrincipalName, realm string, etype int32) (EncryptionKey, uint32, bool) {
+	var found *KeytabEntry
+	for _, entry := range kt.Entries {
+		if entry.Key.KeyType != etype || !strings.EqualFold(entry.Realm, realm) || !entry.Principal.Equal(principal) {
+			continue
+		}
+		if found == nil || entry.KVNO > found.KVNO {
+			found = entry
+		}
+	}
+	if found == nil {
+		return EncryptionKey{}, 0, false
+	}
+	return found.Key, found.KVNO, true
+}
+
+// ETypes 返回 keytab 中主体拥有密钥的加密类型
+func (kt *Keytab) ETypes(principal PrincipalName, realm string) []int32 {
+	var etypes []int32
+	for _, etype := range DefaultETypes {
+		if _, _, ok := kt.GetKey(principal, realm, etype); ok {
+			etypes = append(etypes, etype)
+		}
+	}
+	return etypes
+}
+
+// Marshal 将 keytab 编码为 0x0502 格式
+func (kt *Keytab) Marshal() []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0x05, 0x02})
+	for _, entry := range kt.Entries {
+		w := &binWriter{order: binary.BigEndian}
+		w.uint16(uint16(len(entry.Principal.NameString)))
+		w.bytes16([]byte(entry.Realm))
+		for _, name := range entry.Principal.NameString {
+			w.bytes16([]byte(name))
+		}
+		w.uint32(uint32(entry.Principal.NameType))
+		w.uint32(uint32(entry.Timestamp.Unix()))
+		w.uint8(uint8(entry.KVNO))
+		w.uint16(uint16(entry.Key.KeyType))
+		w.bytes16(entry.Key.KeyValue)
+		w.uint32(entry.KVNO)
+		binary.Write(&buf, binary.BigEndian, int32(w.buf.Len()))
+		buf.Write(w.buf.Bytes())
+	}
+	return buf.Bytes()
+}
+
+// DefaultSalt 为默认的 salt：REALM 加上主体名各段
+func DefaultSalt(realm string, principal PrincipalName) string {
+	return realm + strings.Join(principal.NameString, "")
+}
+
+type binReader struct {
+	r     *bytes.Reader
+	order binary.ByteOrder
+	err   error
+}
+
+func (b *binReader) read(n int) []byte {
+	if b.err != nil {
+		return nil
+	}
+	if n < 0 || n > b.r.Len() {
+		b.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	buf := make([]byte, n)
+	_, b.err = io.ReadFull(b.r, buf)
+	return buf
+}
+
+func (b *binReader) uint8() uint8 {
+	buf := b.read(1)
+	if b.err != nil {
+		return 0
+	}
+	return buf[0]
+}
+
+func (b *binReader) uint16() uint16 {
+	buf := b.read(2)
+	if b.err != nil {
+		return 0
+	}
+	return b.order.Uint16(buf)
+}
+
+func (b *binReader) uint32() uint32 {
+	buf := b.read(4)
+	if b.err != nil {
+		return 0
+	}
+	return b.order.Uint32(buf)
+}
+
+func (b *binReader) bytes16() []byte {
+	return b.read(int(b.uint16()))
+}
+
+func (b *binReader) bytes32() []byte {
+	return b.read(int(b.uint32()))
+}
+
+type binWriter struct {
+	buf   bytes.Buffer
+	order binary.ByteOrder
+}
+
+func (b *binWriter) uint8(i uint8) {
+	b.buf.WriteByte(i)
+}
+
+func (b *binWriter) uint16(i uint16) {
+	binary.Write(&b.buf, b.order, i)
+}
+
+func (b *binWriter) uint32(i uint32) {
+	binary.Write(&b.buf, b.order, i)
+}
+
+func (b *binWriter) bytes16(data []byte) {
+	b.uint16(uint16(len(data)))
+	b.buf.Write(data)
+}
+
+func (b *binWriter) bytes32(data []byte) {
+	b.uint32(uint32(len(data)))
+	b.buf.Write(data)
+}
@@ -0,0 +1,196 @@
+package kerberos
+
+import (
+	"encoding/asn1"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+var (
+	OIDSPNEGO = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 2}
+	OIDKRB5   = asn1.ObjectIdentifier{1, 2, 840, 113554, 1, 2, 2}
+	// OIDMSKRB5 为 Windows 早期实现使用的 Kerberos OID
+	OIDMSKRB5 = asn1.ObjectIdentifier{1, 2, 840, 48018, 1, 2, 2}
+)
+
+const (
+	gssFlagMutual   = 0x02
+	gssFlagSequence = 0x08
+	gssFlagConf     = 0x10
+	gssFlagInteg    = 0x20
+)
+
+// krb5 GSS token 中 AP-REQ 的 TOK_ID
+var tokIDAPReq = []byte{0x01, 0x00}
+
+type negTokenInit struct {
+	MechTypes    []asn1.ObjectIdentifier `asn1:"explicit,tag:0"`
+	ReqFlags     asn1.BitString          `asn1:"optional,explicit,tag:1"`
+	MechToken    []byte                  `asn1:"optional,explicit,tag:2"`
+	MechTokenMIC []byte                  `asn1:"optional,explicit,tag:3"`
+}
+
+// SPNEGOToken 为 spn 申请服务票据并生成 SPNEGO NegTokenInit，可以直接用于 HTTP Negotiate 认证与 SMB 会话建立
+func (c *Client) SPNEGOToken(spn string) ([]byte, error) {
+	apReq, err := c.APReq(spn)
+	if err != nil {
+		return nil, err
+	}
+	return NewSPNEGOToken(apReq)
+}
+
+// APReq 为 spn 申请服务票据并生成访问服务使用的 AP-REQ，Authenticator 中携带 GSS-API 校验和
+func (c *Client) APReq(spn string) ([]byte, error) {
+	cred, err := c.ServiceTicket(spn)
+	if err != nil {
+		return nil, err
+	}
+	cksum := make([]byte, 24)
+	binary.LittleEndian.PutUint32(cksum, 16)
+	binary.LittleEndian.PutUint32(cksum[20:], gssFlagInteg|gssFlagConf|gssFlagSequence)
+	return NewAPReq(cred, KeyUsageAPReqAuthenticator, Checksum{CksumType: ChecksumGSSAPI, Checksum: cksum}, 0)
+}
+
+// NewKRB5Token 将 AP-REQ 包装为 RFC 1964 中的 krb5 GSS-API 初始上下文令牌
+func NewKRB5Token(apReq []byte) ([]byte, error) {
+	oid, err := asn1.Marshal(OIDKRB5)
+	if err != nil {
+		return nil, err
+	}
+	body := append(append(oid, tokIDAPReq...), apReq...)
+	return asn1.Marshal(asn1.RawValue{Class: asn1.ClassApplication, Tag: 0, IsCompound: true, Bytes: body})
+}
+
+// NewSPNEGOToken 将 AP-REQ 包装为 SPNEGO NegTokenInit
+func NewSPNEGOToken(apReq []byte) ([]byte, error) {
+	mechToken, err := NewKRB5Token(apReq)
+	if err != nil {
+		return nil, err
+	}
+	init, err := asn1.Marshal(negTokenInit{
+		MechTypes: []asn1.ObjectIdentifier{OIDKRB5, OIDMSKRB5},
+		MechToken: mechToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	choice, err := asn1.Marshal(explicitRaw(0, init))
+	if err != nil {
+		return nil, err
+	}
+	oid, err := asn1.Marshal(OIDSPNEGO)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(asn1.RawValue{Class: asn1.ClassApplication, Tag: 0, IsCompound: true, Bytes: append(oid, choice...)})
+}
+
+// ParseSPNEGOToken 从 SPNEGO NegTokenInit 或 krb5 GSS-API 令牌中取出 AP-REQ，
+// 传入的已经是 AP-REQ 时原样返回
+func ParseSPNEGOToken(token []byte) ([]byte, error) {
+	var outer asn1.RawValue
+	if _, err := asn1.Unmarshal(token, &outer); err != nil {
+		return nil, utils.Wrap(err, "unmarshal spnego token failed")
+	}
+	if outer.Class == asn1.ClassApplication && outer.Tag == ApplicationAPReq {
+		return token, nil
+	}
+	if outer.Class != asn1.ClassApplication || outer.Tag != 0 {
+		return nil, utils.Errorf("unexpected gss token [%v %v]", outer.Class, outer.Tag)
+	}
+	var oid asn1.ObjectIdentifier
+	rest, err := asn1.Unmarshal(outer.Bytes, &oid)
+	if err != nil {
+		return nil, utils.Wrap(err, "unmarshal gss mech oid failed")
+	}
+	switch {
+	case oid.Equal(OIDKRB5) || oid.Equal(OIDMSKRB5):
+		if len(rest) < 2 || rest[0] != tokIDAPReq[0] || rest[1] != tokIDAPReq[1] {
+			return nil, utils.Error("krb5 gss token is not an AP-REQ")
+		}
+		return rest[2:], nil
+	case oid.Equal(OIDSPNEGO):
+		var choice asn1.RawValue
+		if _, err := asn1.Unmarshal(rest, &choice); err != nil {
+			return nil, utils.Wrap(err, "unmarshal spnego token failed")
+		}
+		if choice.Class != asn1.ClassContextSpecific || choice.Tag != 0 {
+			return nil, utils.Error("spnego token is not a NegTokenInit")
+		}
+		var init negTokenInit
+		if _, err := asn1.Unmarshal(choice.Bytes, &init); err != nil {
+			return nil, utils.Wrap(err, "unmarshal NegTokenInit failed")
+		}
+		if len(init.MechToken) <= 0 {
+			return nil, utils.Error("NegTokenInit without mech token")
+		}
+		return ParseSPNEGOToken(init.MechToken)
+	}
+	return nil, utils.Errorf("unsupported gss mech %v", oid)
+}
+
+// APReqInfo 为服务端校验 AP-REQ 后得到的信息
+type APReqInfo struct {
+	Client     PrincipalName
+	Realm      string
+	Server     PrincipalName
+	SessionKey EncryptionKey
+	EndTime    time.Time
+}
+
+func (i *APReqInfo) ClientName() string {
+	return i.Client.String() + "@" + i.Realm
+}
+
+// VerifyAPReq 使用 keytab 中的服务密钥校验 AP-REQ（或 SPNEGO 令牌），
+// 解密票据与 Authenticator 并检查两者的客户端是否一致，可用于编写测试或者伪造的服务端
+func VerifyAPReq(token []byte, keytab *Keytab) (*APReqInfo, error) {
+	apReqRaw, err := ParseSPNEGOToken(token)
+	if err != nil {
+		return nil, err
+	}
+	var apReq APReq
+	if err := UnmarshalApplication(apReqRaw, &apReq, ApplicationAPReq); err != nil {
+		return nil, err
+	}
+	var ticket Ticket
+	if err := UnmarshalApplication(apReq.Ticket.Bytes, &ticket, ApplicationTicket); err != nil {
+		return nil, err
+	}
+	key, _, ok := keytab.GetKey(ticket.SName, ticket.Realm, ticket.EncPart.EType)
+	if !ok {
+		return nil, utils.Errorf("no %v key for service %v@%v", ETypeName(ticket.EncPart.EType), ticket.SName, ticket.Realm)
+	}
+	plain, err := DecryptData(key, KeyUsageTicket, ticket.EncPart)
+	if err != nil {
+		return nil, utils.Wrap(err, "decrypt ticket failed")
+	}
+	var encPart EncTicketPart
+	if err := UnmarshalApplication(plain, &encPart, ApplicationEncTicketPart); err != nil {
+		return nil, err
+	}
+	if time.Now().After(encPart.EndTime) {
+		return nil, &KRBError{ErrorCode: ErrCodeTicketExpired, EText: "ticket expired"}
+	}
+	plain, err = DecryptData(encPart.Key, KeyUsageAPReqAuthenticator, apReq.Authenticator)
+	if err != nil {
+		return nil, utils.Wrap(err, "decrypt authenticator failed")
+	}
+	var auth Authenticator
+	if err := UnmarshalApplication(plain, &auth, ApplicationAuthenticator); err != nil {
+		return nil, err
+	}
+	if !auth.CName.Equal(encPart.CName) || !strings.EqualFold(auth.CRealm, encPart.CRealm) {
+		return nil, &KRBError{ErrorCode: ErrCodeAPModified, EText: "authenticator client mismatch"}
+	}
+	return &APReqInfo{
+		Client:     encPart.CName,
+		Realm:      encPart.CRealm,
+		Server:     ticket.SName,
+		SessionKey: encPart.Key,
+		EndTime:    encPart.EndTime,
+	}, nil
+}
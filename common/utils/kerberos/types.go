@@ -0,0 +1,313 @@
+package kerberos
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RFC 4120 中的消息类型（APPLICATION tag）
+const (
+	ApplicationTicket        = 1
+	ApplicationAuthenticator = 2
+	ApplicationEncTicketPart = 3
+	ApplicationASReq         = 10
+	ApplicationASRep         = 11
+	ApplicationTGSReq        = 12
+	ApplicationTGSRep        = 13
+	ApplicationAPReq         = 14
+	ApplicationAPRep         = 15
+	ApplicationEncASRepPart  = 25
+	ApplicationEncTGSRepPart = 26
+	ApplicationEncAPRepPart  = 27
+	ApplicationKRBError      = 30
+)
+
+const (
+	NameTypeUnknown   = 0
+	NameTypePrincipal = 1
+	NameTypeSrvInst   = 2
+	NameTypeSrvHst    = 3
+)
+
+const (
+	PADataTGSReq       = 1
+	PADataEncTimestamp = 2
+	PADataETypeInfo    = 11
+	PADataETypeInfo2   = 19
+	PADataPACRequest   = 128
+)
+
+// key usage，见 RFC 4120 7.5.1
+const (
+	KeyUsageASReqTimestamp         = 1
+	KeyUsageTicket                 = 2
+	KeyUsageASRepEncPart           = 3
+	KeyUsageTGSReqChecksum         = 6
+	KeyUsageTGSReqAuthenticator    = 7
+	KeyUsageTGSRepEncPart          = 8
+	KeyUsageAPReqAuthenticatorCksm = 10
+	KeyUsageAPReqAuthenticator     = 11
+	KeyUsageAPRepEncPart           = 12
+)
+
+const (
+	ErrCodePrincipalUnknown     = 6
+	ErrCodeServerUnknown        = 7
+	ErrCodeClientRevoked        = 18
+	ErrCodeKeyExpired           = 23
+	ErrCodePreAuthFailed        = 24
+	ErrCodePreAuthRequired      = 25
+	ErrCodeETypeNotSupported    = 14
+	ErrCodeSkew                 = 37
+	ErrCodeAPModified           = 41
+	ErrCodeWrongRealm           = 68
+	ErrCodeResponseTooBig       = 52
+	ErrCodeGeneric              = 60
+	ErrCodeTicketExpired        = 32
+	ErrCodeIntegrityCheckFailed = 31
+)
+
+var errorCodeNames = map[int32]string{
+	ErrCodePrincipalUnknown:     "KDC_ERR_C_PRINCIPAL_UNKNOWN",
+	ErrCodeServerUnknown:        "KDC_ERR_S_PRINCIPAL_UNKNOWN",
+	ErrCodeETypeNotSupported:    "KDC_ERR_ETYPE_NOSUPP",
+	ErrCodeClientRevoked:        "KDC_ERR_CLIENT_REVOKED",
+	ErrCodeKeyExpired:           "KDC_ERR_KEY_EXPIRED",
+	ErrCodePreAuthFailed:        "KDC_ERR_PREAUTH_FAILED",
+	ErrCodePreAuthRequired:      "KDC_ERR_PREAUTH_REQUIRED",
+	ErrCodeIntegrityCheckFailed: "KRB_AP_ERR_BAD_INTEGRITY",
+	ErrCodeTicketExpired:        "KRB_AP_ERR_TKT_EXPIRED",
+	ErrCodeSkew:                 "KRB_AP_ERR_SKEW",
+	ErrCodeAPModified:           "KRB_AP_ERR_MODIFIED",
+	ErrCodeResponseTooBig:       "KRB_ERR_RESPONSE_TOO_BIG",
+	ErrCodeGeneric:              "KRB_ERR_GENERIC",
+	ErrCodeWrongRealm:           "KDC_ERR_WRONG_REALM",
+}
+
+const (
+	// forwardable | renewable | canonicalize | renewable-ok
+	defaultASOptions = 0x40810010
+	// forwardable | renewable | canonicalize
+	defaultTGSOptions = 0x40810000
+)
+
+type PrincipalName struct {
+	NameType   int32    `asn1:"explicit,tag:0"`
+	NameString []string `asn1:"explicit,tag:1"`
+}
+
+// NewPrincipalName 根据 name 构造主体名，name 中的 / 会被拆分为多段，如 HTTP/www.example.com
+func NewPrincipalName(nameType int32, name string) PrincipalName {
+	return PrincipalName{NameType: nameType, NameString: strings.Split(name, "/")}
+}
+
+func (p PrincipalName) String() string {
+	return strings.Join(p.NameString, "/")
+}
+
+func (p PrincipalName) Equal(o PrincipalName) bool {
+	return strings.EqualFold(p.String(), o.String())
+}
+
+type EncryptedData struct {
+	EType  int32  `asn1:"explicit,tag:0"`
+	KVNO   int    `asn1:"optional,explicit,tag:1"`
+	Cipher []byte `asn1:"explicit,tag:2"`
+}
+
+type EncryptionKey struct {
+	KeyType  int32  `asn1:"explicit,tag:0"`
+	KeyValue []byte `asn1:"explicit,tag:1"`
+}
+
+type Checksum struct {
+	CksumType int32  `asn1:"explicit,tag:0"`
+	Checksum  []byte `asn1:"explicit,tag:1"`
+}
+
+type PAData struct {
+	PADataType  int32  `asn1:"explicit,tag:1"`
+	PADataValue []byte `asn1:"explicit,tag:2"`
+}
+
+type PAEncTimestamp struct {
+	PATimestamp time.Time `asn1:"generalized,explicit,tag:0"`
+	PAUSec      int       `asn1:"optional,explicit,tag:1"`
+}
+
+type ETypeInfo2Entry struct {
+	EType     int32  `asn1:"explicit,tag:0"`
+	Salt      string `asn1:"optional,explicit,tag:1"`
+	S2KParams []byte `asn1:"optional,explicit,tag:2"`
+}
+
+type HostAddress struct {
+	AddrType int32  `asn1:"explicit,tag:0"`
+	Address  []byte `asn1:"explicit,tag:1"`
+}
+
+type AuthorizationDataEntry struct {
+	ADType int32  `asn1:"explicit,tag:0"`
+	ADData []byte `asn1:"explicit,tag:1"`
+}
+
+type TransitedEncoding struct {
+	TRType   int32  `asn1:"explicit,tag:0"`
+	Contents []byte `asn1:"explicit,tag:1"`
+}
+
+type LastReq struct {
+	LRType  int32     `asn1:"explicit,tag:0"`
+	LRValue time.Time `asn1:"generalized,explicit,tag:1"`
+}
+
+// Ticket 为 [APPLICATION 1]，出现在其他结构中时以 asn1.RawValue 保存，避免票据被重新编码
+type Ticket struct {
+	TktVNO  int           `asn1:"explicit,tag:0"`
+	Realm   string        `asn1:"explicit,tag:1"`
+	SName   PrincipalName `asn1:"explicit,tag:2"`
+	EncPart EncryptedData `asn1:"explicit,tag:3"`
+}
+
+type EncTicketPart struct {
+	Flags             asn1.BitString           `asn1:"explicit,tag:0"`
+	Key               EncryptionKey            `asn1:"explicit,tag:1"`
+	CRealm            string                   `asn1:"explicit,tag:2"`
+	CName             PrincipalName            `asn1:"explicit,tag:3"`
+	Transited         TransitedEncoding        `asn1:"explicit,tag:4"`
+	AuthTime          time.Time                `asn1:"generalized,explicit,tag:5"`
+	StartTime         time.Time                `asn1:"generalized,optional,explicit,tag:6"`
+	EndTime           time.Time                `asn1:"generalized,explicit,tag:7"`
+	RenewTill         time.Time                `asn1:"generalized,optional,explicit,tag:8"`
+	CAddr             []HostAddress            `asn1:"optional,explicit,tag:9"`
+	AuthorizationData []AuthorizationDataEntry `asn1:"optional,explicit,tag:10"`
+}
+
+type KDCReqBody struct {
+	KDCOptions        asn1.BitString  `asn1:"explicit,tag:0"`
+	CName             PrincipalName   `asn1:"optional,explicit,tag:1"`
+	Realm             string          `asn1:"explicit,tag:2"`
+	SName             PrincipalName   `asn1:"optional,explicit,tag:3"`
+	From              time.Time       `asn1:"generalized,optional,explicit,tag:4"`
+	Till              time.Time       `asn1:"generalized,explicit,tag:5"`
+	RTime             time.Time       `asn1:"generalized,optional,explicit,tag:6"`
+	Nonce             int             `asn1:"explicit,tag:7"`
+	EType             []int32         `asn1:"explicit,tag:8"`
+	Addresses         []HostAddress   `asn1:"optional,explicit,tag:9"`
+	EncAuthData       EncryptedData   `asn1:"optional,explicit,tag:10"`
+	AdditionalTickets []asn1.RawValue `asn1:"optional,explicit,tag:11"`
+}
+
+// KDCReq 为 AS-REQ [APPLICATION 10] 与 TGS-REQ [APPLICATION 12] 的共同结构
+type KDCReq struct {
+	PVNO    int        `asn1:"explicit,tag:1"`
+	MsgType int        `asn1:"explicit,tag:2"`
+	PAData  []PAData   `asn1:"optional,explicit,tag:3"`
+	ReqBody KDCReqBody `asn1:"explicit,tag:4"`
+}
+
+// KDCRep 为 AS-REP [APPLICATION 11] 与 TGS-REP [APPLICATION 13] 的共同结构
+type KDCRep struct {
+	PVNO    int           `asn1:"explicit,tag:0"`
+	MsgType int           `asn1:"explicit,tag:1"`
+	PAData  []PAData      `asn1:"optional,explicit,tag:2"`
+	CRealm  string        `asn1:"explicit,tag:3"`
+	CName   PrincipalName `asn1:"explicit,tag:4"`
+	Ticket  asn1.RawValue `asn1:"explicit,tag:5"`
+	EncPart EncryptedData `asn1:"explicit,tag:6"`
+}
+
+// EncKDCRepPart 为 EncASRepPart [APPLICATION 25] 与 EncTGSRepPart [APPLICATION 26] 的共同结构
+type EncKDCRepPart struct {
+	Key           EncryptionKey  `asn1:"explicit,tag:0"`
+	LastReqs      []LastReq      `asn1:"explicit,tag:1"`
+	Nonce         int            `asn1:"explicit,tag:2"`
+	KeyExpiration time.Time      `asn1:"generalized,optional,explicit,tag:3"`
+	Flags         asn1.BitString `asn1:"explicit,tag:4"`
+	AuthTime      time.Time      `asn1:"generalized,explicit,tag:5"`
+	StartTime     time.Time      `asn1:"generalized,optional,explicit,tag:6"`
+	EndTime       time.Time      `asn1:"generalized,explicit,tag:7"`
+	RenewTill     time.Time      `asn1:"generalized,optional,explicit,tag:8"`
+	SRealm        string         `asn1:"explicit,tag:9"`
+	SName         PrincipalName  `asn1:"explicit,tag:10"`
+	CAddr         []HostAddress  `asn1:"optional,explicit,tag:11"`
+}
+
+type APReq struct {
+	PVNO          int            `asn1:"explicit,tag:0"`
+	MsgType       int            `asn1:"explicit,tag:1"`
+	APOptions     asn1.BitString `asn1:"explicit,tag:2"`
+	Ticket        asn1.RawValue  `asn1:"explicit,tag:3"`
+	Authenticator EncryptedData  `asn1:"explicit,tag:4"`
+}
+
+type Authenticator struct {
+	AVNO              int                      `asn1:"explicit,tag:0"`
+	CRealm            string                   `asn1:"explicit,tag:1"`
+	CName             PrincipalName            `asn1:"explicit,tag:2"`
+	Cksum             Checksum                 `asn1:"optional,explicit,tag:3"`
+	CUSec             int                      `asn1:"explicit,tag:4"`
+	CTime             time.Time                `asn1:"generalized,explicit,tag:5"`
+	SubKey            EncryptionKey            `asn1:"optional,explicit,tag:6"`
+	SeqNumber         int64                    `asn1:"optional,explicit,tag:7"`
+	AuthorizationData []AuthorizationDataEntry `asn1:"optional,explicit,tag:8"`
+}
+
+type KRBError struct {
+	PVNO      int           `asn1:"explicit,tag:0"`
+	MsgType   int           `asn1:"explicit,tag:1"`
+	CTime     time.Time     `asn1:"generalized,optional,explicit,tag:2"`
+	CUSec     int           `asn1:"optional,explicit,tag:3"`
+	STime     time.Time     `asn1:"generalized,explicit,tag:4"`
+	SUSec     int           `asn1:"explicit,tag:5"`
+	ErrorCode int32         `asn1:"explicit,tag:6"`
+	CRealm    string        `asn1:"optional,explicit,tag:7"`
+	CName     PrincipalName `asn1:"optional,explicit,tag:8"`
+	Realm     string        `asn1:"explicit,tag:9"`
+	SName     PrincipalName `asn1:"explicit,tag:10"`
+	EText     string        `asn1:"optional,explicit,tag:11"`
+	EData     []byte        `asn1:"optional,explicit,tag:12"`
+}
+
+func (e *KRBError) Error() string {
+	name, ok := errorCodeNames[e.ErrorCode]
+	if !ok {
+		name = "KRB_ERROR"
+	}
+	if e.EText != "" {
+		return fmt.Sprintf("kerberos error %v(%v): %v", name, e.ErrorCode, e.EText)
+	}
+	return fmt.Sprintf("kerberos error %v(%v)", name, e.ErrorCode)
+}
+
+// ErrorCode 返回 err 中的 Kerberos 错误码，err 不是 KRB-ERROR 时返回 -1
+func ErrorCode(err error) int32 {
+	var krbErr *KRBError
+	if errors.As(err, &krbErr) {
+		return krbErr.ErrorCode
+	}
+	return -1
+}
+
+// IsCredentialError 判断错误是否由错误的用户名或密码导致
+func IsCredentialError(err error) bool {
+	switch ErrorCode(err) {
+	case ErrCodePreAuthFailed, ErrCodePrincipalUnknown, ErrCodeIntegrityCheckFailed, ErrCodeClientRevoked, ErrCodeKeyExpired:
+		return true
+	}
+	return false
+}
+
+func newBitString(flags uint32) asn1.BitString {
+	return asn1.BitString{
+		Bytes:     []byte{byte(flags >> 24), byte(flags >> 16), byte(flags >> 8), byte(flags)},
+		BitLength: 32,
+	}
+}
+
+func kerberosTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
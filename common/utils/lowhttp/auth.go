@@ -3,13 +3,16 @@ package lowhttp
 import (
 	"bufio"
 	"encoding/base64"
+	"fmt"
 	"github.com/yaklang/yaklang/common/consts"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/utils"
 	"github.com/yaklang/yaklang/common/utils/bruteutils/grdp/protocol/nla"
+	"github.com/yaklang/yaklang/common/utils/kerberos"
 	"github.com/yaklang/yaklang/common/yak/yaklib/codec"
 	"net"
 	"strings"
+	"time"
 )
 
 type Authentication interface {
@@ -80,7 +83,7 @@ func (na *NtlmAuthentication) Authenticate(conn net.Conn, config *LowhttpExecCon
 	}
 	challengeHeader := GetHTTPPacketHeader(negotiationResponseByte, "WWW-Authenticate")
 	if !(len(challengeHeader) > 5 && strings.HasPrefix(challengeHeader, "NTLM ")) {
-		return nil, utils.Wrap(err, "Authenticate header non-standard ")
+		return nil, utils.Error("Authenticate header non-standard")
 	}
 	challenge, err := codec.DecodeBase64(challengeHeader[5:])
 	if err != nil {
@@ -91,6 +94,77 @@ func (na *NtlmAuthentication) Authenticate(conn net.Conn, config *LowhttpExecCon
 	return authReq, nil
 }
 
+type KerberosAuthentication struct {
+	Client *kerberos.Client
+	// Fallback 为 Kerberos 认证失败（如 KDC 不可达）时使用的认证方式，一般为 NTLM
+	Fallback Authentication
+}
+
+func (ka *KerberosAuthentication) Authenticate(conn net.Conn, config *LowhttpExecConfig) ([]byte, error) {
+	spn := kerberosSPN(config.Packet)
+	token, err := ka.Client.SPNEGOToken(spn)
+	if err != nil {
+		if ka.Fallback != nil {
+			log.Warnf("kerberos authenticate %v failed: %v, fallback to ntlm", spn, err)
+			return ka.Fallback.Authenticate(conn, config)
+		}
+		return nil, utils.Wrapf(err, "kerberos authenticate %v failed", spn)
+	}
+	return ReplaceHTTPPacketHeader(config.Packet, "Authorization", "Negotiate "+codec.EncodeBase64(token)), nil
+}
+
+// kerberosSPN 返回 HTTP 服务的 SPN，即 HTTP/<host>，不包含端口
+func kerberosSPN(packet []byte) string {
+	host := GetHTTPPacketHeader(packet, "Host")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return "HTTP/" + strings.ToLower(strings.Trim(host, "[]"))
+}
+
+var kerberosClientCache = utils.NewTTLCache[*kerberos.Client](30 * time.Minute)
+
+func useKerberos(opt *LowhttpExecConfig) bool {
+	return opt.KerberosRealm != "" || opt.KerberosKDC != "" || opt.KerberosKeytab != "" || opt.KerberosCCache != ""
+}
+
+// GetKerberosAuth 为 Negotiate 认证创建 Kerberos 认证，同一组凭据的客户端会被缓存以复用 TGT 与服务票据
+func GetKerberosAuth(username, password string, opt *LowhttpExecConfig) (*KerberosAuthentication, error) {
+	cacheKey := utils.CalcSha1(username, password, opt.KerberosRealm, opt.KerberosKDC, opt.KerberosKeytab, opt.KerberosCCache, fmt.Sprint(opt.Proxy))
+	if client, ok := kerberosClientCache.Get(cacheKey); ok {
+		return &KerberosAuthentication{Client: client}, nil
+	}
+
+	kerberosOpts := []kerberos.ClientOption{
+		kerberos.WithPassword(password),
+		kerberos.WithKDC(opt.KerberosKDC),
+		kerberos.WithProxy(opt.Proxy...),
+	}
+	if opt.ConnectTimeout > 0 {
+		kerberosOpts = append(kerberosOpts, kerberos.WithTimeout(opt.ConnectTimeout))
+	}
+	if opt.KerberosKeytab != "" {
+		keytab, err := kerberos.LoadKeytab(opt.KerberosKeytab)
+		if err != nil {
+			return nil, err
+		}
+		kerberosOpts = append(kerberosOpts, kerberos.WithKeytab(keytab))
+	}
+	if opt.KerberosCCache != "" {
+		ccache, err := kerberos.LoadCCache(opt.KerberosCCache)
+		if err != nil {
+			return nil, err
+		}
+		kerberosOpts = append(kerberosOpts, kerberos.WithCCache(ccache))
+	}
+	client, err := kerberos.NewClient(username, opt.KerberosRealm, kerberosOpts...)
+	if err != nil {
+		return nil, err
+	}
+	kerberosClientCache.Set(cacheKey, client)
+	return &KerberosAuthentication{Client: client}, nil
+}
+
 type CustomAuthClient struct {
 	handler func([]byte) ([]byte, error) //自定义认证处理函数
 }
@@ -135,12 +209,34 @@ func GetHttpAuth(authHeader string, opt *LowhttpExecConfig) Authentication {
 	authResp := strings.SplitN(authHeader, " ", 2)
 	authType := authResp[0]
 	host := GetHTTPPacketHeader(opt.Packet, "Host")
-	if opt.Username != "" || opt.Password != "" {
+	if opt.Username != "" || opt.Password != "" || useKerberos(opt) {
+		if strings.EqualFold(authType, "negotiate") && useKerberos(opt) {
+			return getKerberosAuthWithFallback(authHeader, opt.Username, opt.Password, opt)
+		}
 		return GetAuth(authHeader, opt.Username, opt.Password)
 	}
 	authInfo := consts.GetGlobalHTTPAuthInfo(host, authType)
 	if authInfo == nil {
 		return nil
 	}
+	if strings.EqualFold(authType, "negotiate") && strings.EqualFold(authInfo.AuthType, "kerberos") {
+		return getKerberosAuthWithFallback(authHeader, authInfo.AuthUsername, authInfo.AuthPassword, opt)
+	}
 	return GetAuth(authHeader, authInfo.AuthUsername, authInfo.AuthPassword)
 }
+
+func getKerberosAuthWithFallback(authHeader string, username, password string, opt *LowhttpExecConfig) Authentication {
+	fallback := GetAuth(authHeader, username, password)
+	auth, err := GetKerberosAuth(username, password, opt)
+	if err != nil {
+		log.Warnf("create kerberos authentication failed: %v", err)
+		if username == "" {
+			return nil
+		}
+		return fallback
+	}
+	if password != "" {
+		auth.Fallback = fallback
+	}
+	return auth
+}
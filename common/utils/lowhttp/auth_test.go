@@ -1,9 +1,17 @@
 package lowhttp
 
 import (
-	"github.com/yaklang/yaklang/common/utils"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/kerberos"
+	"github.com/yaklang/yaklang/common/utils/kerberos/kerberostest"
+	"github.com/yaklang/yaklang/common/yak/yaklib/codec"
 )
 
 type authCase struct {
@@ -66,3 +74,54 @@ func TestHTTPAuth(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPKerberosAuth(t *testing.T) {
+	kdc := kerberostest.NewMockKDC("example.com")
+	kdc.AddPrincipal("alice", "Passw0rd!")
+	kdc.AddPrincipal("HTTP/127.0.0.1", "service-secret")
+	kdcAddr, err := kdc.Start()
+	require.NoError(t, err)
+	defer kdc.Close()
+
+	keytab := kdc.Keytab("HTTP/127.0.0.1")
+	host, port := utils.DebugMockHTTPHandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		auth := request.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Negotiate ") {
+			token, _ := codec.DecodeBase64(auth[len("Negotiate "):])
+			if info, err := kerberos.VerifyAPReq(token, keytab); err == nil {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(info.ClientName()))
+				return
+			}
+		}
+		w.Header().Set("WWW-Authenticate", "Negotiate")
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	packet := []byte("GET / HTTP/1.1\r\nHost: " + utils.HostPort(host, port) + "\r\n\r\n")
+
+	rsp, err := HTTPWithoutRedirect(
+		WithPacketBytes(packet),
+		WithUsername("alice"), WithPassword("Passw0rd!"),
+		WithKerberosRealm("EXAMPLE.COM"), WithKerberosKDC(kdcAddr),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 200, GetStatusCodeFromResponse(rsp.RawPacket))
+	require.Equal(t, "alice@EXAMPLE.COM", string(GetHTTPPacketBody(rsp.RawPacket)))
+
+	// 使用 ccache 中的票据，不需要用户名密码
+	client, err := kerberos.NewClient("alice@EXAMPLE.COM", "", kerberos.WithPassword("Passw0rd!"), kerberos.WithKDC(kdcAddr))
+	require.NoError(t, err)
+	require.NoError(t, client.Login())
+	ccachePath := filepath.Join(t.TempDir(), "krb5cc")
+	require.NoError(t, os.WriteFile(ccachePath, client.CCache().Marshal(), 0o600))
+	rsp, err = HTTPWithoutRedirect(WithPacketBytes(packet), WithKerberosCCache(ccachePath), WithKerberosKDC(kdcAddr))
+	require.NoError(t, err)
+	require.Equal(t, 200, GetStatusCodeFromResponse(rsp.RawPacket))
+
+	rsp, err = HTTPWithoutRedirect(
+		WithPacketBytes(packet),
+		WithUsername("EXAMPLE\\alice"), WithPassword("wrong"), WithKerberosKDC(kdcAddr),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 401, GetStatusCodeFromResponse(rsp.RawPacket))
+}
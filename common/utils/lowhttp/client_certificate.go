@@ -0,0 +1,96 @@
+package lowhttp
+
+import (
+	"crypto/tls"
+	"strings"
+	"sync"
+
+	"github.com/gobwas/glob"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// ClientCertificateStore 为按名称保存的 mTLS 客户端证书仓库，由 yakit 在数据库初始化后注册
+type ClientCertificateStore interface {
+	// GetClientCertificate 按名称加载客户端证书
+	GetClientCertificate(name string) (*tls.Certificate, error)
+	// MatchClientCertificate 返回仓库中为 host 配置的证书名称，没有匹配的证书时返回空字符串
+	MatchClientCertificate(host string, port int) string
+}
+
+var (
+	clientCertificateStore      ClientCertificateStore
+	clientCertificateStoreMutex = new(sync.RWMutex)
+)
+
+func RegisterClientCertificateStore(s ClientCertificateStore) {
+	clientCertificateStoreMutex.Lock()
+	defer clientCertificateStoreMutex.Unlock()
+	clientCertificateStore = s
+}
+
+func getClientCertificateStore() ClientCertificateStore {
+	clientCertificateStoreMutex.RLock()
+	defer clientCertificateStoreMutex.RUnlock()
+	return clientCertificateStore
+}
+
+// ClientCertificateRule 指定匹配 HostPatterns 的目标使用名为 Name 的客户端证书
+type ClientCertificateRule struct {
+	Name         string
+	HostPatterns []string
+}
+
+// Match 判断目标是否匹配规则，HostPatterns 为空时匹配所有目标
+func (r *ClientCertificateRule) Match(host string, port int) bool {
+	return MatchClientCertificateHost(r.HostPatterns, host, port)
+}
+
+// MatchClientCertificateHost 使用 glob 匹配 host 或 host:port，patterns 为空时总是匹配
+func MatchClientCertificateHost(patterns []string, host string, port int) bool {
+	if len(patterns) <= 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	hostPort := utils.HostPort(host, port)
+	for _, pattern := range patterns {
+		g, err := glob.Compile(strings.ToLower(strings.TrimSpace(pattern)))
+		if err != nil {
+			log.Warnf("invalid client certificate host pattern %#v: %v", pattern, err)
+			continue
+		}
+		if g.Match(host) || g.Match(hostPort) {
+			return true
+		}
+	}
+	return false
+}
+
+// getClientCertificate 选择连接 host:port 使用的客户端证书：
+// 优先使用请求中显式指定的证书，其次使用证书仓库中按 host 配置的证书，都没有时返回空，由 netx 使用全局证书
+func (o *LowhttpExecConfig) getClientCertificate(host string, port int) (string, *tls.Certificate, error) {
+	store := getClientCertificateStore()
+	name := ""
+	for _, rule := range o.ClientCertificates {
+		if rule.Match(host, port) {
+			name = rule.Name
+			break
+		}
+	}
+	if name == "" {
+		if store == nil {
+			return "", nil, nil
+		}
+		if name = store.MatchClientCertificate(host, port); name == "" {
+			return "", nil, nil
+		}
+	}
+	if store == nil {
+		return "", nil, utils.Errorf("client certificate store is not registered, cannot load %#v", name)
+	}
+	cert, err := store.GetClientCertificate(name)
+	if err != nil {
+		return "", nil, utils.Wrapf(err, "load client certificate %#v failed", name)
+	}
+	return name, cert, nil
+}
@@ -0,0 +1,119 @@
+package lowhttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/tlsutils"
+)
+
+type testClientCertificateStore struct {
+	certs map[string]*tls.Certificate
+	rules []*ClientCertificateRule
+}
+
+func (s *testClientCertificateStore) GetClientCertificate(name string) (*tls.Certificate, error) {
+	cert, ok := s.certs[name]
+	if !ok {
+		return nil, utils.Errorf("client certificate %v not found", name)
+	}
+	return cert, nil
+}
+
+func (s *testClientCertificateStore) MatchClientCertificate(host string, port int) string {
+	for _, rule := range s.rules {
+		if rule.Match(host, port) {
+			return rule.Name
+		}
+	}
+	return ""
+}
+
+func TestClientCertificate(t *testing.T) {
+	ca, caKey, err := tlsutils.GenerateSelfSignedCertKeyWithCommonName("mtls-ca", "", nil, nil)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca))
+
+	store := &testClientCertificateStore{certs: make(map[string]*tls.Certificate)}
+	for _, name := range []string{"tenant-a", "tenant-b"} {
+		cert, key, err := tlsutils.SignClientCrtNKeyWithParams(ca, caKey, name, time.Now().Add(time.Hour), true)
+		require.NoError(t, err)
+		pair, err := tls.X509KeyPair(cert, key)
+		require.NoError(t, err)
+		store.certs[name] = &pair
+	}
+	RegisterClientCertificateStore(store)
+	defer RegisterClientCertificateStore(nil)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+	host, port, _ := utils.ParseStringToHostPort(server.URL)
+
+	request := func(opts ...LowhttpOpt) (*LowhttpResponse, error) {
+		opts = append([]LowhttpOpt{
+			WithHttps(true),
+			WithHost(host), WithPort(port),
+			WithRequest([]byte("GET / HTTP/1.1\r\nHost: www.example.com\r\n\r\n")),
+			WithTimeout(5 * time.Second),
+			WithSaveHTTPFlow(false),
+		}, opts...)
+		return HTTP(opts...)
+	}
+	assertIdentity := func(name string, rsp *LowhttpResponse, err error) {
+		require.NoError(t, err)
+		_, body := SplitHTTPPacketFast(rsp.RawPacket)
+		assert.Equal(t, name, string(body))
+		assert.Equal(t, name, rsp.ClientCertificate)
+	}
+
+	// 没有证书时服务端拒绝握手
+	_, err = request()
+	require.Error(t, err)
+
+	rsp, err := request(WithClientCertificate("tenant-a"))
+	assertIdentity("tenant-a", rsp, err)
+
+	// 按顺序匹配 host 规则
+	rsp, err = request(
+		WithClientCertificate("tenant-a", "*.example.com"),
+		WithClientCertificate("tenant-b", "127.0.0.*"),
+	)
+	assertIdentity("tenant-b", rsp, err)
+
+	// 未显式指定时使用证书仓库中按 host 配置的证书
+	store.rules = []*ClientCertificateRule{{Name: "tenant-b", HostPatterns: []string{utils.HostPort(host, port)}}}
+	rsp, err = request()
+	assertIdentity("tenant-b", rsp, err)
+	rsp, err = request(WithClientCertificate("tenant-a", "127.0.0.1"))
+	assertIdentity("tenant-a", rsp, err)
+	store.rules = nil
+
+	// 连接池中不同身份的连接不能复用
+	for _, name := range []string{"tenant-a", "tenant-b", "tenant-a"} {
+		rsp, err = request(WithConnPool(true), WithClientCertificate(name))
+		assertIdentity(name, rsp, err)
+	}
+
+	_, err = request(WithClientCertificate("tenant-c"))
+	require.Error(t, err)
+}
+
+func TestMatchClientCertificateHost(t *testing.T) {
+	assert.True(t, MatchClientCertificateHost(nil, "example.com", 443))
+	assert.True(t, MatchClientCertificateHost([]string{"*.example.com"}, "API.example.com", 443))
+	assert.True(t, MatchClientCertificateHost([]string{"*.example.com:8443"}, "api.example.com", 8443))
+	assert.False(t, MatchClientCertificateHost([]string{"*.example.com:8443"}, "api.example.com", 443))
+	assert.False(t, MatchClientCertificateHost([]string{"[invalid", "example.org"}, "example.com", 443))
+}
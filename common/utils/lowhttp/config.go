@@ -26,6 +26,7 @@ type LowhttpExecConfig struct {
 	Https                            bool
 	ResponseCallback                 func(response *LowhttpResponse)
 	Http2                            bool
+	Http3                            bool
 	GmTLS                            bool
 	OverrideEnableSystemProxyFromEnv bool
 	EnableSystemProxyFromEnv         bool
@@ -57,6 +58,15 @@ type LowhttpExecConfig struct {
 	Username                         string
 	Password                         string
 
+	// Kerberos 认证配置，设置任意一项后 Negotiate 认证使用 Kerberos 而不是 NTLM
+	KerberosRealm  string
+	KerberosKDC    string
+	KerberosKeytab string
+	KerberosCCache string
+
+	// ClientCertificates 按目标选择 mTLS 客户端证书，按顺序匹配，都不匹配时使用证书仓库中按 host 配置的证书
+	ClientCertificates []*ClientCertificateRule
+
 	// DefaultBufferSize means unexpected situation's buffer size
 	DefaultBufferSize int
 
@@ -81,6 +91,8 @@ type LowhttpResponse struct {
 	Proxy                  string
 	Https                  bool
 	Http2                  bool
+	Http3                  bool
+	ClientCertificate      string // 使用的客户端证书名称
 	RawRequest             []byte
 	Source                 string // 请求源
 	RuntimeId              string
@@ -235,6 +247,45 @@ func WithPassword(password string) LowhttpOpt {
 	}
 }
 
+// WithKerberosRealm 设置 Kerberos 域，未设置时从 user@REALM 或者 DOMAIN\user 格式的用户名中解析
+func WithKerberosRealm(realm string) LowhttpOpt {
+	return func(o *LowhttpExecConfig) {
+		o.KerberosRealm = realm
+	}
+}
+
+// WithKerberosKDC 设置 KDC 地址，未设置时通过域的 SRV 记录查找
+func WithKerberosKDC(kdc string) LowhttpOpt {
+	return func(o *LowhttpExecConfig) {
+		o.KerberosKDC = kdc
+	}
+}
+
+// WithKerberosKeytab 设置 keytab 文件路径，使用其中的密钥代替密码进行 Kerberos 认证
+func WithKerberosKeytab(path string) LowhttpOpt {
+	return func(o *LowhttpExecConfig) {
+		o.KerberosKeytab = path
+	}
+}
+
+// WithKerberosCCache 设置 ccache 文件路径，使用其中的票据进行 Kerberos 认证，此时可以不设置用户名密码
+func WithKerberosCCache(path string) LowhttpOpt {
+	return func(o *LowhttpExecConfig) {
+		o.KerberosCCache = path
+	}
+}
+
+// WithClientCertificate 使用证书仓库中名为 name 的客户端证书，hostPatterns 为空时对所有目标生效，
+// 否则只对 host 或 host:port 匹配任意一个 glob 的目标生效，可以多次使用为不同目标指定不同的证书
+func WithClientCertificate(name string, hostPatterns ...string) LowhttpOpt {
+	return func(o *LowhttpExecConfig) {
+		o.ClientCertificates = append(o.ClientCertificates, &ClientCertificateRule{
+			Name:         name,
+			HostPatterns: utils.StringArrayFilterEmpty(hostPatterns),
+		})
+	}
+}
+
 func WithSource(s string) LowhttpOpt {
 	return func(o *LowhttpExecConfig) {
 		o.RequestSource = s
@@ -318,6 +369,15 @@ func WithHttp2(Http2 bool) LowhttpOpt {
 	}
 }
 
+// WithHttp3 使用 HTTP/3（QUIC）发送请求，HTTP/3 总是使用 TLS，并且不支持代理
+// 注意：HTTP/3 的请求头由 QPACK 编码，请求报文会先被解析为 http.Request 再发送，
+// 因此原始报文中请求头的顺序、大小写以及非标准的写法都不会被保留
+func WithHttp3(Http3 bool) LowhttpOpt {
+	return func(o *LowhttpExecConfig) {
+		o.Http3 = Http3
+	}
+}
+
 func WithTimeout(timeout time.Duration) LowhttpOpt {
 	return func(o *LowhttpExecConfig) {
 		o.Timeout = timeout
@@ -639,10 +639,11 @@ type connectKey struct {
 	scheme, addr string   //协议和目标地址
 	https        bool
 	gmTls        bool
+	clientCert   string //客户端证书名称
 }
 
 func (c connectKey) hash() string {
-	return utils.CalcSha1(c.proxy, c.scheme, c.addr, c.https, c.gmTls)
+	return utils.CalcSha1(c.proxy, c.scheme, c.addr, c.https, c.gmTls, c.clientCert)
 }
 
 type connLRU struct {
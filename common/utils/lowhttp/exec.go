@@ -254,7 +254,13 @@ func HTTPWithoutRedirect(opts ...LowhttpOpt) (*LowhttpResponse, error) {
 
 	https := forceHttps
 
-	if gmTLS {
+	// HTTP/3 只能使用 TLS
+	enableHttp3 := option.Http3
+	if _, _, proto := GetHTTPPacketFirstLine(requestPacket); strings.HasPrefix(proto, "HTTP/3") {
+		enableHttp3 = true
+	}
+
+	if gmTLS || enableHttp3 {
 		https = true
 	}
 
@@ -420,6 +426,38 @@ func HTTPWithoutRedirect(opts ...LowhttpOpt) (*LowhttpResponse, error) {
 	response.RawRequest = requestPacket
 	response.Http2 = enableHttp2
 
+	var clientCert *tls.Certificate
+	if https || enableHttp3 {
+		response.ClientCertificate, clientCert, err = option.getClientCertificate(host, port)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if enableHttp3 {
+		if len(proxy) > 0 {
+			log.Warnf("http3(quic) does not support proxy, ignore proxy: %v", proxy)
+		}
+		if option.BeforeDoRequest != nil {
+			requestPacket = option.BeforeDoRequest(requestPacket)
+			response.RawRequest = requestPacket
+		}
+		if option.NativeHTTPRequestInstance != nil {
+			httpctx.SetRequestHTTPS(option.NativeHTTPRequestInstance, true)
+			httpctx.SetBareRequestBytes(option.NativeHTTPRequestInstance, requestPacket)
+		}
+		response.Https = true
+		response.Http2 = false
+		response.Http3 = true
+		responsePacket, err := doHTTP3Request(ctx, option, host, port, urlIns, requestPacket, response, clientCert)
+		if err != nil {
+			return response, err
+		}
+		httpctx.SetBareResponseBytes(option.NativeHTTPRequestInstance, responsePacket)
+		response.RawPacket = responsePacket
+		return response, nil
+	}
+
 	// https://github.com/mattn/go-ieproxy
 	var (
 		conn                 net.Conn
@@ -471,6 +509,9 @@ func HTTPWithoutRedirect(opts ...LowhttpOpt) (*LowhttpResponse, error) {
 			}))
 		}
 		dialopts = append(dialopts, netx.DialX_WithGMTLSSupport(gmTLS), netx.DialX_WithTLS(https))
+		if clientCert != nil {
+			dialopts = append(dialopts, netx.DialX_WithClientCertificates(*clientCert))
+		}
 	}
 
 	if forceProxy {
@@ -512,6 +553,8 @@ func HTTPWithoutRedirect(opts ...LowhttpOpt) (*LowhttpResponse, error) {
 		addr:   originAddr,
 		https:  option.Https,
 		gmTls:  option.GmTLS,
+		// 不同身份的连接不能复用
+		clientCert: response.ClientCertificate,
 	}
 	haveNativeHTTPRequestInstance := option.NativeHTTPRequestInstance != nil
 RECONNECT:
@@ -576,6 +619,7 @@ RECONNECT:
 
 	if enableHttp2 && conn.(*persistConn).cacheKey.scheme != H2 {
 		enableHttp2 = false
+		response.Http2 = false
 		method, uri, _ := GetHTTPPacketFirstLine(requestPacket)
 		requestPacket = ReplaceHTTPPacketFirstLine(requestPacket, strings.Join([]string{method, uri, "HTTP/1.1"}, " "))
 	}
@@ -603,6 +647,7 @@ RECONNECT:
 		}
 
 		h2Stream := h2Conn.newStream(option.NativeHTTPRequestInstance, requestPacket)
+		h2Stream.noFixContentLength = noFixContentLength
 
 		if err := h2Stream.doRequest(); err != nil {
 			if h2Stream.ID == 1 { // first stream
@@ -614,6 +659,10 @@ RECONNECT:
 		}
 		resp, responsePacket := h2Stream.waitResponse(timeout)
 		_ = resp
+		if len(responsePacket) <= 0 {
+			// 超时或连接关闭前没有收到响应头
+			return response, utils.Errorf("h2 stream-id %v read response failed: no response headers", h2Stream.ID)
+		}
 		httpctx.SetBareResponseBytes(option.NativeHTTPRequestInstance, responsePacket)
 		response.RawPacket = responsePacket
 		return response, nil
@@ -61,6 +61,9 @@ type http2ClientStream struct {
 	req       *http.Request
 	reqPacket []byte
 
+	// 保留请求中的 Content-Length 与 Transfer-Encoding，用于 H2 降级走私测试
+	noFixContentLength bool
+
 	resp       *http.Response
 	bodyBuffer *bytes.Buffer
 	respPacket []byte
@@ -170,9 +173,11 @@ func (h2Conn *http2ClientConn) newStream(req *http.Request, packet []byte) *http
 	cs.sentHeaders = false
 	cs.sentEndStream = false
 	cs.readEndStream = false
+	cs.readHeaderEnd = false
 	cs.readEndStreamSignal = make(chan struct{}, 1)
 	cs.req = req
 	cs.reqPacket = packet
+	cs.noFixContentLength = false
 	cs.resp.Header = make(http.Header) // init header
 
 	h2Conn.mu.Lock()
@@ -304,8 +309,11 @@ func (cs *http2ClientStream) doRequest() error {
 					}
 				}
 
-			case "content-length", "connection", "proxy-connection", //todo cl问题是否处理
-				"transfer-encoding", "upgrade",
+			case "content-length", "transfer-encoding":
+				if cs.noFixContentLength {
+					addH2Header(key, value)
+				}
+			case "connection", "proxy-connection", "upgrade",
 				"keep-alive": // H2不应该存在的头
 			default:
 				addH2Header(key, value)
@@ -405,8 +413,13 @@ func (cs *http2ClientStream) waitResponse(timeout time.Duration) (http.Response,
 	case <-closeFlag:
 		log.Errorf("h2 stream-id %v wait response conn closed : %s", cs.ID, flow)
 	}
-	cs.resp.Body = io.NopCloser(cs.bodyBuffer)
-	cs.respPacket, _ = utils.DumpHTTPResponse(cs.resp, len(cs.bodyBuffer.Bytes()) > 0)
+	if cs.readHeaderEnd {
+		cs.resp.Body = io.NopCloser(cs.bodyBuffer)
+		cs.respPacket, _ = utils.DumpHTTPResponse(cs.resp, len(cs.bodyBuffer.Bytes()) > 0)
+	} else {
+		// 没有收到响应头时不构造响应包
+		cs.respPacket = nil
+	}
 	cs.h2Conn.mu.Lock()
 	cs.h2Conn.streams[cs.ID] = nil
 	cs.h2Conn.mu.Unlock()
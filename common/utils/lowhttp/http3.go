@@ -0,0 +1,127 @@
+package lowhttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/netx"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp/httpctx"
+)
+
+// H3 是 HTTP/3 的 ALPN
+const H3 = http3.NextProtoH3
+
+// doHTTP3Request 把原始请求报文通过 QUIC 发送，每次请求使用新的 QUIC 连接。
+// 返回的响应报文的协议为 HTTP/3.0
+//
+// quic-go 的 http3.RoundTripper 只接受 http.Request，请求头会经由 http.Header 交给 QPACK 编码，
+// 所以原始报文中请求头的顺序与大小写都会丢失，
+// 实际发送的请求与 requestPacket 并不是逐字节一致的
+func doHTTP3Request(ctx context.Context, option *LowhttpExecConfig, host string, port int, urlIns *url.URL, requestPacket []byte, response *LowhttpResponse, clientCert *tls.Certificate) ([]byte, error) {
+	traceInfo := response.TraceInfo
+	req, err := ParseBytesToHttpRequest(requestPacket)
+	if err != nil {
+		return nil, utils.Wrapf(err, "parse request for http3 failed")
+	}
+	u := *urlIns
+	u.Scheme = "https"
+	req.URL = &u
+	req.RequestURI = ""
+	if req.Host == "" {
+		req.Host = u.Host
+	}
+
+	timeout := option.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	ip := host
+	if net.ParseIP(utils.FixForParseIP(host)) == nil {
+		dnsStart := time.Now()
+		ip = netx.LookupFirst(
+			host,
+			netx.WithTimeout(option.ConnectTimeout),
+			netx.WithDNSServers(option.DNSServers...),
+			netx.WithTemporaryHosts(option.EtcHosts),
+		)
+		traceInfo.DNSTime = time.Since(dnsStart)
+		if ip == "" {
+			return nil, utils.Errorf("cannot resolve %s", host)
+		}
+	}
+	addr := utils.HostPort(ip, port)
+
+	var (
+		connStart   time.Time
+		requestSent time.Time
+	)
+	tlsConfig := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: !option.VerifyCertificate,
+		NextProtos:         []string{H3},
+		MinVersion:         tls.VersionTLS13,
+	}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	roundTripper := &http3.RoundTripper{
+		DisableCompression: true,
+		TLSClientConfig:    tlsConfig,
+		QuicConfig: &quic.Config{
+			HandshakeIdleTimeout: option.ConnectTimeout,
+			MaxIdleTimeout:       timeout,
+		},
+		Dial: func(ctx context.Context, _ string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+			connStart = time.Now()
+			conn, err := quic.DialAddrEarly(ctx, addr, tlsCfg, cfg)
+			if err != nil {
+				return nil, err
+			}
+			traceInfo.ConnTime = time.Since(connStart)
+			response.RemoteAddr = conn.RemoteAddr().String()
+			requestSent = time.Now()
+			return conn, nil
+		},
+	}
+	defer roundTripper.Close()
+
+	rsp, err := roundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, utils.Wrapf(err, "http3 request %s failed", addr)
+	}
+	defer rsp.Body.Close()
+	if !requestSent.IsZero() {
+		traceInfo.ServerTime = time.Since(requestSent)
+	}
+	response.PortIsOpen = true
+	if option.NativeHTTPRequestInstance != nil {
+		httpctx.SetRemoteAddr(option.NativeHTTPRequestInstance, response.RemoteAddr)
+	}
+
+	var bodyReader io.Reader = rsp.Body
+	if option.ResponseBodyMirrorWriter != nil {
+		bodyReader = io.TeeReader(bodyReader, option.ResponseBodyMirrorWriter)
+	}
+	body, err := io.ReadAll(bodyReader)
+	if err != nil && len(body) <= 0 {
+		return nil, utils.Wrapf(err, "read http3 response body failed")
+	} else if err != nil {
+		log.Warnf("read http3 response body failed: %v", err)
+	}
+	response.ResponseBodySize = int64(len(body))
+	rsp.Body = io.NopCloser(bytes.NewReader(body))
+	return utils.DumpHTTPResponse(rsp, true)
+}
@@ -0,0 +1,73 @@
+package lowhttp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/tlsutils"
+)
+
+func debugMockHTTP3(t *testing.T, handler http.HandlerFunc) (string, int) {
+	crt, key, err := tlsutils.GenerateSelfSignedCertKey("127.0.0.1", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(crt, key)
+	require.NoError(t, err)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &http3.Server{
+		Handler:   handler,
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}}),
+	}
+	go server.Serve(conn)
+	t.Cleanup(func() {
+		server.Close()
+		conn.Close()
+	})
+	host, port, _ := utils.ParseStringToHostPort(conn.LocalAddr().String())
+	return host, port
+}
+
+func TestHTTP3(t *testing.T) {
+	token := utils.RandStringBytes(16)
+	host, port := debugMockHTTP3(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Proto", r.Proto)
+		w.Header().Set("X-Host", r.Host)
+		w.WriteHeader(201)
+		w.Write([]byte(token + string(body)))
+	})
+
+	packet := fmt.Sprintf(`POST /abc?a=1 HTTP/1.1
+Host: %s
+Content-Type: text/plain
+
+hello`, utils.HostPort(host, port))
+	rsp, err := HTTP(WithPacketBytes([]byte(packet)), WithHttp3(true), WithTimeoutFloat(5), WithConnectTimeoutFloat(5))
+	require.NoError(t, err)
+	assert.True(t, rsp.Http3)
+	assert.True(t, rsp.Https)
+	assert.Equal(t, "https://"+utils.HostPort(host, port)+"/abc?a=1", rsp.Url)
+
+	proto, code, _ := GetHTTPPacketFirstLine(rsp.RawPacket)
+	assert.Equal(t, "HTTP/3.0", proto)
+	assert.Equal(t, "201", code)
+	assert.Equal(t, "HTTP/3.0", GetHTTPPacketHeader(rsp.RawPacket, "X-Proto"))
+	assert.Equal(t, utils.HostPort(host, port), GetHTTPPacketHeader(rsp.RawPacket, "X-Host"))
+	assert.Equal(t, token+"hello", string(GetHTTPPacketBody(rsp.RawPacket)))
+
+	// 请求行中的 HTTP/3 也会使用 QUIC 发送
+	packet = fmt.Sprintf("GET / HTTP/3\r\nHost: %s\r\n\r\n", utils.HostPort(host, port))
+	rsp, err = HTTP(WithPacketBytes([]byte(packet)), WithTimeoutFloat(5), WithConnectTimeoutFloat(5))
+	require.NoError(t, err)
+	assert.True(t, rsp.Http3)
+	assert.Equal(t, token, string(GetHTTPPacketBody(rsp.RawPacket)))
+}
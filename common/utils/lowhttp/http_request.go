@@ -118,11 +118,12 @@ func FixHTTPPacketCRLF(raw []byte, noFixLength bool) []byte {
 	if handleChunked {
 		// chunked body is very complex
 		// if multiRequest: extract and remove body suffix
-		var bodyDecode []byte
-		bodyDecode, restBody = codec.HTTPChunkedDecodeWithRestBytes(body)
-		if len(bodyDecode) > 0 {
-			readLen := len(body) - len(restBody)
-			body = body[:readLen]
+		_, restBody = codec.HTTPChunkedDecodeWithRestBytes(body)
+		if len(restBody) > 0 && bytes.HasSuffix(body, restBody) {
+			body = body[:len(body)-len(restBody)]
+		} else {
+			// chunked 不完整时 restBody 并非 body 的后缀，保持原样
+			restBody = nil
 		}
 	}
 
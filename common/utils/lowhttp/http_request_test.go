@@ -15,6 +15,7 @@ import (
 	"github.com/yaklang/yaklang/common/yak/yaklib/codec"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/stretchr/testify/assert"
 )
 
 const _multipartDemo = `
@@ -558,6 +559,33 @@ abcdadasdfabcdadasdfabcdadasdfabcdadasdfabcdadasdf`), "Transfer-Encoding", "chun
 	}
 }
 
+func TestFixHTTPPacketCRLF_ChunkedWithPipelinedRequest(t *testing.T) {
+	header := "POST / HTTP/1.1\r\nHost: www.example.com\r\nTransfer-Encoding: chunked\r\n\r\n"
+	body := "1\r\na\r\n0\r\n\r\n"
+	next := "GET /next HTTP/1.1\r\nHost: www.example.com\r\n\r\n"
+	for _, noFixLength := range []bool{true, false} {
+		// 完整的 chunked body 之后的请求原样保留在末尾
+		raw := FixHTTPPacketCRLF([]byte(header+body+next), noFixLength)
+		assert.Equal(t, header+body+next, string(raw), "noFixLength: %v", noFixLength)
+	}
+}
+
+func TestFixHTTPPacketCRLF_TruncatedChunked(t *testing.T) {
+	header := "POST / HTTP/1.1\r\nHost: www.example.com\r\nContent-Length: 4\r\nTransfer-Encoding: chunked\r\n\r\n"
+	for _, body := range []string{
+		"1\r\nA",
+		"1\r\nA\r\nX",
+		"5\r\nab",
+	} {
+		// 不完整的 chunked body 不能被截断或重复
+		raw := FixHTTPPacketCRLF([]byte(header+body), true)
+		assert.Equal(t, header+body, string(raw))
+
+		raw = FixHTTPPacketCRLF([]byte(strings.Replace(header, "Content-Length: 4\r\n", "", 1)+body), true)
+		assert.True(t, strings.HasSuffix(string(raw), "\r\n\r\n"+body), "%q", raw)
+	}
+}
+
 func TestHTTPPacketCRLF_EmptyResult(t *testing.T) {
 	var as = FixHTTPPacketCRLF(nil, true)
 	spew.Dump(as)
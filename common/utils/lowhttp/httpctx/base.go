@@ -193,6 +193,15 @@ func GetRemoteAddr(r *http.Request) string {
 	return r.RemoteAddr
 }
 
+func SetClientCertificate(r *http.Request, name string) {
+	SetContextValueInfoFromRequest(r, REQUEST_CONTEXT_KEY_ClientCertificate, name)
+}
+
+// GetClientCertificate 获取转发请求时使用的 mTLS 客户端证书名称
+func GetClientCertificate(r *http.Request) string {
+	return GetContextStringInfoFromRequest(r, REQUEST_CONTEXT_KEY_ClientCertificate)
+}
+
 func GetPlainRequestBytes(r *http.Request) []byte {
 	return []byte(GetContextStringInfoFromRequest(r, REQUEST_CONTEXT_KEY_RequestPlainBytes))
 }
@@ -252,6 +261,7 @@ const (
 	REQUEST_CONTEXT_KEY_ConnectedToPort              = "connectedToPort"
 	REQUEST_CONTEXT_KEY_ConnectedToHost              = "connectedToHost"
 	REQUEST_CONTEXT_KEY_RemoteAddr                   = "remoteAddr"
+	REQUEST_CONTEXT_KEY_ClientCertificate            = "clientCertificate"
 	REQUEST_CONTEXT_KEY_ViaConnect                   = "viaConnect"
 	REQUEST_CONTEXT_KEY_ResponseHeaderCallback       = "responseHeaderCallback"
 	REQUEST_CONTEXT_KEY_ResponseHeaderWriter         = "responseHeaderWriter"
@@ -34,6 +34,7 @@ type PocConfig struct {
 	Port                 int
 	ForceHttps           bool
 	ForceHttp2           bool
+	ForceHttp3           bool
 	Timeout              time.Duration
 	ConnectTimeout       time.Duration
 	RetryTimes           int
@@ -53,6 +54,10 @@ type PocConfig struct {
 	Source               string
 	Username             string
 	Password             string
+	KerberosRealm        string
+	KerberosKDC          string
+	KerberosKeytab       string
+	KerberosCCache       string
 
 	// packetHandler
 	PacketHandler []func([]byte) []byte
@@ -78,6 +83,9 @@ type PocConfig struct {
 
 	DNSServers []string
 	DNSNoCache bool
+
+	// mTLS 客户端证书
+	ClientCertificates []*lowhttp.ClientCertificateRule
 }
 
 func (c *PocConfig) ToLowhttpOptions() []lowhttp.LowhttpOpt {
@@ -90,6 +98,7 @@ func (c *PocConfig) ToLowhttpOptions() []lowhttp.LowhttpOpt {
 	}
 	opts = append(opts, lowhttp.WithHttps(c.ForceHttps))
 	opts = append(opts, lowhttp.WithHttp2(c.ForceHttp2))
+	opts = append(opts, lowhttp.WithHttp3(c.ForceHttp3))
 	if c.Timeout > 0 {
 		opts = append(opts, lowhttp.WithTimeout(c.Timeout))
 	}
@@ -151,9 +160,16 @@ func (c *PocConfig) ToLowhttpOptions() []lowhttp.LowhttpOpt {
 		opts = append(opts, lowhttp.WithDNSServers(c.DNSServers))
 	}
 	opts = append(opts, lowhttp.WithDNSNoCache(c.DNSNoCache))
+	for _, rule := range c.ClientCertificates {
+		opts = append(opts, lowhttp.WithClientCertificate(rule.Name, rule.HostPatterns...))
+	}
 
 	opts = append(opts, lowhttp.WithUsername(c.Username))
 	opts = append(opts, lowhttp.WithPassword(c.Password))
+	opts = append(opts, lowhttp.WithKerberosRealm(c.KerberosRealm))
+	opts = append(opts, lowhttp.WithKerberosKDC(c.KerberosKDC))
+	opts = append(opts, lowhttp.WithKerberosKeytab(c.KerberosKeytab))
+	opts = append(opts, lowhttp.WithKerberosCCache(c.KerberosCCache))
 	return opts
 }
 
@@ -346,6 +362,18 @@ func WithForceHTTP2(isHttp2 bool) PocConfigOption {
 	}
 }
 
+// http3 是一个请求选项参数，用于指定是否使用 http3（QUIC）协议，默认为 false。http3 总是使用 TLS，并且不支持代理
+// 使用 http3 时请求头会按照 http.Header 重新编码，原始报文中请求头的顺序与大小写不会被保留
+// Example:
+// ```
+// poc.Get("https://www.example.com", poc.http3(true)) // 向 www.example.com 发起请求，使用 http3 协议
+// ```
+func WithForceHTTP3(isHttp3 bool) PocConfigOption {
+	return func(c *PocConfig) {
+		c.ForceHttp3 = isHttp3
+	}
+}
+
 // timeout 是一个请求选项参数，用于指定读取超时时间，默认为15秒
 // Example:
 // ```
@@ -577,6 +605,92 @@ func WithDNSNoCache(b bool) PocConfigOption {
 	}
 }
 
+// clientCertificate 是一个请求选项参数，用于指定请求使用证书仓库中名为 name 的 mTLS 客户端证书，
+// 可以传入 host 的 glob 匹配规则，只对匹配的目标使用该证书，多次使用可以为不同的目标指定不同的证书
+// Example:
+// ```
+// // 向 example.com 发起请求，服务端要求客户端证书时使用名为 tenant-a 的证书
+// poc.Get("https://example.com", poc.clientCertificate("tenant-a"))
+// // 访问 *.tenant-b.com 时使用 tenant-b 证书，其他目标使用 tenant-a 证书
+// poc.Get("https://api.tenant-b.com", poc.clientCertificate("tenant-b", "*.tenant-b.com"), poc.clientCertificate("tenant-a"))
+// ```
+func WithClientCertificate(name string, hostPatterns ...string) PocConfigOption {
+	return func(c *PocConfig) {
+		c.ClientCertificates = append(c.ClientCertificates, &lowhttp.ClientCertificateRule{
+			Name:         name,
+			HostPatterns: utils.StringArrayFilterEmpty(hostPatterns),
+		})
+	}
+}
+
+// username 是一个请求选项参数，用于指定服务端要求 NTLM / Negotiate 等认证时使用的用户名，
+// 可以使用 user@REALM 或者 DOMAIN\user 格式携带域
+// Example:
+// ```
+// poc.Get("http://example.com", poc.username("CORP\\alice"), poc.password("Passw0rd!"))
+// ```
+func WithUsername(username string) PocConfigOption {
+	return func(c *PocConfig) {
+		c.Username = username
+	}
+}
+
+// password 是一个请求选项参数，用于指定服务端要求 NTLM / Negotiate 等认证时使用的密码
+// Example:
+// ```
+// poc.Get("http://example.com", poc.username("CORP\\alice"), poc.password("Passw0rd!"))
+// ```
+func WithPassword(password string) PocConfigOption {
+	return func(c *PocConfig) {
+		c.Password = password
+	}
+}
+
+// kerberosRealm 是一个请求选项参数，用于指定 Negotiate 认证使用的 Kerberos 域，
+// 设置任意 kerberos 选项后，服务端要求 Negotiate 认证时会优先使用 Kerberos，失败时回退到 NTLM
+// Example:
+// ```
+// poc.Get("http://intranet.corp.example.com", poc.username("alice"), poc.password("Passw0rd!"), poc.kerberosRealm("CORP.EXAMPLE.COM"))
+// ```
+func WithKerberosRealm(realm string) PocConfigOption {
+	return func(c *PocConfig) {
+		c.KerberosRealm = realm
+	}
+}
+
+// kerberosKDC 是一个请求选项参数，用于指定 KDC 地址，未设置时通过域的 SRV 记录查找
+// Example:
+// ```
+// poc.Get("http://intranet.corp.example.com", poc.username("alice@CORP.EXAMPLE.COM"), poc.password("Passw0rd!"), poc.kerberosKDC("10.0.0.1:88"))
+// ```
+func WithKerberosKDC(kdc string) PocConfigOption {
+	return func(c *PocConfig) {
+		c.KerberosKDC = kdc
+	}
+}
+
+// kerberosKeytab 是一个请求选项参数，用于指定 keytab 文件路径，使用其中的密钥代替密码进行 Kerberos 认证
+// Example:
+// ```
+// poc.Get("http://intranet.corp.example.com", poc.username("alice@CORP.EXAMPLE.COM"), poc.kerberosKeytab("/tmp/alice.keytab"))
+// ```
+func WithKerberosKeytab(path string) PocConfigOption {
+	return func(c *PocConfig) {
+		c.KerberosKeytab = path
+	}
+}
+
+// kerberosCCache 是一个请求选项参数，用于指定 ccache 文件路径，使用其中的票据进行 Kerberos 认证，此时可以不设置用户名密码
+// Example:
+// ```
+// poc.Get("http://intranet.corp.example.com", poc.kerberosCCache("/tmp/krb5cc_1000"))
+// ```
+func WithKerberosCCache(path string) PocConfigOption {
+	return func(c *PocConfig) {
+		c.KerberosCCache = path
+	}
+}
+
 // replaceFirstLine 是一个请求选项参数，用于改变请求报文，修改第一行（即请求方法，请求路径，协议版本）
 // Example:
 // ```
@@ -1475,6 +1589,7 @@ var PoCExports = map[string]interface{}{
 	"redirectHandler":      WithRedirectHandler,
 	"https":                WithForceHTTPS,
 	"http2":                WithForceHTTP2,
+	"http3":                WithForceHTTP3,
 	"params":               WithParams,
 	"proxy":                WithProxy,
 	"timeout":              WithTimeout,
@@ -1483,6 +1598,13 @@ var PoCExports = map[string]interface{}{
 	"connectTimeout":       WithConnectTimeout,
 	"dnsServer":            WithDNSServers,
 	"dnsNoCache":           WithDNSNoCache,
+	"clientCertificate":    WithClientCertificate,
+	"username":             WithUsername,
+	"password":             WithPassword,
+	"kerberosRealm":        WithKerberosRealm,
+	"kerberosKDC":          WithKerberosKDC,
+	"kerberosKeytab":       WithKerberosKeytab,
+	"kerberosCCache":       WithKerberosCCache,
 	"noFixContentLength":   WithNoFixContentLength,
 	"session":              WithSession,
 	"save":                 WithSave,
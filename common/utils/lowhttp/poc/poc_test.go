@@ -3,10 +3,16 @@ package poc
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/kerberos"
+	"github.com/yaklang/yaklang/common/utils/kerberos/kerberostest"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/yak/yaklib/codec"
 )
 
 func TestDoWithAutoHTTPS(t *testing.T) {
@@ -27,3 +33,35 @@ func TestDoWithAutoHTTPS(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDoWithKerberos(t *testing.T) {
+	kdc := kerberostest.NewMockKDC("example.com")
+	kdc.AddPrincipal("alice", "Passw0rd!")
+	kdc.AddPrincipal("HTTP/127.0.0.1", "service-secret")
+	kdcAddr, err := kdc.Start()
+	require.NoError(t, err)
+	defer kdc.Close()
+
+	keytab := kdc.Keytab("HTTP/127.0.0.1")
+	host, port := utils.DebugMockHTTPHandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		auth := request.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Negotiate ") {
+			token, _ := codec.DecodeBase64(auth[len("Negotiate "):])
+			if info, err := kerberos.VerifyAPReq(token, keytab); err == nil {
+				w.Write([]byte(info.ClientName()))
+				return
+			}
+		}
+		w.Header().Set("WWW-Authenticate", "Negotiate")
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	rsp, _, err := DoGET(
+		fmt.Sprintf("http://%s", utils.HostPort(host, port)),
+		WithUsername("alice"), WithPassword("Passw0rd!"),
+		WithKerberosRealm("example.com"), WithKerberosKDC(kdcAddr),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 200, lowhttp.GetStatusCodeFromResponse(rsp.RawPacket))
+	require.Equal(t, "alice@EXAMPLE.COM", string(lowhttp.GetHTTPPacketBody(rsp.RawPacket)))
+}
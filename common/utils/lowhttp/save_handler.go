@@ -6,13 +6,13 @@ import (
 	"sync"
 )
 
-type saveHTTPFlowHandler func(https bool, req []byte, rsp []byte, url string, remoteAddr string, reqSource string, runtimeId string, fromPlugin string)
+type saveHTTPFlowHandler func(https bool, req []byte, rsp []byte, url string, remoteAddr string, reqSource string, runtimeId string, fromPlugin string, clientCertificate string)
 
 var saveHTTPFlowFunc saveHTTPFlowHandler
 
 func RegisterSaveHTTPFlowHandler(h saveHTTPFlowHandler) {
 	m := new(sync.Mutex)
-	saveHTTPFlowFunc = func(https bool, req []byte, rsp []byte, url string, remoteAddr string, reqSource string, runtimeId string, fromPlugin string) {
+	saveHTTPFlowFunc = func(https bool, req []byte, rsp []byte, url string, remoteAddr string, reqSource string, runtimeId string, fromPlugin string, clientCertificate string) {
 		m.Lock()
 		defer m.Unlock()
 
@@ -21,7 +21,7 @@ func RegisterSaveHTTPFlowHandler(h saveHTTPFlowHandler) {
 				log.Errorf("call lowhttp.saveHTTPFlowFunc panic: %s", err)
 			}
 		}()
-		h(https, req, rsp, url, remoteAddr, reqSource, runtimeId, fromPlugin)
+		h(https, req, rsp, url, remoteAddr, reqSource, runtimeId, fromPlugin, clientCertificate)
 	}
 }
 func SaveResponse(r *LowhttpResponse) {
@@ -35,5 +35,5 @@ func SaveResponse(r *LowhttpResponse) {
 	if r.TooLarge {
 		rawPacket = ReplaceHTTPPacketBodyFast(rawPacket, []byte(`[[response too large(`+utils.ByteSize(uint64(r.TooLargeLimit))+`), truncated]] find more in web fuzzer history!`))
 	}
-	saveHTTPFlowFunc(r.Https, r.RawRequest, rawPacket, r.Url, r.RemoteAddr, r.Source, r.RuntimeId, r.FromPlugin)
+	saveHTTPFlowFunc(r.Https, r.RawRequest, rawPacket, r.Url, r.RemoteAddr, r.Source, r.RuntimeId, r.FromPlugin, r.ClientCertificate)
 }
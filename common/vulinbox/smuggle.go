@@ -211,7 +211,7 @@ Content-Type: text/html; charset=utf-8
 		}
 		return proxied.Bytes(), nil
 	}
-	handleRequest := func(ret []byte, writer *bufio.Writer) {
+	handleRequest := func(ret []byte, reader *bufio.Reader, writer *bufio.Writer) {
 		for {
 			chunked := false
 			_, body := lowhttp.SplitHTTPPacket(ret, func(method string, requestUri string, proto string) error {
@@ -223,6 +223,11 @@ Content-Type: text/html; charset=utf-8
 				}
 				return line
 			})
+			if chunked && len(body) > 0 && !bytes.Contains(body, []byte("0\r\n\r\n")) {
+				// 代理只转发了 Content-Length 长度的部分 chunk，真实服务器会一直等待剩余的 chunk，直到连接关闭
+				_, _ = io.Copy(io.Discard, reader)
+				return
+			}
 			_, pth, _ := lowhttp.GetHTTPPacketFirstLine(ret)
 			switch pth {
 			case "/":
@@ -267,7 +272,7 @@ Content-Type: text/html; charset=utf-8
 			}
 			fmt.Println(string(proxies))
 			spew.Dump(proxies)
-			handleRequest(proxies, bw)
+			handleRequest(proxies, br, bw)
 			utils.FlushWriter(bw)
 			println("=====================================")
 		}()
@@ -6,6 +6,9 @@ import (
 
 func (b *astbuilder) SetRange(token antlr4util.CanStartStopToken) func() {
 	r := antlr4util.GetRange(token)
+	if r != nil {
+		r.FileName = b.GetProgram().GetCurrentFile()
+	}
 	backup := b.CurrentRange
 	b.CurrentRange = r
 
@@ -1309,6 +1309,8 @@ func main() {
 		yakcmds.PcapCommand,
 		yakcmds.SuricataLoaderCommand,
 		yakcmds.ChaosMakerCommand,
+		yakcmds.SyntaxFlowCommand,
+		yakcmds.RiskExportCommand,
 
 		// chaosmaker
 		{
@@ -0,0 +1,116 @@
+package yakcmds
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jinzhu/gorm"
+	"github.com/urfave/cli"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+var RiskExportCommand = cli.Command{
+	Name:    "export-risks",
+	Aliases: []string{"risk-export"},
+	Usage:   "yak export-risks --format sarif [--project-db project.db] [--severity high,critical] [--fail-on high] [-o risks.sarif]",
+	Description: "Export risks in a project database as SARIF 2.1.0, JUnit XML or DefectDojo Generic Findings JSON, " +
+		"exit with non-zero code when --fail-on is set and risks reach the severity",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format,f",
+			Value: yakit.RiskExportFormatSARIF,
+			Usage: "output format: sarif / junit / defectdojo",
+		},
+		cli.StringFlag{
+			Name:  "project-db,db",
+			Usage: "project database file, default current project database",
+		},
+		cli.StringFlag{
+			Name:  "output,o",
+			Usage: "output file, default stdout",
+		},
+		cli.StringFlag{
+			Name:  "search",
+			Usage: "search keyword in ip / url / title / payload ...",
+		},
+		cli.StringFlag{
+			Name:  "severity",
+			Usage: "filter by severity, split by comma: critical,high,warning,low,info",
+		},
+		cli.StringFlag{
+			Name:  "type",
+			Usage: "filter by risk type, split by comma",
+		},
+		cli.StringSliceFlag{
+			Name:  "triage-status",
+			Usage: "filter by triage status, can be used multiple times: new / confirmed / false_positive / fixed / reopened",
+		},
+		cli.Float64Flag{
+			Name:  "min-cvss",
+			Usage: "only export risks whose cvss score is not less than it",
+		},
+		cli.StringFlag{
+			Name:  "fail-on",
+			Usage: "severity for failed testcases (junit) and ci gate, exit 1 when any risk reaches it",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		db := consts.GetGormProjectDatabase()
+		if dbFile := c.String("project-db"); dbFile != "" {
+			if utils.GetFirstExistedFile(dbFile) == "" {
+				return utils.Errorf("project database not existed: %v", dbFile)
+			}
+			projectDB, err := gorm.Open("sqlite3", dbFile)
+			if err != nil {
+				return utils.Errorf("open project database failed: %s", err)
+			}
+			defer projectDB.Close()
+			// 导出只读取数据库，旧版本缺少研判 / CVSS 等字段时直接报错，不做迁移
+			if err := yakit.CheckRiskExportSchema(projectDB); err != nil {
+				return err
+			}
+			db = projectDB
+		}
+		if db == nil {
+			return utils.Error("no project database")
+		}
+
+		risks, err := yakit.QueryRisksForExport(db, context.Background(), &ypb.QueryRisksRequest{
+			Search:       c.String("search"),
+			Severity:     c.String("severity"),
+			RiskType:     c.String("type"),
+			TriageStatus: c.StringSlice("triage-status"),
+			MinCVSSScore: c.Float64("min-cvss"),
+		}, nil)
+		if err != nil {
+			return err
+		}
+		raw, failures, err := yakit.ExportRisks(risks, &yakit.RiskExportConfig{
+			Format:       c.String("format"),
+			FailSeverity: c.String("fail-on"),
+			ToolVersion:  consts.GetYakVersion(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if output := c.String("output"); output != "" {
+			if err := os.WriteFile(output, raw, 0o644); err != nil {
+				return err
+			}
+			log.Infof("%d risks saved to %s", len(risks), output)
+		} else {
+			fmt.Println(string(raw))
+		}
+
+		if c.String("fail-on") != "" && failures > 0 {
+			return cli.NewExitError(fmt.Sprintf("%d/%d risks reach severity %v", failures, len(risks), c.String("fail-on")), 1)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,115 @@
+package yakcmds
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yak/ssaapi"
+)
+
+var SyntaxFlowCommand = cli.Command{
+	Name:    "syntaxflow",
+	Aliases: []string{"sf"},
+	Usage:   "yak syntaxflow -t ./project -l php [-r rules] [--format sarif] [-o report.sarif]",
+	Description: "Scan a project with SyntaxFlow rules (builtin rules and rules from --rule), " +
+		"output the results as json or sarif",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "target,t",
+			Usage: "project directory to scan",
+		},
+		cli.StringFlag{
+			Name:  "language,l",
+			Value: string(ssaapi.Yak),
+			Usage: "language of the project: yak / js / php",
+		},
+		cli.StringSliceFlag{
+			Name:  "rule,r",
+			Usage: "extra rule file or directory (*.yaml / *.yml), can be used multiple times",
+		},
+		cli.BoolFlag{
+			Name:  "no-builtin",
+			Usage: "do not load builtin rules",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "json",
+			Usage: "output format: json / sarif",
+		},
+		cli.StringFlag{
+			Name:  "output,o",
+			Usage: "output file, default stdout",
+		},
+		cli.BoolFlag{
+			Name:  "self-test",
+			Usage: "run the test cases in rules instead of scanning",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		var rules []*ssaapi.SyntaxFlowRule
+		if !c.Bool("no-builtin") {
+			rules = append(rules, ssaapi.GetBuiltinSyntaxFlowRules()...)
+		}
+		for _, p := range c.StringSlice("rule") {
+			ret, err := ssaapi.LoadSyntaxFlowRules(p)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, ret...)
+		}
+		if len(rules) == 0 {
+			return utils.Errorf("no syntaxflow rule")
+		}
+
+		if c.Bool("self-test") {
+			var failed int
+			for _, rule := range rules {
+				if err := rule.SelfTest(); err != nil {
+					failed++
+					log.Errorf("%v", err)
+					continue
+				}
+				log.Infof("syntaxflow rule %s passed", rule.Name)
+			}
+			if failed > 0 {
+				return utils.Errorf("%d/%d syntaxflow rules failed", failed, len(rules))
+			}
+			return nil
+		}
+
+		target := c.String("target")
+		if target == "" {
+			return utils.Errorf("target is empty")
+		}
+		prog, err := ssaapi.ParseProjectFromDir(target, ssaapi.WithLanguage(ssaapi.Language(c.String("language"))))
+		if err != nil {
+			return err
+		}
+		results := prog.RunSyntaxFlowRules(rules...)
+		for _, r := range results {
+			log.Infof("%s", r.String())
+		}
+
+		var raw []byte
+		switch c.String("format") {
+		case "json":
+			raw, err = ssaapi.SyntaxFlowResultsToJSON(results)
+		case "sarif":
+			raw, err = ssaapi.SyntaxFlowResultsToSARIF(rules, results)
+		default:
+			return utils.Errorf("unsupported format: %s", c.String("format"))
+		}
+		if err != nil {
+			return err
+		}
+		if output := c.String("output"); output != "" {
+			log.Infof("%d results saved to %s", len(results), output)
+			return os.WriteFile(output, raw, 0o644)
+		}
+		fmt.Println(string(raw))
+		return nil
+	},
+}
@@ -17,6 +17,9 @@ import (
 type ResultCallback func(y *YakTemplate, reqBulk any /**YakRequestBulkConfig / YakNetworkBulkConfig*/, rsp any /*[]*lowhttp.LowhttpResponse / [][]byte*/, result bool, extractor map[string]interface{})
 type HTTPResultCallback func(y *YakTemplate, reqBulk *YakRequestBulkConfig, rsp []*lowhttp.LowhttpResponse, result bool, extractor map[string]interface{})
 type TCPResultCallback func(y *YakTemplate, reqBulk *YakNetworkBulkConfig, rsp []*NucleiTcpResponse, result bool, extractor map[string]interface{})
+type DNSResultCallback func(y *YakTemplate, reqBulk *YakDNSBulkConfig, rsp []*NucleiDNSResponse, result bool, extractor map[string]interface{})
+type HeadlessResultCallback func(y *YakTemplate, reqBulk *YakHeadlessBulkConfig, rsp []*NucleiHeadlessResponse, result bool, extractor map[string]interface{})
+type SSLResultCallback func(y *YakTemplate, reqBulk *YakSSLBulkConfig, rsp []*NucleiSSLResponse, result bool, extractor map[string]interface{})
 
 func HTTPResultCallbackWrapper(callback HTTPResultCallback) ResultCallback {
 	return func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
@@ -50,6 +53,54 @@ func TCPResultCallbackWrapper(callback TCPResultCallback) ResultCallback {
 	}
 }
 
+func DNSResultCallbackWrapper(callback DNSResultCallback) ResultCallback {
+	return func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+		bulk, ok := reqBulk.(*YakDNSBulkConfig)
+		if !ok {
+			return
+		}
+
+		results, ok := rsp.([]*NucleiDNSResponse)
+		if !ok {
+			return
+		}
+
+		callback(y, bulk, results, result, extractor)
+	}
+}
+
+func HeadlessResultCallbackWrapper(callback HeadlessResultCallback) ResultCallback {
+	return func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+		bulk, ok := reqBulk.(*YakHeadlessBulkConfig)
+		if !ok {
+			return
+		}
+
+		results, ok := rsp.([]*NucleiHeadlessResponse)
+		if !ok {
+			return
+		}
+
+		callback(y, bulk, results, result, extractor)
+	}
+}
+
+func SSLResultCallbackWrapper(callback SSLResultCallback) ResultCallback {
+	return func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+		bulk, ok := reqBulk.(*YakSSLBulkConfig)
+		if !ok {
+			return
+		}
+
+		results, ok := rsp.([]*NucleiSSLResponse)
+		if !ok {
+			return
+		}
+
+		callback(y, bulk, results, result, extractor)
+	}
+}
+
 type ConfigOption func(*Config)
 
 type Config struct {
@@ -90,6 +141,48 @@ type Config struct {
 	OnTemplateLoaded  func(*YakTemplate) bool
 	BeforeSendPackage func(data []byte, isHttps bool) []byte
 	defaultFilter     *filter.StringFilter
+
+	// WorkflowTemplateLoader 用于加载 workflow 中引用的模板（路径或者 tags）
+	WorkflowTemplateLoader func(template string, tags []string) ([]*YakTemplate, error)
+	// onMatcherNames 在带名字的 matcher 命中时回调，workflow 用它来决定是否执行子模板
+	onMatcherNames func(names []string)
+
+	// headless 模板需要浏览器，批量扫描时默认跳过
+	EnableHeadless bool
+	ShowBrowser    bool
+	// HeadlessPageCreator 用于创建 headless 模板执行步骤的页面，为空时使用 simulator/simple 启动浏览器
+	HeadlessPageCreator func(lowhttpConfig *lowhttp.LowhttpExecConfig) (HeadlessPage, error)
+}
+
+func WithEnableHeadless(b bool) ConfigOption {
+	return func(config *Config) {
+		config.EnableHeadless = b
+	}
+}
+
+func WithShowBrowser(b bool) ConfigOption {
+	return func(config *Config) {
+		config.ShowBrowser = b
+	}
+}
+
+func WithHeadlessPageCreator(f func(lowhttpConfig *lowhttp.LowhttpExecConfig) (HeadlessPage, error)) ConfigOption {
+	return func(config *Config) {
+		config.HeadlessPageCreator = f
+	}
+}
+
+func (c *Config) createHeadlessPage(lowhttpConfig *lowhttp.LowhttpExecConfig) (HeadlessPage, error) {
+	if c.HeadlessPageCreator != nil {
+		return c.HeadlessPageCreator(lowhttpConfig)
+	}
+	return newSimpleHeadlessPage(c.ShowBrowser, lowhttpConfig)
+}
+
+func WithWorkflowTemplateLoader(f func(template string, tags []string) ([]*YakTemplate, error)) ConfigOption {
+	return func(config *Config) {
+		config.WorkflowTemplateLoader = f
+	}
 }
 
 func WithCustomVulnFilter(f *filter.StringFilter) ConfigOption {
@@ -264,6 +357,66 @@ func WithTCPResultCallback(f TCPResultCallback) ConfigOption {
 	}
 }
 
+func WithDNSResultCallback(f DNSResultCallback) ConfigOption {
+	return func(config *Config) {
+		if config.Callback != nil {
+			originCallback := config.Callback
+			config.Callback = func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+				defer func() {
+					if err := recover(); err != nil {
+						log.Errorf("(WithCallback) httptpl execute result callback failed: %v", err)
+						utils.PrintCurrentGoroutineRuntimeStack()
+					}
+				}()
+				originCallback(y, reqBulk, rsp, result, extractor)
+				DNSResultCallbackWrapper(f)(y, reqBulk, rsp, result, extractor)
+			}
+		} else {
+			config.Callback = DNSResultCallbackWrapper(f)
+		}
+	}
+}
+
+func WithHeadlessResultCallback(f HeadlessResultCallback) ConfigOption {
+	return func(config *Config) {
+		if config.Callback != nil {
+			originCallback := config.Callback
+			config.Callback = func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+				defer func() {
+					if err := recover(); err != nil {
+						log.Errorf("(WithCallback) httptpl execute result callback failed: %v", err)
+						utils.PrintCurrentGoroutineRuntimeStack()
+					}
+				}()
+				originCallback(y, reqBulk, rsp, result, extractor)
+				HeadlessResultCallbackWrapper(f)(y, reqBulk, rsp, result, extractor)
+			}
+		} else {
+			config.Callback = HeadlessResultCallbackWrapper(f)
+		}
+	}
+}
+
+func WithSSLResultCallback(f SSLResultCallback) ConfigOption {
+	return func(config *Config) {
+		if config.Callback != nil {
+			originCallback := config.Callback
+			config.Callback = func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+				defer func() {
+					if err := recover(); err != nil {
+						log.Errorf("(WithCallback) httptpl execute result callback failed: %v", err)
+						utils.PrintCurrentGoroutineRuntimeStack()
+					}
+				}()
+				originCallback(y, reqBulk, rsp, result, extractor)
+				SSLResultCallbackWrapper(f)(y, reqBulk, rsp, result, extractor)
+			}
+		} else {
+			config.Callback = SSLResultCallbackWrapper(f)
+		}
+	}
+}
+
 func (c *Config) ExecuteResultCallback(y *YakTemplate, bulk *YakRequestBulkConfig, rsp []*lowhttp.LowhttpResponse, result bool, extractor map[string]interface{}) {
 	if c == nil {
 		return
@@ -280,6 +433,35 @@ func (c *Config) ExecuteResultCallback(y *YakTemplate, bulk *YakRequestBulkConfi
 	}
 }
 
+func (c *Config) executeMatcherNamesCallback(names []string) {
+	if c == nil || c.onMatcherNames == nil || len(names) <= 0 {
+		return
+	}
+	c.onMatcherNames(names)
+}
+
+// executeRawMatcherNamesCallback 用于 tcp / dns / ssl / headless 等基于原始数据匹配的协议，
+// 命中后重新执行带名字的子 matcher，把命中的名字交给 workflow
+func (c *Config) executeRawMatcherNamesCallback(matcher *YakMatcher, rsp []byte, vars map[string]any) {
+	if c == nil || c.onMatcherNames == nil || matcher == nil {
+		return
+	}
+	matchers := matcher.SubMatchers
+	if len(matchers) <= 0 {
+		matchers = []*YakMatcher{matcher}
+	}
+	var names []string
+	for _, m := range matchers {
+		if m.Name == "" {
+			continue
+		}
+		if b, _ := m.ExecuteRawWithConfig(c, rsp, vars); b {
+			names = append(names, m.Name)
+		}
+	}
+	c.executeMatcherNamesCallback(names)
+}
+
 func (c *Config) ExecuteTCPResultCallback(y *YakTemplate, bulk *YakNetworkBulkConfig, rsp []*NucleiTcpResponse, result bool, extractor map[string]interface{}) {
 	if c == nil {
 		return
@@ -295,6 +477,51 @@ func (c *Config) ExecuteTCPResultCallback(y *YakTemplate, bulk *YakNetworkBulkCo
 	}
 }
 
+func (c *Config) ExecuteDNSResultCallback(y *YakTemplate, bulk *YakDNSBulkConfig, rsp []*NucleiDNSResponse, result bool, extractor map[string]interface{}) {
+	if c == nil {
+		return
+	}
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("httptpl execute result callback failed: %v", err)
+			utils.PrintCurrentGoroutineRuntimeStack()
+		}
+	}()
+	if c.Callback != nil {
+		c.Callback(y, bulk, rsp, result, extractor)
+	}
+}
+
+func (c *Config) ExecuteHeadlessResultCallback(y *YakTemplate, bulk *YakHeadlessBulkConfig, rsp []*NucleiHeadlessResponse, result bool, extractor map[string]interface{}) {
+	if c == nil {
+		return
+	}
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("httptpl execute result callback failed: %v", err)
+			utils.PrintCurrentGoroutineRuntimeStack()
+		}
+	}()
+	if c.Callback != nil {
+		c.Callback(y, bulk, rsp, result, extractor)
+	}
+}
+
+func (c *Config) ExecuteSSLResultCallback(y *YakTemplate, bulk *YakSSLBulkConfig, rsp []*NucleiSSLResponse, result bool, extractor map[string]interface{}) {
+	if c == nil {
+		return
+	}
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("httptpl execute result callback failed: %v", err)
+			utils.PrintCurrentGoroutineRuntimeStack()
+		}
+	}()
+	if c.Callback != nil {
+		c.Callback(y, bulk, rsp, result, extractor)
+	}
+}
+
 // NewConfig 创建一个默认的配置
 var defaultFilter = filter.NewFilter()
 
@@ -359,6 +586,48 @@ func (c *Config) AppendTCPResultCallback(handler TCPResultCallback) {
 	}
 }
 
+func (c *Config) AppendDNSResultCallback(handler DNSResultCallback) {
+	handlerRaw := DNSResultCallbackWrapper(handler)
+	if c.Callback == nil {
+		c.Callback = handlerRaw
+		return
+	}
+
+	origin := c.Callback
+	c.Callback = func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+		origin(y, reqBulk, rsp, result, extractor)
+		handlerRaw(y, reqBulk, rsp, result, extractor)
+	}
+}
+
+func (c *Config) AppendHeadlessResultCallback(handler HeadlessResultCallback) {
+	handlerRaw := HeadlessResultCallbackWrapper(handler)
+	if c.Callback == nil {
+		c.Callback = handlerRaw
+		return
+	}
+
+	origin := c.Callback
+	c.Callback = func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+		origin(y, reqBulk, rsp, result, extractor)
+		handlerRaw(y, reqBulk, rsp, result, extractor)
+	}
+}
+
+func (c *Config) AppendSSLResultCallback(handler SSLResultCallback) {
+	handlerRaw := SSLResultCallbackWrapper(handler)
+	if c.Callback == nil {
+		c.Callback = handlerRaw
+		return
+	}
+
+	origin := c.Callback
+	c.Callback = func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+		origin(y, reqBulk, rsp, result, extractor)
+		handlerRaw(y, reqBulk, rsp, result, extractor)
+	}
+}
+
 func (c *Config) GenerateYakTemplate() (chan *YakTemplate, error) {
 	if c.IsNuclei() {
 		ch := make(chan *YakTemplate)
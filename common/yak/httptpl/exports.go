@@ -122,6 +122,11 @@ func ScanPacket(req []byte, opts ...interface{}) (count uint64) {
 				continue
 			}
 
+			if len(tpl.HeadlessRequestSequences) > 0 && !config.EnableHeadless {
+				log.Infof("skip template %s because of headless is disabled", tpl.Name)
+				continue
+			}
+
 			if tpl.ReverseConnectionNeed && !config.EnableReverseConnectionFeature {
 				log.Infof("skip template %s because of reverse connection feature is disabled", tpl.Name)
 				continue
@@ -327,6 +332,9 @@ func WithOnRisk(target string, onRisk func(i *yakit.Risk)) ConfigOption {
 	return func(config *Config) {
 		_callback(i)(config)
 		_tcpCallback(i)(config)
+		_dnsCallback(i)(config)
+		_headlessCallback(i)(config)
+		_sslCallback(i)(config)
 	}
 }
 
@@ -378,6 +386,54 @@ func processVulnerability(target any, filterVul *filter.StringFilter, vCh chan *
 				}
 			}
 
+			if len(tpl.DNSRequestSequences) > 0 {
+				resp := i["responses"].([]*NucleiDNSResponse)
+				calcSha1 = utils.CalcSha1(tpl.Name, resp[0].RawRequest, target)
+
+				currTarget = resp[0].Name
+				if len(resp) == 1 {
+					details["request"] = string(resp[0].RawRequest)
+					details["response"] = string(resp[0].RawPacket)
+				} else {
+					for idx, r := range resp {
+						details[fmt.Sprintf("request_%d", idx+1)] = string(r.RawRequest)
+						details[fmt.Sprintf("response_%d", idx+1)] = string(r.RawPacket)
+					}
+				}
+			}
+
+			if len(tpl.HeadlessRequestSequences) > 0 {
+				resp := i["responses"].([]*NucleiHeadlessResponse)
+				calcSha1 = utils.CalcSha1(tpl.Name, resp[0].RawRequest, target)
+
+				currTarget = resp[0].URL
+				if len(resp) == 1 {
+					details["request"] = string(resp[0].RawRequest)
+					details["response"] = string(resp[0].RawPacket)
+				} else {
+					for idx, r := range resp {
+						details[fmt.Sprintf("request_%d", idx+1)] = string(r.RawRequest)
+						details[fmt.Sprintf("response_%d", idx+1)] = string(r.RawPacket)
+					}
+				}
+			}
+
+			if len(tpl.SSLRequestSequences) > 0 {
+				resp := i["responses"].([]*NucleiSSLResponse)
+				calcSha1 = utils.CalcSha1(tpl.Name, resp[0].RawRequest, target)
+
+				currTarget = resp[0].Address
+				if len(resp) == 1 {
+					details["request"] = string(resp[0].RawRequest)
+					details["response"] = string(resp[0].RawPacket)
+				} else {
+					for idx, r := range resp {
+						details[fmt.Sprintf("request_%d", idx+1)] = string(r.RawRequest)
+						details[fmt.Sprintf("response_%d", idx+1)] = string(r.RawPacket)
+					}
+				}
+			}
+
 			pv := &tools.PocVul{
 				Source:        "nuclei",
 				Target:        currTarget,
@@ -430,6 +486,9 @@ func ScanLegacyWithFilter(target any, filterVul *filter.StringFilter, opt ...int
 	i := processVulnerability(target, filterVul, vCh)
 	opt = append(opt, _callback(i))
 	opt = append(opt, _tcpCallback(i))
+	opt = append(opt, _dnsCallback(i))
+	opt = append(opt, _headlessCallback(i))
+	opt = append(opt, _sslCallback(i))
 
 	c, _, _ := toConfig(opt...)
 	if strings.TrimSpace(c.SingleTemplateRaw) != "" {
@@ -475,8 +534,8 @@ var Exports = map[string]interface{}{
 	"pageTimeout":             _timeout,
 	"retry":                   lowhttp.WithRetryTimes,
 	"rateLimit":               rateLimit,
-	"headless":                nucleiOptionDummy("headless"),
-	"showBrowser":             nucleiOptionDummy("showBrowser"),
+	"headless":                WithEnableHeadless,
+	"showBrowser":             WithShowBrowser,
 	"dnsResolver":             lowhttp.WithDNSServers,
 	"systemDnsResolver":       nucleiOptionDummy("systemDnsResolver"),
 	"metrics":                 nucleiOptionDummy("metrics"),
@@ -504,6 +563,9 @@ var Exports = map[string]interface{}{
 	"mode":                    WithMode,
 	"resultCallback":          _callback,
 	"tcpResultCallback":       _tcpCallback,
+	"dnsResultCallback":       _dnsCallback,
+	"headlessResultCallback":  _headlessCallback,
+	"sslResultCallback":       _sslCallback,
 	"https":                   lowhttp.WithHttps,
 	"http2":                   lowhttp.WithHttp2,
 	"runtimeId":               lowhttp.WithRuntimeId,
@@ -536,6 +598,45 @@ func _tcpCallback(handler func(i map[string]interface{})) ConfigOption {
 	})
 }
 
+func _dnsCallback(handler func(i map[string]interface{})) ConfigOption {
+	return WithDNSResultCallback(func(y *YakTemplate, reqBulk *YakDNSBulkConfig, rsp []*NucleiDNSResponse, result bool, extractor map[string]interface{}) {
+		handler(map[string]interface{}{
+			"template":  y,
+			"requests":  reqBulk,
+			"responses": rsp,
+			"response":  rsp,
+			"match":     result,
+			"extractor": extractor,
+		})
+	})
+}
+
+func _headlessCallback(handler func(i map[string]interface{})) ConfigOption {
+	return WithHeadlessResultCallback(func(y *YakTemplate, reqBulk *YakHeadlessBulkConfig, rsp []*NucleiHeadlessResponse, result bool, extractor map[string]interface{}) {
+		handler(map[string]interface{}{
+			"template":  y,
+			"requests":  reqBulk,
+			"responses": rsp,
+			"response":  rsp,
+			"match":     result,
+			"extractor": extractor,
+		})
+	})
+}
+
+func _sslCallback(handler func(i map[string]interface{})) ConfigOption {
+	return WithSSLResultCallback(func(y *YakTemplate, reqBulk *YakSSLBulkConfig, rsp []*NucleiSSLResponse, result bool, extractor map[string]interface{}) {
+		handler(map[string]interface{}{
+			"template":  y,
+			"requests":  reqBulk,
+			"responses": rsp,
+			"response":  rsp,
+			"match":     result,
+			"extractor": extractor,
+		})
+	})
+}
+
 func noInteractsh(b bool) ConfigOption {
 	return WithEnableReverseConnectionFeature(!b)
 }
@@ -141,10 +141,45 @@ func CreateYakTemplateFromNucleiTemplateRaw(tplRaw string) (*YakTemplate, error)
 			}
 
 			return yakTemp, nil
-		} else if utils.MapGetFirstRaw(mid, "workflows") != nil {
-			return nil, utils.Error("yakit nuclei cannot support workflows now~")
-		} else if utils.MapGetFirstRaw(mid, "headless") != nil {
-			return nil, utils.Errorf("nuclei template `headless(crawler)` is not supported (*)")
+		} else if ret := utils.MapGetFirstRaw(mid, "dns"); ret != nil {
+			if reflect.TypeOf(ret).Kind() != reflect.Slice {
+				return nil, utils.Error("nuclei template `dns` is not slice")
+			}
+			yakTemp.Variables = generateYakVariables(mid)
+			yakTemp.DNSRequestSequences, err = parseDNSBulk(utils.InterfaceToSliceInterface(ret))
+			if err != nil {
+				return nil, utils.Errorf("parse dns bulk failed: %v", err)
+			}
+			return yakTemp, nil
+		} else if ret := utils.MapGetFirstRaw(mid, "workflows"); ret != nil {
+			if reflect.TypeOf(ret).Kind() != reflect.Slice {
+				return nil, utils.Error("nuclei template `workflows` is not slice")
+			}
+			yakTemp.Workflows, err = parseWorkflows(utils.InterfaceToSliceInterface(ret))
+			if err != nil {
+				return nil, utils.Errorf("parse workflows failed: %v", err)
+			}
+			return yakTemp, nil
+		} else if ret := utils.MapGetFirstRaw(mid, "ssl"); ret != nil {
+			if reflect.TypeOf(ret).Kind() != reflect.Slice {
+				return nil, utils.Error("nuclei template `ssl` is not slice")
+			}
+			yakTemp.Variables = generateYakVariables(mid)
+			yakTemp.SSLRequestSequences, err = parseSSLBulk(utils.InterfaceToSliceInterface(ret))
+			if err != nil {
+				return nil, utils.Errorf("parse ssl bulk failed: %v", err)
+			}
+			return yakTemp, nil
+		} else if ret := utils.MapGetFirstRaw(mid, "headless"); ret != nil {
+			if reflect.TypeOf(ret).Kind() != reflect.Slice {
+				return nil, utils.Error("nuclei template `headless` is not slice")
+			}
+			yakTemp.Variables = generateYakVariables(mid)
+			yakTemp.HeadlessRequestSequences, err = parseHeadlessBulk(utils.InterfaceToSliceInterface(ret))
+			if err != nil {
+				return nil, utils.Errorf("parse headless bulk failed: %v", err)
+			}
+			return yakTemp, nil
 		} else {
 			log.Warnf("-----------------NUCLEI FORMATTER CANNOT FIX--------------------")
 			fmt.Println(tplRaw)
@@ -319,6 +354,9 @@ func generateYakExtractors(req map[string]interface{}) ([]*YakExtractor, error)
 		m := utils.InterfaceToMapInterface(i)
 		ext.Name = utils.MapGetString(m, "name")
 		ext.Scope = utils.MapGetString(m, "scope")
+		if ext.Scope == "" {
+			ext.Scope = utils.MapGetString(m, "part")
+		}
 		ext.Id = utils.MapGetInt(m, "id")
 
 		switch utils.MapGetString(m, "type") {
@@ -370,6 +408,7 @@ func generateYakMatcher(req map[string]interface{}) (*YakMatcher, error) {
 		match.Negative = utils.MapGetBool(m, "negative")
 		match.Condition = utils.MapGetString(m, "condition")
 		match.Id = utils.MapGetInt(m, "id")
+		match.Name = utils.MapGetString(m, "name")
 
 		switch utils.MapGetString(m, "part") {
 		case "body":
@@ -382,6 +421,9 @@ func generateYakMatcher(req map[string]interface{}) (*YakMatcher, error) {
 			match.Scope = "raw"
 		case "interactsh_protocol", "oob_protocol":
 			match.Scope = "oob_protocol"
+		default:
+			// 其他协议的响应字段，例如 dns 的 answer / headless 的 data
+			match.Scope = utils.MapGetString(m, "part")
 		}
 
 		switch utils.MapGetString(m, "type") {
@@ -462,3 +504,23 @@ func generateYakVariables(req map[string]interface{}) *YakVariables {
 	}
 	return vars
 }
+
+// fixRawResponseScope headless / ssl 的响应没有 http 头，body / resp / response 都指向整个响应
+func fixRawResponseScope(scope string) string {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "", "body", "resp", "response":
+		return "raw"
+	default:
+		return scope
+	}
+}
+
+func fixRawResponseMatcherScope(matcher *YakMatcher) {
+	if matcher == nil {
+		return
+	}
+	matcher.Scope = fixRawResponseScope(matcher.Scope)
+	for _, sub := range matcher.SubMatchers {
+		fixRawResponseMatcherScope(sub)
+	}
+}
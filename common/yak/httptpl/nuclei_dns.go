@@ -0,0 +1,62 @@
+package httptpl
+
+import (
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+func parseDNSBulk(ret []any) ([]*YakDNSBulkConfig, error) {
+	var confs []*YakDNSBulkConfig
+	for _, i := range utils.InterfaceToSliceInterface(ret) {
+		data := utils.InterfaceToGeneralMap(i)
+		bulk := &YakDNSBulkConfig{
+			Names:     utils.InterfaceToStringSlice(utils.MapGetRaw(data, "name")),
+			Type:      strings.ToUpper(strings.TrimSpace(utils.MapGetString(data, "type"))),
+			Class:     strings.ToUpper(strings.TrimSpace(utils.MapGetString(data, "class"))),
+			Retries:   utils.MapGetInt(data, "retries"),
+			Recursion: true,
+			Resolvers: utils.InterfaceToStringSlice(utils.MapGetRaw(data, "resolvers")),
+		}
+		if utils.MapGetRaw(data, "recursion") != nil {
+			bulk.Recursion = utils.MapGetBool(data, "recursion")
+		}
+		if bulk.Type == "" {
+			bulk.Type = "A"
+		}
+		if _, ok := dns.StringToType[bulk.Type]; !ok {
+			return nil, utils.Errorf("unsupported dns type: %v", bulk.Type)
+		}
+		if bulk.Class == "" || bulk.Class == "IN" {
+			bulk.Class = "INET"
+		}
+		if _, ok := dnsClassMap[bulk.Class]; !ok {
+			return nil, utils.Errorf("unsupported dns class: %v", bulk.Class)
+		}
+		if len(bulk.Names) <= 0 {
+			bulk.Names = []string{"{{FQDN}}"}
+		}
+
+		matcher, err := generateYakMatcher(data)
+		if err != nil {
+			log.Warnf("build matcher failed: %s", err)
+		}
+		bulk.Matcher = matcher
+		extractors, err := generateYakExtractors(data)
+		if err != nil {
+			log.Warnf("build extractor failed: %s", err)
+		}
+		bulk.Extractor = extractors
+		if len(bulk.Extractor) <= 0 && bulk.Matcher == nil {
+			log.Warn("no matcher and extractor found")
+			continue
+		}
+		confs = append(confs, bulk)
+	}
+	if len(confs) <= 0 {
+		return nil, utils.Error("empty dns bulk config")
+	}
+	return confs, nil
+}
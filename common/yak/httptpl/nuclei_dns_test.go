@@ -0,0 +1,140 @@
+package httptpl
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+func debugMockDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &dns.Server{PacketConn: conn, Handler: handler}
+	go server.ActivateAndServe()
+	t.Cleanup(func() {
+		server.Shutdown()
+	})
+	return conn.LocalAddr().String()
+}
+
+func TestGenerateDNSVars(t *testing.T) {
+	vars := generateDNSVars("www.example.co.uk")
+	assert.Equal(t, "www.example.co.uk", vars["FQDN"])
+	assert.Equal(t, "example.co.uk", vars["RDN"])
+	assert.Equal(t, "example", vars["DN"])
+	assert.Equal(t, "uk", vars["TLD"])
+	assert.Equal(t, "www", vars["SD"])
+}
+
+func TestCreateYakTemplateFromNucleiTemplateRaw_DNS(t *testing.T) {
+	var queried []string
+	server := debugMockDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		q := r.Question[0]
+		queried = append(queried, fmt.Sprintf("%v %v", dns.TypeToString[q.Qtype], q.Name))
+		if q.Qtype == dns.TypeCNAME {
+			rr, _ := dns.NewRR(q.Name + " 60 IN CNAME takeover-target.github.io.")
+			msg.Answer = append(msg.Answer, rr)
+		}
+		w.WriteMsg(msg)
+	})
+
+	demo := `id: dns-cname-takeover
+info:
+  name: dns cname takeover
+  author: v1ll4n
+  severity: high
+
+dns:
+  - name: "{{FQDN}}"
+    type: CNAME
+    class: inet
+    recursion: true
+    retries: 2
+    matchers-condition: and
+    matchers:
+      - type: word
+        part: answer
+        words:
+          - "github.io"
+      - type: word
+        part: rcode
+        words:
+          - "NOERROR"
+    extractors:
+      - type: regex
+        name: cname
+        part: answer
+        group: 1
+        regex:
+          - 'CNAME\s+([a-z.-]+)'
+`
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, tpl.DNSRequestSequences, 1)
+	assert.Equal(t, "CNAME", tpl.DNSRequestSequences[0].Type)
+	assert.Equal(t, "INET", tpl.DNSRequestSequences[0].Class)
+
+	var (
+		matched   bool
+		extracted map[string]any
+	)
+	config := NewConfig(WithDNSResultCallback(func(y *YakTemplate, reqBulk *YakDNSBulkConfig, rsp []*NucleiDNSResponse, result bool, extractor map[string]interface{}) {
+		matched = result
+		extracted = extractor
+	}))
+	n, err := tpl.ExecWithUrl("http://blog.example.com", config, lowhttp.WithDNSServers([]string{server}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"CNAME blog.example.com."}, queried)
+	assert.True(t, matched)
+	assert.Equal(t, "takeover-target.github.io.", utils.InterfaceToString(extracted["cname"]))
+}
+
+func TestCreateYakTemplateFromNucleiTemplateRaw_DNSExchangeFailed(t *testing.T) {
+	// 不回复任何请求的 DNS 服务器，查询只会超时
+	server := debugMockDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {})
+
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(`id: dns-timeout
+info:
+  name: dns timeout
+  author: v1ll4n
+  severity: info
+
+dns:
+  - name: "{{FQDN}}"
+    type: A
+    matchers:
+      - type: word
+        part: rcode
+        words:
+          - "NOERROR"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	config := NewConfig(WithDNSResultCallback(func(y *YakTemplate, reqBulk *YakDNSBulkConfig, rsp []*NucleiDNSResponse, result bool, extractor map[string]interface{}) {
+		called = true
+	}))
+	n, err := tpl.ExecWithUrl("http://blog.example.com", config, lowhttp.WithDNSServers([]string{server}), lowhttp.WithTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, n)
+	assert.False(t, called)
+}
@@ -0,0 +1,58 @@
+package httptpl
+
+import (
+	"strings"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+func parseHeadlessBulk(ret []any) ([]*YakHeadlessBulkConfig, error) {
+	var confs []*YakHeadlessBulkConfig
+	for _, i := range utils.InterfaceToSliceInterface(ret) {
+		data := utils.InterfaceToGeneralMap(i)
+		bulk := &YakHeadlessBulkConfig{}
+		for _, stepRaw := range utils.InterfaceToSliceInterface(utils.MapGetRaw(data, "steps")) {
+			stepMap := utils.InterfaceToGeneralMap(stepRaw)
+			step := &YakHeadlessStep{
+				Action: strings.ToLower(strings.TrimSpace(utils.MapGetString(stepMap, "action"))),
+				Name:   utils.MapGetString(stepMap, "name"),
+				Args:   make(map[string]string),
+			}
+			if step.Action == "" {
+				return nil, utils.Error("headless step action is empty")
+			}
+			for k, v := range utils.InterfaceToGeneralMap(utils.MapGetRaw(stepMap, "args")) {
+				step.Args[strings.ToLower(k)] = utils.InterfaceToString(v)
+			}
+			bulk.Steps = append(bulk.Steps, step)
+		}
+		if len(bulk.Steps) <= 0 {
+			return nil, utils.Error("headless steps is empty")
+		}
+
+		matcher, err := generateYakMatcher(data)
+		if err != nil {
+			log.Warnf("build matcher failed: %s", err)
+		}
+		fixRawResponseMatcherScope(matcher)
+		bulk.Matcher = matcher
+		extractors, err := generateYakExtractors(data)
+		if err != nil {
+			log.Warnf("build extractor failed: %s", err)
+		}
+		for _, extractor := range extractors {
+			extractor.Scope = fixRawResponseScope(extractor.Scope)
+		}
+		bulk.Extractor = extractors
+		if len(bulk.Extractor) <= 0 && bulk.Matcher == nil {
+			log.Warn("no matcher and extractor found")
+			continue
+		}
+		confs = append(confs, bulk)
+	}
+	if len(confs) <= 0 {
+		return nil, utils.Error("empty headless bulk config")
+	}
+	return confs, nil
+}
@@ -0,0 +1,256 @@
+package httptpl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+type mockHeadlessPage struct {
+	url     string
+	inputs  map[string]string
+	headers map[string]string
+	actions []string
+	closed  bool
+}
+
+func (p *mockHeadlessPage) Navigate(url string) error {
+	p.url = url
+	p.actions = append(p.actions, "navigate "+url)
+	return nil
+}
+
+func (p *mockHeadlessPage) Click(by, selector string, right bool) error {
+	p.actions = append(p.actions, "click "+by+" "+selector)
+	if selector == "#login" && p.inputs["#username"] == "admin" {
+		p.url = strings.TrimSuffix(p.url, "/") + "/dashboard"
+	}
+	return nil
+}
+
+func (p *mockHeadlessPage) Input(by, selector, value string) error {
+	p.inputs[selector] = value
+	return nil
+}
+
+func (p *mockHeadlessPage) Select(by, selector string, values []string, selected bool) error {
+	return nil
+}
+
+func (p *mockHeadlessPage) WaitVisible(by, selector string) error {
+	return nil
+}
+
+func (p *mockHeadlessPage) Extract(by, selector, target, attribute string) (string, error) {
+	if target == "attribute" {
+		return "/static/" + attribute + ".js", nil
+	}
+	return "welcome " + p.inputs["#username"], nil
+}
+
+func (p *mockHeadlessPage) WaitLoad() error {
+	return nil
+}
+
+func (p *mockHeadlessPage) WaitIdle(timeout time.Duration) error {
+	return nil
+}
+
+func (p *mockHeadlessPage) Eval(js string, hook bool) (string, error) {
+	if hook {
+		return "", nil
+	}
+	return "yak-version-1.2.3", nil
+}
+
+func (p *mockHeadlessPage) Keyboard(keys string) error {
+	return nil
+}
+
+func (p *mockHeadlessPage) SetExtraHeaders(headers map[string]string) error {
+	p.headers = headers
+	return nil
+}
+
+func (p *mockHeadlessPage) Screenshot() (string, error) {
+	return "data:image/png;base64,", nil
+}
+
+func (p *mockHeadlessPage) HTML() (string, error) {
+	return "<html><body>" + p.url + "</body></html>", nil
+}
+
+func (p *mockHeadlessPage) URL() (string, error) {
+	return p.url, nil
+}
+
+func (p *mockHeadlessPage) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestCreateYakTemplateFromNucleiTemplateRaw_Headless(t *testing.T) {
+	demo := `id: headless-login
+info:
+  name: headless login
+  author: v1ll4n
+  severity: info
+
+headless:
+  - steps:
+      - action: setheader
+        args:
+          part: request
+          key: X-Test
+          value: "{{Hostname}}"
+      - action: navigate
+        args:
+          url: "{{BaseURL}}/login"
+      - action: waitload
+      - action: text
+        args:
+          by: selector
+          selector: "#username"
+          value: admin
+      - action: click
+        args:
+          by: x
+          xpath: "#login"
+      - action: script
+        name: version
+        args:
+          code: "() => window.version"
+      - action: extract
+        name: welcome
+        args:
+          selector: ".welcome"
+      - action: files
+        args:
+          selector: "#upload"
+    matchers-condition: and
+    matchers:
+      - type: word
+        words:
+          - "/dashboard"
+      - type: word
+        part: version
+        words:
+          - "yak-version"
+      - type: word
+        part: data
+        words:
+          - "welcome admin"
+    extractors:
+      - type: regex
+        name: ver
+        part: version
+        group: 1
+        regex:
+          - 'yak-version-([\d.]+)'
+`
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, tpl.HeadlessRequestSequences, 1)
+	assert.Len(t, tpl.HeadlessRequestSequences[0].Steps, 8)
+	assert.Equal(t, "raw", tpl.HeadlessRequestSequences[0].Matcher.SubMatchers[0].Scope)
+
+	page := &mockHeadlessPage{inputs: make(map[string]string)}
+	var (
+		matched   bool
+		extracted map[string]any
+		responses []*NucleiHeadlessResponse
+	)
+	config := NewConfig(
+		WithHeadlessPageCreator(func(lowhttpConfig *lowhttp.LowhttpExecConfig) (HeadlessPage, error) {
+			return page, nil
+		}),
+		WithHeadlessResultCallback(func(y *YakTemplate, reqBulk *YakHeadlessBulkConfig, rsp []*NucleiHeadlessResponse, result bool, extractor map[string]interface{}) {
+			matched = result
+			extracted = extractor
+			responses = rsp
+		}),
+	)
+	n, err := tpl.ExecWithUrl("http://example.com:8080", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, n)
+	assert.True(t, page.closed)
+	assert.Equal(t, "example.com:8080", page.headers["X-Test"])
+	assert.Equal(t, []string{"navigate http://example.com:8080/login", "click xpath #login"}, page.actions)
+	assert.True(t, matched)
+	assert.Equal(t, "1.2.3", utils.InterfaceToString(extracted["ver"]))
+	assert.Len(t, responses, 1)
+	assert.Equal(t, "http://example.com:8080/login/dashboard", responses[0].URL)
+	assert.Equal(t, "yak-version-1.2.3", responses[0].Data["version"])
+}
+
+// hangingHeadlessPage 执行 js 时一直不返回，直到页面被关闭
+type hangingHeadlessPage struct {
+	mockHeadlessPage
+	closeCh chan struct{}
+}
+
+func (p *hangingHeadlessPage) Eval(js string, hook bool) (string, error) {
+	<-p.closeCh
+	return "", utils.Error("page closed")
+}
+
+func (p *hangingHeadlessPage) Close() error {
+	p.closed = true
+	close(p.closeCh)
+	return nil
+}
+
+func TestCreateYakTemplateFromNucleiTemplateRaw_HeadlessCancel(t *testing.T) {
+	for name, step := range map[string]string{
+		"script": `      - action: script
+        args:
+          code: "() => new Promise(() => {})"`,
+		"sleep": `      - action: sleep
+        args:
+          duration: 100`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			tpl, err := CreateYakTemplateFromNucleiTemplateRaw(`id: headless-hang
+info:
+  name: headless hang
+  author: v1ll4n
+  severity: info
+
+headless:
+  - steps:
+      - action: navigate
+        args:
+          url: "{{BaseURL}}"
+` + step + `
+    matchers:
+      - type: word
+        words:
+          - "example"
+`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			page := &hangingHeadlessPage{mockHeadlessPage: mockHeadlessPage{inputs: make(map[string]string)}, closeCh: make(chan struct{})}
+			config := NewConfig(WithHeadlessPageCreator(func(lowhttpConfig *lowhttp.LowhttpExecConfig) (HeadlessPage, error) {
+				return page, nil
+			}))
+
+			// 扫描取消后，卡住的步骤不能继续阻塞模板执行
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			start := time.Now()
+			tpl.ExecWithUrl("http://example.com", config, lowhttp.WithContext(ctx))
+			assert.Less(t, time.Since(start), 5*time.Second)
+			assert.True(t, page.closed)
+		})
+	}
+}
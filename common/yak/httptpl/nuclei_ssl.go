@@ -0,0 +1,64 @@
+package httptpl
+
+import (
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/netx"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+func parseSSLBulk(ret []any) ([]*YakSSLBulkConfig, error) {
+	var confs []*YakSSLBulkConfig
+	for _, i := range utils.InterfaceToSliceInterface(ret) {
+		data := utils.InterfaceToGeneralMap(i)
+		bulk := &YakSSLBulkConfig{
+			Address:        utils.MapGetString(data, "address"),
+			MinVersion:     utils.MapGetString(data, "min_version"),
+			MaxVersion:     utils.MapGetString(data, "max_version"),
+			CipherSuites:   utils.InterfaceToStringSlice(utils.MapGetRaw(data, "cipher_suites")),
+			SNI:            utils.MapGetString(data, "sni"),
+			GMTLS:          utils.MapGetBool(data, "gmtls"),
+			TLSVersionEnum: utils.MapGetBool(data, "tls_version_enum"),
+			TLSCipherEnum:  utils.MapGetBool(data, "tls_cipher_enum"),
+		}
+		if bulk.Address == "" {
+			bulk.Address = "{{Host}}:{{Port}}"
+		}
+		for _, v := range []string{bulk.MinVersion, bulk.MaxVersion} {
+			if v == "" {
+				continue
+			}
+			if _, err := netx.ParseTLSVersion(v); err != nil {
+				return nil, err
+			}
+		}
+		for _, c := range bulk.CipherSuites {
+			if _, err := netx.ParseTLSCipherSuite(c); err != nil {
+				return nil, err
+			}
+		}
+
+		matcher, err := generateYakMatcher(data)
+		if err != nil {
+			log.Warnf("build matcher failed: %s", err)
+		}
+		fixRawResponseMatcherScope(matcher)
+		bulk.Matcher = matcher
+		extractors, err := generateYakExtractors(data)
+		if err != nil {
+			log.Warnf("build extractor failed: %s", err)
+		}
+		for _, extractor := range extractors {
+			extractor.Scope = fixRawResponseScope(extractor.Scope)
+		}
+		bulk.Extractor = extractors
+		if len(bulk.Extractor) <= 0 && bulk.Matcher == nil {
+			log.Warn("no matcher and extractor found")
+			continue
+		}
+		confs = append(confs, bulk)
+	}
+	if len(confs) <= 0 {
+		return nil, utils.Error("empty ssl bulk config")
+	}
+	return confs, nil
+}
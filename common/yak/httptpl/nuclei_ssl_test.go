@@ -0,0 +1,121 @@
+package httptpl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+func TestCreateYakTemplateFromNucleiTemplateRaw_SSL(t *testing.T) {
+	host, port := utils.DebugMockHTTPS([]byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"))
+
+	demo := `id: self-signed-ssl
+info:
+  name: self signed ssl
+  author: v1ll4n
+  severity: low
+
+ssl:
+  - address: "{{Host}}:{{Port}}"
+    max_version: tls12
+    tls_version_enum: true
+    matchers-condition: and
+    matchers:
+      - type: dsl
+        dsl:
+          - "probe_status && untrusted"
+      - type: word
+        part: tls_version
+        words:
+          - "tls12"
+    extractors:
+      - type: json
+        name: cipher
+        json:
+          - ".cipher"
+      - type: json
+        name: versions
+        json:
+          - ".tls_version_enum[]"
+`
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, tpl.SSLRequestSequences, 1)
+	assert.Equal(t, "raw", tpl.SSLRequestSequences[0].Extractor[0].Scope)
+
+	var (
+		matched   bool
+		extracted map[string]any
+		responses []*NucleiSSLResponse
+	)
+	config := NewConfig(WithSSLResultCallback(func(y *YakTemplate, reqBulk *YakSSLBulkConfig, rsp []*NucleiSSLResponse, result bool, extractor map[string]interface{}) {
+		matched = result
+		extracted = extractor
+		responses = rsp
+	}))
+	n, err := tpl.ExecWithUrl(fmt.Sprintf("https://%v", utils.HostPort(host, port)), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, n)
+	assert.True(t, matched)
+	assert.NotEmpty(t, extracted["cipher"])
+	assert.Contains(t, utils.InterfaceToString(extracted["versions"]), "tls12")
+	assert.NotContains(t, utils.InterfaceToString(extracted["versions"]), "tls10")
+	assert.Len(t, responses, 1)
+	assert.NotNil(t, responses[0].Connection)
+	assert.Equal(t, "tls12", responses[0].Fields["tls_version"])
+}
+
+func TestCreateYakTemplateFromNucleiTemplateRaw_SSL_GMTLSVersionEnum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, port := utils.DebugMockOnlyGMHTTP(ctx, func(req []byte) []byte {
+		return []byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n")
+	})
+
+	demo := `id: gmssl-version-enum
+info:
+  name: gmssl version enum
+  severity: info
+
+ssl:
+  - address: "{{Host}}:{{Port}}"
+    gmtls: true
+    tls_version_enum: true
+    matchers:
+      - type: word
+        part: tls_version
+        words:
+          - "gmssl"
+    extractors:
+      - type: json
+        name: versions
+        json:
+          - ".tls_version_enum[]"
+`
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		matched   bool
+		extracted map[string]any
+	)
+	config := NewConfig(WithSSLResultCallback(func(y *YakTemplate, reqBulk *YakSSLBulkConfig, rsp []*NucleiSSLResponse, result bool, extractor map[string]interface{}) {
+		matched = result
+		extracted = extractor
+	}))
+	_, err = tpl.ExecWithUrl(fmt.Sprintf("https://%v", utils.HostPort(host, port)), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, matched)
+	assert.Equal(t, "gmssl", utils.InterfaceToString(extracted["versions"]))
+}
@@ -0,0 +1,82 @@
+package httptpl
+
+import (
+	"strings"
+
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// https://docs.projectdiscovery.io/templates/workflows/overview
+//
+// workflows:
+//   - template: http/technologies/tech-detect.yaml
+//     matchers:
+//       - name: wordpress
+//         subtemplates:
+//           - tags: wordpress
+//   - template: http/technologies/jira-detect.yaml
+//     subtemplates:
+//       - template: http/cves/2019/CVE-2019-8451.yaml
+
+func parseWorkflows(ret []any) ([]*YakWorkflow, error) {
+	var workflows []*YakWorkflow
+	for _, i := range ret {
+		workflow, err := parseWorkflow(utils.InterfaceToGeneralMap(i))
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, workflow)
+	}
+	if len(workflows) <= 0 {
+		return nil, utils.Error("empty workflows")
+	}
+	return workflows, nil
+}
+
+func parseWorkflow(data map[string]any) (*YakWorkflow, error) {
+	workflow := &YakWorkflow{
+		Template: utils.MapGetString(data, "template"),
+		Tags: utils.PrettifyListFromStringSplitEx(
+			strings.Join(utils.InterfaceToStringSlice(utils.MapGetRaw(data, "tags")), ","), ",",
+		),
+	}
+	if workflow.Template == "" && len(workflow.Tags) <= 0 {
+		return nil, utils.Error("workflow need `template` or `tags`")
+	}
+
+	for _, matcherRaw := range utils.InterfaceToSliceInterface(utils.MapGetRaw(data, "matchers")) {
+		matcherData := utils.InterfaceToGeneralMap(matcherRaw)
+		matcher := &YakWorkflowMatcher{
+			Names:     utils.InterfaceToStringSlice(utils.MapGetRaw(matcherData, "name")),
+			Condition: utils.MapGetString(matcherData, "condition"),
+		}
+		if len(matcher.Names) <= 0 {
+			return nil, utils.Errorf("workflow[%v] matcher name is empty", workflow.Template)
+		}
+		subtemplates, err := parseSubtemplates(matcherData)
+		if err != nil {
+			return nil, err
+		}
+		matcher.Subtemplates = subtemplates
+		workflow.Matchers = append(workflow.Matchers, matcher)
+	}
+
+	subtemplates, err := parseSubtemplates(data)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Subtemplates = subtemplates
+	return workflow, nil
+}
+
+func parseSubtemplates(data map[string]any) ([]*YakWorkflow, error) {
+	var subtemplates []*YakWorkflow
+	for _, i := range utils.InterfaceToSliceInterface(utils.MapGetRaw(data, "subtemplates")) {
+		sub, err := parseWorkflow(utils.InterfaceToGeneralMap(i))
+		if err != nil {
+			return nil, err
+		}
+		subtemplates = append(subtemplates, sub)
+	}
+	return subtemplates, nil
+}
@@ -0,0 +1,268 @@
+package httptpl
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+func TestCreateYakTemplateFromNucleiTemplateRaw_Workflows(t *testing.T) {
+	demo := `id: workflow-demo
+info:
+  name: workflow demo
+  author: v1ll4n
+
+workflows:
+  - template: technologies/tech-detect.yaml
+    matchers:
+      - name: wordpress
+        subtemplates:
+          - tags: wordpress
+      - name:
+          - jira
+          - confluence
+        condition: and
+        subtemplates:
+          - template: cves/atlassian.yaml
+  - template: technologies/nginx.yaml
+    subtemplates:
+      - template: misconfiguration/nginx-status.yaml
+`
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(demo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, tpl.Workflows, 2)
+	first := tpl.Workflows[0]
+	assert.Equal(t, "technologies/tech-detect.yaml", first.Template)
+	assert.Len(t, first.Matchers, 2)
+	assert.Equal(t, []string{"wordpress"}, first.Matchers[0].Names)
+	assert.Equal(t, []string{"wordpress"}, first.Matchers[0].Subtemplates[0].Tags)
+	assert.Equal(t, []string{"jira", "confluence"}, first.Matchers[1].Names)
+	assert.False(t, first.Matchers[1].Match(map[string]bool{"jira": true}))
+	assert.True(t, first.Matchers[1].Match(map[string]bool{"jira": true, "confluence": true}))
+	assert.Equal(t, "misconfiguration/nginx-status.yaml", tpl.Workflows[1].Subtemplates[0].Template)
+}
+
+func TestMockTest_Workflows(t *testing.T) {
+	server, port := utils.DebugMockHTTP([]byte("HTTP/1.1 200 OK\r\n" +
+		"Server: nginx\r\n\r\n" +
+		"<html>wp-content</html>"))
+
+	templates := map[string]string{
+		"detect.yaml": `id: detect
+info:
+  name: detect
+requests:
+  - method: GET
+    path:
+      - "{{BaseURL}}/"
+    matchers-condition: or
+    matchers:
+      - type: word
+        name: wordpress
+        words:
+          - "wp-content"
+      - type: word
+        name: drupal
+        words:
+          - "Drupal.settings"
+`,
+		"wordpress.yaml": `id: wordpress-check
+info:
+  name: wordpress-check
+requests:
+  - method: GET
+    path:
+      - "{{BaseURL}}/wp-login.php"
+    matchers:
+      - type: word
+        words:
+          - "wp-content"
+`,
+		"drupal.yaml": `id: drupal-check
+info:
+  name: drupal-check
+requests:
+  - method: GET
+    path:
+      - "{{BaseURL}}/CHANGELOG.txt"
+    matchers:
+      - type: word
+        words:
+          - "html"
+`,
+	}
+
+	workflow := `id: workflow-demo
+info:
+  name: workflow demo
+
+workflows:
+  - template: detect.yaml
+    matchers:
+      - name: wordpress
+        subtemplates:
+          - template: wordpress.yaml
+      - name: drupal
+        subtemplates:
+          - template: drupal.yaml
+`
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(workflow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		m       sync.Mutex
+		matched []string
+	)
+	config := NewConfig(
+		WithWorkflowTemplateLoader(func(template string, tags []string) ([]*YakTemplate, error) {
+			raw, ok := templates[template]
+			if !ok {
+				return nil, utils.Errorf("template %v not found", template)
+			}
+			sub, err := CreateYakTemplateFromNucleiTemplateRaw(raw)
+			if err != nil {
+				return nil, err
+			}
+			return []*YakTemplate{sub}, nil
+		}),
+		WithResultCallback(func(y *YakTemplate, reqBulk *YakRequestBulkConfig, rsp []*lowhttp.LowhttpResponse, result bool, extractor map[string]interface{}) {
+			if !result {
+				return
+			}
+			m.Lock()
+			defer m.Unlock()
+			matched = append(matched, y.Id)
+		}),
+	)
+	count, err := tpl.ExecWithUrl("http://www.example.com", config, lowhttp.WithHost(server), lowhttp.WithPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(matched)
+	assert.Equal(t, []string{"detect", "wordpress-check"}, matched)
+	assert.Equal(t, 2, count)
+}
+
+func TestMockTest_DNSWorkflows(t *testing.T) {
+	server := debugMockDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		q := r.Question[0]
+		switch q.Qtype {
+		case dns.TypeCNAME:
+			rr, _ := dns.NewRR(q.Name + " 60 IN CNAME takeover-target.github.io.")
+			msg.Answer = append(msg.Answer, rr)
+		case dns.TypeTXT:
+			rr, _ := dns.NewRR(q.Name + ` 60 IN TXT "github-pages"`)
+			msg.Answer = append(msg.Answer, rr)
+		}
+		w.WriteMsg(msg)
+	})
+
+	templates := map[string]string{
+		"detect.yaml": `id: detect
+info:
+  name: detect
+dns:
+  - name: "{{FQDN}}"
+    type: CNAME
+    matchers-condition: or
+    matchers:
+      - type: word
+        name: github
+        part: answer
+        words:
+          - "github.io"
+      - type: word
+        name: heroku
+        part: answer
+        words:
+          - "herokuapp.com"
+`,
+		"github.yaml": `id: github-check
+info:
+  name: github-check
+dns:
+  - name: "{{FQDN}}"
+    type: TXT
+    matchers:
+      - type: word
+        part: answer
+        words:
+          - "github-pages"
+`,
+		"heroku.yaml": `id: heroku-check
+info:
+  name: heroku-check
+dns:
+  - name: "{{FQDN}}"
+    type: TXT
+    matchers:
+      - type: word
+        part: answer
+        words:
+          - "TXT"
+`,
+	}
+
+	workflow := `id: dns-workflow-demo
+info:
+  name: dns workflow demo
+
+workflows:
+  - template: detect.yaml
+    matchers:
+      - name: github
+        subtemplates:
+          - template: github.yaml
+      - name: heroku
+        subtemplates:
+          - template: heroku.yaml
+`
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(workflow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		m       sync.Mutex
+		matched []string
+	)
+	config := NewConfig(
+		WithWorkflowTemplateLoader(func(template string, tags []string) ([]*YakTemplate, error) {
+			raw, ok := templates[template]
+			if !ok {
+				return nil, utils.Errorf("template %v not found", template)
+			}
+			sub, err := CreateYakTemplateFromNucleiTemplateRaw(raw)
+			if err != nil {
+				return nil, err
+			}
+			return []*YakTemplate{sub}, nil
+		}),
+		WithDNSResultCallback(func(y *YakTemplate, reqBulk *YakDNSBulkConfig, rsp []*NucleiDNSResponse, result bool, extractor map[string]interface{}) {
+			if !result {
+				return
+			}
+			m.Lock()
+			defer m.Unlock()
+			matched = append(matched, y.Id)
+		}),
+	)
+	count, err := tpl.ExecWithUrl("http://blog.example.com", config, lowhttp.WithDNSServers([]string{server}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(matched)
+	assert.Equal(t, []string{"detect", "github-check"}, matched)
+	assert.Equal(t, 2, count)
+}
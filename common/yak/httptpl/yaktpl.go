@@ -61,8 +61,12 @@ type YakTemplate struct {
 	// interactsh
 	ReverseConnectionNeed bool `json:"reverseConnectionNeed"`
 
-	TCPRequestSequences  []*YakNetworkBulkConfig
-	HTTPRequestSequences []*YakRequestBulkConfig
+	TCPRequestSequences      []*YakNetworkBulkConfig
+	HTTPRequestSequences     []*YakRequestBulkConfig
+	DNSRequestSequences      []*YakDNSBulkConfig
+	HeadlessRequestSequences []*YakHeadlessBulkConfig
+	SSLRequestSequences      []*YakSSLBulkConfig
+	Workflows                []*YakWorkflow
 
 	// placeHolderMap
 	PlaceHolderMap map[string]string
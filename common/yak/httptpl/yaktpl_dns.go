@@ -0,0 +1,261 @@
+package httptpl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/miekg/dns"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/netx"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"golang.org/x/net/publicsuffix"
+)
+
+var dnsClassMap = map[string]uint16{
+	"INET":   dns.ClassINET,
+	"CSNET":  dns.ClassCSNET,
+	"CHAOS":  dns.ClassCHAOS,
+	"HESIOD": dns.ClassHESIOD,
+	"NONE":   dns.ClassNONE,
+	"ANY":    dns.ClassANY,
+}
+
+type YakDNSBulkConfig struct {
+	// Names 是要查询的域名，支持 {{FQDN}} / {{RDN}} 等变量
+	Names []string
+	// A / AAAA / CNAME / TXT / MX / NS / SOA / PTR / CAA / ANY ...
+	Type string
+	// INET / CSNET / CHAOS / HESIOD / NONE / ANY
+	Class     string
+	Retries   int
+	Recursion bool
+	// Resolvers 为空时使用 lowhttp 配置的 dns 服务器或者 netx 默认的 dns 服务器
+	Resolvers []string
+
+	Matcher   *YakMatcher
+	Extractor []*YakExtractor
+}
+
+type NucleiDNSResponse struct {
+	Name       string
+	Server     string
+	RawRequest []byte
+	// RawPacket 是 dig 风格的响应文本
+	RawPacket []byte
+
+	Rcode    string
+	Question string
+	Answer   string
+	Ns       string
+	Extra    string
+	Msg      *dns.Msg
+}
+
+func (r *NucleiDNSResponse) ToVars() map[string]any {
+	return map[string]any{
+		"host":     r.Name,
+		"server":   r.Server,
+		"request":  string(r.RawRequest),
+		"raw":      string(r.RawPacket),
+		"rcode":    r.Rcode,
+		"question": r.Question,
+		"answer":   r.Answer,
+		"ns":       r.Ns,
+		"extra":    r.Extra,
+	}
+}
+
+func dnsRRsToString(rrs []dns.RR) string {
+	var lines []string
+	for _, rr := range rrs {
+		lines = append(lines, rr.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func newNucleiDNSResponse(name, server string, req, rsp *dns.Msg) *NucleiDNSResponse {
+	var questions []string
+	for _, q := range rsp.Question {
+		questions = append(questions, q.String())
+	}
+	return &NucleiDNSResponse{
+		Name:       name,
+		Server:     server,
+		RawRequest: []byte(req.String()),
+		RawPacket:  []byte(rsp.String()),
+		Rcode:      dns.RcodeToString[rsp.Rcode],
+		Question:   strings.Join(questions, "\n"),
+		Answer:     dnsRRsToString(rsp.Answer),
+		Ns:         dnsRRsToString(rsp.Ns),
+		Extra:      dnsRRsToString(rsp.Extra),
+		Msg:        rsp,
+	}
+}
+
+// generateDNSVars 生成 dns 模板中的域名变量
+// FQDN: www.example.co.uk / RDN: example.co.uk / DN: example / TLD: uk / SD: www
+func generateDNSVars(host string) map[string]any {
+	host = strings.TrimSuffix(host, ".")
+	vars := map[string]any{
+		"FQDN": host,
+		"RDN":  host,
+		"DN":   "",
+		"TLD":  "",
+		"SD":   "",
+	}
+	if utils.IsIPv4(host) || utils.IsIPv6(host) {
+		return vars
+	}
+	if idx := strings.LastIndex(host, "."); idx >= 0 {
+		vars["TLD"] = host[idx+1:]
+	}
+	rdn, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return vars
+	}
+	vars["RDN"] = rdn
+	if suffix, _ := publicsuffix.PublicSuffix(rdn); suffix != "" {
+		vars["DN"] = strings.TrimSuffix(strings.TrimSuffix(rdn, suffix), ".")
+	}
+	vars["SD"] = strings.TrimSuffix(strings.TrimSuffix(host, rdn), ".")
+	return vars
+}
+
+func (y *YakDNSBulkConfig) buildMsg(name string) (*dns.Msg, error) {
+	qtype, ok := dns.StringToType[strings.ToUpper(y.Type)]
+	if !ok {
+		return nil, utils.Errorf("unsupported dns type: %v", y.Type)
+	}
+	qclass, ok := dnsClassMap[strings.ToUpper(y.Class)]
+	if !ok {
+		qclass = dns.ClassINET
+	}
+	if qtype == dns.TypePTR {
+		if arpa, err := dns.ReverseAddr(name); err == nil {
+			name = arpa
+		}
+	}
+	msg := new(dns.Msg)
+	msg.Id = dns.Id()
+	msg.RecursionDesired = y.Recursion
+	msg.Question = []dns.Question{{Name: dns.Fqdn(name), Qtype: qtype, Qclass: qclass}}
+	return msg, nil
+}
+
+func (y *YakDNSBulkConfig) Execute(
+	config *Config,
+	vars map[string]any, params map[string]string, lowhttpConfig *lowhttp.LowhttpExecConfig,
+	callback func(rsp []*NucleiDNSResponse, matched bool, extractorResults map[string]any),
+) error {
+	if len(y.Names) <= 0 {
+		return utils.Error("YakDNSBulkConfig names is empty")
+	}
+
+	renderVars := utils.InterfaceToMapInterface(params)
+	host := params["Host"]
+	if lowhttpConfig.Host != "" && !utils.IsIPv4(lowhttpConfig.Host) && !utils.IsIPv6(lowhttpConfig.Host) {
+		host = lowhttpConfig.Host
+	}
+	for k, v := range generateDNSVars(host) {
+		renderVars[k] = v
+	}
+	for k, v := range vars {
+		renderVars[k] = v
+	}
+
+	var dnsOpts []netx.DNSOption
+	if len(y.Resolvers) > 0 {
+		dnsOpts = append(dnsOpts, netx.WithDNSServers(y.Resolvers...))
+	} else if len(lowhttpConfig.DNSServers) > 0 {
+		dnsOpts = append(dnsOpts, netx.WithDNSServers(lowhttpConfig.DNSServers...))
+	}
+	if lowhttpConfig.Timeout > 0 {
+		dnsOpts = append(dnsOpts, netx.WithTimeout(lowhttpConfig.Timeout))
+	}
+	if y.Retries > 0 {
+		dnsOpts = append(dnsOpts, netx.WithDNSRetryTimes(y.Retries))
+	}
+	if lowhttpConfig.Ctx != nil {
+		dnsOpts = append(dnsOpts, netx.WithDNSContext(lowhttpConfig.Ctx))
+	}
+
+	for _, nameRaw := range y.Names {
+		name, err := RenderNucleiTagWithVar(nameRaw, renderVars)
+		if err != nil {
+			log.Errorf("YakDNSBulkConfig render name[%v] error: %s", nameRaw, err)
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		msg, err := y.buildMsg(name)
+		if err != nil {
+			return err
+		}
+		if config.Debug || config.DebugRequest {
+			fmt.Println("---------------------DNS REQUEST---------------------")
+			fmt.Println(msg.String())
+		}
+		rsp, server, err := netx.ExchangeDNS(msg, dnsOpts...)
+		if err != nil {
+			// 网络错误不是匹配结果，不触发回调
+			log.Errorf("exchange dns[%v %v] failed: %s", y.Type, name, err)
+			continue
+		}
+		response := newNucleiDNSResponse(name, server, msg, rsp)
+		if config.Debug || config.DebugResponse {
+			fmt.Println("---------------------DNS RESPONSE---------------------")
+			fmt.Println(string(response.RawPacket))
+		}
+		y.handleResponse(config, response, renderVars, callback)
+	}
+	return nil
+}
+
+func (y *YakDNSBulkConfig) handleResponse(
+	config *Config, response *NucleiDNSResponse, vars map[string]any,
+	callback func(rsp []*NucleiDNSResponse, matched bool, extractorResults map[string]any),
+) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("YakDNSBulkConfig handle response failed: %v", err)
+			utils.PrintCurrentGoroutineRuntimeStack()
+		}
+	}()
+
+	vars = utils.MergeGeneralMap(vars, response.ToVars())
+	extractorResults := make(map[string]any)
+	for _, extractor := range y.Extractor {
+		extractorVars, err := extractor.Execute(response.RawPacket, vars)
+		if err != nil {
+			log.Warnf("YakDNSBulkConfig extractor.Execute failed: %s", err)
+			continue
+		}
+		for k, v := range extractorVars {
+			v := ExtractResultToString(v)
+			vars[k] = v
+			extractorResults[k] = v
+		}
+	}
+
+	var matched bool
+	if y.Matcher != nil {
+		var err error
+		matched, err = y.Matcher.ExecuteRawWithConfig(config, response.RawPacket, vars)
+		if err != nil {
+			log.Errorf("YakDNSBulkConfig matcher.ExecuteRaw failed: %s", err)
+		}
+		if matched {
+			config.executeRawMatcherNamesCallback(y.Matcher, response.RawPacket, vars)
+		}
+	}
+	if config.Debug {
+		fmt.Println("---------------------DNS RESULT---------------------")
+		fmt.Printf("%v Matched: %v\n", response.Name, matched)
+		spew.Dump(extractorResults)
+	}
+	callback([]*NucleiDNSResponse{response}, matched, extractorResults)
+}
@@ -113,6 +113,10 @@ func (y *YakTemplate) ExecWithUrl(u string, config *Config, opts ...lowhttp.Lowh
 		config = NewConfig()
 	}
 
+	if len(y.Workflows) > 0 {
+		return y.execWorkflows(u, config, opts...)
+	}
+
 	var count int64 = 0
 	if y.ReverseConnectionNeed {
 		var err error
@@ -241,8 +245,110 @@ func (y *YakTemplate) ExecWithUrl(u string, config *Config, opts ...lowhttp.Lowh
 		}
 		swg.Wait()
 		return int(count), nil
+	} else if len(y.DNSRequestSequences) > 0 {
+		swg := utils.NewSizedWaitGroup(tplConcurrent)
+		for _, dnsReq := range y.DNSRequestSequences {
+			swg.Add()
+			dnsReq := dnsReq
+
+			go func() {
+				defer swg.Done()
+				defer func() {
+					if err := recover(); err != nil {
+						utils.PrintCurrentGoroutineRuntimeStack()
+					}
+				}()
+				p := y.Variables.ToMap()
+
+				lowhttpConfig := lowhttp.NewLowhttpOption()
+				for _, opt := range opts {
+					opt(lowhttpConfig)
+				}
+				renderVars := utils2.ExtractorVarsFromUrl(u)
+				err := dnsReq.Execute(config, p, renderVars, lowhttpConfig, func(response []*NucleiDNSResponse, matched bool, extractorResults map[string]any) {
+					atomic.AddInt64(&count, 1)
+					config.ExecuteDNSResultCallback(y, dnsReq, response, matched, extractorResults)
+					if matched {
+						log.Infof("[%v]-[%v] matched", y.Name, y.Id)
+					}
+				})
+				if err != nil {
+					log.Errorf("dnsReq.Execute failed: %s", err)
+				}
+			}()
+		}
+		swg.Wait()
+		return int(count), nil
+	} else if len(y.HeadlessRequestSequences) > 0 {
+		swg := utils.NewSizedWaitGroup(tplConcurrent)
+		for _, headlessReq := range y.HeadlessRequestSequences {
+			swg.Add()
+			headlessReq := headlessReq
+
+			go func() {
+				defer swg.Done()
+				defer func() {
+					if err := recover(); err != nil {
+						utils.PrintCurrentGoroutineRuntimeStack()
+					}
+				}()
+				p := y.Variables.ToMap()
+
+				lowhttpConfig := lowhttp.NewLowhttpOption()
+				for _, opt := range opts {
+					opt(lowhttpConfig)
+				}
+				renderVars := utils2.ExtractorVarsFromUrl(u)
+				err := headlessReq.Execute(config, p, renderVars, lowhttpConfig, func(response []*NucleiHeadlessResponse, matched bool, extractorResults map[string]any) {
+					atomic.AddInt64(&count, 1)
+					config.ExecuteHeadlessResultCallback(y, headlessReq, response, matched, extractorResults)
+					if matched {
+						log.Infof("[%v]-[%v] matched", y.Name, y.Id)
+					}
+				})
+				if err != nil {
+					log.Errorf("headlessReq.Execute failed: %s", err)
+				}
+			}()
+		}
+		swg.Wait()
+		return int(count), nil
+	} else if len(y.SSLRequestSequences) > 0 {
+		swg := utils.NewSizedWaitGroup(tplConcurrent)
+		for _, sslReq := range y.SSLRequestSequences {
+			swg.Add()
+			sslReq := sslReq
+
+			go func() {
+				defer swg.Done()
+				defer func() {
+					if err := recover(); err != nil {
+						utils.PrintCurrentGoroutineRuntimeStack()
+					}
+				}()
+				p := y.Variables.ToMap()
+
+				lowhttpConfig := lowhttp.NewLowhttpOption()
+				for _, opt := range opts {
+					opt(lowhttpConfig)
+				}
+				renderVars := utils2.ExtractorVarsFromUrl(u)
+				err := sslReq.Execute(config, p, renderVars, lowhttpConfig, func(response []*NucleiSSLResponse, matched bool, extractorResults map[string]any) {
+					atomic.AddInt64(&count, 1)
+					config.ExecuteSSLResultCallback(y, sslReq, response, matched, extractorResults)
+					if matched {
+						log.Infof("[%v]-[%v] matched", y.Name, y.Id)
+					}
+				})
+				if err != nil {
+					log.Errorf("sslReq.Execute failed: %s", err)
+				}
+			}()
+		}
+		swg.Wait()
+		return int(count), nil
 	} else {
-		return 0, utils.Errorf("[%s] tcp/http is all empty!", y.Name)
+		return 0, utils.Errorf("[%s] tcp/http/dns/ssl/headless is all empty!", y.Name)
 	}
 }
 func (y *YakTemplate) Exec(config *Config, isHttps bool, reqOrigin []byte, opts ...lowhttp.LowhttpOpt) (int, error) {
@@ -336,6 +442,18 @@ func (y *YakTemplate) handleRequestSequences(config *Config, reqOrigin *YakReque
 		} else {
 			matchRes = funk.All(tempMatchersResult...)
 		}
+		if matchRes {
+			var names []string
+			for matcherIndex, matcher := range matchers {
+				if matcher.Name == "" {
+					continue
+				}
+				if b, ok := tempMatchersResult[matcherIndex].(bool); ok && b {
+					names = append(names, matcher.Name)
+				}
+			}
+			config.executeMatcherNamesCallback(names)
+		}
 		matchResults = append(matchResults, matchRes)
 		return matchRes
 	}
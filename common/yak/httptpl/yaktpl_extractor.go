@@ -65,6 +65,11 @@ func (y *YakExtractor) Execute(rsp []byte, previous ...map[string]any) (map[stri
 		material = header
 	default:
 		material = string(rsp)
+		for _, vars := range previous {
+			if v, ok := getProtocolScopeMaterial(strings.TrimSpace(strings.ToLower(y.Scope)), vars); ok {
+				material = v
+			}
+		}
 	}
 	var results = []string{}
 	addResult := func(result interface{}) {
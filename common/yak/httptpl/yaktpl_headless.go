@@ -0,0 +1,505 @@
+package httptpl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/simulator/simple"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/yak/yaklib/codec"
+)
+
+// defaultHeadlessStepTimeout 是未设置超时时每个步骤的默认超时时间
+const defaultHeadlessStepTimeout = 15 * time.Second
+
+// HeadlessPage 是 headless 模板执行步骤时操作的页面，默认由 simulator/simple 的浏览器实现
+// by 为 xpath 时 selector 是 xpath，否则是 css selector
+type HeadlessPage interface {
+	Navigate(url string) error
+	Click(by, selector string, right bool) error
+	Input(by, selector, value string) error
+	Select(by, selector string, values []string, selected bool) error
+	WaitVisible(by, selector string) error
+	// Extract 获取元素的文本（target 为 text）或者属性（target 为 attribute）
+	Extract(by, selector, target, attribute string) (string, error)
+	WaitLoad() error
+	WaitIdle(timeout time.Duration) error
+	// Eval 执行 js，hook 为 true 时在每个新文档加载之前执行
+	Eval(js string, hook bool) (string, error)
+	Keyboard(keys string) error
+	SetExtraHeaders(headers map[string]string) error
+	Screenshot() (string, error)
+	HTML() (string, error)
+	URL() (string, error)
+	Close() error
+}
+
+type YakHeadlessStep struct {
+	// navigate / script / click / rightclick / text / select / screenshot / extract / getresource
+	// waitload / waitidle / waitvisible / setheader / addheader / keyboard / sleep / debug
+	Action string
+	// Name 不为空时，步骤的输出可以在 matcher / extractor 中通过 part: name 使用
+	Name string
+	Args map[string]string
+}
+
+func (s *YakHeadlessStep) String() string {
+	var keys []string
+	for k := range s.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var args []string
+	for _, k := range keys {
+		args = append(args, fmt.Sprintf("%v=%v", k, s.Args[k]))
+	}
+	if s.Name != "" {
+		return fmt.Sprintf("%v(%v) %v", s.Action, s.Name, strings.Join(args, " "))
+	}
+	return fmt.Sprintf("%v %v", s.Action, strings.Join(args, " "))
+}
+
+type YakHeadlessBulkConfig struct {
+	Steps []*YakHeadlessStep
+
+	Matcher   *YakMatcher
+	Extractor []*YakExtractor
+}
+
+type NucleiHeadlessResponse struct {
+	URL string
+	// RawRequest 是执行过的步骤
+	RawRequest []byte
+	// RawPacket 是执行完所有步骤之后页面的 html
+	RawPacket []byte
+	// Data 是带名字的步骤的输出
+	Data map[string]string
+}
+
+func (r *NucleiHeadlessResponse) ToVars() map[string]any {
+	vars := map[string]any{
+		"url":     r.URL,
+		"request": string(r.RawRequest),
+		"raw":     string(r.RawPacket),
+		"resp":    string(r.RawPacket),
+	}
+	var data []string
+	for k, v := range r.Data {
+		vars[k] = v
+		data = append(data, v)
+	}
+	sort.Strings(data)
+	vars["data"] = strings.Join(data, "\n")
+	return vars
+}
+
+func getHeadlessStepSelector(args map[string]string) (string, string) {
+	by := strings.ToLower(args["by"])
+	selector := args["selector"]
+	if by == "x" || by == "xpath" || selector == "" {
+		if xpath := args["xpath"]; xpath != "" {
+			return "xpath", xpath
+		}
+	}
+	return "selector", selector
+}
+
+func (y *YakHeadlessBulkConfig) Execute(
+	config *Config,
+	vars map[string]any, params map[string]string, lowhttpConfig *lowhttp.LowhttpExecConfig,
+	callback func(rsp []*NucleiHeadlessResponse, matched bool, extractorResults map[string]any),
+) error {
+	if len(y.Steps) <= 0 {
+		return utils.Error("YakHeadlessBulkConfig steps is empty")
+	}
+
+	renderVars := utils.InterfaceToMapInterface(params)
+	for k, v := range vars {
+		renderVars[k] = v
+	}
+
+	page, err := config.createHeadlessPage(lowhttpConfig)
+	if err != nil {
+		return utils.Errorf("create headless page failed: %s", err)
+	}
+	defer page.Close()
+
+	response := &NucleiHeadlessResponse{Data: make(map[string]string)}
+	var (
+		steps   []string
+		headers = make(map[string]string)
+	)
+	for _, step := range y.Steps {
+		args := make(map[string]string, len(step.Args))
+		for k, v := range step.Args {
+			rendered, err := RenderNucleiTagWithVar(v, renderVars)
+			if err != nil {
+				log.Errorf("YakHeadlessBulkConfig render arg[%v] error: %s", v, err)
+				rendered = v
+			}
+			args[k] = rendered
+		}
+		rendered := &YakHeadlessStep{Action: step.Action, Name: step.Name, Args: args}
+		steps = append(steps, rendered.String())
+		if config.Debug || config.DebugRequest {
+			fmt.Println("---------------------HEADLESS STEP---------------------")
+			fmt.Println(rendered.String())
+		}
+
+		output, err := y.executeStepWithContext(page, rendered, headers, lowhttpConfig)
+		if err != nil {
+			callback(nil, false, nil)
+			return utils.Errorf("headless step[%v] failed: %s", rendered.String(), err)
+		}
+		if step.Name != "" {
+			response.Data[step.Name] = output
+			renderVars[step.Name] = output
+		}
+	}
+
+	response.RawRequest = []byte(strings.Join(steps, "\n"))
+	response.URL, _ = page.URL()
+	html, err := page.HTML()
+	if err != nil {
+		log.Errorf("get headless page html failed: %s", err)
+	}
+	response.RawPacket = []byte(html)
+	if config.Debug || config.DebugResponse {
+		fmt.Println("---------------------HEADLESS RESPONSE---------------------")
+		fmt.Println(html)
+	}
+	y.handleResponse(config, response, renderVars, callback)
+	return nil
+}
+
+// executeStepWithContext 执行步骤，扫描取消（lowhttp 的 ctx 结束）时不再等待页面返回，
+// 卡住的步骤在页面关闭后退出
+func (y *YakHeadlessBulkConfig) executeStepWithContext(page HeadlessPage, step *YakHeadlessStep, headers map[string]string, lowhttpConfig *lowhttp.LowhttpExecConfig) (string, error) {
+	ctx := lowhttpConfig.Ctx
+	if ctx == nil {
+		return y.executeStep(page, step, headers, lowhttpConfig)
+	}
+	type stepResult struct {
+		output string
+		err    error
+	}
+	done := make(chan *stepResult, 1)
+	go func() {
+		output, err := y.executeStep(page, step, headers, lowhttpConfig)
+		done <- &stepResult{output: output, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.output, result.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (y *YakHeadlessBulkConfig) executeStep(page HeadlessPage, step *YakHeadlessStep, headers map[string]string, lowhttpConfig *lowhttp.LowhttpExecConfig) (string, error) {
+	args := step.Args
+	by, selector := getHeadlessStepSelector(args)
+	switch step.Action {
+	case "navigate":
+		return "", page.Navigate(args["url"])
+	case "script":
+		return page.Eval(args["code"], utils.InterfaceToBoolean(args["hook"]))
+	case "click":
+		return "", page.Click(by, selector, false)
+	case "rightclick":
+		return "", page.Click(by, selector, true)
+	case "text":
+		return "", page.Input(by, selector, args["value"])
+	case "select":
+		selected := true
+		if v, ok := args["selected"]; ok {
+			selected = utils.InterfaceToBoolean(v)
+		}
+		return "", page.Select(by, selector, []string{args["value"]}, selected)
+	case "screenshot":
+		return page.Screenshot()
+	case "extract":
+		target := args["target"]
+		if target == "" {
+			target = "text"
+		}
+		return page.Extract(by, selector, target, args["attribute"])
+	case "getresource":
+		return page.Extract(by, selector, "attribute", "src")
+	case "waitload", "waitdom", "waitfcp", "waitfmp":
+		return "", page.WaitLoad()
+	case "waitidle", "waitstable":
+		timeout := time.Second
+		if d := codec.Atof(args["duration"]); d > 0 {
+			timeout = utils.FloatSecondDuration(d)
+		} else if lowhttpConfig.Timeout > 0 {
+			timeout = lowhttpConfig.Timeout
+		}
+		return "", page.WaitIdle(timeout)
+	case "waitvisible":
+		return "", page.WaitVisible(by, selector)
+	case "setheader", "addheader":
+		if part := strings.ToLower(args["part"]); part != "" && part != "request" {
+			log.Warnf("headless action %v only support request part, got: %v", step.Action, part)
+			return "", nil
+		}
+		headers[args["key"]] = args["value"]
+		return "", page.SetExtraHeaders(headers)
+	case "keyboard":
+		return "", page.Keyboard(args["keys"])
+	case "sleep":
+		d := codec.Atof(args["duration"])
+		if d <= 0 {
+			d = 1
+		}
+		if lowhttpConfig.Ctx == nil {
+			time.Sleep(utils.FloatSecondDuration(d))
+			return "", nil
+		}
+		select {
+		case <-time.After(utils.FloatSecondDuration(d)):
+			return "", nil
+		case <-lowhttpConfig.Ctx.Done():
+			return "", lowhttpConfig.Ctx.Err()
+		}
+	case "debug":
+		return "", nil
+	default:
+		log.Warnf("headless action %v is not supported, skipped", step.Action)
+		return "", nil
+	}
+}
+
+func (y *YakHeadlessBulkConfig) handleResponse(
+	config *Config, response *NucleiHeadlessResponse, vars map[string]any,
+	callback func(rsp []*NucleiHeadlessResponse, matched bool, extractorResults map[string]any),
+) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("YakHeadlessBulkConfig handle response failed: %v", err)
+			utils.PrintCurrentGoroutineRuntimeStack()
+		}
+	}()
+
+	vars = utils.MergeGeneralMap(vars, response.ToVars())
+	extractorResults := make(map[string]any)
+	for _, extractor := range y.Extractor {
+		extractorVars, err := extractor.Execute(response.RawPacket, vars)
+		if err != nil {
+			log.Warnf("YakHeadlessBulkConfig extractor.Execute failed: %s", err)
+			continue
+		}
+		for k, v := range extractorVars {
+			v := ExtractResultToString(v)
+			vars[k] = v
+			extractorResults[k] = v
+		}
+	}
+
+	var matched bool
+	if y.Matcher != nil {
+		var err error
+		matched, err = y.Matcher.ExecuteRawWithConfig(config, response.RawPacket, vars)
+		if err != nil {
+			log.Errorf("YakHeadlessBulkConfig matcher.ExecuteRaw failed: %s", err)
+		}
+		if matched {
+			config.executeRawMatcherNamesCallback(y.Matcher, response.RawPacket, vars)
+		}
+	}
+	if config.Debug {
+		fmt.Println("---------------------HEADLESS RESULT---------------------")
+		fmt.Printf("%v Matched: %v\n", response.URL, matched)
+		spew.Dump(extractorResults)
+	}
+	callback([]*NucleiHeadlessResponse{response}, matched, extractorResults)
+}
+
+// simpleHeadlessPage 使用 simulator/simple 实现 HeadlessPage，每个页面独占一个浏览器
+type simpleHeadlessPage struct {
+	browser *simple.VBrowser
+	page    *simple.VPage
+	// timeout 限制每个步骤的执行时间，rod 查找元素时找不到会一直重试
+	timeout time.Duration
+	// ctx 为扫描的 ctx，取消后页面上的操作立即返回
+	ctx context.Context
+}
+
+func newSimpleHeadlessPage(showBrowser bool, lowhttpConfig *lowhttp.LowhttpExecConfig) (HeadlessPage, error) {
+	opts := []simple.BrowserConfigOpt{simple.WithHeadless(!showBrowser)}
+	if lowhttpConfig != nil && len(lowhttpConfig.Proxy) > 0 {
+		opts = append(opts, simple.WithProxy(lowhttpConfig.Proxy[0]))
+	}
+	browser, err := simple.CreateHeadlessBrowserEx(opts...)
+	if err != nil {
+		return nil, err
+	}
+	page, err := browser.NewPage()
+	if err != nil {
+		browser.Close()
+		return nil, err
+	}
+	timeout := defaultHeadlessStepTimeout
+	if lowhttpConfig != nil && lowhttpConfig.Timeout > 0 {
+		timeout = lowhttpConfig.Timeout
+	}
+	headlessPage := &simpleHeadlessPage{browser: browser, page: page, timeout: timeout}
+	if lowhttpConfig != nil {
+		headlessPage.ctx = lowhttpConfig.Ctx
+	}
+	return headlessPage, nil
+}
+
+// withTimeout 在带步骤超时、绑定扫描 ctx 的页面上执行操作，避免页面不返回时一直阻塞
+func (p *simpleHeadlessPage) withTimeout(f func(page *simple.VPage) error) error {
+	page := p.page
+	if p.ctx != nil {
+		page = page.Context(p.ctx)
+	}
+	page = page.Timeout(p.timeout)
+	defer page.CancelTimeout()
+	return f(page)
+}
+
+func (p *simpleHeadlessPage) Navigate(url string) error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		return page.NavigateEx(url)
+	})
+}
+
+func (p *simpleHeadlessPage) Click(by, selector string, right bool) error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		element, err := page.ElementBy(by, selector)
+		if err != nil {
+			return err
+		}
+		button := proto.InputMouseButtonLeft
+		if right {
+			button = proto.InputMouseButtonRight
+		}
+		return element.Click(button, 1)
+	})
+}
+
+func (p *simpleHeadlessPage) Input(by, selector, value string) error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		element, err := page.ElementBy(by, selector)
+		if err != nil {
+			return err
+		}
+		return element.Input(value)
+	})
+}
+
+func (p *simpleHeadlessPage) Select(by, selector string, values []string, selected bool) error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		element, err := page.ElementBy(by, selector)
+		if err != nil {
+			return err
+		}
+		return element.Select(values, selected, rod.SelectorTypeText)
+	})
+}
+
+func (p *simpleHeadlessPage) WaitVisible(by, selector string) error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		element, err := page.ElementBy(by, selector)
+		if err != nil {
+			return err
+		}
+		return element.WaitVisible()
+	})
+}
+
+func (p *simpleHeadlessPage) Extract(by, selector, target, attribute string) (string, error) {
+	var result string
+	err := p.withTimeout(func(page *simple.VPage) error {
+		element, err := page.ElementBy(by, selector)
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(target) == "attribute" {
+			attr, err := element.Attribute(attribute)
+			if err != nil || attr == nil {
+				return err
+			}
+			result = *attr
+			return nil
+		}
+		result, err = element.Text()
+		return err
+	})
+	return result, err
+}
+
+func (p *simpleHeadlessPage) WaitLoad() error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		return page.WaitLoad()
+	})
+}
+
+func (p *simpleHeadlessPage) WaitIdle(timeout time.Duration) error {
+	return p.page.WaitIdle(timeout)
+}
+
+func (p *simpleHeadlessPage) Eval(js string, hook bool) (string, error) {
+	var result string
+	err := p.withTimeout(func(page *simple.VPage) error {
+		if hook {
+			return page.EvalOnNewDocument(js)
+		}
+		var err error
+		result, err = page.Eval(js)
+		return err
+	})
+	return result, err
+}
+
+func (p *simpleHeadlessPage) Keyboard(keys string) error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		return page.Keyboard(keys)
+	})
+}
+
+func (p *simpleHeadlessPage) SetExtraHeaders(headers map[string]string) error {
+	return p.withTimeout(func(page *simple.VPage) error {
+		return page.SetExtraHeaders(headers)
+	})
+}
+
+func (p *simpleHeadlessPage) Screenshot() (string, error) {
+	var result string
+	err := p.withTimeout(func(page *simple.VPage) error {
+		var err error
+		result, err = page.ScreenShot()
+		return err
+	})
+	return result, err
+}
+
+func (p *simpleHeadlessPage) HTML() (string, error) {
+	var result string
+	err := p.withTimeout(func(page *simple.VPage) error {
+		var err error
+		result, err = page.HTML()
+		return err
+	})
+	return result, err
+}
+
+func (p *simpleHeadlessPage) URL() (string, error) {
+	return p.page.URL()
+}
+
+func (p *simpleHeadlessPage) Close() error {
+	p.page.Close()
+	return p.browser.Close()
+}
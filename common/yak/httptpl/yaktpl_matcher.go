@@ -35,7 +35,9 @@ type YakMatcher struct {
 	// word
 	// regexp
 	// expr
-	Id          int
+	Id int
+	// Name 是 nuclei 中 matcher 的名字，workflow 通过名字决定是否执行子模板
+	Name        string
 	MatcherType string
 	/*
 		nuclei-dsl
@@ -70,6 +72,23 @@ type YakMatcher struct {
 
 var matcherResponseCache = utils.NewTTLCache[string](1 * time.Minute)
 
+// builtinMatcherScopes 是直接从响应报文中获取的 scope
+var builtinMatcherScopes = []string{
+	"", "status", "status_code", "header", "body", "raw", "all", "interactsh_protocol", "oob_protocol",
+}
+
+// getProtocolScopeMaterial 获取非 HTTP 协议的响应字段（dns 的 answer / headless 的 data 等），由执行器放在 vars 中
+func getProtocolScopeMaterial(scope string, vars map[string]any) (string, bool) {
+	if utils.StringArrayContains(builtinMatcherScopes, scope) {
+		return "", false
+	}
+	v, ok := vars[scope]
+	if !ok {
+		return "", false
+	}
+	return utils.InterfaceToString(v), true
+}
+
 func cacheHash(rsp []byte, location string) string {
 	return utils.CalcSha1(rsp, location)
 }
@@ -155,6 +174,9 @@ func (y *YakMatcher) executeRaw(config *Config, rsp []byte, duration float64, va
 		}
 		var material string
 		scope := strings.ToLower(y.Scope)
+		if v, ok := getProtocolScopeMaterial(scope, vars); ok {
+			return v
+		}
 		scopeHash := cacheHash(rsp, scope)
 
 		material, ok := matcherResponseCache.Get(scopeHash)
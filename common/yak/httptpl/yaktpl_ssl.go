@@ -0,0 +1,329 @@
+package httptpl
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/netx"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+)
+
+type YakSSLBulkConfig struct {
+	// Address 默认为 {{Host}}:{{Port}}
+	Address string
+	// MinVersion / MaxVersion: ssl30 / tls10 / tls11 / tls12 / tls13
+	MinVersion   string
+	MaxVersion   string
+	CipherSuites []string
+	SNI          string
+	// GMTLS 为 true 时使用国密 SSL 握手
+	GMTLS bool
+	// TLSVersionEnum / TLSCipherEnum 为 true 时，会逐个尝试协议版本与不安全的加密套件
+	TLSVersionEnum bool
+	TLSCipherEnum  bool
+
+	Matcher   *YakMatcher
+	Extractor []*YakExtractor
+}
+
+type NucleiSSLResponse struct {
+	Address    string
+	RawRequest []byte
+	// RawPacket 是 json 格式的握手与证书信息
+	RawPacket []byte
+
+	Fields     map[string]any
+	Connection *netx.TLSInspectConnectionResult
+}
+
+func (r *NucleiSSLResponse) ToVars() map[string]any {
+	vars := make(map[string]any, len(r.Fields)+3)
+	for k, v := range r.Fields {
+		vars[k] = v
+	}
+	vars["request"] = string(r.RawRequest)
+	vars["raw"] = string(r.RawPacket)
+	vars["response"] = string(r.RawPacket)
+	return vars
+}
+
+func certificateFingerprints(cert *x509.Certificate) map[string]string {
+	md5Sum := md5.Sum(cert.Raw)
+	sha1Sum := sha1.Sum(cert.Raw)
+	sha256Sum := sha256.Sum256(cert.Raw)
+	return map[string]string{
+		"md5":    hex.EncodeToString(md5Sum[:]),
+		"sha1":   hex.EncodeToString(sha1Sum[:]),
+		"sha256": hex.EncodeToString(sha256Sum[:]),
+	}
+}
+
+func isSelfSignedCertificate(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false
+	}
+	return cert.CheckSignatureFrom(cert) == nil
+}
+
+// generateSSLFields 生成 ssl 模板中可以使用的字段，字段名与 nuclei 的 ssl 协议保持一致
+func generateSSLFields(host string, result *netx.TLSInspectConnectionResult) map[string]any {
+	fields := map[string]any{
+		"host":                 host,
+		"probe_status":         result != nil,
+		"tls_version":          "",
+		"cipher":               "",
+		"is_gmtls":             false,
+		"expired":              false,
+		"self_signed":          false,
+		"mismatched":           false,
+		"untrusted":            false,
+		"wildcard_certificate": false,
+	}
+	if result == nil {
+		return fields
+	}
+	fields["address"] = result.Address
+	fields["sni"] = result.ServerName
+	fields["tls_version"] = result.VersionName
+	fields["cipher"] = result.CipherSuiteName
+	fields["is_gmtls"] = result.IsGMTLS
+	fields["untrusted"] = !result.Verified
+	fields["verify_error"] = result.VerifyError
+
+	var chain []string
+	for _, cert := range result.Certificates {
+		chain = append(chain, cert.Subject.String())
+	}
+	fields["certificate_chain"] = chain
+	if len(result.Certificates) <= 0 {
+		return fields
+	}
+
+	leaf := result.Certificates[0]
+	now := time.Now()
+	var names []string
+	names = append(names, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		names = append(names, ip.String())
+	}
+	domains := append([]string{}, names...)
+	if leaf.Subject.CommonName != "" {
+		domains = append(domains, leaf.Subject.CommonName)
+	}
+	var wildcard bool
+	for _, name := range domains {
+		if strings.HasPrefix(name, "*.") {
+			wildcard = true
+		}
+	}
+	fields["not_before"] = leaf.NotBefore.Format(time.RFC3339)
+	fields["not_after"] = leaf.NotAfter.Format(time.RFC3339)
+	fields["subject_dn"] = leaf.Subject.String()
+	fields["subject_cn"] = leaf.Subject.CommonName
+	fields["subject_org"] = leaf.Subject.Organization
+	fields["subject_an"] = names
+	fields["domains"] = utils.RemoveRepeatStringSlice(domains)
+	fields["serial"] = hex.EncodeToString(leaf.SerialNumber.Bytes())
+	fields["issuer_dn"] = leaf.Issuer.String()
+	fields["issuer_cn"] = leaf.Issuer.CommonName
+	fields["issuer_org"] = leaf.Issuer.Organization
+	fields["fingerprint_hash"] = certificateFingerprints(leaf)
+	fields["wildcard_certificate"] = wildcard
+	fields["expired"] = now.After(leaf.NotAfter) || now.Before(leaf.NotBefore)
+	fields["self_signed"] = isSelfSignedCertificate(leaf)
+	fields["mismatched"] = leaf.VerifyHostname(host) != nil
+	return fields
+}
+
+func (y *YakSSLBulkConfig) inspectOptions(sni string, lowhttpConfig *lowhttp.LowhttpExecConfig) ([]netx.TLSInspectOption, error) {
+	opts := []netx.TLSInspectOption{netx.TLSInspect_WithSNI(sni)}
+	if lowhttpConfig.Timeout > 0 {
+		opts = append(opts, netx.TLSInspect_WithTimeout(lowhttpConfig.Timeout))
+	}
+	if len(lowhttpConfig.Proxy) > 0 {
+		opts = append(opts, netx.TLSInspect_WithProxy(lowhttpConfig.Proxy...))
+	}
+	if y.GMTLS || lowhttpConfig.GmTLS {
+		opts = append(opts, netx.TLSInspect_WithGMTLS(true))
+	}
+	var minVersion, maxVersion uint16 = tls.VersionSSL30, tls.VersionTLS13 // nolint[:staticcheck]
+	var err error
+	if y.MinVersion != "" {
+		minVersion, err = netx.ParseTLSVersion(y.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if y.MaxVersion != "" {
+		maxVersion, err = netx.ParseTLSVersion(y.MaxVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	opts = append(opts, netx.TLSInspect_WithVersion(minVersion, maxVersion))
+	var suites []uint16
+	for _, c := range y.CipherSuites {
+		suite, err := netx.ParseTLSCipherSuite(c)
+		if err != nil {
+			return nil, err
+		}
+		suites = append(suites, suite)
+	}
+	if len(suites) > 0 {
+		opts = append(opts, netx.TLSInspect_WithCipherSuites(suites...))
+	}
+	return opts, nil
+}
+
+// enumerate 逐个尝试 tls 版本以及不安全的加密套件
+func (y *YakSSLBulkConfig) enumerate(address string, opts []netx.TLSInspectOption) ([]string, []string) {
+	var versions, ciphers []string
+	for _, version := range []uint16{tls.VersionSSL30, tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13} { // nolint[:staticcheck]
+		versionOpts := append(append([]netx.TLSInspectOption{}, opts...), netx.TLSInspect_WithVersion(version, version))
+		if _, err := netx.TLSInspectConnection(address, versionOpts...); err != nil {
+			continue
+		}
+		versions = append(versions, netx.TLSVersionToString(version))
+		if !y.TLSCipherEnum || version == tls.VersionTLS13 {
+			// tls1.3 的加密套件不可配置
+			continue
+		}
+		for _, suite := range tls.InsecureCipherSuites() {
+			cipherOpts := append(append([]netx.TLSInspectOption{}, versionOpts...), netx.TLSInspect_WithCipherSuites(suite.ID))
+			if _, err := netx.TLSInspectConnection(address, cipherOpts...); err == nil {
+				ciphers = append(ciphers, suite.Name)
+			}
+		}
+	}
+	return versions, utils.RemoveRepeatStringSlice(ciphers)
+}
+
+func (y *YakSSLBulkConfig) Execute(
+	config *Config,
+	vars map[string]any, params map[string]string, lowhttpConfig *lowhttp.LowhttpExecConfig,
+	callback func(rsp []*NucleiSSLResponse, matched bool, extractorResults map[string]any),
+) error {
+	renderVars := utils.InterfaceToMapInterface(params)
+	for k, v := range vars {
+		renderVars[k] = v
+	}
+
+	address, err := RenderNucleiTagWithVar(y.Address, renderVars)
+	if err != nil {
+		return utils.Errorf("YakSSLBulkConfig render address[%v] error: %s", y.Address, err)
+	}
+	host, _, _ := utils.ParseStringToHostPort(address)
+	if host == "" {
+		host = params["Host"]
+	}
+	sni := host
+	if y.SNI != "" {
+		sni, err = RenderNucleiTagWithVar(y.SNI, renderVars)
+		if err != nil {
+			return utils.Errorf("YakSSLBulkConfig render sni[%v] error: %s", y.SNI, err)
+		}
+	}
+	opts, err := y.inspectOptions(sni, lowhttpConfig)
+	if err != nil {
+		return err
+	}
+
+	gmTLS := y.GMTLS || lowhttpConfig.GmTLS
+	rawRequest := fmt.Sprintf("address: %v\nsni: %v\nmin_version: %v\nmax_version: %v\ncipher_suites: %v\ngmtls: %v",
+		address, sni, y.MinVersion, y.MaxVersion, strings.Join(y.CipherSuites, ","), gmTLS)
+	if config.Debug || config.DebugRequest {
+		fmt.Println("---------------------SSL REQUEST---------------------")
+		fmt.Println(rawRequest)
+	}
+	result, err := netx.TLSInspectConnection(address, opts...)
+	if err != nil {
+		// 握手失败也需要执行 matcher，模板可以通过 probe_status 判断是否支持某个协议版本
+		log.Debugf("inspect tls[%v] failed: %s", address, err)
+	}
+
+	fields := generateSSLFields(host, result)
+	if gmTLS && (y.TLSVersionEnum || y.TLSCipherEnum) {
+		// 国密握手固定协商 GMSSL，不受版本范围限制，逐个版本尝试会把所有版本都当作支持
+		var versions []string
+		if result != nil {
+			versions = append(versions, result.VersionName)
+		}
+		fields["tls_version_enum"], fields["tls_cipher_enum"] = versions, []string(nil)
+	} else if y.TLSVersionEnum || y.TLSCipherEnum {
+		fields["tls_version_enum"], fields["tls_cipher_enum"] = y.enumerate(address, opts)
+	}
+	packet, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return utils.Errorf("marshal ssl response failed: %s", err)
+	}
+	response := &NucleiSSLResponse{
+		Address:    address,
+		RawRequest: []byte(rawRequest),
+		RawPacket:  packet,
+		Fields:     fields,
+		Connection: result,
+	}
+	if config.Debug || config.DebugResponse {
+		fmt.Println("---------------------SSL RESPONSE---------------------")
+		fmt.Println(string(packet))
+	}
+	y.handleResponse(config, response, renderVars, callback)
+	return nil
+}
+
+func (y *YakSSLBulkConfig) handleResponse(
+	config *Config, response *NucleiSSLResponse, vars map[string]any,
+	callback func(rsp []*NucleiSSLResponse, matched bool, extractorResults map[string]any),
+) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("YakSSLBulkConfig handle response failed: %v", err)
+			utils.PrintCurrentGoroutineRuntimeStack()
+		}
+	}()
+
+	vars = utils.MergeGeneralMap(vars, response.ToVars())
+	extractorResults := make(map[string]any)
+	for _, extractor := range y.Extractor {
+		extractorVars, err := extractor.Execute(response.RawPacket, vars)
+		if err != nil {
+			log.Warnf("YakSSLBulkConfig extractor.Execute failed: %s", err)
+			continue
+		}
+		for k, v := range extractorVars {
+			v := ExtractResultToString(v)
+			vars[k] = v
+			extractorResults[k] = v
+		}
+	}
+
+	var matched bool
+	if y.Matcher != nil {
+		var err error
+		matched, err = y.Matcher.ExecuteRawWithConfig(config, response.RawPacket, vars)
+		if err != nil {
+			log.Errorf("YakSSLBulkConfig matcher.ExecuteRaw failed: %s", err)
+		}
+		if matched {
+			config.executeRawMatcherNamesCallback(y.Matcher, response.RawPacket, vars)
+		}
+	}
+	if config.Debug {
+		fmt.Println("---------------------SSL RESULT---------------------")
+		fmt.Printf("%v Matched: %v\n", response.Address, matched)
+		spew.Dump(extractorResults)
+	}
+	callback([]*NucleiSSLResponse{response}, matched, extractorResults)
+}
@@ -131,6 +131,9 @@ func (y *YakNetworkBulkConfig) handleConn(
 			if err != nil {
 				log.Errorf("YakNetworkBulkConfig matcher.ExecuteRaw failed: %s", err)
 			}
+			if matched {
+				config.executeRawMatcherNamesCallback(y.Matcher, response.RawPacket, vars)
+			}
 			callback(availableResponse, matched, extractorResults)
 		}
 	}
@@ -0,0 +1,275 @@
+package httptpl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/bizhelper"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+// workflow 中 subtemplates 最多嵌套的层数，避免模板互相引用导致死循环
+const maxWorkflowDepth = 16
+
+type YakWorkflow struct {
+	// Template 是引用的模板路径，可以是文件也可以是目录
+	Template string
+	// Tags 按 tags 选择模板
+	Tags []string
+
+	// Matchers 根据模板中命中的 matcher 名字来选择执行的子模板
+	Matchers []*YakWorkflowMatcher
+	// Subtemplates 在模板命中之后执行（Matchers 为空时生效）
+	Subtemplates []*YakWorkflow
+}
+
+type YakWorkflowMatcher struct {
+	Names []string
+	// or / and
+	Condition    string
+	Subtemplates []*YakWorkflow
+}
+
+func (m *YakWorkflowMatcher) Match(names map[string]bool) bool {
+	if strings.ToLower(strings.TrimSpace(m.Condition)) == "and" {
+		for _, name := range m.Names {
+			if !names[name] {
+				return false
+			}
+		}
+		return len(m.Names) > 0
+	}
+	for _, name := range m.Names {
+		if names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (y *YakTemplate) execWorkflows(u string, config *Config, opts ...lowhttp.LowhttpOpt) (int, error) {
+	loader := config.WorkflowTemplateLoader
+	if loader == nil {
+		loader = defaultWorkflowTemplateLoader
+	}
+
+	var count int64
+	swg := utils.NewSizedWaitGroup(config.ConcurrentInTemplates)
+	for _, workflow := range y.Workflows {
+		workflow := workflow
+		swg.Add()
+		go func() {
+			defer swg.Done()
+			atomic.AddInt64(&count, workflow.execute(u, config, loader, 0, opts...))
+		}()
+	}
+	swg.Wait()
+	return int(count), nil
+}
+
+func (w *YakWorkflow) execute(
+	u string, config *Config,
+	loader func(string, []string) ([]*YakTemplate, error),
+	depth int, opts ...lowhttp.LowhttpOpt,
+) int64 {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("execute workflow failed: %v", err)
+			utils.PrintCurrentGoroutineRuntimeStack()
+		}
+	}()
+
+	if depth > maxWorkflowDepth {
+		log.Warnf("workflow[%v] is too deep, skipped", w.Template)
+		return 0
+	}
+
+	templates, err := loader(w.Template, w.Tags)
+	if err != nil {
+		log.Errorf("load workflow template[%v] tags%v failed: %s", w.Template, w.Tags, err)
+		return 0
+	}
+
+	var (
+		m            sync.Mutex
+		matched      bool
+		matcherNames = make(map[string]bool)
+	)
+	subConfig := *config
+	subConfig.AppendResultCallback(func(y *YakTemplate, reqBulk any, rsp any, result bool, extractor map[string]interface{}) {
+		if !result {
+			return
+		}
+		m.Lock()
+		defer m.Unlock()
+		matched = true
+	})
+	subConfig.onMatcherNames = func(names []string) {
+		m.Lock()
+		defer m.Unlock()
+		for _, name := range names {
+			matcherNames[name] = true
+		}
+	}
+
+	var count int64
+	for _, tpl := range templates {
+		if len(tpl.Workflows) > 0 {
+			log.Warnf("workflow cannot reference another workflow: %v", tpl.Name)
+			continue
+		}
+		n, err := tpl.ExecWithUrl(u, &subConfig, opts...)
+		if err != nil {
+			log.Errorf("execute workflow template[%v] failed: %s", tpl.Name, err)
+		}
+		count += int64(n)
+	}
+
+	if !matched {
+		return count
+	}
+
+	var subtemplates []*YakWorkflow
+	if len(w.Matchers) > 0 {
+		for _, matcher := range w.Matchers {
+			if matcher.Match(matcherNames) {
+				subtemplates = append(subtemplates, matcher.Subtemplates...)
+			}
+		}
+	} else {
+		subtemplates = w.Subtemplates
+	}
+	for _, sub := range subtemplates {
+		count += sub.execute(u, config, loader, depth+1, opts...)
+	}
+	return count
+}
+
+func defaultWorkflowTemplateLoader(template string, tags []string) ([]*YakTemplate, error) {
+	var templates []*YakTemplate
+	if template != "" {
+		tpls, err := loadWorkflowTemplateByPath(template)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tpls...)
+	}
+	if len(tags) > 0 {
+		tpls, err := loadWorkflowTemplateByTags(tags)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tpls...)
+	}
+	return templates, nil
+}
+
+func loadWorkflowTemplateByPath(template string) ([]*YakTemplate, error) {
+	localPath := utils.GetFirstExistedPath(
+		template,
+		filepath.Join(consts.GetNucleiTemplatesDir(), template),
+		filepath.Join(consts.GetDefaultBaseHomeDir(), "nuclei-templates", template),
+	)
+	if localPath != "" {
+		return loadTemplatesFromLocalPath(localPath)
+	}
+
+	db := consts.GetGormProfileDatabase()
+	if db == nil {
+		return nil, utils.Errorf("cannot found workflow template: %v", template)
+	}
+	db = db.Model(&yakit.YakScript{}).Where("type = 'nuclei'").Where(
+		"(local_path = ?) OR (local_path LIKE ?)",
+		template, strings.TrimRight(template, "/")+"/%",
+	)
+	var templates []*YakTemplate
+	for script := range yakit.YieldYakScripts(db, context.Background()) {
+		tpl, err := CreateYakTemplateFromNucleiTemplateRaw(script.Content)
+		if err != nil {
+			log.Errorf("create yak template failed (workflow): %s", err)
+			continue
+		}
+		templates = append(templates, tpl)
+	}
+	if len(templates) > 0 {
+		return templates, nil
+	}
+
+	// fallback: template id is the file name without extension
+	id := strings.TrimSuffix(filepath.Base(template), filepath.Ext(template))
+	script, err := yakit.GetNucleiYakScriptByName(consts.GetGormProfileDatabase(), id)
+	if err != nil {
+		return nil, utils.Errorf("cannot found workflow template: %v", template)
+	}
+	tpl, err := CreateYakTemplateFromNucleiTemplateRaw(script.Content)
+	if err != nil {
+		return nil, err
+	}
+	return []*YakTemplate{tpl}, nil
+}
+
+func loadTemplatesFromLocalPath(localPath string) ([]*YakTemplate, error) {
+	if !utils.IsDir(localPath) {
+		raw, err := os.ReadFile(localPath)
+		if err != nil {
+			return nil, err
+		}
+		tpl, err := CreateYakTemplateFromNucleiTemplateRaw(string(raw))
+		if err != nil {
+			return nil, err
+		}
+		return []*YakTemplate{tpl}, nil
+	}
+
+	files, err := utils.ReadFilesRecursivelyWithLimit(localPath, 100000)
+	if err != nil {
+		return nil, err
+	}
+	var templates []*YakTemplate
+	for _, f := range files {
+		if f.IsDir {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(f.Path))
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		raw, err := os.ReadFile(f.Path)
+		if err != nil {
+			continue
+		}
+		tpl, err := CreateYakTemplateFromNucleiTemplateRaw(string(raw))
+		if err != nil {
+			log.Errorf("create yak template[%v] failed (workflow): %s", f.Path, err)
+			continue
+		}
+		templates = append(templates, tpl)
+	}
+	return templates, nil
+}
+
+func loadWorkflowTemplateByTags(tags []string) ([]*YakTemplate, error) {
+	db := consts.GetGormProfileDatabase()
+	if db == nil {
+		return nil, utils.Errorf("cannot load workflow templates by tags: %v", tags)
+	}
+	db = bizhelper.FuzzSearchWithStringArrayOrEx(db.Where("type = 'nuclei'"), []string{"tags"}, tags, false)
+	var templates []*YakTemplate
+	for script := range yakit.YieldYakScripts(db, context.Background()) {
+		tpl, err := CreateYakTemplateFromNucleiTemplateRaw(script.Content)
+		if err != nil {
+			log.Errorf("create yak template failed (workflow tags): %s", err)
+			continue
+		}
+		templates = append(templates, tpl)
+	}
+	return templates, nil
+}
@@ -3,40 +3,110 @@ package php2ssa
 import (
 	"github.com/antlr/antlr4/runtime/Go/antlr/v4"
 	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
 	"github.com/yaklang/yaklang/common/yak/antlr4util"
 	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
 	"github.com/yaklang/yaklang/common/yak/ssa"
+	"github.com/yaklang/yaklang/common/yak/ssa4analyze"
 )
 
+type Parser struct {
+}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Parse(src string, must bool, callBack func(*ssa.FunctionBuilder)) (*ssa.Program, error) {
+	return parseSSA(src, must, nil, callBack)
+}
+
+func (p *Parser) Feed(src string, must bool, prog *ssa.Program) {
+	parseSSA(src, must, prog, nil)
+}
+
 type builder struct {
 	ast  phpparser.IHtmlDocumentContext
 	prog *ssa.Program
 	ir   *ssa.FunctionBuilder
+
+	// dir 是当前编译的文件所在目录，用于解析 include / require 的相对路径
+	dir string
+	// included / including 记录 include 过的文件，避免 include_once 重复编译以及循环 include
+	included  map[string]struct{}
+	including map[string]struct{}
+}
+
+func parseSSA(src string, force bool, prog *ssa.Program, callback func(*ssa.FunctionBuilder)) (ret *ssa.Program, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ret = nil
+			err = utils.Errorf("parse error with panic : %v", r)
+		}
+	}()
+
+	if prog == nil {
+		prog = ssa.NewProgram()
+	}
+	ast, err := frontEnd(src, force)
+	if err != nil {
+		return prog, nil
+	}
+
+	funcBuilder := prog.GetAndCreateMainFunctionBuilder()
+	if callback != nil {
+		callback(funcBuilder)
+	}
+	withBuiltinFunctions(funcBuilder)
+	builder := &builder{
+		ast:       ast,
+		prog:      prog,
+		ir:        funcBuilder,
+		included:  make(map[string]struct{}),
+		including: make(map[string]struct{}),
+	}
+	builder.dir = builder.fileDir(prog.GetCurrentFile())
+	if file := prog.GetCurrentFile(); file != "" {
+		builder.including[file] = struct{}{}
+	}
+	builder.Build()
+
+	ssa4analyze.RunAnalyzer(prog)
+	return prog, nil
 }
 
-func ParseSSA(src string, f func(builder *ssa.FunctionBuilder)) (prog *ssa.Program) {
+func frontEnd(src string, must bool) (phpparser.IHtmlDocumentContext, error) {
+	errListener := antlr4util.NewErrorListener()
 	lex := phpparser.NewPHPLexer(antlr.NewInputStream(src))
+	lex.RemoveErrorListeners()
+	lex.AddErrorListener(errListener)
 	tokenStream := antlr.NewCommonTokenStream(lex, antlr.TokenDefaultChannel)
 	parser := phpparser.NewPHPParser(tokenStream)
 	parser.RemoveErrorListeners()
-	parser.AddErrorListener(antlr4util.NewLegacyErrorListener())
-	program := ssa.NewProgram()
-	builder := &builder{
-		prog: program,
-		ast:  parser.HtmlDocument(),
+	parser.AddErrorListener(errListener)
+	parser.SetErrorHandler(antlr.NewDefaultErrorStrategy())
+	ast := parser.HtmlDocument()
+	if must || len(errListener.GetErrors()) == 0 {
+		return ast, nil
 	}
-	builder.Build()
-	for _, r := range builder.prog.GetErrors() {
+	return nil, utils.Errorf("parse AST FrontEnd error : %v", errListener.GetErrors())
+}
+
+// ParseSSA 忽略语法错误编译 php 代码，主要用于调试
+func ParseSSA(src string, f func(builder *ssa.FunctionBuilder)) *ssa.Program {
+	prog, err := parseSSA(src, true, nil, f)
+	if err != nil {
+		log.Errorf("php2ssa parse failed: %v", err)
+		return nil
+	}
+	for _, r := range prog.GetErrors() {
 		log.Errorf("ssa-ir program error: %v", r)
 	}
-	return builder.prog
+	return prog
 }
 
 func (y *builder) Build() {
-	pkg := ssa.NewPackage("main")
-	y.prog.AddPackage(pkg)
-	main := pkg.NewFunction("main")
-	y.ir = ssa.NewBuilder(main, nil)
+	y.buildSuperGlobals()
 	y.VisitHtmlDocument(y.ast)
 	y.ir.Finish()
 }
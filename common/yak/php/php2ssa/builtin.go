@@ -0,0 +1,126 @@
+package php2ssa
+
+import (
+	"strings"
+
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
+
+// superGlobals 是 php 的超全局变量，作为 main 函数的参数（外部输入）处理，
+// 这样在 use-def 链上可以直接追溯到这些数据源
+var superGlobals = []string{
+	"$_GET", "$_POST", "$_COOKIE", "$_REQUEST",
+	"$_SERVER", "$_FILES", "$_ENV", "$_SESSION",
+	"$GLOBALS",
+}
+
+func (y *builder) buildSuperGlobals() {
+	for _, name := range superGlobals {
+		if y.ir.PeekValue(name) != nil {
+			// feed code into an exist program
+			continue
+		}
+		y.ir.NewParam(name)
+	}
+}
+
+var (
+	builtinStringFunc = func(...any) string { return "" }
+	builtinBoolFunc   = func(...any) bool { return false }
+	builtinIntFunc    = func(...any) int { return 0 }
+	builtinAnyFunc    = func(...any) any { return nil }
+)
+
+// builtinFunctions 是常见的 php 内置函数，主要用于标记危险函数（sink）以及过滤函数，
+// 这些函数会作为外部函数编译，调用它们时不会产生 undefined 值
+var builtinFunctions = map[string]any{
+	// 命令执行
+	"system":     builtinStringFunc,
+	"exec":       builtinStringFunc,
+	"shell_exec": builtinStringFunc,
+	"passthru":   builtinAnyFunc,
+	"popen":      builtinAnyFunc,
+	"proc_open":  builtinAnyFunc,
+	"pcntl_exec": builtinAnyFunc,
+
+	// 代码执行
+	"eval":                 builtinAnyFunc,
+	"assert":               builtinBoolFunc,
+	"create_function":      builtinAnyFunc,
+	"call_user_func":       builtinAnyFunc,
+	"call_user_func_array": builtinAnyFunc,
+	"preg_replace":         builtinStringFunc,
+	"unserialize":          builtinAnyFunc,
+
+	// 文件包含与文件操作
+	"include":            builtinAnyFunc,
+	"include_once":       builtinAnyFunc,
+	"require":            builtinAnyFunc,
+	"require_once":       builtinAnyFunc,
+	"file_get_contents":  builtinStringFunc,
+	"file_put_contents":  builtinIntFunc,
+	"fopen":              builtinAnyFunc,
+	"readfile":           builtinIntFunc,
+	"unlink":             builtinBoolFunc,
+	"move_uploaded_file": builtinBoolFunc,
+
+	// 数据库
+	"mysql_query":  builtinAnyFunc,
+	"mysqli_query": builtinAnyFunc,
+	"pg_query":     builtinAnyFunc,
+	"sqlite_query": builtinAnyFunc,
+
+	// 输出
+	"echo":     builtinAnyFunc,
+	"print":    builtinIntFunc,
+	"printf":   builtinIntFunc,
+	"print_r":  builtinAnyFunc,
+	"var_dump": builtinAnyFunc,
+	"header":   builtinAnyFunc,
+
+	// 网络请求
+	"curl_exec": builtinAnyFunc,
+	"fsockopen": builtinAnyFunc,
+
+	// 过滤函数
+	"htmlspecialchars":          builtinStringFunc,
+	"htmlentities":              builtinStringFunc,
+	"strip_tags":                builtinStringFunc,
+	"addslashes":                builtinStringFunc,
+	"escapeshellarg":            builtinStringFunc,
+	"escapeshellcmd":            builtinStringFunc,
+	"mysql_real_escape_string":  builtinStringFunc,
+	"mysqli_real_escape_string": builtinStringFunc,
+	"intval":                    builtinIntFunc,
+	"basename":                  builtinStringFunc,
+
+	// 语言结构
+	"isset": builtinBoolFunc,
+	"empty": builtinBoolFunc,
+	"unset": builtinAnyFunc,
+	"exit":  builtinAnyFunc,
+	"die":   builtinAnyFunc,
+	"count": builtinIntFunc,
+}
+
+// withBuiltinFunctions 把内置函数合并到外部值中，用户传入的外部值优先
+func withBuiltinFunctions(b *ssa.FunctionBuilder) {
+	values := make(map[string]any, len(builtinFunctions)+len(b.ExternInstance))
+	for name, f := range builtinFunctions {
+		values[name] = f
+	}
+	for name, v := range b.ExternInstance {
+		values[name] = v
+	}
+	b.WithExternValue(values)
+}
+
+// builtinFunctionName php 的函数名大小写不敏感，内置函数统一使用小写
+func builtinFunctionName(name string) string {
+	if lower := strings.ToLower(name); lower != name {
+		if _, ok := builtinFunctions[lower]; ok {
+			return lower
+		}
+	}
+	return name
+}
@@ -0,0 +1,20 @@
+package php2ssa
+
+import (
+	"github.com/yaklang/yaklang/common/yak/antlr4util"
+)
+
+func (y *builder) SetRange(token antlr4util.CanStartStopToken) func() {
+	r := antlr4util.GetRange(token)
+	if r == nil {
+		return func() {}
+	}
+	r.FileName = y.prog.GetCurrentFile()
+	ir := y.ir
+	backup := ir.CurrentRange
+	ir.CurrentRange = r
+
+	return func() {
+		ir.CurrentRange = backup
+	}
+}
@@ -0,0 +1,364 @@
+package php2ssa
+
+import (
+	"strings"
+
+	"github.com/yaklang/yaklang/common/log"
+	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
+
+// chainItem 是 chain 的访问结果：一个变量名，一个对象成员，或者是一个不能被赋值的值（如函数调用）
+//
+//	$a          => name
+//	$a[1]->b    => object/key
+//	foo()       => value
+type chainItem struct {
+	name   string
+	object ssa.Value
+	key    ssa.Value
+	value  ssa.Value
+}
+
+func (y *builder) readChainItem(item *chainItem) ssa.Value {
+	switch {
+	case item == nil:
+		return nil
+	case item.key != nil:
+		return y.ir.ReadMemberCallVariable(item.object, item.key)
+	case item.name != "":
+		return y.ir.ReadValue(item.name)
+	default:
+		return item.value
+	}
+}
+
+func (y *builder) leftChainItem(item *chainItem) *ssa.Variable {
+	switch {
+	case item == nil:
+		return nil
+	case item.key != nil:
+		return y.ir.CreateMemberCallVariable(item.object, item.key)
+	case item.name != "":
+		return y.ir.CreateVariable(item.name)
+	default:
+		return nil
+	}
+}
+
+// memberPath 按照 [] / {} 逐层读取成员，返回最后一层的对象与 key，
+// $a[] 这种空下标使用 count($a) 作为 key
+func (y *builder) memberPath(object, key ssa.Value, exprs []phpparser.ISquareCurlyExpressionContext) (ssa.Value, ssa.Value) {
+	for _, expr := range exprs {
+		if key != nil {
+			object = y.ir.ReadMemberCallVariable(object, key)
+		}
+		key = y.VisitSquareCurlyExpression(expr)
+		if key == nil {
+			key = y.emitCall(y.ir.ReadValue("count"), []ssa.Value{object})
+		}
+	}
+	return object, key
+}
+
+func (y *builder) VisitAssignable(raw phpparser.IAssignableContext) ssa.Value {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.AssignableContext)
+	if i == nil {
+		return nil
+	}
+
+	if i.Chain() != nil {
+		return y.VisitChain(i.Chain())
+	} else if i.ArrayCreation() != nil {
+		return y.VisitArrayCreation(i.ArrayCreation())
+	} else {
+		log.Errorf("cannot build leftValue Assignable with: %v", i.GetText())
+		return nil
+	}
+}
+
+// VisitLeftAssignable 返回可以被赋值的变量，[$a, $b] 这种解构赋值返回 nil
+func (y *builder) VisitLeftAssignable(raw phpparser.IAssignableContext) *ssa.Variable {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.AssignableContext)
+	if i == nil {
+		return nil
+	}
+
+	if i.Chain() != nil {
+		return y.VisitLeftChain(i.Chain())
+	}
+	log.Errorf("cannot build leftValue Assignable with: %v", i.GetText())
+	return nil
+}
+
+func (y *builder) VisitChain(raw phpparser.IChainContext) ssa.Value {
+	return y.readChainItem(y.visitChain(raw))
+}
+
+func (y *builder) VisitLeftChain(raw phpparser.IChainContext) *ssa.Variable {
+	variable := y.leftChainItem(y.visitChain(raw))
+	if variable == nil && raw != nil {
+		log.Warnf("cannot assign to: %v", raw.GetText())
+	}
+	return variable
+}
+
+func (y *builder) visitChain(raw phpparser.IChainContext) *chainItem {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.ChainContext)
+	if i == nil {
+		return nil
+	}
+
+	item := y.VisitChainOrigin(i.ChainOrigin())
+	for _, m := range i.AllMemberAccess() {
+		item = y.VisitMemberAccess(y.readChainItem(item), m)
+	}
+	return item
+}
+
+func (y *builder) VisitMemberAccess(object ssa.Value, raw phpparser.IMemberAccessContext) *chainItem {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.MemberAccessContext)
+	if i == nil {
+		return nil
+	}
+
+	item := y.VisitKeyedFieldName(object, i.KeyedFieldName())
+	if i.ActualArguments() != nil {
+		// $a->method(...)
+		return &chainItem{value: y.VisitActualArguments(y.readChainItem(item), i.ActualArguments())}
+	}
+	return item
+}
+
+// VisitActualArguments 调用 callee，foo()()[1] 这种连续调用与下标访问依次处理
+func (y *builder) VisitActualArguments(callee ssa.Value, raw phpparser.IActualArgumentsContext) ssa.Value {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.ActualArgumentsContext)
+	if i == nil {
+		return nil
+	}
+
+	ret := callee
+	for _, a := range i.AllArguments() {
+		ret = y.emitCall(ret, y.VisitArguments(a))
+	}
+
+	if exprs := i.AllSquareCurlyExpression(); len(exprs) > 0 {
+		object, key := y.memberPath(ret, nil, exprs)
+		ret = y.ir.ReadMemberCallVariable(object, key)
+	}
+	return ret
+}
+
+func (y *builder) VisitKeyedFieldName(object ssa.Value, raw phpparser.IKeyedFieldNameContext) *chainItem {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.KeyedFieldNameContext)
+	if i == nil {
+		return nil
+	}
+
+	if ret, ok := i.KeyedSimpleFieldName().(*phpparser.KeyedSimpleFieldNameContext); ok {
+		// $a->b / $a->{"b"} / $a->b[1]
+		var key ssa.Value
+		if ret.Identifier() != nil {
+			key = y.ir.EmitConstInst(y.VisitIdentifier(ret.Identifier()))
+		} else {
+			key = y.VisitExpression(ret.Expression())
+		}
+		object, key = y.memberPath(object, key, ret.AllSquareCurlyExpression())
+		return &chainItem{object: object, key: key}
+	} else if i.KeyedVariable() != nil {
+		// $a->$b
+		key := y.readChainItem(y.VisitKeyedVariable(i.KeyedVariable()))
+		return &chainItem{object: object, key: key}
+	}
+
+	return nil
+}
+
+func (y *builder) VisitKeyedVariable(raw phpparser.IKeyedVariableContext) *chainItem {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.KeyedVariableContext)
+	if i == nil {
+		return nil
+	}
+
+	/*
+		$hello = "world";
+		$a = "hello";
+		echo $$a; // world
+	*/
+	dollarCount := len(i.AllDollar())
+	item := &chainItem{}
+	if i.VarName() != nil {
+		item.name = i.VarName().GetText()
+	} else {
+		// ${expr}
+		dollarCount--
+		item.value = y.VisitExpression(i.Expression())
+		y.dynamicVariable(item)
+	}
+	for ; dollarCount > 0; dollarCount-- {
+		item.value = y.readChainItem(item)
+		y.dynamicVariable(item)
+	}
+
+	if exprs := i.AllSquareCurlyExpression(); len(exprs) > 0 {
+		object, key := y.memberPath(y.readChainItem(item), nil, exprs)
+		return &chainItem{object: object, key: key}
+	}
+	return item
+}
+
+// dynamicVariable 可变变量，变量名是常量字符串的时候可以确定是哪一个变量
+func (y *builder) dynamicVariable(item *chainItem) {
+	item.name = ""
+	if c, ok := ssa.ToConst(item.value); ok && c.IsString() {
+		item.name = "$" + c.VarString()
+	}
+}
+
+func (y *builder) VisitSquareCurlyExpression(raw phpparser.ISquareCurlyExpressionContext) ssa.Value {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.SquareCurlyExpressionContext)
+	if i == nil {
+		return nil
+	}
+
+	if i.Expression() == nil {
+		/*
+			$a = array("apple", "banana");
+			$a[] = "cherry";
+
+			// 现在，$a 包含 "apple", "banana", "cherry"
+		*/
+		return nil
+	}
+	return y.VisitExpression(i.Expression())
+}
+
+func (y *builder) VisitFunctionCall(raw phpparser.IFunctionCallContext) ssa.Value {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.FunctionCallContext)
+	if i == nil {
+		return nil
+	}
+
+	v := y.VisitFunctionCallName(i.FunctionCallName())
+	return y.VisitActualArguments(v, i.ActualArguments())
+}
+
+func (y *builder) VisitFunctionCallName(raw phpparser.IFunctionCallNameContext) ssa.Value {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.FunctionCallNameContext)
+	if i == nil {
+		return nil
+	}
+
+	if ret := i.QualifiedNamespaceName(); ret != nil {
+		return y.VisitQualifiedNamespaceName(ret)
+	} else if ret := i.ChainBase(); ret != nil {
+		return y.readChainItem(y.VisitChainBase(ret))
+	} else if ret := i.ClassConstant(); ret != nil {
+		return y.VisitClassConstant(ret)
+	} else if ret := i.Parentheses(); ret != nil {
+		return y.VisitParentheses(ret)
+	} else if ret := i.Label(); ret != nil {
+		return y.ir.ReadValue(builtinFunctionName(ret.GetText()))
+	}
+	log.Errorf("BUG: unknown function call name: %v", i.GetText())
+	return nil
+}
+
+func (y *builder) VisitChainOrigin(raw phpparser.IChainOriginContext) *chainItem {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.ChainOriginContext)
+	if i == nil {
+		return nil
+	}
+
+	if ret := i.NewExpr(); ret != nil {
+		return &chainItem{value: y.VisitNewExpr(ret)}
+	} else if ret := i.FunctionCall(); ret != nil {
+		return &chainItem{value: y.VisitFunctionCall(ret)}
+	} else if ret := i.ChainBase(); ret != nil {
+		return y.VisitChainBase(ret)
+	} else {
+		log.Errorf("BUG: unknown chain origin: %v", i.GetText())
+	}
+
+	return nil
+}
+
+func (y *builder) VisitChainBase(raw phpparser.IChainBaseContext) *chainItem {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.ChainBaseContext)
+	if i == nil {
+		return nil
+	}
+
+	vars := i.AllKeyedVariable()
+	if len(vars) == 0 {
+		return nil
+	}
+
+	var class ssa.Value
+	if ret := i.QualifiedStaticTypeRef(); ret != nil {
+		// A::$b
+		class = y.ir.ReadValue(y.resolveName(ret.GetText()))
+	} else if len(vars) == 1 {
+		return y.VisitKeyedVariable(vars[0])
+	} else {
+		// $a::$b
+		class = y.readChainItem(y.VisitKeyedVariable(vars[0]))
+	}
+
+	// 静态属性使用去掉 $ 的名字作为 key，与类中的属性定义一致
+	static, _ := vars[len(vars)-1].(*phpparser.KeyedVariableContext)
+	if static == nil || static.VarName() == nil {
+		return &chainItem{value: class}
+	}
+	name := strings.TrimPrefix(static.VarName().GetText(), "$")
+	object, key := y.memberPath(class, y.ir.EmitConstInst(name), static.AllSquareCurlyExpression())
+	return &chainItem{object: object, key: key}
+}
@@ -1,9 +1,11 @@
 package php2ssa
 
 import (
+	"sort"
+	"strings"
+
 	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
 	"github.com/yaklang/yaklang/common/yak/ssa"
-	"strings"
 )
 
 func (y *builder) VisitNewExpr(raw phpparser.INewExprContext) ssa.Value {
@@ -16,12 +18,42 @@ func (y *builder) VisitNewExpr(raw phpparser.INewExprContext) ssa.Value {
 		return nil
 	}
 
-	t := y.VisitTypeRef(i.TypeRef())
-	_ = t
+	class := y.readClass(i.TypeRef())
+	var args []ssa.Value
 	if i.Arguments() != nil {
-		return nil
+		args = y.VisitArguments(i.Arguments())
 	}
-	return nil
+	if _, ok := ssa.ToMake(class); !ok {
+		// unknown class, treat it as a constructor function
+		return y.ir.EmitCall(y.ir.NewCall(class, args))
+	}
+	// new 得到的对象就是类本身，参数传递给构造函数
+	if ctor, ok := class.GetStringMember("__construct"); ok {
+		y.ir.EmitCall(y.ir.NewCall(ctor, args))
+	}
+	return class
+}
+
+// readClass 读取 new / instanceof 后的类
+func (y *builder) readClass(raw phpparser.ITypeRefContext) ssa.Value {
+	i, _ := raw.(*phpparser.TypeRefContext)
+	if i == nil {
+		return y.ir.EmitConstInstNil()
+	}
+
+	if ret := i.QualifiedNamespaceName(); ret != nil {
+		return y.ir.ReadValue(y.qualifiedName(ret))
+	} else if ret, ok := i.IndirectTypeRef().(*phpparser.IndirectTypeRefContext); ok {
+		// new $className / new $obj->className
+		class := y.readChainItem(y.VisitChainBase(ret.ChainBase()))
+		for _, field := range ret.AllKeyedFieldName() {
+			class = y.readChainItem(y.VisitKeyedFieldName(class, field))
+		}
+		return class
+	} else if ret := i.AnonymousClass(); ret != nil {
+		return y.VisitAnonymousClass(ret)
+	}
+	return y.ir.ReadValue(i.GetText())
 }
 
 func (y *builder) VisitTypeRef(raw phpparser.ITypeRefContext) ssa.Type {
@@ -86,6 +118,8 @@ func (y *builder) VisitClassDeclaration(raw phpparser.IClassDeclarationContext)
 	if i == nil {
 		return nil
 	}
+	recoverRange := y.SetRange(i.BaseParserRuleContext)
+	defer recoverRange()
 
 	// notes #[...] for dec
 	if i.Attributes() != nil {
@@ -137,12 +171,8 @@ func (y *builder) VisitClassDeclaration(raw phpparser.IClassDeclarationContext)
 			}
 		}
 	}
-	_ = objectTemplate
-	_ = mergedTemplate
-
-	for _, field := range i.AllClassStatement() {
-		y.VisitClassStatement(field)
-	}
+	class := y.buildClass(mergedTemplate, i.AllClassStatement())
+	y.ir.WriteVariable(objectTemplate, class)
 
 	//// how to build a template?
 	//// y.ir is a SSA.Function
@@ -161,7 +191,94 @@ func (y *builder) VisitClassDeclaration(raw phpparser.IClassDeclarationContext)
 	return nil
 }
 
-func (y *builder) VisitClassStatement(raw phpparser.IClassStatementContext) interface{} {
+func (y *builder) VisitAnonymousClass(raw phpparser.IAnonymousClassContext) ssa.Value {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.AnonymousClassContext)
+	if i == nil {
+		return nil
+	}
+
+	var mergedTemplate []string
+	if i.Extends() != nil && i.QualifiedStaticTypeRef() != nil {
+		mergedTemplate = append(mergedTemplate, i.QualifiedStaticTypeRef().GetText())
+	}
+	if list, ok := i.InterfaceList().(*phpparser.InterfaceListContext); ok {
+		for _, impl := range list.AllQualifiedStaticTypeRef() {
+			mergedTemplate = append(mergedTemplate, impl.GetText())
+		}
+	}
+	return y.buildClass(mergedTemplate, i.AllClassStatement())
+}
+
+// classMember 是类中的一个成员，属性与常量直接保存值，方法在构建类对象的时候才会编译，
+// 这样方法中的 $this 可以指向类对象
+type classMember struct {
+	name   string
+	value  ssa.Value
+	method func() ssa.Value
+}
+
+func (y *builder) buildClass(parents []string, stmts []phpparser.IClassStatementContext) ssa.Value {
+	var members []*classMember
+	defined := make(map[string]struct{})
+	add := func(ms ...*classMember) {
+		for _, m := range ms {
+			if _, ok := defined[m.name]; ok {
+				continue
+			}
+			defined[m.name] = struct{}{}
+			members = append(members, m)
+		}
+	}
+	for _, stmt := range stmts {
+		add(y.VisitClassStatement(stmt)...)
+	}
+	// extends / implements: 合并父类中没有被覆盖的成员
+	for _, parent := range parents {
+		add(y.inheritMembers(parent)...)
+	}
+
+	ir := y.ir
+	ir.MarkedVariable = ir.CreateVariable("$this")
+	defer func() {
+		ir.MarkedVariable = nil
+	}()
+	return ir.InterfaceAddFieldBuild(len(members),
+		func(i int) ssa.Value {
+			return ssa.NewConst(members[i].name)
+		},
+		func(i int) ssa.Value {
+			if m := members[i]; m.method != nil {
+				return m.method()
+			}
+			return members[i].value
+		},
+	)
+}
+
+func (y *builder) inheritMembers(name string) []*classMember {
+	parent := y.ir.PeekValue(y.resolveName(name))
+	if parent == nil {
+		return nil
+	}
+	var members []*classMember
+	for key, value := range parent.GetAllMember() {
+		c, ok := ssa.ToConst(key)
+		if !ok || !c.IsString() {
+			continue
+		}
+		members = append(members, &classMember{name: c.VarString(), value: value})
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].name < members[j].name
+	})
+	return members
+}
+
+func (y *builder) VisitClassStatement(raw phpparser.IClassStatementContext) []*classMember {
 	if y == nil || raw == nil {
 		return nil
 	}
@@ -170,12 +287,15 @@ func (y *builder) VisitClassStatement(raw phpparser.IClassStatementContext) inte
 	if i == nil {
 		return nil
 	}
+	recoverRange := y.SetRange(i.BaseParserRuleContext)
+	defer recoverRange()
 
 	// note: PHP8 #[...] attributes
 	if i.Attributes() != nil {
 		// handle php8
 	}
 
+	var members []*classMember
 	var memberDecorationVerbose string
 	if i.PropertyModifiers() != nil {
 		// handle variable
@@ -185,32 +305,42 @@ func (y *builder) VisitClassStatement(raw phpparser.IClassStatementContext) inte
 			y.VisitTypeHint(i.TypeHint())
 		}
 
-		// handle variable name
+		// handle variable name, $this->name without `$`
 		for _, va := range i.AllVariableInitializer() {
-			y.VisitVariableInitializer(va)
-		}
-
-		return nil
-	} else if i.MemberModifiers() != nil {
-		memberDecorationVerbose = i.MemberModifiers().GetText()
-		// const / function
-		if i.Const() != nil {
-			// handle const
-			if i.TypeHint() != nil {
-				varType := y.VisitTypeHint(i.TypeHint())
-				_ = varType
+			name, value := y.VisitVariableInitializer(va)
+			if value == nil {
+				value = y.ir.EmitConstInstNil()
 			}
-			for _, c := range i.AllIdentifierInitializer() {
-				y.VisitIdentifierInitializer(c)
+			members = append(members, &classMember{name: strings.TrimPrefix(name, "$"), value: value})
+		}
+	} else if i.Const() != nil {
+		// handle const
+		if i.TypeHint() != nil {
+			varType := y.VisitTypeHint(i.TypeHint())
+			_ = varType
+		}
+		for _, c := range i.AllIdentifierInitializer() {
+			name, value := y.VisitIdentifierInitializer(c)
+			if name == "" || value == nil {
+				continue
 			}
-		} else if i.Function_() != nil {
-			isFuncRef := i.Ampersand() != nil
-			var funcName = i.Identifier()
+			members = append(members, &classMember{name: name, value: value})
+		}
+	} else if i.Function_() != nil {
+		if i.MemberModifiers() != nil {
+			memberDecorationVerbose = i.MemberModifiers().GetText()
+		}
+		isFuncRef := i.Ampersand() != nil
+		funcName := i.Identifier().GetText()
+		_ = isFuncRef
+
+		members = append(members, &classMember{name: funcName, method: func() ssa.Value {
+			newFunc := y.ir.NewFunc(funcName)
+			y.ir = y.ir.PushFunction(newFunc)
 			if i.FormalParameterList() != nil {
 				// handle formal parameter list
 				y.VisitFormalParameterList(i.FormalParameterList())
 			}
-			_, _ = isFuncRef, funcName
 
 			// baseCtorCall
 			if i.BaseCtorCall() != nil {
@@ -222,14 +352,22 @@ func (y *builder) VisitClassStatement(raw phpparser.IClassStatementContext) inte
 			}
 
 			y.VisitMethodBody(i.MethodBody())
-		}
+			y.ir.Finish()
+			y.ir = y.ir.PopFunction()
+			return newFunc
+		}})
 	} else if i.Use() != nil {
-		y.VisitQualifiedNamespaceNameList(i.QualifiedNamespaceNameList())
+		// use trait
+		if list, ok := i.QualifiedNamespaceNameList().(*phpparser.QualifiedNamespaceNameListContext); ok {
+			for _, name := range list.AllQualifiedNamespaceName() {
+				members = append(members, y.inheritMembers(name.GetText())...)
+			}
+		}
 		y.VisitTraitAdaptations(i.TraitAdaptations())
 	}
 	_ = memberDecorationVerbose
 
-	return nil
+	return members
 }
 
 func (y *builder) VisitTraitAdaptations(raw phpparser.ITraitAdaptationsContext) interface{} {
@@ -320,6 +458,9 @@ func (y *builder) VisitMethodBody(raw phpparser.IMethodBodyContext) interface{}
 		return nil
 	}
 
+	if i.BlockStatement() != nil {
+		y.VisitBlockStatement(i.BlockStatement())
+	}
 	return nil
 }
 
@@ -412,17 +553,18 @@ func (y *builder) VisitFormalParameter(raw phpparser.IFormalParameterContext) in
 	return nil
 }
 
-func (y *builder) VisitIdentifierInitializer(raw phpparser.IIdentifierInitializerContext) interface{} {
+// VisitIdentifierInitializer read ast and return const name and ssaValue
+func (y *builder) VisitIdentifierInitializer(raw phpparser.IIdentifierInitializerContext) (string, ssa.Value) {
 	if y == nil || raw == nil {
-		return nil
+		return "", nil
 	}
 
 	i, _ := raw.(*phpparser.IdentifierInitializerContext)
 	if i == nil {
-		return nil
+		return "", nil
 	}
 
-	return nil
+	return y.VisitIdentifier(i.Identifier()), y.VisitConstantInitializer(i.ConstantInitializer())
 }
 
 // VisitVariableInitializer read ast and return varName and ssaValue
@@ -451,7 +593,29 @@ func (y *builder) VisitClassConstant(raw phpparser.IClassConstantContext) ssa.Va
 		return nil
 	}
 
-	panic("CLASS CONSTANT TODO")
+	if i.Class() != nil || i.Parent_() != nil {
+		// parent::__construct / class::name
+		return y.ir.ReadValue(i.GetText())
+	}
 
-	return nil
+	var class ssa.Value
+	if ret := i.QualifiedStaticTypeRef(); ret != nil {
+		class = y.ir.ReadValue(y.resolveName(ret.GetText()))
+	} else if ret := i.String_(); ret != nil {
+		class = y.ir.ReadValue(y.resolveName(y.VisitString_(ret).String()))
+	} else {
+		class = y.readChainItem(y.VisitKeyedVariable(i.KeyedVariable(0)))
+	}
+
+	// A::CONST / A::method / A::$static
+	var key ssa.Value
+	if ret := i.Identifier(); ret != nil {
+		key = y.ir.EmitConstInst(ret.GetText())
+	} else if ret := i.KeyedVariable(len(i.AllKeyedVariable()) - 1); ret != nil {
+		key = y.ir.EmitConstInst(strings.TrimPrefix(ret.GetText(), "$"))
+	}
+	if key == nil {
+		return class
+	}
+	return y.ir.ReadMemberCallVariable(class, key)
 }
@@ -16,10 +16,9 @@ func (y *builder) VisitBreakStatement(raw phpparser.IBreakStatementContext) inte
 		return nil
 	}
 
-	if break_ := y.ir.GetBreak(); break_ != nil {
-		return y.ir.EmitJump(break_)
+	if !y.ir.Break() {
+		log.Errorf("break statement not in loop or switch: raw %v", i.GetText())
 	}
-	log.Errorf("break statement not in loop or switch: raw %v", i.GetText())
 	return nil
 }
 
@@ -12,5 +12,6 @@ func (y *builder) VisitEchoStatement(raw phpparser.IEchoStatementContext) interf
 		return nil
 	}
 
-	return nil
+	args := y.VisitExpressionList(i.ExpressionList())
+	return y.emitCall(y.ir.ReadValue("echo"), args)
 }
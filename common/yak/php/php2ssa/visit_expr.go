@@ -1,6 +1,10 @@
 package php2ssa
 
 import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr/v4"
+	"github.com/google/uuid"
 	"github.com/yaklang/yaklang/common/log"
 	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
 	"github.com/yaklang/yaklang/common/yak/ssa"
@@ -48,37 +52,30 @@ func (y *builder) VisitExpression(raw phpparser.IExpressionContext) ssa.Value {
 		return nil
 	}
 
+	ret := y.visitExpression(raw)
+	if ret == nil && !y.ir.IsBlockFinish() {
+		// keep use-def chain complete, unhandled expression as undefined value
+		ret = y.ir.EmitUndefined(raw.GetText())
+	}
+	return ret
+}
+
+func (y *builder) visitExpression(raw phpparser.IExpressionContext) ssa.Value {
 	switch ret := raw.(type) {
 	case *phpparser.CloneExpressionContext:
 		// 浅拷贝，一个对象
 		// 如果类定义了 __clone，就执行 __clone
 		target := y.VisitExpression(ret.Expression())
-		checkCloneBuildin := y.ir.BuildIf()
-		checkCloneBuildin.BuildCondition(func() ssa.Value {
-			return y.ir.EmitBinOp(
-				ssa.OpNotEq,
-				y.ir.EmitField(target, y.ir.EmitConstInst("__clone")),
-				y.ir.EmitConstInstNil(),
-			)
-		})
-
-		checkCloneBuildin.BuildTrue(func() {
-			// have __clone
-			calling := y.ir.NewCall(
-				y.ir.EmitField(target, y.ir.EmitConstInst("__clone")),
-				nil,
-			)
-			y.ir.EmitCall(calling)
-		})
-
-		//
-		return nil
+		if clone, ok := target.GetStringMember("__clone"); ok {
+			y.emitCall(clone, nil)
+		}
+		return target
 	case *phpparser.KeywordNewExpressionContext:
 		return y.VisitNewExpr(ret.NewExpr())
 	case *phpparser.IndexerExpressionContext:
 		v1 := y.VisitStringConstant(ret.StringConstant())
 		indexKey := y.VisitExpression(ret.Expression())
-		return y.ir.EmitField(v1, indexKey)
+		return y.ir.ReadMemberCallVariable(v1, indexKey)
 	case *phpparser.CastExpressionContext:
 		target := y.VisitExpression(ret.Expression())
 		return y.ir.EmitTypeCast(target, y.VisitCastOperation(ret.CastOperation()))
@@ -108,31 +105,31 @@ func (y *builder) VisitExpression(raw phpparser.IExpressionContext) ssa.Value {
 			return val
 		}
 	case *phpparser.PrefixIncDecExpressionContext:
-		val := y.VisitChain(ret.Chain())
-		if ret.Inc() != nil {
-			after := y.ir.EmitBinOp(ssa.OpAdd, val, y.ir.EmitConstInst(1))
-			y.ir.EmitUpdate(val, after)
-			return after
-		} else if ret.Dec() != nil {
-			after := y.ir.EmitBinOp(ssa.OpSub, val, y.ir.EmitConstInst(1))
-			y.ir.EmitUpdate(val, after)
-			return after
+		variable := y.VisitLeftChain(ret.Chain())
+		if variable == nil {
+			return y.VisitChain(ret.Chain())
 		}
-		return y.ir.EmitConstInstNil()
+		op := ssa.OpAdd
+		if ret.Dec() != nil {
+			op = ssa.OpSub
+		}
+		after := y.ir.EmitBinOp(op, y.ir.ReadValueByVariable(variable), y.ir.EmitConstInst(1))
+		y.ir.AssignVariable(variable, after)
+		return after
 	case *phpparser.PostfixIncDecExpressionContext:
-		val := y.VisitChain(ret.Chain())
-		if ret.Inc() != nil {
-			after := y.ir.EmitBinOp(ssa.OpAdd, val, y.ir.EmitConstInst(1))
-			y.ir.EmitUpdate(val, after)
-			return val
-		} else if ret.Dec() != nil {
-			after := y.ir.EmitBinOp(ssa.OpSub, val, y.ir.EmitConstInst(1))
-			y.ir.EmitUpdate(val, after)
-			return val
+		variable := y.VisitLeftChain(ret.Chain())
+		if variable == nil {
+			return y.VisitChain(ret.Chain())
 		}
-		return y.ir.EmitConstInstNil()
+		op := ssa.OpAdd
+		if ret.Dec() != nil {
+			op = ssa.OpSub
+		}
+		val := y.ir.ReadValueByVariable(variable)
+		y.ir.AssignVariable(variable, y.ir.EmitBinOp(op, val, y.ir.EmitConstInst(1)))
+		return val
 	case *phpparser.PrintExpressionContext:
-		return y.ir.EmitConstInst(1)
+		return y.emitCall(y.ir.ReadValue("print"), []ssa.Value{y.VisitExpression(ret.Expression())})
 	case *phpparser.ArrayCreationExpressionContext:
 		// arrayCreation
 		return y.VisitArrayCreation(ret.ArrayCreation())
@@ -143,48 +140,26 @@ func (y *builder) VisitExpression(raw phpparser.IExpressionContext) ssa.Value {
 			return y.VisitConstant(i)
 		} else if i := ret.String_(); i != nil {
 			return y.VisitString_(i)
-		} else if ret.Label() != nil {
+		} else if i := ret.Label(); i != nil {
 			return y.ir.EmitConstInst(i.GetText())
 		} else {
 			log.Warnf("PHP Scalar Expr Failed: %s", ret.GetText())
 		}
 	case *phpparser.BackQuoteStringExpressionContext:
+		// `cmd` 等价于 shell_exec("cmd")
 		r := ret.GetText()
 		if len(r) >= 2 {
 			r = r[1 : len(r)-1]
 		}
-		return y.ir.EmitConstInst(r)
+		return y.emitCall(y.ir.ReadValue("shell_exec"), []ssa.Value{y.ir.EmitConstInst(r)})
 	case *phpparser.ParenthesisExpressionContext:
 		return y.VisitParentheses(ret.Parentheses())
 	case *phpparser.SpecialWordExpressionContext:
-		if i := ret.Yield(); i != nil {
-			return y.ir.EmitConstInstNil()
-		} else if i := ret.List(); i != nil {
-
-		} else if i := ret.IsSet(); i != nil {
-
-		} else if i := ret.Empty(); i != nil {
-
-		} else if i := ret.Eval(); i != nil {
-
-		} else if i := ret.Exit(); i != nil {
-
-		} else if i := ret.Include(); i != nil {
-
-		} else if i := ret.IncludeOnce(); i != nil {
-
-		} else if i := ret.Require(); i != nil {
-
-		} else if i := ret.RequireOnce(); i != nil {
-
-		} else if i := ret.Throw(); i != nil {
-
-		} else {
-			log.Errorf("unhandled special word: %v", ret.GetText())
-		}
-		return y.ir.EmitConstInstNil()
+		return y.VisitSpecialWordExpression(ret)
 	case *phpparser.LambdaFunctionExpressionContext:
+		return y.VisitLambdaFunctionExpr(ret.LambdaFunctionExpr())
 	case *phpparser.MatchExpressionContext:
+		return y.VisitMatchExpr(ret.MatchExpr())
 	case *phpparser.ArithmeticExpressionContext:
 		op1 := y.VisitExpression(ret.Expression(0))
 		op2 := y.VisitExpression(ret.Expression(1))
@@ -204,71 +179,48 @@ func (y *builder) VisitExpression(raw phpparser.IExpressionContext) ssa.Value {
 		case "%":
 			o = ssa.OpMod
 		case ".":
-			return y.ir.EmitFieldMust(op1, op2)
+			// string concat
+			o = ssa.OpAdd
 		default:
 			log.Errorf("unhandled arithmetic expression: %v", ret.GetText())
 			return nil
 		}
 		return y.ir.EmitBinOp(o, op1, op2)
 	case *phpparser.InstanceOfExpressionContext:
-		// instanceof
-		panic("NOT IMPL")
+		// instanceof: 类型判断的结果在编译期无法确定
+		y.VisitExpression(ret.Expression())
+		y.readClass(ret.TypeRef())
+		return y.ir.EmitConstInstAny()
 	case *phpparser.ComparisonExpressionContext:
+		var o ssa.BinaryOpcode
 		switch ret.GetOp().GetText() {
 		case "<<":
-			return y.ir.EmitBinOp(ssa.OpShl, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
+			o = ssa.OpShl
 		case ">>":
-			return y.ir.EmitBinOp(ssa.OpShr, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
+			o = ssa.OpShr
 		case "<":
-			return y.ir.EmitBinOp(ssa.OpLt, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
+			o = ssa.OpLt
 		case ">":
-			return y.ir.EmitBinOp(ssa.OpGt, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
+			o = ssa.OpGt
 		case "<=":
-			return y.ir.EmitBinOp(ssa.OpLtEq, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
+			o = ssa.OpLtEq
 		case ">=":
-			return y.ir.EmitBinOp(ssa.OpGtEq, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
-		case "==":
-			return y.ir.EmitBinOp(ssa.OpEq, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
-		case "===":
-			return y.ir.EmitBinOp(ssa.OpEq, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
-		case "!=":
-			return y.ir.EmitBinOp(ssa.OpNotEq, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
-		case "!==":
-			return y.ir.EmitBinOp(ssa.OpNotEq, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
+			o = ssa.OpGtEq
+		case "==", "===":
+			o = ssa.OpEq
+		case "!=", "!==", "<>":
+			o = ssa.OpNotEq
 		default:
 			log.Errorf("unhandled comparison expression: %v", ret.GetText())
+			return y.ir.EmitConstInstNil()
 		}
-		return y.ir.EmitConstInstNil()
+		return y.ir.EmitBinOp(o, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
 	case *phpparser.BitwiseExpressionContext:
 		switch ret.GetOp().GetText() {
 		case "&&":
-			ifStmt := y.ir.BuildIf()
-			var v1, v2 ssa.Value
-			var result ssa.Value
-			ifStmt.BuildCondition(func() ssa.Value {
-				v1 = y.VisitExpression(ret.Expression(0))
-				return v1
-			}).BuildTrue(func() {
-				v2 = y.VisitExpression(ret.Expression(1))
-				result = y.ir.EmitBinOp(ssa.OpEq, v2, y.ir.EmitConstInst(true))
-			}).BuildFalse(func() {
-				result = y.ir.EmitConstInst(false)
-			})
-			ifStmt.Finish()
-			return result
+			return y.logicAnd(ret.Expression(0), ret.Expression(1))
 		case "||":
-			var v1, v2 ssa.Value
-			var result ssa.Value
-			y.ir.BuildIf().BuildCondition(func() ssa.Value {
-				v1 = y.VisitExpression(ret.Expression(0))
-				return v1
-			}).BuildTrue(func() {
-				result = y.ir.EmitConstInst(true)
-			}).BuildFalse(func() {
-				v2 = y.VisitExpression(ret.Expression(1))
-				result = y.ir.EmitBinOp(ssa.OpEq, v2, y.ir.EmitConstInst(true))
-			}).Finish()
-			return result
+			return y.logicOr(ret.Expression(0), ret.Expression(1))
 		case "|":
 			return y.ir.EmitBinOp(ssa.OpOr, y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1)))
 		case "^":
@@ -279,501 +231,479 @@ func (y *builder) VisitExpression(raw phpparser.IExpressionContext) ssa.Value {
 			return y.ir.EmitConstInstNil()
 		}
 	case *phpparser.ConditionalExpressionContext:
+		/*
+			a ? b : c
+			a ?: c
+		*/
 		v1 := y.VisitExpression(ret.Expression(0))
-		exprCount := len(ret.AllExpression())
-		var result ssa.Value
-		ifb := y.ir.BuildIf().BuildCondition(func() ssa.Value {
-			// false 0 nil
-			t1 := y.ir.EmitBinOp(ssa.OpNotEq, v1, y.ir.EmitConstInstNil())
-			t2 := y.ir.EmitBinOp(ssa.OpNotEq, v1, y.ir.EmitConstInst(0))
-			t3 := y.ir.EmitBinOp(ssa.OpNotEq, v1, y.ir.EmitConstInst(false))
-			return y.ir.EmitBinOp(ssa.OpLogicAnd, t1, y.ir.EmitBinOp(ssa.OpLogicAnd, t2, t3))
-		})
-		switch exprCount {
+		switch len(ret.AllExpression()) {
 		case 2:
-			ifb.BuildTrue(func() {
-				result = v1
-			}).BuildFalse(func() {
-				result = y.VisitExpression(ret.Expression(1))
-			}).Finish()
-			return result
+			return y.handlerJumpExpression(
+				func() ssa.Value { return v1 },
+				func() ssa.Value { return v1 },
+				func() ssa.Value { return y.VisitExpression(ret.Expression(1)) },
+			)
 		case 3:
-			ifb.BuildTrue(func() {
-				result = y.VisitExpression(ret.Expression(1))
-			}).BuildFalse(func() {
-				result = y.VisitExpression(ret.Expression(2))
-			}).Finish()
-			return result
+			return y.handlerJumpExpression(
+				func() ssa.Value { return v1 },
+				func() ssa.Value { return y.VisitExpression(ret.Expression(1)) },
+				func() ssa.Value { return y.VisitExpression(ret.Expression(2)) },
+			)
 		default:
 			log.Errorf("unhandled conditional expression: %v", ret.GetText())
 			return y.ir.EmitConstInstNil()
 		}
 	case *phpparser.NullCoalescingExpressionContext:
+		// a ?? b
 		v1 := y.VisitExpression(ret.Expression(0))
-		var result ssa.Value
-		y.ir.BuildIf().BuildCondition(func() ssa.Value {
-			return y.ir.EmitBinOp(ssa.OpEq, v1, y.ir.EmitConstInstNil())
-		}).BuildTrue(func() {
-			result = v1
-		}).BuildFalse(func() {
-			result = y.VisitExpression(ret.Expression(1))
-		})
-		return result
+		return y.handlerJumpExpression(
+			func() ssa.Value { return y.ir.EmitBinOp(ssa.OpNotEq, v1, y.ir.EmitConstInstNil()) },
+			func() ssa.Value { return v1 },
+			func() ssa.Value { return y.VisitExpression(ret.Expression(1)) },
+		)
 	case *phpparser.SpaceshipExpressionContext:
-		var result ssa.Value
-		var v1, v2 = y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1))
-		y.ir.BuildIf().BuildCondition(func() ssa.Value {
-			return y.ir.EmitBinOp(ssa.OpEq, v1, v2)
-		}).BuildTrue(func() {
-			result = y.ir.EmitConstInst(0)
-		}).BuildElif(func() ssa.Value {
-			return y.ir.EmitBinOp(ssa.OpLt, v1, v2)
-		}, func() {
-			result = y.ir.EmitConstInst(-1)
-		}).BuildFalse(func() {
-			result = y.ir.EmitConstInst(1)
-		}).Finish()
-		return result
+		// a <=> b: a == b ? 0 : (a < b ? -1 : 1)
+		v1, v2 := y.VisitExpression(ret.Expression(0)), y.VisitExpression(ret.Expression(1))
+		return y.handlerJumpExpression(
+			func() ssa.Value { return y.ir.EmitBinOp(ssa.OpEq, v1, v2) },
+			func() ssa.Value { return y.ir.EmitConstInst(0) },
+			func() ssa.Value {
+				return y.handlerJumpExpression(
+					func() ssa.Value { return y.ir.EmitBinOp(ssa.OpLt, v1, v2) },
+					func() ssa.Value { return y.ir.EmitConstInst(-1) },
+					func() ssa.Value { return y.ir.EmitConstInst(1) },
+				)
+			},
+		)
 	case *phpparser.ArrayDestructExpressionContext:
 		// [$1, $2, $3] = $arr;
 		// unpacking
+		value := y.VisitExpression(ret.Expression())
+		y.assignArrayDestructuring(ret.ArrayDestructuring(), value)
+		return value
 	case *phpparser.AssignmentExpressionContext:
+		if ret.Attributes() != nil {
+			y.VisitAttributes(ret.Attributes())
+		}
+
 		if ret.AssignmentOperator() != nil {
 			// assignable assignmentOperator attributes? expression        # AssignmentExpression
+			operator := ret.AssignmentOperator().GetText()
+			if assign, ok := ret.Assignable().(*phpparser.AssignableContext); ok && assign.ArrayCreation() != nil {
+				// [$a, $b] = $arr;
+				value := y.VisitExpression(ret.Expression())
+				y.assignArrayCreation(assign.ArrayCreation(), value)
+				return value
+			}
 
-			// left value: chain array creation
-			leftValues := y.VisitAssignable(ret.Assignable())
-
-			var annotation any
-			if ret.Attributes() != nil {
-				annotation = y.VisitAttributes(ret.Attributes())
-				_ = annotation
+			variable := y.VisitLeftAssignable(ret.Assignable())
+			if variable == nil {
+				return y.VisitExpression(ret.Expression())
 			}
 
-			rightValue := y.VisitExpression(ret.Expression())
-			operator := ret.AssignmentOperator()
-			switch operator.GetText() {
-			case "=":
-				break
-			case "+=":
-				rightValue = y.ir.EmitBinOp(ssa.OpAdd, leftValues, rightValue)
-			case "-=":
-				rightValue = y.ir.EmitBinOp(ssa.OpSub, leftValues, rightValue)
-			case "*=":
-				rightValue = y.ir.EmitBinOp(ssa.OpMul, leftValues, rightValue)
-			case "**=":
-				rightValue = y.ir.EmitBinOp(ssa.OpPow, leftValues, rightValue)
-			case "/=":
-				rightValue = y.ir.EmitBinOp(ssa.OpDiv, leftValues, rightValue)
-			case "%=":
-				rightValue = y.ir.EmitBinOp(ssa.OpMod, leftValues, rightValue)
-			case ".=":
-				rightValue = y.ir.EmitFieldMust(leftValues, rightValue)
-			case "&=":
-				rightValue = y.ir.EmitBinOp(ssa.OpAnd, leftValues, rightValue)
-			case "|=":
-				rightValue = y.ir.EmitBinOp(ssa.OpOr, leftValues, rightValue)
-			case "^=":
-				rightValue = y.ir.EmitBinOp(ssa.OpXor, leftValues, rightValue)
-			case "<<=":
-				rightValue = y.ir.EmitBinOp(ssa.OpShl, leftValues, rightValue)
-			case ">>=":
-				rightValue = y.ir.EmitBinOp(ssa.OpShr, leftValues, rightValue)
-			case "??=":
+			if operator == "??=" {
 				// 左值为空的时候，才会赋值
-				var returnVal = leftValues
-				var leftValueIsEmpty ssa.Value
-				y.ir.BuildIf().BuildCondition(func() ssa.Value {
-					leftValueIsEmpty = y.ir.EmitBinOp(ssa.OpEq, leftValues, y.ir.EmitConstInstNil())
-					return leftValueIsEmpty
-				}).BuildTrue(func() {
-					y.ir.EmitUpdate(leftValues, rightValue)
-					returnVal = rightValue
-				}).Finish()
-				return returnVal
-			default:
-				log.Errorf("unhandled assignment operator: %v", operator.GetText())
+				value := y.handlerJumpExpression(
+					func() ssa.Value {
+						return y.ir.EmitBinOp(ssa.OpNotEq, y.ir.ReadValueByVariable(variable), y.ir.EmitConstInstNil())
+					},
+					func() ssa.Value { return y.ir.ReadValueByVariable(variable) },
+					func() ssa.Value { return y.VisitExpression(ret.Expression()) },
+				)
+				y.ir.AssignVariable(variable, value)
+				return value
+			}
+
+			value := y.VisitExpression(ret.Expression())
+			if opcode, ok := assignOperators[operator]; ok {
+				value = y.ir.EmitBinOp(opcode, y.ir.ReadValueByVariable(variable), value)
+			} else if operator != "=" {
+				log.Errorf("unhandled assignment operator: %v", operator)
 			}
-			updateVal := y.ir.EmitUpdate(leftValues, rightValue)
-			_ = updateVal
-			return rightValue
+			y.ir.AssignVariable(variable, value)
+			return value
 		} else if ret.Ampersand() != nil {
 			// assignable Eq attributes? '&' (chain | newExpr)
-			leftValues := y.VisitAssignable(ret.Assignable())
-			if ret.Attributes() != nil {
-				y.VisitAttributes(ret.Attributes())
-			}
+			variable := y.VisitLeftAssignable(ret.Assignable())
 
 			// right val
+			var value ssa.Value
 			if i := ret.Chain(); i != nil {
-				y.VisitChain(i)
+				value = y.VisitChain(i)
 			} else if i := ret.NewExpr(); i != nil {
-				y.VisitNewExpr(i)
+				value = y.VisitNewExpr(i)
+			}
+			if variable != nil && value != nil {
+				y.ir.AssignVariable(variable, value)
 			}
-			_ = leftValues
+			return value
 		}
 
 	case *phpparser.LogicalExpressionContext:
 		if ret.LogicalAnd() != nil {
-			ifStmt := y.ir.BuildIf()
-			var v1, v2 ssa.Value
-			var result ssa.Value
-			ifStmt.BuildCondition(func() ssa.Value {
-				v1 = y.VisitExpression(ret.Expression(0))
-				return v1
-			}).BuildTrue(func() {
-				v2 = y.VisitExpression(ret.Expression(1))
-				result = y.ir.EmitBinOp(ssa.OpEq, v2, y.ir.EmitConstInst(true))
-			}).BuildFalse(func() {
-				result = y.ir.EmitConstInst(false)
-			})
-			ifStmt.Finish()
-			return result
+			return y.logicAnd(ret.Expression(0), ret.Expression(1))
 		} else if ret.LogicalOr() != nil {
-			var v1, v2 ssa.Value
-			var result ssa.Value
-			y.ir.BuildIf().BuildCondition(func() ssa.Value {
-				v1 = y.VisitExpression(ret.Expression(0))
-				return v1
-			}).BuildTrue(func() {
-				result = y.ir.EmitConstInst(true)
-			}).BuildFalse(func() {
-				v2 = y.VisitExpression(ret.Expression(1))
-				result = y.ir.EmitBinOp(ssa.OpEq, v2, y.ir.EmitConstInst(true))
-			}).Finish()
-			return result
+			return y.logicOr(ret.Expression(0), ret.Expression(1))
 		} else if ret.LogicalXor() != nil {
-			var v1, v2 ssa.Value
-			var result ssa.Value
-			v1 = y.VisitExpression(ret.Expression(0))
-			v2 = y.VisitExpression(ret.Expression(1))
-			y.ir.BuildIf().BuildCondition(func() ssa.Value {
-				return v1
-			}).BuildTrue(func() {
-				result = y.ir.EmitBinOp(ssa.OpEq, v2, y.ir.EmitConstInst(false))
-			}).BuildFalse(func() {
-				result = y.ir.EmitBinOp(ssa.OpEq, v2, y.ir.EmitConstInst(true))
-			}).Finish()
-			return result
+			v1 := y.ir.EmitUnOp(ssa.OpNot, y.VisitExpression(ret.Expression(0)))
+			v2 := y.ir.EmitUnOp(ssa.OpNot, y.VisitExpression(ret.Expression(1)))
+			return y.ir.EmitBinOp(ssa.OpNotEq, v1, v2)
 		} else {
 			log.Errorf("unhandled logical expression: %v", ret.GetText())
 			return nil
 		}
 	default:
-		ret.GetText()
 		log.Errorf("unhandled expression: %v(T: %T)", ret.GetText(), ret)
-		log.Errorf("-------------unhandled expression: %v(%T)", ret.GetText(), ret)
-		_ = ret
 	}
 
 	return nil
 }
 
-func (y *builder) VisitAssignable(raw phpparser.IAssignableContext) ssa.Value {
-	if y == nil || raw == nil {
-		return nil
-	}
-
-	i, _ := raw.(*phpparser.AssignableContext)
-	if i == nil {
-		return nil
-	}
-
-	if i.Chain() != nil {
-		return y.VisitChain(i.Chain())
-	} else if i.ArrayCreation() != nil {
-		return y.VisitArrayCreation(i.ArrayCreation())
-	} else {
-		log.Errorf("cannot build leftValue Assignable with: %v", i.Chain().GetText())
-		return nil
-	}
+var assignOperators = map[string]ssa.BinaryOpcode{
+	"+=":  ssa.OpAdd,
+	"-=":  ssa.OpSub,
+	"*=":  ssa.OpMul,
+	"**=": ssa.OpPow,
+	"/=":  ssa.OpDiv,
+	"%=":  ssa.OpMod,
+	".=":  ssa.OpAdd,
+	"&=":  ssa.OpAnd,
+	"|=":  ssa.OpOr,
+	"^=":  ssa.OpXor,
+	"<<=": ssa.OpShl,
+	">>=": ssa.OpShr,
 }
 
-func (y *builder) VisitChain(raw phpparser.IChainContext) ssa.Value {
-	if y == nil || raw == nil {
-		return nil
-	}
-
-	i, _ := raw.(*phpparser.ChainContext)
-	if i == nil {
-		return nil
-	}
-
-	origin := y.VisitChainOrigin(i.ChainOrigin())
-
-	for _, m := range i.AllMemberAccess() {
-		origin = y.ir.EmitField(origin, y.VisitMemberAccess(m))
-	}
-	return origin
+// handlerJumpExpression 处理带有跳转的表达式（三元、短路、??），
+// 每个分支的值写入同一个临时变量，最后读取这个变量得到 phi
+func (y *builder) handlerJumpExpression(cond, trueExpr, falseExpr func() ssa.Value) ssa.Value {
+	id := uuid.NewString()
+	y.ir.WriteVariable(id, y.ir.EmitConstInstAny())
+	ifb := y.ir.CreateIfBuilder()
+	ifb.AppendItem(cond, func() {
+		y.ir.WriteVariable(id, trueExpr())
+	})
+	ifb.SetElse(func() {
+		y.ir.WriteVariable(id, falseExpr())
+	})
+	ifb.Build()
+	return y.ir.ReadValue(id)
 }
 
-func (y *builder) VisitMemberAccess(raw phpparser.IMemberAccessContext) ssa.Value {
-	if y == nil || raw == nil {
-		return nil
-	}
-
-	i, _ := raw.(*phpparser.MemberAccessContext)
-	if i == nil {
-		return nil
-	}
-
-	y.VisitKeyedFieldName(i.KeyedFieldName())
-	if i.ActualArguments() != nil {
-		y.VisitActualArguments(i.ActualArguments())
-	}
-
-	return nil
+func (y *builder) logicAnd(left, right phpparser.IExpressionContext) ssa.Value {
+	v1 := y.VisitExpression(left)
+	return y.handlerJumpExpression(
+		func() ssa.Value { return v1 },
+		func() ssa.Value { return y.VisitExpression(right) },
+		func() ssa.Value { return v1 },
+	)
 }
 
-func (y *builder) VisitActualArguments(raw phpparser.IActualArgumentsContext) interface{} {
-	if y == nil || raw == nil {
-		return nil
-	}
-
-	i, _ := raw.(*phpparser.ActualArgumentsContext)
-	if i == nil {
-		return nil
-	}
-
-	// PHP8 annotation
-	for _, a := range i.AllArguments() {
-		y.VisitArguments(a)
-	}
-
-	for _, a := range i.AllSquareCurlyExpression() {
-		y.VisitSquareCurlyExpression(a)
-	}
-
-	return nil
+func (y *builder) logicOr(left, right phpparser.IExpressionContext) ssa.Value {
+	v1 := y.VisitExpression(left)
+	return y.handlerJumpExpression(
+		func() ssa.Value { return v1 },
+		func() ssa.Value { return v1 },
+		func() ssa.Value { return y.VisitExpression(right) },
+	)
 }
 
-func (y *builder) VisitKeyedFieldName(raw phpparser.IKeyedFieldNameContext) interface{} {
-	if y == nil || raw == nil {
-		return nil
+func (y *builder) VisitSpecialWordExpression(ret *phpparser.SpecialWordExpressionContext) ssa.Value {
+	callBuiltin := func(name string, args ...ssa.Value) ssa.Value {
+		return y.emitCall(y.ir.ReadValue(name), args)
 	}
 
-	i, _ := raw.(*phpparser.KeyedFieldNameContext)
-	if i == nil {
-		return nil
-	}
-
-	if i.KeyedSimpleFieldName() != nil {
-		y.VisitKeyedSimpleFieldName(i.KeyedSimpleFieldName())
-	} else if i.KeyedVariable() != nil {
-		y.VisitKeyedVariable(i.KeyedVariable())
+	if i := ret.Yield(); i != nil {
+		return y.ir.EmitConstInstNil()
+	} else if i := ret.List(); i != nil {
+		// list($a, $b) = $arr;
+		value := y.VisitExpression(ret.Expression())
+		y.assignList(ret.AssignmentList(), value)
+		return value
+	} else if i := ret.IsSet(); i != nil {
+		var args []ssa.Value
+		if list, ok := ret.ChainList().(*phpparser.ChainListContext); ok {
+			for _, c := range list.AllChain() {
+				args = append(args, y.VisitChain(c))
+			}
+		}
+		return callBuiltin("isset", args...)
+	} else if i := ret.Empty(); i != nil {
+		return callBuiltin("empty", y.VisitChain(ret.Chain()))
+	} else if i := ret.Eval(); i != nil {
+		return callBuiltin("eval", y.VisitExpression(ret.Expression()))
+	} else if i := ret.Exit(); i != nil {
+		var args []ssa.Value
+		if ret.Parentheses() != nil {
+			args = append(args, y.VisitParentheses(ret.Parentheses()))
+		}
+		return callBuiltin(strings.ToLower(i.GetText()), args...)
+	} else if i := ret.Include(); i != nil {
+		return y.buildInclude("include", ret.Expression())
+	} else if i := ret.IncludeOnce(); i != nil {
+		return y.buildInclude("include_once", ret.Expression())
+	} else if i := ret.Require(); i != nil {
+		return y.buildInclude("require", ret.Expression())
+	} else if i := ret.RequireOnce(); i != nil {
+		return y.buildInclude("require_once", ret.Expression())
+	} else if i := ret.Throw(); i != nil {
+		value := y.VisitExpression(ret.Expression())
+		y.ir.EmitPanic(value)
+		return value
+	} else {
+		log.Errorf("unhandled special word: %v", ret.GetText())
 	}
-
-	return nil
+	return y.ir.EmitConstInstNil()
 }
 
-func (y *builder) VisitKeyedVariable(raw phpparser.IKeyedVariableContext) ssa.Value {
+func (y *builder) VisitMatchExpr(raw phpparser.IMatchExprContext) ssa.Value {
 	if y == nil || raw == nil {
 		return nil
 	}
 
-	i, _ := raw.(*phpparser.KeyedVariableContext)
+	i, _ := raw.(*phpparser.MatchExprContext)
 	if i == nil {
 		return nil
 	}
 
-	dollarCount := len(i.AllDollar())
-	var varMain ssa.Value
-	if i.VarName() != nil {
-		// ($*)$a
-		//// {} as index [] as sliceCall
-		varMain = y.ir.ReadVariable(i.VarName().GetText(), true)
-		for i := 0; i < dollarCount; i++ {
-			//TODO: val = y.ir.ReadDynamicVariable(val)
+	/*
+		match ($a) {
+			1, 2 => "a",
+			3 => "b",
+			default => "c",
 		}
-	} else {
-		// {} as name [] as sliceCall
-		varMain = nil // TODO: y.ir.ReadDynamicVariable()
-		for i := 0; i < dollarCount; i++ {
-			// val = y.ir.ReadDynamicVariable(val)
+	*/
+	subject := y.VisitExpression(i.Expression())
+	id := uuid.NewString()
+	y.ir.WriteVariable(id, y.ir.EmitConstInstAny())
+	ifb := y.ir.CreateIfBuilder()
+	for _, item := range i.AllMatchItem() {
+		item, ok := item.(*phpparser.MatchItemContext)
+		if !ok {
+			continue
 		}
-	}
-
-	for _, a := range i.AllSquareCurlyExpression() {
-		v := y.VisitSquareCurlyExpression(a)
-		if v == nil {
-			count := y.ir.ReadVariable("count", true)
-			calling := y.ir.NewCall(count, nil)
-			y.ir.EmitCall(calling)
-			v = calling
+		exprs := item.AllExpression()
+		if len(exprs) < 2 {
+			continue
+		}
+		conds, result := exprs[:len(exprs)-1], exprs[len(exprs)-1]
+		if len(conds) == 1 && strings.ToLower(conds[0].GetText()) == "default" {
+			ifb.SetElse(func() {
+				y.ir.WriteVariable(id, y.VisitExpression(result))
+			})
+			continue
 		}
-		varMain = y.ir.EmitField(varMain, v)
+		ifb.AppendItem(func() ssa.Value {
+			var cond ssa.Value
+			for _, expr := range conds {
+				eq := y.ir.EmitBinOp(ssa.OpEq, subject, y.VisitExpression(expr))
+				if cond == nil {
+					cond = eq
+				} else {
+					cond = y.ir.EmitBinOp(ssa.OpLogicOr, cond, eq)
+				}
+			}
+			return cond
+		}, func() {
+			y.ir.WriteVariable(id, y.VisitExpression(result))
+		})
 	}
-
-	return varMain
+	ifb.Build()
+	return y.ir.ReadValue(id)
 }
 
-func (y *builder) VisitKeyedSimpleFieldName(raw phpparser.IKeyedSimpleFieldNameContext) interface{} {
-	if y == nil || raw == nil {
-		return nil
-	}
-
-	i, _ := raw.(*phpparser.KeyedSimpleFieldNameContext)
-	if i == nil {
+// emitCall 调用函数，当前块已经结束的时候返回 nil
+func (y *builder) emitCall(callee ssa.Value, args []ssa.Value) ssa.Value {
+	if callee == nil {
 		return nil
 	}
-
-	if i.Identifier() != nil {
-		v := y.VisitIdentifier(i.Identifier())
-		_ = v
-	} else if i.Expression() != nil {
-		v := y.VisitExpression(i.Expression())
-		_ = v
-	}
-
-	for _, sce := range i.AllSquareCurlyExpression() {
-		y.VisitSquareCurlyExpression(sce)
+	if call := y.ir.EmitCall(y.ir.NewCall(callee, args)); call != nil {
+		return call
 	}
-
 	return nil
 }
 
-func (y *builder) VisitSquareCurlyExpression(raw phpparser.ISquareCurlyExpressionContext) ssa.Value {
+func (y *builder) VisitExpressionList(raw phpparser.IExpressionListContext) []ssa.Value {
 	if y == nil || raw == nil {
 		return nil
 	}
 
-	i, _ := raw.(*phpparser.SquareCurlyExpressionContext)
+	i, _ := raw.(*phpparser.ExpressionListContext)
 	if i == nil {
 		return nil
 	}
 
-	if i.OpenSquareBracket() != nil {
-		if i.Expression() != nil {
-			return y.VisitExpression(i.Expression())
-		} else {
-			/*
-				$a = array("apple", "banana");
-				$a[] = "cherry";
-
-				// 现在，$a 包含 "apple", "banana", "cherry"
-			*/
-			log.Warnf("PHP $a[...] call empty")
-			return nil
-		}
-	} else {
-		return y.VisitExpression(i.Expression())
+	var ret []ssa.Value
+	for _, expr := range i.AllExpression() {
+		ret = append(ret, y.VisitExpression(expr))
 	}
+	return ret
 }
 
-func (y *builder) VisitFunctionCall(raw phpparser.IFunctionCallContext) ssa.Value {
+func (y *builder) VisitArrayCreation(raw phpparser.IArrayCreationContext) ssa.Value {
 	if y == nil || raw == nil {
 		return nil
 	}
 
-	i, _ := raw.(*phpparser.FunctionCallContext)
+	i, _ := raw.(*phpparser.ArrayCreationContext)
 	if i == nil {
 		return nil
 	}
 
-	v := y.VisitFunctionCallName(i.FunctionCallName())
-	c := y.ir.NewCall(v, nil)
-	return y.ir.EmitCall(c)
+	val := y.buildArray(y.VisitArrayItemList(i.ArrayItemList()))
+	if i := i.Expression(); i != nil {
+		return y.ir.ReadMemberCallVariable(val, y.VisitExpression(i))
+	}
+	return val
 }
 
-func (y *builder) VisitFunctionCallName(raw phpparser.IFunctionCallNameContext) ssa.Value {
-	if y == nil || raw == nil {
-		return nil
+func (y *builder) buildArray(items [][2]ssa.Value) ssa.Value {
+	keys := make([]ssa.Value, 0, len(items))
+	values := make([]ssa.Value, 0, len(items))
+	for _, kv := range items {
+		keys = append(keys, kv[0])
+		values = append(values, kv[1])
 	}
+	return y.ir.CreateInterfaceWithMap(keys, values)
+}
 
-	i, _ := raw.(*phpparser.FunctionCallNameContext)
+// assignArrayCreation [$a, 'k' => $b] = $arr;
+func (y *builder) assignArrayCreation(raw phpparser.IArrayCreationContext, value ssa.Value) {
+	i, _ := raw.(*phpparser.ArrayCreationContext)
 	if i == nil {
-		return nil
+		return
 	}
-
-	if ret := i.QualifiedNamespaceName(); ret != nil {
-		return y.VisitQualifiedNamespaceName(ret)
-	} else if ret := i.ChainBase(); ret != nil {
-		return y.VisitChainBase(ret)
-	} else if ret := i.ClassConstant(); ret != nil {
-		return y.VisitClassConstant(ret)
-	} else if ret := i.Parentheses(); ret != nil {
-		return y.VisitParentheses(ret)
-	} else if ret := i.Label(); ret != nil {
-		return y.ir.ReadVariable(i.Label().GetText(), true)
-	}
-	log.Errorf("BUG: unknown function call name: %v", i.GetText())
-	return nil
-}
-
-func (y *builder) VisitChainOrigin(raw phpparser.IChainOriginContext) ssa.Value {
-	if y == nil || raw == nil {
-		return nil
+	list, _ := i.ArrayItemList().(*phpparser.ArrayItemListContext)
+	if list == nil {
+		return
 	}
 
-	i, _ := raw.(*phpparser.ChainOriginContext)
-	if i == nil {
-		return nil
+	index := 0
+	for _, a := range list.AllArrayItem() {
+		item, _ := a.(*phpparser.ArrayItemContext)
+		if item == nil {
+			continue
+		}
+		var key ssa.Value
+		var variable *ssa.Variable
+		switch {
+		case item.Chain() != nil:
+			if item.Expression(0) != nil {
+				key = y.VisitExpression(item.Expression(0))
+			}
+			variable = y.VisitLeftChain(item.Chain())
+		case item.Expression(1) != nil:
+			key = y.VisitExpression(item.Expression(0))
+			variable = y.visitLeftExpression(item.Expression(1), y.ir.ReadMemberCallVariable(value, key))
+		default:
+			variable = y.visitLeftExpression(item.Expression(0), y.ir.ReadMemberCallVariable(value, y.ir.EmitConstInst(index)))
+		}
+		if key == nil {
+			key = y.ir.EmitConstInst(index)
+			index++
+		}
+		if variable != nil {
+			y.ir.AssignVariable(variable, y.ir.ReadMemberCallVariable(value, key))
+		}
 	}
+}
 
-	if ret := i.NewExpr(); ret != nil {
-		return y.VisitNewExpr(ret)
-	} else if ret := i.FunctionCall(); ret != nil {
-		return y.VisitFunctionCall(ret)
-	} else if ret := i.ChainBase(); ret != nil {
-		return y.VisitChainBase(ret)
-	} else {
-		log.Errorf("BUG: unknown chain origin: %v", i.GetText())
+// visitLeftExpression 解构赋值中的元素，嵌套的解构直接赋值并返回 nil
+func (y *builder) visitLeftExpression(raw phpparser.IExpressionContext, value ssa.Value) *ssa.Variable {
+	switch ret := raw.(type) {
+	case *phpparser.ChainExpressionContext:
+		return y.VisitLeftChain(ret.Chain())
+	case *phpparser.ArrayCreationExpressionContext:
+		y.assignArrayCreation(ret.ArrayCreation(), value)
+	default:
+		log.Warnf("cannot assign to: %v", raw.GetText())
 	}
-
 	return nil
 }
 
-func (y *builder) VisitChainBase(raw phpparser.IChainBaseContext) ssa.Value {
-	if y == nil || raw == nil {
-		return nil
-	}
-
-	i, _ := raw.(*phpparser.ChainBaseContext)
+// assignArrayDestructuring [$a, , $b] = $arr; ['a' => $a, 'b' => $b] = $arr;
+func (y *builder) assignArrayDestructuring(raw phpparser.IArrayDestructuringContext, value ssa.Value) {
+	i, _ := raw.(*phpparser.ArrayDestructuringContext)
 	if i == nil {
-		return nil
+		return
 	}
 
-	/*
-		$hello = "world";
-		$a = "hello";
-		echo $$a; // world
-	*/
-	if ret := i.QualifiedStaticTypeRef(); ret != nil {
-		panic("NOT IMPL")
-	} else {
-		var ret ssa.Value
-		for _, i := range i.AllKeyedVariable() {
-			if ret == nil {
-				ret = y.VisitKeyedVariable(i)
-				continue
+	index := 0
+	for _, child := range i.GetChildren() {
+		switch item := child.(type) {
+		case antlr.TerminalNode:
+			// 跳过的元素也占用下标
+			if item.GetText() == "," && len(i.AllIndexedDestructItem()) > 0 {
+				index++
+			}
+		case *phpparser.IndexedDestructItemContext:
+			if variable := y.VisitLeftChain(item.Chain()); variable != nil {
+				y.ir.AssignVariable(variable, y.ir.ReadMemberCallVariable(value, y.ir.EmitConstInst(index)))
+			}
+		case *phpparser.KeyedDestructItemContext:
+			var key ssa.Value
+			if item.Expression() != nil {
+				key = y.VisitExpression(item.Expression())
+			} else {
+				key = y.ir.EmitConstInst(index)
+				index++
+			}
+			if variable := y.VisitLeftChain(item.Chain()); variable != nil {
+				y.ir.AssignVariable(variable, y.ir.ReadMemberCallVariable(value, key))
 			}
-			ret = y.ir.EmitField(ret, y.VisitKeyedVariable(i))
 		}
-		return ret
 	}
-	return nil
 }
 
-func (y *builder) VisitArrayCreation(raw phpparser.IArrayCreationContext) ssa.Value {
-	if y == nil || raw == nil {
-		return nil
-	}
-
-	i, _ := raw.(*phpparser.ArrayCreationContext)
+// assignList list($a, , list($b)) = $arr;
+func (y *builder) assignList(raw phpparser.IAssignmentListContext, value ssa.Value) {
+	i, _ := raw.(*phpparser.AssignmentListContext)
 	if i == nil {
-		return nil
+		return
 	}
 
-	val := y.ir.EmitInterfaceMake(func(feed func(key ssa.Value, val ssa.Value)) {
-		for _, kv := range y.VisitArrayItemList(i.ArrayItemList()) {
-			feed(kv[0], kv[1])
+	index := 0
+	for _, child := range i.GetChildren() {
+		switch item := child.(type) {
+		case antlr.TerminalNode:
+			if item.GetText() == "," {
+				index++
+			}
+		case *phpparser.AssignmentListElementContext:
+			member := y.ir.ReadMemberCallVariable(value, y.ir.EmitConstInst(index))
+			switch {
+			case item.Chain() != nil:
+				if variable := y.VisitLeftChain(item.Chain()); variable != nil {
+					y.ir.AssignVariable(variable, member)
+				}
+			case item.AssignmentList() != nil:
+				y.assignList(item.AssignmentList(), member)
+			case item.ArrayItem() != nil:
+				// list('a' => $a) = $arr;
+				arrayItem, _ := item.ArrayItem().(*phpparser.ArrayItemContext)
+				if arrayItem == nil {
+					continue
+				}
+				if arrayItem.Chain() != nil {
+					if arrayItem.Expression(0) != nil {
+						member = y.ir.ReadMemberCallVariable(value, y.VisitExpression(arrayItem.Expression(0)))
+					}
+					if variable := y.VisitLeftChain(arrayItem.Chain()); variable != nil {
+						y.ir.AssignVariable(variable, member)
+					}
+					continue
+				}
+				target := arrayItem.Expression(0)
+				if arrayItem.Expression(1) != nil {
+					member = y.ir.ReadMemberCallVariable(value, y.VisitExpression(arrayItem.Expression(0)))
+					target = arrayItem.Expression(1)
+				}
+				if variable := y.visitLeftExpression(target, member); variable != nil {
+					y.ir.AssignVariable(variable, member)
+				}
+			}
 		}
-	})
-
-	if i := i.Expression(); i != nil {
-		return y.ir.EmitField(val, y.VisitExpression(i))
 	}
-	return val
 }
 
 func (y *builder) VisitArrayItemList(raw phpparser.IArrayItemListContext) [][2]ssa.Value {
@@ -895,12 +825,11 @@ func (y *builder) VisitStringConstant(raw phpparser.IStringConstantContext) ssa.
 		return nil
 	}
 
-	ret := y.ir.ReadVariable(i.GetText(), false)
-	if ret == nil {
-		return y.ir.EmitConstInst(i.GetText())
+	// 未定义的常量在 php 中会被当作字符串
+	if ret := y.ir.PeekValue(i.GetText()); ret != nil {
+		return ret
 	}
-
-	return ret
+	return y.ir.EmitConstInst(i.GetText())
 }
 
 func (y *builder) VisitConstantInitializer(raw phpparser.IConstantInitializerContext) ssa.Value {
@@ -914,11 +843,7 @@ func (y *builder) VisitConstantInitializer(raw phpparser.IConstantInitializerCon
 	}
 
 	if ret := i.ArrayItemList(); ret != nil {
-		return y.ir.EmitInterfaceMake(func(feed func(key ssa.Value, val ssa.Value)) {
-			for _, kv := range y.VisitArrayItemList(ret) {
-				feed(kv[0], kv[1])
-			}
-		})
+		return y.buildArray(y.VisitArrayItemList(ret))
 	} else if ret := i.ConstantInitializer(); ret != nil {
 		val := y.VisitConstantInitializer(ret)
 		if i.Minus() != nil {
@@ -932,7 +857,7 @@ func (y *builder) VisitConstantInitializer(raw phpparser.IConstantInitializerCon
 				initVal = y.VisitConstantString(c)
 				continue
 			}
-			initVal = y.ir.EmitField(initVal, y.VisitConstantString(c))
+			initVal = y.ir.EmitBinOp(ssa.OpAdd, initVal, y.VisitConstantString(c))
 		}
 		if initVal == nil {
 			log.Errorf("unhandled constant initializer: %v", i.GetText())
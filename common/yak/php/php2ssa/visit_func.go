@@ -1,6 +1,9 @@
 package php2ssa
 
-import phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
+import (
+	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
 
 func (y *builder) VisitFunctionDeclaration(raw phpparser.IFunctionDeclarationContext) interface{} {
 	if y == nil || raw == nil {
@@ -11,6 +14,8 @@ func (y *builder) VisitFunctionDeclaration(raw phpparser.IFunctionDeclarationCon
 	if i == nil {
 		return nil
 	}
+	recoverRange := y.SetRange(i.BaseParserRuleContext)
+	defer recoverRange()
 
 	var attr string
 	if ret := i.Attributes(); ret != nil {
@@ -22,11 +27,10 @@ func (y *builder) VisitFunctionDeclaration(raw phpparser.IFunctionDeclarationCon
 	_ = isRef
 
 	funcName := i.Identifier().GetText()
-	funcDec, symbolTable := y.ir.NewFunc(funcName)
-	current := y.ir.CurrentBlock
+	newFunc := y.ir.NewFunc(funcName)
 
 	{
-		y.ir = y.ir.PushFunction(funcDec, symbolTable, current)
+		y.ir = y.ir.PushFunction(newFunc)
 
 		y.VisitFormalParameterList(i.FormalParameterList())
 		y.VisitBlockStatement(i.BlockStatement())
@@ -35,6 +39,47 @@ func (y *builder) VisitFunctionDeclaration(raw phpparser.IFunctionDeclarationCon
 		y.ir = y.ir.PopFunction()
 	}
 
-	y.ir.WriteVariable(funcName, funcDec)
+	y.ir.WriteVariable(funcName, newFunc)
 	return nil
 }
+
+func (y *builder) VisitLambdaFunctionExpr(raw phpparser.ILambdaFunctionExprContext) ssa.Value {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.LambdaFunctionExprContext)
+	if i == nil {
+		return nil
+	}
+	recoverRange := y.SetRange(i.BaseParserRuleContext)
+	defer recoverRange()
+
+	/*
+		function ($a) use ($b) { ... }
+		fn ($a) => $a + $b
+	*/
+	newFunc := y.ir.NewFunc("")
+	{
+		y.ir = y.ir.PushFunction(newFunc)
+
+		y.VisitFormalParameterList(i.FormalParameterList())
+		if uses, ok := i.LambdaFunctionUseVars().(*phpparser.LambdaFunctionUseVarsContext); ok {
+			// captured variable, read as free-value
+			for _, use := range uses.AllLambdaFunctionUseVar() {
+				if use, ok := use.(*phpparser.LambdaFunctionUseVarContext); ok {
+					y.ir.ReadValue(use.VarName().GetText())
+				}
+			}
+		}
+		if i.BlockStatement() != nil {
+			y.VisitBlockStatement(i.BlockStatement())
+		} else if expr := i.Expression(); expr != nil {
+			y.ir.EmitReturn([]ssa.Value{y.VisitExpression(expr)})
+		}
+
+		y.ir.Finish()
+		y.ir = y.ir.PopFunction()
+	}
+	return newFunc
+}
@@ -12,5 +12,20 @@ func (y *builder) VisitGlobalStatement(raw phpparser.IGlobalStatementContext) in
 		return nil
 	}
 
+	// global $a; 读取外层的变量，在函数中作为 free-value 使用
+	for _, g := range i.AllGlobalVar() {
+		g, ok := g.(*phpparser.GlobalVarContext)
+		if !ok {
+			continue
+		}
+		if g.VarName() != nil {
+			name := g.VarName().GetText()
+			y.ir.WriteVariable(name, y.ir.ReadValue(name))
+		} else if g.Chain() != nil {
+			y.VisitChain(g.Chain())
+		} else if g.Expression() != nil {
+			y.VisitExpression(g.Expression())
+		}
+	}
 	return nil
 }
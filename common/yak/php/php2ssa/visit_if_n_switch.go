@@ -1,6 +1,8 @@
 package php2ssa
 
 import (
+	"strings"
+
 	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
 	"github.com/yaklang/yaklang/common/yak/ssa"
 )
@@ -23,50 +25,48 @@ func (y *builder) VisitIfStatement(raw phpparser.IIfStatementContext) interface{
 			if (true) echo "abc"; else if true 1+1;
 			if (true) echo "abc"; else if true 1+1; else "abc"."ccc";
 		*/
-		i := y.ir.BuildIf()
-		i.BuildCondition(func() ssa.Value {
+		i := y.ir.CreateIfBuilder()
+		i.AppendItem(func() ssa.Value {
 			return y.VisitParentheses(stmt.Parentheses())
-		})
-		i.BuildTrue(func() {
+		}, func() {
 			y.VisitStatement(stmt.Statement())
 		})
 		for _, elseIf := range stmt.AllElseIfStatement() {
 			elseIfStmt := elseIf.(*phpparser.ElseIfStatementContext)
-			i.BuildElif(func() ssa.Value {
+			i.AppendItem(func() ssa.Value {
 				return y.VisitParentheses(elseIfStmt.Parentheses())
 			}, func() {
 				y.VisitStatement(elseIfStmt.Statement())
 			})
 		}
-		if stmt.ElseStatement() != nil {
-			i.BuildFalse(func() {
-				y.VisitStatement(stmt.ElseStatement().(*phpparser.ElseStatementContext).Statement())
+		if elseStmt, ok := stmt.ElseStatement().(*phpparser.ElseStatementContext); ok {
+			i.SetElse(func() {
+				y.VisitStatement(elseStmt.Statement())
 			})
 		}
-		i.Finish()
+		i.Build()
 	} else {
 		// tag if
-		i := y.ir.BuildIf()
-		i.BuildCondition(func() ssa.Value {
+		i := y.ir.CreateIfBuilder()
+		i.AppendItem(func() ssa.Value {
 			return y.VisitParentheses(stmt.Parentheses())
-		})
-		i.BuildTrue(func() {
+		}, func() {
 			y.VisitInnerStatementList(stmt.InnerStatementList())
 		})
 		for _, elseIf := range stmt.AllElseIfColonStatement() {
 			elseIfStmt := elseIf.(*phpparser.ElseIfColonStatementContext)
-			i.BuildElif(func() ssa.Value {
+			i.AppendItem(func() ssa.Value {
 				return y.VisitParentheses(elseIfStmt.Parentheses())
 			}, func() {
 				y.VisitInnerStatementList(elseIfStmt.InnerStatementList())
 			})
 		}
-		if stmt.ElseStatement() != nil {
-			i.BuildFalse(func() {
-				y.VisitInnerStatementList(stmt.ElseColonStatement().(*phpparser.ElseColonStatementContext).InnerStatementList())
+		if elseStmt, ok := stmt.ElseColonStatement().(*phpparser.ElseColonStatementContext); ok {
+			i.SetElse(func() {
+				y.VisitInnerStatementList(elseStmt.InnerStatementList())
 			})
 		}
-		i.Finish()
+		i.Build()
 	}
 
 	return nil
@@ -82,16 +82,96 @@ func (y *builder) VisitSwitchStatement(raw phpparser.ISwitchStatementContext) in
 		return nil
 	}
 
+	// php switch 没有 break 时会 fallthrough 到下一个 case，但 SSA 的 switch 在 fallthrough 时不会合并作用域，
+	// 后面读到的变量会变成 undefined，这里按照每个 case 结束后都跳出处理，没有 break 的 case 中的赋值同样流到 switch 之后
 	ir := y.ir.BuildSwitch()
-	ir.DefaultBreak = false
+	ir.DefaultBreak = true
 
 	var cond ssa.Value
 	ir.BuildCondition(func() ssa.Value {
 		cond = y.VisitParentheses(i.Parentheses())
 		return cond
 	})
-	blocks := i.AllSwitchBlock()
-	var results = make([]ssa.Value, len(blocks))
-	_ = results
+
+	var (
+		cases        []*phpparser.SwitchBlockContext
+		caseBodies   [][]phpparser.IInnerStatementContext
+		defaultBody  []phpparser.IInnerStatementContext
+		defaultFound bool
+	)
+	for _, b := range i.AllSwitchBlock() {
+		block, ok := b.(*phpparser.SwitchBlockContext)
+		if !ok {
+			continue
+		}
+		body, defaults, hasDefault := splitSwitchDefault(block.InnerStatementList())
+		if len(block.AllDefault()) > 0 {
+			defaultFound = true
+			defaultBody = append(defaultBody, body...)
+		} else {
+			cases = append(cases, block)
+			caseBodies = append(caseBodies, body)
+		}
+		if hasDefault {
+			defaultFound = true
+			defaultBody = append(defaultBody, defaults...)
+		}
+	}
+
+	ir.BuildCaseSize(len(cases))
+	ir.SetCase(func(index int) []ssa.Value {
+		var values []ssa.Value
+		for _, expr := range cases[index].AllExpression() {
+			values = append(values, y.VisitExpression(expr))
+		}
+		return values
+	})
+	ir.BuildBody(func(index int) {
+		for _, stmt := range caseBodies[index] {
+			y.VisitInnerStatement(stmt)
+		}
+	})
+	if defaultFound {
+		ir.BuildDefault(func() {
+			for _, stmt := range defaultBody {
+				y.VisitInnerStatement(stmt)
+			}
+		})
+	}
+	ir.Finish()
 	return nil
 }
+
+// splitSwitchDefault 把 case 中的语句按照 default 分开：default 也是合法的标识符，
+// 前一个 case 后面的 default: 会被解析为 label 语句，和前一个 case 在同一个 switch block 中
+func splitSwitchDefault(raw phpparser.IInnerStatementListContext) (body, defaults []phpparser.IInnerStatementContext, hasDefault bool) {
+	list, _ := raw.(*phpparser.InnerStatementListContext)
+	if list == nil {
+		return nil, nil, false
+	}
+	for _, stmt := range list.AllInnerStatement() {
+		if !hasDefault && isSwitchDefaultLabel(stmt) {
+			hasDefault = true
+			continue
+		}
+		if hasDefault {
+			defaults = append(defaults, stmt)
+		} else {
+			body = append(body, stmt)
+		}
+	}
+	return body, defaults, hasDefault
+}
+
+func isSwitchDefaultLabel(raw phpparser.IInnerStatementContext) bool {
+	i, _ := raw.(*phpparser.InnerStatementContext)
+	if i == nil {
+		return false
+	}
+	stmt, _ := i.Statement().(*phpparser.StatementContext)
+	if stmt == nil || stmt.LabelStatement() == nil {
+		return false
+	}
+	label, _ := stmt.LabelStatement().(*phpparser.LabelStatementContext)
+	return label != nil && strings.EqualFold(label.Identifier().GetText(), "default")
+}
@@ -0,0 +1,131 @@
+package php2ssa
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr/v4"
+	"github.com/yaklang/yaklang/common/log"
+	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
+
+// buildInclude 处理 include / require，路径是常量的时候把被包含的文件直接编译到当前位置，
+// 无论是否能找到文件，都会保留一次对内置函数的调用，方便分析文件包含漏洞
+func (y *builder) buildInclude(kind string, raw phpparser.IExpressionContext) ssa.Value {
+	path := y.VisitExpression(raw)
+	ret := y.emitCall(y.ir.ReadValue(kind), []ssa.Value{path})
+
+	c, ok := ssa.ToConst(path)
+	if !ok || !c.IsString() || c.VarString() == "" {
+		return ret
+	}
+
+	filename := y.resolveIncludePath(c.VarString())
+
+	if _, ok := y.including[filename]; ok {
+		// include self
+		return ret
+	}
+	if _, ok := y.included[filename]; ok && (kind == "include_once" || kind == "require_once") {
+		return ret
+	}
+
+	content, err := y.prog.ReadFile(filename)
+	if err != nil {
+		log.Debugf("php include file %v failed: %v", filename, err)
+		return ret
+	}
+	ast, err := frontEnd(string(content), false)
+	if err != nil {
+		log.Warnf("php include file %v parse failed: %v", filename, err)
+		return ret
+	}
+
+	y.included[filename] = struct{}{}
+	y.including[filename] = struct{}{}
+	dir, currentFile := y.dir, y.prog.GetCurrentFile()
+	y.dir = y.fileDir(filename)
+	y.prog.SetCurrentFile(filename)
+	defer func() {
+		y.dir = dir
+		y.prog.SetCurrentFile(currentFile)
+		delete(y.including, filename)
+	}()
+
+	y.VisitHtmlDocument(ast)
+	return ret
+}
+
+// resolveIncludePath 项目中的文件使用 / 分隔的相对路径，本地文件使用绝对路径
+func (y *builder) resolveIncludePath(name string) string {
+	if y.prog.GetFileSystem() != nil {
+		if !path.IsAbs(name) {
+			name = path.Join(y.dir, name)
+		}
+		return strings.TrimPrefix(path.Clean(name), "/")
+	}
+
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(y.dir, name)
+	}
+	if abs, err := filepath.Abs(name); err == nil {
+		name = abs
+	}
+	return name
+}
+
+func (y *builder) fileDir(name string) string {
+	if name == "" {
+		return ""
+	}
+	if y.prog.GetFileSystem() != nil {
+		return path.Dir(name)
+	}
+	return filepath.Dir(name)
+}
+
+// Includes 返回 src 中路径为常量的 include / require 所包含的文件，file 为 src 所在的文件，
+// 项目编译时被包含的文件会在 include 的位置编译，可以据此跳过这些文件的单独编译
+func (p *Parser) Includes(src string, file string, fsys fs.FS) (files []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warnf("php scan include of %v failed: %v", file, r)
+		}
+	}()
+	ast, err := frontEnd(src, false)
+	if err != nil {
+		return nil
+	}
+
+	// 使用临时的 Program 计算路径，与编译时的常量折叠保持一致
+	prog := ssa.NewProgram()
+	prog.SetFileSystem(fsys)
+	prog.SetCurrentFile(file)
+	y := &builder{
+		prog:      prog,
+		ir:        prog.GetAndCreateMainFunctionBuilder(),
+		included:  make(map[string]struct{}),
+		including: make(map[string]struct{}),
+	}
+	y.dir = y.fileDir(file)
+
+	var walk func(tree antlr.Tree)
+	walk = func(tree antlr.Tree) {
+		if expr, ok := tree.(*phpparser.SpecialWordExpressionContext); ok {
+			if expr.Include() != nil || expr.IncludeOnce() != nil || expr.Require() != nil || expr.RequireOnce() != nil {
+				if c, ok := ssa.ToConst(y.VisitExpression(expr.Expression())); ok && c.IsString() && c.VarString() != "" {
+					files = append(files, y.resolveIncludePath(c.VarString()))
+				}
+				return
+			}
+		}
+		for _, child := range tree.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(ast)
+	return files
+}
@@ -30,9 +30,9 @@ func (y *builder) VisitConstant(raw phpparser.IConstantContext) ssa.Value {
 		return y.ir.EmitUndefined(i.MagicConstant().GetText())
 	} else if i.ClassConstant() != nil {
 		// class constant
-		log.Warnf("class constant not support yet: %s", i.ClassConstant().GetText())
+		return y.VisitClassConstant(i.ClassConstant())
 	} else if i.QualifiedNamespaceName() != nil {
-		y.VisitQualifiedNamespaceName(i.QualifiedNamespaceName())
+		return y.VisitQualifiedNamespaceName(i.QualifiedNamespaceName())
 	} else {
 		log.Warnf("unknown constant: %s", i.GetText())
 	}
@@ -149,12 +149,67 @@ func (y *builder) VisitString_(raw phpparser.IStringContext) ssa.Value {
 		return nil
 	}
 
-	str, err := yakunquote.Unquote(raw.GetText())
-	if err != nil {
-		str = raw.GetText()
+	switch {
+	case i.SingleQuoteString() != nil:
+		// 单引号字符串只转义 \' 和 \\
+		text := i.SingleQuoteString().GetText()
+		text = text[1 : len(text)-1]
+		text = strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(text)
+		return y.ir.EmitConstInst(text)
+	case i.StartHereDoc() != nil || i.StartNowDoc() != nil:
+		var builder strings.Builder
+		for _, text := range i.AllHereDocText() {
+			builder.WriteString(text.GetText())
+		}
+		return y.ir.EmitConstInst(strings.TrimSuffix(strings.TrimPrefix(builder.String(), "\n"), "\n"))
+	default:
+		// "hello $name!" => "hello " + $name + "!"
+		var ret ssa.Value
+		var builder strings.Builder
+		add := func(v ssa.Value) {
+			if ret == nil {
+				ret = v
+				return
+			}
+			ret = y.ir.EmitBinOp(ssa.OpAdd, ret, v)
+		}
+		flush := func() {
+			if builder.Len() > 0 {
+				add(y.ir.EmitConstInst(builder.String()))
+				builder.Reset()
+			}
+		}
+		for _, part := range i.AllInterpolatedStringPart() {
+			part, ok := part.(*phpparser.InterpolatedStringPartContext)
+			if !ok {
+				continue
+			}
+			switch {
+			case part.Chain() != nil:
+				flush()
+				add(y.VisitChain(part.Chain()))
+			case part.UnicodeEscape() != nil:
+				// \u{1F418}
+				text := part.GetText()
+				if r, err := strconv.ParseInt(text[3:len(text)-1], 16, 32); err == nil {
+					builder.WriteRune(rune(r))
+				} else {
+					builder.WriteString(text)
+				}
+			default:
+				text := part.GetText()
+				if str, err := yakunquote.Unquote(`"` + text + `"`); err == nil {
+					text = str
+				}
+				builder.WriteString(text)
+			}
+		}
+		flush()
+		if ret == nil {
+			return y.ir.EmitConstInst("")
+		}
+		return ret
 	}
-
-	return y.ir.EmitConstInst(str)
 }
 
 func (y *builder) VisitIdentifier(raw phpparser.IIdentifierContext) string {
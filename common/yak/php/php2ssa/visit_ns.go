@@ -1,6 +1,8 @@
 package php2ssa
 
 import (
+	"strings"
+
 	"github.com/yaklang/yaklang/common/log"
 	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
 	"github.com/yaklang/yaklang/common/yak/ssa"
@@ -29,18 +31,28 @@ func (y *builder) VisitQualifiedNamespaceName(raw phpparser.IQualifiedNamespaceN
 		return nil
 	}
 
-	if i.Namespace() != nil {
-		// declare namespace mode
-		list := i.NamespaceNameList().(*phpparser.NamespaceNameListContext)
-		if ret := list.NamespaceNameTail(); ret != nil {
-
-		}
-		return y.ir.EmitConstInst(nil)
-	} else {
+	return y.ir.ReadValue(y.qualifiedName(i))
+}
 
+func (y *builder) qualifiedName(raw phpparser.IQualifiedNamespaceNameContext) string {
+	i, _ := raw.(*phpparser.QualifiedNamespaceNameContext)
+	if i == nil {
+		return ""
+	}
+	list, _ := i.NamespaceNameList().(*phpparser.NamespaceNameListContext)
+	if list == nil {
+		return y.resolveName(i.GetText())
 	}
+	return y.resolveName(list.GetText())
+}
 
-	return nil
+// resolveName 暂不区分命名空间，\Foo\Bar\baz 使用最后一段 baz 作为名字
+func (y *builder) resolveName(name string) string {
+	name = strings.TrimPrefix(name, "namespace")
+	if idx := strings.LastIndex(name, "\\"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return builtinFunctionName(name)
 }
 
 func (y *builder) VisitNamespaceNameTail(raw phpparser.INamespaceNameTailContext) interface{} {
@@ -12,5 +12,12 @@ func (y *builder) VisitStaticVariableStatement(raw phpparser.IStaticVariableStat
 		return nil
 	}
 
+	for _, v := range i.AllVariableInitializer() {
+		name, value := y.VisitVariableInitializer(v)
+		if value == nil {
+			value = y.ir.EmitConstInstNil()
+		}
+		y.ir.WriteVariable(name, value)
+	}
 	return nil
 }
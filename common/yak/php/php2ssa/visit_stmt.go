@@ -61,6 +61,13 @@ func (y *builder) VisitGlobalConstantDeclaration(raw phpparser.IGlobalConstantDe
 		return nil
 	}
 
+	for _, c := range i.AllIdentifierInitializer() {
+		name, val := y.VisitIdentifierInitializer(c)
+		if name == "" || val == nil {
+			continue
+		}
+		y.ir.WriteVariable(name, val)
+	}
 	return nil
 }
 
@@ -74,6 +81,34 @@ func (y *builder) VisitNamespaceDeclaration(raw phpparser.INamespaceDeclarationC
 		return nil
 	}
 
+	// namespace 只影响符号的查找，代码按照顺序编译在当前函数中
+	for _, stmt := range i.AllNamespaceStatement() {
+		y.VisitNamespaceStatement(stmt)
+	}
+	return nil
+}
+
+func (y *builder) VisitNamespaceStatement(raw phpparser.INamespaceStatementContext) interface{} {
+	if y == nil || raw == nil {
+		return nil
+	}
+
+	i, _ := raw.(*phpparser.NamespaceStatementContext)
+	if i == nil {
+		return nil
+	}
+
+	if ret := i.Statement(); ret != nil {
+		y.VisitStatement(ret)
+	} else if ret := i.UseDeclaration(); ret != nil {
+		y.VisitUseDeclaration(ret)
+	} else if ret := i.FunctionDeclaration(); ret != nil {
+		y.VisitFunctionDeclaration(ret)
+	} else if ret := i.ClassDeclaration(); ret != nil {
+		y.VisitClassDeclaration(ret)
+	} else if ret := i.GlobalConstantDeclaration(); ret != nil {
+		y.VisitGlobalConstantDeclaration(ret)
+	}
 	return nil
 }
 
@@ -100,6 +135,13 @@ func (y *builder) VisitStatement(raw phpparser.IStatementContext) interface{} {
 		return nil
 	}
 
+	if y.ir.IsBlockFinish() {
+		// unreachable code after return / break / continue
+		return nil
+	}
+	recoverRange := y.SetRange(i.BaseParserRuleContext)
+	defer recoverRange()
+
 	if r := i.LabelStatement(); r != nil {
 		y.VisitLabelStatement(r)
 	} else if b := i.BlockStatement(); b != nil {
@@ -142,8 +184,6 @@ func (y *builder) VisitStatement(raw phpparser.IStatementContext) interface{} {
 		y.VisitGotoStatement(i.GotoStatement())
 	} else if i.DeclareStatement() != nil {
 		y.VisitDeclareStatement(i.DeclareStatement())
-	} else if i.ExpressionStatement() != nil {
-		y.VisitExpressionStatement(i.ExpressionStatement())
 	} else if i.EmptyStatement_() != nil {
 		y.VisitEmptyStatement(i.EmptyStatement_())
 	} else if i.InlineHtmlStatement() != nil {
@@ -242,7 +282,7 @@ func (y *builder) VisitTypeHint(raw phpparser.ITypeHintContext) ssa.Type {
 		return y.VisitPrimitiveType(i.PrimitiveType())
 	} else if i.Pipe() != nil {
 		types := lo.Map(i.AllTypeHint(), func(item phpparser.ITypeHintContext, index int) ssa.Type {
-			return y.VisitTypeHint(i)
+			return y.VisitTypeHint(item)
 		})
 		_ = types
 		// need a
@@ -1,6 +1,9 @@
 package php2ssa
 
-import phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
+import (
+	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
 
 func (y *builder) VisitTryCatchFinally(raw phpparser.ITryCatchFinallyContext) interface{} {
 	if y == nil || raw == nil {
@@ -12,6 +15,55 @@ func (y *builder) VisitTryCatchFinally(raw phpparser.ITryCatchFinallyContext) in
 		return nil
 	}
 
+	var catches []*phpparser.CatchClauseContext
+	for _, c := range i.AllCatchClause() {
+		if c, ok := c.(*phpparser.CatchClauseContext); ok {
+			catches = append(catches, c)
+		}
+	}
+
+	tryBuilder := y.ir.BuildTry()
+	tryBuilder.BuildTryBlock(func() {
+		y.VisitBlockStatement(i.BlockStatement())
+	})
+
+	// 所有 catch 共用同一个异常值，以第一个 catch 的变量名为准
+	var errName string
+	tryBuilder.BuildError(func() string {
+		if len(catches) > 0 && catches[0].VarName() != nil {
+			errName = catches[0].VarName().GetText()
+		}
+		return errName
+	})
+
+	tryBuilder.BuildCatch(func() {
+		if len(catches) == 1 {
+			y.VisitBlockStatement(catches[0].BlockStatement())
+			return
+		}
+		// catch (A $a) {} catch (B $b) {}: 异常类型在编译期无法确定，每个 catch 都可能执行
+		ifb := y.ir.CreateIfBuilder()
+		for _, c := range catches {
+			c := c
+			ifb.AppendItem(func() ssa.Value {
+				return y.ir.EmitConstInstAny()
+			}, func() {
+				if c.VarName() != nil && errName != "" && c.VarName().GetText() != errName {
+					y.ir.WriteVariable(c.VarName().GetText(), y.ir.ReadValue(errName))
+				}
+				y.VisitBlockStatement(c.BlockStatement())
+			})
+		}
+		ifb.Build()
+	})
+
+	if f, ok := i.FinallyStatement().(*phpparser.FinallyStatementContext); ok {
+		tryBuilder.BuildFinally(func() {
+			y.VisitBlockStatement(f.BlockStatement())
+		})
+	}
+
+	tryBuilder.Finish()
 	return nil
 }
 
@@ -25,5 +77,6 @@ func (y *builder) VisitThrowStatement(raw phpparser.IThrowStatementContext) inte
 		return nil
 	}
 
+	y.ir.EmitPanic(y.VisitExpression(i.Expression()))
 	return nil
 }
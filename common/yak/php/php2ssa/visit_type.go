@@ -35,6 +35,4 @@ func (y *builder) VisitCastOperation(raw phpparser.ICastOperationContext) ssa.Ty
 	default:
 		return ssa.GetAnyType()
 	}
-
-	return nil
 }
@@ -12,5 +12,14 @@ func (y *builder) VisitUnsetStatement(raw phpparser.IUnsetStatementContext) inte
 		return nil
 	}
 
+	list, _ := i.ChainList().(*phpparser.ChainListContext)
+	if list == nil {
+		return nil
+	}
+	for _, c := range list.AllChain() {
+		if variable := y.VisitLeftChain(c); variable != nil {
+			y.ir.AssignVariable(variable, y.ir.EmitConstInstNil())
+		}
+	}
 	return nil
 }
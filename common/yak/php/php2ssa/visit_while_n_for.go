@@ -1,6 +1,7 @@
 package php2ssa
 
 import (
+	"github.com/yaklang/yaklang/common/log"
 	phpparser "github.com/yaklang/yaklang/common/yak/php/parser"
 	"github.com/yaklang/yaklang/common/yak/ssa"
 )
@@ -15,16 +16,16 @@ func (y *builder) VisitWhileStatement(raw phpparser.IWhileStatementContext) inte
 		return nil
 	}
 
-	loop := y.ir.BuildLoop()
-	loop.BuildCondition(func() ssa.Value {
+	loop := y.ir.CreateLoopBuilder()
+	loop.SetCondition(func() ssa.Value {
 		return y.VisitParentheses(i.Parentheses())
 	})
 	if i.Statement() != nil {
-		loop.BuildBody(func() {
+		loop.SetBody(func() {
 			y.VisitStatement(i.Statement())
 		})
 	} else {
-		loop.BuildBody(func() {
+		loop.SetBody(func() {
 			y.VisitInnerStatementList(i.InnerStatementList())
 		})
 	}
@@ -42,19 +43,14 @@ func (y *builder) VisitDoWhileStatement(raw phpparser.IDoWhileStatementContext)
 		return nil
 	}
 
-	loop := y.ir.BuildLoop()
-	loop.BuildCondition(func() ssa.Value {
-		return y.ir.EmitConstInst(true)
+	// do {body} while (cond) => body; while (cond) {body}
+	y.VisitStatement(i.Statement())
+	loop := y.ir.CreateLoopBuilder()
+	loop.SetCondition(func() ssa.Value {
+		return y.VisitParentheses(i.Parentheses())
 	})
-	loop.BuildBody(func() {
+	loop.SetBody(func() {
 		y.VisitStatement(i.Statement())
-		y.ir.BuildIf().BuildCondition(func() ssa.Value {
-			return y.VisitParentheses(i.Parentheses())
-		}).BuildTrue(func() {
-			y.ir.EmitJump(y.ir.GetContinue())
-		}).BuildFalse(func() {
-			y.ir.EmitJump(y.ir.GetBreak())
-		}).Finish()
 	})
 	loop.Finish()
 	return nil
@@ -70,6 +66,33 @@ func (y *builder) VisitForStatement(raw phpparser.IForStatementContext) interfac
 		return nil
 	}
 
+	loop := y.ir.CreateLoopBuilder()
+	if init, ok := i.ForInit().(*phpparser.ForInitContext); ok {
+		loop.SetFirst(func() []ssa.Value {
+			return y.VisitExpressionList(init.ExpressionList())
+		})
+	}
+	loop.SetCondition(func() ssa.Value {
+		// for (init; a, b; update) 以最后一个表达式作为条件
+		conds := y.VisitExpressionList(i.ExpressionList())
+		if len(conds) > 0 {
+			return conds[len(conds)-1]
+		}
+		return y.ir.EmitConstInst(true)
+	})
+	if update, ok := i.ForUpdate().(*phpparser.ForUpdateContext); ok {
+		loop.SetThird(func() []ssa.Value {
+			return y.VisitExpressionList(update.ExpressionList())
+		})
+	}
+	loop.SetBody(func() {
+		if i.Statement() != nil {
+			y.VisitStatement(i.Statement())
+		} else {
+			y.VisitInnerStatementList(i.InnerStatementList())
+		}
+	})
+	loop.Finish()
 	return nil
 }
 
@@ -83,10 +106,9 @@ func (y *builder) VisitContinueStatement(raw phpparser.IContinueStatementContext
 		return nil
 	}
 
-	if t := y.ir.GetContinue(); t != nil {
-		return y.ir.EmitJump(t)
+	if !y.ir.Continue() {
+		log.Errorf("continue statement not in loop: raw %v", i.GetText())
 	}
-
 	return nil
 }
 
@@ -100,5 +122,52 @@ func (y *builder) VisitForeachStatement(raw phpparser.IForeachStatementContext)
 		return nil
 	}
 
+	/*
+		foreach ($arr as $value)
+		foreach ($arr as $key => $value)
+		foreach ($arr as [$a, $b])
+		foreach ($arr as list($a, $b))
+	*/
+	loop := y.ir.CreateLoopBuilder()
+	var iter ssa.Value
+	loop.SetFirst(func() []ssa.Value {
+		if expr := i.Expression(); expr != nil {
+			iter = y.VisitExpression(expr)
+		} else {
+			iter = y.VisitChain(i.Chain(0))
+		}
+		return []ssa.Value{iter}
+	})
+	loop.SetCondition(func() ssa.Value {
+		key, field, ok := y.ir.EmitNext(iter, false)
+		switch {
+		case i.ArrayDestructuring() != nil:
+			y.assignArrayDestructuring(i.ArrayDestructuring(), field)
+		case i.List() != nil:
+			y.assignList(i.AssignmentList(), field)
+		case i.DoubleArrow() != nil:
+			// key => value, value is the last chain
+			if variable := y.VisitLeftAssignable(i.Assignable()); variable != nil {
+				y.ir.AssignVariable(variable, key)
+			}
+			chains := i.AllChain()
+			if variable := y.VisitLeftChain(chains[len(chains)-1]); variable != nil {
+				y.ir.AssignVariable(variable, field)
+			}
+		default:
+			if variable := y.VisitLeftAssignable(i.Assignable()); variable != nil {
+				y.ir.AssignVariable(variable, field)
+			}
+		}
+		return ok
+	})
+	loop.SetBody(func() {
+		if i.Statement() != nil {
+			y.VisitStatement(i.Statement())
+		} else {
+			y.VisitInnerStatementList(i.InnerStatementList())
+		}
+	})
+	loop.Finish()
 	return nil
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/yaklang/yaklang/common/rpa"
 	"github.com/yaklang/yaklang/common/sca"
 	"github.com/yaklang/yaklang/common/simulator"
+	"github.com/yaklang/yaklang/common/smuggle"
 	"github.com/yaklang/yaklang/common/systemd"
 	"github.com/yaklang/yaklang/common/t3"
 	"github.com/yaklang/yaklang/common/utils"
@@ -291,6 +292,9 @@ func initYaklangLib() {
 	// openapi
 	yaklang.Import("openapi", openapi.Exports)
 
+	// http request smuggling
+	yaklang.Import("smuggle", smuggle.Exports)
+
 	yaklang.Import("sandbox", SandboxExports)
 
 	// 处理 yakit 库的一些函数名
@@ -3,6 +3,8 @@ package ssa
 import "fmt"
 
 type Range struct {
+	// FileName is the source file of this range, empty when compiling a single code string
+	FileName   string
 	SourceCode *string
 	Start, End *Position
 }
@@ -1,6 +1,9 @@
 package ssa
 
 import (
+	"io/fs"
+	"os"
+	"strings"
 	"sync"
 
 	"github.com/yaklang/yaklang/common/utils/omap"
@@ -103,3 +106,29 @@ func (pkg *Package) GetFunction(name string) *Function {
 		return nil
 	}
 }
+
+// SetFileSystem set the file system of a project, include / import will read file from it
+func (prog *Program) SetFileSystem(fsys fs.FS) {
+	prog.fileSystem = fsys
+}
+
+func (prog *Program) GetFileSystem() fs.FS {
+	return prog.fileSystem
+}
+
+// SetCurrentFile set the file being compiled, range created by frontend will record this file name
+func (prog *Program) SetCurrentFile(name string) {
+	prog.currentFile = name
+}
+
+func (prog *Program) GetCurrentFile() string {
+	return prog.currentFile
+}
+
+// ReadFile read file from the file system of project, or local file if not set
+func (prog *Program) ReadFile(name string) ([]byte, error) {
+	if prog.fileSystem == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(prog.fileSystem, strings.TrimPrefix(name, "/"))
+}
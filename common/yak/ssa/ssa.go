@@ -1,6 +1,7 @@
 package ssa
 
 import (
+	"io/fs"
 	"sync"
 
 	"github.com/yaklang/yaklang/common/utils/omap"
@@ -135,6 +136,10 @@ type Program struct {
 
 	// for build
 	buildOnce sync.Once
+
+	// for project: the file system of source code and the file being compiled
+	fileSystem  fs.FS
+	currentFile string
 }
 
 type Package struct {
@@ -0,0 +1,315 @@
+package ssaapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+// GetProgramHash 返回程序在数据库中的 hash，即 config.CaclHash()
+func (p *Program) GetProgramHash() string {
+	if p.config == nil {
+		return ""
+	}
+	return p.config.CaclHash()
+}
+
+// SaveToDatabase 把程序中所有的函数、基本块与指令保存到数据库中
+func (p *Program) SaveToDatabase(db *gorm.DB) error {
+	if p.IsNil() || p.config == nil {
+		return utils.Errorf("program or config is nil")
+	}
+	if db == nil {
+		return utils.Errorf("database is nil")
+	}
+
+	hash := p.GetProgramHash()
+	s := newIrSerializer(p.Program)
+	codes := s.serialize()
+
+	prog := &yakit.SSAProgram{
+		Hash:        hash,
+		Language:    string(p.config.language),
+		ProgramName: p.config.programName,
+	}
+	if main := s.mainFunction(); main != nil {
+		prog.MainFunctionId = s.id(main)
+	}
+
+	files := make([]*yakit.SSASourceFile, 0, len(p.config.sourceFiles))
+	for name, contentHash := range p.config.sourceFiles {
+		files = append(files, &yakit.SSASourceFile{
+			FileName:    name,
+			ContentHash: contentHash,
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].FileName < files[j].FileName })
+
+	return yakit.SaveSSAProgram(db, prog, files, codes)
+}
+
+// saveToDatabaseIfNotCached 数据库中已经有相同 hash 且没有过期的程序时不再重复保存
+func (p *Program) saveToDatabaseIfNotCached(db *gorm.DB) error {
+	if _, ok := loadCachedIrProgram(db, p.GetProgramHash()); ok {
+		return nil
+	}
+	return p.SaveToDatabase(db)
+}
+
+// saveToConfigDatabase 编译完成后，设置了数据库时保存程序
+func (p *Program) saveToConfigDatabase() {
+	if p.config == nil || p.config.db == nil {
+		return
+	}
+	if err := p.saveToDatabaseIfNotCached(p.config.db); err != nil {
+		log.Errorf("save ssa program to database failed: %v", err)
+	}
+}
+
+// loadCachedIrProgram 加载数据库中相同 hash 的程序，已经过期（部分源文件变化）的程序不能作为缓存
+func loadCachedIrProgram(db *gorm.DB, hash string) (*IrProgram, bool) {
+	if !yakit.HasSSAProgram(db, hash) {
+		return nil, false
+	}
+	ir, err := LoadIrProgram(db, hash)
+	if err != nil || ir.Outdated {
+		return nil, false
+	}
+	return ir, true
+}
+
+// ParseIrProgram 与 Parse 相同，但是需要通过 WithDatabase 设置数据库，
+// 数据库中已经保存了相同配置（config.CaclHash）的程序时直接加载，不会重新编译，返回的 IrProgram 在查询时按函数加载指令
+func ParseIrProgram(code string, opts ...Option) (*IrProgram, error) {
+	config := defaultConfig(code)
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.db == nil {
+		return nil, utils.Errorf("database is not set")
+	}
+	hash := config.CaclHash()
+	if ir, ok := loadCachedIrProgram(config.db, hash); ok {
+		return ir, nil
+	}
+	// Parse 可能命中内存中的缓存（编译时没有设置数据库），这里需要再保存一次
+	prog, err := Parse(code, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := prog.saveToDatabaseIfNotCached(config.db); err != nil {
+		return nil, err
+	}
+	return LoadIrProgram(config.db, hash)
+}
+
+// invalidateChangedSourceFiles 同名程序中，内容发生变化或被删除的源文件对应的 IR 会被清除
+func invalidateChangedSourceFiles(db *gorm.DB, name string, current map[string]string) error {
+	progs, err := yakit.GetSSAProgramsByName(db, name)
+	if err != nil {
+		return err
+	}
+	for _, prog := range progs {
+		files, err := yakit.GetSSASourceFiles(db, prog.Hash)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if hash, ok := current[file.FileName]; ok && hash == file.ContentHash {
+				continue
+			}
+			log.Infof("ssa program %s: source file %s changed, invalidate it", name, file.FileName)
+			if err := yakit.InvalidateSSASourceFile(db, prog.Hash, file.FileName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// irSerializer 为函数与基本块分配 ID（它们不在 Program.IdToInstructionMap 中），
+// 普通指令使用原本的 ID，删除过指令（如 phi）之后 ID 不连续，新的 ID 从最大的 ID 之后开始分配
+type irSerializer struct {
+	prog   *ssa.Program
+	ids    map[ssa.Instruction]int64
+	nextId int64
+
+	funcs     []*ssa.Function
+	variables map[ssa.Instruction][]string
+}
+
+func newIrSerializer(prog *ssa.Program) *irSerializer {
+	s := &irSerializer{
+		prog:      prog,
+		ids:       make(map[ssa.Instruction]int64),
+		variables: make(map[ssa.Instruction][]string),
+	}
+	prog.IdToInstructionMap.ForEach(func(id int, inst ssa.Instruction) bool {
+		s.ids[inst] = int64(id)
+		if int64(id) >= s.nextId {
+			s.nextId = int64(id) + 1
+		}
+		return true
+	})
+
+	// 按照名字排序，保证每次保存的 ID 一致
+	pkgNames := utils.GetSortedMapKeys(prog.Packages)
+	for _, pkgName := range pkgNames {
+		pkg := prog.Packages[pkgName]
+		for _, funcName := range utils.GetSortedMapKeys(pkg.Funcs) {
+			s.addFunction(pkg.Funcs[funcName])
+		}
+	}
+
+	prog.NameToInstructions.ForEach(func(name string, insts []ssa.Instruction) bool {
+		for _, inst := range insts {
+			s.variables[inst] = append(s.variables[inst], name)
+		}
+		return true
+	})
+	return s
+}
+
+func (s *irSerializer) addFunction(f *ssa.Function) {
+	if _, ok := s.ids[f]; ok {
+		return
+	}
+	s.ids[f] = s.nextId
+	s.nextId++
+	s.funcs = append(s.funcs, f)
+	for _, b := range f.Blocks {
+		if _, ok := s.ids[b]; !ok {
+			s.ids[b] = s.nextId
+			s.nextId++
+		}
+	}
+	for _, child := range f.ChildFuncs {
+		s.addFunction(child)
+	}
+}
+
+func (s *irSerializer) mainFunction() *ssa.Function {
+	for _, f := range s.funcs {
+		if f.IsMain() {
+			return f
+		}
+	}
+	return nil
+}
+
+// id 返回指令的 ID，不属于这个程序的指令返回 -1
+func (s *irSerializer) id(inst ssa.Instruction) int64 {
+	if utils.IsNil(inst) {
+		return -1
+	}
+	if id, ok := s.ids[inst]; ok {
+		return id
+	}
+	return -1
+}
+
+func (s *irSerializer) serialize() []*yakit.SSAIrCode {
+	codes := make([]*yakit.SSAIrCode, 0, len(s.ids))
+	s.prog.IdToInstructionMap.ForEach(func(_ int, inst ssa.Instruction) bool {
+		codes = append(codes, s.serializeInstruction(inst))
+		return true
+	})
+	for _, f := range s.funcs {
+		codes = append(codes, s.serializeFunction(f))
+		for _, b := range f.Blocks {
+			codes = append(codes, s.serializeBlock(b))
+		}
+	}
+	return codes
+}
+
+func (s *irSerializer) newIrCode(inst ssa.Instruction) *yakit.SSAIrCode {
+	code := &yakit.SSAIrCode{
+		InstId:      s.id(inst),
+		Opcode:      string(inst.GetOpcode()),
+		Name:        inst.GetName(),
+		VerboseName: inst.GetVerboseName(),
+		Variables:   strings.Join(s.variables[inst], ","),
+		IsExtern:    inst.IsExtern(),
+		FunctionId:  -1,
+		BlockId:     -1,
+	}
+	if f := inst.GetFunc(); f != nil {
+		code.FunctionId = s.id(f)
+	}
+	if b := inst.GetBlock(); b != nil {
+		code.BlockId = s.id(b)
+	}
+	if t, ok := inst.(ssa.Typed); ok && !utils.IsNil(t.GetType()) {
+		code.TypeStr = t.GetType().String()
+	}
+	if node, ok := inst.(ssa.Node); ok {
+		code.Defs = instIdsToString(s, ssa.GetValues(node))
+		code.Users = instIdsToString(s, node.GetUsers())
+	}
+	if r := inst.GetRange(); r != nil {
+		code.FileName = r.FileName
+		if r.SourceCode != nil {
+			code.SourceCode = *r.SourceCode
+		}
+		if r.Start != nil {
+			code.StartOffset, code.StartLine, code.StartColumn = r.Start.Offset, r.Start.Line, r.Start.Column
+		}
+		if r.End != nil {
+			code.EndOffset, code.EndLine, code.EndColumn = r.End.Offset, r.End.Line, r.End.Column
+		}
+	}
+	return code
+}
+
+func (s *irSerializer) serializeInstruction(inst ssa.Instruction) *yakit.SSAIrCode {
+	code := s.newIrCode(inst)
+	code.Disasm = ssa.LineDisasm(inst)
+	return code
+}
+
+func (s *irSerializer) serializeFunction(f *ssa.Function) *yakit.SSAIrCode {
+	code := s.newIrCode(f)
+	code.Disasm = f.GetName()
+	// 函数的 FunctionId 指向父函数
+	code.FunctionId = -1
+	if parent := f.GetParent(); parent != nil {
+		code.FunctionId = s.id(parent)
+	}
+	code.BlockId = -1
+	code.Params = instIdsToString(s, f.Param)
+	code.Returns = instIdsToString(s, f.Return)
+	code.Blocks = instIdsToString(s, f.Blocks)
+	code.ChildFuncs = instIdsToString(s, f.ChildFuncs)
+	return code
+}
+
+func (s *irSerializer) serializeBlock(b *ssa.BasicBlock) *yakit.SSAIrCode {
+	code := s.newIrCode(b)
+	code.Disasm = b.GetName()
+	code.TypeStr = ""
+	code.Preds = instIdsToString(s, b.Preds)
+	code.Succs = instIdsToString(s, b.Succs)
+	insts := make([]ssa.Instruction, 0, len(b.Phis)+len(b.Insts))
+	for _, phi := range b.Phis {
+		insts = append(insts, phi)
+	}
+	insts = append(insts, b.Insts...)
+	code.Insts = instIdsToString(s, insts)
+	return code
+}
+
+func instIdsToString[T ssa.Instruction](s *irSerializer, insts []T) string {
+	ids := make([]int64, 0, len(insts))
+	for _, inst := range insts {
+		if id := s.id(inst); id >= 0 {
+			ids = append(ids, id)
+		}
+	}
+	return yakit.IdsToString(ids)
+}
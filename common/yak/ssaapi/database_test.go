@@ -0,0 +1,220 @@
+package ssaapi
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+func newTestIrDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "ssa.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.AutoMigrate(&yakit.SSAProgram{}, &yakit.SSASourceFile{}, &yakit.SSAIrCode{})
+	return db
+}
+
+func TestIrProgram_SaveAndLoad(t *testing.T) {
+	db := newTestIrDatabase(t)
+
+	prog, err := Parse(`
+f = (a) => {
+	b = a + 1
+	return b
+}
+c = f(1)
+println(c)
+`, WithDatabase(db), WithExternInfo("TestIrProgram_SaveAndLoad"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ir, err := LoadIrProgram(db, prog.GetProgramHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, ir.Outdated)
+
+	main, err := ir.GetMainFunction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "main", main.GetName())
+	assert.NotEmpty(t, main.GetInstructions())
+
+	// lazy load function by name
+	children := main.GetChildFunctions()
+	if assert.Len(t, children, 1) {
+		f := children[0]
+		params := f.GetParameters()
+		if assert.Len(t, params, 1) {
+			assert.Equal(t, "a", params[0].GetName())
+			// use-def: a + 1
+			users := params[0].GetUsers()
+			if assert.NotEmpty(t, users) {
+				assert.Equal(t, "BinOp", users[0].Opcode)
+				assert.Equal(t, f.GetId(), users[0].GetFunction().GetId())
+			}
+		}
+		assert.Len(t, f.GetReturns(), 1)
+	}
+
+	// Ref: same as Program.Ref
+	c := ir.Ref("c")
+	if assert.Len(t, c, len(prog.Ref("c"))) && assert.NotEmpty(t, c) {
+		assert.Equal(t, int64(prog.Ref("c")[0].GetId()), c[0].GetId())
+		assert.Equal(t, "Call", c[0].Opcode)
+		assert.Equal(t, int64(6), c[0].GetRange().Start.Line)
+		operands := c[0].GetOperands()
+		if assert.NotEmpty(t, operands) {
+			assert.True(t, operands[0].IsFunction())
+		}
+	}
+}
+
+func TestIrProgram_InvalidateChangedFile(t *testing.T) {
+	db := newTestIrDatabase(t)
+
+	fsys := fstest.MapFS{
+		"a.yak": {Data: []byte("a = 1\n")},
+		"b.yak": {Data: []byte("b = 2\n")},
+	}
+	prog, err := ParseProject(fsys, WithDatabase(db), WithProgramName("test-project"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldHash := prog.GetProgramHash()
+	ir, err := LoadIrProgram(db, oldHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, ir.Ref("a"))
+	assert.NotEmpty(t, ir.Ref("b"))
+
+	// b.yak changed
+	fsys["b.yak"] = &fstest.MapFile{Data: []byte("b = 3\n")}
+	prog, err = ParseProject(fsys, WithDatabase(db), WithProgramName("test-project"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, oldHash, prog.GetProgramHash())
+
+	ir, err = LoadIrProgram(db, oldHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, ir.Outdated)
+	assert.NotEmpty(t, ir.Ref("a"))
+	assert.Empty(t, ir.Ref("b"))
+
+	ir, err = LoadIrProgram(db, prog.GetProgramHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, ir.Outdated)
+	assert.NotEmpty(t, ir.Ref("b"))
+}
+
+func TestIrProgram_UniqueInstId(t *testing.T) {
+	db := newTestIrDatabase(t)
+
+	// 闭包中修改外部变量与循环会删除部分指令，指令 ID 不再连续
+	prog, err := Parse(`
+a = 1
+b = () => { a = 2 }
+b()
+println(a)
+for {
+	a++
+	if a > 3 { break }
+}
+`, WithDatabase(db), WithExternInfo("TestIrProgram_UniqueInstId"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var codes []*yakit.SSAIrCode
+	db.Model(&yakit.SSAIrCode{}).Where("program_hash = ?", prog.GetProgramHash()).Find(&codes)
+	assert.NotEmpty(t, codes)
+	ids := make(map[int64]string)
+	for _, code := range codes {
+		if opcode, ok := ids[code.InstId]; ok {
+			t.Fatalf("duplicate inst id %d: %s and %s", code.InstId, opcode, code.Opcode)
+		}
+		ids[code.InstId] = code.Opcode
+	}
+}
+
+func TestIrProgram_ParseProjectFromCache(t *testing.T) {
+	db := newTestIrDatabase(t)
+
+	fsys := fstest.MapFS{
+		"lib.yak":  {Data: []byte("f = (a) => {\n\treturn a + 1\n}\ng = () => {\n\treturn 2\n}\n")},
+		"main.yak": {Data: []byte("c = f(1)\n")},
+	}
+	ir, err := ParseProjectIrProgram(fsys, WithDatabase(db), WithProgramName("cache-project"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var maxId uint
+	db.Model(&yakit.SSAIrCode{}).Where("program_hash = ?", ir.Hash).Select("max(id)").Row().Scan(&maxId)
+
+	// 源文件没有变化时直接从数据库加载，不会重新编译保存
+	cached, err := ParseProjectIrProgram(fsys, WithDatabase(db), WithProgramName("cache-project"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ir.Hash, cached.Hash)
+	var cachedMaxId uint
+	db.Model(&yakit.SSAIrCode{}).Where("program_hash = ?", ir.Hash).Select("max(id)").Row().Scan(&cachedMaxId)
+	assert.Equal(t, maxId, cachedMaxId)
+
+	// 函数中的指令在查询时才加载
+	assert.Empty(t, cached.loadedFuncs)
+	f := cached.Ref("f")
+	if assert.Len(t, f, 1) && assert.True(t, f[0].IsFunction()) {
+		assert.Len(t, f[0].GetParameters(), 1)
+		assert.NotEmpty(t, f[0].GetInstructions())
+	}
+	assert.Len(t, cached.loadedFuncs, 1)
+	assert.Contains(t, cached.loadedFuncs, f[0].GetId())
+
+	// 源文件变化之后重新编译
+	fsys["main.yak"] = &fstest.MapFile{Data: []byte("c = f(2)\n")}
+	changed, err := ParseProjectIrProgram(fsys, WithDatabase(db), WithProgramName("cache-project"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, ir.Hash, changed.Hash)
+	assert.NotEmpty(t, changed.Ref("c"))
+}
+
+func TestIrProgram_ParseFromCache(t *testing.T) {
+	db := newTestIrDatabase(t)
+	code := "a = 1\nb = a + 2\n"
+
+	// 内存中的缓存没有保存到数据库时，也需要保存
+	_, err := Parse(code, WithExternInfo("TestIrProgram_ParseFromCache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ir, err := ParseIrProgram(code, WithDatabase(db), WithExternInfo("TestIrProgram_ParseFromCache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, ir.Ref("b"))
+
+	cached, err := ParseIrProgram(code, WithDatabase(db), WithExternInfo("TestIrProgram_ParseFromCache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ir.Hash, cached.Hash)
+	assert.NotEmpty(t, cached.Ref("b"))
+}
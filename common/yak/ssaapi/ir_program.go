@@ -0,0 +1,261 @@
+package ssaapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+// IrProgram 是保存在数据库中的 SSA 程序，不需要重新编译就可以查询，
+// 指令按照函数为单位懒加载：第一次访问某个函数中的指令时，才会把整个函数的指令读取出来
+type IrProgram struct {
+	db *gorm.DB
+
+	Hash        string
+	Language    Language
+	ProgramName string
+	// 部分源文件已经发生变化，对应的 IR 已经被删除
+	Outdated bool
+
+	mainFunctionId int64
+	codes          map[int64]*IrCode
+	loadedFuncs    map[int64]struct{}
+}
+
+// IrCode 是数据库中的一条 SSA 指令（包含函数与基本块）
+type IrCode struct {
+	*yakit.SSAIrCode
+	prog *IrProgram
+}
+
+func LoadIrProgram(db *gorm.DB, hash string) (*IrProgram, error) {
+	if db == nil {
+		return nil, utils.Errorf("database is nil")
+	}
+	prog, err := yakit.GetSSAProgramByHash(db, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &IrProgram{
+		db:             db,
+		Hash:           prog.Hash,
+		Language:       Language(prog.Language),
+		ProgramName:    prog.ProgramName,
+		Outdated:       prog.Outdated,
+		mainFunctionId: prog.MainFunctionId,
+		codes:          make(map[int64]*IrCode),
+		loadedFuncs:    make(map[int64]struct{}),
+	}, nil
+}
+
+// LoadIrProgramFromProjectDatabase 从 yakit 的项目数据库中加载程序
+func LoadIrProgramFromProjectDatabase(hash string) (*IrProgram, error) {
+	return LoadIrProgram(consts.GetGormProjectDatabase(), hash)
+}
+
+func (p *IrProgram) newIrCode(code *yakit.SSAIrCode) *IrCode {
+	if ret, ok := p.codes[code.InstId]; ok {
+		return ret
+	}
+	ret := &IrCode{SSAIrCode: code, prog: p}
+	p.codes[code.InstId] = ret
+	return ret
+}
+
+// loadFunction 读取函数 id 中所有的指令与基本块
+func (p *IrProgram) loadFunction(id int64) {
+	if id < 0 {
+		return
+	}
+	if _, ok := p.loadedFuncs[id]; ok {
+		return
+	}
+	p.loadedFuncs[id] = struct{}{}
+	codes, err := yakit.GetSSAIrCodesByFunction(p.db, p.Hash, id)
+	if err != nil {
+		log.Errorf("load ssa function %d failed: %v", id, err)
+		return
+	}
+	for _, code := range codes {
+		p.newIrCode(code)
+	}
+}
+
+func (p *IrProgram) GetInstructionById(id int64) (*IrCode, error) {
+	if code, ok := p.codes[id]; ok {
+		return code, nil
+	}
+	code, err := yakit.GetSSAIrCodeById(p.db, p.Hash, id)
+	if err != nil {
+		return nil, err
+	}
+	ret := p.newIrCode(code)
+	if ret.IsFunction() {
+		p.loadFunction(ret.InstId)
+	} else {
+		p.loadFunction(ret.FunctionId)
+	}
+	return ret, nil
+}
+
+func (p *IrProgram) getInstructionsByIds(ids []int64) []*IrCode {
+	ret := make([]*IrCode, 0, len(ids))
+	var missing []int64
+	for _, id := range ids {
+		if _, ok := p.codes[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		codes, err := yakit.GetSSAIrCodesByIds(p.db, p.Hash, missing)
+		if err != nil {
+			log.Errorf("load ssa ir codes failed: %v", err)
+		}
+		for _, code := range codes {
+			p.newIrCode(code)
+		}
+	}
+	for _, id := range ids {
+		// 被删除的指令（源文件发生变化）会被忽略
+		if code, ok := p.codes[id]; ok {
+			ret = append(ret, code)
+		}
+	}
+	return ret
+}
+
+func (p *IrProgram) GetMainFunction() (*IrCode, error) {
+	return p.GetInstructionById(p.mainFunctionId)
+}
+
+// GetFunctionsByName 根据函数名查询函数，函数中的指令会在访问时加载
+func (p *IrProgram) GetFunctionsByName(name string) []*IrCode {
+	codes, err := yakit.GetSSAIrCodesByOpcodeAndName(p.db, p.Hash, string(ssa.OpFunction), name)
+	if err != nil {
+		log.Errorf("get ssa function %s failed: %v", name, err)
+		return nil
+	}
+	ret := make([]*IrCode, 0, len(codes))
+	for _, code := range codes {
+		ret = append(ret, p.newIrCode(code))
+	}
+	return ret
+}
+
+// Ref 与 Program.Ref 一致，查询变量 name 对应的所有指令
+func (p *IrProgram) Ref(name string) []*IrCode {
+	codes, err := yakit.GetSSAIrCodesByVariable(p.db, p.Hash, name)
+	if err != nil {
+		log.Errorf("ref %s failed: %v", name, err)
+		return nil
+	}
+	ret := make([]*IrCode, 0, len(codes))
+	for _, code := range codes {
+		// LIKE 中的 _ 会匹配任意字符，这里再检查一次
+		if !utils.StringArrayContains(strings.Split(code.Variables, ","), name) {
+			continue
+		}
+		ret = append(ret, p.newIrCode(code))
+	}
+	return ret
+}
+
+func (c *IrCode) GetId() int64           { return c.InstId }
+func (c *IrCode) GetOpcode() ssa.Opcode  { return ssa.Opcode(c.Opcode) }
+func (c *IrCode) GetName() string        { return c.Name }
+func (c *IrCode) GetProgram() *IrProgram { return c.prog }
+func (c *IrCode) IsFunction() bool       { return c.GetOpcode() == ssa.OpFunction }
+func (c *IrCode) IsBasicBlock() bool     { return c.GetOpcode() == ssa.OpBasicBlock }
+func (c *IrCode) String() string         { return c.Disasm }
+
+func (c *IrCode) GetOperands() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.Defs))
+}
+
+func (c *IrCode) GetUsers() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.Users))
+}
+
+func (c *IrCode) GetParameters() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.Params))
+}
+
+func (c *IrCode) GetReturns() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.Returns))
+}
+
+func (c *IrCode) GetBlocks() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.Blocks))
+}
+
+func (c *IrCode) GetChildFunctions() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.ChildFuncs))
+}
+
+func (c *IrCode) GetPreds() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.Preds))
+}
+
+func (c *IrCode) GetSuccs() []*IrCode {
+	return c.prog.getInstructionsByIds(yakit.StringToIds(c.Succs))
+}
+
+// GetInstructions 基本块返回其中的指令，函数返回所有基本块中的指令
+func (c *IrCode) GetInstructions() []*IrCode {
+	switch {
+	case c.IsBasicBlock():
+		return c.prog.getInstructionsByIds(yakit.StringToIds(c.Insts))
+	case c.IsFunction():
+		c.prog.loadFunction(c.InstId)
+		var ret []*IrCode
+		for _, block := range c.GetBlocks() {
+			ret = append(ret, block.GetInstructions()...)
+		}
+		return ret
+	default:
+		return nil
+	}
+}
+
+// GetFunction 返回指令所在的函数，函数返回它的父函数
+func (c *IrCode) GetFunction() *IrCode {
+	if c.FunctionId < 0 {
+		return nil
+	}
+	f, err := c.prog.GetInstructionById(c.FunctionId)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+func (c *IrCode) GetBlock() *IrCode {
+	if c.BlockId < 0 {
+		return nil
+	}
+	b, err := c.prog.GetInstructionById(c.BlockId)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func (c *IrCode) GetRange() *ssa.Range {
+	r := ssa.NewRange(
+		ssa.NewPosition(c.StartOffset, c.StartLine, c.StartColumn),
+		ssa.NewPosition(c.EndOffset, c.EndLine, c.EndColumn),
+		c.SourceCode,
+	)
+	r.FileName = c.FileName
+	return r
+}
+
+func (c *IrCode) StringWithSource() string {
+	return fmt.Sprintf("[%-6s] %s\t%s", c.Opcode, c.Disasm, c.GetRange())
+}
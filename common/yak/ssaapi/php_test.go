@@ -0,0 +1,255 @@
+package ssaapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPHP_SuperGlobalToSink(t *testing.T) {
+	prog, err := Parse(`<?php
+$a = $_GET["cmd"];
+$b = "ls " . $a;
+system($b);
+`, WithLanguage(PHP))
+	if err != nil {
+		t.Fatal(err)
+	}
+	system := prog.Ref("system")
+	assert.NotEmpty(t, system)
+	assert.False(t, system[0].IsUndefined(), "builtin function should not be undefined")
+
+	var args Values
+	for _, v := range system {
+		for _, call := range v.GetUsers() {
+			if call.IsCall() {
+				args = append(args, call.GetCallArgs()...)
+			}
+		}
+	}
+	if len(args) != 1 {
+		t.Fatalf("want 1 arg for system, got %d", len(args))
+	}
+
+	get := prog.Ref("$_GET")
+	assert.NotEmpty(t, get)
+	assert.True(t, get[0].IsParameter(), "super global should be parameter of main")
+
+	var found bool
+	args.GetTopDefs().ForEach(func(v *Value) {
+		if r := v.GetRange(); r != nil && strings.Contains(r.String(), `$_GET["cmd"]`) {
+			found = true
+		}
+	})
+	assert.True(t, found, "system arg should come from $_GET")
+}
+
+func TestPHP_Include(t *testing.T) {
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.php")
+	err := os.WriteFile(lib, []byte(`<?php
+function run($cmd) {
+	return shell_exec($cmd);
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog, err := Parse(`<?php
+include "`+lib+`";
+run($_POST["c"]);
+`, WithLanguage(PHP))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := prog.Ref("run")
+	assert.NotEmpty(t, run)
+	assert.True(t, run[0].IsFunction(), "function in included file should be compiled")
+}
+
+func parsePHP(t *testing.T, code string) *Program {
+	prog, err := Parse(code, WithLanguage(PHP))
+	require.NoError(t, err)
+	return prog
+}
+
+// phpCallArgs 返回对函数 name 的所有调用的参数
+func phpCallArgs(prog *Program, name string) Values {
+	var args Values
+	for _, v := range prog.Ref(name) {
+		for _, call := range v.GetUsers() {
+			if call.IsCall() {
+				args = append(args, call.GetCallArgs()...)
+			}
+		}
+	}
+	return args
+}
+
+// phpConstDefs 返回 v 的顶层定义中的常量
+func phpConstDefs(v *Value) []any {
+	var consts []any
+	Values{v}.GetTopDefs().ForEach(func(def *Value) {
+		if def.IsConstInst() {
+			consts = append(consts, def.GetConstValue())
+		}
+	})
+	return consts
+}
+
+func TestPHP_ClassMembers(t *testing.T) {
+	prog := parsePHP(t, `<?php
+class A {
+	public $name = "default-name";
+	const T = "const-t";
+	public function __construct($n) { $this->name = $n; }
+	public function run() { return system($this->name); }
+}
+class B extends A {
+	public function hello() { return "hello"; }
+}
+$b = new B($_GET["x"]);
+$b->run();
+echo A::T;
+echo $b->hello();
+`)
+	b := prog.Ref("$b")
+	require.NotEmpty(t, b)
+	assert.True(t, b[0].IsMake(), "object is the class make")
+
+	echo := phpCallArgs(prog, "echo")
+	require.Len(t, echo, 2)
+	// class constant
+	assert.Equal(t, "const-t", echo[0].GetConstValue())
+	// method defined in B returns its value through the call
+	assert.True(t, echo[1].IsCall())
+	assert.Equal(t, []any{"hello"}, phpConstDefs(echo[1]))
+
+	// inherited method from A is compiled and reads the property of the object
+	system := phpCallArgs(prog, "system")
+	require.Len(t, system, 1)
+	assert.Contains(t, phpConstDefs(system[0]), "default-name")
+}
+
+func TestPHP_Namespace(t *testing.T) {
+	prog := parsePHP(t, `<?php
+namespace App\Util;
+function run($c) { return shell_exec($c); }
+\App\Util\run($_GET["a"]);
+run($_POST["b"]);
+namespace\run("c");
+`)
+	run := prog.Ref("run")
+	require.NotEmpty(t, run)
+	assert.True(t, run[0].IsFunction())
+	// fully qualified, unqualified and namespace relative names resolve to the same function
+	assert.Len(t, phpCallArgs(prog, "run"), 3)
+
+	shellExec := phpCallArgs(prog, "shell_exec")
+	require.Len(t, shellExec, 1)
+	assert.True(t, shellExec[0].IsParameter())
+}
+
+func TestPHP_LoopPhi(t *testing.T) {
+	prog := parsePHP(t, `<?php
+$a = 1;
+for ($i = 0; $i < 10; $i++) { $a = $a + $i; }
+echo $a;
+$b = "init";
+foreach ($_GET as $k => $v) { $b = $v; }
+echo $b;
+$c = 0;
+while ($c < 5) { $c = $c + 2; }
+echo $c;
+`)
+	echo := phpCallArgs(prog, "echo")
+	require.Len(t, echo, 3)
+	for _, v := range echo {
+		assert.True(t, v.IsPhi(), "value after loop should be phi: %v", v)
+	}
+	// for: $a = phi(1, $a + $i), $i = phi(0, $i + 1)
+	assert.Contains(t, phpConstDefs(echo[0]), int64(1))
+	assert.Contains(t, phpConstDefs(echo[0]), int64(0))
+	// foreach: $b = phi("init", value of $_GET)
+	assert.Contains(t, phpConstDefs(echo[1]), "init")
+	assert.Greater(t, len(Values{echo[1]}.GetTopDefs()), 1)
+	// while: $c = phi(0, $c + 2)
+	assert.ElementsMatch(t, []any{int64(0), int64(2)}, phpConstDefs(echo[2]))
+}
+
+func TestPHP_TryCatch(t *testing.T) {
+	prog := parsePHP(t, `<?php
+$a = "init";
+try {
+	$a = $_GET["x"];
+	throw new Exception("e");
+} catch (InvalidArgumentException $e) {
+	$a = "invalid";
+} catch (Exception $e2) {
+	echo $e2;
+} finally {
+	echo "done";
+}
+system($a);
+`)
+	system := phpCallArgs(prog, "system")
+	require.Len(t, system, 1)
+	assert.True(t, system[0].IsPhi())
+	defs := phpConstDefs(system[0])
+	assert.Contains(t, defs, "init")
+	assert.Contains(t, defs, "invalid")
+
+	// every catch shares the same error value
+	e, e2 := prog.Ref("$e"), prog.Ref("$e2")
+	require.NotEmpty(t, e)
+	require.NotEmpty(t, e2)
+	assert.Equal(t, e[0].GetId(), e2[0].GetId())
+
+	echo := phpCallArgs(prog, "echo")
+	require.Len(t, echo, 2)
+	assert.Equal(t, "done", echo[1].GetConstValue(), "finally block should be built")
+}
+
+func TestPHP_Switch(t *testing.T) {
+	prog := parsePHP(t, `<?php
+$s = "0";
+switch ($_GET["s"]) {
+case "a": $s = "1"; break;
+case "b":
+case "c": $s = "2"; break;
+default: $s = "3";
+}
+echo $s;
+`)
+	echo := phpCallArgs(prog, "echo")
+	require.Len(t, echo, 1)
+	defs := phpConstDefs(echo[0])
+	assert.Contains(t, defs, "1")
+	assert.Contains(t, defs, "2")
+	assert.Contains(t, defs, "3", "default after case should be built")
+}
+
+func TestPHP_IncludeOnce(t *testing.T) {
+	prog, err := ParseProject(fstest.MapFS{
+		"index.php": {Data: []byte("<?php\ninclude_once \"lib.php\";\ninclude_once \"lib.php\";\nrequire_once \"./lib.php\";\ninclude \"twice.php\";\ninclude \"twice.php\";\n")},
+		"lib.php":   {Data: []byte("<?php\nsystem(\"from-lib\");\n")},
+		"twice.php": {Data: []byte("<?php\nsystem(\"from-twice\");\n")},
+	}, WithLanguage(PHP))
+	require.NoError(t, err)
+
+	var consts []any
+	for _, arg := range phpCallArgs(prog, "system") {
+		consts = append(consts, arg.GetConstValue())
+	}
+	// include_once / require_once compile the file only once, include compiles it every time
+	assert.ElementsMatch(t, []any{"from-lib", "from-twice", "from-twice"}, consts)
+
+	include := phpCallArgs(prog, "include_once")
+	assert.Len(t, include, 2, "include_once call is kept for analysis")
+}
@@ -0,0 +1,247 @@
+package ssaapi
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/memfile"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
+
+// LanguageFileExtensions 项目编译时，根据扩展名选择需要编译的文件
+var LanguageFileExtensions = map[Language][]string{
+	Yak: {".yak"},
+	JS:  {".js"},
+	PHP: {".php", ".phtml", ".inc"},
+}
+
+// includeScanner 由支持文件包含的语言实现，返回文件中通过 include 等语句包含的文件
+type includeScanner interface {
+	Includes(src string, file string, fsys fs.FS) []string
+}
+
+// ParseProject 编译 fsys 中所有属于当前语言的文件到同一个 Program 中
+// 文件按照路径顺序依次编译，后编译的文件可以使用前面文件中定义的符号，
+// include / require 等语句会从 fsys 中读取文件，每个 Value 的 Range 中记录了所在的文件，
+// 被其他文件包含的文件只在包含的位置编译，不会再单独编译一次
+func ParseProject(fsys fs.FS, opts ...Option) (*Program, error) {
+	project, err := newProjectSources(fsys, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return project.parse(), nil
+}
+
+// ParseProjectIrProgram 与 ParseProject 相同，但是需要通过 WithDatabase 设置数据库，
+// 数据库中已经保存了相同源文件与配置的程序时直接加载，不会重新编译，返回的 IrProgram 在查询时按函数加载指令
+func ParseProjectIrProgram(fsys fs.FS, opts ...Option) (*IrProgram, error) {
+	project, err := newProjectSources(fsys, opts...)
+	if err != nil {
+		return nil, err
+	}
+	db := project.config.db
+	if db == nil {
+		return nil, utils.Errorf("database is not set")
+	}
+	hash := project.config.CaclHash()
+	if ir, ok := loadCachedIrProgram(db, hash); ok {
+		return ir, nil
+	}
+	project.parse()
+	return LoadIrProgram(db, hash)
+}
+
+// projectSources 是项目中需要编译的源文件
+type projectSources struct {
+	config  *config
+	fsys    fs.FS
+	files   []string
+	sources map[string][]byte
+}
+
+// newProjectSources 读取项目中的源文件，计算文件 hash，并清除同名程序中已经发生变化的源文件对应的 IR
+func newProjectSources(fsys fs.FS, opts ...Option) (*projectSources, error) {
+	config := defaultConfig("")
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.Parser == nil {
+		return nil, utils.Errorf("not support language %s", config.language)
+	}
+
+	files, err := walkProjectFiles(fsys, LanguageFileExtensions[config.language])
+	if err != nil {
+		return nil, utils.Wrapf(err, "walk project files")
+	}
+	if len(files) == 0 {
+		return nil, utils.Errorf("no %s file found in project", config.language)
+	}
+
+	sources := make(map[string][]byte, len(files))
+	config.sourceFiles = make(map[string]string, len(files))
+	for _, file := range files {
+		raw, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			log.Warnf("read project file %s failed: %v", file, err)
+			continue
+		}
+		sources[file] = raw
+		config.sourceFiles[file] = utils.CalcSha1(raw)
+	}
+	if config.db != nil && config.programName != "" {
+		if err := invalidateChangedSourceFiles(config.db, config.programName, config.sourceFiles); err != nil {
+			log.Errorf("invalidate ssa program %s failed: %v", config.programName, err)
+		}
+	}
+	return &projectSources{config: config, fsys: fsys, files: files, sources: sources}, nil
+}
+
+func (p *projectSources) parse() *Program {
+	config := p.config
+	prog := ssa.NewProgram()
+	prog.SetFileSystem(p.fsys)
+	config.initBuilder(prog.GetAndCreateMainFunctionBuilder())
+	for _, file := range projectEntryFiles(config.Parser, p.fsys, p.files, p.sources) {
+		prog.SetCurrentFile(file)
+		config.Parser.Feed(string(p.sources[file]), config.ignoreSyntaxErr, prog)
+	}
+	prog.SetCurrentFile("")
+
+	ret := NewProgram(prog)
+	ret.AddConfig(config)
+	ret.saveToConfigDatabase()
+	return ret
+}
+
+// ParseProjectFromDir 编译本地目录中的项目，没有设置程序名的时候使用目录的绝对路径作为程序名
+func ParseProjectFromDir(dir string, opts ...Option) (*Program, error) {
+	opts, err := projectDirOptions(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProject(os.DirFS(dir), opts...)
+}
+
+// ParseProjectIrProgramFromDir 与 ParseProjectFromDir 相同，返回保存在数据库中的程序
+func ParseProjectIrProgramFromDir(dir string, opts ...Option) (*IrProgram, error) {
+	opts, err := projectDirOptions(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProjectIrProgram(os.DirFS(dir), opts...)
+}
+
+func projectDirOptions(dir string, opts []Option) ([]Option, error) {
+	if !utils.IsDir(dir) {
+		return nil, utils.Errorf("%s is not a directory", dir)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		opts = append([]Option{WithProgramName(abs)}, opts...)
+	}
+	return opts, nil
+}
+
+// ParseProjectFromZip 编译 zip 压缩包中的项目
+func ParseProjectFromZip(raw []byte, opts ...Option) (*Program, error) {
+	reader, err := zip.NewReader(memfile.New(raw), int64(len(raw)))
+	if err != nil {
+		return nil, utils.Wrapf(err, "open zip failed")
+	}
+	return ParseProject(reader, opts...)
+}
+
+// projectEntryFiles 返回需要单独编译的文件，被其他文件包含的文件会在包含的位置编译，
+// 循环包含时按照路径顺序选择第一个文件作为入口
+func projectEntryFiles(parser LanguageParser, fsys fs.FS, files []string, sources map[string][]byte) []string {
+	var entries []string
+	scanner, ok := parser.(includeScanner)
+	if !ok {
+		for _, file := range files {
+			if _, ok := sources[file]; ok {
+				entries = append(entries, file)
+			}
+		}
+		return entries
+	}
+
+	includes := make(map[string][]string, len(sources))
+	included := make(map[string]struct{})
+	for _, file := range files {
+		raw, ok := sources[file]
+		if !ok {
+			continue
+		}
+		for _, name := range scanner.Includes(string(raw), file, fsys) {
+			if name == file {
+				continue
+			}
+			includes[file] = append(includes[file], name)
+			included[name] = struct{}{}
+		}
+	}
+
+	covered := make(map[string]struct{})
+	var cover func(file string)
+	cover = func(file string) {
+		if _, ok := covered[file]; ok {
+			return
+		}
+		covered[file] = struct{}{}
+		for _, name := range includes[file] {
+			cover(name)
+		}
+	}
+	for _, file := range files {
+		if _, ok := sources[file]; !ok {
+			continue
+		}
+		if _, ok := included[file]; !ok {
+			cover(file)
+		}
+	}
+	for _, file := range files {
+		if _, ok := sources[file]; !ok {
+			continue
+		}
+		if _, ok := included[file]; !ok {
+			entries = append(entries, file)
+			continue
+		}
+		if _, ok := covered[file]; !ok {
+			entries = append(entries, file)
+			cover(file)
+		}
+	}
+	return entries
+}
+
+func walkProjectFiles(fsys fs.FS, exts []string) ([]string, error) {
+	var files []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// skip .git / .idea ...
+			if name != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(path.Ext(name))
+		for _, e := range exts {
+			if ext == e {
+				files = append(files, name)
+				break
+			}
+		}
+		return nil
+	})
+	return files, err
+}
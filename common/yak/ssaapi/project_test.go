@@ -0,0 +1,117 @@
+package ssaapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProject_Yak(t *testing.T) {
+	fsys := fstest.MapFS{
+		"lib/a.yak":  {Data: []byte("getCmd = () => {\n\treturn \"whoami\"\n}\n")},
+		"main.yak":   {Data: []byte("cmd = getCmd()\nprintln(cmd)\n")},
+		"readme.txt": {Data: []byte("not source")},
+	}
+	prog, err := ParseProject(fsys, WithLanguage(Yak))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog.Show()
+
+	getCmd := prog.Ref("getCmd")
+	assert.NotEmpty(t, getCmd)
+	assert.True(t, getCmd[0].IsFunction())
+	if r := getCmd[0].GetRange(); assert.NotNil(t, r) {
+		assert.Equal(t, "lib/a.yak", r.FileName)
+	}
+
+	cmd := prog.Ref("cmd")
+	assert.NotEmpty(t, cmd)
+	if r := cmd[0].GetRange(); assert.NotNil(t, r) {
+		assert.Equal(t, "main.yak", r.FileName)
+		assert.Equal(t, int64(1), r.Start.Line)
+	}
+	// cross file call
+	assert.True(t, cmd[0].IsCall())
+	assert.True(t, cmd[0].GetCallee().IsFunction())
+}
+
+func TestParseProject_PHPInclude(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.php":     {Data: []byte("<?php\ninclude \"inc/lib.php\";\n$a = run($_GET['c']);\n")},
+		"inc/lib.php":   {Data: []byte("<?php\nrequire_once 'util.php';\nfunction run($c) { return do_exec($c); }\n")},
+		"inc/util.php":  {Data: []byte("<?php\nfunction do_exec($c) {\n\treturn system($c);\n}\n")},
+		".git/hook.php": {Data: []byte("<?php\n$hidden = 1;\n")},
+	}
+	prog, err := ParseProject(fsys, WithLanguage(PHP))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := prog.Ref("run")
+	assert.NotEmpty(t, run)
+	assert.True(t, run[0].IsFunction())
+
+	doExec := prog.Ref("do_exec")
+	assert.NotEmpty(t, doExec)
+	if r := doExec[0].GetRange(); assert.NotNil(t, r) {
+		assert.Equal(t, "inc/util.php", r.FileName)
+		assert.Equal(t, int64(2), r.Start.Line)
+	}
+
+	a := prog.Ref("$a")
+	assert.NotEmpty(t, a)
+	assert.Equal(t, "index.php", a[0].GetRange().FileName)
+	assert.Empty(t, prog.Ref("$hidden"))
+}
+
+func TestParseProject_PHPIncludeNotCompiledTwice(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a_config.php": {Data: []byte("<?php\n$cmd = $_GET['c'];\n")},
+		"index.php":    {Data: []byte("<?php\ninclude 'a_config.php';\nsystem($cmd);\n")},
+		// 循环包含时选择第一个文件作为入口
+		"loop/a.php": {Data: []byte("<?php\ninclude_once 'b.php';\n$la = 1;\n")},
+		"loop/b.php": {Data: []byte("<?php\ninclude_once 'a.php';\n$lb = 2;\n")},
+	}
+	prog, err := ParseProject(fsys, WithLanguage(PHP))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, prog.Ref("$cmd"), 1)
+	assert.Len(t, prog.Ref("system").GetUsers(), 1)
+	assert.Len(t, prog.Ref("$la"), 1)
+	assert.Len(t, prog.Ref("$lb"), 1)
+}
+
+func TestParseProjectFromZip(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, code := range map[string]string{
+		"src/a.js": "function a() { return 1 }",
+		"src/b.js": "var b = a()",
+	} {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(code))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	prog, err := ParseProjectFromZip(buf.Bytes(), WithLanguage(JS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := prog.Ref("b")
+	assert.NotEmpty(t, b)
+	assert.Equal(t, "src/b.js", b[0].GetRange().FileName)
+
+	_, err = ParseProject(fstest.MapFS{"a.txt": {Data: []byte("")}}, WithLanguage(JS))
+	assert.Error(t, err)
+}
@@ -3,8 +3,11 @@ package ssaapi
 import (
 	"time"
 
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/consts"
 	"github.com/yaklang/yaklang/common/utils"
 	js2ssa "github.com/yaklang/yaklang/common/yak/JS2ssa"
+	"github.com/yaklang/yaklang/common/yak/php/php2ssa"
 	"github.com/yaklang/yaklang/common/yak/ssa"
 	"github.com/yaklang/yaklang/common/yak/yak2ssa"
 )
@@ -14,6 +17,7 @@ type Language string
 const (
 	JS  Language = "js"
 	Yak Language = "yak"
+	PHP Language = "php"
 )
 
 type LanguageParser interface {
@@ -25,6 +29,7 @@ var (
 	LanguageParsers = map[Language]LanguageParser{
 		Yak: yak2ssa.NewParser(),
 		JS:  js2ssa.NewParser(),
+		PHP: php2ssa.NewParser(),
 	}
 )
 
@@ -41,6 +46,12 @@ type config struct {
 	externMethod ssa.MethodBuilder
 	// for hash
 	externInfo string
+	// project source files and content hash, for hash and invalidation
+	sourceFiles map[string]string
+
+	// save compiled program to database
+	db          *gorm.DB
+	programName string
 }
 
 func defaultConfig(code string) *config {
@@ -55,7 +66,7 @@ func defaultConfig(code string) *config {
 }
 
 func (c *config) CaclHash() string {
-	return utils.CalcSha1(c.code, c.language, c.ignoreSyntaxErr, c.externInfo)
+	return utils.CalcSha1(c.code, c.language, c.ignoreSyntaxErr, c.externInfo, c.sourceFiles)
 }
 
 type Option func(*config)
@@ -130,6 +141,27 @@ func WithFeedCode(b ...bool) Option {
 	}
 }
 
+// WithDatabase 编译完成后把 SSA IR 保存到数据库中，可以通过 LoadIrProgram 按需加载，
+// ParseIrProgram / ParseProjectIrProgram 会优先使用数据库中相同 hash 的程序
+func WithDatabase(db *gorm.DB) Option {
+	return func(c *config) {
+		c.db = db
+	}
+}
+
+// WithProjectDatabase 使用 yakit 的项目数据库保存 SSA IR
+func WithProjectDatabase() Option {
+	return WithDatabase(consts.GetGormProjectDatabase())
+}
+
+// WithProgramName 设置程序名，同名程序再次编译时，旧程序中发生变化的源文件对应的 IR 会被清除并标记为过期，
+// 新的源文件会重新编译整个程序
+func WithProgramName(name string) Option {
+	return func(c *config) {
+		c.programName = name
+	}
+}
+
 var ttlSSAParseCache = utils.NewTTLCache[*Program](30 * time.Minute)
 
 func Parse(code string, opts ...Option) (*Program, error) {
@@ -152,19 +184,21 @@ func Parse(code string, opts ...Option) (*Program, error) {
 		}
 		ret = NewProgram(prog)
 		ret.AddConfig(config)
+		ret.saveToConfigDatabase()
 	}
 	ttlSSAParseCache.SetWithTTL(hash, ret, 30*time.Minute)
 	return ret, nil
 }
 
+func (c *config) initBuilder(fb *ssa.FunctionBuilder) {
+	fb.WithExternLib(c.externLib)
+	fb.WithExternValue(c.externValue)
+	fb.WithExternMethod(c.externMethod)
+	fb.WithDefineFunction(c.defineFunc)
+}
+
 func parseWithConfig(c *config) (*ssa.Program, error) {
-	callback := func(fb *ssa.FunctionBuilder) {
-		fb.WithExternLib(c.externLib)
-		fb.WithExternValue(c.externValue)
-		fb.WithExternMethod(c.externMethod)
-		fb.WithDefineFunction(c.defineFunc)
-	}
-	return c.Parser.Parse(c.code, c.ignoreSyntaxErr, callback)
+	return c.Parser.Parse(c.code, c.ignoreSyntaxErr, c.initBuilder)
 }
 
 func (p *Program) Feed(code string) {
@@ -175,12 +209,25 @@ func (p *Program) Feed(code string) {
 }
 
 var Exports = map[string]any{
-	"Parse": Parse,
-
-	"withLanguage":    WithLanguage,
-	"withExternLib":   WithExternLib,
-	"withExternValue": WithExternValue,
+	"Parse":               Parse,
+	"ParseProjectFromDir": ParseProjectFromDir,
+	"ParseProjectFromZip": ParseProjectFromZip,
+	"LoadIrProgram":       LoadIrProgramFromProjectDatabase,
+
+	"ParseIrProgram":               ParseIrProgram,
+	"ParseProjectIrProgramFromDir": ParseProjectIrProgramFromDir,
+
+	// taint
+	"ParseTaintRule":       ParseTaintRule,
+	"GetBuiltinTaintRules": GetBuiltinTaintRules,
+
+	"withLanguage":        WithLanguage,
+	"withExternLib":       WithExternLib,
+	"withExternValue":     WithExternValue,
+	"withProgramName":     WithProgramName,
+	"withProjectDatabase": WithProjectDatabase,
 	// language:
 	"Javascript": JS,
 	"Yak":        Yak,
+	"PHP":        PHP,
 }
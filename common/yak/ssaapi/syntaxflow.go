@@ -0,0 +1,125 @@
+package ssaapi
+
+import (
+	"github.com/yaklang/yaklang/common/syntaxflow/sfvm"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/omap"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
+
+// syntaxFlowValue 把 Value 包装为 sfvm.ValueOperator，用于在 SyntaxFlow 中查询
+type syntaxFlowValue struct {
+	*Value
+}
+
+var _ sfvm.ValueOperator = (*syntaxFlowValue)(nil)
+
+func newSyntaxFlowValues(vs Values) []sfvm.ValueOperator {
+	ret := make([]sfvm.ValueOperator, 0, len(vs))
+	for _, v := range vs {
+		if v == nil || utils.IsNil(v.node) {
+			continue
+		}
+		ret = append(ret, &syntaxFlowValue{Value: v})
+	}
+	return ret
+}
+
+func (v *syntaxFlowValue) GetFields(key string) []sfvm.ValueOperator {
+	obj, ok := v.node.(ssa.Value)
+	if !ok || !obj.IsObject() {
+		return nil
+	}
+	var ret Values
+	for k, member := range obj.GetAllMember() {
+		if c, ok := ssa.ToConst(k); ok && c.VarString() == key {
+			ret = append(ret, NewValue(member))
+		}
+	}
+	return newSyntaxFlowValues(ret)
+}
+
+func (v *syntaxFlowValue) GetCalled() []sfvm.ValueOperator {
+	return newSyntaxFlowValues(v.GetCalledBy().Filter(func(call *Value) bool {
+		return call.GetCallee().Compare(v.Value)
+	}))
+}
+
+func (v *syntaxFlowValue) GetCallActualParams(index int) []sfvm.ValueOperator {
+	args := v.GetCallArgs()
+	if index < 0 || index >= len(args) {
+		return nil
+	}
+	return newSyntaxFlowValues(args[index : index+1])
+}
+
+func (v *syntaxFlowValue) GetFunctionParams(index int) []sfvm.ValueOperator {
+	param := v.GetParameter(index)
+	if param == nil {
+		return nil
+	}
+	return newSyntaxFlowValues(Values{param})
+}
+
+// GetCallReturns 调用返回调用结果被使用的地方，函数返回 return 语句中的值
+func (v *syntaxFlowValue) GetCallReturns() []sfvm.ValueOperator {
+	if v.IsCall() {
+		return newSyntaxFlowValues(v.Value.GetCallReturns())
+	}
+	f, ok := ssa.ToFunction(v.node)
+	if !ok {
+		return nil
+	}
+	var ret Values
+	for _, r := range f.Return {
+		for _, result := range r.Results {
+			ret = append(ret, NewValue(result))
+		}
+	}
+	return newSyntaxFlowValues(ret)
+}
+
+// syntaxFlowRoot 返回 SyntaxFlow 查询的起点：变量名 => 变量对应的值
+func (p *Program) syntaxFlowRoot() *omap.OrderedMap[string, any] {
+	root := omap.NewGeneralOrderedMap()
+	for name, vs := range p.GetAllSymbols() {
+		ops := newSyntaxFlowValues(vs)
+		if len(ops) == 0 {
+			continue
+		}
+		list := make([]any, 0, len(ops))
+		for _, op := range ops {
+			list = append(list, op)
+		}
+		root.Set(name, list)
+	}
+	return root
+}
+
+// SyntaxFlow 使用 SyntaxFlow 规则查询程序，返回规则中每个变量（如 `exec.System(#0) => $cmd` 中的 cmd）对应的值
+//
+//	f()        调用 f 的所有调用
+//	f(#0)      调用 f 时的第 0 个实参
+//	f#0        函数 f 的第 0 个形参
+//	f#>        函数 f 的返回值，对调用而言是调用结果被使用的地方
+func (p *Program) SyntaxFlow(rule string) (map[string]Values, error) {
+	if p.IsNil() {
+		return nil, utils.Errorf("program is nil")
+	}
+	vm := sfvm.NewSyntaxFlowVirtualMachine()
+	if err := vm.Compile(rule); err != nil {
+		return nil, err
+	}
+	results := make(map[string]Values)
+	vm.Feed(p.syntaxFlowRoot()).ForEach(func(name string, v any) bool {
+		var vs Values
+		for _, op := range sfvm.ValueOperators(v) {
+			if sv, ok := op.(*syntaxFlowValue); ok {
+				vs = append(vs, sv.Value)
+			}
+		}
+		results[name] = vs
+		return true
+	})
+	return results, nil
+}
@@ -0,0 +1,124 @@
+package ssaapi
+
+import (
+	"encoding/json"
+
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+// SyntaxFlowReportItem 是 JSON 报告中的一条结果
+type SyntaxFlowReportItem struct {
+	Rule        string `json:"rule"`
+	Title       string `json:"title"`
+	CWE         string `json:"cwe,omitempty"`
+	Severity    string `json:"severity"`
+	Description string `json:"description,omitempty"`
+	Fix         string `json:"fix,omitempty"`
+	Variable    string `json:"variable"`
+	FileName    string `json:"file_name,omitempty"`
+	StartLine   int64  `json:"start_line"`
+	StartColumn int64  `json:"start_column"`
+	EndLine     int64  `json:"end_line"`
+	EndColumn   int64  `json:"end_column"`
+	Code        string `json:"code"`
+	IR          string `json:"ir"`
+}
+
+func (r *SyntaxFlowResult) ReportItem() *SyntaxFlowReportItem {
+	rule := r.Rule
+	item := &SyntaxFlowReportItem{
+		Rule:        rule.Name,
+		Title:       rule.Title,
+		CWE:         rule.CWE,
+		Severity:    rule.Severity,
+		Description: rule.Description,
+		Fix:         rule.Fix,
+		Variable:    r.Variable,
+		IR:          r.Value.String(),
+	}
+	if rng := r.Value.GetRange(); rng != nil {
+		item.FileName = rng.FileName
+		if rng.Start != nil {
+			item.StartLine, item.StartColumn = rng.Start.Line, rng.Start.Column
+		}
+		if rng.End != nil {
+			item.EndLine, item.EndColumn = rng.End.Line, rng.End.Column
+		}
+		if rng.SourceCode != nil {
+			item.Code = *rng.SourceCode
+		}
+	}
+	return item
+}
+
+// SyntaxFlowResultsToJSON 把结果输出为 JSON 数组
+func SyntaxFlowResultsToJSON(results []*SyntaxFlowResult) ([]byte, error) {
+	items := make([]*SyntaxFlowReportItem, 0, len(results))
+	for _, r := range results {
+		items = append(items, r.ReportItem())
+	}
+	return json.MarshalIndent(items, "", "  ")
+}
+
+// SyntaxFlowResultsToSARIF 把结果输出为 SARIF 2.1.0 格式（与漏洞导出共用 yakit 中的 SARIF 结构），rules 中所有的规则都会出现在 driver.rules 中
+func SyntaxFlowResultsToSARIF(rules []*SyntaxFlowRule, results []*SyntaxFlowResult) ([]byte, error) {
+	driver := &yakit.SARIFDriver{
+		Name:           "syntaxflow",
+		InformationUri: "https://yaklang.io",
+	}
+	ruleIndex := make(map[string]int)
+	addRule := func(rule *SyntaxFlowRule) {
+		if _, ok := ruleIndex[rule.Name]; ok {
+			return
+		}
+		properties := map[string]any{"severity": rule.Severity}
+		if rule.CWE != "" {
+			properties["tags"] = []string{rule.CWE}
+		}
+		ruleIndex[rule.Name] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, &yakit.SARIFRule{
+			Id:               rule.Name,
+			Name:             rule.Title,
+			ShortDescription: &yakit.SARIFMessage{Text: rule.Title},
+			FullDescription:  &yakit.SARIFMessage{Text: rule.Description},
+			Help:             &yakit.SARIFMessage{Text: rule.Fix},
+			Properties:       properties,
+		})
+	}
+	for _, rule := range rules {
+		addRule(rule)
+	}
+
+	run := &yakit.SARIFRun{Tool: &yakit.SARIFTool{Driver: driver}, Results: make([]*yakit.SARIFResult, 0, len(results))}
+	for _, r := range results {
+		addRule(r.Rule)
+		item := r.ReportItem()
+		// SARIF 中的列从 1 开始
+		region := &yakit.SARIFRegion{
+			StartLine:   item.StartLine,
+			StartColumn: item.StartColumn + 1,
+			EndLine:     item.EndLine,
+			EndColumn:   item.EndColumn + 1,
+		}
+		if item.Code != "" {
+			region.Snippet = &yakit.SARIFMessage{Text: item.Code}
+		}
+		run.Results = append(run.Results, &yakit.SARIFResult{
+			RuleId:    r.Rule.Name,
+			RuleIndex: ruleIndex[r.Rule.Name],
+			Level:     yakit.SARIFLevel(r.Rule.Severity),
+			Message:   &yakit.SARIFMessage{Text: r.Rule.Title + ": " + item.Code},
+			Locations: []*yakit.SARIFLocation{{
+				PhysicalLocation: &yakit.SARIFPhysicalLocation{
+					ArtifactLocation: &yakit.SARIFArtifactLocation{Uri: item.FileName},
+					Region:           region,
+				},
+			}},
+		})
+	}
+	return json.MarshalIndent(&yakit.SARIFLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []*yakit.SARIFRun{run},
+	}, "", "  ")
+}
@@ -0,0 +1,259 @@
+package ssaapi
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/syntaxflow/sfvm"
+	"github.com/yaklang/yaklang/common/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// SyntaxFlowRule 是带有元信息的 SyntaxFlow 规则，规则中 alert 指定的变量有值时报告漏洞
+//
+//	name: yak-unsafe-command-exec
+//	title: Yak 命令执行函数使用非常量参数
+//	cwe: CWE-78
+//	severity: high
+//	language: yak
+//	description: 外部输入直接进入命令执行函数
+//	fix: 对参数进行白名单校验
+//	rule: os.System(#0) => $sink
+//	alert: [sink]
+//	ignore-const: true
+//	tests:
+//	  positive:
+//	    - os.System(cli.String("cmd"))
+//	  negative:
+//	    - os.System("ls")
+type SyntaxFlowRule struct {
+	Name        string `yaml:"name" json:"name"`
+	Title       string `yaml:"title" json:"title"`
+	CWE         string `yaml:"cwe" json:"cwe"`
+	Severity    string `yaml:"severity" json:"severity"`
+	Description string `yaml:"description" json:"description"`
+	Fix         string `yaml:"fix" json:"fix"`
+	// 规则适用的语言，为空表示所有语言
+	Language Language `yaml:"language" json:"language"`
+
+	Rule string `yaml:"rule" json:"rule"`
+	// 需要报告的变量，为空表示报告规则中所有的变量
+	Alert []string `yaml:"alert" json:"alert"`
+	// 值的所有来源（top defs）都是常量时不报告，如 os.System("ls")
+	IgnoreConst bool `yaml:"ignore-const" json:"ignore_const"`
+
+	Tests SyntaxFlowRuleTests `yaml:"tests" json:"tests"`
+}
+
+// SyntaxFlowRuleTests 是规则的自测样例，positive 中的代码必须命中，negative 中的代码不能命中
+type SyntaxFlowRuleTests struct {
+	Positive []string `yaml:"positive" json:"positive"`
+	Negative []string `yaml:"negative" json:"negative"`
+}
+
+// SyntaxFlowResult 是规则在程序中的一个命中
+type SyntaxFlowResult struct {
+	Rule     *SyntaxFlowRule
+	Variable string
+	Value    *Value
+}
+
+// ParseSyntaxFlowRule 解析 YAML 格式的 SyntaxFlow 规则，并检查规则语法
+func ParseSyntaxFlowRule(raw string) (*SyntaxFlowRule, error) {
+	var rule SyntaxFlowRule
+	if err := yaml.Unmarshal([]byte(raw), &rule); err != nil {
+		return nil, utils.Wrapf(err, "parse syntaxflow rule failed")
+	}
+	if rule.Name == "" {
+		return nil, utils.Errorf("syntaxflow rule name is empty")
+	}
+	if strings.TrimSpace(rule.Rule) == "" {
+		return nil, utils.Errorf("syntaxflow rule %s: rule is empty", rule.Name)
+	}
+	if rule.Title == "" {
+		rule.Title = rule.Name
+	}
+	if err := sfvm.NewSyntaxFlowVirtualMachine().Compile(rule.Rule); err != nil {
+		return nil, utils.Wrapf(err, "syntaxflow rule %s", rule.Name)
+	}
+	return &rule, nil
+}
+
+// LoadSyntaxFlowRules 从文件或目录（目录中所有的 .yaml / .yml 文件）中加载规则
+func LoadSyntaxFlowRules(p string) ([]*SyntaxFlowRule, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{p}
+	if info.IsDir() {
+		files = nil
+		err := filepath.Walk(p, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := filepath.Ext(file); !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var rules []*SyntaxFlowRule
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := ParseSyntaxFlowRule(string(raw))
+		if err != nil {
+			return nil, utils.Wrapf(err, "load %s failed", file)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+//go:embed syntaxflow_rules/*.yaml
+var builtinSyntaxFlowRules embed.FS
+
+// GetBuiltinSyntaxFlowRules 返回内置的 SyntaxFlow 规则
+func GetBuiltinSyntaxFlowRules() []*SyntaxFlowRule {
+	entries, err := builtinSyntaxFlowRules.ReadDir("syntaxflow_rules")
+	if err != nil {
+		log.Errorf("read builtin syntaxflow rules failed: %v", err)
+		return nil
+	}
+	var rules []*SyntaxFlowRule
+	for _, entry := range entries {
+		raw, err := builtinSyntaxFlowRules.ReadFile(path.Join("syntaxflow_rules", entry.Name()))
+		if err != nil {
+			log.Errorf("read builtin syntaxflow rule %s failed: %v", entry.Name(), err)
+			continue
+		}
+		rule, err := ParseSyntaxFlowRule(string(raw))
+		if err != nil {
+			log.Errorf("parse builtin syntaxflow rule %s failed: %v", entry.Name(), err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (r *SyntaxFlowRule) alert(name string) bool {
+	return len(r.Alert) == 0 || utils.StringArrayContains(r.Alert, name)
+}
+
+// RunSyntaxFlowRule 在程序上运行规则，返回所有需要报告的值
+func (p *Program) RunSyntaxFlowRule(rule *SyntaxFlowRule) ([]*SyntaxFlowResult, error) {
+	vars, err := p.SyntaxFlow(rule.Rule)
+	if err != nil {
+		return nil, utils.Wrapf(err, "run syntaxflow rule %s", rule.Name)
+	}
+	names := lo.Keys(vars)
+	sort.Strings(names)
+	var results []*SyntaxFlowResult
+	for _, name := range names {
+		if !rule.alert(name) {
+			continue
+		}
+		for _, v := range vars[name] {
+			if rule.IgnoreConst && isConstOnly(v) {
+				continue
+			}
+			results = append(results, &SyntaxFlowResult{Rule: rule, Variable: name, Value: v})
+		}
+	}
+	return results, nil
+}
+
+// RunSyntaxFlowRules 运行所有适用于当前语言的规则
+func (p *Program) RunSyntaxFlowRules(rules ...*SyntaxFlowRule) []*SyntaxFlowResult {
+	var results []*SyntaxFlowResult
+	for _, rule := range rules {
+		if rule.Language != "" && p.config != nil && rule.Language != p.config.language {
+			continue
+		}
+		ret, err := p.RunSyntaxFlowRule(rule)
+		if err != nil {
+			log.Errorf("%v", err)
+			continue
+		}
+		results = append(results, ret...)
+	}
+	return results
+}
+
+// SelfTest 使用规则中的样例代码测试规则
+func (r *SyntaxFlowRule) SelfTest() error {
+	if len(r.Tests.Positive) == 0 && len(r.Tests.Negative) == 0 {
+		return utils.Errorf("syntaxflow rule %s: no test case", r.Name)
+	}
+	run := func(code string) (int, error) {
+		var opts []Option
+		if r.Language != "" {
+			opts = append(opts, WithLanguage(r.Language))
+		}
+		prog, err := Parse(code, opts...)
+		if err != nil {
+			return 0, utils.Wrapf(err, "syntaxflow rule %s: compile test code failed", r.Name)
+		}
+		results, err := prog.RunSyntaxFlowRule(r)
+		return len(results), err
+	}
+	for i, code := range r.Tests.Positive {
+		n, err := run(code)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return utils.Errorf("syntaxflow rule %s: positive test #%d not matched", r.Name, i)
+		}
+	}
+	for i, code := range r.Tests.Negative {
+		n, err := run(code)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return utils.Errorf("syntaxflow rule %s: negative test #%d matched %d values", r.Name, i, n)
+		}
+	}
+	return nil
+}
+
+// isConstOnly 值的所有来源都是常量
+func isConstOnly(v *Value) bool {
+	if v.IsConstInst() {
+		return true
+	}
+	defs := v.GetTopDefs()
+	if len(defs) == 0 {
+		return false
+	}
+	for _, def := range defs {
+		if !def.IsConstInst() {
+			return false
+		}
+	}
+	return true
+}
+
+// Location 返回命中的位置，如 index.php:3:1
+func (r *SyntaxFlowResult) Location() string {
+	return formatRangeLocation(r.Value.GetRange())
+}
+
+func (r *SyntaxFlowResult) String() string {
+	return fmt.Sprintf("[%s] %s $%s: %s", r.Rule.Name, r.Location(), r.Variable, r.Value.String())
+}
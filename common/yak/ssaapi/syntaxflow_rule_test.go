@@ -0,0 +1,95 @@
+package ssaapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+func TestSyntaxFlowRule_Builtin(t *testing.T) {
+	rules := GetBuiltinSyntaxFlowRules()
+	require.NotEmpty(t, rules)
+	for _, rule := range rules {
+		assert.NoError(t, rule.SelfTest(), rule.Name)
+	}
+}
+
+// 内置的 SyntaxFlow 规则与污点规则的结果都使用规则名作为规则 ID，不能重名
+func TestSyntaxFlowRule_BuiltinNameNotConflictWithTaintRule(t *testing.T) {
+	names := make(map[string]struct{})
+	for _, rule := range GetBuiltinTaintRules() {
+		names[rule.Name] = struct{}{}
+	}
+	for _, rule := range GetBuiltinSyntaxFlowRules() {
+		assert.NotContains(t, names, rule.Name)
+	}
+}
+
+func TestSyntaxFlowRule_Parse(t *testing.T) {
+	_, err := ParseSyntaxFlowRule("name: bad\nrule: os.System(#\n")
+	assert.Error(t, err)
+
+	_, err = ParseSyntaxFlowRule("rule: os.System(#0) => $a\n")
+	assert.Error(t, err)
+
+	rule, err := ParseSyntaxFlowRule(`
+name: test
+severity: high
+rule: |
+  os.System(#0) => $a
+  exec.System(#0) => $b
+alert: [b]
+ignore-const: true
+tests:
+  positive:
+    - exec.System(cli.String("cmd"))
+  negative:
+    - os.System(cli.String("cmd"))
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "test", rule.Title)
+	assert.NoError(t, rule.SelfTest())
+}
+
+func TestSyntaxFlowRule_Report(t *testing.T) {
+	rule, err := ParseSyntaxFlowRule(`
+name: test
+title: Test
+cwe: CWE-78
+severity: high
+rule: exec.System(#0) => $cmd
+ignore-const: true
+`)
+	require.NoError(t, err)
+	prog, err := Parse(`
+exec.System("ls")
+exec.System(cli.String("cmd"))
+`)
+	require.NoError(t, err)
+	results := prog.RunSyntaxFlowRules(rule)
+	require.Len(t, results, 1)
+	assert.Equal(t, "cmd", results[0].Variable)
+	assert.True(t, strings.HasPrefix(results[0].Location(), "3:"), results[0].Location())
+
+	raw, err := SyntaxFlowResultsToJSON(results)
+	require.NoError(t, err)
+	var items []*SyntaxFlowReportItem
+	require.NoError(t, json.Unmarshal(raw, &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "CWE-78", items[0].CWE)
+	assert.Equal(t, int64(3), items[0].StartLine)
+
+	raw, err = SyntaxFlowResultsToSARIF([]*SyntaxFlowRule{rule}, results)
+	require.NoError(t, err)
+	var log yakit.SARIFLog
+	require.NoError(t, json.Unmarshal(raw, &log))
+	require.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 1)
+	require.Len(t, log.Runs[0].Results, 1)
+	assert.Equal(t, "error", log.Runs[0].Results[0].Level)
+	assert.Equal(t, "test", log.Runs[0].Results[0].RuleId)
+}
@@ -0,0 +1,58 @@
+package ssaapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxFlow_CallAndParams(t *testing.T) {
+	prog, err := Parse(`
+f = (a, b) => {
+	c = a + b
+	return c
+}
+d = f(1, 2)
+println(d)
+cmd = cli.String("cmd")
+os.System("ping " + cmd)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(rule string) Values {
+		res, err := prog.SyntaxFlow(rule + " => $a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("%s: %v", rule, res["a"])
+		return res["a"]
+	}
+
+	// calls
+	if calls := check("os.System()"); assert.Len(t, calls, 1) {
+		assert.True(t, calls[0].IsCall())
+	}
+	// argument of call
+	if args := check("os.System(#0)"); assert.Len(t, args, 1) {
+		assert.True(t, args[0].IsBinOp())
+	}
+	if args := check("f(#1)"); assert.Len(t, args, 1) {
+		assert.Equal(t, 2, args[0].GetConstValue())
+	}
+	assert.Empty(t, check("f(#2)"))
+	// parameter of function
+	if params := check("f#0"); assert.Len(t, params, 1) {
+		assert.True(t, params[0].IsParameter())
+		assert.Equal(t, "a", params[0].GetName())
+	}
+	// return value of function and call
+	if rets := check("f#>"); assert.Len(t, rets, 1) {
+		assert.True(t, rets[0].IsBinOp())
+	}
+	if rets := check("f()#>"); assert.Len(t, rets, 1) {
+		assert.True(t, rets[0].IsCall())
+		assert.Equal(t, "println", rets[0].GetCallee().GetName())
+	}
+}
@@ -0,0 +1,260 @@
+package ssaapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yak/ssa"
+)
+
+// TaintFinding 是一条从 source 到 sink 的污点传播路径，
+// Path 中的第一个值是 source，最后一个值是 sink 的调用
+type TaintFinding struct {
+	Rule   *TaintRule
+	Source *Value
+	Sink   *Value
+	Path   Values
+}
+
+func (f *TaintFinding) String() string {
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("[%s] %s => %s\n", f.Rule.Name, f.Source.GetVerboseName(), f.Sink.GetVerboseName()))
+	for i, v := range f.Path {
+		buf.WriteString(fmt.Sprintf("\t%d: %s\n", i, v.StringWithSource()))
+	}
+	return buf.String()
+}
+
+type taintState struct {
+	value *Value
+	path  Values
+	// 进入函数时的调用点，return 的时候只回到对应的调用点
+	callStack []*Value
+}
+
+func (s *taintState) next(v *Value) *taintState {
+	path := make(Values, len(s.path), len(s.path)+1)
+	copy(path, s.path)
+	return &taintState{
+		value:     v,
+		path:      append(path, v),
+		callStack: s.callStack,
+	}
+}
+
+func (s *taintState) key() string {
+	if len(s.callStack) == 0 {
+		return fmt.Sprint(s.value.GetId())
+	}
+	return fmt.Sprintf("%d@%d", s.value.GetId(), s.callStack[len(s.callStack)-1].GetId())
+}
+
+type taintAnalyzer struct {
+	prog     *Program
+	rule     *TaintRule
+	findings []*TaintFinding
+	found    map[string]struct{}
+}
+
+// Taint 使用规则在程序上进行污点分析，返回所有从 source 到达 sink 的路径
+func (p *Program) Taint(rule *TaintRule) []*TaintFinding {
+	if p.IsNil() || rule == nil {
+		return nil
+	}
+	a := &taintAnalyzer{
+		prog:  p,
+		rule:  rule,
+		found: make(map[string]struct{}),
+	}
+	for _, source := range a.sources() {
+		a.propagate(source)
+	}
+	return a.findings
+}
+
+// sources 返回所有匹配 source 的值：匹配函数名的调用返回值，以及匹配变量名的值（如 $_GET）
+func (a *taintAnalyzer) sources() Values {
+	var ret Values
+	a.prog.Program.IdToInstructionMap.ForEach(func(_ int, inst ssa.Instruction) bool {
+		node, ok := inst.(ssa.InstructionNode)
+		if !ok {
+			return true
+		}
+		v := NewValue(node)
+		var names []string
+		if v.IsCall() {
+			names = valueNames(v.GetCallee())
+		} else if !v.IsFunction() {
+			names = valueNames(v)
+		}
+		if a.rule.matchSource(names) {
+			ret = append(ret, v)
+		}
+		return true
+	})
+	return ret
+}
+
+func (a *taintAnalyzer) propagate(source *Value) {
+	visited := make(map[string]struct{})
+	queue := []*taintState{{value: source, path: Values{source}}}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if len(state.path) > a.rule.maxDepth() {
+			continue
+		}
+		key := state.key()
+		if _, ok := visited[key]; ok {
+			continue
+		}
+		visited[key] = struct{}{}
+		queue = append(queue, a.step(source, state)...)
+	}
+}
+
+// step 返回污点从 state 出发可以传播到的下一个值
+func (a *taintAnalyzer) step(source *Value, state *taintState) []*taintState {
+	var next []*taintState
+	v := state.value
+
+	// 对象被污染，对象的成员也被污染：$_GET => $_GET["cmd"]
+	if obj, ok := v.node.(ssa.Value); ok && obj.IsObject() {
+		for _, member := range obj.GetAllMember() {
+			next = append(next, state.next(NewValue(member)))
+		}
+	}
+
+	for _, user := range v.GetUsers() {
+		switch {
+		case user.IsCall():
+			next = append(next, a.stepCall(source, state, user)...)
+		case user.IsReturn():
+			next = append(next, a.stepReturn(state, user)...)
+		case user.IsJump(), user.IsIf(), user.IsLoop(), user.IsSwitch(),
+			user.IsAssert(), user.IsUpdate(), user.IsErrorHandler(), user.IsPanic():
+			// 控制流指令不传播数据
+		default:
+			next = append(next, state.next(user))
+		}
+	}
+	return next
+}
+
+func (a *taintAnalyzer) stepCall(source *Value, state *taintState, call *Value) []*taintState {
+	c, ok := ssa.ToCall(call.node)
+	if !ok {
+		return nil
+	}
+	v := state.value
+	if c.Method == v.node {
+		// 调用被污染的函数，不传播
+		return nil
+	}
+	index := -1
+	for i, arg := range c.Args {
+		if arg == v.node {
+			index = i
+			break
+		}
+	}
+
+	names := valueNames(call.GetCallee())
+	if a.rule.matchSink(names, index) {
+		a.addFinding(source, call, state.next(call).path)
+		return nil
+	}
+	if a.rule.matchSanitizer(names) {
+		return nil
+	}
+
+	callee := call.GetCallee()
+	if f, ok := ssa.ToFunction(callee.node); ok && len(f.Blocks) > 0 && index >= 0 {
+		// 函数定义在程序中，进入函数继续分析
+		if index < len(f.Param) {
+			next := state.next(NewValue(f.Param[index]))
+			next.callStack = append(append([]*Value{}, state.callStack...), call)
+			return []*taintState{next}
+		}
+		return nil
+	}
+
+	if a.rule.matchPropagator(names) || a.rule.PropagateUnknownCall {
+		return []*taintState{state.next(call)}
+	}
+	return nil
+}
+
+func (a *taintAnalyzer) stepReturn(state *taintState, ret *Value) []*taintState {
+	if n := len(state.callStack); n > 0 {
+		call := state.callStack[n-1]
+		next := state.next(call)
+		next.callStack = state.callStack[:n-1]
+		return []*taintState{next}
+	}
+
+	// 没有调用上下文（source 在函数内部），回到所有调用这个函数的地方
+	var next []*taintState
+	f := ret.GetFunction()
+	if f == nil || utils.IsNil(f.node) {
+		return nil
+	}
+	for _, call := range f.GetCalledBy() {
+		if call.GetCallee().Compare(f) {
+			next = append(next, state.next(call))
+		}
+	}
+	return next
+}
+
+func (a *taintAnalyzer) addFinding(source, sink *Value, path Values) {
+	key := fmt.Sprintf("%d-%d", source.GetId(), sink.GetId())
+	if _, ok := a.found[key]; ok {
+		return
+	}
+	a.found[key] = struct{}{}
+	log.Debugf("taint rule %s found: %s => %s", a.rule.Name, source, sink)
+	a.findings = append(a.findings, &TaintFinding{
+		Rule:   a.rule,
+		Source: source,
+		Sink:   sink,
+		Path:   path,
+	})
+}
+
+// valueNames 返回值可能的名字，函数如 system / f，成员如 os.System / $o->run 中的 run
+func valueNames(v *Value) []string {
+	if v == nil || utils.IsNil(v.node) {
+		return nil
+	}
+	names := variableNames(v.node)
+	if member, ok := v.node.(ssa.Value); ok && member.IsMember() {
+		if c, ok := ssa.ToConst(member.GetKey()); ok && c.IsString() {
+			key := c.VarString()
+			names = append(names, key)
+			for _, objName := range variableNames(member.GetObject()) {
+				names = append(names, objName+"."+key)
+			}
+		}
+	}
+	return names
+}
+
+func variableNames(inst ssa.Instruction) []string {
+	if utils.IsNil(inst) {
+		return nil
+	}
+	var names []string
+	if name := inst.GetName(); name != "" {
+		names = append(names, name)
+	}
+	if name := inst.GetVerboseName(); name != "" {
+		names = append(names, name)
+	}
+	for name := range inst.GetAllVariables() {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,291 @@
+package ssaapi
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+	"gopkg.in/yaml.v3"
+)
+
+// TaintSpec 通过名字（支持 glob，如 os.* / $_*）匹配函数或变量
+//
+//	name: system
+//	args: [0]     # 仅对 sink 有效，污点到达哪些参数时才算命中，为空表示任意参数
+type TaintSpec struct {
+	Name string `yaml:"name" json:"name"`
+	Args []int  `yaml:"args,omitempty" json:"args,omitempty"`
+
+	matcher glob.Glob
+}
+
+func (s *TaintSpec) match(names []string) bool {
+	if s.matcher == nil {
+		return false
+	}
+	for _, name := range names {
+		if s.matcher.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// TaintRule 是一条污点分析规则
+//
+//	name: php-command-injection
+//	title: PHP 命令注入
+//	severity: high
+//	risk-type: command-injection
+//	language: php
+//	sources:
+//	  - name: $_GET
+//	sinks:
+//	  - name: system
+//	    args: [0]
+//	sanitizers:
+//	  - name: escapeshellarg
+//	propagators:
+//	  - name: trim
+type TaintRule struct {
+	Name        string `yaml:"name" json:"name"`
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description" json:"description"`
+	Solution    string `yaml:"solution" json:"solution"`
+	Severity    string `yaml:"severity" json:"severity"`
+	RiskType    string `yaml:"risk-type" json:"risk_type"`
+	// 规则适用的语言，为空表示所有语言
+	Language Language `yaml:"language" json:"language"`
+
+	Sources     []*TaintSpec `yaml:"sources" json:"sources"`
+	Sinks       []*TaintSpec `yaml:"sinks" json:"sinks"`
+	Sanitizers  []*TaintSpec `yaml:"sanitizers" json:"sanitizers"`
+	Propagators []*TaintSpec `yaml:"propagators" json:"propagators"`
+
+	// 调用没有在程序中定义、也不在 propagators 中的函数时，返回值是否被污染
+	PropagateUnknownCall bool `yaml:"propagate-unknown-call" json:"propagate_unknown_call"`
+	// 路径的最大长度，默认 64
+	MaxDepth int `yaml:"max-depth" json:"max_depth"`
+}
+
+const defaultTaintMaxDepth = 64
+
+// ParseTaintRule 解析 YAML 格式的污点分析规则
+func ParseTaintRule(raw string) (*TaintRule, error) {
+	var rule TaintRule
+	if err := yaml.Unmarshal([]byte(raw), &rule); err != nil {
+		return nil, utils.Wrapf(err, "parse taint rule failed")
+	}
+	if err := rule.compile(); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *TaintRule) compile() error {
+	if r.Name == "" {
+		return utils.Errorf("taint rule name is empty")
+	}
+	if len(r.Sources) == 0 || len(r.Sinks) == 0 {
+		return utils.Errorf("taint rule %s: sources and sinks are required", r.Name)
+	}
+	if r.Title == "" {
+		r.Title = r.Name
+	}
+	for _, specs := range [][]*TaintSpec{r.Sources, r.Sinks, r.Sanitizers, r.Propagators} {
+		for _, spec := range specs {
+			if spec == nil || spec.Name == "" {
+				return utils.Errorf("taint rule %s: empty spec name", r.Name)
+			}
+			matcher, err := glob.Compile(spec.Name)
+			if err != nil {
+				return utils.Wrapf(err, "taint rule %s: compile %s failed", r.Name, spec.Name)
+			}
+			spec.matcher = matcher
+		}
+	}
+	return nil
+}
+
+func (r *TaintRule) maxDepth() int {
+	if r.MaxDepth > 0 {
+		return r.MaxDepth
+	}
+	return defaultTaintMaxDepth
+}
+
+func matchTaintSpecs(specs []*TaintSpec, names []string) bool {
+	for _, spec := range specs {
+		if spec.match(names) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *TaintRule) matchSource(names []string) bool {
+	return matchTaintSpecs(r.Sources, names)
+}
+
+func (r *TaintRule) matchSanitizer(names []string) bool {
+	return matchTaintSpecs(r.Sanitizers, names)
+}
+
+func (r *TaintRule) matchPropagator(names []string) bool {
+	return matchTaintSpecs(r.Propagators, names)
+}
+
+// matchSink 污点作为第 index 个参数传入时，是否命中 sink
+func (r *TaintRule) matchSink(names []string, index int) bool {
+	for _, spec := range r.Sinks {
+		if !spec.match(names) {
+			continue
+		}
+		if len(spec.Args) == 0 {
+			return true
+		}
+		for _, i := range spec.Args {
+			if i == index {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TaintWithRules 使用适用于当前语言的所有规则进行污点分析
+func (p *Program) TaintWithRules(rules ...*TaintRule) []*TaintFinding {
+	var findings []*TaintFinding
+	for _, rule := range rules {
+		if rule.Language != "" && p.config != nil && rule.Language != p.config.language {
+			continue
+		}
+		findings = append(findings, p.Taint(rule)...)
+	}
+	return findings
+}
+
+//go:embed taint_rules/*.yaml
+var builtinTaintRules embed.FS
+
+// GetBuiltinTaintRules 返回内置的污点分析规则
+func GetBuiltinTaintRules() []*TaintRule {
+	entries, err := builtinTaintRules.ReadDir("taint_rules")
+	if err != nil {
+		log.Errorf("read builtin taint rules failed: %v", err)
+		return nil
+	}
+	var rules []*TaintRule
+	for _, entry := range entries {
+		raw, err := builtinTaintRules.ReadFile(path.Join("taint_rules", entry.Name()))
+		if err != nil {
+			log.Errorf("read builtin taint rule %s failed: %v", entry.Name(), err)
+			continue
+		}
+		rule, err := ParseTaintRule(string(raw))
+		if err != nil {
+			log.Errorf("parse builtin taint rule %s failed: %v", entry.Name(), err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// TaintHop 是污点路径中的一步，用于保存到 risk 的 details 中
+type TaintHop struct {
+	Opcode   string `json:"opcode"`
+	Code     string `json:"code"`
+	FileName string `json:"file_name,omitempty"`
+	Line     int64  `json:"line"`
+	Column   int64  `json:"column"`
+	Source   string `json:"source"`
+}
+
+func (f *TaintFinding) Hops() []*TaintHop {
+	hops := make([]*TaintHop, 0, len(f.Path))
+	for _, v := range f.Path {
+		hop := &TaintHop{
+			Opcode: string(v.GetOpcode()),
+			Code:   v.String(),
+		}
+		if r := v.GetRange(); r != nil {
+			hop.FileName = r.FileName
+			if r.Start != nil {
+				hop.Line, hop.Column = r.Start.Line, r.Start.Column
+			}
+			if r.SourceCode != nil {
+				hop.Source = *r.SourceCode
+			}
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// location 返回 sink 所在的位置，如 index.php:3:1
+func (f *TaintFinding) location() string {
+	return formatRangeLocation(f.Sink.GetRange())
+}
+
+// sinkLocation 返回 sink 的源码位置，导出 SARIF 等格式时作为漏洞位置
+func (f *TaintFinding) sinkLocation() *yakit.RiskCodeLocation {
+	loc := &yakit.RiskCodeLocation{}
+	r := f.Sink.GetRange()
+	if r == nil {
+		return loc
+	}
+	loc.FileName = r.FileName
+	if r.Start != nil {
+		loc.StartLine, loc.StartColumn = r.Start.Line, r.Start.Column
+	}
+	if r.End != nil {
+		loc.EndLine, loc.EndColumn = r.End.Line, r.End.Column
+	}
+	if r.SourceCode != nil {
+		loc.Code = *r.SourceCode
+	}
+	return loc
+}
+
+// ToRisk 把污点分析结果转换为 yakit 的 risk（不保存）
+func (f *TaintFinding) ToRisk(opts ...yakit.RiskParamsOpt) *yakit.Risk {
+	rule := f.Rule
+	loc := f.sinkLocation()
+	hops := f.Hops()
+	paths := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		paths = append(paths, fmt.Sprintf("%s:%d:%d %s", hop.FileName, hop.Line, hop.Column, hop.Source))
+	}
+
+	riskOpts := []yakit.RiskParamsOpt{
+		yakit.WithRiskParam_Title(rule.Title),
+		yakit.WithRiskParam_TitleVerbose(rule.Title),
+		yakit.WithRiskParam_Description(rule.Description),
+		yakit.WithRiskParam_Solution(rule.Solution),
+		yakit.WithRiskParam_Severity(rule.Severity),
+		yakit.WithRiskParam_Parameter(f.location()),
+		yakit.WithRiskParam_Payload(loc.Code),
+		yakit.WithRiskParam_Details(loc.Details(map[string]any{
+			"rule":   rule.Name,
+			"source": f.Source.String(),
+			"sink":   f.Sink.String(),
+			"path":   strings.Join(paths, "\n"),
+		})),
+	}
+	if rule.RiskType != "" {
+		riskOpts = append(riskOpts, yakit.WithRiskParam_RiskType(rule.RiskType))
+	}
+	return yakit.CreateRisk("", append(riskOpts, opts...)...)
+}
+
+// SaveToRisk 把污点分析结果保存为 yakit 的 risk
+func (f *TaintFinding) SaveToRisk(opts ...yakit.RiskParamsOpt) (*yakit.Risk, error) {
+	r := f.ToRisk(opts...)
+	return r, yakit.SaveRisk(r)
+}
@@ -0,0 +1,157 @@
+package ssaapi
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+)
+
+func getBuiltinTaintRule(t *testing.T, name string) *TaintRule {
+	for _, rule := range GetBuiltinTaintRules() {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	t.Fatalf("builtin taint rule %s not found", name)
+	return nil
+}
+
+func TestTaint_PHPCommandInjection(t *testing.T) {
+	prog, err := Parse(`<?php
+function wrap($x) {
+	return "ping -c 1 " . $x;
+}
+$ip = $_GET["ip"];
+system(wrap($ip));
+system(escapeshellarg($ip));
+system("ls");
+`, WithLanguage(PHP))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := prog.Taint(getBuiltinTaintRule(t, "php-command-injection"))
+	if !assert.Len(t, findings, 1) {
+		return
+	}
+	finding := findings[0]
+	t.Log(finding)
+	assert.True(t, finding.Source.IsParameter())
+	assert.Equal(t, "$_GET", finding.Source.GetName())
+	assert.True(t, finding.Sink.IsCall())
+	assert.Equal(t, int64(6), finding.Sink.GetRange().Start.Line)
+
+	// path: $_GET => $_GET["ip"] => $x => "ping" . $x => wrap() => system()
+	hops := finding.Hops()
+	assert.Greater(t, len(hops), 4)
+	var inFunction bool
+	for _, hop := range hops {
+		if hop.Line == 3 {
+			inFunction = true
+		}
+	}
+	assert.True(t, inFunction, "path should go through function wrap")
+}
+
+func TestTaint_YakRuleYAML(t *testing.T) {
+	rule, err := ParseTaintRule(`
+name: test-yak
+title: test
+severity: high
+sources:
+  - name: cli.String
+sinks:
+  - name: os.System
+    args: [0]
+propagators:
+  - name: str.TrimSpace
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog, err := Parse(`
+a = cli.String("target")
+b = str.TrimSpace(a)
+os.System("ping " + b)
+c = str.ToLower(a)
+os.System(c)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := prog.Taint(rule)
+	if assert.Len(t, findings, 1) {
+		t.Log(findings[0])
+		assert.Equal(t, int64(4), findings[0].Sink.GetRange().Start.Line)
+
+		risk := findings[0].ToRisk()
+		assert.Equal(t, "test", risk.Title)
+		assert.Equal(t, "high", risk.Severity)
+		assert.Contains(t, risk.Details, "cli.String")
+	}
+
+	rule.PropagateUnknownCall = true
+	assert.Len(t, prog.Taint(rule), 2)
+}
+
+func TestTaint_ToRiskSARIF(t *testing.T) {
+	prog, err := ParseProject(fstest.MapFS{
+		"main.yak": {Data: []byte("a = cli.String(\"target\")\nos.System(a)\n")},
+	}, WithLanguage(Yak))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := ParseTaintRule(`
+name: test-yak-sarif
+title: test
+severity: high
+sources:
+  - name: cli.String
+sinks:
+  - name: os.System
+    args: [0]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := prog.Taint(rule)
+	if !assert.Len(t, findings, 1) {
+		return
+	}
+
+	// ToRisk 写入的源码位置需要能被漏洞导出读出来
+	raw, err := yakit.RisksToSARIF([]*yakit.Risk{findings[0].ToRisk()}, nil)
+	require.NoError(t, err)
+	var log yakit.SARIFLog
+	require.NoError(t, json.Unmarshal(raw, &log))
+	require.Len(t, log.Runs[0].Results, 1)
+	require.Len(t, log.Runs[0].Results[0].Locations, 1)
+	location := log.Runs[0].Results[0].Locations[0].PhysicalLocation
+	assert.Equal(t, "main.yak", location.ArtifactLocation.Uri)
+	require.NotNil(t, location.Region)
+	assert.EqualValues(t, 2, location.Region.StartLine)
+	assert.Greater(t, location.Region.StartColumn, int64(0))
+	require.NotNil(t, location.Region.Snippet)
+	assert.Contains(t, location.Region.Snippet.Text, "os.System")
+}
+
+func TestTaint_RuleLanguage(t *testing.T) {
+	prog, err := Parse(`<?php eval($_POST["code"]);`, WithLanguage(PHP))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := prog.TaintWithRules(GetBuiltinTaintRules()...)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, "php-code-injection", findings[0].Rule.Name)
+	}
+
+	_, err = ParseTaintRule(`name: no-sink
+sources:
+  - name: a`)
+	assert.Error(t, err)
+}
@@ -201,6 +201,17 @@ func (v *Value) GetRange() *ssa.Range {
 	return v.node.GetRange()
 }
 
+// formatRangeLocation 把源码范围格式化为 file:line:column，没有文件名时为 line:column
+func formatRangeLocation(r *ssa.Range) string {
+	if r == nil || r.Start == nil {
+		return ""
+	}
+	if r.FileName != "" {
+		return fmt.Sprintf("%s:%d:%d", r.FileName, r.Start.Line, r.Start.Column)
+	}
+	return fmt.Sprintf("%d:%d", r.Start.Line, r.Start.Column)
+}
+
 func (i *Value) HasOperands() bool {
 	return i.node.HasValues()
 }
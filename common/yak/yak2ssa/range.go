@@ -15,6 +15,7 @@ func (b *astbuilder) SetRange(token antlr4util.CanStartStopToken) func() {
 	if r == nil {
 		return func() {}
 	}
+	r.FileName = b.GetProgram().GetCurrentFile()
 	backup := b.CurrentRange
 	b.CurrentRange = r
 
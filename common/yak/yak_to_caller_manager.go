@@ -26,6 +26,7 @@ import (
 	"github.com/yaklang/yaklang/common/go-funk"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/mutate"
+	"github.com/yaklang/yaklang/common/smuggle"
 	"github.com/yaklang/yaklang/common/utils"
 	"github.com/yaklang/yaklang/common/utils/lowhttp"
 	"github.com/yaklang/yaklang/common/yak/antlr4yak"
@@ -726,6 +727,23 @@ func BindYakitPluginContextToEngine(nIns *antlr4yak.Engine, pluginContext *Yakit
 		}
 		return i
 	})
+	nIns.GetVM().RegisterMapMemberCallHandler("smuggle", "Scan", func(i interface{}) interface{} {
+		originFunc, ok := i.(func(target any, opts ...smuggle.Option) ([]*smuggle.Result, error))
+		if ok {
+			return func(target any, opts ...smuggle.Option) ([]*smuggle.Result, error) {
+				opts = append([]smuggle.Option{smuggle.WithContext(streamContext)}, opts...)
+				opts = append(opts, smuggle.WithFromPlugin(pluginName))
+				if runtimeId != "" {
+					opts = append(opts, smuggle.WithRuntimeId(runtimeId))
+				}
+				if proxy != "" {
+					opts = append(opts, smuggle.WithProxy(proxy))
+				}
+				return originFunc(target, opts...)
+			}
+		}
+		return i
+	})
 	nIns.GetVM().RegisterMapMemberCallHandler("fuzz", "HTTPRequest", func(i interface{}) interface{} {
 		originFunc, ok := i.(func(interface{}, ...mutate.BuildFuzzHTTPRequestOption) (*mutate.FuzzHTTPRequest, error))
 		if ok {
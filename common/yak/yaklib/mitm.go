@@ -27,6 +27,7 @@ var MitmExports = map[string]interface{}{
 	"wsforcetext":          mitmConfigWSForceTextFrame,
 	"rootCA":               mitmConfigCertAndKey,
 	"useDefaultCA":         mitmConfigUseDefault,
+	"clientCertificate":    mitmConfigClientCertificate,
 }
 
 // Start 启动一个 MITM (中间人)代理服务器，它的第一个参数是端口，接下来可以接收零个到多个选项函数，用于影响中间人代理服务器的行为
@@ -51,6 +52,7 @@ type mitmConfig struct {
 	mitmCert, mitmPkey []byte
 	useDefaultMitmCert bool
 	maxContentLength   int
+	clientCertificates []*lowhttp.ClientCertificateRule
 
 	// 是否开启透明劫持
 	isTransparent            bool
@@ -222,6 +224,21 @@ func mitmMaxContentLength(i int) MitmConfigOpt {
 	}
 }
 
+// clientCertificate 是一个选项函数，用于指定中间人代理服务器转发请求时使用证书仓库中名为 name 的 mTLS 客户端证书，
+// 可以传入 host 的 glob 匹配规则，只对匹配的目标使用该证书，多次使用可以为不同的目标指定不同的证书
+// Example:
+// ```
+// mitm.Start(8080, mitm.clientCertificate("tenant-a", "*.tenant-a.com"), mitm.clientCertificate("tenant-b", "*.tenant-b.com"))
+// ```
+func mitmConfigClientCertificate(name string, hostPatterns ...string) MitmConfigOpt {
+	return func(config *mitmConfig) {
+		config.clientCertificates = append(config.clientCertificates, &lowhttp.ClientCertificateRule{
+			Name:         name,
+			HostPatterns: hostPatterns,
+		})
+	}
+}
+
 // Bridge 启动一个 MITM (中间人)代理服务器，它的第一个参数是端口，第二个参数是下游代理服务器地址，接下来可以接收零个到多个选项函数，用于影响中间人代理服务器的行为
 // Bridge 与 Start 类似，但略有不同，Bridge可以指定下游代理服务器地址，同时默认会在接收到请求和响应时打印到标准输出
 // 如果没有指定 CA 证书和私钥，那么将使用内置的证书和私钥
@@ -444,6 +461,9 @@ func startBridge(
 	if err != nil {
 		return utils.Errorf("create mitm server failed: %s", err)
 	}
+	for _, rule := range config.clientCertificates {
+		server.Configure(crep.MITM_SetClientCertificate(rule.Name, rule.HostPatterns...))
+	}
 	err = server.Serve(config.ctx, utils.HostPort(config.host, port))
 	if err != nil {
 		log.Errorf("server mitm failed: %s", err)
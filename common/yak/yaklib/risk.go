@@ -83,6 +83,7 @@ var (
 		"CheckRandomTriggerByToken": yakit.CheckRandomTriggerByToken,
 		"CheckICMPTriggerByLength":  yakit.CheckICMPTriggerByLength,
 		"ExtractTokenFromUrl":       yakit.ExtractTokenFromUrl,
+		"ParseCVSSVector":           yakit.ParseCVSSVector,
 		"payload":                   yakit.WithRiskParam_Payload,
 		"title":                     yakit.WithRiskParam_Title,
 		"type":                      yakit.WithRiskParam_RiskType,
@@ -98,6 +99,7 @@ var (
 		"runtimeId":                 yakit.WithRiskParam_RuntimeId,
 		"potential":                 yakit.WithRiskParam_Potential,
 		"cve":                       yakit.WithRiskParam_CVE,
+		"cvss":                      yakit.WithRiskParam_CVSS,
 		"severity":                  yakit.WithRiskParam_Severity,
 		"level":                     yakit.WithRiskParam_Severity,
 		"fromYakScript":             yakit.WithRiskParam_FromScript,
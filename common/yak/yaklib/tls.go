@@ -1,8 +1,10 @@
 package yaklib
 
 import (
+	"github.com/yaklang/yaklang/common/consts"
 	"github.com/yaklang/yaklang/common/netx"
 	"github.com/yaklang/yaklang/common/utils/tlsutils"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
 )
 
 // GenerateRSA1024KeyPair 生成1024位大小的RSA公私钥对，返回PEM格式公钥和私钥与错误
@@ -41,6 +43,78 @@ func generateRootCA(commonName string) (ca []byte, key []byte, err error) {
 	return tlsutils.GenerateSelfSignedCertKeyWithCommonName(commonName, "", nil, nil)
 }
 
+// ImportClientCertificate 将 PEM 格式的客户端证书与私钥以 name 导入到证书仓库，同名证书会被覆盖，
+// 可以传入 host 的 glob 匹配规则，HTTP 请求（包括 Web Fuzzer 与 MITM）访问匹配的目标时会自动使用该证书
+// Example:
+// ```
+// cert, key = tls.SignClientCertAndKey(ca, caKey)~
+// tls.ImportClientCertificate("tenant-a", cert, key, "*.tenant-a.com")~
+// poc.Get("https://api.tenant-a.com")~ // 自动使用 tenant-a 证书
+// ```
+func importClientCertificate(name string, cert, key []byte, hostPatterns ...string) error {
+	_, err := yakit.ImportClientCertificateFromPEM(consts.GetGormProfileDatabase(), name, cert, key, hostPatterns...)
+	return err
+}
+
+// ImportClientCertificateFromP12 将 p12/pfx 格式的客户端证书以 name 导入到证书仓库，同名证书会被覆盖，
+// 可以传入 host 的 glob 匹配规则，HTTP 请求访问匹配的目标时会自动使用该证书
+// Example:
+// ```
+// tls.ImportClientCertificateFromP12("tenant-b", file.ReadFile("tenant-b.p12")~, "password", "*.tenant-b.com")~
+// ```
+func importClientCertificateFromP12(name string, p12 []byte, password string, hostPatterns ...string) error {
+	_, err := yakit.ImportClientCertificateFromP12(consts.GetGormProfileDatabase(), name, p12, password, hostPatterns...)
+	return err
+}
+
+// ExportClientCertificate 从证书仓库中导出名为 name 的客户端证书，返回 PEM 格式证书（包含证书链）、私钥与错误
+// Example:
+// ```
+// cert, key = tls.ExportClientCertificate("tenant-a")~
+// ```
+func exportClientCertificate(name string) ([]byte, []byte, error) {
+	return yakit.ExportClientCertificateToPEM(consts.GetGormProfileDatabase(), name)
+}
+
+// ExportClientCertificateToP12 从证书仓库中导出名为 name 的客户端证书，返回使用 password 加密的 p12 数据与错误
+// Example:
+// ```
+// p12 = tls.ExportClientCertificateToP12("tenant-a", "password")~
+// file.Save("tenant-a.p12", p12)
+// ```
+func exportClientCertificateToP12(name string, password string) ([]byte, error) {
+	return yakit.ExportClientCertificateToP12(consts.GetGormProfileDatabase(), name, password)
+}
+
+// ListClientCertificates 列出证书仓库中的所有客户端证书
+// Example:
+// ```
+// for cert in tls.ListClientCertificates()~ {
+// println(cert.Name, cert.HostPattern, cert.Subject)
+// }
+// ```
+func listClientCertificates() ([]*yakit.ClientCertificate, error) {
+	return yakit.QueryClientCertificates(consts.GetGormProfileDatabase())
+}
+
+// SetClientCertificateHostPattern 修改证书仓库中名为 name 的证书自动匹配的 host glob 规则，不传入规则时取消自动匹配
+// Example:
+// ```
+// tls.SetClientCertificateHostPattern("tenant-a", "*.tenant-a.com", "10.0.0.*")~
+// ```
+func setClientCertificateHostPattern(name string, hostPatterns ...string) error {
+	return yakit.SetClientCertificateHostPattern(consts.GetGormProfileDatabase(), name, hostPatterns...)
+}
+
+// DeleteClientCertificate 从证书仓库中删除名为 name 的客户端证书
+// Example:
+// ```
+// tls.DeleteClientCertificate("tenant-a")~
+// ```
+func deleteClientCertificate(name string) error {
+	return yakit.DeleteClientCertificateByName(consts.GetGormProfileDatabase(), name)
+}
+
 var TlsExports = map[string]interface{}{
 	"GenerateRSAKeyPair":       tlsutils.RSAGenerateKeyPair,
 	"GenerateRSA1024KeyPair":   generateRSA1024KeyPair,
@@ -55,4 +129,12 @@ var TlsExports = map[string]interface{}{
 	"Inspect":                  netx.TLSInspect,
 	"EncryptWithPkcs1v15":      tlsutils.PemPkcs1v15Encrypt,
 	"DecryptWithPkcs1v15":      tlsutils.PemPkcs1v15Decrypt,
+
+	"ImportClientCertificate":         importClientCertificate,
+	"ImportClientCertificateFromP12":  importClientCertificateFromP12,
+	"ExportClientCertificate":         exportClientCertificate,
+	"ExportClientCertificateToP12":    exportClientCertificateToP12,
+	"ListClientCertificates":          listClientCertificates,
+	"SetClientCertificateHostPattern": setClientCertificateHostPattern,
+	"DeleteClientCertificate":         deleteClientCertificate,
 }
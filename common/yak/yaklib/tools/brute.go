@@ -29,6 +29,7 @@ var BruterExports = map[string]interface{}{
 	"bruteHandler":       yakBruteOpt_coreHandler,
 	"okToStop":           yakBruteOpt_OkToStop,
 	"finishingThreshold": yakBruteOpt_FinishingThreshold,
+	"kerberos":           yakBruteOpt_kerberos,
 }
 
 type yakBruter struct {
@@ -43,6 +44,9 @@ type yakBruter struct {
 	coreHandler   bruteutils.BruteCallback
 	resultHandler func(res *bruteutils.BruteItemResult)
 
+	// 使用 Kerberos 认证爆破域账户，支持 kerberos / smb / mssql
+	kerberos *bruteutils.KerberosConfig
+
 	// 同时支持多少个并发目标同时测试？默认 256
 	concurrentTarget int
 
@@ -131,6 +135,15 @@ func yakBruteOpt_coreHandler(cb func(item *bruteutils.BruteItem) *bruteutils.Bru
 	}
 }
 
+func yakBruteOpt_kerberos(realm string, kdc ...string) yakBruteOpt {
+	return func(bruter *yakBruter) {
+		bruter.kerberos = &bruteutils.KerberosConfig{Realm: realm}
+		if len(kdc) > 0 {
+			bruter.kerberos.KDC = kdc[0]
+		}
+	}
+}
+
 func (y *yakBruter) Start(targets ...string) (chan *bruteutils.BruteItemResult, error) {
 	action, err := bruteutils.WithDelayerWaiter(y.minDelay, y.maxDelay)
 	if err != nil {
@@ -235,6 +248,14 @@ func _yakitBruterNew(typeStr string, opts ...yakBruteOpt) (*yakBruter, error) {
 		p(bruter)
 	}
 
+	if bruter.coreHandler == nil && bruter.kerberos != nil {
+		coreHandler, err := bruteutils.GetKerberosBruteFuncByType(bruter.bruteType, bruter.kerberos)
+		if err != nil {
+			return nil, utils.Errorf("get kerberos bruter for [%v] failed: %s", typeStr, err)
+		}
+		bruter.coreHandler = coreHandler
+	}
+
 	if bruter.coreHandler == nil {
 		coreHandler, err := bruteutils.GetBruteFuncByType(bruter.bruteType)
 		if err != nil {
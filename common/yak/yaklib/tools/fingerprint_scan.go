@@ -445,6 +445,10 @@ var FingerprintScanExports = map[string]interface{}{
 	// 使用 web 指纹识别规则进行扫描
 	"webRule": fp.WithWebFingerprintRule,
 
+	// 导入 Wappalyzer / FingerprintHub / EHole 指纹规则，与当前 web 指纹规则合并
+	"wappalyzerRule":     fp.WithWappalyzerRule,
+	"fingerprintHubRule": fp.WithFingerprintHubRule,
+
 	// 可以使用 nmap 的规则进行扫描，也可以写 nmap 规则进行扫描
 	"nmapRule": fp.WithNmapRule,
 
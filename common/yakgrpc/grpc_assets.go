@@ -728,6 +728,72 @@ func (s *Server) DownloadReport(ctx context.Context, req *ypb.DownloadReportRequ
 	return &ypb.Empty{}, nil
 }
 
+func (s *Server) RenderReport(ctx context.Context, req *ypb.RenderReportRequest) (*ypb.RenderReportResponse, error) {
+	var (
+		record *yakit.ReportRecord
+		err    error
+	)
+	if req.GetId() > 0 {
+		record, err = yakit.GetReportRecord(s.GetProjectDatabase(), req.GetId())
+	} else if req.GetHash() != "" {
+		record, err = yakit.GetReportRecordByHash(s.GetProjectDatabase(), req.GetHash())
+	} else {
+		return nil, utils.Errorf("empty search")
+	}
+	if err != nil {
+		return nil, err
+	}
+	report, err := record.ToReport()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &yakit.ReportTemplate{}
+	if req.GetTemplateFile() != "" {
+		loaded, err := yakit.LoadReportTemplate(req.GetTemplateFile())
+		if err != nil {
+			return nil, err
+		}
+		tmpl.Merge(loaded)
+	}
+	if t := req.GetTemplate(); t != nil {
+		tmpl.Merge(&yakit.ReportTemplate{
+			CompanyName:  t.GetCompanyName(),
+			Logo:         t.GetLogo(),
+			PrimaryColor: t.GetPrimaryColor(),
+			Header:       t.GetHeader(),
+			Footer:       t.GetFooter(),
+			CSS:          t.GetCSS(),
+			HTMLLayout:   t.GetHTMLLayout(),
+		})
+	}
+
+	format := req.GetFormat()
+	if format == "" {
+		format = yakit.REPORT_RENDER_FORMAT_HTML
+	}
+	raw, err := yakit.RenderReport(report, format, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFileDir() == "" {
+		return &ypb.RenderReportResponse{Data: raw}, nil
+	}
+
+	fileName := req.GetFileName()
+	if fileName == "" {
+		fileName = fmt.Sprintf("report-%v", record.ID)
+	}
+	if filepath.Ext(fileName) == "" {
+		fileName += "." + strings.ToLower(format)
+	}
+	dataPath := filepath.Join(req.GetFileDir(), fileName)
+	if err := ioutil.WriteFile(dataPath, raw, 0666); err != nil {
+		return nil, utils.Errorf("write report failed: %s", err)
+	}
+	return &ypb.RenderReportResponse{FilePath: dataPath}, nil
+}
+
 func (s *Server) UploadRiskToOnline(ctx context.Context, req *ypb.UploadRiskToOnlineRequest) (*ypb.Empty, error) {
 	if req.Token == "" {
 		return nil, utils.Errorf("params empty")
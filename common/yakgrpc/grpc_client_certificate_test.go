@@ -0,0 +1,120 @@
+package yakgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/utils/tlsutils"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+// mockClientCertificateServer 启动要求客户端证书的 https 服务，响应为客户端证书的 CN，
+// 并把 CN 为 name 的证书导入证书仓库
+func mockClientCertificateServer(t *testing.T) (name string, host string, port int) {
+	ca, caKey, err := tlsutils.GenerateSelfSignedCertKeyWithCommonName("mtls-ca", "", nil, nil)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca))
+
+	name = "test-" + utils.RandStringBytes(8)
+	cert, key, err := tlsutils.SignClientCrtNKeyWithParams(ca, caKey, name, time.Now().Add(time.Hour), true)
+	require.NoError(t, err)
+	db := consts.GetGormProfileDatabase()
+	_, err = yakit.ImportClientCertificateFromPEM(db, name, cert, key)
+	require.NoError(t, err)
+	t.Cleanup(func() { yakit.DeleteClientCertificateByName(db, name) })
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	host, port, _ = utils.ParseStringToHostPort(server.URL)
+	return name, host, port
+}
+
+func TestGRPCMUSTPASS_HTTPFuzzer_ClientCertificate(t *testing.T) {
+	client, err := NewLocalClient()
+	require.NoError(t, err)
+	name, host, port := mockClientCertificateServer(t)
+
+	fuzz := func(rules ...*ypb.ClientCertificateRule) *ypb.FuzzerResponse {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		stream, err := client.HTTPFuzzer(ctx, &ypb.FuzzerRequest{
+			Request:            "GET / HTTP/1.1\r\nHost: " + utils.HostPort(host, port) + "\r\n\r\n",
+			IsHTTPS:            true,
+			ClientCertificates: rules,
+		})
+		require.NoError(t, err)
+		var last *ypb.FuzzerResponse
+		for {
+			rsp, err := stream.Recv()
+			if err != nil {
+				break
+			}
+			last = rsp
+		}
+		require.NotNil(t, last)
+		return last
+	}
+
+	// 没有证书时服务端拒绝握手
+	rsp := fuzz()
+	assert.False(t, rsp.GetOk())
+
+	// host 不匹配时不使用证书
+	rsp = fuzz(&ypb.ClientCertificateRule{Name: name, HostPatterns: []string{"*.example.com"}})
+	assert.False(t, rsp.GetOk())
+
+	rsp = fuzz(&ypb.ClientCertificateRule{Name: name, HostPatterns: []string{host}})
+	require.True(t, rsp.GetOk(), rsp.GetReason())
+	_, body := lowhttp.SplitHTTPPacketFast(rsp.GetResponseRaw())
+	assert.Equal(t, name, string(body))
+}
+
+func TestGRPCMUSTPASS_MITM_ClientCertificate(t *testing.T) {
+	client, err := NewLocalClient()
+	require.NoError(t, err)
+	name, host, port := mockClientCertificateServer(t)
+
+	mitmPort := utils.GetRandomAvailableTCPPort()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var body []byte
+	RunMITMTestServer(client, ctx, &ypb.MITMRequest{
+		Host: "127.0.0.1",
+		Port: uint32(mitmPort),
+		ClientCertificates: []*ypb.ClientCertificateRule{
+			{Name: name, HostPatterns: []string{utils.HostPort(host, port)}},
+		},
+	}, func(mitmClient ypb.Yak_MITMClient) {
+		defer cancel()
+		rsp, err := lowhttp.HTTP(
+			lowhttp.WithHttps(true),
+			lowhttp.WithRequest([]byte("GET / HTTP/1.1\r\nHost: "+utils.HostPort(host, port)+"\r\n\r\n")),
+			lowhttp.WithProxy("http://"+utils.HostPort("127.0.0.1", mitmPort)),
+			lowhttp.WithTimeout(10*time.Second),
+			lowhttp.WithSaveHTTPFlow(false),
+		)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, body = lowhttp.SplitHTTPPacketFast(rsp.RawPacket)
+	})
+	assert.Equal(t, name, string(body))
+}
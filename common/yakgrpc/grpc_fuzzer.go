@@ -719,6 +719,9 @@ func (s *Server) HTTPFuzzer(req *ypb.FuzzerRequest, stream ypb.Yak_HTTPFuzzerSer
 			mutate.WithPoolOpt_NoSystemProxy(req.GetNoSystemProxy()),
 			mutate.WithPoolOpt_RequestCountLimiter(requestCount),
 		}
+		for _, rule := range req.GetClientCertificates() {
+			httpPoolOpts = append(httpPoolOpts, mutate.WithPoolOpt_ClientCertificate(rule.GetName(), rule.GetHostPatterns()...))
+		}
 
 		fuzzMode := req.GetFuzzTagMode() // ""/"close"/"standard"/"legacy"
 		forceFuzz := req.GetForceFuzz()  // true/false
@@ -1457,6 +1457,7 @@ func (s *Server) MITM(stream ypb.Yak_MITMServer) error {
 		startCreateFlow = time.Now()
 
 		flow.FitHTTPRequest(req)
+		flow.ClientCertificate = httpctx.GetClientCertificate(req)
 
 		// Hidden Index 用来标注 MITM 劫持的顺序
 		flow.HiddenIndex = getPacketIndex()
@@ -1628,6 +1629,9 @@ func (s *Server) MITM(stream ypb.Yak_MITMServer) error {
 	for _, cert := range firstReq.GetCertificates() {
 		opts = append(opts, crep.MITM_MutualTLSClient(cert.CrtPem, cert.KeyPem, cert.GetCaCertificates()...))
 	}
+	for _, rule := range firstReq.GetClientCertificates() {
+		opts = append(opts, crep.MITM_SetClientCertificate(rule.GetName(), rule.GetHostPatterns()...))
+	}
 
 	mServer, err = crep.NewMITMServer(
 		crep.MITM_ProxyAuth(proxyUsername, proxyPassword),
@@ -169,7 +169,8 @@ func (s *Server) PcapX(stream ypb.Yak_PcapXServer) error {
 	return nil
 }
 func ParseReassembledTraffic(data []byte) ([]any, error) {
-	for _, ruleName := range []string{"http", "tls"} {
+	// 按照特征的严格程度排列，mysql 只能校验长度字段，容易误识别，放在靠后的位置
+	for _, ruleName := range []string{"http", "smb2", "kerberos", "postgresql", "resp", "mysql", "tls"} {
 		info, err := ParseTraffic(data, "application-layer."+ruleName)
 		if err == nil {
 			return info, nil
@@ -2,9 +2,14 @@ package yakgrpc
 
 import (
 	"context"
+	"testing"
+
 	"github.com/davecgh/go-spew/spew"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yak/yaklib/codec"
 	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
-	"testing"
 )
 
 func TestServer_PcapX(t *testing.T) {
@@ -18,3 +23,22 @@ func TestServer_PcapX(t *testing.T) {
 	}
 	spew.Dump(rsp)
 }
+
+func TestParseReassembledTraffic(t *testing.T) {
+	for protocol, data := range map[string]string{
+		"SMB2":             "00000068fe534d4240000000000000000000010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000024000200010000000000000011111111111111111111111111111111000000000000000002021002",
+		"Kerberos Message": "0000000e6a0c300aa103020105a20302010a",
+		"PostgreSQL":       "0000002500030000" + codec.EncodeToHex("user\x00postgres\x00database\x00test\x00\x00"),
+		"RESP":             codec.EncodeToHex("*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n"),
+		"MySQL":            "210000000373656c65637420404076657273696f6e5f636f6d6d656e74206c696d69742031",
+	} {
+		payload, err := codec.DecodeHex(data)
+		require.NoError(t, err)
+		info, err := ParseReassembledTraffic(payload)
+		require.NoError(t, err, protocol)
+		require.NotEmpty(t, info, protocol)
+		last, ok := utils.GetLastElement(info).(map[string]any)
+		require.True(t, ok, protocol)
+		assert.Equal(t, protocol, last["name"])
+	}
+}
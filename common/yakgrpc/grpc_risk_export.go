@@ -0,0 +1,79 @@
+package yakgrpc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+const exportRisksChunkSize = 64 * 1024
+
+func (s *Server) ExportRisks(req *ypb.ExportRisksRequest, stream ypb.Yak_ExportRisksServer) error {
+	if req.GetFormat() == "" {
+		return utils.Error("empty export format")
+	}
+	feedProgress := func(percent float64, verbose string) {
+		stream.Send(&ypb.ExportRisksResponse{Percent: percent, Verbose: verbose})
+	}
+
+	feedProgress(0, "开始读取漏洞")
+	risks, err := yakit.QueryRisksForExport(s.GetProjectDatabase(), stream.Context(), req.GetFilter(), func(count, total int) {
+		if count%100 == 0 && total > 0 {
+			feedProgress(0.8*float64(count)/float64(total), fmt.Sprintf("已读取 %d/%d 条漏洞", count, total))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	feedProgress(0.8, fmt.Sprintf("读取 %d 条漏洞，开始导出", len(risks)))
+	raw, failures, err := yakit.ExportRisks(risks, &yakit.RiskExportConfig{
+		Format:       req.GetFormat(),
+		FailSeverity: req.GetFailSeverity(),
+		ToolVersion:  consts.GetYakVersion(),
+	})
+	if err != nil {
+		return err
+	}
+
+	targetPath := req.GetTargetPath()
+	if targetPath == "" {
+		targetPath = filepath.Join(
+			consts.GetDefaultYakitBaseTempDir(),
+			fmt.Sprintf("risks-%s-%s%s", req.GetFormat(), time.Now().Format("20060102150405"), yakit.RiskExportFileExt(req.GetFormat())),
+		)
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
+		return utils.Errorf("create export dir failed: %s", err)
+	}
+	if err := os.WriteFile(targetPath, raw, 0o644); err != nil {
+		return utils.Errorf("write export file failed: %s", err)
+	}
+
+	for start := 0; start < len(raw); start += exportRisksChunkSize {
+		end := start + exportRisksChunkSize
+		if end > len(raw) {
+			end = len(raw)
+		}
+		if err := stream.Send(&ypb.ExportRisksResponse{
+			Percent:    0.9,
+			TargetPath: targetPath,
+			Data:       raw[start:end],
+		}); err != nil {
+			return err
+		}
+	}
+	return stream.Send(&ypb.ExportRisksResponse{
+		Percent:    1,
+		Verbose:    fmt.Sprintf("导出完成，共 %d 条漏洞，%d 条未通过", len(risks), failures),
+		TargetPath: targetPath,
+		Total:      int64(len(risks)),
+		Failures:   int64(failures),
+	})
+}
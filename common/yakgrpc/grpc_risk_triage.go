@@ -0,0 +1,123 @@
+package yakgrpc
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/yakit"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+func (s *Server) UpdateRiskTriage(ctx context.Context, req *ypb.UpdateRiskTriageRequest) (*ypb.Empty, error) {
+	err := yakit.UpdateRiskTriage(s.GetProjectDatabase(), &yakit.RiskTriageUpdate{
+		Ids:           req.GetIds(),
+		TriageStatus:  req.GetTriageStatus(),
+		Assignee:      req.GetAssignee(),
+		ClearAssignee: req.GetClearAssignee(),
+		Comment:       req.GetComment(),
+		Operator:      req.GetOperator(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ypb.Empty{}, nil
+}
+
+func (s *Server) QueryRiskTriageHistory(ctx context.Context, req *ypb.QueryRiskTriageHistoryRequest) (*ypb.QueryRiskTriageHistoryResponse, error) {
+	if req.GetRiskId() <= 0 {
+		return nil, utils.Error("empty risk id")
+	}
+	histories, err := yakit.QueryRiskTriageHistory(s.GetProjectDatabase(), req.GetRiskId())
+	if err != nil {
+		return nil, err
+	}
+	var data []*ypb.RiskTriageHistory
+	for _, h := range histories {
+		data = append(data, h.ToGRPCModel())
+	}
+	return &ypb.QueryRiskTriageHistoryResponse{Data: data}, nil
+}
+
+// retestRiskStream 转发 ExecPacketScan 的输出，同时记录本次复测的 runtime id
+type retestRiskStream struct {
+	ypb.Yak_RetestRiskServer
+
+	mutex     *sync.Mutex
+	runtimeId string
+}
+
+func (r *retestRiskStream) Send(result *ypb.ExecResult) error {
+	r.mutex.Lock()
+	if r.runtimeId == "" && result.GetRuntimeID() != "" {
+		r.runtimeId = result.GetRuntimeID()
+	}
+	r.mutex.Unlock()
+	return r.Yak_RetestRiskServer.Send(result)
+}
+
+func (r *retestRiskStream) getRuntimeId() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.runtimeId
+}
+
+// RetestRisk 使用发现漏洞的插件重放原始请求，根据是否再次发现同一指纹的漏洞更新研判状态
+func (s *Server) RetestRisk(req *ypb.RetestRiskRequest, stream ypb.Yak_RetestRiskServer) error {
+	db := s.GetProjectDatabase()
+	risk, err := yakit.GetRisk(db, req.GetId())
+	if err != nil {
+		return err
+	}
+	if risk.FromYakScript == "" {
+		return utils.Errorf("risk[%v] has no source plugin, cannot retest", risk.ID)
+	}
+	packet, err := strconv.Unquote(risk.QuotedRequest)
+	if err != nil {
+		packet = risk.QuotedRequest
+	}
+	if packet == "" {
+		return utils.Errorf("risk[%v] has no request packet, cannot retest", risk.ID)
+	}
+
+	// 历史数据没有指纹，补上后复测结果才能合并回这条记录
+	if risk.Fingerprint == "" {
+		risk.Fingerprint = yakit.CalcRiskFingerprint(risk)
+		if db := db.Model(&yakit.Risk{}).Where("id = ?", risk.ID).Update("fingerprint", risk.Fingerprint); db.Error != nil {
+			return utils.Errorf("update risk fingerprint failed: %s", db.Error)
+		}
+	}
+
+	isHttps := false
+	if u, err := url.Parse(risk.Url); err == nil {
+		isHttps = strings.EqualFold(u.Scheme, "https")
+	}
+
+	wrapper := &retestRiskStream{Yak_RetestRiskServer: stream, mutex: new(sync.Mutex)}
+	err = s.ExecPacketScan(&ypb.ExecPacketScanRequest{
+		HTTPRequest:         []byte(packet),
+		HTTPS:               isHttps,
+		TotalTimeoutSeconds: req.GetTotalTimeoutSeconds(),
+		PluginList:          []string{risk.FromYakScript},
+	}, wrapper)
+	if err != nil {
+		return utils.Errorf("retest risk[%v] failed: %s", risk.ID, err)
+	}
+
+	runtimeId := wrapper.getRuntimeId()
+	if runtimeId == "" {
+		return utils.Errorf("retest risk[%v] failed: no runtime id", risk.ID)
+	}
+	var count int
+	if db := db.Model(&yakit.Risk{}).Where(
+		"fingerprint = ? AND (runtime_id = ? OR last_runtime_id = ?)", risk.Fingerprint, runtimeId, runtimeId,
+	).Count(&count); db.Error != nil {
+		return utils.Errorf("query retest result failed: %s", db.Error)
+	}
+
+	_, err = yakit.ApplyRiskRetestResult(db, int64(risk.ID), count > 0, runtimeId, req.GetOperator())
+	return err
+}
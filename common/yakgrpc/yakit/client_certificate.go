@@ -0,0 +1,254 @@
+package yakit
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/bizhelper"
+	"github.com/yaklang/yaklang/common/utils/lowhttp"
+	"github.com/yaklang/yaklang/common/utils/tlsutils"
+	"github.com/yaklang/yaklang/common/utils/tlsutils/go-pkcs12"
+)
+
+// ClientCertificate 为保存在用户数据库中的 mTLS 客户端证书，HTTP 请求可以按名称或按 host 自动选择使用
+type ClientCertificate struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"unique_index"`
+	// HostPattern 为逗号分隔的 glob（匹配 host 或 host:port），为空时只能按名称显式使用
+	HostPattern string `json:"host_pattern"`
+
+	CertPEM string `json:"cert_pem"`
+	KeyPEM  string `json:"key_pem"`
+	// ChainPEM 为证书链中的其他证书
+	ChainPEM string `json:"chain_pem"`
+
+	Subject     string `json:"subject"`
+	Issuer      string `json:"issuer"`
+	NotAfter    int64  `json:"not_after"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+func (c *ClientCertificate) HostPatterns() []string {
+	return utils.PrettifyListFromStringSplited(c.HostPattern, ",")
+}
+
+// TLSCertificate 将证书与证书链解析为 tls.Certificate
+func (c *ClientCertificate) TLSCertificate() (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair([]byte(c.CertPEM+"\n"+c.ChainPEM), []byte(c.KeyPEM))
+	if err != nil {
+		return nil, utils.Wrapf(err, "parse client certificate %v failed", c.Name)
+	}
+	return &cert, nil
+}
+
+func init() {
+	lowhttp.RegisterClientCertificateStore(&clientCertificateStore{})
+}
+
+// NewClientCertificateFromPEM 校验证书与私钥是否匹配并生成 ClientCertificate，certPEM 中的第一个证书为客户端证书，其余的作为证书链
+func NewClientCertificateFromPEM(name string, certPEM, keyPEM []byte, hostPatterns ...string) (*ClientCertificate, error) {
+	if name == "" {
+		return nil, utils.Error("client certificate name is empty")
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, utils.Wrap(err, "invalid client certificate/key pair")
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, utils.Wrap(err, "parse client certificate failed")
+	}
+	var ca bytes.Buffer
+	for _, der := range pair.Certificate[1:] {
+		pem.Encode(&ca, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	fingerprint := sha256.Sum256(leaf.Raw)
+	return &ClientCertificate{
+		Name:        name,
+		HostPattern: strings.Join(utils.StringArrayFilterEmpty(hostPatterns), ","),
+		CertPEM:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})),
+		KeyPEM:      string(keyPEM),
+		ChainPEM:    ca.String(),
+		Subject:     leaf.Subject.String(),
+		Issuer:      leaf.Issuer.String(),
+		NotAfter:    leaf.NotAfter.Unix(),
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+	}, nil
+}
+
+// ImportClientCertificateFromPEM 导入 PEM 格式的客户端证书，同名证书会被覆盖
+func ImportClientCertificateFromPEM(db *gorm.DB, name string, certPEM, keyPEM []byte, hostPatterns ...string) (*ClientCertificate, error) {
+	cert, err := NewClientCertificateFromPEM(name, certPEM, keyPEM, hostPatterns...)
+	if err != nil {
+		return nil, err
+	}
+	return cert, CreateOrUpdateClientCertificate(db, cert)
+}
+
+// ImportClientCertificateFromP12 导入 p12/pfx 格式的客户端证书，同名证书会被覆盖
+func ImportClientCertificateFromP12(db *gorm.DB, name string, p12 []byte, password string, hostPatterns ...string) (*ClientCertificate, error) {
+	certPEM, keyPEM, ca, err := tlsutils.LoadP12ToPEM(p12, password)
+	if err != nil {
+		return nil, utils.Wrap(err, "load p12 failed")
+	}
+	return ImportClientCertificateFromPEM(db, name, append(certPEM, bytes.Join(ca, nil)...), keyPEM, hostPatterns...)
+}
+
+func CreateOrUpdateClientCertificate(db *gorm.DB, cert *ClientCertificate) error {
+	db = UserDataAndPluginDatabaseScope(db)
+	db = db.Model(&ClientCertificate{})
+	if db := db.Where("name = ?", cert.Name).Assign(map[string]interface{}{
+		"name":         cert.Name,
+		"host_pattern": cert.HostPattern,
+		"cert_pem":     cert.CertPEM,
+		"key_pem":      cert.KeyPEM,
+		"chain_pem":    cert.ChainPEM,
+		"subject":      cert.Subject,
+		"issuer":       cert.Issuer,
+		"not_after":    cert.NotAfter,
+		"fingerprint":  cert.Fingerprint,
+	}).FirstOrCreate(&ClientCertificate{}); db.Error != nil {
+		return utils.Errorf("create/update client certificate failed: %s", db.Error)
+	}
+	clientCertificateCache.Purge()
+	return nil
+}
+
+// SetClientCertificateHostPattern 修改证书自动匹配的 host，传入空时取消自动匹配
+func SetClientCertificateHostPattern(db *gorm.DB, name string, hostPatterns ...string) error {
+	db = UserDataAndPluginDatabaseScope(db)
+	db = db.Model(&ClientCertificate{}).Where("name = ?", name).Update("host_pattern", strings.Join(utils.StringArrayFilterEmpty(hostPatterns), ","))
+	if db.Error != nil {
+		return utils.Errorf("update client certificate host pattern failed: %s", db.Error)
+	}
+	if db.RowsAffected <= 0 {
+		return utils.Errorf("client certificate %v not found", name)
+	}
+	clientCertificateCache.Purge()
+	return nil
+}
+
+func GetClientCertificateByName(db *gorm.DB, name string) (*ClientCertificate, error) {
+	db = UserDataAndPluginDatabaseScope(db)
+	var cert ClientCertificate
+	if db := db.Model(&ClientCertificate{}).Where("name = ?", name).First(&cert); db.Error != nil {
+		return nil, utils.Errorf("get client certificate %v failed: %s", name, db.Error)
+	}
+	return &cert, nil
+}
+
+func QueryClientCertificates(db *gorm.DB) ([]*ClientCertificate, error) {
+	db = UserDataAndPluginDatabaseScope(db)
+	var certs []*ClientCertificate
+	db = db.Model(&ClientCertificate{})
+	db = bizhelper.QueryOrder(db, "id", "asc")
+	if db := db.Find(&certs); db.Error != nil {
+		return nil, utils.Errorf("query client certificates failed: %s", db.Error)
+	}
+	return certs, nil
+}
+
+func DeleteClientCertificateByName(db *gorm.DB, name string) error {
+	db = UserDataAndPluginDatabaseScope(db)
+	if db := db.Model(&ClientCertificate{}).Where("name = ?", name).Unscoped().Delete(&ClientCertificate{}); db.Error != nil {
+		return utils.Errorf("delete client certificate %v failed: %s", name, db.Error)
+	}
+	clientCertificateCache.Purge()
+	return nil
+}
+
+// ExportClientCertificateToPEM 导出证书（包含证书链）与私钥
+func ExportClientCertificateToPEM(db *gorm.DB, name string) (certPEM []byte, keyPEM []byte, _ error) {
+	cert, err := GetClientCertificateByName(db, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(cert.CertPEM + cert.ChainPEM), []byte(cert.KeyPEM), nil
+}
+
+// ExportClientCertificateToP12 以 password 加密导出 p12 格式的证书
+func ExportClientCertificateToP12(db *gorm.DB, name string, password string) ([]byte, error) {
+	cert, err := GetClientCertificateByName(db, name)
+	if err != nil {
+		return nil, err
+	}
+	pair, err := cert.TLSCertificate()
+	if err != nil {
+		return nil, err
+	}
+	var certs []*x509.Certificate
+	for _, der := range pair.Certificate {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, utils.Wrap(err, "parse certificate failed")
+		}
+		certs = append(certs, c)
+	}
+	return pkcs12.Encode(rand.Reader, pair.PrivateKey, certs[0], certs[1:], password)
+}
+
+// clientCertificateCache 缓存解析后的证书与 host 规则，证书变更时清空
+var clientCertificateCache = utils.NewTTLCache[any](time.Minute)
+
+const clientCertificateRulesKey = "__rules__"
+
+// clientCertificateStore 为 lowhttp 提供按名称与按 host 选择证书的能力
+type clientCertificateStore struct{}
+
+func (s *clientCertificateStore) GetClientCertificate(name string) (*tls.Certificate, error) {
+	if cached, ok := clientCertificateCache.Get("cert:" + name); ok {
+		return cached.(*tls.Certificate), nil
+	}
+	cert, err := GetClientCertificateByName(consts.GetGormProfileDatabase(), name)
+	if err != nil {
+		return nil, err
+	}
+	if cert.NotAfter > 0 && time.Now().Unix() > cert.NotAfter {
+		log.Warnf("client certificate %v is expired at %v", name, time.Unix(cert.NotAfter, 0))
+	}
+	pair, err := cert.TLSCertificate()
+	if err != nil {
+		return nil, err
+	}
+	clientCertificateCache.Set("cert:"+name, pair)
+	return pair, nil
+}
+
+func (s *clientCertificateStore) MatchClientCertificate(host string, port int) string {
+	var rules []*lowhttp.ClientCertificateRule
+	if cached, ok := clientCertificateCache.Get(clientCertificateRulesKey); ok {
+		rules = cached.([]*lowhttp.ClientCertificateRule)
+	} else {
+		db := consts.GetGormProfileDatabase()
+		if db == nil {
+			return ""
+		}
+		var certs []*ClientCertificate
+		if db := db.Model(&ClientCertificate{}).Select("name, host_pattern").Where("host_pattern <> ''").Order("id asc").Find(&certs); db.Error != nil {
+			log.Errorf("query client certificate rules failed: %s", db.Error)
+			return ""
+		}
+		for _, cert := range certs {
+			rules = append(rules, &lowhttp.ClientCertificateRule{Name: cert.Name, HostPatterns: cert.HostPatterns()})
+		}
+		clientCertificateCache.Set(clientCertificateRulesKey, rules)
+	}
+	for _, rule := range rules {
+		if rule.Match(host, port) {
+			return rule.Name
+		}
+	}
+	return ""
+}
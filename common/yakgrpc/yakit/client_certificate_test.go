@@ -0,0 +1,67 @@
+package yakit
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/utils/tlsutils"
+)
+
+func TestClientCertificateStore(t *testing.T) {
+	InitialDatabase()
+	db := consts.GetGormProfileDatabase()
+
+	ca, caKey, err := tlsutils.GenerateSelfSignedCertKeyWithCommonName("mtls-ca", "", nil, nil)
+	require.NoError(t, err)
+	cert, key, err := tlsutils.SignClientCrtNKeyWithParams(ca, caKey, "tenant", time.Now().Add(time.Hour), true)
+	require.NoError(t, err)
+
+	name := "test-" + utils.RandStringBytes(8)
+	p12Name := name + "-p12"
+	defer DeleteClientCertificateByName(db, name)
+	defer DeleteClientCertificateByName(db, p12Name)
+
+	_, err = ImportClientCertificateFromPEM(db, name, cert, []byte("bad key"))
+	require.Error(t, err)
+	imported, err := ImportClientCertificateFromPEM(db, name, append(cert, ca...), key, "*."+name+".com")
+	require.NoError(t, err)
+	assert.Equal(t, "CN=tenant", imported.Subject)
+	assert.Contains(t, imported.ChainPEM, "CERTIFICATE")
+
+	// p12 导出后重新导入得到相同的证书
+	p12, err := ExportClientCertificateToP12(db, name, "secret")
+	require.NoError(t, err)
+	_, err = ImportClientCertificateFromP12(db, p12Name, p12, "wrong")
+	require.Error(t, err)
+	_, err = ImportClientCertificateFromP12(db, p12Name, p12, "secret")
+	require.NoError(t, err)
+	fromP12, err := GetClientCertificateByName(db, p12Name)
+	require.NoError(t, err)
+	assert.Equal(t, imported.Fingerprint, fromP12.Fingerprint)
+	assert.Equal(t, imported.ChainPEM, fromP12.ChainPEM)
+
+	exportCert, exportKey, err := ExportClientCertificateToPEM(db, name)
+	require.NoError(t, err)
+	_, err = tls.X509KeyPair(exportCert, exportKey)
+	require.NoError(t, err)
+
+	store := &clientCertificateStore{}
+	assert.Equal(t, name, store.MatchClientCertificate("api."+name+".com", 443))
+	assert.Equal(t, "", store.MatchClientCertificate(name+".org", 443))
+	pair, err := store.GetClientCertificate(name)
+	require.NoError(t, err)
+	assert.Len(t, pair.Certificate, 2)
+
+	require.NoError(t, SetClientCertificateHostPattern(db, name, name+".org"))
+	assert.Equal(t, "", store.MatchClientCertificate("api."+name+".com", 443))
+	assert.Equal(t, name, store.MatchClientCertificate(name+".org", 443))
+
+	require.NoError(t, DeleteClientCertificateByName(db, name))
+	_, err = store.GetClientCertificate(name)
+	require.Error(t, err)
+}
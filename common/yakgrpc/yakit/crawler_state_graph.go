@@ -0,0 +1,41 @@
+package yakit
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// CrawlerStateGraph 为 crawlerx 爬取得到的页面状态图，用于导出与增量爬取
+type CrawlerStateGraph struct {
+	gorm.Model
+
+	Target          string `json:"target" gorm:"index"`
+	RuntimeId       string `json:"runtime_id" gorm:"index"`
+	StateCount      int    `json:"state_count"`
+	TransitionCount int    `json:"transition_count"`
+	// Graph 为 JSON 格式的状态图
+	Graph string `json:"graph"`
+}
+
+func SaveCrawlerStateGraph(db *gorm.DB, graph *CrawlerStateGraph) error {
+	if db := db.Model(&CrawlerStateGraph{}).Save(graph); db.Error != nil {
+		return utils.Errorf("save crawler state graph failed: %s", db.Error)
+	}
+	return nil
+}
+
+// GetLatestCrawlerStateGraph 获取 target 最近一次爬取的状态图
+func GetLatestCrawlerStateGraph(db *gorm.DB, target string) (*CrawlerStateGraph, error) {
+	var graph CrawlerStateGraph
+	if db := db.Model(&CrawlerStateGraph{}).Where("target = ?", target).Order("id desc").First(&graph); db.Error != nil {
+		return nil, utils.Errorf("get crawler state graph of %v failed: %s", target, db.Error)
+	}
+	return &graph, nil
+}
+
+func DeleteCrawlerStateGraphByTarget(db *gorm.DB, target string) error {
+	if db := db.Model(&CrawlerStateGraph{}).Where("target = ?", target).Unscoped().Delete(&CrawlerStateGraph{}); db.Error != nil {
+		return utils.Errorf("delete crawler state graph of %v failed: %s", target, db.Error)
+	}
+	return nil
+}
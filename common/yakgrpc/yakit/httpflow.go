@@ -21,6 +21,7 @@ import (
 	"github.com/yaklang/yaklang/common/consts"
 	"github.com/yaklang/yaklang/common/domainextractor"
 	"github.com/yaklang/yaklang/common/go-funk"
+	"github.com/yaklang/yaklang/common/grpcx"
 	"github.com/yaklang/yaklang/common/jsonextractor"
 	"github.com/yaklang/yaklang/common/log"
 	"github.com/yaklang/yaklang/common/mutate"
@@ -45,7 +46,7 @@ func yakitColor(i string) string {
 
 func init() {
 	RegisterPostInitDatabaseFunction(func() error {
-		lowhttp.RegisterSaveHTTPFlowHandler(func(https bool, req []byte, rsp []byte, url string, remoteAddr string, reqSource string, runtimeId string, fromPlugin string) {
+		lowhttp.RegisterSaveHTTPFlowHandler(func(https bool, req []byte, rsp []byte, url string, remoteAddr string, reqSource string, runtimeId string, fromPlugin string, clientCertificate string) {
 			if rsp == nil || len(rsp) == 0 {
 				return
 			}
@@ -71,8 +72,12 @@ func init() {
 				flow.SourceType = "scan"
 
 			}
+			if proto, _, _ := lowhttp.GetHTTPPacketFirstLine(rsp); strings.HasPrefix(proto, "HTTP/3") {
+				flow.AddTag("HTTP/3")
+			}
 			flow.FromPlugin = fromPlugin
 			flow.RuntimeId = runtimeId
+			flow.ClientCertificate = clientCertificate
 			flow.HiddenIndex = uuid.New().String()
 			err = InsertHTTPFlow(db, flow)
 			if err != nil {
@@ -115,6 +120,9 @@ type HTTPFlow struct {
 	RuntimeId  string
 	FromPlugin string
 
+	// 请求使用的 mTLS 客户端证书名称
+	ClientCertificate string
+
 	// friendly for gorm build instance, not for store
 	// 这两个字段不参与数据库存储，但是在序列化的时候，会被覆盖
 	// 主要用来标记用户的 Request 和 Response 是否超大
@@ -492,6 +500,24 @@ func (f *HTTPFlow) toGRPCModel(full bool) (*ypb.HTTPFlow, error) {
 			}
 			flow.JsonObjects = append(flow.JsonObjects, utf8safe(j))
 		}
+
+		// gRPC 的 protobuf 消息没有 schema，按照字段编号渲染为 JSON
+		for _, packet := range []struct {
+			raw  []byte
+			body []byte
+		}{{flow.Request, requestBody}, {flow.Response, responseBody}} {
+			if len(packet.body) <= 0 || !grpcx.IsGRPCContentType(lowhttp.GetHTTPPacketContentType(packet.raw)) {
+				continue
+			}
+			msgs, err := grpcx.DecodeMessages(packet.body, nil)
+			if err != nil {
+				log.Debugf("decode grpc messages failed: %v", err)
+				continue
+			}
+			for _, msg := range msgs {
+				flow.JsonObjects = append(flow.JsonObjects, utf8safe(msg.ToJSON()))
+			}
+		}
 	}
 	f.setCacheGRPCModel(full, flow)
 	return flow, nil
@@ -15,7 +15,7 @@ var ProfileTables = []interface{}{
 	&GeneralStorage{}, &MarkdownDoc{},
 	&Project{},
 	&NavigationBar{}, &NaslScript{},
-	&WebFuzzerLabel{},
+	&WebFuzzerLabel{}, &ClientCertificate{},
 }
 
 func InitializeDefaultDatabaseSchema() {
@@ -37,7 +37,7 @@ var ProjectTables = []interface{}{
 	&Port{},
 	&Domain{}, &Host{},
 	&MarkdownDoc{}, &ExecResult{},
-	&Risk{}, &WebFuzzerTask{}, &WebFuzzerResponse{},
+	&Risk{}, &RiskTriageHistory{}, &WebFuzzerTask{}, &WebFuzzerResponse{},
 	&ReportRecord{}, &ScreenRecorder{},
 	&ProjectGeneralStorage{},
 	// rss
@@ -50,6 +50,12 @@ var ProjectTables = []interface{}{
 
 	// HybridScan
 	&HybridScanTask{},
+
+	// ssa
+	&SSAProgram{}, &SSASourceFile{}, &SSAIrCode{},
+
+	// crawler
+	&CrawlerStateGraph{},
 }
 
 func UserDataAndPluginDatabaseScope(db *gorm.DB) *gorm.DB {
@@ -175,6 +175,8 @@ type Report struct {
 	OwnerValue string        `json:"owner"`
 	FromValue  string        `json:"from"`
 	Items      []*ReportItem `json:"items"`
+
+	publishedAt time.Time
 }
 
 func NewReport() *Report {
@@ -389,6 +391,8 @@ func (r *ReportRecord) ToReport() (*Report, error) {
 		OwnerValue: r.Owner,
 		FromValue:  r.From,
 		Items:      items,
+
+		publishedAt: r.PublishedAt,
 	}
 	return reportIns, nil
 }
@@ -407,4 +411,17 @@ func (r *Report) Save() int {
 
 var ReportExports = map[string]interface{}{
 	"New": NewReport,
+
+	"RenderHTML": _renderReport(REPORT_RENDER_FORMAT_HTML),
+	"RenderDOCX": _renderReport(REPORT_RENDER_FORMAT_DOCX),
+	"RenderFile": _renderReportToFile,
+
+	"templateFile": _reportWithTemplateFile,
+	"companyName":  _reportWithCompanyName,
+	"logo":         _reportWithLogo,
+	"primaryColor": _reportWithPrimaryColor,
+	"header":       _reportWithHeader,
+	"footer":       _reportWithFooter,
+	"css":          _reportWithCSS,
+	"htmlLayout":   _reportWithHTMLLayout,
 }
@@ -0,0 +1,578 @@
+package yakit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+const (
+	REPORT_RENDER_FORMAT_HTML = "html"
+	REPORT_RENDER_FORMAT_DOCX = "docx"
+)
+
+// ReportTemplate 报告渲染模版，用于在 HTML / DOCX 中展示公司品牌信息
+type ReportTemplate struct {
+	CompanyName string `json:"company_name"`
+	// Logo 可以是图片文件路径，也可以是 data URI 或 base64 编码的图片
+	Logo         string `json:"logo"`
+	PrimaryColor string `json:"primary_color"`
+	Header       string `json:"header"`
+	Footer       string `json:"footer"`
+	// CSS 追加到 HTML 默认样式之后，DOCX 不使用
+	CSS string `json:"css"`
+	// HTMLLayout 为 html/template 模版，设置后替换 HTML 的默认页面布局
+	HTMLLayout string `json:"html_layout"`
+}
+
+type ReportRenderOption func(t *ReportTemplate)
+
+func NewReportTemplate(opts ...ReportRenderOption) *ReportTemplate {
+	t := &ReportTemplate{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *ReportTemplate) primaryColor() string {
+	if t == nil || t.PrimaryColor == "" {
+		return "#1677ff"
+	}
+	return t.PrimaryColor
+}
+
+// Merge 使用 other 中非空的字段覆盖当前模版
+func (t *ReportTemplate) Merge(other *ReportTemplate) {
+	if other == nil {
+		return
+	}
+	for _, pair := range [][2]*string{
+		{&t.CompanyName, &other.CompanyName},
+		{&t.Logo, &other.Logo},
+		{&t.PrimaryColor, &other.PrimaryColor},
+		{&t.Header, &other.Header},
+		{&t.Footer, &other.Footer},
+		{&t.CSS, &other.CSS},
+		{&t.HTMLLayout, &other.HTMLLayout},
+	} {
+		if *pair[1] != "" {
+			*pair[0] = *pair[1]
+		}
+	}
+}
+
+// logoData 读取 Logo 图片，返回图片内容与 MIME 类型
+func (t *ReportTemplate) logoData() ([]byte, string, error) {
+	if t == nil || t.Logo == "" {
+		return nil, "", nil
+	}
+	var raw []byte
+	switch {
+	case strings.HasPrefix(t.Logo, "data:"):
+		idx := strings.Index(t.Logo, ",")
+		if idx < 0 {
+			return nil, "", utils.Error("invalid logo data uri")
+		}
+		decoded, err := base64.StdEncoding.DecodeString(t.Logo[idx+1:])
+		if err != nil {
+			return nil, "", utils.Errorf("decode logo data uri failed: %s", err)
+		}
+		raw = decoded
+	case utils.GetFirstExistedFile(t.Logo) != "":
+		content, err := os.ReadFile(t.Logo)
+		if err != nil {
+			return nil, "", utils.Errorf("read logo file failed: %s", err)
+		}
+		raw = content
+	default:
+		decoded, err := base64.StdEncoding.DecodeString(t.Logo)
+		if err != nil {
+			return nil, "", utils.Errorf("logo is neither a file nor base64 image: %s", t.Logo)
+		}
+		raw = decoded
+	}
+	mimeType := http.DetectContentType(raw)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, "", utils.Errorf("logo is not an image: %s", mimeType)
+	}
+	return raw, mimeType, nil
+}
+
+// LoadReportTemplate 从 JSON 文件中读取报告模版
+func LoadReportTemplate(file string) (*ReportTemplate, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, utils.Errorf("read report template failed: %s", err)
+	}
+	var t ReportTemplate
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, utils.Errorf("unmarshal report template failed: %s", err)
+	}
+	// 相对路径的 Logo 以模版文件所在目录为准
+	if t.Logo != "" && !strings.HasPrefix(t.Logo, "data:") && !filepath.IsAbs(t.Logo) {
+		logo := filepath.Join(filepath.Dir(file), t.Logo)
+		if utils.GetFirstExistedFile(logo) != "" {
+			t.Logo = logo
+		}
+	}
+	return &t, nil
+}
+
+// report block 为渲染前统一的报告内容，HTML 与 DOCX 都基于它输出
+const (
+	reportBlockMarkdown = "markdown"
+	reportBlockDivider  = "divider"
+	reportBlockTable    = "table"
+	reportBlockDetail   = "detail"
+	reportBlockChart    = "chart"
+	reportBlockCode     = "code"
+	reportBlockCover    = "cover"
+)
+
+type reportCell struct {
+	Value string
+	Color string
+	Code  bool
+}
+
+type reportChartPair struct {
+	Key   string
+	Value float64
+	Color string
+}
+
+type reportBlock struct {
+	Kind    string
+	Title   string
+	Content string
+
+	// table / detail
+	Header []string
+	Rows   [][]*reportCell
+
+	// chart
+	ChartType   string
+	Pairs       []*reportChartPair
+	CenterLabel string
+	CenterValue float64
+}
+
+type reportCoverLevel struct {
+	Verbose string
+	Color   string
+}
+
+var reportCoverLevels = map[string]*reportCoverLevel{
+	"critical": {Verbose: "严重", Color: "#8B0000"},
+	"high":     {Verbose: "高危", Color: "#FF4500"},
+	"warning":  {Verbose: "中危", Color: "#FFA500"},
+	"low":      {Verbose: "低危", Color: "#FDD338"},
+	"security": {Verbose: "安全", Color: "#43ab42"},
+}
+
+func (r *Report) renderBlocks() []*reportBlock {
+	var blocks []*reportBlock
+	for _, item := range r.Items {
+		if item == nil {
+			continue
+		}
+		blocks = append(blocks, parseReportItem(item)...)
+	}
+	return blocks
+}
+
+func parseReportItem(item *ReportItem) []*reportBlock {
+	switch item.Type {
+	case REPORT_ITEM_TYPE_MARKDOWN:
+		return []*reportBlock{{Kind: reportBlockMarkdown, Content: item.Content}}
+	case REPORT_ITEM_TYPE_DIVIDER:
+		return []*reportBlock{{Kind: reportBlockDivider}}
+	case REPORT_ITEM_TYPE_CODE:
+		return []*reportBlock{{Kind: reportBlockCode, Content: item.Content}}
+	case REPORT_ITEM_TYPE_TABLE, REPORT_ITEM_SEARCH_TYPE_TABLE:
+		var table struct {
+			Header []string   `json:"header"`
+			Data   [][]string `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(item.Content), &table); err != nil {
+			return []*reportBlock{{Kind: reportBlockCode, Content: item.Content}}
+		}
+		block := &reportBlock{Kind: reportBlockTable, Header: table.Header}
+		for _, line := range table.Data {
+			var row []*reportCell
+			for _, cell := range line {
+				row = append(row, &reportCell{Value: cell})
+			}
+			block.Rows = append(block.Rows, row)
+		}
+		return []*reportBlock{block}
+	case REPORT_ITEM_TYPE_PIE_GRAPH, REPORT_ITEM_TYPE_VERTICAL_BAR_GRAPH,
+		REPORT_ITEM_TYPE_HORIZONTAL_BAR_GRAPH, REPORT_ITEM_TYPE_WORDCLOUD:
+		var pairs []*graphKVPair
+		if err := json.Unmarshal([]byte(item.Content), &pairs); err != nil {
+			return []*reportBlock{{Kind: reportBlockCode, Content: item.Content}}
+		}
+		block := &reportBlock{Kind: reportBlockChart, ChartType: item.Type}
+		for _, pair := range pairs {
+			block.Pairs = append(block.Pairs, &reportChartPair{Key: pair.Key, Value: float64(pair.Value)})
+		}
+		return []*reportBlock{block}
+	case REPORT_ITEM_TYPE_RAW:
+		return parseReportRawItem(item.Content)
+	default:
+		return []*reportBlock{{Kind: reportBlockCode, Content: item.Content}}
+	}
+}
+
+// parseReportRawItem 解析 Raw 中前端约定的结构化组件，无法识别的内容按 JSON 代码块展示
+func parseReportRawItem(content string) []*reportBlock {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return []*reportBlock{{Kind: reportBlockCode, Content: content}}
+	}
+	// Raw(json.dumps(...)) 会把 JSON 字符串再编码一次
+	if s, ok := raw.(string); ok {
+		var inner interface{}
+		if err := json.Unmarshal([]byte(s), &inner); err != nil {
+			return []*reportBlock{{Kind: reportBlockCode, Content: s}}
+		}
+		raw = inner
+	}
+
+	fallback := func() []*reportBlock {
+		pretty, _ := json.MarshalIndent(raw, "", "  ")
+		return []*reportBlock{{Kind: reportBlockCode, Content: string(pretty)}}
+	}
+
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		if list, ok := raw.([]interface{}); ok {
+			if block := parseReportRecordTable(list); block != nil {
+				return []*reportBlock{block}
+			}
+		}
+		return fallback()
+	}
+
+	title := utils.MapGetString(m, "title")
+	data := m["data"]
+	switch utils.MapGetString(m, "type") {
+	case "report-cover":
+		return []*reportBlock{{Kind: reportBlockCover, Content: fmt.Sprint(data)}}
+	case "pie-graph", "bar-graph":
+		chartType := REPORT_ITEM_TYPE_PIE_GRAPH
+		if utils.MapGetString(m, "type") == "bar-graph" {
+			chartType = REPORT_ITEM_TYPE_VERTICAL_BAR_GRAPH
+		}
+		block := &reportBlock{Kind: reportBlockChart, ChartType: chartType, Title: title}
+		var colors []string
+		if list, ok := m["color"].([]interface{}); ok {
+			for _, c := range list {
+				colors = append(colors, fmt.Sprint(c))
+			}
+		}
+		list, _ := data.([]interface{})
+		for _, i := range list {
+			pair := utils.InterfaceToGeneralMap(i)
+			if pair == nil {
+				continue
+			}
+			key := utils.InterfaceToString(utils.MapGetFirstRaw(pair, "name", "key"))
+			value := reportFloat(utils.MapGetFirstRaw(pair, "value", "data"))
+			// direction 为 center 的项是环形图中间的汇总数据
+			if utils.MapGetString(pair, "direction") == "center" {
+				block.CenterLabel, block.CenterValue = key, value
+				continue
+			}
+			color := utils.MapGetString(pair, "color")
+			if color == "" && len(block.Pairs) < len(colors) {
+				color = colors[len(block.Pairs)]
+			}
+			block.Pairs = append(block.Pairs, &reportChartPair{Key: key, Value: value, Color: color})
+		}
+		return []*reportBlock{block}
+	case "fix-list":
+		if record, ok := data.(map[string]interface{}); ok {
+			return []*reportBlock{parseReportRecordDetail(title, record)}
+		}
+	case "fix-array-list":
+		list, _ := data.([]interface{})
+		var blocks []*reportBlock
+		if title != "" {
+			blocks = append(blocks, &reportBlock{Kind: reportBlockMarkdown, Content: "#### " + title})
+		}
+		for _, i := range list {
+			if record, ok := i.(map[string]interface{}); ok {
+				blocks = append(blocks, parseReportRecordDetail("", record))
+			}
+		}
+		if len(blocks) > 0 {
+			return blocks
+		}
+	default:
+		if list, ok := data.([]interface{}); ok {
+			if block := parseReportRecordTable(list); block != nil {
+				block.Title = title
+				return []*reportBlock{block}
+			}
+		}
+	}
+	return fallback()
+}
+
+type reportRecordField struct {
+	Name string
+	Sort float64
+	Cell *reportCell
+}
+
+// parseReportRecord 解析形如 {"字段": {"value": ..., "sort": 1, "color": ..., "type": "code"}} 的记录，按 sort 排序
+func parseReportRecord(record map[string]interface{}) []*reportRecordField {
+	var fields []*reportRecordField
+	for name, value := range record {
+		field := &reportRecordField{Name: name, Sort: 1 << 20, Cell: &reportCell{}}
+		if m, ok := value.(map[string]interface{}); ok {
+			if v, ok := m["value"]; ok {
+				if s, ok := m["sort"]; ok {
+					field.Sort = reportFloat(s)
+				}
+				field.Cell.Value = reportValueString(v)
+				field.Cell.Color = utils.MapGetString(m, "color")
+				field.Cell.Code = utils.MapGetString(m, "type") == "code"
+				fields = append(fields, field)
+				continue
+			}
+		}
+		field.Cell.Value = reportValueString(value)
+		fields = append(fields, field)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].Sort != fields[j].Sort {
+			return fields[i].Sort < fields[j].Sort
+		}
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
+
+func reportFloat(v interface{}) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(v)), 64)
+	return f
+}
+
+func reportValueString(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	default:
+		return utils.InterfaceToString(v)
+	}
+}
+
+func parseReportRecordDetail(title string, record map[string]interface{}) *reportBlock {
+	block := &reportBlock{Kind: reportBlockDetail, Title: title}
+	for _, field := range parseReportRecord(record) {
+		block.Rows = append(block.Rows, []*reportCell{{Value: field.Name}, field.Cell})
+	}
+	return block
+}
+
+func parseReportRecordTable(list []interface{}) *reportBlock {
+	var records [][]*reportRecordField
+	columns := make(map[string]float64)
+	for _, i := range list {
+		record, ok := i.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := parseReportRecord(record)
+		for _, field := range fields {
+			if s, ok := columns[field.Name]; !ok || field.Sort < s {
+				columns[field.Name] = field.Sort
+			}
+		}
+		records = append(records, fields)
+	}
+	if len(columns) <= 0 {
+		return nil
+	}
+
+	block := &reportBlock{Kind: reportBlockTable}
+	for name := range columns {
+		block.Header = append(block.Header, name)
+	}
+	sort.SliceStable(block.Header, func(i, j int) bool {
+		a, b := block.Header[i], block.Header[j]
+		if columns[a] != columns[b] {
+			return columns[a] < columns[b]
+		}
+		return a < b
+	})
+	for _, fields := range records {
+		row := make([]*reportCell, len(block.Header))
+		for index, name := range block.Header {
+			row[index] = &reportCell{}
+			for _, field := range fields {
+				if field.Name == name {
+					row[index] = field.Cell
+					break
+				}
+			}
+		}
+		block.Rows = append(block.Rows, row)
+	}
+	return block
+}
+
+// RenderReport 把报告渲染为 html 或 docx
+func RenderReport(r *Report, format string, t *ReportTemplate) ([]byte, error) {
+	if r == nil {
+		return nil, utils.Error("empty report")
+	}
+	if t == nil {
+		t = &ReportTemplate{}
+	}
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case REPORT_RENDER_FORMAT_HTML, "htm":
+		return RenderReportHTML(r, t)
+	case REPORT_RENDER_FORMAT_DOCX:
+		return RenderReportDOCX(r, t)
+	default:
+		return nil, utils.Errorf("unsupported report format: %v", format)
+	}
+}
+
+func (r *Report) publishedTime() time.Time {
+	if r.publishedAt.IsZero() {
+		return time.Now()
+	}
+	return r.publishedAt
+}
+
+// loadReport 支持报告 ID、Hash、*Report 以及 *ReportRecord
+func loadReport(i interface{}) (*Report, error) {
+	switch ret := i.(type) {
+	case *Report:
+		return ret, nil
+	case *ReportRecord:
+		return ret.ToReport()
+	case string:
+		db := consts.GetGormProjectDatabase()
+		if db == nil {
+			return nil, utils.Error("no database connection")
+		}
+		record, err := GetReportRecordByHash(db, ret)
+		if err != nil {
+			return nil, err
+		}
+		return record.ToReport()
+	default:
+		id := utils.InterfaceToInt(i)
+		if id <= 0 {
+			return nil, utils.Errorf("invalid report: %v", i)
+		}
+		db := consts.GetGormProjectDatabase()
+		if db == nil {
+			return nil, utils.Error("no database connection")
+		}
+		record, err := GetReportRecord(db, int64(id))
+		if err != nil {
+			return nil, err
+		}
+		return record.ToReport()
+	}
+}
+
+func _renderReport(format string) func(i interface{}, opts ...ReportRenderOption) ([]byte, error) {
+	return func(i interface{}, opts ...ReportRenderOption) ([]byte, error) {
+		r, err := loadReport(i)
+		if err != nil {
+			return nil, err
+		}
+		return RenderReport(r, format, NewReportTemplate(opts...))
+	}
+}
+
+// _renderReportToFile 根据文件后缀选择 html 或 docx 并写入文件
+func _renderReportToFile(i interface{}, file string, opts ...ReportRenderOption) error {
+	r, err := loadReport(i)
+	if err != nil {
+		return err
+	}
+	raw, err := RenderReport(r, filepath.Ext(file), NewReportTemplate(opts...))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, raw, 0o666); err != nil {
+		return utils.Errorf("write report file failed: %s", err)
+	}
+	return nil
+}
+
+func _reportWithTemplateFile(file string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		loaded, err := LoadReportTemplate(file)
+		if err != nil {
+			log.Errorf("load report template failed: %s", err)
+			return
+		}
+		t.Merge(loaded)
+	}
+}
+
+func _reportWithCompanyName(name string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		t.CompanyName = name
+	}
+}
+
+func _reportWithLogo(logo string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		t.Logo = logo
+	}
+}
+
+func _reportWithPrimaryColor(color string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		t.PrimaryColor = color
+	}
+}
+
+func _reportWithHeader(header string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		t.Header = header
+	}
+}
+
+func _reportWithFooter(footer string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		t.Footer = footer
+	}
+}
+
+func _reportWithCSS(css string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		t.CSS = css
+	}
+}
+
+func _reportWithHTMLLayout(layout string) ReportRenderOption {
+	return func(t *ReportTemplate) {
+		t.HTMLLayout = layout
+	}
+}
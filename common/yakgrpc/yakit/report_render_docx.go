@@ -0,0 +1,602 @@
+package yakit
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+const (
+	docxLogoRelationId = "rIdLogo"
+	// 1 英寸为 914400 EMU
+	docxEMUPerInch = 914400
+)
+
+// docxRun 为 WordprocessingML 中一段相同格式的文本
+type docxRun struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Strike bool
+	Code   bool
+	Link   bool
+	Color  string
+	Break  bool
+}
+
+type docxLogo struct {
+	Raw       []byte
+	Extension string
+	Width     int64
+	Height    int64
+}
+
+type docxWriter struct {
+	body     bytes.Buffer
+	template *ReportTemplate
+	logo     *docxLogo
+	drawings int
+}
+
+func docxEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// docxColor 把 #RRGGBB 转换为 DOCX 使用的 RRGGBB，无法识别时返回空
+func docxColor(color string) string {
+	color = strings.TrimPrefix(strings.TrimSpace(color), "#")
+	if len(color) == 3 {
+		color = string([]byte{color[0], color[0], color[1], color[1], color[2], color[2]})
+	}
+	if len(color) != 6 {
+		return ""
+	}
+	for _, c := range color {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return ""
+		}
+	}
+	return strings.ToUpper(color)
+}
+
+func (w *docxWriter) writeRuns(buf *bytes.Buffer, runs []*docxRun) {
+	for _, run := range runs {
+		if run.Text == "" && !run.Break {
+			continue
+		}
+		buf.WriteString("<w:r>")
+		var props []string
+		if run.Code {
+			props = append(props, `<w:rStyle w:val="CodeChar"/>`)
+		}
+		if run.Bold {
+			props = append(props, "<w:b/>")
+		}
+		if run.Italic {
+			props = append(props, "<w:i/>")
+		}
+		if run.Strike {
+			props = append(props, "<w:strike/>")
+		}
+		if run.Link {
+			props = append(props, `<w:color w:val="0563C1"/><w:u w:val="single"/>`)
+		} else if color := docxColor(run.Color); color != "" {
+			props = append(props, fmt.Sprintf(`<w:color w:val="%s"/>`, color))
+		}
+		if len(props) > 0 {
+			buf.WriteString("<w:rPr>" + strings.Join(props, "") + "</w:rPr>")
+		}
+		if run.Break {
+			buf.WriteString("<w:br/>")
+		}
+		// 文本中的换行使用 w:br
+		for index, line := range strings.Split(run.Text, "\n") {
+			if index > 0 {
+				buf.WriteString("<w:br/>")
+			}
+			if line != "" {
+				fmt.Fprintf(buf, `<w:t xml:space="preserve">%s</w:t>`, docxEscape(line))
+			}
+		}
+		buf.WriteString("</w:r>")
+	}
+}
+
+// paragraph 写入一个段落，indent 为左缩进（twip）
+func (w *docxWriter) paragraph(buf *bytes.Buffer, style string, indent int, runs ...*docxRun) {
+	buf.WriteString("<w:p>")
+	if style != "" || indent > 0 {
+		buf.WriteString("<w:pPr>")
+		if style != "" {
+			fmt.Fprintf(buf, `<w:pStyle w:val="%s"/>`, style)
+		}
+		if indent > 0 {
+			fmt.Fprintf(buf, `<w:ind w:left="%d"/>`, indent)
+		}
+		buf.WriteString("</w:pPr>")
+	}
+	w.writeRuns(buf, runs)
+	buf.WriteString("</w:p>")
+}
+
+func (w *docxWriter) pageBreak() {
+	w.body.WriteString(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+}
+
+// table 写入表格，header 为空时不输出表头
+func (w *docxWriter) table(header []string, rows [][]*reportCell, firstColumnBold bool) {
+	columns := len(header)
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns <= 0 {
+		return
+	}
+
+	buf := &w.body
+	buf.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="ReportTable"/><w:tblW w:w="5000" w:type="pct"/></w:tblPr><w:tblGrid>`)
+	// A4 页面去掉页边距约 9000 twip
+	for i := 0; i < columns; i++ {
+		fmt.Fprintf(buf, `<w:gridCol w:w="%d"/>`, 9000/columns)
+	}
+	buf.WriteString("</w:tblGrid>")
+	if len(header) > 0 {
+		buf.WriteString(`<w:tr><w:trPr><w:tblHeader/></w:trPr>`)
+		for _, h := range header {
+			fmt.Fprintf(buf, `<w:tc><w:tcPr><w:shd w:val="clear" w:color="auto" w:fill="%s"/></w:tcPr>`, docxColor(w.template.primaryColor()))
+			w.paragraph(buf, "", 0, &docxRun{Text: h, Bold: true, Color: "#FFFFFF"})
+			buf.WriteString("</w:tc>")
+		}
+		for i := len(header); i < columns; i++ {
+			buf.WriteString(`<w:tc><w:p/></w:tc>`)
+		}
+		buf.WriteString("</w:tr>")
+	}
+	for _, row := range rows {
+		buf.WriteString("<w:tr>")
+		for index := 0; index < columns; index++ {
+			buf.WriteString("<w:tc>")
+			if index >= len(row) || row[index] == nil {
+				buf.WriteString("<w:p/></w:tc>")
+				continue
+			}
+			cell := row[index]
+			if cell.Code {
+				w.paragraph(buf, "Code", 0, &docxRun{Text: cell.Value})
+			} else {
+				w.paragraph(buf, "", 0, &docxRun{Text: cell.Value, Color: cell.Color, Bold: firstColumnBold && index == 0})
+			}
+			buf.WriteString("</w:tc>")
+		}
+		buf.WriteString("</w:tr>")
+	}
+	buf.WriteString("</w:tbl>")
+	// 表格后面紧跟表格时 Word 会合并，插入空段落分隔
+	w.body.WriteString("<w:p/>")
+}
+
+// markdownInlines 把 markdown 行内节点转换为 run
+func (w *docxWriter) markdownInlines(node *blackfriday.Node, base docxRun) []*docxRun {
+	var runs []*docxRun
+	for child := node.FirstChild; child != nil; child = child.Next {
+		style := base
+		switch child.Type {
+		case blackfriday.Text:
+			style.Text = string(child.Literal)
+			runs = append(runs, &style)
+		case blackfriday.Code:
+			style.Text = string(child.Literal)
+			style.Code = true
+			runs = append(runs, &style)
+		case blackfriday.Softbreak:
+			style.Text = " "
+			runs = append(runs, &style)
+		case blackfriday.Hardbreak:
+			style.Break = true
+			runs = append(runs, &style)
+		case blackfriday.Strong:
+			style.Bold = true
+			runs = append(runs, w.markdownInlines(child, style)...)
+		case blackfriday.Emph:
+			style.Italic = true
+			runs = append(runs, w.markdownInlines(child, style)...)
+		case blackfriday.Del:
+			style.Strike = true
+			runs = append(runs, w.markdownInlines(child, style)...)
+		case blackfriday.Link:
+			style.Link = true
+			runs = append(runs, w.markdownInlines(child, style)...)
+			text := renderMarkdownPlainText(child)
+			if dest := string(child.LinkData.Destination); dest != "" && dest != text {
+				runs = append(runs, &docxRun{Text: fmt.Sprintf(" (%s)", dest)})
+			}
+		case blackfriday.Image:
+			style.Italic = true
+			style.Text = fmt.Sprintf("[%s]", renderMarkdownPlainText(child))
+			runs = append(runs, &style)
+		case blackfriday.HTMLSpan:
+			// 与 HTML 渲染保持一致，忽略内嵌 HTML
+		default:
+			runs = append(runs, w.markdownInlines(child, style)...)
+		}
+	}
+	return runs
+}
+
+func renderMarkdownPlainText(node *blackfriday.Node) string {
+	var buf strings.Builder
+	node.Walk(func(n *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if entering && (n.Type == blackfriday.Text || n.Type == blackfriday.Code) {
+			buf.Write(n.Literal)
+		}
+		return blackfriday.GoToNext
+	})
+	return buf.String()
+}
+
+// markdownBlocks 把 markdown 块级节点写入文档，depth 为列表嵌套层级
+func (w *docxWriter) markdownBlocks(node *blackfriday.Node, depth int, quote bool) {
+	for child := node.FirstChild; child != nil; child = child.Next {
+		w.markdownBlock(child, depth, quote)
+	}
+}
+
+func (w *docxWriter) markdownBlock(node *blackfriday.Node, depth int, quote bool) {
+	switch node.Type {
+	case blackfriday.Heading:
+		level := node.HeadingData.Level
+		if level < 1 {
+			level = 1
+		} else if level > 6 {
+			level = 6
+		}
+		w.paragraph(&w.body, fmt.Sprintf("Heading%d", level), 0, w.markdownInlines(node, docxRun{})...)
+	case blackfriday.Paragraph:
+		style := ""
+		if quote {
+			style = "Quote"
+		}
+		w.paragraph(&w.body, style, depth*360, w.markdownInlines(node, docxRun{})...)
+	case blackfriday.BlockQuote:
+		w.markdownBlocks(node, depth, true)
+	case blackfriday.HorizontalRule:
+		w.body.WriteString(docxHorizontalRule)
+	case blackfriday.CodeBlock:
+		w.paragraph(&w.body, "Code", depth*360, &docxRun{Text: strings.TrimRight(string(node.Literal), "\n")})
+	case blackfriday.List:
+		w.markdownList(node, depth)
+	case blackfriday.Table:
+		w.markdownTable(node)
+	case blackfriday.HTMLBlock:
+	default:
+		w.markdownBlocks(node, depth, quote)
+	}
+}
+
+func (w *docxWriter) markdownList(list *blackfriday.Node, depth int) {
+	ordered := list.ListData.ListFlags&blackfriday.ListTypeOrdered != 0
+	index := 1
+	for item := list.FirstChild; item != nil; item = item.Next {
+		if item.Type != blackfriday.Item {
+			continue
+		}
+		bullet := "• "
+		if ordered {
+			bullet = fmt.Sprintf("%d. ", index)
+			index++
+		}
+		first := true
+		for child := item.FirstChild; child != nil; child = child.Next {
+			switch child.Type {
+			case blackfriday.List:
+				w.markdownList(child, depth+1)
+			case blackfriday.Paragraph:
+				runs := w.markdownInlines(child, docxRun{})
+				if first {
+					runs = append([]*docxRun{{Text: bullet}}, runs...)
+					first = false
+				}
+				w.paragraph(&w.body, "", (depth+1)*360, runs...)
+			default:
+				w.markdownBlock(child, depth+1, false)
+			}
+		}
+	}
+}
+
+func (w *docxWriter) markdownTable(table *blackfriday.Node) {
+	var (
+		header []string
+		rows   [][]*reportCell
+	)
+	table.Walk(func(n *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering || n.Type != blackfriday.TableRow {
+			return blackfriday.GoToNext
+		}
+		var cells []*reportCell
+		isHeader := false
+		for cell := n.FirstChild; cell != nil; cell = cell.Next {
+			isHeader = cell.TableCellData.IsHeader
+			cells = append(cells, &reportCell{Value: renderMarkdownPlainText(cell)})
+		}
+		if isHeader {
+			for _, c := range cells {
+				header = append(header, c.Value)
+			}
+		} else {
+			rows = append(rows, cells)
+		}
+		return blackfriday.SkipChildren
+	})
+	w.table(header, rows, false)
+}
+
+func (w *docxWriter) markdown(md string) {
+	root := blackfriday.New(blackfriday.WithExtensions(blackfriday.CommonExtensions)).Parse([]byte(md))
+	w.markdownBlocks(root, 0, false)
+}
+
+// chart DOCX 中没有 SVG 渲染能力，图表以数据表格的形式展示
+func (w *docxWriter) chart(block *reportBlock) {
+	if block.Title != "" {
+		w.paragraph(&w.body, "Caption", 0, &docxRun{Text: block.Title})
+	}
+	total := reportChartTotal(block.Pairs)
+	var rows [][]*reportCell
+	for _, pair := range block.Pairs {
+		percent := 0.0
+		if total > 0 && pair.Value > 0 {
+			percent = pair.Value / total * 100
+		}
+		rows = append(rows, []*reportCell{
+			{Value: pair.Key, Color: pair.Color},
+			{Value: reportFormatNumber(pair.Value)},
+			{Value: fmt.Sprintf("%.1f%%", percent)},
+		})
+	}
+	if block.CenterLabel != "" {
+		rows = append(rows, []*reportCell{{Value: block.CenterLabel}, {Value: reportFormatNumber(block.CenterValue)}, {}})
+	}
+	w.table([]string{"名称", "数量", "占比"}, rows, false)
+}
+
+func (w *docxWriter) block(block *reportBlock) {
+	switch block.Kind {
+	case reportBlockMarkdown:
+		w.markdown(block.Content)
+	case reportBlockDivider:
+		w.body.WriteString(docxHorizontalRule)
+	case reportBlockCode:
+		w.paragraph(&w.body, "Code", 0, &docxRun{Text: block.Content})
+	case reportBlockCover:
+		level, ok := reportCoverLevels[block.Content]
+		if !ok {
+			return
+		}
+		w.paragraph(&w.body, "", 0, &docxRun{Text: "本次检测整体风险等级：" + level.Verbose, Bold: true, Color: level.Color})
+	case reportBlockChart:
+		w.chart(block)
+	case reportBlockTable:
+		if block.Title != "" {
+			w.paragraph(&w.body, "Caption", 0, &docxRun{Text: block.Title})
+		}
+		w.table(block.Header, block.Rows, false)
+	case reportBlockDetail:
+		if block.Title != "" {
+			w.paragraph(&w.body, "Caption", 0, &docxRun{Text: block.Title})
+		}
+		w.table(nil, block.Rows, true)
+	}
+}
+
+// drawing 生成内联图片，heightInch 为图片高度，宽度按原始比例缩放
+func (w *docxWriter) drawing(heightInch float64) string {
+	if w.logo == nil || w.logo.Height <= 0 {
+		return ""
+	}
+	w.drawings++
+	cy := int64(heightInch * docxEMUPerInch)
+	cx := int64(math.Round(float64(cy) * float64(w.logo.Width) / float64(w.logo.Height)))
+	return fmt.Sprintf(`<w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0"><wp:extent cx="%d" cy="%d"/><wp:docPr id="%d" name="logo%d"/>`+
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+		`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:nvPicPr><pic:cNvPr id="0" name="logo.%s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+		`<pic:blipFill><a:blip r:embed="%s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+		`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr></pic:pic>`+
+		`</a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`,
+		cx, cy, w.drawings, w.drawings, w.logo.Extension, docxLogoRelationId, cx, cy)
+}
+
+func loadDocxLogo(t *ReportTemplate) *docxLogo {
+	raw, _, err := t.logoData()
+	if err != nil {
+		log.Warnf("load report logo failed: %s", err)
+		return nil
+	}
+	if len(raw) <= 0 {
+		return nil
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		log.Warnf("decode report logo failed: %s", err)
+		return nil
+	}
+	return &docxLogo{Raw: raw, Extension: format, Width: int64(config.Width), Height: int64(config.Height)}
+}
+
+const docxHorizontalRule = `<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="D9D9D9"/></w:pBdr></w:pPr></w:p>`
+
+const docxNamespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" ` +
+	`xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"`
+
+func docxStyles(primary string) string {
+	var headings strings.Builder
+	sizes := []int{36, 32, 28, 26, 24, 22}
+	for i, size := range sizes {
+		fmt.Fprintf(&headings, `<w:style w:type="paragraph" w:styleId="Heading%d"><w:name w:val="heading %d"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>`+
+			`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="%d"/></w:pPr><w:rPr><w:b/><w:color w:val="%s"/><w:sz w:val="%d"/></w:rPr></w:style>`,
+			i+1, i+1, i, primary, size)
+	}
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Arial" w:hAnsi="Arial" w:eastAsia="Microsoft YaHei" w:cs="Arial"/><w:sz w:val="21"/><w:lang w:val="en-US" w:eastAsia="zh-CN"/></w:rPr></w:rPrDefault>` +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="80" w:line="300" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:qFormat/><w:pPr><w:jc w:val="center"/><w:spacing w:before="2400" w:after="480"/></w:pPr>` +
+		`<w:rPr><w:b/><w:color w:val="` + primary + `"/><w:sz w:val="52"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Subtitle"><w:name w:val="Subtitle"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:color w:val="595959"/><w:sz w:val="24"/></w:rPr></w:style>` +
+		headings.String() +
+		`<w:style w:type="paragraph" w:styleId="Caption"><w:name w:val="caption"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="120"/></w:pPr><w:rPr><w:b/><w:color w:val="595959"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:basedOn w:val="Normal"/><w:pPr><w:pBdr><w:left w:val="single" w:sz="18" w:space="8" w:color="D9D9D9"/></w:pBdr><w:ind w:left="240"/></w:pPr><w:rPr><w:color w:val="595959"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Code"><w:name w:val="Code"/><w:basedOn w:val="Normal"/><w:pPr><w:shd w:val="clear" w:color="auto" w:fill="F6F8FA"/><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr>` +
+		`<w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:sz w:val="18"/></w:rPr></w:style>` +
+		`<w:style w:type="character" w:styleId="CodeChar"><w:name w:val="Code Char"/><w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:shd w:val="clear" w:color="auto" w:fill="F0F0F0"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Header"><w:name w:val="header"/><w:basedOn w:val="Normal"/><w:rPr><w:color w:val="8C8C8C"/><w:sz w:val="18"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Footer"><w:name w:val="footer"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:color w:val="8C8C8C"/><w:sz w:val="18"/></w:rPr></w:style>` +
+		`<w:style w:type="table" w:styleId="ReportTable"><w:name w:val="Report Table"/><w:tblPr><w:tblBorders>` +
+		`<w:top w:val="single" w:sz="4" w:color="E8E8E8"/><w:left w:val="single" w:sz="4" w:color="E8E8E8"/><w:bottom w:val="single" w:sz="4" w:color="E8E8E8"/>` +
+		`<w:right w:val="single" w:sz="4" w:color="E8E8E8"/><w:insideH w:val="single" w:sz="4" w:color="E8E8E8"/><w:insideV w:val="single" w:sz="4" w:color="E8E8E8"/>` +
+		`</w:tblBorders><w:tblCellMar><w:left w:w="100" w:type="dxa"/><w:right w:w="100" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+		`</w:styles>`
+}
+
+// RenderReportDOCX 把报告渲染为 DOCX 文档，图表以数据表格展示，模版中的 Logo / 页眉 / 页脚写入页眉页脚
+func RenderReportDOCX(r *Report, t *ReportTemplate) ([]byte, error) {
+	if t == nil {
+		t = &ReportTemplate{}
+	}
+	w := &docxWriter{template: t, logo: loadDocxLogo(t)}
+	primary := docxColor(t.primaryColor())
+	if primary == "" {
+		primary = docxColor((&ReportTemplate{}).primaryColor())
+	}
+
+	// 封面
+	if logo := w.drawing(0.8); logo != "" {
+		w.body.WriteString(`<w:p><w:pPr><w:jc w:val="center"/><w:spacing w:before="1200"/></w:pPr>` + logo + `</w:p>`)
+	}
+	w.paragraph(&w.body, "Title", 0, &docxRun{Text: r.TitleValue})
+	if t.CompanyName != "" {
+		w.paragraph(&w.body, "Subtitle", 0, &docxRun{Text: t.CompanyName})
+	}
+	meta := r.OwnerValue
+	if r.FromValue != "" {
+		meta += " / " + r.FromValue
+	}
+	w.paragraph(&w.body, "Subtitle", 0, &docxRun{Text: meta})
+	w.paragraph(&w.body, "Subtitle", 0, &docxRun{Text: r.publishedTime().Format(utils.DefaultTimeFormat)})
+	w.pageBreak()
+
+	for _, block := range r.renderBlocks() {
+		w.block(block)
+	}
+
+	var header bytes.Buffer
+	header.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:hdr ` + docxNamespaces + `>`)
+	header.WriteString(`<w:p><w:pPr><w:pStyle w:val="Header"/><w:tabs><w:tab w:val="right" w:pos="9000"/></w:tabs></w:pPr>`)
+	if logo := w.drawing(0.3); logo != "" {
+		header.WriteString(logo)
+	} else if t.CompanyName != "" {
+		w.writeRuns(&header, []*docxRun{{Text: t.CompanyName}})
+	}
+	header.WriteString(`<w:r><w:tab/></w:r>`)
+	w.writeRuns(&header, []*docxRun{{Text: t.Header}})
+	header.WriteString(`</w:p></w:hdr>`)
+
+	var footer bytes.Buffer
+	footer.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:ftr ` + docxNamespaces + `><w:p><w:pPr><w:pStyle w:val="Footer"/></w:pPr>`)
+	if t.Footer != "" {
+		w.writeRuns(&footer, []*docxRun{{Text: t.Footer + "  "}})
+	}
+	footer.WriteString(`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> PAGE </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>1</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>`)
+	footer.WriteString(`</w:p></w:ftr>`)
+
+	var document bytes.Buffer
+	document.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:document ` + docxNamespaces + `><w:body>`)
+	document.Write(w.body.Bytes())
+	document.WriteString(`<w:sectPr><w:headerReference w:type="default" r:id="rIdHeader"/><w:footerReference w:type="default" r:id="rIdFooter"/>` +
+		`<w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/>` +
+		`<w:titlePg/></w:sectPr></w:body></w:document>`)
+
+	logoRelationship := ""
+	contentTypes := ""
+	if w.logo != nil {
+		logoRelationship = fmt.Sprintf(`<Relationship Id="%s" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/logo.%s"/>`,
+			docxLogoRelationId, w.logo.Extension)
+		contentTypes = fmt.Sprintf(`<Default Extension="%s" ContentType="image/%s"/>`, w.logo.Extension, w.logo.Extension)
+	}
+
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` + contentTypes +
+			`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+			`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+			`<Override PartName="/word/header1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"/>` +
+			`<Override PartName="/word/footer1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"/>` +
+			`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+			`</Types>`)},
+		{"_rels/.rels", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+			`</Relationships>`)},
+		{"docProps/core.xml", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+			`<dc:title>` + docxEscape(r.TitleValue) + `</dc:title><dc:creator>` + docxEscape(r.OwnerValue) + `</dc:creator></cp:coreProperties>`)},
+		{"word/_rels/document.xml.rels", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`<Relationship Id="rIdHeader" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>` +
+			`<Relationship Id="rIdFooter" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer" Target="footer1.xml"/>` +
+			logoRelationship + `</Relationships>`)},
+		{"word/_rels/header1.xml.rels", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + logoRelationship + `</Relationships>`)},
+		{"word/document.xml", document.Bytes()},
+		{"word/styles.xml", []byte(docxStyles(primary))},
+		{"word/header1.xml", header.Bytes()},
+		{"word/footer1.xml", footer.Bytes()},
+	}
+	if w.logo != nil {
+		files = append(files, struct {
+			name    string
+			content []byte
+		}{"word/media/logo." + w.logo.Extension, w.logo.Raw})
+	}
+
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return nil, utils.Errorf("create docx part %v failed: %s", f.name, err)
+		}
+		if _, err := fw.Write(f.content); err != nil {
+			return nil, utils.Errorf("write docx part %v failed: %s", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, utils.Errorf("close docx failed: %s", err)
+	}
+	return out.Bytes(), nil
+}
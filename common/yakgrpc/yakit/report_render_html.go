@@ -0,0 +1,215 @@
+package yakit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// ReportHTMLLayoutData 为 HTML 页面布局模版可以使用的数据
+type ReportHTMLLayoutData struct {
+	Title        string
+	Owner        string
+	From         string
+	PublishedAt  string
+	CompanyName  string
+	Header       string
+	Footer       string
+	PrimaryColor string
+	Logo         template.URL
+	CSS          template.CSS
+	Body         template.HTML
+}
+
+const defaultReportHTMLLayout = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+:root { --primary: {{ .PrimaryColor }}; }
+body { margin: 0; color: #262626; font: 14px/1.7 -apple-system, "PingFang SC", "Microsoft YaHei", "Helvetica Neue", Arial, sans-serif; background: #f5f5f5; }
+.report-page { max-width: 1024px; margin: 0 auto; background: #fff; padding: 0 48px 48px; }
+.report-brand { display: flex; align-items: center; justify-content: space-between; border-bottom: 3px solid var(--primary); padding: 16px 0; color: #595959; }
+.report-brand img { max-height: 40px; }
+.report-cover { padding: 96px 0 64px; text-align: center; border-bottom: 1px solid #f0f0f0; margin-bottom: 32px; }
+.report-cover h1 { font-size: 34px; color: var(--primary); margin: 0 0 24px; }
+.report-cover .report-meta { color: #8c8c8c; }
+h1, h2, h3, h4, h5, h6 { color: var(--primary); }
+table { border-collapse: collapse; width: 100%; margin: 12px 0; table-layout: fixed; word-break: break-all; }
+th, td { border: 1px solid #e8e8e8; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: var(--primary); color: #fff; }
+table.report-detail td:first-child { width: 18%; background: #fafafa; font-weight: bold; }
+pre { background: #f6f8fa; border: 1px solid #eee; padding: 12px; overflow: auto; white-space: pre-wrap; word-break: break-all; font: 12px/1.5 Consolas, Menlo, monospace; }
+blockquote { margin: 0; padding: 0 16px; border-left: 4px solid #d9d9d9; color: #595959; }
+hr { border: none; border-top: 1px solid #e8e8e8; margin: 24px 0; }
+.report-figure { margin: 16px 0; text-align: center; }
+.report-figure figcaption { color: #595959; margin-bottom: 8px; font-weight: bold; }
+.report-chart { max-width: 100%; height: auto; }
+.report-level { border-radius: 4px; color: #fff; padding: 16px 24px; font-size: 18px; margin: 16px 0; }
+.report-footer { border-top: 1px solid #e8e8e8; margin-top: 48px; padding-top: 12px; color: #8c8c8c; text-align: center; font-size: 12px; }
+@media print { body { background: #fff; } .report-page { max-width: none; padding: 0; } .report-cover { page-break-after: always; } }
+{{ .CSS }}
+</style>
+</head>
+<body>
+<div class="report-page">
+{{- if or .Logo .CompanyName .Header }}
+<div class="report-brand">
+<div>{{ if .Logo }}<img src="{{ .Logo }}" alt="logo">{{ else }}{{ .CompanyName }}{{ end }}</div>
+<div>{{ .Header }}</div>
+</div>
+{{- end }}
+<div class="report-cover">
+<h1>{{ .Title }}</h1>
+<div class="report-meta">
+{{- if .CompanyName }}<div>{{ .CompanyName }}</div>{{ end }}
+<div>{{ .Owner }}{{ if .From }} / {{ .From }}{{ end }}</div>
+<div>{{ .PublishedAt }}</div>
+</div>
+</div>
+<div class="report-content">
+{{ .Body }}
+</div>
+{{- if .Footer }}
+<div class="report-footer">{{ .Footer }}</div>
+{{- end }}
+</div>
+</body>
+</html>
+`
+
+// renderReportMarkdownHTML 与前端保持一致，不渲染 markdown 中内嵌的 HTML，
+// 标题等内容可能来自扫描目标，只保留 http/https/mailto 等安全链接，javascript: 等链接按文本输出
+func renderReportMarkdownHTML(md string) string {
+	renderer := blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+		Flags: blackfriday.CommonHTMLFlags | blackfriday.SkipHTML | blackfriday.Safelink,
+	})
+	return string(blackfriday.Run(
+		[]byte(md),
+		blackfriday.WithExtensions(blackfriday.CommonExtensions),
+		blackfriday.WithRenderer(renderer),
+	))
+}
+
+func renderReportCellHTML(buf *bytes.Buffer, cell *reportCell) {
+	style := ""
+	if cell.Color != "" {
+		style = fmt.Sprintf(` style="color: %s"`, html.EscapeString(cell.Color))
+	}
+	if cell.Code {
+		fmt.Fprintf(buf, `<td%s><pre>%s</pre></td>`, style, html.EscapeString(cell.Value))
+		return
+	}
+	fmt.Fprintf(buf, `<td%s>%s</td>`, style, strings.ReplaceAll(html.EscapeString(cell.Value), "\n", "<br>"))
+}
+
+func renderReportBlockHTML(buf *bytes.Buffer, block *reportBlock) {
+	switch block.Kind {
+	case reportBlockMarkdown:
+		buf.WriteString(renderReportMarkdownHTML(block.Content))
+	case reportBlockDivider:
+		buf.WriteString("<hr>\n")
+	case reportBlockCode:
+		fmt.Fprintf(buf, "<pre><code>%s</code></pre>\n", html.EscapeString(block.Content))
+	case reportBlockCover:
+		level, ok := reportCoverLevels[block.Content]
+		if !ok {
+			return
+		}
+		fmt.Fprintf(buf, `<div class="report-level" style="background: %s">本次检测整体风险等级：%s</div>`+"\n", level.Color, level.Verbose)
+	case reportBlockChart:
+		buf.WriteString(`<figure class="report-figure">`)
+		if block.Title != "" {
+			fmt.Fprintf(buf, "<figcaption>%s</figcaption>", html.EscapeString(block.Title))
+		}
+		buf.WriteString(renderReportChartSVG(block))
+		buf.WriteString("</figure>\n")
+	case reportBlockTable:
+		if block.Title != "" {
+			fmt.Fprintf(buf, "<h4>%s</h4>\n", html.EscapeString(block.Title))
+		}
+		buf.WriteString("<table>\n")
+		if len(block.Header) > 0 {
+			buf.WriteString("<thead><tr>")
+			for _, h := range block.Header {
+				fmt.Fprintf(buf, "<th>%s</th>", html.EscapeString(h))
+			}
+			buf.WriteString("</tr></thead>\n")
+		}
+		buf.WriteString("<tbody>\n")
+		for _, row := range block.Rows {
+			buf.WriteString("<tr>")
+			for _, cell := range row {
+				renderReportCellHTML(buf, cell)
+			}
+			buf.WriteString("</tr>\n")
+		}
+		buf.WriteString("</tbody></table>\n")
+	case reportBlockDetail:
+		if block.Title != "" {
+			fmt.Fprintf(buf, "<h4>%s</h4>\n", html.EscapeString(block.Title))
+		}
+		buf.WriteString(`<table class="report-detail"><tbody>` + "\n")
+		for _, row := range block.Rows {
+			buf.WriteString("<tr>")
+			for _, cell := range row {
+				renderReportCellHTML(buf, cell)
+			}
+			buf.WriteString("</tr>\n")
+		}
+		buf.WriteString("</tbody></table>\n")
+	}
+}
+
+// RenderReportHTML 把报告渲染为不依赖外部资源的单文件 HTML，图表以内联 SVG 展示
+func RenderReportHTML(r *Report, t *ReportTemplate) ([]byte, error) {
+	if t == nil {
+		t = &ReportTemplate{}
+	}
+	var body bytes.Buffer
+	for _, block := range r.renderBlocks() {
+		renderReportBlockHTML(&body, block)
+	}
+
+	data := &ReportHTMLLayoutData{
+		Title:        r.TitleValue,
+		Owner:        r.OwnerValue,
+		From:         r.FromValue,
+		PublishedAt:  r.publishedTime().Format(utils.DefaultTimeFormat),
+		CompanyName:  t.CompanyName,
+		Header:       t.Header,
+		Footer:       t.Footer,
+		PrimaryColor: t.primaryColor(),
+		CSS:          template.CSS(t.CSS),
+		Body:         template.HTML(body.String()),
+	}
+	logo, mimeType, err := t.logoData()
+	if err != nil {
+		log.Warnf("load report logo failed: %s", err)
+	} else if len(logo) > 0 {
+		data.Logo = template.URL(fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(logo)))
+	}
+
+	layout := t.HTMLLayout
+	if layout == "" {
+		layout = defaultReportHTMLLayout
+	}
+	tmpl, err := template.New("report").Parse(layout)
+	if err != nil {
+		return nil, utils.Errorf("parse report html layout failed: %s", err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, utils.Errorf("render report html failed: %s", err)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,250 @@
+package yakit
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"unicode/utf8"
+)
+
+var reportChartPalette = []string{
+	"#5470c6", "#91cc75", "#fac858", "#ee6666", "#73c0de",
+	"#3ba272", "#fc8452", "#9a60b4", "#ea7ccc", "#8d98b3",
+}
+
+func reportChartColor(pair *reportChartPair, index int) string {
+	if pair.Color != "" {
+		return pair.Color
+	}
+	return reportChartPalette[index%len(reportChartPalette)]
+}
+
+// reportTextWidth 估算文本宽度，中文按一个字号计算，其他字符按半个多字号计算
+func reportTextWidth(s string, fontSize float64) float64 {
+	var width float64
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			width += fontSize * 0.6
+		} else {
+			width += fontSize
+		}
+	}
+	return width
+}
+
+func reportChartTotal(pairs []*reportChartPair) float64 {
+	var total float64
+	for _, pair := range pairs {
+		if pair.Value > 0 {
+			total += pair.Value
+		}
+	}
+	return total
+}
+
+func reportFormatNumber(f float64) string {
+	if f == math.Trunc(f) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	return fmt.Sprintf("%.2f", f)
+}
+
+// renderReportChartSVG 把图表渲染为内联 SVG
+func renderReportChartSVG(block *reportBlock) string {
+	switch block.ChartType {
+	case REPORT_ITEM_TYPE_PIE_GRAPH:
+		return renderReportPieSVG(block)
+	case REPORT_ITEM_TYPE_HORIZONTAL_BAR_GRAPH:
+		return renderReportHorizontalBarSVG(block)
+	case REPORT_ITEM_TYPE_WORDCLOUD:
+		return renderReportWordCloudSVG(block)
+	default:
+		return renderReportVerticalBarSVG(block)
+	}
+}
+
+func renderReportPieSVG(block *reportBlock) string {
+	const (
+		cx, cy, radius = 130.0, 130.0, 110.0
+		legendX        = 280.0
+		lineHeight     = 24.0
+	)
+	total := reportChartTotal(block.Pairs)
+	height := math.Max(2*cy, float64(len(block.Pairs))*lineHeight+20)
+	legendWidth := 0.0
+	for _, pair := range block.Pairs {
+		legendWidth = math.Max(legendWidth, reportTextWidth(pair.Key, 13)+90)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" class="report-chart" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`,
+		legendX+legendWidth, height, legendX+legendWidth, height)
+	if total <= 0 {
+		fmt.Fprintf(&buf, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="#f0f0f0"/>`, cx, cy, radius)
+	}
+	angle := -math.Pi / 2
+	for index, pair := range block.Pairs {
+		if total <= 0 || pair.Value <= 0 {
+			continue
+		}
+		color := reportChartColor(pair, index)
+		if pair.Value >= total {
+			fmt.Fprintf(&buf, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="%s"/>`, cx, cy, radius, html.EscapeString(color))
+			continue
+		}
+		delta := pair.Value / total * 2 * math.Pi
+		x1, y1 := cx+radius*math.Cos(angle), cy+radius*math.Sin(angle)
+		x2, y2 := cx+radius*math.Cos(angle+delta), cy+radius*math.Sin(angle+delta)
+		largeArc := 0
+		if delta > math.Pi {
+			largeArc = 1
+		}
+		fmt.Fprintf(&buf, `<path d="M%.2f,%.2f L%.2f,%.2f A%.0f,%.0f 0 %d,1 %.2f,%.2f Z" fill="%s" stroke="#fff" stroke-width="1"/>`,
+			cx, cy, x1, y1, radius, radius, largeArc, x2, y2, html.EscapeString(color))
+		angle += delta
+	}
+	if block.CenterLabel != "" {
+		fmt.Fprintf(&buf, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="#fff"/>`, cx, cy, radius*0.55)
+		fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f" text-anchor="middle" font-size="22" font-weight="bold">%s</text>`,
+			cx, cy, reportFormatNumber(block.CenterValue))
+		fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f" text-anchor="middle" font-size="12" fill="#666">%s</text>`,
+			cx, cy+20, html.EscapeString(block.CenterLabel))
+	}
+	for index, pair := range block.Pairs {
+		y := 20 + float64(index)*lineHeight
+		percent := 0.0
+		if total > 0 && pair.Value > 0 {
+			percent = pair.Value / total * 100
+		}
+		fmt.Fprintf(&buf, `<rect x="%.0f" y="%.0f" width="12" height="12" rx="2" fill="%s"/>`,
+			legendX, y-10, html.EscapeString(reportChartColor(pair, index)))
+		fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f" font-size="13">%s %s (%.1f%%)</text>`,
+			legendX+18, y, html.EscapeString(pair.Key), reportFormatNumber(pair.Value), percent)
+	}
+	buf.WriteString(`</svg>`)
+	return buf.String()
+}
+
+func reportChartMax(pairs []*reportChartPair) float64 {
+	var max float64
+	for _, pair := range pairs {
+		max = math.Max(max, pair.Value)
+	}
+	if max <= 0 {
+		return 1
+	}
+	return max
+}
+
+func renderReportVerticalBarSVG(block *reportBlock) string {
+	const (
+		chartHeight = 220.0
+		top         = 24.0
+		left        = 40.0
+		barWidth    = 36.0
+		gap         = 28.0
+	)
+	max := reportChartMax(block.Pairs)
+	width := left + float64(len(block.Pairs))*(barWidth+gap) + gap
+	height := top + chartHeight + 60
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" class="report-chart" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`,
+		width, height, width, height)
+	fmt.Fprintf(&buf, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#ccc"/>`, left, top+chartHeight, width, top+chartHeight)
+	for index, pair := range block.Pairs {
+		x := left + gap + float64(index)*(barWidth+gap)
+		h := math.Max(pair.Value, 0) / max * chartHeight
+		y := top + chartHeight - h
+		fmt.Fprintf(&buf, `<rect x="%.0f" y="%.2f" width="%.0f" height="%.2f" fill="%s"/>`,
+			x, y, barWidth, h, html.EscapeString(reportChartColor(pair, index)))
+		fmt.Fprintf(&buf, `<text x="%.0f" y="%.2f" text-anchor="middle" font-size="12">%s</text>`,
+			x+barWidth/2, y-6, reportFormatNumber(pair.Value))
+		fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f" text-anchor="end" font-size="12" transform="rotate(-30 %.0f %.0f)">%s</text>`,
+			x+barWidth/2, top+chartHeight+16, x+barWidth/2, top+chartHeight+16, html.EscapeString(pair.Key))
+	}
+	buf.WriteString(`</svg>`)
+	return buf.String()
+}
+
+func renderReportHorizontalBarSVG(block *reportBlock) string {
+	const (
+		barHeight  = 20.0
+		gap        = 10.0
+		chartWidth = 420.0
+	)
+	labelWidth := 40.0
+	for _, pair := range block.Pairs {
+		labelWidth = math.Max(labelWidth, reportTextWidth(pair.Key, 12)+12)
+	}
+	max := reportChartMax(block.Pairs)
+	width := labelWidth + chartWidth + 60
+	height := float64(len(block.Pairs))*(barHeight+gap) + gap
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" class="report-chart" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`,
+		width, height, width, height)
+	for index, pair := range block.Pairs {
+		y := gap + float64(index)*(barHeight+gap)
+		w := math.Max(pair.Value, 0) / max * chartWidth
+		fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f" text-anchor="end" font-size="12">%s</text>`,
+			labelWidth-6, y+barHeight*0.7, html.EscapeString(pair.Key))
+		fmt.Fprintf(&buf, `<rect x="%.0f" y="%.0f" width="%.2f" height="%.0f" fill="%s"/>`,
+			labelWidth, y, w, barHeight, html.EscapeString(reportChartColor(pair, index)))
+		fmt.Fprintf(&buf, `<text x="%.2f" y="%.0f" font-size="12">%s</text>`,
+			labelWidth+w+6, y+barHeight*0.7, reportFormatNumber(pair.Value))
+	}
+	buf.WriteString(`</svg>`)
+	return buf.String()
+}
+
+// renderReportWordCloudSVG 按权重计算字号，从左到右依次排布
+func renderReportWordCloudSVG(block *reportBlock) string {
+	const (
+		width              = 640.0
+		minFont, maxFont   = 12.0, 40.0
+		paddingX, paddingY = 12.0, 8.0
+	)
+	max := reportChartMax(block.Pairs)
+
+	type word struct {
+		text       string
+		x, y, size float64
+		color      string
+	}
+	var (
+		words      []*word
+		x          = paddingX
+		y          = 0.0
+		lineHeight = 0.0
+	)
+	for index, pair := range block.Pairs {
+		size := minFont + math.Max(pair.Value, 0)/max*(maxFont-minFont)
+		w := reportTextWidth(pair.Key, size)
+		if x+w > width-paddingX && x > paddingX {
+			x = paddingX
+			y += lineHeight + paddingY
+			lineHeight = 0
+		}
+		lineHeight = math.Max(lineHeight, size)
+		words = append(words, &word{text: pair.Key, x: x, y: y, size: size, color: reportChartColor(pair, index)})
+		x += w + paddingX
+	}
+
+	// 每一行按行内最大字号对齐基线
+	var buf bytes.Buffer
+	height := y + lineHeight + paddingY*2
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" class="report-chart" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`,
+		width, height, width, height)
+	lineMax := make(map[float64]float64)
+	for _, w := range words {
+		lineMax[w.y] = math.Max(lineMax[w.y], w.size)
+	}
+	for _, w := range words {
+		fmt.Fprintf(&buf, `<text x="%.2f" y="%.2f" font-size="%.1f" fill="%s">%s</text>`,
+			w.x, w.y+lineMax[w.y]+paddingY, w.size, html.EscapeString(w.color), html.EscapeString(w.text))
+	}
+	buf.WriteString(`</svg>`)
+	return buf.String()
+}
@@ -0,0 +1,141 @@
+package yakit
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRenderTestReport() *Report {
+	r := NewReport()
+	r.Title("渲染测试报告")
+	r.Owner("tester")
+	r.Markdown("# 概述\n\n**重点** 内容 `code` <script>alert(1)</script>\n\n- 第一项\n- 第二项\n\n| 名称 | 数量 |\n| --- | --- |\n| a | 1 |\n")
+	r.Table([]string{"地址", "端口"}, []interface{}{"127.0.0.1", 80})
+	r.PieGraph(map[string]interface{}{"key": "高危", "value": 3}, map[string]interface{}{"key": "低危", "value": 1})
+	r.WordCloud(map[string]interface{}{"key": "nginx", "value": 10})
+	r.Raw(map[string]interface{}{"type": "report-cover", "data": "high"})
+	r.Raw(map[string]interface{}{"type": "bar-graph", "title": "漏洞汇总", "data": []interface{}{
+		map[string]interface{}{"name": "严重漏洞", "value": 2},
+	}, "color": []string{"#f70208"}})
+	raw, _ := json.Marshal(map[string]interface{}{"type": "risk-list", "data": []interface{}{
+		map[string]interface{}{
+			"资产":   map[string]interface{}{"value": "10.0.0.1", "sort": 1},
+			"风险等级": map[string]interface{}{"value": "高危", "color": "#FF4500", "sort": 2},
+		},
+	}})
+	r.Raw(string(raw))
+	r.Raw(map[string]interface{}{"type": "fix-list", "data": map[string]interface{}{
+		"标题":           map[string]interface{}{"value": "SQL 注入", "sort": 1},
+		"HTTP Request": map[string]interface{}{"value": "GET / HTTP/1.1", "sort": 2, "type": "code"},
+	}})
+	r.Code("print(1)")
+	return r
+}
+
+func TestRenderReportHTML(t *testing.T) {
+	r := newRenderTestReport()
+	raw, err := RenderReport(r, "html", NewReportTemplate(
+		_reportWithCompanyName("Example Corp"),
+		_reportWithPrimaryColor("#aa0000"),
+		_reportWithFooter("内部资料"),
+	))
+	require.NoError(t, err)
+	page := string(raw)
+
+	assert.Contains(t, page, "<title>渲染测试报告</title>")
+	assert.Contains(t, page, "Example Corp")
+	assert.Contains(t, page, "内部资料")
+	assert.Contains(t, page, "--primary: #aa0000")
+	assert.Contains(t, page, "<strong>重点</strong>")
+	assert.NotContains(t, page, "<script>")
+	assert.Equal(t, 3, strings.Count(page, "<svg"))
+	assert.Contains(t, page, "漏洞汇总")
+	assert.Contains(t, page, "本次检测整体风险等级：高危")
+	assert.Contains(t, page, `<th>资产</th><th>风险等级</th>`)
+	assert.Contains(t, page, `<td style="color: #FF4500">高危</td>`)
+	assert.Contains(t, page, "<pre>GET / HTTP/1.1</pre>")
+
+	// 漏洞标题来自扫描目标，其中的 javascript: 链接不能渲染成可点击的链接
+	linkReport := NewReport()
+	linkReport.Raw(map[string]interface{}{"type": "fix-array-list", "title": "[XSS](javascript:alert(1)) [ref](https://example.com/)", "data": []interface{}{
+		map[string]interface{}{"标题": map[string]interface{}{"value": "XSS", "sort": 1}},
+	}})
+	raw, err = RenderReport(linkReport, "html", NewReportTemplate())
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), `href="javascript:`)
+	assert.Contains(t, string(raw), `href="https://example.com/"`)
+
+	raw, err = RenderReport(r, "html", NewReportTemplate(_reportWithHTMLLayout(`<h1>{{ .Title }}</h1>{{ .Body }}`)))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), "<h1>渲染测试报告</h1>"))
+}
+
+func readDocxParts(t *testing.T, raw []byte) map[string][]byte {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	require.NoError(t, err)
+	parts := make(map[string][]byte)
+	for _, f := range zr.File {
+		fp, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(fp)
+		fp.Close()
+		require.NoError(t, err)
+		parts[f.Name] = content
+		if strings.HasSuffix(f.Name, ".xml") || strings.HasSuffix(f.Name, ".rels") {
+			// 每一个 XML 部件都必须是合法的 XML
+			decoder := xml.NewDecoder(bytes.NewReader(content))
+			for {
+				_, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err, f.Name)
+			}
+		}
+	}
+	return parts
+}
+
+func TestRenderReportDOCX(t *testing.T) {
+	dir := t.TempDir()
+	var logo bytes.Buffer
+	require.NoError(t, png.Encode(&logo, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), logo.Bytes(), 0o666))
+	templateFile := filepath.Join(dir, "template.json")
+	require.NoError(t, os.WriteFile(templateFile, []byte(`{"company_name": "Example Corp", "logo": "logo.png", "header": "机密", "footer": "内部资料"}`), 0o666))
+
+	tmpl := NewReportTemplate(_reportWithTemplateFile(templateFile))
+	assert.Equal(t, filepath.Join(dir, "logo.png"), tmpl.Logo)
+
+	raw, err := RenderReport(newRenderTestReport(), "docx", tmpl)
+	require.NoError(t, err)
+	parts := readDocxParts(t, raw)
+
+	document := string(parts["word/document.xml"])
+	assert.Contains(t, document, "渲染测试报告")
+	assert.Contains(t, document, `<w:pStyle w:val="Heading1"/>`)
+	assert.Contains(t, document, "<w:b/></w:rPr><w:t xml:space=\"preserve\">重点</w:t>")
+	assert.Contains(t, document, "• ")
+	assert.Contains(t, document, "SQL 注入")
+	assert.Contains(t, document, "75.0%")
+	assert.NotContains(t, document, "script")
+	assert.Contains(t, string(parts["word/header1.xml"]), "机密")
+	assert.Contains(t, string(parts["word/footer1.xml"]), "内部资料")
+	assert.Equal(t, logo.Bytes(), parts["word/media/logo.png"])
+	assert.Contains(t, string(parts["word/_rels/header1.xml.rels"]), "media/logo.png")
+
+	_, err = RenderReport(newRenderTestReport(), "pdf", nil)
+	require.Error(t, err)
+}
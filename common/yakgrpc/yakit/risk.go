@@ -63,6 +63,23 @@ type Risk struct {
 	TaskName            string `json:"task_name"`
 	CveAccessVector     string `json:"cve_access_vector"`
 	CveAccessComplexity string `json:"cve_access_complexity"`
+
+	// 研判状态、指派人，为空时认为是新漏洞
+	TriageStatus string `json:"triage_status" gorm:"index"`
+	Assignee     string `json:"assignee"`
+	// 跨扫描稳定的漏洞指纹，重复扫描出的相同漏洞合并到同一条记录
+	Fingerprint   string `json:"fingerprint" gorm:"index"`
+	SeenCount     int64  `json:"seen_count"`
+	LastSeenAt    int64  `json:"last_seen_at"`
+	LastRuntimeId string `json:"last_runtime_id"`
+
+	// CVSS 向量及评分，CVSSScore 为最终评分，用于过滤与排序
+	CVSSVersion            string  `json:"cvss_version"`
+	CVSSVector             string  `json:"cvss_vector"`
+	CVSSBaseScore          float64 `json:"cvss_base_score"`
+	CVSSTemporalScore      float64 `json:"cvss_temporal_score"`
+	CVSSEnvironmentalScore float64 `json:"cvss_environmental_score"`
+	CVSSScore              float64 `json:"cvss_score" gorm:"index"`
 }
 
 func (p *Risk) ToGRPCModel() *ypb.Risk {
@@ -117,6 +134,20 @@ func (p *Risk) ToGRPCModel() *ypb.Risk {
 		RuntimeId: utils.EscapeInvalidUTF8Byte([]byte(p.RuntimeId)),
 		CVE:       utils.EscapeInvalidUTF8Byte([]byte(p.CVE)),
 		TaskName:  utils.EscapeInvalidUTF8Byte([]byte(p.TaskName)),
+
+		TriageStatus:  p.GetTriageStatus(),
+		Assignee:      utils.EscapeInvalidUTF8Byte([]byte(p.Assignee)),
+		Fingerprint:   p.Fingerprint,
+		SeenCount:     p.SeenCount,
+		LastSeenAt:    p.LastSeenAt,
+		LastRuntimeId: p.LastRuntimeId,
+
+		CVSSVersion:            p.CVSSVersion,
+		CVSSVector:             p.CVSSVector,
+		CVSSBaseScore:          p.CVSSBaseScore,
+		CVSSTemporalScore:      p.CVSSTemporalScore,
+		CVSSEnvironmentalScore: p.CVSSEnvironmentalScore,
+		CVSSScore:              p.CVSSScore,
 	}
 }
 
@@ -132,8 +163,19 @@ func (p *Risk) BeforeSave() error {
 		p.IPInteger, _ = utils.IPv4ToUint64(p.IP)
 	}
 
+	// 只设置了向量（例如通过 map 更新）时补全评分
+	if p.CVSSVector != "" && p.CVSSVersion == "" {
+		if err := p.SetCVSS(p.CVSSVector); err != nil {
+			log.Warnf("calc risk cvss failed: %s", err)
+		}
+	}
+
 	if p.Severity == "" {
-		p.Severity = "info"
+		if p.CVSSVersion != "" {
+			p.Severity = CVSSScoreToSeverity(p.CVSSScore)
+		} else {
+			p.Severity = "info"
+		}
 	}
 
 	if p.RiskType == "" {
@@ -144,6 +186,10 @@ func (p *Risk) BeforeSave() error {
 		p.RiskTypeVerbose = "信息"
 	}
 
+	if p.Fingerprint == "" {
+		p.Fingerprint = CalcRiskFingerprint(p)
+	}
+
 	return nil
 }
 
@@ -200,7 +246,7 @@ func GetRisk(db *gorm.DB, id int64) (*Risk, error) {
 
 func GetRisksByRuntimeId(db *gorm.DB, runtimeId string) ([]*Risk, error) {
 	var r []*Risk
-	if db := db.Model(&Risk{}).Where("runtime_id = ?", runtimeId).Find(&r); db.Error != nil {
+	if db := db.Model(&Risk{}).Where("runtime_id = ? OR last_runtime_id = ?", runtimeId, runtimeId).Find(&r); db.Error != nil {
 		return nil, utils.Errorf("get Risks failed: %s", db.Error)
 	}
 	return r, nil
@@ -208,7 +254,7 @@ func GetRisksByRuntimeId(db *gorm.DB, runtimeId string) ([]*Risk, error) {
 
 func CountRiskByRuntimeId(db *gorm.DB, runtimeId string) (uint32, error) {
 	var count uint32
-	if db := db.Model(&Risk{}).Where("runtime_id = ?", runtimeId).Count(&count); db.Error != nil {
+	if db := db.Model(&Risk{}).Where("runtime_id = ? OR last_runtime_id = ?", runtimeId, runtimeId).Count(&count); db.Error != nil {
 		return 0, utils.Errorf("get Risks count failed: %s", db.Error)
 	}
 	return count, nil
@@ -271,6 +317,15 @@ func FilterByQueryRisks(db *gorm.DB, params *ypb.QueryRisksRequest) (_ *gorm.DB,
 		utils.PrettifyListFromStringSplitEx(params.GetSeverity()),
 	)
 	db = bizhelper.ExactQueryString(db, "token", params.GetToken())
+	db = FilterRiskByTriageStatus(db, params.GetTriageStatus())
+	db = bizhelper.ExactQueryString(db, "assignee", params.GetAssignee())
+	db = bizhelper.ExactQueryString(db, "fingerprint", params.GetFingerprint())
+	if params.GetMinCVSSScore() > 0 {
+		db = db.Where("cvss_score >= ?", params.GetMinCVSSScore())
+	}
+	if params.GetMaxCVSSScore() > 0 {
+		db = db.Where("cvss_score <= ?", params.GetMaxCVSSScore())
+	}
 	return db, nil
 }
 
@@ -390,7 +445,8 @@ func YieldRisksByTarget(db *gorm.DB, ctx context.Context, target string) chan *R
 func YieldRisksByRuntimeId(db *gorm.DB, ctx context.Context, runtimeId string) chan *Risk {
 	outC := make(chan *Risk)
 	db = db.Model(&Risk{})
-	db = db.Where("runtime_id = ?", runtimeId)
+	// 跨扫描去重后，重复发现的漏洞只会更新 last_runtime_id
+	db = db.Where("runtime_id = ? OR last_runtime_id = ?", runtimeId, runtimeId)
 
 	go func() {
 		defer close(outC)
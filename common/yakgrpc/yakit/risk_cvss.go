@@ -0,0 +1,175 @@
+package yakit
+
+import (
+	"strings"
+
+	gocvss30 "github.com/pandatix/go-cvss/30"
+	gocvss31 "github.com/pandatix/go-cvss/31"
+	gocvss40 "github.com/pandatix/go-cvss/40"
+	"github.com/yaklang/yaklang/common/log"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+const (
+	CVSSVersion30 = "3.0"
+	CVSSVersion31 = "3.1"
+	CVSSVersion40 = "4.0"
+)
+
+var (
+	cvss3TemporalMetrics      = []string{"E", "RL", "RC"}
+	cvss3EnvironmentalMetrics = []string{"CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA"}
+
+	cvss4ThreatMetrics        = []string{"E"}
+	cvss4EnvironmentalMetrics = []string{"CR", "IR", "AR", "MAV", "MAC", "MAT", "MPR", "MUI", "MVC", "MVI", "MVA", "MSC", "MSI", "MSA"}
+)
+
+// CVSSScore 为解析并计算后的 CVSS 向量
+// 对于 4.0 向量，TemporalScore 为 CVSS-BT（基础 + 威胁指标），EnvironmentalScore 为 CVSS-BTE
+// Score 为最终评分：设置了环境指标时取环境评分，否则设置了时间 / 威胁指标时取时间评分，否则取基础评分
+type CVSSScore struct {
+	Version            string
+	Vector             string
+	BaseScore          float64
+	TemporalScore      float64
+	EnvironmentalScore float64
+	Score              float64
+}
+
+// Severity 根据最终评分换算为 Risk 使用的漏洞等级
+func (s *CVSSScore) Severity() string {
+	return CVSSScoreToSeverity(s.Score)
+}
+
+type cvss3Vector interface {
+	Get(abv string) (string, error)
+	Vector() string
+	BaseScore() float64
+	TemporalScore() float64
+	EnvironmentalScore() float64
+}
+
+// ParseCVSSVector 解析并校验 CVSS 3.0 / 3.1 / 4.0 向量，计算基础、时间与环境评分
+func ParseCVSSVector(vector string) (*CVSSScore, error) {
+	vector = strings.TrimSpace(vector)
+	switch {
+	case strings.HasPrefix(vector, "CVSS:3.0/"):
+		v, err := gocvss30.ParseVector(vector)
+		if err != nil {
+			return nil, utils.Errorf("invalid cvss vector[%v]: %s", vector, err)
+		}
+		return calcCVSS3Score(CVSSVersion30, v), nil
+	case strings.HasPrefix(vector, "CVSS:3.1/"):
+		v, err := gocvss31.ParseVector(vector)
+		if err != nil {
+			return nil, utils.Errorf("invalid cvss vector[%v]: %s", vector, err)
+		}
+		return calcCVSS3Score(CVSSVersion31, v), nil
+	case strings.HasPrefix(vector, "CVSS:4.0/"):
+		v, err := gocvss40.ParseVector(vector)
+		if err != nil {
+			return nil, utils.Errorf("invalid cvss vector[%v]: %s", vector, err)
+		}
+		return calcCVSS4Score(v)
+	default:
+		return nil, utils.Errorf("unsupported cvss vector[%v], only CVSS:3.0 / CVSS:3.1 / CVSS:4.0 is supported", vector)
+	}
+}
+
+func cvssMetricsDefined(get func(string) (string, error), metrics []string) bool {
+	for _, metric := range metrics {
+		if value, _ := get(metric); value != "" && value != "X" {
+			return true
+		}
+	}
+	return false
+}
+
+func calcCVSS3Score(version string, v cvss3Vector) *CVSSScore {
+	s := &CVSSScore{
+		Version:            version,
+		Vector:             v.Vector(),
+		BaseScore:          v.BaseScore(),
+		TemporalScore:      v.TemporalScore(),
+		EnvironmentalScore: v.EnvironmentalScore(),
+	}
+	switch {
+	case cvssMetricsDefined(v.Get, cvss3EnvironmentalMetrics):
+		s.Score = s.EnvironmentalScore
+	case cvssMetricsDefined(v.Get, cvss3TemporalMetrics):
+		s.Score = s.TemporalScore
+	default:
+		s.Score = s.BaseScore
+	}
+	return s
+}
+
+// calcCVSS4Score 4.0 没有独立的时间 / 环境公式，通过清空对应指标组分别计算 CVSS-B、CVSS-BT 与 CVSS-BTE
+func calcCVSS4Score(v *gocvss40.CVSS40) (*CVSSScore, error) {
+	s := &CVSSScore{
+		Version:            CVSSVersion40,
+		Vector:             v.Vector(),
+		EnvironmentalScore: v.Score(),
+	}
+	s.Score = s.EnvironmentalScore
+
+	threat := *v
+	for _, metric := range cvss4EnvironmentalMetrics {
+		if err := threat.Set(metric, "X"); err != nil {
+			return nil, utils.Errorf("reset cvss metric %v failed: %s", metric, err)
+		}
+	}
+	s.TemporalScore = threat.Score()
+
+	base := threat
+	for _, metric := range cvss4ThreatMetrics {
+		if err := base.Set(metric, "X"); err != nil {
+			return nil, utils.Errorf("reset cvss metric %v failed: %s", metric, err)
+		}
+	}
+	s.BaseScore = base.Score()
+
+	if !cvssMetricsDefined(v.Get, cvss4EnvironmentalMetrics) {
+		s.EnvironmentalScore = s.TemporalScore
+	}
+	return s, nil
+}
+
+// CVSSScoreToSeverity 按 CVSS 定性评级把评分换算为 Risk 的漏洞等级
+func CVSSScoreToSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "warning"
+	case score >= 0.1:
+		return "low"
+	default:
+		return "info"
+	}
+}
+
+// SetCVSS 为漏洞设置 CVSS 向量并计算评分，向量非法时不修改漏洞
+func (p *Risk) SetCVSS(vector string) error {
+	s, err := ParseCVSSVector(vector)
+	if err != nil {
+		return err
+	}
+	p.CVSSVersion = s.Version
+	p.CVSSVector = s.Vector
+	p.CVSSBaseScore = s.BaseScore
+	p.CVSSTemporalScore = s.TemporalScore
+	p.CVSSEnvironmentalScore = s.EnvironmentalScore
+	p.CVSSScore = s.Score
+	return nil
+}
+
+func WithRiskParam_CVSS(vector string) RiskParamsOpt {
+	return func(r *Risk) {
+		if err := r.SetCVSS(vector); err != nil {
+			log.Warnf("set risk cvss failed: %s", err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package yakit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+func TestParseCVSSVector(t *testing.T) {
+	s, err := ParseCVSSVector("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	require.NoError(t, err)
+	assert.Equal(t, CVSSVersion31, s.Version)
+	assert.Equal(t, 9.8, s.BaseScore)
+	assert.Equal(t, 9.8, s.Score)
+	assert.Equal(t, "critical", s.Severity())
+
+	s, err = ParseCVSSVector("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P/RL:O/RC:C")
+	require.NoError(t, err)
+	assert.Equal(t, 9.8, s.BaseScore)
+	assert.Equal(t, 8.8, s.TemporalScore)
+	assert.Equal(t, 8.8, s.Score)
+	assert.Equal(t, "high", s.Severity())
+
+	s, err = ParseCVSSVector("CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N")
+	require.NoError(t, err)
+	assert.Equal(t, CVSSVersion30, s.Version)
+	assert.Equal(t, 6.1, s.Score)
+	assert.Equal(t, "warning", s.Severity())
+
+	s, err = ParseCVSSVector("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	require.NoError(t, err)
+	assert.Equal(t, CVSSVersion40, s.Version)
+	assert.Equal(t, 9.3, s.BaseScore)
+	assert.Equal(t, 9.3, s.Score)
+
+	s, err = ParseCVSSVector("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U")
+	require.NoError(t, err)
+	assert.Equal(t, 9.3, s.BaseScore)
+	assert.Less(t, s.TemporalScore, s.BaseScore)
+	assert.Equal(t, s.TemporalScore, s.Score)
+
+	for _, vector := range []string{
+		"",
+		"AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H",
+	} {
+		_, err := ParseCVSSVector(vector)
+		assert.Error(t, err, vector)
+	}
+}
+
+func TestQueryRisksByCVSSScore(t *testing.T) {
+	InitialDatabase()
+	InitializeDefaultDatabaseSchema()
+	db := consts.GetGormProjectDatabase()
+
+	token := utils.RandStringBytes(10)
+	u := "http://127.0.0.1:8787/" + token + "/index.php?id=1"
+	for i, vector := range []string{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N",
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+	} {
+		r := _createRisk(u, WithRiskParam_Title(token), WithRiskParam_Parameter(utils.InterfaceToString(i)), WithRiskParam_CVSS(vector))
+		require.NoError(t, _saveRisk(r))
+		saved, err := GetRiskByHash(db, r.Hash)
+		require.NoError(t, err)
+		defer DeleteRiskByID(db, int64(saved.ID))
+	}
+
+	_, risks, err := QueryRisks(db, &ypb.QueryRisksRequest{
+		Search:       token,
+		MinCVSSScore: 7,
+		Pagination:   &ypb.Paging{Page: 1, Limit: 10, OrderBy: "cvss_score", Order: "desc"},
+	})
+	require.NoError(t, err)
+	require.Len(t, risks, 2)
+	assert.Equal(t, 9.8, risks[0].CVSSScore)
+	assert.Equal(t, "critical", risks[0].Severity)
+	assert.Equal(t, CVSSVersion40, risks[1].CVSSVersion)
+	assert.Equal(t, 9.3, risks[1].ToGRPCModel().GetCVSSScore())
+
+	_, risks, err = QueryRisks(db, &ypb.QueryRisksRequest{
+		Search:       token,
+		MaxCVSSScore: 7,
+	})
+	require.NoError(t, err)
+	require.Len(t, risks, 1)
+	assert.Equal(t, "warning", risks[0].Severity)
+	assert.Equal(t, 6.1, risks[0].CVSSBaseScore)
+}
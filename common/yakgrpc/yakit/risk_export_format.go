@@ -0,0 +1,693 @@
+package yakit
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+const (
+	RiskExportFormatSARIF      = "sarif"
+	RiskExportFormatJUnit      = "junit"
+	RiskExportFormatDefectDojo = "defectdojo"
+)
+
+var riskSeverityRanks = map[string]int{
+	"info":     0,
+	"low":      1,
+	"warning":  2,
+	"high":     3,
+	"critical": 4,
+}
+
+// RiskSeverityRank 返回漏洞等级的高低，用于与阈值比较
+func RiskSeverityRank(severity string) int {
+	return riskSeverityRanks[NormalizeRiskSeverity(severity)]
+}
+
+// RiskExportConfig 为导出漏洞时的配置
+// FailSeverity 仅影响 JUnit：达到该等级且未被研判为误报 / 已修复的漏洞记为失败的用例，为空时使用 low
+type RiskExportConfig struct {
+	Format       string
+	FailSeverity string
+	ToolName     string
+	ToolVersion  string
+}
+
+func (c *RiskExportConfig) toolName() string {
+	if c == nil || c.ToolName == "" {
+		return "yaklang"
+	}
+	return c.ToolName
+}
+
+func (c *RiskExportConfig) failRank() int {
+	if c == nil || c.FailSeverity == "" {
+		return riskSeverityRanks["low"]
+	}
+	return RiskSeverityRank(c.FailSeverity)
+}
+
+// IsRiskExportFailure 判断漏洞在 CI 门禁中是否应当视为失败
+func IsRiskExportFailure(r *Risk, failSeverity string) bool {
+	return (&RiskExportConfig{FailSeverity: failSeverity}).isFailure(r)
+}
+
+func (c *RiskExportConfig) isFailure(r *Risk) bool {
+	switch r.GetTriageStatus() {
+	case RiskTriageStatusFalsePositive, RiskTriageStatusFixed:
+		return false
+	}
+	return RiskSeverityRank(r.Severity) >= c.failRank()
+}
+
+// ExportRisks 把漏洞序列化为 SARIF 2.1.0、JUnit XML 或 DefectDojo Generic Findings JSON，同时返回 CI 门禁中失败的漏洞数量
+func ExportRisks(risks []*Risk, c *RiskExportConfig) ([]byte, int, error) {
+	if c == nil {
+		c = &RiskExportConfig{}
+	}
+	failures := 0
+	for _, r := range risks {
+		if c.isFailure(r) {
+			failures++
+		}
+	}
+
+	var (
+		raw []byte
+		err error
+	)
+	switch strings.ToLower(c.Format) {
+	case RiskExportFormatSARIF:
+		raw, err = RisksToSARIF(risks, c)
+	case RiskExportFormatJUnit:
+		raw, err = RisksToJUnit(risks, c)
+	case RiskExportFormatDefectDojo, "generic", "dojo":
+		raw, err = RisksToDefectDojo(risks)
+	default:
+		return nil, 0, utils.Errorf("unsupported risk export format: %v", c.Format)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return raw, failures, nil
+}
+
+// RiskExportFileExt 返回导出格式对应的文件后缀
+func RiskExportFileExt(format string) string {
+	switch strings.ToLower(format) {
+	case RiskExportFormatSARIF:
+		return ".sarif"
+	case RiskExportFormatJUnit:
+		return ".xml"
+	default:
+		return ".json"
+	}
+}
+
+// riskExportColumns 为导出及其过滤条件依赖的、后续版本才加入的字段
+var riskExportColumns = []string{"triage_status", "fingerprint", "cvss_version", "cvss_vector", "cvss_score"}
+
+// CheckRiskExportSchema 只读检查数据库中的漏洞表是否可以导出，导出不会迁移（修改）用户指定的数据库，
+// 旧版本的数据库需要先用新版本打开升级
+func CheckRiskExportSchema(db *gorm.DB) error {
+	if !db.HasTable(&Risk{}) {
+		return utils.Error("no risks table in project database")
+	}
+	table := db.NewScope(&Risk{}).TableName()
+	var missing []string
+	for _, column := range riskExportColumns {
+		if !db.Dialect().HasColumn(table, column) {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return utils.Errorf("risks table in project database is outdated (missing columns: %v), open it with the latest yakit to upgrade", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// QueryRisksForExport 按 QueryRisks 的过滤条件读取全部漏洞（忽略分页），onProgress 用于反馈读取进度
+func QueryRisksForExport(db *gorm.DB, ctx context.Context, filter *ypb.QueryRisksRequest, onProgress func(count, total int)) ([]*Risk, error) {
+	if filter == nil {
+		filter = &ypb.QueryRisksRequest{}
+	}
+	db, err := FilterByQueryRisks(db, filter)
+	if err != nil {
+		return nil, err
+	}
+	var total int
+	if db := db.Count(&total); db.Error != nil {
+		return nil, utils.Errorf("count risks failed: %s", db.Error)
+	}
+
+	risks := make([]*Risk, 0, total)
+	for r := range YieldRisks(db, ctx) {
+		risks = append(risks, r)
+		if onProgress != nil {
+			onProgress(len(risks), total)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return risks, nil
+}
+
+func (p *Risk) exportTitle() string {
+	if p.TitleVerbose != "" {
+		return p.TitleVerbose
+	}
+	return p.Title
+}
+
+func (p *Risk) detailsJSON() string {
+	details, _ := strconv.Unquote(p.Details)
+	if details == "" {
+		details = p.Details
+	}
+	return details
+}
+
+// RiskCodeLocation 为代码审计类漏洞在 details 中记录的源码位置，与 details 中的其他字段平铺在同一层，
+// 行号从 1 开始，列号与 SSA 的 range 一致从 0 开始
+type RiskCodeLocation struct {
+	FileName    string `json:"file_name"`
+	StartLine   int64  `json:"start_line"`
+	StartColumn int64  `json:"start_column"`
+	EndLine     int64  `json:"end_line,omitempty"`
+	EndColumn   int64  `json:"end_column,omitempty"`
+	Code        string `json:"code,omitempty"`
+}
+
+// Details 把源码位置写入漏洞 details，用于 WithRiskParam_Details
+func (l *RiskCodeLocation) Details(details map[string]any) map[string]any {
+	if details == nil {
+		details = make(map[string]any)
+	}
+	details["file_name"] = l.FileName
+	details["start_line"] = l.StartLine
+	details["start_column"] = l.StartColumn
+	if l.EndLine > 0 {
+		details["end_line"] = l.EndLine
+		details["end_column"] = l.EndColumn
+	}
+	if l.Code != "" {
+		details["code"] = l.Code
+	}
+	return details
+}
+
+func (p *Risk) codeLocation() *RiskCodeLocation {
+	var loc RiskCodeLocation
+	if err := json.Unmarshal([]byte(p.detailsJSON()), &loc); err != nil || loc.FileName == "" {
+		return nil
+	}
+	return &loc
+}
+
+// SARIF 2.1.0 中用到的部分结构
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type SARIFLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    *SARIFTool     `json:"tool"`
+	Results []*SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver *SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string       `json:"name"`
+	Version        string       `json:"version,omitempty"`
+	InformationUri string       `json:"informationUri,omitempty"`
+	Rules          []*SARIFRule `json:"rules"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFRule struct {
+	Id               string         `json:"id"`
+	Name             string         `json:"name"`
+	ShortDescription *SARIFMessage  `json:"shortDescription"`
+	FullDescription  *SARIFMessage  `json:"fullDescription,omitempty"`
+	Help             *SARIFMessage  `json:"help,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+type SARIFResult struct {
+	RuleId              string              `json:"ruleId"`
+	RuleIndex           int                 `json:"ruleIndex"`
+	Level               string              `json:"level"`
+	Message             *SARIFMessage       `json:"message"`
+	Locations           []*SARIFLocation    `json:"locations,omitempty"`
+	PartialFingerprints map[string]string   `json:"partialFingerprints,omitempty"`
+	Suppressions        []*SARIFSuppression `json:"suppressions,omitempty"`
+	Properties          map[string]any      `json:"properties,omitempty"`
+}
+
+type SARIFSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation *SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion           `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type SARIFRegion struct {
+	StartLine   int64         `json:"startLine,omitempty"`
+	StartColumn int64         `json:"startColumn,omitempty"`
+	EndLine     int64         `json:"endLine,omitempty"`
+	EndColumn   int64         `json:"endColumn,omitempty"`
+	Snippet     *SARIFMessage `json:"snippet,omitempty"`
+}
+
+// SARIFLevel 把漏洞等级转换为 SARIF 中的 level
+func SARIFLevel(severity string) string {
+	switch NormalizeRiskSeverity(severity) {
+	case "critical", "high":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverity 为 GitHub Code Scanning 等平台使用的 security-severity，没有 CVSS 评分时按等级估算
+func sarifSecuritySeverity(r *Risk) float64 {
+	if r.CVSSVersion != "" {
+		return r.CVSSScore
+	}
+	switch NormalizeRiskSeverity(r.Severity) {
+	case "critical":
+		return 9.0
+	case "high":
+		return 7.0
+	case "warning":
+		return 5.0
+	case "low":
+		return 2.0
+	default:
+		return 0
+	}
+}
+
+func riskSARIFLocation(r *Risk) *SARIFLocation {
+	if loc := r.codeLocation(); loc != nil {
+		// SARIF 中的列从 1 开始
+		region := &SARIFRegion{
+			StartLine:   loc.StartLine,
+			StartColumn: loc.StartColumn + 1,
+		}
+		if loc.EndLine > 0 {
+			region.EndLine, region.EndColumn = loc.EndLine, loc.EndColumn+1
+		}
+		if loc.Code != "" {
+			region.Snippet = &SARIFMessage{Text: loc.Code}
+		}
+		if region.StartLine <= 0 {
+			region = nil
+		}
+		return &SARIFLocation{PhysicalLocation: &SARIFPhysicalLocation{
+			ArtifactLocation: &SARIFArtifactLocation{Uri: loc.FileName},
+			Region:           region,
+		}}
+	}
+	if r.Url != "" {
+		return &SARIFLocation{PhysicalLocation: &SARIFPhysicalLocation{
+			ArtifactLocation: &SARIFArtifactLocation{Uri: r.Url},
+		}}
+	}
+	return nil
+}
+
+// RisksToSARIF 把漏洞输出为 SARIF 2.1.0，按漏洞类型生成规则，代码审计类漏洞带有源码位置，Web 漏洞以 URL 作为位置
+func RisksToSARIF(risks []*Risk, c *RiskExportConfig) ([]byte, error) {
+	driver := &SARIFDriver{
+		Name:           c.toolName(),
+		InformationUri: "https://yaklang.io",
+		Rules:          make([]*SARIFRule, 0),
+	}
+	if c != nil {
+		driver.Version = c.ToolVersion
+	}
+	ruleIndex := make(map[string]int)
+	run := &SARIFRun{Tool: &SARIFTool{Driver: driver}, Results: make([]*SARIFResult, 0, len(risks))}
+	for _, r := range risks {
+		ruleId := r.RiskType
+		if ruleId == "" {
+			ruleId = "info"
+		}
+		index, ok := ruleIndex[ruleId]
+		if !ok {
+			index = len(driver.Rules)
+			ruleIndex[ruleId] = index
+			name := r.RiskTypeVerbose
+			if name == "" {
+				name = ruleId
+			}
+			rule := &SARIFRule{
+				Id:               ruleId,
+				Name:             name,
+				ShortDescription: &SARIFMessage{Text: name},
+				Properties:       map[string]any{"tags": []string{"security"}},
+			}
+			if r.Description != "" {
+				rule.FullDescription = &SARIFMessage{Text: r.Description}
+			}
+			if r.Solution != "" {
+				rule.Help = &SARIFMessage{Text: r.Solution}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+		// 规则的 security-severity 取该类型下最高的评分
+		rule := driver.Rules[index]
+		if score := sarifSecuritySeverity(r); score > 0 {
+			current, _ := strconv.ParseFloat(utils.InterfaceToString(rule.Properties["security-severity"]), 64)
+			if score > current {
+				rule.Properties["security-severity"] = fmt.Sprintf("%.1f", score)
+			}
+		}
+
+		properties := map[string]any{
+			"severity":      NormalizeRiskSeverity(r.Severity),
+			"triage_status": r.GetTriageStatus(),
+		}
+		if r.FromYakScript != "" {
+			properties["plugin"] = r.FromYakScript
+		}
+		if r.CVE != "" {
+			properties["cve"] = r.CVE
+		}
+		if r.CVSSVersion != "" {
+			properties["cvss_vector"] = r.CVSSVector
+			properties["cvss_score"] = r.CVSSScore
+		}
+		if r.Parameter != "" {
+			properties["parameter"] = r.Parameter
+		}
+		result := &SARIFResult{
+			RuleId:     ruleId,
+			RuleIndex:  index,
+			Level:      SARIFLevel(r.Severity),
+			Message:    &SARIFMessage{Text: r.exportTitle()},
+			Properties: properties,
+		}
+		if loc := riskSARIFLocation(r); loc != nil {
+			result.Locations = []*SARIFLocation{loc}
+		}
+		if r.Fingerprint != "" {
+			result.PartialFingerprints = map[string]string{"yakRiskFingerprint/v1": r.Fingerprint}
+		}
+		if r.GetTriageStatus() == RiskTriageStatusFalsePositive {
+			result.Suppressions = []*SARIFSuppression{{Kind: "external", Status: "accepted", Justification: "false positive"}}
+		}
+		run.Results = append(run.Results, result)
+	}
+	return json.MarshalIndent(&SARIFLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []*SARIFRun{run},
+	}, "", "  ")
+}
+
+// JUnit XML，每个插件（没有插件时为漏洞类型）作为一个 testsuite，每个漏洞作为一个 testcase
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func riskJUnitDetail(r *Risk) string {
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, key+": "+value)
+		}
+	}
+	add("Target", utils.StringOr(r.Url, utils.HostPort(r.Host, r.Port)))
+	add("Severity", NormalizeRiskSeverity(r.Severity))
+	add("Type", r.RiskTypeVerbose)
+	add("CVE", r.CVE)
+	if r.CVSSVersion != "" {
+		add("CVSS", fmt.Sprintf("%.1f %s", r.CVSSScore, r.CVSSVector))
+	}
+	add("Parameter", r.Parameter)
+	payload, _ := strconv.Unquote(r.Payload)
+	add("Payload", utils.StringOr(payload, r.Payload))
+	if loc := r.codeLocation(); loc != nil {
+		add("Location", fmt.Sprintf("%s:%d", loc.FileName, loc.StartLine))
+	}
+	add("Description", r.Description)
+	add("Solution", r.Solution)
+	return strings.Join(lines, "\n")
+}
+
+// RisksToJUnit 把漏洞输出为 JUnit XML，达到失败等级的漏洞为失败用例，误报为跳过的用例，已修复与低于阈值的漏洞为通过的用例
+func RisksToJUnit(risks []*Risk, c *RiskExportConfig) ([]byte, error) {
+	if c == nil {
+		c = &RiskExportConfig{}
+	}
+	root := &junitTestSuites{Name: c.toolName()}
+	suites := make(map[string]*junitTestSuite)
+	for _, r := range risks {
+		suiteName := utils.StringOr(r.FromYakScript, r.RiskTypeVerbose, r.RiskType, "default")
+		suite, ok := suites[suiteName]
+		if !ok {
+			suite = &junitTestSuite{Name: suiteName}
+			suites[suiteName] = suite
+			root.Suites = append(root.Suites, suite)
+		}
+		testCase := &junitTestCase{
+			Name:      fmt.Sprintf("[%s] %s", NormalizeRiskSeverity(r.Severity), r.exportTitle()),
+			ClassName: utils.StringOr(r.RiskType, "info"),
+		}
+		detail := riskJUnitDetail(r)
+		switch {
+		case r.GetTriageStatus() == RiskTriageStatusFalsePositive:
+			testCase.Skipped = &junitSkipped{Message: "false positive"}
+			suite.Skipped++
+		case c.isFailure(r):
+			testCase.Failure = &junitFailure{
+				Message: r.exportTitle(),
+				Type:    NormalizeRiskSeverity(r.Severity),
+				Content: detail,
+			}
+			suite.Failures++
+		default:
+			testCase.SystemOut = detail
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	sort.SliceStable(root.Suites, func(i, j int) bool {
+		return root.Suites[i].Name < root.Suites[j].Name
+	})
+	for _, suite := range root.Suites {
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Skipped += suite.Skipped
+	}
+	raw, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, utils.Errorf("marshal junit failed: %s", err)
+	}
+	return append([]byte(xml.Header), raw...), nil
+}
+
+// DefectDojo Generic Findings Import 格式
+// https://documentation.defectdojo.com/integrations/parsers/file/generic/
+type defectDojoReport struct {
+	Findings []*defectDojoFinding `json:"findings"`
+}
+
+type defectDojoFinding struct {
+	Title                 string                `json:"title"`
+	Description           string                `json:"description"`
+	Severity              string                `json:"severity"`
+	Mitigation            string                `json:"mitigation,omitempty"`
+	Date                  string                `json:"date,omitempty"`
+	CVSSv3                string                `json:"cvssv3,omitempty"`
+	CVSSv3Score           float64               `json:"cvssv3_score,omitempty"`
+	FilePath              string                `json:"file_path,omitempty"`
+	Line                  int64                 `json:"line,omitempty"`
+	Param                 string                `json:"param,omitempty"`
+	Payload               string                `json:"payload,omitempty"`
+	UniqueIdFromTool      string                `json:"unique_id_from_tool,omitempty"`
+	VulnIdFromTool        string                `json:"vuln_id_from_tool,omitempty"`
+	StaticFinding         bool                  `json:"static_finding"`
+	DynamicFinding        bool                  `json:"dynamic_finding"`
+	Active                bool                  `json:"active"`
+	Verified              bool                  `json:"verified"`
+	FalsePositive         bool                  `json:"false_p"`
+	IsMitigated           bool                  `json:"is_mitigated"`
+	VulnerabilityIds      []*defectDojoVulnId   `json:"vulnerability_ids,omitempty"`
+	Endpoints             []*defectDojoEndpoint `json:"endpoints,omitempty"`
+	Tags                  []string              `json:"tags,omitempty"`
+	StepsToReproduce      string                `json:"steps_to_reproduce,omitempty"`
+	SeverityJustification string                `json:"severity_justification,omitempty"`
+}
+
+type defectDojoVulnId struct {
+	VulnerabilityId string `json:"vulnerability_id"`
+}
+
+type defectDojoEndpoint struct {
+	Protocol string `json:"protocol,omitempty"`
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Query    string `json:"query,omitempty"`
+}
+
+func defectDojoSeverity(severity string) string {
+	switch NormalizeRiskSeverity(severity) {
+	case "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "warning":
+		return "Medium"
+	case "low":
+		return "Low"
+	default:
+		return "Info"
+	}
+}
+
+func riskDefectDojoEndpoint(r *Risk) *defectDojoEndpoint {
+	if r.Url != "" {
+		if u, err := url.Parse(r.Url); err == nil && u.Hostname() != "" {
+			port, _ := strconv.Atoi(u.Port())
+			if port <= 0 {
+				port = r.Port
+			}
+			return &defectDojoEndpoint{
+				Protocol: u.Scheme,
+				Host:     u.Hostname(),
+				Port:     port,
+				Path:     strings.TrimPrefix(u.Path, "/"),
+				Query:    u.RawQuery,
+			}
+		}
+	}
+	host := utils.StringOr(r.Host, r.IP)
+	if host == "" {
+		return nil
+	}
+	return &defectDojoEndpoint{Host: host, Port: r.Port}
+}
+
+// RisksToDefectDojo 把漏洞输出为 DefectDojo 等漏洞管理平台可导入的 Generic Findings JSON，研判状态映射为 verified / false_p / is_mitigated
+func RisksToDefectDojo(risks []*Risk) ([]byte, error) {
+	report := &defectDojoReport{Findings: make([]*defectDojoFinding, 0, len(risks))}
+	for _, r := range risks {
+		status := r.GetTriageStatus()
+		finding := &defectDojoFinding{
+			Title:            r.exportTitle(),
+			Description:      utils.StringOr(r.Description, r.exportTitle()),
+			Severity:         defectDojoSeverity(r.Severity),
+			Mitigation:       r.Solution,
+			Date:             r.CreatedAt.Format("2006-01-02"),
+			Param:            r.Parameter,
+			UniqueIdFromTool: r.Fingerprint,
+			VulnIdFromTool:   r.RiskType,
+			Active:           status != RiskTriageStatusFixed && status != RiskTriageStatusFalsePositive,
+			Verified:         status == RiskTriageStatusConfirmed || status == RiskTriageStatusReopened,
+			FalsePositive:    status == RiskTriageStatusFalsePositive,
+			IsMitigated:      status == RiskTriageStatusFixed,
+			StepsToReproduce: riskJUnitDetail(r),
+		}
+		if r.CreatedAt.IsZero() {
+			finding.Date = ""
+		}
+		if payload, err := strconv.Unquote(r.Payload); err == nil {
+			finding.Payload = payload
+		} else {
+			finding.Payload = r.Payload
+		}
+		if strings.HasPrefix(r.CVSSVector, "CVSS:3") {
+			finding.CVSSv3 = r.CVSSVector
+			finding.CVSSv3Score = r.CVSSScore
+		}
+		if r.CVSSVersion != "" {
+			finding.SeverityJustification = fmt.Sprintf("CVSS %s: %.1f", r.CVSSVersion, r.CVSSScore)
+		}
+		if r.CVE != "" {
+			finding.VulnerabilityIds = []*defectDojoVulnId{{VulnerabilityId: r.CVE}}
+		}
+		if loc := r.codeLocation(); loc != nil {
+			finding.StaticFinding = true
+			finding.FilePath = loc.FileName
+			finding.Line = loc.StartLine
+		} else {
+			finding.DynamicFinding = true
+			if endpoint := riskDefectDojoEndpoint(r); endpoint != nil {
+				finding.Endpoints = []*defectDojoEndpoint{endpoint}
+			}
+		}
+		if r.FromYakScript != "" {
+			finding.Tags = []string{r.FromYakScript}
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
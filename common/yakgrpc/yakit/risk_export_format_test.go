@@ -0,0 +1,199 @@
+package yakit
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+func newExportTestRisks() []*Risk {
+	web := _createRisk("http://127.0.0.1:8787/admin/login.php?id=1",
+		WithRiskParam_Title("SQL Injection"),
+		WithRiskParam_TitleVerbose("SQL 注入"),
+		WithRiskParam_RiskType("sqli"),
+		WithRiskParam_Severity("high"),
+		WithRiskParam_Parameter("id"),
+		WithRiskParam_Payload("1'"),
+		WithRiskParam_CVE("CVE-2024-0001"),
+		WithRiskParam_CVSS("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+		WithRiskParam_FromScript("sqli-scanner"),
+	)
+	web.Fingerprint = CalcRiskFingerprint(web)
+	web.TriageStatus = RiskTriageStatusConfirmed
+	web.CreatedAt = time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+
+	code := _createRisk("127.0.0.1",
+		WithRiskParam_Title("command injection"),
+		WithRiskParam_RiskType("rce-command"),
+		WithRiskParam_Severity("critical"),
+		WithRiskParam_Details(map[string]any{"file_name": "src/main.go", "start_line": 12, "start_column": 3, "code": "exec.Command(cmd)"}),
+	)
+
+	fp := _createRisk("http://127.0.0.1:8787/xss",
+		WithRiskParam_Title("xss"),
+		WithRiskParam_RiskType("xss"),
+		WithRiskParam_Severity("middle"),
+	)
+	fp.TriageStatus = RiskTriageStatusFalsePositive
+
+	info := _createRisk("http://127.0.0.1:8787/",
+		WithRiskParam_Title("server banner"),
+		WithRiskParam_Severity("info"),
+	)
+	return []*Risk{web, code, fp, info}
+}
+
+func TestRisksToSARIF(t *testing.T) {
+	raw, failures, err := ExportRisks(newExportTestRisks(), &RiskExportConfig{Format: RiskExportFormatSARIF})
+	require.NoError(t, err)
+	assert.Equal(t, 2, failures)
+
+	var log SARIFLog
+	require.NoError(t, json.Unmarshal(raw, &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 4)
+	require.Len(t, run.Results, 4)
+
+	web := run.Results[0]
+	assert.Equal(t, "sqli", web.RuleId)
+	assert.Equal(t, "error", web.Level)
+	assert.Equal(t, "SQL 注入", web.Message.Text)
+	assert.Equal(t, "http://127.0.0.1:8787/admin/login.php?id=1", web.Locations[0].PhysicalLocation.ArtifactLocation.Uri)
+	assert.NotEmpty(t, web.PartialFingerprints["yakRiskFingerprint/v1"])
+	assert.Equal(t, "9.8", run.Tool.Driver.Rules[0].Properties["security-severity"])
+
+	code := run.Results[1]
+	assert.Equal(t, "src/main.go", code.Locations[0].PhysicalLocation.ArtifactLocation.Uri)
+	assert.EqualValues(t, 12, code.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.EqualValues(t, 4, code.Locations[0].PhysicalLocation.Region.StartColumn)
+	assert.Equal(t, "exec.Command(cmd)", code.Locations[0].PhysicalLocation.Region.Snippet.Text)
+
+	assert.Equal(t, "warning", run.Results[2].Level)
+	require.Len(t, run.Results[2].Suppressions, 1)
+	assert.Equal(t, "note", run.Results[3].Level)
+	assert.Empty(t, run.Results[3].Suppressions)
+}
+
+func TestRisksToJUnit(t *testing.T) {
+	raw, failures, err := ExportRisks(newExportTestRisks(), &RiskExportConfig{Format: RiskExportFormatJUnit, FailSeverity: "high"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, failures)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(raw, &suites))
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+	for _, suite := range suites.Suites {
+		for _, c := range suite.Cases {
+			switch c.ClassName {
+			case "sqli":
+				require.NotNil(t, c.Failure)
+				assert.Equal(t, "sqli-scanner", suite.Name)
+				assert.Contains(t, c.Failure.Content, "CVE: CVE-2024-0001")
+				assert.Contains(t, c.Failure.Content, "Payload: 1'")
+			case "rce-command":
+				require.NotNil(t, c.Failure)
+				assert.Contains(t, c.Failure.Content, "Location: src/main.go:12")
+			case "xss":
+				assert.NotNil(t, c.Skipped)
+			default:
+				assert.Nil(t, c.Failure)
+			}
+		}
+	}
+
+	_, failures, err = ExportRisks(newExportTestRisks(), &RiskExportConfig{Format: RiskExportFormatJUnit, FailSeverity: "critical"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+}
+
+func TestRisksToDefectDojo(t *testing.T) {
+	raw, _, err := ExportRisks(newExportTestRisks(), &RiskExportConfig{Format: RiskExportFormatDefectDojo})
+	require.NoError(t, err)
+
+	var report defectDojoReport
+	require.NoError(t, json.Unmarshal(raw, &report))
+	require.Len(t, report.Findings, 4)
+
+	web := report.Findings[0]
+	assert.Equal(t, "High", web.Severity)
+	assert.Equal(t, "2024-05-01", web.Date)
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", web.CVSSv3)
+	assert.Equal(t, 9.8, web.CVSSv3Score)
+	assert.Equal(t, "CVE-2024-0001", web.VulnerabilityIds[0].VulnerabilityId)
+	assert.True(t, web.Verified)
+	assert.True(t, web.DynamicFinding)
+	assert.Equal(t, "1'", web.Payload)
+	require.Len(t, web.Endpoints, 1)
+	assert.Equal(t, &defectDojoEndpoint{Protocol: "http", Host: "127.0.0.1", Port: 8787, Path: "admin/login.php", Query: "id=1"}, web.Endpoints[0])
+
+	code := report.Findings[1]
+	assert.Equal(t, "Critical", code.Severity)
+	assert.True(t, code.StaticFinding)
+	assert.Equal(t, "src/main.go", code.FilePath)
+	assert.EqualValues(t, 12, code.Line)
+
+	assert.Equal(t, "Medium", report.Findings[2].Severity)
+	assert.True(t, report.Findings[2].FalsePositive)
+	assert.False(t, report.Findings[2].Active)
+	assert.Equal(t, "Info", report.Findings[3].Severity)
+
+	_, _, err = ExportRisks(nil, &RiskExportConfig{Format: "csv"})
+	require.Error(t, err)
+}
+
+func TestQueryRisksForExport(t *testing.T) {
+	InitialDatabase()
+	InitializeDefaultDatabaseSchema()
+	db := consts.GetGormProjectDatabase()
+
+	token := utils.RandStringBytes(10)
+	for i := 0; i < 20; i++ {
+		r := _createRisk("http://127.0.0.1:8787/"+token, WithRiskParam_Title(token), WithRiskParam_Parameter(strconv.Itoa(i)), WithRiskParam_Severity("high"))
+		require.NoError(t, _saveRisk(r))
+		saved, err := GetRiskByHash(db, r.Hash)
+		require.NoError(t, err)
+		defer DeleteRiskByID(db, int64(saved.ID))
+	}
+
+	var progress int
+	risks, err := QueryRisksForExport(db, context.Background(), &ypb.QueryRisksRequest{Search: token}, func(count, total int) {
+		progress = count
+		assert.Equal(t, 20, total)
+	})
+	require.NoError(t, err)
+	assert.Len(t, risks, 20)
+	assert.Equal(t, 20, progress)
+}
+
+func TestCheckRiskExportSchema(t *testing.T) {
+	db, err := gorm.Open("sqlite3", filepath.Join(t.TempDir(), "project.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	assert.Error(t, CheckRiskExportSchema(db))
+
+	// 旧版本数据库的漏洞表没有研判 / CVSS 字段，检查只报错，不能修改数据库
+	require.NoError(t, db.Exec("CREATE TABLE risks (id integer primary key, hash varchar(255), title varchar(255))").Error)
+	err = CheckRiskExportSchema(db)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "triage_status")
+	assert.False(t, db.Dialect().HasColumn("risks", "triage_status"))
+
+	require.NoError(t, db.AutoMigrate(&Risk{}).Error)
+	assert.NoError(t, CheckRiskExportSchema(db))
+}
@@ -282,22 +282,27 @@ func WithRiskParam_CVE(s string) RiskParamsOpt {
 	}
 }
 
+// NormalizeRiskSeverity 把各种写法的漏洞等级统一为 critical / high / warning / low / info
+func NormalizeRiskSeverity(i string) string {
+	switch strings.TrimSpace(strings.ToLower(i)) {
+	case "high":
+		return "high"
+	case "critical", "panic", "fatal":
+		return "critical"
+	case "warning", "warn", "middle", "medium":
+		return "warning"
+	case "info", "debug", "trace", "fingerprint", "note", "fp":
+		return "info"
+	case "low", "default":
+		return "low"
+	default:
+		return "low"
+	}
+}
+
 func WithRiskParam_Severity(i string) RiskParamsOpt {
 	return func(r *Risk) {
-		switch strings.TrimSpace(strings.ToLower(i)) {
-		case "high":
-			r.Severity = "high"
-		case "critical", "panic", "fatal":
-			r.Severity = "critical"
-		case "warning", "warn", "middle", "medium":
-			r.Severity = "warning"
-		case "info", "debug", "trace", "fingerprint", "note", "fp":
-			r.Severity = "info"
-		case "low", "default":
-			r.Severity = "low"
-		default:
-			r.Severity = "low"
-		}
+		r.Severity = NormalizeRiskSeverity(i)
 	}
 }
 
@@ -426,11 +431,31 @@ func _saveRisk(r *Risk) error {
 		if cveData != nil {
 			r.CveAccessVector = cveData.AccessVector
 			r.CveAccessComplexity = cveData.AccessComplexity
+			// 插件未指定向量时使用 CVE 库中的 CVSS 3.x 向量
+			if r.CVSSVector == "" && strings.HasPrefix(cveData.CVSSVectorString, "CVSS:3") {
+				if err := r.SetCVSS(cveData.CVSSVectorString); err != nil {
+					log.Warnf("set cvss from %v failed: %s", r.CVE, err)
+				}
+			}
 		}
 	}
 	if r.Description == "" && r.Solution == "" {
 		r.Description, r.Solution = SolutionAndDescriptionByCWE(r.FromYakScript, r.RiskTypeVerbose, r.TitleVerbose)
 	}
+	// 与历史扫描中指纹相同的漏洞合并到已有记录，不再新建
+	merged, err := MergeDuplicateRisk(db, r)
+	if err != nil {
+		log.Errorf("merge duplicate risk failed: %s", err)
+	}
+	if merged {
+		return nil
+	}
+	if r.SeenCount <= 0 {
+		r.SeenCount = 1
+	}
+	r.LastSeenAt = time.Now().Unix()
+	r.LastRuntimeId = r.RuntimeId
+
 	count := 0
 	for {
 		count++
@@ -0,0 +1,377 @@
+package yakit
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/utils"
+	"github.com/yaklang/yaklang/common/yakgrpc/ypb"
+)
+
+const (
+	RiskTriageStatusNew           = "new"
+	RiskTriageStatusConfirmed     = "confirmed"
+	RiskTriageStatusFalsePositive = "false_positive"
+	RiskTriageStatusFixed         = "fixed"
+	RiskTriageStatusReopened      = "reopened"
+)
+
+const (
+	RiskTriageActionStatus     = "status"
+	RiskTriageActionAssign     = "assign"
+	RiskTriageActionComment    = "comment"
+	RiskTriageActionRegression = "regression"
+	RiskTriageActionRetest     = "retest"
+)
+
+// riskTriageTransitions 研判状态允许的流转，key 为当前状态
+var riskTriageTransitions = map[string][]string{
+	RiskTriageStatusNew:           {RiskTriageStatusConfirmed, RiskTriageStatusFalsePositive, RiskTriageStatusFixed},
+	RiskTriageStatusConfirmed:     {RiskTriageStatusFixed, RiskTriageStatusFalsePositive},
+	RiskTriageStatusFalsePositive: {RiskTriageStatusConfirmed, RiskTriageStatusReopened},
+	RiskTriageStatusFixed:         {RiskTriageStatusReopened},
+	RiskTriageStatusReopened:      {RiskTriageStatusConfirmed, RiskTriageStatusFixed, RiskTriageStatusFalsePositive},
+}
+
+// RiskTriageHistory 记录漏洞研判过程中的状态流转、指派、评论与复测结果
+type RiskTriageHistory struct {
+	gorm.Model
+
+	RiskId     int64  `json:"risk_id" gorm:"index"`
+	Action     string `json:"action"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Assignee   string `json:"assignee"`
+	Comment    string `json:"comment"`
+	Operator   string `json:"operator"`
+	RuntimeId  string `json:"runtime_id"`
+}
+
+// GetTriageStatus 返回漏洞的研判状态，历史数据没有状态时视为 new
+func (p *Risk) GetTriageStatus() string {
+	if p.TriageStatus == "" {
+		return RiskTriageStatusNew
+	}
+	return p.TriageStatus
+}
+
+func IsValidRiskTriageStatus(status string) bool {
+	_, ok := riskTriageTransitions[status]
+	return ok
+}
+
+// CanTransitRiskTriageStatus 判断研判状态是否允许从 from 流转到 to，状态不变视为允许
+func CanTransitRiskTriageStatus(from, to string) bool {
+	if from == "" {
+		from = RiskTriageStatusNew
+	}
+	if from == to {
+		return true
+	}
+	for _, next := range riskTriageTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterRiskByTriageStatus 按研判状态过滤，new 同时匹配没有研判状态的历史数据
+func FilterRiskByTriageStatus(db *gorm.DB, status []string) *gorm.DB {
+	if len(status) <= 0 {
+		return db
+	}
+	var conds []string
+	var items []interface{}
+	for _, s := range status {
+		if s == RiskTriageStatusNew {
+			conds = append(conds, "triage_status = ? OR triage_status = '' OR triage_status IS NULL")
+		} else {
+			conds = append(conds, "triage_status = ?")
+		}
+		items = append(items, s)
+	}
+	return db.Where(strings.Join(conds, " OR "), items...)
+}
+
+// riskFingerprintDigitRegexp 优先匹配 CVE/CNVD/CNNVD 编号，编号本身是区分漏洞的关键信息，不能被归一化
+var riskFingerprintDigitRegexp = regexp.MustCompile(`(?i)\b(?:cve|cnvd|cnnvd)-\d{4}-\d+|\d+`)
+
+// CalcRiskFingerprint 计算漏洞指纹：插件、漏洞类型、CVE、目标、端口、路径（不含 query）、参数与归一化后的标题，
+// 不包含时间、payload、runtime_id 等每次扫描都会变化的内容，用于跨扫描合并同一个漏洞
+func CalcRiskFingerprint(r *Risk) string {
+	path := ""
+	host := r.Host
+	if r.Url != "" {
+		if u, err := url.Parse(r.Url); err == nil {
+			path = u.Path
+			if host == "" {
+				host = u.Hostname()
+			}
+		} else {
+			path = strings.SplitN(r.Url, "?", 2)[0]
+		}
+	}
+	if path == "" && r.Url != "" {
+		path = "/"
+	}
+	if host == "" {
+		host = r.IP
+	}
+
+	title := r.TitleVerbose
+	if title == "" {
+		title = r.Title
+	}
+	// 标题中经常带有地址、时间等数字，统一替换掉，漏洞编号保留原样
+	title = riskFingerprintDigitRegexp.ReplaceAllStringFunc(strings.ToLower(strings.TrimSpace(title)), func(s string) string {
+		if s[0] >= '0' && s[0] <= '9' {
+			return "#"
+		}
+		return s
+	})
+
+	raw := strings.Join([]string{
+		r.FromYakScript,
+		r.RiskType,
+		strings.ToUpper(r.CVE),
+		strings.ToLower(host),
+		fmt.Sprint(r.Port),
+		path,
+		r.Parameter,
+		title,
+	}, "|")
+	return fmt.Sprintf("%x", sha1.Sum([]byte(raw)))
+}
+
+// MergeDuplicateRisk 查找与 r 指纹相同的已有漏洞，找到时累加出现次数、刷新请求响应并返回 true，
+// 已修复的漏洞再次出现时自动流转为 reopened 并记录一条 regression 历史
+func MergeDuplicateRisk(db *gorm.DB, r *Risk) (bool, error) {
+	// 反连类漏洞需要按 token 单独确认，不参与合并
+	if r.WaitingVerified || r.ReverseToken != "" {
+		return false, nil
+	}
+	if r.Fingerprint == "" {
+		r.Fingerprint = CalcRiskFingerprint(r)
+	}
+
+	var existed Risk
+	if db := db.Model(&Risk{}).Where("fingerprint = ? AND hash <> ?", r.Fingerprint, r.Hash).Order("id asc").First(&existed); db.Error != nil {
+		if gorm.IsRecordNotFoundError(db.Error) {
+			return false, nil
+		}
+		return false, utils.Errorf("query duplicate risk failed: %s", db.Error)
+	}
+
+	seenCount := existed.SeenCount
+	if seenCount <= 0 {
+		seenCount = 1
+	}
+	updates := map[string]interface{}{
+		"seen_count":      seenCount + 1,
+		"last_seen_at":    time.Now().Unix(),
+		"last_runtime_id": r.RuntimeId,
+	}
+	if r.QuotedRequest != "" {
+		updates["quoted_request"] = r.QuotedRequest
+	}
+	if r.QuotedResponse != "" {
+		updates["quoted_response"] = r.QuotedResponse
+	}
+	if r.Payload != "" {
+		updates["payload"] = r.Payload
+	}
+
+	status := existed.GetTriageStatus()
+	if status == RiskTriageStatusFixed {
+		updates["triage_status"] = RiskTriageStatusReopened
+	}
+
+	if db := db.Model(&Risk{}).Where("id = ?", existed.ID).Updates(updates); db.Error != nil {
+		return false, utils.Errorf("merge duplicate risk failed: %s", db.Error)
+	}
+	if status == RiskTriageStatusFixed {
+		err := CreateRiskTriageHistory(db, &RiskTriageHistory{
+			RiskId:     int64(existed.ID),
+			Action:     RiskTriageActionRegression,
+			FromStatus: status,
+			ToStatus:   RiskTriageStatusReopened,
+			Comment:    "fixed risk found again",
+			RuntimeId:  r.RuntimeId,
+		})
+		if err != nil {
+			return true, err
+		}
+	}
+
+	// 调用方持有的 r 指向被合并的记录
+	r.Model = existed.Model
+	r.Hash = existed.Hash
+	r.SeenCount = seenCount + 1
+	r.LastRuntimeId = r.RuntimeId
+	r.RuntimeId = existed.RuntimeId
+	r.TriageStatus = existed.TriageStatus
+	if status == RiskTriageStatusFixed {
+		r.TriageStatus = RiskTriageStatusReopened
+	}
+	r.Assignee = existed.Assignee
+	return true, nil
+}
+
+func CreateRiskTriageHistory(db *gorm.DB, h *RiskTriageHistory) error {
+	if db := db.Model(&RiskTriageHistory{}).Save(h); db.Error != nil {
+		return utils.Errorf("save risk triage history failed: %s", db.Error)
+	}
+	return nil
+}
+
+func QueryRiskTriageHistory(db *gorm.DB, riskId int64) ([]*RiskTriageHistory, error) {
+	var histories []*RiskTriageHistory
+	if db := db.Model(&RiskTriageHistory{}).Where("risk_id = ?", riskId).Order("id asc").Find(&histories); db.Error != nil {
+		return nil, utils.Errorf("query risk triage history failed: %s", db.Error)
+	}
+	return histories, nil
+}
+
+type RiskTriageUpdate struct {
+	Ids           []int64
+	TriageStatus  string
+	Assignee      string
+	ClearAssignee bool
+	Comment       string
+	Operator      string
+}
+
+// UpdateRiskTriage 批量更新漏洞研判状态 / 指派人 / 评论，任意一条状态流转不合法时整体不生效
+func UpdateRiskTriage(db *gorm.DB, update *RiskTriageUpdate) error {
+	if len(update.Ids) <= 0 {
+		return utils.Error("empty risk ids")
+	}
+	if update.TriageStatus != "" && !IsValidRiskTriageStatus(update.TriageStatus) {
+		return utils.Errorf("invalid triage status: %v", update.TriageStatus)
+	}
+
+	var risks []*Risk
+	if db := db.Model(&Risk{}).Where("id IN (?)", update.Ids).Find(&risks); db.Error != nil {
+		return utils.Errorf("query risks failed: %s", db.Error)
+	}
+	if len(risks) != len(update.Ids) {
+		return utils.Errorf("some risks not found, expect %v, got %v", len(update.Ids), len(risks))
+	}
+	if update.TriageStatus != "" {
+		for _, r := range risks {
+			if !CanTransitRiskTriageStatus(r.GetTriageStatus(), update.TriageStatus) {
+				return utils.Errorf("risk[%v] cannot transit triage status from %v to %v", r.ID, r.GetTriageStatus(), update.TriageStatus)
+			}
+		}
+	}
+
+	return utils.GormTransaction(db, func(tx *gorm.DB) error {
+		for _, r := range risks {
+			updates := make(map[string]interface{})
+			var histories []*RiskTriageHistory
+			from := r.GetTriageStatus()
+			if update.TriageStatus != "" && update.TriageStatus != from {
+				updates["triage_status"] = update.TriageStatus
+				histories = append(histories, &RiskTriageHistory{
+					Action:     RiskTriageActionStatus,
+					FromStatus: from,
+					ToStatus:   update.TriageStatus,
+					Comment:    update.Comment,
+				})
+			}
+			assignee := update.Assignee
+			if update.ClearAssignee {
+				assignee = ""
+			}
+			if (update.ClearAssignee || update.Assignee != "") && assignee != r.Assignee {
+				updates["assignee"] = assignee
+				histories = append(histories, &RiskTriageHistory{
+					Action:   RiskTriageActionAssign,
+					Assignee: assignee,
+				})
+			}
+			if len(histories) <= 0 && update.Comment != "" {
+				histories = append(histories, &RiskTriageHistory{
+					Action:  RiskTriageActionComment,
+					Comment: update.Comment,
+				})
+			}
+
+			if len(updates) > 0 {
+				if db := tx.Model(&Risk{}).Where("id = ?", r.ID).Updates(updates); db.Error != nil {
+					return utils.Errorf("update risk[%v] triage failed: %s", r.ID, db.Error)
+				}
+			}
+			for _, h := range histories {
+				h.RiskId = int64(r.ID)
+				h.Operator = update.Operator
+				if h.FromStatus == "" {
+					h.FromStatus, h.ToStatus = from, from
+				}
+				if err := CreateRiskTriageHistory(tx, h); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// ApplyRiskRetestResult 根据复测结果更新漏洞状态：仍然存在时已修复的漏洞重新打开，不存在时标记为已修复，
+// 误报状态保持不变；返回流转后的状态
+func ApplyRiskRetestResult(db *gorm.DB, riskId int64, vulnerable bool, runtimeId string, operator string) (string, error) {
+	r, err := GetRisk(db, riskId)
+	if err != nil {
+		return "", err
+	}
+
+	from := r.GetTriageStatus()
+	to := from
+	switch {
+	case from == RiskTriageStatusFalsePositive:
+	case vulnerable && from == RiskTriageStatusFixed:
+		to = RiskTriageStatusReopened
+	case !vulnerable:
+		to = RiskTriageStatusFixed
+	}
+
+	comment := "still vulnerable"
+	if !vulnerable {
+		comment = "not reproduced"
+	}
+	if to != from {
+		if db := db.Model(&Risk{}).Where("id = ?", r.ID).Update("triage_status", to); db.Error != nil {
+			return "", utils.Errorf("update risk[%v] triage status failed: %s", r.ID, db.Error)
+		}
+	}
+	return to, CreateRiskTriageHistory(db, &RiskTriageHistory{
+		RiskId:     riskId,
+		Action:     RiskTriageActionRetest,
+		FromStatus: from,
+		ToStatus:   to,
+		Comment:    comment,
+		Operator:   operator,
+		RuntimeId:  runtimeId,
+	})
+}
+
+func (h *RiskTriageHistory) ToGRPCModel() *ypb.RiskTriageHistory {
+	return &ypb.RiskTriageHistory{
+		Id:         int64(h.ID),
+		RiskId:     h.RiskId,
+		Action:     h.Action,
+		FromStatus: h.FromStatus,
+		ToStatus:   h.ToStatus,
+		Assignee:   utils.EscapeInvalidUTF8Byte([]byte(h.Assignee)),
+		Comment:    utils.EscapeInvalidUTF8Byte([]byte(h.Comment)),
+		Operator:   utils.EscapeInvalidUTF8Byte([]byte(h.Operator)),
+		RuntimeId:  h.RuntimeId,
+		CreatedAt:  h.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,86 @@
+package yakit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaklang/yaklang/common/consts"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+func TestCalcRiskFingerprint(t *testing.T) {
+	a := &Risk{FromYakScript: "sqli", RiskType: "sqlinjection", Url: "http://example.com/a.php?id=1", Port: 80, Parameter: "id", Title: "SQL Injection at 1.1.1.1"}
+	b := &Risk{FromYakScript: "sqli", RiskType: "sqlinjection", Url: "http://EXAMPLE.com/a.php?id=2&t=3", Port: 80, Parameter: "id", Title: "SQL Injection at 2.2.2.2", Payload: "1'"}
+	assert.Equal(t, CalcRiskFingerprint(a), CalcRiskFingerprint(b))
+
+	c := *b
+	c.Parameter = "name"
+	assert.NotEqual(t, CalcRiskFingerprint(a), CalcRiskFingerprint(&c))
+
+	// CVE 字段为空时，标题中的不同漏洞编号不能被合并
+	log4j := &Risk{FromYakScript: "nuclei", RiskType: "rce", Url: "http://example.com/", Port: 80, Title: "CVE-2021-44228 Log4j RCE at 1.1.1.1"}
+	spring := &Risk{FromYakScript: "nuclei", RiskType: "rce", Url: "http://example.com/", Port: 80, Title: "CVE-2022-22965 Log4j RCE at 1.1.1.1"}
+	assert.NotEqual(t, CalcRiskFingerprint(log4j), CalcRiskFingerprint(spring))
+	again := *log4j
+	again.Title = "cve-2021-44228 Log4j RCE at 2.2.2.2"
+	assert.Equal(t, CalcRiskFingerprint(log4j), CalcRiskFingerprint(&again))
+}
+
+func TestRiskTriageTransition(t *testing.T) {
+	assert.True(t, CanTransitRiskTriageStatus("", RiskTriageStatusConfirmed))
+	assert.True(t, CanTransitRiskTriageStatus(RiskTriageStatusFixed, RiskTriageStatusReopened))
+	assert.False(t, CanTransitRiskTriageStatus(RiskTriageStatusFixed, RiskTriageStatusConfirmed))
+	assert.False(t, CanTransitRiskTriageStatus(RiskTriageStatusNew, RiskTriageStatusReopened))
+	assert.False(t, IsValidRiskTriageStatus("unknown"))
+}
+
+func TestRiskTriageWorkflow(t *testing.T) {
+	InitialDatabase()
+	InitializeDefaultDatabaseSchema()
+	db := consts.GetGormProjectDatabase()
+
+	u := "http://127.0.0.1:8787/" + utils.RandStringBytes(10) + "/index.php?id=1"
+	first := _createRisk(u, WithRiskParam_Title("triage test"), WithRiskParam_RiskType("xss"), WithRiskParam_Parameter("id"), WithRiskParam_RuntimeId("r1"))
+	require.NoError(t, _saveRisk(first))
+
+	risk, err := GetRiskByHash(db, first.Hash)
+	require.NoError(t, err)
+	defer DeleteRiskByID(db, int64(risk.ID))
+	assert.Equal(t, RiskTriageStatusNew, risk.GetTriageStatus())
+	assert.EqualValues(t, 1, risk.SeenCount)
+
+	err = UpdateRiskTriage(db, &RiskTriageUpdate{Ids: []int64{int64(risk.ID)}, TriageStatus: RiskTriageStatusReopened})
+	require.Error(t, err)
+	err = UpdateRiskTriage(db, &RiskTriageUpdate{Ids: []int64{int64(risk.ID)}, TriageStatus: RiskTriageStatusFixed, Assignee: "alice", Operator: "bob"})
+	require.NoError(t, err)
+
+	// 相同指纹的漏洞再次出现时合并到已有记录，已修复的漏洞重新打开
+	second := _createRisk(u, WithRiskParam_Title("triage test"), WithRiskParam_RiskType("xss"), WithRiskParam_Parameter("id"), WithRiskParam_RuntimeId("r2"))
+	require.NoError(t, _saveRisk(second))
+	assert.Equal(t, first.Hash, second.Hash)
+
+	risk, err = GetRisk(db, int64(risk.ID))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, risk.SeenCount)
+	assert.Equal(t, "r2", risk.LastRuntimeId)
+	assert.Equal(t, "alice", risk.Assignee)
+	assert.Equal(t, RiskTriageStatusReopened, risk.TriageStatus)
+
+	count, err := CountRiskByRuntimeId(db, "r2")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, uint32(1))
+
+	status, err := ApplyRiskRetestResult(db, int64(risk.ID), false, "r3", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, RiskTriageStatusFixed, status)
+
+	histories, err := QueryRiskTriageHistory(db, int64(risk.ID))
+	require.NoError(t, err)
+	defer db.Unscoped().Where("risk_id = ?", risk.ID).Delete(&RiskTriageHistory{})
+	var actions []string
+	for _, h := range histories {
+		actions = append(actions, h.Action)
+	}
+	assert.Equal(t, []string{RiskTriageActionStatus, RiskTriageActionAssign, RiskTriageActionRegression, RiskTriageActionRetest}, actions)
+}
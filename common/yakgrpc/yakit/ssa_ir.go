@@ -0,0 +1,243 @@
+package yakit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yaklang/yaklang/common/utils"
+)
+
+// SSAProgram 是一个编译好的 SSA 程序，Hash 与 ssaapi 中 config.CaclHash() 的结果一致
+type SSAProgram struct {
+	gorm.Model
+
+	Hash     string `json:"hash" gorm:"unique_index"`
+	Language string `json:"language"`
+	// 项目的名字（如项目目录），同名的程序在源文件变化时会被增量失效
+	ProgramName string `json:"program_name" gorm:"index"`
+
+	// 入口函数（main）的指令 ID
+	MainFunctionId int64 `json:"main_function_id"`
+	// 源文件发生变化之后，部分 IR 已经被删除，这个程序不能再作为缓存使用
+	Outdated bool `json:"outdated"`
+}
+
+// SSASourceFile 记录编译时每一个源文件的内容 hash，用于判断源文件是否发生变化
+type SSASourceFile struct {
+	gorm.Model
+
+	ProgramHash string `json:"program_hash" gorm:"index"`
+	FileName    string `json:"file_name" gorm:"index"`
+	ContentHash string `json:"content_hash"`
+}
+
+// SSAIrCode 是一个 SSA 指令，函数与基本块也作为指令保存，
+// 指令之间的引用（use-def / 控制流 / 所属关系）都使用指令 ID 表示
+type SSAIrCode struct {
+	gorm.Model
+
+	ProgramHash string `json:"program_hash" gorm:"index"`
+	InstId      int64  `json:"inst_id" gorm:"index"`
+
+	Opcode      string `json:"opcode" gorm:"index"`
+	Name        string `json:"name" gorm:"index"`
+	VerboseName string `json:"verbose_name"`
+	// 所有的变量名，使用 , 分隔，Program.Ref 使用这个字段查询
+	Variables string `json:"variables"`
+	// 反汇编的结果，方便展示
+	Disasm   string `json:"disasm"`
+	TypeStr  string `json:"type_str"`
+	IsExtern bool   `json:"is_extern"`
+
+	// 所属的函数与基本块，函数指令的 FunctionId 是它的父函数
+	FunctionId int64 `json:"function_id" gorm:"index"`
+	BlockId    int64 `json:"block_id"`
+
+	// use-def: Defs 为操作数，Users 为使用这个值的指令
+	Defs  string `json:"defs"`
+	Users string `json:"users"`
+
+	// 函数: 参数 / 返回 / 基本块 / 子函数
+	Params     string `json:"params"`
+	Returns    string `json:"returns"`
+	Blocks     string `json:"blocks"`
+	ChildFuncs string `json:"child_funcs"`
+	// 基本块: 前驱 / 后继 / 包含的指令
+	Preds string `json:"preds"`
+	Succs string `json:"succs"`
+	Insts string `json:"insts"`
+
+	// 位置信息
+	FileName    string `json:"file_name" gorm:"index"`
+	SourceCode  string `json:"source_code"`
+	StartOffset int64  `json:"start_offset"`
+	StartLine   int64  `json:"start_line"`
+	StartColumn int64  `json:"start_column"`
+	EndOffset   int64  `json:"end_offset"`
+	EndLine     int64  `json:"end_line"`
+	EndColumn   int64  `json:"end_column"`
+}
+
+// IdsToString / StringToIds 用于保存 SSAIrCode 中的指令 ID 列表
+func IdsToString(ids []int64) string {
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(items, ",")
+}
+
+func StringToIds(s string) []int64 {
+	ids := make([]int64, 0)
+	for _, item := range utils.PrettifyListFromStringSplited(s, ",") {
+		id, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func GetSSAProgramByHash(db *gorm.DB, hash string) (*SSAProgram, error) {
+	var prog SSAProgram
+	if db := db.Model(&SSAProgram{}).Where("hash = ?", hash).First(&prog); db.Error != nil {
+		return nil, utils.Errorf("get ssa program failed: %s", db.Error)
+	}
+	return &prog, nil
+}
+
+func HasSSAProgram(db *gorm.DB, hash string) bool {
+	var count int
+	if db := db.Model(&SSAProgram{}).Where("hash = ?", hash).Count(&count); db.Error != nil {
+		return false
+	}
+	return count > 0
+}
+
+// SaveSSAProgram 保存程序以及它的源文件与指令，已经存在的同一个 hash 的程序会被覆盖
+func SaveSSAProgram(db *gorm.DB, prog *SSAProgram, files []*SSASourceFile, codes []*SSAIrCode) error {
+	return utils.GormTransaction(db, func(tx *gorm.DB) error {
+		if err := DeleteSSAProgram(tx, prog.Hash); err != nil {
+			return err
+		}
+		if db := tx.Save(prog); db.Error != nil {
+			return utils.Errorf("save ssa program failed: %s", db.Error)
+		}
+		for _, f := range files {
+			f.ProgramHash = prog.Hash
+			if db := tx.Save(f); db.Error != nil {
+				return utils.Errorf("save ssa source file failed: %s", db.Error)
+			}
+		}
+		for _, c := range codes {
+			c.ProgramHash = prog.Hash
+			if db := tx.Save(c); db.Error != nil {
+				return utils.Errorf("save ssa ir code failed: %s", db.Error)
+			}
+		}
+		return nil
+	})
+}
+
+func DeleteSSAProgram(db *gorm.DB, hash string) error {
+	if db := db.Unscoped().Where("hash = ?", hash).Delete(&SSAProgram{}); db.Error != nil {
+		return utils.Errorf("delete ssa program failed: %s", db.Error)
+	}
+	if db := db.Unscoped().Where("program_hash = ?", hash).Delete(&SSASourceFile{}); db.Error != nil {
+		return utils.Errorf("delete ssa source file failed: %s", db.Error)
+	}
+	if db := db.Unscoped().Where("program_hash = ?", hash).Delete(&SSAIrCode{}); db.Error != nil {
+		return utils.Errorf("delete ssa ir code failed: %s", db.Error)
+	}
+	return nil
+}
+
+func GetSSAProgramsByName(db *gorm.DB, name string) ([]*SSAProgram, error) {
+	var progs []*SSAProgram
+	if db := db.Model(&SSAProgram{}).Where("program_name = ?", name).Find(&progs); db.Error != nil {
+		return nil, utils.Errorf("get ssa programs failed: %s", db.Error)
+	}
+	return progs, nil
+}
+
+func GetSSASourceFiles(db *gorm.DB, hash string) ([]*SSASourceFile, error) {
+	var files []*SSASourceFile
+	if db := db.Model(&SSASourceFile{}).Where("program_hash = ?", hash).Find(&files); db.Error != nil {
+		return nil, utils.Errorf("get ssa source files failed: %s", db.Error)
+	}
+	return files, nil
+}
+
+func GetSSAIrCodeById(db *gorm.DB, hash string, id int64) (*SSAIrCode, error) {
+	var code SSAIrCode
+	if db := db.Model(&SSAIrCode{}).Where("program_hash = ? AND inst_id = ?", hash, id).First(&code); db.Error != nil {
+		return nil, utils.Errorf("get ssa ir code failed: %s", db.Error)
+	}
+	return &code, nil
+}
+
+func GetSSAIrCodesByIds(db *gorm.DB, hash string, ids []int64) ([]*SSAIrCode, error) {
+	var codes []*SSAIrCode
+	if len(ids) == 0 {
+		return codes, nil
+	}
+	if db := db.Model(&SSAIrCode{}).Where("program_hash = ? AND inst_id IN (?)", hash, ids).Find(&codes); db.Error != nil {
+		return nil, utils.Errorf("get ssa ir codes failed: %s", db.Error)
+	}
+	return codes, nil
+}
+
+// GetSSAIrCodesByFunction 查询一个函数中的所有指令（不包含子函数中的指令）
+func GetSSAIrCodesByFunction(db *gorm.DB, hash string, funcId int64) ([]*SSAIrCode, error) {
+	var codes []*SSAIrCode
+	if db := db.Model(&SSAIrCode{}).Where("program_hash = ? AND function_id = ?", hash, funcId).Order("inst_id asc").Find(&codes); db.Error != nil {
+		return nil, utils.Errorf("get ssa ir codes failed: %s", db.Error)
+	}
+	return codes, nil
+}
+
+func GetSSAIrCodesByOpcodeAndName(db *gorm.DB, hash, opcode, name string) ([]*SSAIrCode, error) {
+	var codes []*SSAIrCode
+	db = db.Model(&SSAIrCode{}).Where("program_hash = ?", hash)
+	if opcode != "" {
+		db = db.Where("opcode = ?", opcode)
+	}
+	if name != "" {
+		db = db.Where("name = ?", name)
+	}
+	if db := db.Order("inst_id asc").Find(&codes); db.Error != nil {
+		return nil, utils.Errorf("get ssa ir codes failed: %s", db.Error)
+	}
+	return codes, nil
+}
+
+// GetSSAIrCodesByVariable 查询定义了变量 name 的所有指令
+func GetSSAIrCodesByVariable(db *gorm.DB, hash, name string) ([]*SSAIrCode, error) {
+	var codes []*SSAIrCode
+	db = db.Model(&SSAIrCode{}).Where("program_hash = ?", hash).Where(
+		"variables = ? OR variables LIKE ? OR variables LIKE ? OR variables LIKE ?",
+		name, name+",%", "%,"+name, "%,"+name+",%",
+	)
+	if db := db.Order("inst_id asc").Find(&codes); db.Error != nil {
+		return nil, utils.Errorf("get ssa ir codes failed: %s", db.Error)
+	}
+	return codes, nil
+}
+
+// InvalidateSSASourceFile 删除源文件 fileName 中的指令，并把程序标记为过期
+func InvalidateSSASourceFile(db *gorm.DB, hash string, fileName string) error {
+	return utils.GormTransaction(db, func(tx *gorm.DB) error {
+		if db := tx.Unscoped().Where("program_hash = ? AND file_name = ?", hash, fileName).Delete(&SSAIrCode{}); db.Error != nil {
+			return utils.Errorf("delete ssa ir code failed: %s", db.Error)
+		}
+		if db := tx.Unscoped().Where("program_hash = ? AND file_name = ?", hash, fileName).Delete(&SSASourceFile{}); db.Error != nil {
+			return utils.Errorf("delete ssa source file failed: %s", db.Error)
+		}
+		if db := tx.Model(&SSAProgram{}).Where("hash = ?", hash).Update("outdated", true); db.Error != nil {
+			return utils.Errorf("update ssa program failed: %s", db.Error)
+		}
+		return nil
+	})
+}
@@ -253,12 +253,20 @@ type YakClient interface {
 	QueryNewRisk(ctx context.Context, in *QueryNewRiskRequest, opts ...grpc.CallOption) (*QueryNewRiskResponse, error)
 	NewRiskRead(ctx context.Context, in *NewRiskReadRequest, opts ...grpc.CallOption) (*Empty, error)
 	UploadRiskToOnline(ctx context.Context, in *UploadRiskToOnlineRequest, opts ...grpc.CallOption) (*Empty, error)
+	// 漏洞研判：状态流转 / 指派 / 评论，以及研判历史与复测
+	UpdateRiskTriage(ctx context.Context, in *UpdateRiskTriageRequest, opts ...grpc.CallOption) (*Empty, error)
+	QueryRiskTriageHistory(ctx context.Context, in *QueryRiskTriageHistoryRequest, opts ...grpc.CallOption) (*QueryRiskTriageHistoryResponse, error)
+	RetestRisk(ctx context.Context, in *RetestRiskRequest, opts ...grpc.CallOption) (Yak_RetestRiskClient, error)
+	// 把过滤后的漏洞导出为 SARIF / JUnit / DefectDojo Generic Findings 格式
+	ExportRisks(ctx context.Context, in *ExportRisksRequest, opts ...grpc.CallOption) (Yak_ExportRisksClient, error)
 	// Report
 	QueryReports(ctx context.Context, in *QueryReportsRequest, opts ...grpc.CallOption) (*QueryReportsResponse, error)
 	QueryReport(ctx context.Context, in *QueryReportRequest, opts ...grpc.CallOption) (*Report, error)
 	DeleteReport(ctx context.Context, in *DeleteReportRequest, opts ...grpc.CallOption) (*Empty, error)
 	QueryAvailableReportFrom(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Fields, error)
 	DownloadReport(ctx context.Context, in *DownloadReportRequest, opts ...grpc.CallOption) (*Empty, error)
+	// 服务端渲染报告为独立的 HTML / DOCX 文件
+	RenderReport(ctx context.Context, in *RenderReportRequest, opts ...grpc.CallOption) (*RenderReportResponse, error)
 	//Yso
 	GetAllYsoGadgetOptions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*YsoOptionsWithVerbose, error)
 	GetAllYsoClassOptions(ctx context.Context, in *YsoOptionsRequerstWithVerbose, opts ...grpc.CallOption) (*YsoOptionsWithVerbose, error)
@@ -2902,6 +2910,88 @@ func (c *yakClient) UploadRiskToOnline(ctx context.Context, in *UploadRiskToOnli
 	return out, nil
 }
 
+func (c *yakClient) UpdateRiskTriage(ctx context.Context, in *UpdateRiskTriageRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/ypb.Yak/UpdateRiskTriage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yakClient) QueryRiskTriageHistory(ctx context.Context, in *QueryRiskTriageHistoryRequest, opts ...grpc.CallOption) (*QueryRiskTriageHistoryResponse, error) {
+	out := new(QueryRiskTriageHistoryResponse)
+	err := c.cc.Invoke(ctx, "/ypb.Yak/QueryRiskTriageHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yakClient) RetestRisk(ctx context.Context, in *RetestRiskRequest, opts ...grpc.CallOption) (Yak_RetestRiskClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[33], "/ypb.Yak/RetestRisk", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &yakRetestRiskClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Yak_RetestRiskClient interface {
+	Recv() (*ExecResult, error)
+	grpc.ClientStream
+}
+
+type yakRetestRiskClient struct {
+	grpc.ClientStream
+}
+
+func (x *yakRetestRiskClient) Recv() (*ExecResult, error) {
+	m := new(ExecResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *yakClient) ExportRisks(ctx context.Context, in *ExportRisksRequest, opts ...grpc.CallOption) (Yak_ExportRisksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[34], "/ypb.Yak/ExportRisks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &yakExportRisksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Yak_ExportRisksClient interface {
+	Recv() (*ExportRisksResponse, error)
+	grpc.ClientStream
+}
+
+type yakExportRisksClient struct {
+	grpc.ClientStream
+}
+
+func (x *yakExportRisksClient) Recv() (*ExportRisksResponse, error) {
+	m := new(ExportRisksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *yakClient) QueryReports(ctx context.Context, in *QueryReportsRequest, opts ...grpc.CallOption) (*QueryReportsResponse, error) {
 	out := new(QueryReportsResponse)
 	err := c.cc.Invoke(ctx, "/ypb.Yak/QueryReports", in, out, opts...)
@@ -2947,6 +3037,15 @@ func (c *yakClient) DownloadReport(ctx context.Context, in *DownloadReportReques
 	return out, nil
 }
 
+func (c *yakClient) RenderReport(ctx context.Context, in *RenderReportRequest, opts ...grpc.CallOption) (*RenderReportResponse, error) {
+	out := new(RenderReportResponse)
+	err := c.cc.Invoke(ctx, "/ypb.Yak/RenderReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *yakClient) GetAllYsoGadgetOptions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*YsoOptionsWithVerbose, error) {
 	out := new(YsoOptionsWithVerbose)
 	err := c.cc.Invoke(ctx, "/ypb.Yak/GetAllYsoGadgetOptions", in, out, opts...)
@@ -3173,7 +3272,7 @@ func (c *yakClient) ForceUpdateAvailableYakScriptTags(ctx context.Context, in *E
 }
 
 func (c *yakClient) ExecYakitPluginsByYakScriptFilter(ctx context.Context, in *ExecYakitPluginsByYakScriptFilterRequest, opts ...grpc.CallOption) (Yak_ExecYakitPluginsByYakScriptFilterClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[33], "/ypb.Yak/ExecYakitPluginsByYakScriptFilter", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[35], "/ypb.Yak/ExecYakitPluginsByYakScriptFilter", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3268,7 +3367,7 @@ func (c *yakClient) GenerateURL(ctx context.Context, in *GenerateURLRequest, opt
 }
 
 func (c *yakClient) ExtractDataToFile(ctx context.Context, opts ...grpc.CallOption) (Yak_ExtractDataToFileClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[34], "/ypb.Yak/ExtractDataToFile", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[36], "/ypb.Yak/ExtractDataToFile", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3425,7 +3524,7 @@ func (c *yakClient) DownloadOnlinePluginByIds(ctx context.Context, in *DownloadO
 }
 
 func (c *yakClient) DownloadOnlinePluginAll(ctx context.Context, in *DownloadOnlinePluginByTokenRequest, opts ...grpc.CallOption) (Yak_DownloadOnlinePluginAllClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[35], "/ypb.Yak/DownloadOnlinePluginAll", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[37], "/ypb.Yak/DownloadOnlinePluginAll", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3502,7 +3601,7 @@ func (c *yakClient) DownloadOnlinePluginByScriptNames(ctx context.Context, in *D
 }
 
 func (c *yakClient) DownloadOnlinePlugins(ctx context.Context, in *DownloadOnlinePluginsRequest, opts ...grpc.CallOption) (Yak_DownloadOnlinePluginsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[36], "/ypb.Yak/DownloadOnlinePlugins", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[38], "/ypb.Yak/DownloadOnlinePlugins", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3552,7 +3651,7 @@ func (c *yakClient) DownloadOnlinePluginByPluginName(ctx context.Context, in *Do
 }
 
 func (c *yakClient) ExecPacketScan(ctx context.Context, in *ExecPacketScanRequest, opts ...grpc.CallOption) (Yak_ExecPacketScanClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[37], "/ypb.Yak/ExecPacketScan", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[39], "/ypb.Yak/ExecPacketScan", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3674,7 +3773,7 @@ func (c *yakClient) ResetAndInvalidUserData(ctx context.Context, in *ResetAndInv
 }
 
 func (c *yakClient) CreateYaklangShell(ctx context.Context, opts ...grpc.CallOption) (Yak_CreateYaklangShellClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[38], "/ypb.Yak/CreateYaklangShell", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[40], "/ypb.Yak/CreateYaklangShell", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3705,7 +3804,7 @@ func (x *yakCreateYaklangShellClient) Recv() (*YaklangShellResponse, error) {
 }
 
 func (c *yakClient) AttachCombinedOutput(ctx context.Context, in *AttachCombinedOutputRequest, opts ...grpc.CallOption) (Yak_AttachCombinedOutputClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[39], "/ypb.Yak/AttachCombinedOutput", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[41], "/ypb.Yak/AttachCombinedOutput", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3845,7 +3944,7 @@ func (c *yakClient) GetTemporaryProject(ctx context.Context, in *Empty, opts ...
 }
 
 func (c *yakClient) ExportProject(ctx context.Context, in *ExportProjectRequest, opts ...grpc.CallOption) (Yak_ExportProjectClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[40], "/ypb.Yak/ExportProject", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[42], "/ypb.Yak/ExportProject", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3877,7 +3976,7 @@ func (x *yakExportProjectClient) Recv() (*ProjectIOProgress, error) {
 }
 
 func (c *yakClient) ImportProject(ctx context.Context, in *ImportProjectRequest, opts ...grpc.CallOption) (Yak_ImportProjectClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[41], "/ypb.Yak/ImportProject", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[43], "/ypb.Yak/ImportProject", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3954,7 +4053,7 @@ func (c *yakClient) DeleteChaosMakerRuleByID(ctx context.Context, in *DeleteChao
 }
 
 func (c *yakClient) ExecuteChaosMakerRule(ctx context.Context, in *ExecuteChaosMakerRuleRequest, opts ...grpc.CallOption) (Yak_ExecuteChaosMakerRuleClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[42], "/ypb.Yak/ExecuteChaosMakerRule", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[44], "/ypb.Yak/ExecuteChaosMakerRule", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4031,7 +4130,7 @@ func (c *yakClient) IsCVEDatabaseReady(ctx context.Context, in *IsCVEDatabaseRea
 }
 
 func (c *yakClient) UpdateCVEDatabase(ctx context.Context, in *UpdateCVEDatabaseRequest, opts ...grpc.CallOption) (Yak_UpdateCVEDatabaseClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[43], "/ypb.Yak/UpdateCVEDatabase", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[45], "/ypb.Yak/UpdateCVEDatabase", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4063,7 +4162,7 @@ func (x *yakUpdateCVEDatabaseClient) Recv() (*ExecResult, error) {
 }
 
 func (c *yakClient) ExportsProfileDatabase(ctx context.Context, in *ExportsProfileDatabaseRequest, opts ...grpc.CallOption) (Yak_ExportsProfileDatabaseClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[44], "/ypb.Yak/ExportsProfileDatabase", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[46], "/ypb.Yak/ExportsProfileDatabase", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4095,7 +4194,7 @@ func (x *yakExportsProfileDatabaseClient) Recv() (*ExecResult, error) {
 }
 
 func (c *yakClient) ImportsProfileDatabase(ctx context.Context, in *ImportsProfileDatabaseRequest, opts ...grpc.CallOption) (Yak_ImportsProfileDatabaseClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[45], "/ypb.Yak/ImportsProfileDatabase", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[47], "/ypb.Yak/ImportsProfileDatabase", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4163,7 +4262,7 @@ func (c *yakClient) IsScrecorderReady(ctx context.Context, in *IsScrecorderReady
 }
 
 func (c *yakClient) InstallScrecorder(ctx context.Context, in *InstallScrecorderRequest, opts ...grpc.CallOption) (Yak_InstallScrecorderClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[46], "/ypb.Yak/InstallScrecorder", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[48], "/ypb.Yak/InstallScrecorder", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4195,7 +4294,7 @@ func (x *yakInstallScrecorderClient) Recv() (*ExecResult, error) {
 }
 
 func (c *yakClient) StartScrecorder(ctx context.Context, in *StartScrecorderRequest, opts ...grpc.CallOption) (Yak_StartScrecorderClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[47], "/ypb.Yak/StartScrecorder", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[49], "/ypb.Yak/StartScrecorder", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4281,7 +4380,7 @@ func (c *yakClient) IsVulinboxReady(ctx context.Context, in *IsVulinboxReadyRequ
 }
 
 func (c *yakClient) InstallVulinbox(ctx context.Context, in *InstallVulinboxRequest, opts ...grpc.CallOption) (Yak_InstallVulinboxClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[48], "/ypb.Yak/InstallVulinbox", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[50], "/ypb.Yak/InstallVulinbox", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4313,7 +4412,7 @@ func (x *yakInstallVulinboxClient) Recv() (*ExecResult, error) {
 }
 
 func (c *yakClient) StartVulinbox(ctx context.Context, in *StartVulinboxRequest, opts ...grpc.CallOption) (Yak_StartVulinboxClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[49], "/ypb.Yak/StartVulinbox", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[51], "/ypb.Yak/StartVulinbox", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4345,7 +4444,7 @@ func (x *yakStartVulinboxClient) Recv() (*ExecResult, error) {
 }
 
 func (c *yakClient) GenQualityInspectionReport(ctx context.Context, in *GenQualityInspectionReportRequest, opts ...grpc.CallOption) (Yak_GenQualityInspectionReportClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[50], "/ypb.Yak/GenQualityInspectionReport", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[52], "/ypb.Yak/GenQualityInspectionReport", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4386,7 +4485,7 @@ func (c *yakClient) HTTPRequestBuilder(ctx context.Context, in *HTTPRequestBuild
 }
 
 func (c *yakClient) DebugPlugin(ctx context.Context, in *DebugPluginRequest, opts ...grpc.CallOption) (Yak_DebugPluginClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[51], "/ypb.Yak/DebugPlugin", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[53], "/ypb.Yak/DebugPlugin", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4427,7 +4526,7 @@ func (c *yakClient) SmokingEvaluatePlugin(ctx context.Context, in *SmokingEvalua
 }
 
 func (c *yakClient) SmokingEvaluatePluginBatch(ctx context.Context, in *SmokingEvaluatePluginBatchRequest, opts ...grpc.CallOption) (Yak_SmokingEvaluatePluginBatchClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[52], "/ypb.Yak/SmokingEvaluatePluginBatch", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[54], "/ypb.Yak/SmokingEvaluatePluginBatch", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4468,7 +4567,7 @@ func (c *yakClient) GetSystemDefaultDnsServers(ctx context.Context, in *Empty, o
 }
 
 func (c *yakClient) DiagnoseNetwork(ctx context.Context, in *DiagnoseNetworkRequest, opts ...grpc.CallOption) (Yak_DiagnoseNetworkClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[53], "/ypb.Yak/DiagnoseNetwork", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[55], "/ypb.Yak/DiagnoseNetwork", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4500,7 +4599,7 @@ func (x *yakDiagnoseNetworkClient) Recv() (*DiagnoseNetworkResponse, error) {
 }
 
 func (c *yakClient) DiagnoseNetworkDNS(ctx context.Context, in *DiagnoseNetworkDNSRequest, opts ...grpc.CallOption) (Yak_DiagnoseNetworkDNSClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[54], "/ypb.Yak/DiagnoseNetworkDNS", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[56], "/ypb.Yak/DiagnoseNetworkDNS", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4532,7 +4631,7 @@ func (x *yakDiagnoseNetworkDNSClient) Recv() (*DiagnoseNetworkResponse, error) {
 }
 
 func (c *yakClient) TraceRoute(ctx context.Context, in *TraceRouteRequest, opts ...grpc.CallOption) (Yak_TraceRouteClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[55], "/ypb.Yak/TraceRoute", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[57], "/ypb.Yak/TraceRoute", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4618,7 +4717,7 @@ func (c *yakClient) GetPcapMetadata(ctx context.Context, in *PcapMetadataRequest
 }
 
 func (c *yakClient) PcapX(ctx context.Context, opts ...grpc.CallOption) (Yak_PcapXClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[56], "/ypb.Yak/PcapX", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[58], "/ypb.Yak/PcapX", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4685,7 +4784,7 @@ func (c *yakClient) ParseTraffic(ctx context.Context, in *ParseTrafficRequest, o
 }
 
 func (c *yakClient) DuplexConnection(ctx context.Context, opts ...grpc.CallOption) (Yak_DuplexConnectionClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[57], "/ypb.Yak/DuplexConnection", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[59], "/ypb.Yak/DuplexConnection", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4716,7 +4815,7 @@ func (x *yakDuplexConnectionClient) Recv() (*DuplexConnectionResponse, error) {
 }
 
 func (c *yakClient) HybridScan(ctx context.Context, opts ...grpc.CallOption) (Yak_HybridScanClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[58], "/ypb.Yak/HybridScan", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[60], "/ypb.Yak/HybridScan", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4783,7 +4882,7 @@ func (c *yakClient) GetSpaceEngineAccountStatus(ctx context.Context, in *GetSpac
 }
 
 func (c *yakClient) FetchPortAssetFromSpaceEngine(ctx context.Context, in *FetchPortAssetFromSpaceEngineRequest, opts ...grpc.CallOption) (Yak_FetchPortAssetFromSpaceEngineClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[59], "/ypb.Yak/FetchPortAssetFromSpaceEngine", opts...)
+	stream, err := c.cc.NewStream(ctx, &Yak_ServiceDesc.Streams[61], "/ypb.Yak/FetchPortAssetFromSpaceEngine", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5053,12 +5152,20 @@ type YakServer interface {
 	QueryNewRisk(context.Context, *QueryNewRiskRequest) (*QueryNewRiskResponse, error)
 	NewRiskRead(context.Context, *NewRiskReadRequest) (*Empty, error)
 	UploadRiskToOnline(context.Context, *UploadRiskToOnlineRequest) (*Empty, error)
+	// 漏洞研判：状态流转 / 指派 / 评论，以及研判历史与复测
+	UpdateRiskTriage(context.Context, *UpdateRiskTriageRequest) (*Empty, error)
+	QueryRiskTriageHistory(context.Context, *QueryRiskTriageHistoryRequest) (*QueryRiskTriageHistoryResponse, error)
+	RetestRisk(*RetestRiskRequest, Yak_RetestRiskServer) error
+	// 把过滤后的漏洞导出为 SARIF / JUnit / DefectDojo Generic Findings 格式
+	ExportRisks(*ExportRisksRequest, Yak_ExportRisksServer) error
 	// Report
 	QueryReports(context.Context, *QueryReportsRequest) (*QueryReportsResponse, error)
 	QueryReport(context.Context, *QueryReportRequest) (*Report, error)
 	DeleteReport(context.Context, *DeleteReportRequest) (*Empty, error)
 	QueryAvailableReportFrom(context.Context, *Empty) (*Fields, error)
 	DownloadReport(context.Context, *DownloadReportRequest) (*Empty, error)
+	// 服务端渲染报告为独立的 HTML / DOCX 文件
+	RenderReport(context.Context, *RenderReportRequest) (*RenderReportResponse, error)
 	//Yso
 	GetAllYsoGadgetOptions(context.Context, *Empty) (*YsoOptionsWithVerbose, error)
 	GetAllYsoClassOptions(context.Context, *YsoOptionsRequerstWithVerbose) (*YsoOptionsWithVerbose, error)
@@ -5816,6 +5923,18 @@ func (UnimplementedYakServer) NewRiskRead(context.Context, *NewRiskReadRequest)
 func (UnimplementedYakServer) UploadRiskToOnline(context.Context, *UploadRiskToOnlineRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadRiskToOnline not implemented")
 }
+func (UnimplementedYakServer) UpdateRiskTriage(context.Context, *UpdateRiskTriageRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRiskTriage not implemented")
+}
+func (UnimplementedYakServer) QueryRiskTriageHistory(context.Context, *QueryRiskTriageHistoryRequest) (*QueryRiskTriageHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRiskTriageHistory not implemented")
+}
+func (UnimplementedYakServer) RetestRisk(*RetestRiskRequest, Yak_RetestRiskServer) error {
+	return status.Errorf(codes.Unimplemented, "method RetestRisk not implemented")
+}
+func (UnimplementedYakServer) ExportRisks(*ExportRisksRequest, Yak_ExportRisksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportRisks not implemented")
+}
 func (UnimplementedYakServer) QueryReports(context.Context, *QueryReportsRequest) (*QueryReportsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryReports not implemented")
 }
@@ -5831,6 +5950,9 @@ func (UnimplementedYakServer) QueryAvailableReportFrom(context.Context, *Empty)
 func (UnimplementedYakServer) DownloadReport(context.Context, *DownloadReportRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DownloadReport not implemented")
 }
+func (UnimplementedYakServer) RenderReport(context.Context, *RenderReportRequest) (*RenderReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderReport not implemented")
+}
 func (UnimplementedYakServer) GetAllYsoGadgetOptions(context.Context, *Empty) (*YsoOptionsWithVerbose, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllYsoGadgetOptions not implemented")
 }
@@ -9764,6 +9886,84 @@ func _Yak_UploadRiskToOnline_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Yak_UpdateRiskTriage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRiskTriageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YakServer).UpdateRiskTriage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ypb.Yak/UpdateRiskTriage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YakServer).UpdateRiskTriage(ctx, req.(*UpdateRiskTriageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yak_QueryRiskTriageHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRiskTriageHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YakServer).QueryRiskTriageHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ypb.Yak/QueryRiskTriageHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YakServer).QueryRiskTriageHistory(ctx, req.(*QueryRiskTriageHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yak_RetestRisk_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RetestRiskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YakServer).RetestRisk(m, &yakRetestRiskServer{stream})
+}
+
+type Yak_RetestRiskServer interface {
+	Send(*ExecResult) error
+	grpc.ServerStream
+}
+
+type yakRetestRiskServer struct {
+	grpc.ServerStream
+}
+
+func (x *yakRetestRiskServer) Send(m *ExecResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Yak_ExportRisks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRisksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YakServer).ExportRisks(m, &yakExportRisksServer{stream})
+}
+
+type Yak_ExportRisksServer interface {
+	Send(*ExportRisksResponse) error
+	grpc.ServerStream
+}
+
+type yakExportRisksServer struct {
+	grpc.ServerStream
+}
+
+func (x *yakExportRisksServer) Send(m *ExportRisksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Yak_QueryReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(QueryReportsRequest)
 	if err := dec(in); err != nil {
@@ -9854,6 +10054,24 @@ func _Yak_DownloadReport_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Yak_RenderReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YakServer).RenderReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ypb.Yak/RenderReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YakServer).RenderReport(ctx, req.(*RenderReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Yak_GetAllYsoGadgetOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -13089,6 +13307,14 @@ var Yak_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UploadRiskToOnline",
 			Handler:    _Yak_UploadRiskToOnline_Handler,
 		},
+		{
+			MethodName: "UpdateRiskTriage",
+			Handler:    _Yak_UpdateRiskTriage_Handler,
+		},
+		{
+			MethodName: "QueryRiskTriageHistory",
+			Handler:    _Yak_QueryRiskTriageHistory_Handler,
+		},
 		{
 			MethodName: "QueryReports",
 			Handler:    _Yak_QueryReports_Handler,
@@ -13109,6 +13335,10 @@ var Yak_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DownloadReport",
 			Handler:    _Yak_DownloadReport_Handler,
 		},
+		{
+			MethodName: "RenderReport",
+			Handler:    _Yak_RenderReport_Handler,
+		},
 		{
 			MethodName: "GetAllYsoGadgetOptions",
 			Handler:    _Yak_GetAllYsoGadgetOptions_Handler,
@@ -13728,6 +13958,16 @@ var Yak_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Yak_ConfigGlobalReverse_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RetestRisk",
+			Handler:       _Yak_RetestRisk_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportRisks",
+			Handler:       _Yak_ExportRisks_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ExecYakitPluginsByYakScriptFilter",
 			Handler:       _Yak_ExecYakitPluginsByYakScriptFilter_Handler,